@@ -99,8 +99,10 @@ func initCommands(
 		RunningInAutomation: inAutomation,
 		CLIConfigDir:        configDir,
 		PluginCacheDir:      config.PluginCacheDir,
+		ModuleCacheDir:      config.ModuleCacheDir,
 
 		PluginCacheMayBreakDependencyLockFile: config.PluginCacheMayBreakDependencyLockFile,
+		ExternalHooks:                         config.Hooks,
 
 		ShutdownCh:    makeShutdownCh(),
 		CallerContext: ctx,
@@ -257,6 +259,12 @@ func initCommands(
 			}, nil
 		},
 
+		"providers serve-mirror": func() (cli.Command, error) {
+			return &command.ProvidersServeMirrorCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"providers schema": func() (cli.Command, error) {
 			return &command.ProvidersSchemaCommand{
 				Meta: meta,
@@ -386,6 +394,18 @@ func initCommands(
 			}, nil
 		},
 
+		"state diff": func() (cli.Command, error) {
+			return &command.StateDiffCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"state history": func() (cli.Command, error) {
+			return &command.StateHistoryCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"state pull": func() (cli.Command, error) {
 			return &command.StatePullCommand{
 				Meta: meta,
@@ -398,6 +418,12 @@ func initCommands(
 			}, nil
 		},
 
+		"state rollback": func() (cli.Command, error) {
+			return &command.StateRollbackCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"state show": func() (cli.Command, error) {
 			return &command.StateShowCommand{
 				Meta: meta,
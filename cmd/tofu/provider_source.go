@@ -16,6 +16,7 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/cliconfig"
 	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/oci"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -221,6 +222,28 @@ func providerSourceForCLIConfigLocation(loc cliconfig.ProviderInstallationLocati
 		}
 		return getproviders.NewHTTPMirrorSource(url, services.CredentialsSource()), nil
 
+	case cliconfig.ProviderInstallationOCIMirror:
+		var diags tfdiags.Diagnostics
+		creds, err := oci.DefaultCredentials()
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid credentials for provider installation source",
+				fmt.Sprintf("Cannot read the credentials for the OCI provider mirror %q: %s.", string(loc), err),
+			))
+			return nil, diags
+		}
+		source, err := getproviders.NewOCIMirrorSource(string(loc), creds)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid repository template for provider installation source",
+				fmt.Sprintf("Cannot use %q as the repository template for an OCI provider mirror: %s.", string(loc), err),
+			))
+			return nil, diags
+		}
+		return source, nil
+
 	default:
 		// We should not get here because the set of cases above should
 		// be comprehensive for all of the
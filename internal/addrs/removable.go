@@ -0,0 +1,24 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package addrs
+
+// ConfigRemovable is an interface implemented by address types that can be
+// the target of a "removed" statement in configuration.
+//
+// Like ConfigMoveable, ConfigRemovable represents a static object in the
+// configuration rather than an instance of that object, because a "removed"
+// statement always applies to every instance of its target. Also like
+// ConfigMoveable, it represents an absolute address relative to the root
+// of the configuration. The type RemoveEndpoint represents the relative form
+// given directly in configuration.
+type ConfigRemovable interface {
+	Targetable
+	configRemovableSigil()
+}
+
+// The following are all of the possible ConfigRemovable address types:
+var (
+	_ ConfigRemovable = ConfigResource{}
+	_ ConfigRemovable = Module(nil)
+)
@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package addrs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// RemoveEndpoint is to ConfigRemovable what MoveEndpoint is to
+// ConfigMoveable: a wrapping struct that captures the result of decoding an
+// HCL traversal representing a relative path from the current module to
+// a removable object.
+//
+// To obtain a full address from a RemoveEndpoint you must use the method
+// ConfigRemovable, giving the address of the module where the endpoint was
+// declared.
+type RemoveEndpoint struct {
+	// SourceRange is the location of the physical endpoint address
+	// in configuration, if this RemoveEndpoint was decoded from a
+	// configuration expression.
+	SourceRange tfdiags.SourceRange
+
+	// relSubject is either a Module or a ConfigResource, relative to the
+	// module where the endpoint was declared.
+	relSubject ConfigRemovable
+}
+
+func (e *RemoveEndpoint) String() string {
+	return e.relSubject.String()
+}
+
+// ConfigRemovable transforms the reciever into a ConfigRemovable by
+// resolving it relative to the given base module, which should be the module
+// where the RemoveEndpoint expression was found.
+func (e *RemoveEndpoint) ConfigRemovable(baseModule Module) ConfigRemovable {
+	switch addr := e.relSubject.(type) {
+	case Module:
+		ret := make(Module, 0, len(baseModule)+len(addr))
+		ret = append(ret, baseModule...)
+		ret = append(ret, addr...)
+		return ret
+	case ConfigResource:
+		moduleAddr := make(Module, 0, len(baseModule)+len(addr.Module))
+		moduleAddr = append(moduleAddr, baseModule...)
+		moduleAddr = append(moduleAddr, addr.Module...)
+		return ConfigResource{
+			Module:   moduleAddr,
+			Resource: addr.Resource,
+		}
+	default:
+		// The above should be exhaustive for all of the types
+		// that ParseRemoveEndpoint produces.
+		panic(fmt.Sprintf("unsupported address type %T", addr))
+	}
+}
+
+// ParseRemoveEndpoint attempts to interpret the given traversal as a
+// "remove endpoint" address, which is a relative path from the module
+// containing the traversal to a managed resource or a module call in either
+// the same module or in some child module.
+//
+// A "removed" block always applies to all instances of its target, so unlike
+// move endpoints a remove endpoint must not include any instance keys.
+func ParseRemoveEndpoint(traversal hcl.Traversal) (*RemoveEndpoint, tfdiags.Diagnostics) {
+	path, remain, diags := parseModuleInstancePrefix(traversal)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	rng := tfdiags.SourceRangeFromHCL(traversal.SourceRange())
+
+	for _, step := range path {
+		if step.InstanceKey != NoKey {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Module instance keys not allowed",
+				Detail:   "A \"removed\" block applies to all instances of a module call, so its address must not include instance keys.",
+				Subject:  traversal.SourceRange().Ptr(),
+			})
+			return nil, diags
+		}
+	}
+
+	if len(remain) == 0 {
+		if len(path) == 0 {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid address",
+				Detail:   "The root module cannot be removed.",
+				Subject:  traversal.SourceRange().Ptr(),
+			})
+			return nil, diags
+		}
+		return &RemoveEndpoint{
+			relSubject:  path.Module(),
+			SourceRange: rng,
+		}, diags
+	}
+
+	riAddr, moreDiags := parseResourceInstanceUnderModule(path, remain)
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	if riAddr.Resource.Key != NoKey {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Resource instance keys not allowed",
+			Detail:   "A \"removed\" block applies to all instances of a resource, so its address must not include instance keys.",
+			Subject:  traversal.SourceRange().Ptr(),
+		})
+		return nil, diags
+	}
+
+	if riAddr.Resource.Resource.Mode == DataResourceMode {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Data source address not allowed",
+			Detail:   "Data sources are never destroyed, so there's no need to declare them in a \"removed\" block. To stop reading a data source, remove it from the configuration.",
+			Subject:  traversal.SourceRange().Ptr(),
+		})
+		return nil, diags
+	}
+
+	return &RemoveEndpoint{
+		relSubject:  riAddr.ConfigResource(),
+		SourceRange: rng,
+	}, diags
+}
@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package addrs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestParseRemoveEndpoint(t *testing.T) {
+	tests := []struct {
+		Input   string
+		WantRel ConfigRemovable
+		WantErr string
+	}{
+		{
+			`foo.bar`,
+			ConfigResource{
+				Module: RootModule,
+				Resource: Resource{
+					Mode: ManagedResourceMode,
+					Type: "foo",
+					Name: "bar",
+				},
+			},
+			``,
+		},
+		{
+			`module.boop.foo.bar`,
+			ConfigResource{
+				Module: Module{"boop"},
+				Resource: Resource{
+					Mode: ManagedResourceMode,
+					Type: "foo",
+					Name: "bar",
+				},
+			},
+			``,
+		},
+		{
+			`module.boop`,
+			Module{"boop"},
+			``,
+		},
+		{
+			`module.boop.module.bap`,
+			Module{"boop", "bap"},
+			``,
+		},
+		{
+			`foo.bar[0]`,
+			nil,
+			`Resource instance keys not allowed: A "removed" block applies to all instances of a resource, so its address must not include instance keys.`,
+		},
+		{
+			`module.boop[0].foo.bar`,
+			nil,
+			`Module instance keys not allowed: A "removed" block applies to all instances of a module call, so its address must not include instance keys.`,
+		},
+		{
+			`module.boop["a"]`,
+			nil,
+			`Module instance keys not allowed: A "removed" block applies to all instances of a module call, so its address must not include instance keys.`,
+		},
+		{
+			`data.foo.bar`,
+			nil,
+			`Data source address not allowed: Data sources are never destroyed, so there's no need to declare them in a "removed" block. To stop reading a data source, remove it from the configuration.`,
+		},
+		{
+			`foo`,
+			nil,
+			`Invalid address: Resource specification must include a resource type and name.`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			traversal, hclDiags := hclsyntax.ParseTraversalAbs([]byte(test.Input), "", hcl.InitialPos)
+			if hclDiags.HasErrors() {
+				// We're not trying to test the HCL parser here, so any
+				// failures at this point are likely to be bugs in the
+				// test case itself.
+				t.Fatalf("syntax error: %s", hclDiags.Error())
+			}
+
+			removeEp, diags := ParseRemoveEndpoint(traversal)
+
+			switch {
+			case test.WantErr != "":
+				if !diags.HasErrors() {
+					t.Fatalf("unexpected success\nwant error: %s", test.WantErr)
+				}
+				gotErr := diags.Err().Error()
+				if gotErr != test.WantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", gotErr, test.WantErr)
+				}
+			default:
+				if diags.HasErrors() {
+					t.Fatalf("unexpected error: %s", diags.Err().Error())
+				}
+				if diff := cmp.Diff(test.WantRel, removeEp.relSubject); diff != "" {
+					t.Errorf("wrong result\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestRemoveEndpointConfigRemovable(t *testing.T) {
+	parse := func(input string) *RemoveEndpoint {
+		t.Helper()
+		traversal, hclDiags := hclsyntax.ParseTraversalAbs([]byte(input), "", hcl.InitialPos)
+		if hclDiags.HasErrors() {
+			t.Fatalf("syntax error: %s", hclDiags.Error())
+		}
+		ep, diags := ParseRemoveEndpoint(traversal)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected error: %s", diags.Err().Error())
+		}
+		return ep
+	}
+
+	tests := []struct {
+		Input  string
+		Module Module
+		Want   string
+	}{
+		{`foo.bar`, RootModule, `foo.bar`},
+		{`foo.bar`, Module{"a"}, `module.a.foo.bar`},
+		{`module.b.foo.bar`, Module{"a"}, `module.a.module.b.foo.bar`},
+		{`module.b`, RootModule, `module.b`},
+		{`module.b`, Module{"a"}, `module.a.module.b`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Input+" in "+test.Module.String(), func(t *testing.T) {
+			got := parse(test.Input).ConfigRemovable(test.Module).String()
+			if got != test.Want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.Want)
+			}
+		})
+	}
+}
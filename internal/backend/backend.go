@@ -22,6 +22,7 @@ import (
 	"github.com/opentofu/opentofu/internal/configs/configload"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/depsfile"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
 	"github.com/opentofu/opentofu/internal/states"
@@ -51,8 +52,9 @@ var (
 	ErrWorkspacesNotSupported = errors.New("workspaces not supported")
 )
 
-// InitFn is used to initialize a new backend.
-type InitFn func() Backend
+// InitFn is used to initialize a new backend. Backends that store state
+// snapshots must encrypt and decrypt them using the given encryption.
+type InitFn func(encryption.StateEncryption) Backend
 
 // Backend is the minimal interface that must be implemented to enable OpenTofu.
 type Backend interface {
@@ -271,14 +273,22 @@ type Operation struct {
 	// plan and apply arguments but may not work for all backends.
 	PlanFile *planfile.WrappedPlanFile
 
+	// Encryption is used to encrypt any plan file written to PlanOutPath.
+	Encryption encryption.Encryption
+
 	// The options below are more self-explanatory and affect the runtime
 	// behavior of the operation.
 	PlanMode     plans.Mode
 	AutoApprove  bool
 	Targets      []addrs.Targetable
+	Excludes     []addrs.Targetable
 	ForceReplace []addrs.AbsResourceInstance
 	Variables    map[string]UnparsedVariableValue
 
+	// AllowDeferral allows planning to defer the objects whose count or
+	// for_each values won't be known until apply, rather than failing.
+	AllowDeferral bool
+
 	// Some operations use root module variables only opportunistically or
 	// don't need them at all. If this flag is set, the backend must treat
 	// all variables as optional and provide an unknown value for any required
@@ -311,6 +321,25 @@ type Operation struct {
 	// for unmatched import targets and where any generated config should be
 	// written to.
 	GenerateConfigOut string
+
+	// PolicyDir, if set, is the directory containing the policy files whose
+	// policies the planned changes should be checked against.
+	PolicyDir string
+}
+
+// OperationPlanHook is an optional extension of tofu.Hook for hooks in
+// Operation.Hooks that must be notified when an apply operation creates its
+// own plan, because it wasn't given a saved plan.
+type OperationPlanHook interface {
+	tofu.Hook
+
+	// PrePlan is called before the plan is created. If it returns errors
+	// then the operation fails without creating the plan.
+	PrePlan() tfdiags.Diagnostics
+
+	// PostPlan is called after the plan was created, or failed to be created,
+	// and its diagnostics are reported along with those of the plan.
+	PostPlan(success bool) tfdiags.Diagnostics
 }
 
 // HasConfig returns true if and only if the operation has a ConfigDir value
@@ -321,10 +350,10 @@ func (o *Operation) HasConfig() bool {
 }
 
 // Config loads the configuration that the operation applies to, using the
-// ConfigDir and ConfigLoader fields within the receiving operation.
+// ConfigDir, ConfigLoader and Variables fields within the receiving operation.
 func (o *Operation) Config() (*configs.Config, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
-	config, hclDiags := o.ConfigLoader.LoadConfig(o.ConfigDir)
+	config, hclDiags := o.ConfigLoader.LoadConfig(o.ConfigDir, StaticRootModuleCall(o.Variables))
 	diags = diags.Append(hclDiags)
 	return config, diags
 }
@@ -386,8 +415,9 @@ type RunningOperation struct {
 	Result OperationResult
 
 	// PlanEmpty is populated after a Plan operation completes to note whether
-	// a plan is empty or has changes. This is only used in the CLI to determine
-	// the exit status because the plan value is not available at that point.
+	// a plan is empty or has changes, including deferred changes. This is only
+	// used in the CLI to determine the exit status because the plan value is
+	// not available at that point.
 	PlanEmpty bool
 
 	// State is the final state after the operation completed. Persisting
@@ -26,6 +26,7 @@ import (
 	backendPg "github.com/opentofu/opentofu/internal/backend/remote-state/pg"
 	backendS3 "github.com/opentofu/opentofu/internal/backend/remote-state/s3"
 	backendCloud "github.com/opentofu/opentofu/internal/cloud"
+	"github.com/opentofu/opentofu/internal/encryption"
 )
 
 // backends is the list of available backends. This is a global variable
@@ -52,24 +53,27 @@ func Init(services *disco.Disco) {
 	defer backendsLock.Unlock()
 
 	backends = map[string]backend.InitFn{
-		"local":  func() backend.Backend { return backendLocal.New() },
-		"remote": func() backend.Backend { return backendRemote.New(services) },
+		"local": func(enc encryption.StateEncryption) backend.Backend { return backendLocal.New(enc) },
+
+		// The "remote" and "cloud" backends store state in a service that
+		// must be able to read it, so client-side encryption doesn't apply.
+		"remote": func(encryption.StateEncryption) backend.Backend { return backendRemote.New(services) },
 
 		// Remote State backends.
-		"azurerm":    func() backend.Backend { return backendAzure.New() },
-		"consul":     func() backend.Backend { return backendConsul.New() },
-		"cos":        func() backend.Backend { return backendCos.New() },
-		"gcs":        func() backend.Backend { return backendGCS.New() },
-		"http":       func() backend.Backend { return backendHTTP.New() },
-		"inmem":      func() backend.Backend { return backendInmem.New() },
-		"kubernetes": func() backend.Backend { return backendKubernetes.New() },
-		"oss":        func() backend.Backend { return backendOSS.New() },
-		"pg":         func() backend.Backend { return backendPg.New() },
-		"s3":         func() backend.Backend { return backendS3.New() },
+		"azurerm":    backendAzure.New,
+		"consul":     backendConsul.New,
+		"cos":        backendCos.New,
+		"gcs":        backendGCS.New,
+		"http":       backendHTTP.New,
+		"inmem":      backendInmem.New,
+		"kubernetes": backendKubernetes.New,
+		"oss":        backendOSS.New,
+		"pg":         backendPg.New,
+		"s3":         backendS3.New,
 
 		// Terraform Cloud 'backend'
 		// This is an implementation detail only, used for the cloud package
-		"cloud": func() backend.Backend { return backendCloud.New(services) },
+		"cloud": func(encryption.StateEncryption) backend.Backend { return backendCloud.New(services) },
 	}
 
 	RemovedBackends = map[string]string{
@@ -4,6 +4,7 @@
 package init
 
 import (
+	"github.com/opentofu/opentofu/internal/encryption"
 	"reflect"
 	"testing"
 )
@@ -34,7 +35,7 @@ func TestInit_backend(t *testing.T) {
 			if f == nil {
 				t.Fatalf("backend %q is not present; should be", b.Name)
 			}
-			bType := reflect.TypeOf(f()).String()
+			bType := reflect.TypeOf(f(encryption.StateEncryptionDisabled())).String()
 			if bType != b.Type {
 				t.Fatalf("expected backend %q to be %q, got: %q", b.Name, b.Type, bType)
 			}
@@ -8,11 +8,15 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/hcl/v2"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/logging"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/states"
@@ -48,11 +52,34 @@ func (b *Local) opApply(
 		return
 	}
 
+	// Load the policies before planning, so that we can fail early if the
+	// policy files are invalid. A saved plan was already checked against
+	// policies when it was created.
+	var policies []*configs.Policy
+	if op.PolicyDir != "" {
+		if op.PlanFile != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Cannot check policies for a saved plan",
+				"Policies can only be checked when creating a plan. Use the -policy-dir option with \"tofu plan\" instead.",
+			))
+			op.ReportResult(runningOp, diags)
+			return
+		}
+		var hclDiags hcl.Diagnostics
+		policies, hclDiags = op.ConfigLoader.Parser().LoadPolicyDir(op.PolicyDir)
+		diags = diags.Append(hclDiags)
+		if hclDiags.HasErrors() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+	}
+
 	stateHook := new(StateHook)
 	op.Hooks = append(op.Hooks, stateHook)
 
 	// Get our context
-	lr, _, opState, contextDiags := b.localRun(op)
+	lr, _, opState, contextDiags := b.localRun(stopCtx, op)
 	diags = diags.Append(contextDiags)
 	if contextDiags.HasErrors() {
 		op.ReportResult(runningOp, diags)
@@ -87,10 +114,28 @@ func (b *Local) opApply(
 	var plan *plans.Plan
 	// If we weren't given a plan, then we refresh/plan
 	if op.PlanFile == nil {
+		var planHooks []backend.OperationPlanHook
+		for _, hook := range op.Hooks {
+			if hook, ok := hook.(backend.OperationPlanHook); ok {
+				planHooks = append(planHooks, hook)
+			}
+		}
+		for _, hook := range planHooks {
+			moreDiags = hook.PrePlan()
+			diags = diags.Append(moreDiags)
+			if moreDiags.HasErrors() {
+				op.ReportResult(runningOp, diags)
+				return
+			}
+		}
+
 		// Perform the plan
 		log.Printf("[INFO] backend/local: apply calling Plan")
 		plan, moreDiags = lr.Core.Plan(lr.Config, lr.InputState, lr.PlanOpts)
 		diags = diags.Append(moreDiags)
+		for _, hook := range planHooks {
+			diags = diags.Append(hook.PostPlan(!moreDiags.HasErrors()))
+		}
 		if moreDiags.HasErrors() {
 			// If OpenTofu Core generated a partial plan despite the errors
 			// then we'll make the best effort to render it. OpenTofu Core
@@ -106,11 +151,24 @@ func (b *Local) opApply(
 			return
 		}
 
+		// Check the planned changes against the policies, if any, and
+		// refuse to apply them if they violate any mandatory policies.
+		var policyDiags tfdiags.Diagnostics
+		if len(policies) != 0 {
+			policyDiags = lr.Core.EvalPolicies(lr.Config, plan, policies)
+			diags = diags.Append(policyDiags)
+		}
+
 		trivialPlan := !plan.CanApply()
 		hasUI := op.UIOut != nil && op.UIIn != nil
 		mustConfirm := hasUI && !op.AutoApprove && !trivialPlan
 		op.View.Plan(plan, schemas)
 
+		if policyDiags.HasErrors() || plan.HasMandatoryPolicyViolations() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+
 		if testHookStopPlanApply != nil {
 			testHookStopPlanApply()
 		}
@@ -217,6 +275,21 @@ func (b *Local) opApply(
 			op.ReportResult(runningOp, diags)
 			return
 		}
+		if plan.HasMandatoryPolicyViolations() {
+			var violations strings.Builder
+			for _, v := range plan.PolicyViolations {
+				if v.Mandatory {
+					fmt.Fprintf(&violations, "\n  - %s: policy %q: %s", v.Addr, v.Policy, v.Message)
+				}
+			}
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Cannot apply plan that violates policies",
+				fmt.Sprintf("The planned changes violate the following mandatory policies, so this plan cannot be applied:\n%s", violations.String()),
+			))
+			op.ReportResult(runningOp, diags)
+			return
+		}
 		for _, change := range plan.Changes.Resources {
 			if change.Action != plans.NoOp {
 				op.View.PlannedChange(change)
@@ -293,7 +366,7 @@ func (b *Local) backupStateForError(stateFile *statefile.File, err error, view v
 		fmt.Sprintf("Error saving state: %s", err),
 	))
 
-	local := statemgr.NewFilesystem("errored.tfstate")
+	local := statemgr.NewFilesystem("errored.tfstate", b.encryption)
 	writeErr := local.WriteStateForMigration(stateFile, true)
 	if writeErr != nil {
 		diags = diags.Append(tfdiags.Sourceless(
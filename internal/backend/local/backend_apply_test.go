@@ -21,6 +21,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/depsfile"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/initwd"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/providers"
@@ -74,6 +75,48 @@ test_instance.foo:
 		t.Fatalf("unexpected error output:\n%s", errOutput)
 	}
 }
+
+func TestLocal_applyPolicyViolation(t *testing.T) {
+	b := TestLocal(t)
+
+	p := TestLocalProvider(t, b, "test", applyFixtureSchema())
+
+	policyDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(policyDir, "main.tfpolicy.hcl"), []byte(`
+policy "no_bar" {
+  resource_type = "test_instance"
+
+  rule {
+    condition     = resource.after.ami != "bar"
+    error_message = "The bar AMI is not allowed."
+  }
+}
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op, configCleanup, done := testOperationApply(t, "./testdata/apply")
+	defer configCleanup()
+	op.PolicyDir = policyDir
+
+	run, err := b.Operation(context.Background(), op)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	<-run.Done()
+	if run.Result != backend.OperationFailure {
+		t.Fatal("operation succeeded; want failure")
+	}
+
+	if p.ApplyResourceChangeCalled {
+		t.Fatal("apply should not be called")
+	}
+	if got, want := done(t).Stderr(), `Policy "no_bar" violated`; !strings.Contains(got, want) {
+		t.Errorf("wrong error output\ngot:\n%s\nwant: containing %q", got, want)
+	}
+}
+
 func TestLocal_applyCheck(t *testing.T) {
 	b := TestLocal(t)
 
@@ -349,7 +392,7 @@ type backendWithFailingState struct {
 
 func (b *backendWithFailingState) StateMgr(name string) (statemgr.Full, error) {
 	return &failingState{
-		statemgr.NewFilesystem("failing-state.tfstate"),
+		statemgr.NewFilesystem("failing-state.tfstate", encryption.StateEncryptionDisabled()),
 	}, nil
 }
 
@@ -15,6 +15,7 @@ import (
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -34,11 +35,14 @@ func (b *Local) LocalRun(op *backend.Operation) (*backend.LocalRun, statemgr.Ful
 
 	op.StateLocker = op.StateLocker.WithContext(context.Background())
 
-	lr, _, stateMgr, diags := b.localRun(op)
+	lr, _, stateMgr, diags := b.localRun(context.Background(), op)
 	return lr, stateMgr, diags
 }
 
-func (b *Local) localRun(op *backend.Operation) (*backend.LocalRun, *configload.Snapshot, statemgr.Full, tfdiags.Diagnostics) {
+// localRun prepares a tofu.Context for the given operation. The given context
+// only carries the caller's trace span; cancellation of the operation is
+// handled separately through its StateLocker and the returned tofu.Context.
+func (b *Local) localRun(ctx context.Context, op *backend.Operation) (*backend.LocalRun, *configload.Snapshot, statemgr.Full, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
 	// Get the latest state.
@@ -48,6 +52,7 @@ func (b *Local) localRun(op *backend.Operation) (*backend.LocalRun, *configload.
 		diags = diags.Append(fmt.Errorf("error loading state: %w", err))
 		return nil, nil, nil, diags
 	}
+	statemgr.SetTraceContext(s, ctx)
 	log.Printf("[TRACE] backend/local: requesting state lock for workspace %q", op.Workspace)
 	if diags := op.StateLocker.Lock(s, op.Type.String()); diags.HasErrors() {
 		return nil, nil, nil, diags
@@ -76,6 +81,7 @@ func (b *Local) localRun(op *backend.Operation) (*backend.LocalRun, *configload.
 	}
 	coreOpts.UIInput = op.UIIn
 	coreOpts.Hooks = op.Hooks
+	coreOpts.TraceContext = ctx
 
 	var ctxDiags tfdiags.Diagnostics
 	var configSnap *configload.Snapshot
@@ -142,7 +148,7 @@ func (b *Local) localRunDirect(op *backend.Operation, run *backend.LocalRun, cor
 	var diags tfdiags.Diagnostics
 
 	// Load the configuration using the caller-provided configuration loader.
-	config, configSnap, configDiags := op.ConfigLoader.LoadConfigWithSnapshot(op.ConfigDir)
+	config, configSnap, configDiags := op.ConfigLoader.LoadConfigWithSnapshot(op.ConfigDir, backend.StaticRootModuleCall(op.Variables))
 	diags = diags.Append(configDiags)
 	if configDiags.HasErrors() {
 		return nil, nil, diags
@@ -199,7 +205,9 @@ func (b *Local) localRunDirect(op *backend.Operation, run *backend.LocalRun, cor
 	planOpts := &tofu.PlanOpts{
 		Mode:               op.PlanMode,
 		Targets:            op.Targets,
+		Excludes:           op.Excludes,
 		ForceReplace:       op.ForceReplace,
+		AllowDeferral:      op.AllowDeferral,
 		SetVariables:       variables,
 		SkipRefresh:        op.Type != backend.OperationTypeRefresh && !op.PlanRefresh,
 		GenerateConfigPath: op.GenerateConfigOut,
@@ -245,14 +253,35 @@ func (b *Local) localRunForPlanFile(op *backend.Operation, pf *planfile.Reader,
 		))
 		return nil, snap, diags
 	}
+	plan, err := pf.ReadPlan()
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			errSummary,
+			fmt.Sprintf("Failed to read plan from plan file: %s.", err),
+		))
+		return nil, snap, diags
+	}
+
+	// The configuration is loaded with the same root module variables as
+	// when it was planned, so that anything evaluated statically matches.
 	loader := configload.NewLoaderFromSnapshot(snap)
-	config, configDiags := loader.LoadConfig(snap.Modules[""].Dir)
+	config, configDiags := loader.LoadConfig(snap.Modules[""].Dir, planfile.StaticRootModuleCall(plan))
 	diags = diags.Append(configDiags)
 	if configDiags.HasErrors() {
 		return nil, snap, diags
 	}
 	run.Config = config
 
+	// Ephemeral variables are not saved in the plan file, so we must take
+	// their values from the variables given for this apply.
+	if len(plan.ApplyTimeVariables) != 0 {
+		diags = diags.Append(setApplyTimeVariables(plan, config, op.Variables))
+		if diags.HasErrors() {
+			return nil, snap, diags
+		}
+	}
+
 	// NOTE: We're intentionally comparing the current locks with the
 	// configuration snapshot, rather than the lock snapshot in the plan file,
 	// because it's the current locks which dictate our plugin selections
@@ -339,15 +368,6 @@ func (b *Local) localRunForPlanFile(op *backend.Operation, pf *planfile.Reader,
 	// refreshing we did while building the plan.
 	run.InputState = priorStateFile.State
 
-	plan, err := pf.ReadPlan()
-	if err != nil {
-		diags = diags.Append(tfdiags.Sourceless(
-			tfdiags.Error,
-			errSummary,
-			fmt.Sprintf("Failed to read plan from plan file: %s.", err),
-		))
-		return nil, snap, diags
-	}
 	// When we're applying a saved plan, we populate Plan instead of PlanOpts,
 	// because a plan object incorporates the subset of data from PlanOps that
 	// we need to apply the plan.
@@ -362,6 +382,61 @@ func (b *Local) localRunForPlanFile(op *backend.Operation, pf *planfile.Reader,
 	return run, snap, diags
 }
 
+// setApplyTimeVariables adds to the given plan the values of its ephemeral
+// root module variables, which are never saved in a plan file and so must be
+// set again each time a saved plan is applied.
+//
+// Values for other variables are ignored unless they were set explicitly
+// using -var or -var-file, in which case they are an error because those
+// variables already have values recorded in the plan.
+func setApplyTimeVariables(plan *plans.Plan, config *configs.Config, vv map[string]backend.UnparsedVariableValue) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	applyTime := make(map[string]bool, len(plan.ApplyTimeVariables))
+	for _, name := range plan.ApplyTimeVariables {
+		applyTime[name] = true
+	}
+	if plan.VariableValues == nil {
+		plan.VariableValues = make(map[string]plans.DynamicValue)
+	}
+
+	for name, rv := range vv {
+		vc, declared := config.Module.Variables[name]
+		if !declared {
+			continue
+		}
+
+		val, valDiags := rv.ParseVariableValue(vc.ParsingMode)
+		if !applyTime[name] {
+			if val != nil && (val.SourceType == tofu.ValueFromCLIArg || val.SourceType == tofu.ValueFromNamedFile) {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Can't set variables when applying a saved plan",
+					fmt.Sprintf("The variable %q is not ephemeral, so a saved plan includes the value that was set when it was created. Only ephemeral variables can be set when applying a saved plan.", name),
+				))
+			}
+			continue
+		}
+		diags = diags.Append(valDiags)
+		if valDiags.HasErrors() {
+			continue
+		}
+
+		dv, err := plans.NewDynamicValue(val.Value, cty.DynamicPseudoType)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid value for ephemeral variable",
+				fmt.Sprintf("The value for variable %q could not be prepared for apply: %s.", name, err),
+			))
+			continue
+		}
+		plan.VariableValues[name] = dv
+	}
+
+	return diags
+}
+
 // interactiveCollectVariables attempts to complete the given existing
 // map of variables by interactively prompting for any variables that are
 // declared as required but not yet present.
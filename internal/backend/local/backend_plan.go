@@ -9,7 +9,10 @@ import (
 	"io"
 	"log"
 
+	"github.com/hashicorp/hcl/v2"
+
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/genconfig"
 	"github.com/opentofu/opentofu/internal/logging"
 	"github.com/opentofu/opentofu/internal/plans"
@@ -72,12 +75,25 @@ func (b *Local) opPlan(
 		}
 	}
 
+	// Load the policies before planning, so that we can fail early if the
+	// policy files are invalid.
+	var policies []*configs.Policy
+	if op.PolicyDir != "" {
+		var hclDiags hcl.Diagnostics
+		policies, hclDiags = op.ConfigLoader.Parser().LoadPolicyDir(op.PolicyDir)
+		diags = diags.Append(hclDiags)
+		if hclDiags.HasErrors() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+	}
+
 	if b.ContextOpts == nil {
 		b.ContextOpts = new(tofu.ContextOpts)
 	}
 
 	// Get our context
-	lr, configSnap, opState, ctxDiags := b.localRun(op)
+	lr, configSnap, opState, ctxDiags := b.localRun(stopCtx, op)
 	diags = diags.Append(ctxDiags)
 	if ctxDiags.HasErrors() {
 		op.ReportResult(runningOp, diags)
@@ -131,7 +147,17 @@ func (b *Local) opPlan(
 	}
 
 	// Record whether this plan includes any side-effects that could be applied.
-	runningOp.PlanEmpty = !plan.CanApply()
+	// Deferred changes are work that's still pending too, even though they
+	// can only be planned in a later round.
+	runningOp.PlanEmpty = !plan.CanApply() && !plan.HasDeferredChanges()
+
+	// Check the planned changes against the policies, if any. An incomplete
+	// plan can't be checked, but its errors will be reported anyway. The
+	// violations are recorded in the plan so that a saved plan that violates
+	// mandatory policies can't be applied.
+	if len(policies) != 0 && !plan.Errored {
+		diags = diags.Append(lr.Core.EvalPolicies(lr.Config, plan, policies))
+	}
 
 	// Save the plan to disk
 	if path := op.PlanOutPath; path != "" {
@@ -170,7 +196,7 @@ func (b *Local) opPlan(
 			StateFile:            plannedStateFile,
 			Plan:                 plan,
 			DependencyLocks:      op.DependencyLocks,
-		})
+		}, op.Encryption.Plan())
 		if err != nil {
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
@@ -208,7 +234,7 @@ func (b *Local) opPlan(
 	// creating it.
 	op.ReportResult(runningOp, diags)
 
-	if !runningOp.PlanEmpty {
+	if plan.CanApply() && !plan.HasMandatoryPolicyViolations() {
 		if wroteConfig {
 			op.View.PlanNextStep(op.PlanOutPath, op.GenerateConfigOut)
 		} else {
@@ -19,6 +19,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/depsfile"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/initwd"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
@@ -714,6 +715,84 @@ func TestLocal_planOutPathNoChange(t *testing.T) {
 	}
 }
 
+func TestLocal_planPolicyViolation(t *testing.T) {
+	b := TestLocal(t)
+	TestLocalProvider(t, b, "test", planFixtureSchema())
+
+	policyDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(policyDir, "main.tfpolicy.hcl"), []byte(`
+policy "no_bar" {
+  resource_type = "test_instance"
+
+  rule {
+    condition     = resource.after.ami != "bar"
+    error_message = "The bar AMI is not allowed."
+  }
+}
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	planPath := filepath.Join(t.TempDir(), "plan.tfplan")
+
+	op, configCleanup, done := testOperationPlan(t, "./testdata/plan")
+	defer configCleanup()
+	op.PlanOutPath = planPath
+	op.PolicyDir = policyDir
+	cfg := cty.ObjectVal(map[string]cty.Value{
+		"path": cty.StringVal(b.StatePath),
+	})
+	cfgRaw, err := plans.NewDynamicValue(cfg, cfg.Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	op.PlanOutBackend = &plans.Backend{
+		// Just a placeholder so that we can generate a valid plan file.
+		Type:   "local",
+		Config: cfgRaw,
+	}
+
+	run, err := b.Operation(context.Background(), op)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	<-run.Done()
+	if run.Result != backend.OperationFailure {
+		t.Fatalf("plan operation succeeded; want failure")
+	}
+	if got, want := done(t).Stderr(), `Policy "no_bar" violated`; !strings.Contains(got, want) {
+		t.Errorf("wrong error output\ngot:\n%s\nwant: containing %q", got, want)
+	}
+
+	// The violation is recorded in the saved plan, which can't be applied.
+	plan := testReadPlan(t, planPath)
+	if !plan.HasMandatoryPolicyViolations() {
+		t.Fatalf("saved plan has no mandatory policy violations")
+	}
+
+	planFile, err := planfile.OpenWrapped(planPath, encryption.PlanEncryptionDisabled())
+	if err != nil {
+		t.Fatal(err)
+	}
+	applyOp, applyConfigCleanup, applyDone := testOperationApply(t, "./testdata/plan")
+	defer applyConfigCleanup()
+	applyOp.PlanFile = planFile
+	applyOp.Encryption = encryption.Disabled()
+
+	run, err = b.Operation(context.Background(), applyOp)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	<-run.Done()
+	if run.Result != backend.OperationFailure {
+		t.Fatalf("apply operation succeeded; want failure")
+	}
+	if got, want := applyDone(t).Stderr(), "Cannot apply plan that violates policies"; !strings.Contains(got, want) {
+		t.Errorf("wrong error output\ngot:\n%s\nwant: containing %q", got, want)
+	}
+}
+
 func testOperationPlan(t *testing.T, configDir string) (*backend.Operation, func(), func(*testing.T) *terminal.TestOutput) {
 	t.Helper()
 
@@ -734,6 +813,7 @@ func testOperationPlan(t *testing.T, configDir string) (*backend.Operation, func
 		StateLocker:     clistate.NewNoopLocker(),
 		View:            view,
 		DependencyLocks: depLocks,
+		Encryption:      encryption.Disabled(),
 	}, configCleanup, done
 }
 
@@ -839,7 +919,7 @@ func testPlanState_tainted() *states.State {
 func testReadPlan(t *testing.T, path string) *plans.Plan {
 	t.Helper()
 
-	p, err := planfile.Open(path)
+	p, err := planfile.Open(path, encryption.PlanEncryptionDisabled())
 	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
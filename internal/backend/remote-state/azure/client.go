@@ -10,15 +10,22 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-uuid"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/tombuildsstuff/giovanni/storage/2018-11-09/blob/blobs"
+	"github.com/tombuildsstuff/giovanni/storage/2018-11-09/blob/containers"
 )
 
 const (
+	// currentVersionID is the version ID we use for the blob itself, as
+	// opposed to its snapshots which are identified by their timestamps.
+	currentVersionID = "current"
+
 	leaseHeader = "x-ms-lease-id"
 	// Must be lower case
 	lockInfoMetaKey = "terraformlockid"
@@ -31,6 +38,10 @@ type RemoteClient struct {
 	keyName            string
 	leaseID            string
 	snapshot           bool
+
+	// armClient is used to build the containers client that lists the
+	// snapshots of the blob, which we only need for the state history.
+	armClient *ArmClient
 }
 
 func (c *RemoteClient) Get() (*remote.Payload, error) {
@@ -119,6 +130,118 @@ func (c *RemoteClient) Delete() error {
 	return nil
 }
 
+// Versions implements remote.ClientHistory, using the blob itself and the
+// snapshots of it created when the "snapshot" option is enabled.
+func (c *RemoteClient) Versions(limit int) ([]*remote.PayloadVersion, error) {
+	ctx := context.TODO()
+	client, err := c.armClient.getContainersClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	include := []containers.Dataset{containers.Snapshots}
+	params := containers.ListBlobsInput{
+		Prefix:  &c.keyName,
+		Include: &include,
+	}
+
+	var current *remote.PayloadVersion
+	var snapshots []*remote.PayloadVersion
+	for {
+		resp, err := client.ListBlobs(ctx, c.accountName, c.containerName, params)
+		if err != nil {
+			return nil, fmt.Errorf("error listing snapshots of Blob %q (Container %q / Account %q): %w", c.keyName, c.containerName, c.accountName, err)
+		}
+
+		for _, blob := range resp.Blobs.Blobs {
+			// The prefix can also match the states of other workspaces.
+			if blob.Name != c.keyName {
+				continue
+			}
+
+			if blob.Snapshot == nil {
+				current = &remote.PayloadVersion{ID: currentVersionID}
+				if blob.Properties != nil && blob.Properties.LastModified != nil {
+					current.Timestamp, _ = time.Parse(time.RFC1123, *blob.Properties.LastModified)
+				}
+				continue
+			}
+
+			timestamp, err := time.Parse(time.RFC3339Nano, *blob.Snapshot)
+			if err != nil {
+				return nil, fmt.Errorf("invalid snapshot timestamp %q for Blob %q: %w", *blob.Snapshot, c.keyName, err)
+			}
+			snapshots = append(snapshots, &remote.PayloadVersion{
+				ID:        *blob.Snapshot,
+				Timestamp: timestamp,
+			})
+		}
+
+		if resp.NextMarker == nil || *resp.NextMarker == "" {
+			break
+		}
+		params.Marker = resp.NextMarker
+	}
+
+	if len(snapshots) == 0 && !c.snapshot {
+		return nil, statemgr.ErrHistoryUnsupported
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Timestamp.After(snapshots[j].Timestamp)
+	})
+
+	var ret []*remote.PayloadVersion
+	if current != nil {
+		ret = append(ret, current)
+	}
+	ret = append(ret, snapshots...)
+	if limit > 0 && len(ret) > limit {
+		ret = ret[:limit]
+	}
+	return ret, nil
+}
+
+// GetVersion implements remote.ClientHistory.
+func (c *RemoteClient) GetVersion(id string) (*remote.Payload, error) {
+	if id == currentVersionID {
+		return c.Get()
+	}
+	if _, err := time.Parse(time.RFC3339Nano, id); err != nil {
+		return nil, nil
+	}
+
+	// The blobs client has no way to read a snapshot, so we add the
+	// snapshot parameter to an otherwise normal request for the blob.
+	ctx := context.TODO()
+	req, err := c.giovanniBlobClient.GetPreparer(ctx, c.accountName, c.containerName, c.keyName, blobs.GetInput{})
+	if err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	query.Set("snapshot", id)
+	req.URL.RawQuery = query.Encode()
+
+	resp, err := c.giovanniBlobClient.GetSender(req)
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot %q of Blob %q: %w", id, c.keyName, err)
+	}
+	blob, err := c.giovanniBlobClient.GetResponder(resp)
+	if err != nil {
+		if blob.Response.IsHTTPStatus(http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading snapshot %q of Blob %q: %w", id, c.keyName, err)
+	}
+
+	if len(blob.Contents) == 0 {
+		return nil, nil
+	}
+	return &remote.Payload{
+		Data: blob.Contents,
+	}, nil
+}
+
 func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
 	stateName := fmt.Sprintf("%s/%s", c.containerName, c.keyName)
 	info.Path = stateName
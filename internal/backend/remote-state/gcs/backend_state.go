@@ -96,7 +96,7 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 		return nil, err
 	}
 
-	st := &remote.State{Client: c}
+	st := remote.NewState(c, b.encryption)
 
 	// Grab the value
 	if err := st.RefreshState(); err != nil {
@@ -16,6 +16,7 @@ import (
 	kms "cloud.google.com/go/kms/apiv1"
 	"cloud.google.com/go/storage"
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/httpclient"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/version"
@@ -111,6 +112,32 @@ func TestRemoteClientWithEncryption(t *testing.T) {
 	remote.TestClient(t, rs.Client)
 }
 
+func TestRemoteClientHistory(t *testing.T) {
+	t.Parallel()
+
+	bucket := bucketName(t)
+	be := setupBackend(t, bucket, noPrefix, noEncryptionKey, noKmsKeyName)
+	defer teardownBackend(t, be, noPrefix)
+
+	gcsBE := be.(*Backend)
+	_, err := gcsBE.storageClient.Bucket(bucket).Update(gcsBE.storageContext, storage.BucketAttrsToUpdate{VersioningEnabled: true})
+	if err != nil {
+		t.Fatalf("enabling object versioning failed: %v", err)
+	}
+
+	ss, err := be.StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatalf("be.StateMgr(%q) = %v", backend.DefaultStateName, err)
+	}
+
+	rs, ok := ss.(*remote.State)
+	if !ok {
+		t.Fatalf("be.StateMgr(): got a %T, want a *remote.State", ss)
+	}
+
+	remote.TestClientHistory(t, rs.Client)
+}
+
 func TestRemoteLocks(t *testing.T) {
 	t.Parallel()
 
@@ -236,7 +263,7 @@ func setupBackend(t *testing.T, bucket, prefix, key, kmsName string) backend.Bac
 		config["kms_encryption_key"] = kmsName
 	}
 
-	b := backend.TestBackendConfig(t, New(), backend.TestWrapConfig(config))
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(config))
 	be := b.(*Backend)
 
 	// create the bucket if it doesn't exist
@@ -383,10 +410,12 @@ func teardownBackend(t *testing.T, be backend.Backend, prefix string) {
 	ctx := gcsBE.storageContext
 
 	bucket := gcsBE.storageClient.Bucket(gcsBE.bucketName)
-	objs := bucket.Objects(ctx, nil)
+	// We list all generations, so that buckets with object versioning
+	// enabled are also left empty.
+	objs := bucket.Objects(ctx, &storage.Query{Versions: true})
 
 	for o, err := objs.Next(); err == nil; o, err = objs.Next() {
-		if err := bucket.Object(o.Name).Delete(ctx); err != nil {
+		if err := bucket.Object(o.Name).Generation(o.Generation).Delete(ctx); err != nil {
 			log.Printf("Error trying to delete object: %s %s\n\n", o.Name, err)
 		} else {
 			log.Printf("Object deleted: %s", o.Name)
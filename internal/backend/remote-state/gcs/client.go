@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 
 	"cloud.google.com/go/storage"
@@ -14,6 +15,7 @@ import (
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"golang.org/x/net/context"
+	"google.golang.org/api/iterator"
 )
 
 // remoteClient is used by "state/remote".State to read and write
@@ -84,6 +86,80 @@ func (c *remoteClient) Delete() error {
 	return nil
 }
 
+// Versions implements remote.ClientHistory, using the noncurrent
+// generations of the state object in a bucket with object versioning
+// enabled.
+func (c *remoteClient) Versions(limit int) ([]*remote.PayloadVersion, error) {
+	query := &storage.Query{
+		Prefix:   c.stateFilePath,
+		Versions: true,
+	}
+
+	var ret []*remote.PayloadVersion
+	it := c.storageClient.Bucket(c.bucketName).Objects(c.storageContext, query)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list state versions of %v: %w", c.stateFileURL(), err)
+		}
+		// The prefix can also match the lock file and the states of
+		// other workspaces.
+		if attrs.Name != c.stateFilePath {
+			continue
+		}
+		ret = append(ret, &remote.PayloadVersion{
+			ID:        strconv.FormatInt(attrs.Generation, 10),
+			Timestamp: attrs.Created,
+		})
+	}
+
+	// Generations increase over time, but the listing returns them oldest
+	// first, so we can only apply the limit once we have all of them.
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Timestamp.After(ret[j].Timestamp)
+	})
+	if limit > 0 && len(ret) > limit {
+		ret = ret[:limit]
+	}
+	return ret, nil
+}
+
+// GetVersion implements remote.ClientHistory.
+func (c *remoteClient) GetVersion(id string) (*remote.Payload, error) {
+	generation, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+
+	obj := c.stateFile().Generation(generation)
+	reader, err := obj.NewReader(c.storageContext)
+	if err != nil {
+		if err == storage.ErrObjectNotExist {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Failed to open state version %s of %v: %w", id, c.stateFileURL(), err)
+	}
+	defer reader.Close()
+
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read state version %s of %v: %w", id, c.stateFileURL(), err)
+	}
+
+	attrs, err := obj.Attrs(c.storageContext)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read state version %s attrs of %v: %w", id, c.stateFileURL(), err)
+	}
+
+	return &remote.Payload{
+		Data: contents,
+		MD5:  attrs.MD5,
+	}, nil
+}
+
 // Lock writes to a lock file, ensuring file creation. Returns the generation
 // number, which must be passed to Unlock().
 func (c *remoteClient) Lock(info *statemgr.LockInfo) (string, error) {
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/legacy/helper/schema"
 	statespkg "github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/remote"
@@ -44,7 +45,7 @@ func Reset() {
 }
 
 // New creates a new backend for Inmem remote state.
-func New() backend.Backend {
+func New(enc encryption.StateEncryption) backend.Backend {
 	// Set the schema
 	s := &schema.Backend{
 		Schema: map[string]*schema.Schema{
@@ -55,13 +56,14 @@ func New() backend.Backend {
 			},
 		},
 	}
-	backend := &Backend{Backend: s}
+	backend := &Backend{Backend: s, encryption: enc}
 	backend.Backend.ConfigureFunc = backend.configure
 	return backend
 }
 
 type Backend struct {
 	*schema.Backend
+	encryption encryption.StateEncryption
 }
 
 func (b *Backend) configure(ctx context.Context) error {
@@ -72,9 +74,7 @@ func (b *Backend) configure(ctx context.Context) error {
 		Name: backend.DefaultStateName,
 	}
 
-	states.m[backend.DefaultStateName] = &remote.State{
-		Client: defaultClient,
-	}
+	states.m[backend.DefaultStateName] = remote.NewState(defaultClient, b.encryption)
 
 	// set the default client lock info per the test config
 	data := schema.FromContextBackendConfig(ctx)
@@ -122,11 +122,12 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 
 	s := states.m[name]
 	if s == nil {
-		s = &remote.State{
-			Client: &RemoteClient{
+		s = remote.NewState(
+			&RemoteClient{
 				Name: name,
 			},
-		}
+			b.encryption,
+		)
 		states.m[name] = s
 
 		// to most closely replicate other implementations, we are going to
@@ -12,12 +12,15 @@ import (
 
 	"github.com/lib/pq"
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/legacy/helper/schema"
 )
 
 const (
-	statesTableName = "states"
-	statesIndexName = "states_by_name"
+	statesTableName        = "states"
+	statesIndexName        = "states_by_name"
+	statesHistoryTableName = "states_history"
+	statesHistoryIndexName = "states_history_by_name"
 )
 
 func defaultBoolFunc(k string, dv bool) schema.SchemaDefaultFunc {
@@ -31,7 +34,7 @@ func defaultBoolFunc(k string, dv bool) schema.SchemaDefaultFunc {
 }
 
 // New creates a new backend for Postgres remote state.
-func New() backend.Backend {
+func New(enc encryption.StateEncryption) backend.Backend {
 	s := &schema.Backend{
 		Schema: map[string]*schema.Schema{
 			"conn_str": {
@@ -68,22 +71,31 @@ func New() backend.Backend {
 				Description: "If set to `true`, OpenTofu won't try to create the Postgres index",
 				DefaultFunc: defaultBoolFunc("PG_SKIP_INDEX_CREATION", false),
 			},
+
+			"keep_history": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "If set to `true`, OpenTofu keeps every state snapshot it writes in a history table",
+				DefaultFunc: defaultBoolFunc("PG_KEEP_HISTORY", false),
+			},
 		},
 	}
 
-	result := &Backend{Backend: s}
+	result := &Backend{Backend: s, encryption: enc}
 	result.Backend.ConfigureFunc = result.configure
 	return result
 }
 
 type Backend struct {
 	*schema.Backend
+	encryption encryption.StateEncryption
 
 	// The fields below are set from configure
-	db         *sql.DB
-	configData *schema.ResourceData
-	connStr    string
-	schemaName string
+	db          *sql.DB
+	configData  *schema.ResourceData
+	connStr     string
+	schemaName  string
+	keepHistory bool
 }
 
 func (b *Backend) configure(ctx context.Context) error {
@@ -93,6 +105,7 @@ func (b *Backend) configure(ctx context.Context) error {
 
 	b.connStr = data.Get("conn_str").(string)
 	b.schemaName = pq.QuoteIdentifier(data.Get("schema_name").(string))
+	b.keepHistory = data.Get("keep_history").(bool)
 
 	db, err := sql.Open("postgres", b.connStr)
 	if err != nil {
@@ -135,6 +148,20 @@ func (b *Backend) configure(ctx context.Context) error {
 		if _, err := db.Exec(fmt.Sprintf(query, b.schemaName, statesTableName)); err != nil {
 			return err
 		}
+
+		if b.keepHistory {
+			query = `CREATE TABLE IF NOT EXISTS %s.%s (
+				id bigserial PRIMARY KEY,
+				name text NOT NULL,
+				data text,
+				serial bigint,
+				lineage text,
+				created_at timestamptz NOT NULL DEFAULT now()
+				)`
+			if _, err := db.Exec(fmt.Sprintf(query, b.schemaName, statesHistoryTableName)); err != nil {
+				return err
+			}
+		}
 	}
 
 	if !data.Get("skip_index_creation").(bool) {
@@ -142,6 +169,13 @@ func (b *Backend) configure(ctx context.Context) error {
 		if _, err := db.Exec(fmt.Sprintf(query, statesIndexName, b.schemaName, statesTableName)); err != nil {
 			return err
 		}
+
+		if b.keepHistory {
+			query = `CREATE INDEX IF NOT EXISTS %s ON %s.%s (name)`
+			if _, err := db.Exec(fmt.Sprintf(query, statesHistoryIndexName, b.schemaName, statesHistoryTableName)); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Assign db after its schema is prepared.
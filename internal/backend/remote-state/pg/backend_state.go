@@ -49,18 +49,27 @@ func (b *Backend) DeleteWorkspace(name string, _ bool) error {
 		return err
 	}
 
+	if b.keepHistory {
+		_, err = b.db.Exec(fmt.Sprintf(query, b.schemaName, statesHistoryTableName), name)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 	// Build the state client
-	var stateMgr statemgr.Full = &remote.State{
-		Client: &RemoteClient{
-			Client:     b.db,
-			Name:       name,
-			SchemaName: b.schemaName,
+	var stateMgr statemgr.Full = remote.NewState(
+		&RemoteClient{
+			Client:      b.db,
+			Name:        name,
+			SchemaName:  b.schemaName,
+			KeepHistory: b.keepHistory,
 		},
-	}
+		b.encryption,
+	)
 
 	// Check to see if this state already exists.
 	// If the state doesn't exist, we have to assume this
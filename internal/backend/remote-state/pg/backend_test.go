@@ -17,6 +17,7 @@ import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/lib/pq"
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -160,7 +161,7 @@ func TestBackendConfig(t *testing.T) {
 			defer dbCleaner.Query(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", schemaName))
 
 			var diags tfdiags.Diagnostics
-			b := New().(*Backend)
+			b := New(encryption.StateEncryptionDisabled()).(*Backend)
 			schema := b.ConfigSchema()
 			spec := schema.DecoderSpec()
 			obj, decDiags := hcldec.Decode(config, spec, nil)
@@ -323,7 +324,7 @@ func TestBackendConfigSkipOptions(t *testing.T) {
 			}
 			defer db.Query(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", schemaName))
 
-			b := backend.TestBackendConfig(t, New(), config).(*Backend)
+			b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
 
 			if b == nil {
 				t.Fatal("Backend could not be configured")
@@ -396,7 +397,7 @@ func TestBackendStates(t *testing.T) {
 				"conn_str":    connStr,
 				"schema_name": schemaName,
 			})
-			b := backend.TestBackendConfig(t, New(), config).(*Backend)
+			b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
 
 			if b == nil {
 				t.Fatal("Backend could not be configured")
@@ -421,13 +422,13 @@ func TestBackendStateLocks(t *testing.T) {
 		"conn_str":    connStr,
 		"schema_name": schemaName,
 	})
-	b := backend.TestBackendConfig(t, New(), config).(*Backend)
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
 
 	if b == nil {
 		t.Fatal("Backend could not be configured")
 	}
 
-	bb := backend.TestBackendConfig(t, New(), config).(*Backend)
+	bb := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
 
 	if bb == nil {
 		t.Fatal("Backend could not be configured")
@@ -450,7 +451,7 @@ func TestBackendConcurrentLock(t *testing.T) {
 			"conn_str":    connStr,
 			"schema_name": schemaName,
 		})
-		b := backend.TestBackendConfig(t, New(), config).(*Backend)
+		b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
 
 		if b == nil {
 			t.Fatal("Backend could not be configured")
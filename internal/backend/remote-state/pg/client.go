@@ -6,7 +6,10 @@ package pg
 import (
 	"crypto/md5"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"time"
 
 	uuid "github.com/hashicorp/go-uuid"
 	_ "github.com/lib/pq"
@@ -20,6 +23,10 @@ type RemoteClient struct {
 	Name       string
 	SchemaName string
 
+	// KeepHistory enables recording each snapshot written by Put in the
+	// history table, so that it can be listed by Versions.
+	KeepHistory bool
+
 	info *statemgr.LockInfo
 }
 
@@ -47,11 +54,28 @@ func (c *RemoteClient) Put(data []byte) error {
 	query := `INSERT INTO %s.%s (name, data) VALUES ($1, $2)
 		ON CONFLICT (name) DO UPDATE
 		SET data = $2 WHERE %s.name = $1`
-	_, err := c.Client.Exec(fmt.Sprintf(query, c.SchemaName, statesTableName, statesTableName), c.Name, data)
+	if !c.KeepHistory {
+		_, err := c.Client.Exec(fmt.Sprintf(query, c.SchemaName, statesTableName, statesTableName), c.Name, data)
+		return err
+	}
+
+	// The state and its history entry are written together, so that the
+	// latest history entry always matches the state.
+	tx, err := c.Client.Begin()
 	if err != nil {
 		return err
 	}
-	return nil
+	if _, err := tx.Exec(fmt.Sprintf(query, c.SchemaName, statesTableName, statesTableName), c.Name, data); err != nil {
+		tx.Rollback()
+		return err
+	}
+	serial, lineage := historySnapshotMeta(data)
+	historyQuery := `INSERT INTO %s.%s (name, data, serial, lineage) VALUES ($1, $2, $3, $4)`
+	if _, err := tx.Exec(fmt.Sprintf(historyQuery, c.SchemaName, statesHistoryTableName), c.Name, data, serial, lineage); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
 }
 
 func (c *RemoteClient) Delete() error {
@@ -60,9 +84,99 @@ func (c *RemoteClient) Delete() error {
 	if err != nil {
 		return err
 	}
+	if c.KeepHistory {
+		_, err = c.Client.Exec(fmt.Sprintf(query, c.SchemaName, statesHistoryTableName), c.Name)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// Versions implements remote.ClientHistory, using the history table that is
+// written when KeepHistory is enabled.
+func (c *RemoteClient) Versions(limit int) ([]*remote.PayloadVersion, error) {
+	if !c.KeepHistory {
+		return nil, statemgr.ErrHistoryUnsupported
+	}
+
+	query := `SELECT id, created_at, serial, lineage FROM %s.%s WHERE name = $1 ORDER BY id DESC`
+	args := []interface{}{c.Name}
+	if limit > 0 {
+		query += ` LIMIT $2`
+		args = append(args, limit)
+	}
+	rows, err := c.Client.Query(fmt.Sprintf(query, c.SchemaName, statesHistoryTableName), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret []*remote.PayloadVersion
+	for rows.Next() {
+		var id int64
+		var createdAt time.Time
+		var serial sql.NullInt64
+		var lineage sql.NullString
+		if err := rows.Scan(&id, &createdAt, &serial, &lineage); err != nil {
+			return nil, err
+		}
+		version := &remote.PayloadVersion{
+			ID:        strconv.FormatInt(id, 10),
+			Timestamp: createdAt,
+		}
+		if serial.Valid && lineage.Valid {
+			version.Serial = uint64(serial.Int64)
+			version.Lineage = lineage.String
+		}
+		ret = append(ret, version)
+	}
+	return ret, rows.Err()
+}
+
+// historySnapshotMeta returns the serial and lineage of the given state
+// snapshot, to record alongside it in the history table. Both are null if
+// they can't be found, such as when the state is encrypted.
+func historySnapshotMeta(data []byte) (sql.NullInt64, sql.NullString) {
+	var meta struct {
+		Serial  *int64 `json:"serial"`
+		Lineage string `json:"lineage"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil || meta.Serial == nil || meta.Lineage == "" {
+		return sql.NullInt64{}, sql.NullString{}
+	}
+	return sql.NullInt64{Int64: *meta.Serial, Valid: true}, sql.NullString{String: meta.Lineage, Valid: true}
+}
+
+// GetVersion implements remote.ClientHistory.
+func (c *RemoteClient) GetVersion(id string) (*remote.Payload, error) {
+	if !c.KeepHistory {
+		return nil, statemgr.ErrHistoryUnsupported
+	}
+
+	historyID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+
+	query := `SELECT data FROM %s.%s WHERE name = $1 AND id = $2`
+	row := c.Client.QueryRow(fmt.Sprintf(query, c.SchemaName, statesHistoryTableName), c.Name, historyID)
+	var data []byte
+	err = row.Scan(&data)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	default:
+		md5 := md5.Sum(data)
+		return &remote.Payload{
+			Data: data,
+			MD5:  md5[:],
+		}, nil
+	}
+}
+
 func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
 	var err error
 	var lockID string
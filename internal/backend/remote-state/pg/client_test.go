@@ -12,12 +12,14 @@ import (
 	"testing"
 
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states/remote"
 )
 
 func TestRemoteClient_impl(t *testing.T) {
 	var _ remote.Client = new(RemoteClient)
 	var _ remote.ClientLocker = new(RemoteClient)
+	var _ remote.ClientHistory = new(RemoteClient)
 }
 
 func TestRemoteClient(t *testing.T) {
@@ -34,7 +36,7 @@ func TestRemoteClient(t *testing.T) {
 		"conn_str":    connStr,
 		"schema_name": schemaName,
 	})
-	b := backend.TestBackendConfig(t, New(), config).(*Backend)
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
 
 	if b == nil {
 		t.Fatal("Backend could not be configured")
@@ -48,6 +50,48 @@ func TestRemoteClient(t *testing.T) {
 	remote.TestClient(t, s.(*remote.State).Client)
 }
 
+func TestRemoteClientHistory(t *testing.T) {
+	testACC(t)
+	connStr := getDatabaseUrl()
+	schemaName := fmt.Sprintf("terraform_%s", t.Name())
+	dbCleaner, err := sql.Open("postgres", connStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbCleaner.Query(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", schemaName))
+
+	config := backend.TestWrapConfig(map[string]interface{}{
+		"conn_str":     connStr,
+		"schema_name":  schemaName,
+		"keep_history": true,
+	})
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
+
+	if b == nil {
+		t.Fatal("Backend could not be configured")
+	}
+
+	s, err := b.StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote.TestClientHistory(t, s.(*remote.State).Client)
+}
+
+func TestHistorySnapshotMeta(t *testing.T) {
+	serial, lineage := historySnapshotMeta([]byte(`{"version": 4, "serial": 3, "lineage": "abc"}`))
+	if !serial.Valid || serial.Int64 != 3 || !lineage.Valid || lineage.String != "abc" {
+		t.Errorf("wrong result for a state snapshot: %v %v", serial, lineage)
+	}
+
+	// An encrypted snapshot has no serial or lineage that we can read.
+	serial, lineage = historySnapshotMeta([]byte(`{"encrypted_data": "", "encryption_version": "v0"}`))
+	if serial.Valid || lineage.Valid {
+		t.Errorf("wrong result for an encrypted snapshot: %v %v", serial, lineage)
+	}
+}
+
 func TestRemoteLocks(t *testing.T) {
 	testACC(t)
 	connStr := getDatabaseUrl()
@@ -63,13 +107,13 @@ func TestRemoteLocks(t *testing.T) {
 		"schema_name": schemaName,
 	})
 
-	b1 := backend.TestBackendConfig(t, New(), config).(*Backend)
+	b1 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
 	s1, err := b1.StateMgr(backend.DefaultStateName)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	b2 := backend.TestBackendConfig(t, New(), config).(*Backend)
+	b2 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config).(*Backend)
 	s2, err := b2.StateMgr(backend.DefaultStateName)
 	if err != nil {
 		t.Fatal(err)
@@ -22,6 +22,8 @@ import (
 	dtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	multierror "github.com/hashicorp/go-multierror"
 	uuid "github.com/hashicorp/go-uuid"
 
@@ -34,6 +36,10 @@ const (
 	s3EncryptionAlgorithm  = "AES256"
 	stateIDSuffix          = "-md5"
 	s3ErrCodeInternalError = "InternalError"
+
+	// lockFileSuffix is appended to the state key to name the object that
+	// holds the lock when use_lockfile is enabled.
+	lockFileSuffix = ".tflock"
 )
 
 type RemoteClient struct {
@@ -46,6 +52,7 @@ type RemoteClient struct {
 	acl                   string
 	kmsKeyID              string
 	ddbTable              string
+	useLockFile           bool
 
 	skipS3Checksum bool
 }
@@ -118,11 +125,7 @@ func (c *RemoteClient) get(ctx context.Context) (*remote.Payload, error) {
 		Key:    &c.path,
 	}
 
-	if c.serverSideEncryption && c.customerEncryptionKey != nil {
-		inputHead.SSECustomerKey = aws.String(base64.StdEncoding.EncodeToString(c.customerEncryptionKey))
-		inputHead.SSECustomerAlgorithm = aws.String(s3EncryptionAlgorithm)
-		inputHead.SSECustomerKeyMD5 = aws.String(c.getSSECustomerKeyMD5())
-	}
+	c.configureHeadObject(inputHead)
 
 	// Head works around some s3 compatible backends not handling missing GetObject requests correctly (ex: minio Get returns Missing Bucket)
 	_, err = c.s3Client.HeadObject(ctx, inputHead)
@@ -145,11 +148,7 @@ func (c *RemoteClient) get(ctx context.Context) (*remote.Payload, error) {
 		Key:    &c.path,
 	}
 
-	if c.serverSideEncryption && c.customerEncryptionKey != nil {
-		input.SSECustomerKey = aws.String(base64.StdEncoding.EncodeToString(c.customerEncryptionKey))
-		input.SSECustomerAlgorithm = aws.String(s3EncryptionAlgorithm)
-		input.SSECustomerKeyMD5 = aws.String(c.getSSECustomerKeyMD5())
-	}
+	c.configureGetObject(input)
 
 	output, err = c.s3Client.GetObject(ctx, input)
 	if err != nil {
@@ -199,6 +198,32 @@ func (c *RemoteClient) Put(data []byte) error {
 		Key:           &c.path,
 	}
 
+	c.configurePutObject(i, data)
+
+	log.Printf("[DEBUG] Uploading remote state to S3: %#v", i)
+
+	ctx := context.TODO()
+	ctx, _ = attachLoggerToContext(ctx)
+
+	_, err := c.s3Client.PutObject(ctx, i)
+	if err != nil {
+		return fmt.Errorf("failed to upload state: %w", err)
+	}
+
+	sum := md5.Sum(data)
+	if err := c.putMD5(ctx, sum[:]); err != nil {
+		// if this errors out, we unfortunately have to error out altogether,
+		// since the next Get will inevitably fail.
+		return fmt.Errorf("failed to store state MD5: %w", err)
+
+	}
+
+	return nil
+}
+
+// configurePutObject sets the checksum, encryption and ACL settings shared by
+// all of the objects the client uploads.
+func (c *RemoteClient) configurePutObject(i *s3.PutObjectInput, data []byte) {
 	if !c.skipS3Checksum {
 		i.ChecksumAlgorithm = types.ChecksumAlgorithmSha256
 
@@ -227,26 +252,33 @@ func (c *RemoteClient) Put(data []byte) error {
 	if c.acl != "" {
 		i.ACL = types.ObjectCannedACL(c.acl)
 	}
+}
 
-	log.Printf("[DEBUG] Uploading remote state to S3: %#v", i)
-
-	ctx := context.TODO()
-	ctx, _ = attachLoggerToContext(ctx)
-
-	_, err := c.s3Client.PutObject(ctx, i)
-	if err != nil {
-		return fmt.Errorf("failed to upload state: %w", err)
+// configureGetObject sets the server-side encryption options needed to read
+// an object written with the options set by configurePutObject. Objects
+// encrypted with SSE-S3 or SSE-KMS are decrypted transparently, so only a
+// customer-provided key needs to be sent.
+func (c *RemoteClient) configureGetObject(i *s3.GetObjectInput) {
+	if c.useSSECustomerKey() {
+		i.SSECustomerKey = aws.String(base64.StdEncoding.EncodeToString(c.customerEncryptionKey))
+		i.SSECustomerAlgorithm = aws.String(s3EncryptionAlgorithm)
+		i.SSECustomerKeyMD5 = aws.String(c.getSSECustomerKeyMD5())
 	}
+}
 
-	sum := md5.Sum(data)
-	if err := c.putMD5(ctx, sum[:]); err != nil {
-		// if this errors out, we unfortunately have to error out altogether,
-		// since the next Get will inevitably fail.
-		return fmt.Errorf("failed to store state MD5: %w", err)
-
+// configureHeadObject is like configureGetObject, but for HeadObject requests.
+func (c *RemoteClient) configureHeadObject(i *s3.HeadObjectInput) {
+	if c.useSSECustomerKey() {
+		i.SSECustomerKey = aws.String(base64.StdEncoding.EncodeToString(c.customerEncryptionKey))
+		i.SSECustomerAlgorithm = aws.String(s3EncryptionAlgorithm)
+		i.SSECustomerKeyMD5 = aws.String(c.getSSECustomerKeyMD5())
 	}
+}
 
-	return nil
+// useSSECustomerKey returns true if objects are encrypted with the
+// customer-provided key, following the same precedence as configurePutObject.
+func (c *RemoteClient) useSSECustomerKey() bool {
+	return c.serverSideEncryption && c.kmsKeyID == "" && c.customerEncryptionKey != nil
 }
 
 func (c *RemoteClient) Delete() error {
@@ -269,8 +301,104 @@ func (c *RemoteClient) Delete() error {
 	return nil
 }
 
+// Versions implements remote.ClientHistory, using the object versions of
+// the state object in a bucket with versioning enabled.
+func (c *RemoteClient) Versions(limit int) ([]*remote.PayloadVersion, error) {
+	ctx := context.TODO()
+	ctx, _ = attachLoggerToContext(ctx)
+
+	input := &s3.ListObjectVersionsInput{
+		Bucket: &c.bucketName,
+		Prefix: &c.path,
+	}
+
+	var ret []*remote.PayloadVersion
+	unversioned := false
+	paginator := s3.NewListObjectVersionsPaginator(c.s3Client, input)
+	for paginator.HasMorePages() && (limit <= 0 || len(ret) < limit) {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			var nb *types.NoSuchBucket
+			if errors.As(err, &nb) {
+				return nil, fmt.Errorf(errS3NoSuchBucket, err)
+			}
+			return nil, fmt.Errorf("failed to list state versions: %w", err)
+		}
+
+		// S3 returns the versions of each key from newest to oldest, and
+		// the prefix can also match other keys which we must skip.
+		for _, v := range page.Versions {
+			if aws.ToString(v.Key) != c.path {
+				continue
+			}
+			// Objects written while versioning was never enabled have
+			// the version ID "null" and can't be told apart.
+			if aws.ToString(v.VersionId) == "null" {
+				unversioned = true
+				continue
+			}
+			ret = append(ret, &remote.PayloadVersion{
+				ID:        aws.ToString(v.VersionId),
+				Timestamp: aws.ToTime(v.LastModified),
+			})
+			if limit > 0 && len(ret) == limit {
+				break
+			}
+		}
+	}
+
+	if len(ret) == 0 && unversioned {
+		return nil, statemgr.ErrHistoryUnsupported
+	}
+	return ret, nil
+}
+
+// GetVersion implements remote.ClientHistory.
+func (c *RemoteClient) GetVersion(id string) (*remote.Payload, error) {
+	ctx := context.TODO()
+	ctx, _ = attachLoggerToContext(ctx)
+
+	input := &s3.GetObjectInput{
+		Bucket:    &c.bucketName,
+		Key:       &c.path,
+		VersionId: &id,
+	}
+
+	c.configureGetObject(input)
+
+	output, err := c.s3Client.GetObject(ctx, input)
+	if err != nil {
+		var nk *types.NoSuchKey
+		if errors.As(err, &nk) {
+			return nil, nil
+		}
+		// A version ID that doesn't exist is reported with a generic
+		// API error rather than a typed one.
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "NoSuchVersion" || apiErr.ErrorCode() == "InvalidArgument") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read state version %q: %w", id, err)
+	}
+	defer output.Body.Close()
+
+	buf := bytes.NewBuffer(nil)
+	if _, err := io.Copy(buf, output.Body); err != nil {
+		return nil, fmt.Errorf("Failed to read remote state: %w", err)
+	}
+	if buf.Len() == 0 {
+		return nil, nil
+	}
+
+	sum := md5.Sum(buf.Bytes())
+	return &remote.Payload{
+		Data: buf.Bytes(),
+		MD5:  sum[:],
+	}, nil
+}
+
 func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
-	if c.ddbTable == "" {
+	if c.ddbTable == "" && !c.useLockFile {
 		return "", nil
 	}
 
@@ -285,6 +413,126 @@ func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
 		info.ID = lockID
 	}
 
+	ctx := context.TODO()
+
+	if c.useLockFile {
+		if err := c.s3Lock(ctx, info); err != nil {
+			return "", err
+		}
+	}
+
+	if c.ddbTable != "" {
+		if err := c.dynamoDBLock(ctx, info); err != nil {
+			// Don't leave the lock file behind if we couldn't also take the
+			// DynamoDB lock, which happens while migrating between the two.
+			if c.useLockFile {
+				if unlockErr := c.s3Unlock(ctx, info.ID); unlockErr != nil {
+					err.Err = multierror.Append(err.Err, unlockErr)
+				}
+			}
+			return "", err
+		}
+	}
+
+	return info.ID, nil
+}
+
+// s3Lock takes the lock by creating the lock file, using a conditional write
+// so that the request fails if the lock file already exists.
+func (c *RemoteClient) s3Lock(ctx context.Context, info *statemgr.LockInfo) error {
+	data := info.Marshal()
+	contentType := "application/json"
+
+	i := &s3.PutObjectInput{
+		ContentType:   &contentType,
+		ContentLength: aws.Int64(int64(len(data))),
+		Body:          bytes.NewReader(data),
+		Bucket:        &c.bucketName,
+		Key:           aws.String(c.lockFilePath()),
+	}
+	c.configurePutObject(i, data)
+
+	ctx, _ = attachLoggerToContext(ctx)
+
+	// The SDK doesn't yet expose conditional writes for PutObject, so we
+	// set the If-None-Match header ourselves.
+	_, err := c.s3Client.PutObject(ctx, i, s3.WithAPIOptions(smithyhttp.AddHeaderValue("If-None-Match", "*")))
+	if err != nil {
+		lockInfo, infoErr := c.getS3LockInfo(ctx)
+		if infoErr != nil {
+			err = multierror.Append(err, infoErr)
+		}
+
+		return &statemgr.LockError{
+			Err:  err,
+			Info: lockInfo,
+		}
+	}
+
+	return nil
+}
+
+// s3Unlock releases the lock by deleting the lock file, as long as it still
+// holds the lock with the given ID.
+func (c *RemoteClient) s3Unlock(ctx context.Context, id string) *statemgr.LockError {
+	lockErr := &statemgr.LockError{}
+	ctx, _ = attachLoggerToContext(ctx)
+
+	lockInfo, err := c.getS3LockInfo(ctx)
+	if err != nil {
+		var nk *types.NoSuchKey
+		if errors.As(err, &nk) && c.ddbTable != "" {
+			// The lock may have been taken by a client that only uses
+			// DynamoDB, so there's no lock file for us to remove.
+			log.Printf("[DEBUG] no S3 lock file found for %s, skipping", c.lockFilePath())
+			return nil
+		}
+		lockErr.Err = fmt.Errorf("failed to retrieve lock info: %w", err)
+		return lockErr
+	}
+	lockErr.Info = lockInfo
+
+	if lockInfo.ID != id {
+		lockErr.Err = fmt.Errorf("lock id %q does not match existing lock", id)
+		return lockErr
+	}
+
+	_, err = c.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &c.bucketName,
+		Key:    aws.String(c.lockFilePath()),
+	})
+	if err != nil {
+		lockErr.Err = err
+		return lockErr
+	}
+	return nil
+}
+
+func (c *RemoteClient) getS3LockInfo(ctx context.Context) (*statemgr.LockInfo, error) {
+	input := &s3.GetObjectInput{
+		Bucket: &c.bucketName,
+		Key:    aws.String(c.lockFilePath()),
+	}
+
+	c.configureGetObject(input)
+
+	output, err := c.s3Client.GetObject(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+
+	lockInfo := &statemgr.LockInfo{}
+	if err := json.NewDecoder(output.Body).Decode(lockInfo); err != nil {
+		return nil, err
+	}
+
+	return lockInfo, nil
+}
+
+// dynamoDBLock takes the lock by creating an item in the DynamoDB table,
+// which fails if the item already exists.
+func (c *RemoteClient) dynamoDBLock(ctx context.Context, info *statemgr.LockInfo) *statemgr.LockError {
 	putParams := &dynamodb.PutItemInput{
 		Item: map[string]dtypes.AttributeValue{
 			"LockID": &dtypes.AttributeValueMemberS{Value: c.lockPath()},
@@ -294,7 +542,6 @@ func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
 		ConditionExpression: aws.String("attribute_not_exists(LockID)"),
 	}
 
-	ctx := context.TODO()
 	_, err := c.dynClient.PutItem(ctx, putParams)
 	if err != nil {
 		lockInfo, infoErr := c.getLockInfo(ctx)
@@ -302,14 +549,13 @@ func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
 			err = multierror.Append(err, infoErr)
 		}
 
-		lockErr := &statemgr.LockError{
+		return &statemgr.LockError{
 			Err:  err,
 			Info: lockInfo,
 		}
-		return "", lockErr
 	}
 
-	return info.ID, nil
+	return nil
 }
 
 func (c *RemoteClient) getMD5(ctx context.Context) ([]byte, error) {
@@ -421,12 +667,41 @@ func (c *RemoteClient) getLockInfo(ctx context.Context) (*statemgr.LockInfo, err
 }
 
 func (c *RemoteClient) Unlock(id string) error {
-	if c.ddbTable == "" {
+	ctx := context.TODO()
+
+	// Release both locks even if releasing one of them fails, so that we
+	// don't leave the other one behind.
+	var errs []*statemgr.LockError
+	if c.useLockFile {
+		if err := c.s3Unlock(ctx, id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.ddbTable != "" {
+		if err := c.dynamoDBUnlock(ctx, id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	switch len(errs) {
+	case 0:
 		return nil
+	case 1:
+		return errs[0]
+	default:
+		lockErr := &statemgr.LockError{}
+		for _, err := range errs {
+			if lockErr.Info == nil {
+				lockErr.Info = err.Info
+			}
+			lockErr.Err = multierror.Append(lockErr.Err, err.Err)
+		}
+		return lockErr
 	}
+}
 
+func (c *RemoteClient) dynamoDBUnlock(ctx context.Context, id string) *statemgr.LockError {
 	lockErr := &statemgr.LockError{}
-	ctx := context.TODO()
 
 	// TODO: store the path and lock ID in separate fields, and have proper
 	// projection expression only delete the lock if both match, rather than
@@ -462,6 +737,10 @@ func (c *RemoteClient) lockPath() string {
 	return fmt.Sprintf("%s/%s", c.bucketName, c.path)
 }
 
+func (c *RemoteClient) lockFilePath() string {
+	return c.path + lockFileSuffix
+}
+
 func (c *RemoteClient) getSSECustomerKeyMD5() string {
 	b := md5.Sum(c.customerEncryptionKey)
 	return base64.StdEncoding.EncodeToString(b[:])
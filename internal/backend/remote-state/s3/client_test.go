@@ -7,12 +7,22 @@ import (
 	"bytes"
 	"context"
 	"crypto/md5"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -21,6 +31,7 @@ import (
 func TestRemoteClient_impl(t *testing.T) {
 	var _ remote.Client = new(RemoteClient)
 	var _ remote.ClientLocker = new(RemoteClient)
+	var _ remote.ClientHistory = new(RemoteClient)
 }
 
 func TestRemoteClient(t *testing.T) {
@@ -28,7 +39,7 @@ func TestRemoteClient(t *testing.T) {
 	bucketName := fmt.Sprintf("%s-%x", testBucketPrefix, time.Now().Unix())
 	keyName := "testState"
 
-	b := backend.TestBackendConfig(t, New(), backend.TestWrapConfig(map[string]interface{}{
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(map[string]interface{}{
 		"bucket":  bucketName,
 		"key":     keyName,
 		"encrypt": true,
@@ -46,19 +57,72 @@ func TestRemoteClient(t *testing.T) {
 	remote.TestClient(t, state.(*remote.State).Client)
 }
 
+func TestRemoteClientHistory(t *testing.T) {
+	testACC(t)
+	bucketName := fmt.Sprintf("%s-%x", testBucketPrefix, time.Now().Unix())
+	keyName := "testState"
+
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(map[string]interface{}{
+		"bucket":  bucketName,
+		"key":     keyName,
+		"encrypt": true,
+	})).(*Backend)
+
+	ctx := context.TODO()
+	createS3Bucket(ctx, t, b.s3Client, bucketName, b.awsConfig.Region)
+	defer deleteS3Bucket(ctx, t, b.s3Client, bucketName)
+
+	_, err := b.s3Client.PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+		Bucket: &bucketName,
+		VersioningConfiguration: &types.VersioningConfiguration{
+			Status: types.BucketVersioningStatusEnabled,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The bucket can only be deleted once all of the versions are gone.
+	defer deleteS3ObjectVersions(ctx, t, b.s3Client, bucketName)
+
+	state, err := b.StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote.TestClientHistory(t, state.(*remote.State).Client)
+}
+
+func deleteS3ObjectVersions(ctx context.Context, t *testing.T, s3Client *s3.Client, bucketName string) {
+	resp, err := s3Client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{Bucket: &bucketName})
+	if err != nil {
+		t.Logf("failed to list object versions: %s", err)
+		return
+	}
+	for _, v := range resp.Versions {
+		if _, err := s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &bucketName, Key: v.Key, VersionId: v.VersionId}); err != nil {
+			t.Logf("failed to delete object version: %s", err)
+		}
+	}
+	for _, m := range resp.DeleteMarkers {
+		if _, err := s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &bucketName, Key: m.Key, VersionId: m.VersionId}); err != nil {
+			t.Logf("failed to delete object delete marker: %s", err)
+		}
+	}
+}
+
 func TestRemoteClientLocks(t *testing.T) {
 	testACC(t)
 	bucketName := fmt.Sprintf("%s-%x", testBucketPrefix, time.Now().Unix())
 	keyName := "testState"
 
-	b1 := backend.TestBackendConfig(t, New(), backend.TestWrapConfig(map[string]interface{}{
+	b1 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(map[string]interface{}{
 		"bucket":         bucketName,
 		"key":            keyName,
 		"encrypt":        true,
 		"dynamodb_table": bucketName,
 	})).(*Backend)
 
-	b2 := backend.TestBackendConfig(t, New(), backend.TestWrapConfig(map[string]interface{}{
+	b2 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(map[string]interface{}{
 		"bucket":         bucketName,
 		"key":            keyName,
 		"encrypt":        true,
@@ -90,14 +154,14 @@ func TestForceUnlock(t *testing.T) {
 	bucketName := fmt.Sprintf("%s-force-%x", testBucketPrefix, time.Now().Unix())
 	keyName := "testState"
 
-	b1 := backend.TestBackendConfig(t, New(), backend.TestWrapConfig(map[string]interface{}{
+	b1 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(map[string]interface{}{
 		"bucket":         bucketName,
 		"key":            keyName,
 		"encrypt":        true,
 		"dynamodb_table": bucketName,
 	})).(*Backend)
 
-	b2 := backend.TestBackendConfig(t, New(), backend.TestWrapConfig(map[string]interface{}{
+	b2 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(map[string]interface{}{
 		"bucket":         bucketName,
 		"key":            keyName,
 		"encrypt":        true,
@@ -168,7 +232,7 @@ func TestRemoteClient_clientMD5(t *testing.T) {
 	bucketName := fmt.Sprintf("%s-%x", testBucketPrefix, time.Now().Unix())
 	keyName := "testState"
 
-	b := backend.TestBackendConfig(t, New(), backend.TestWrapConfig(map[string]interface{}{
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(map[string]interface{}{
 		"bucket":         bucketName,
 		"key":            keyName,
 		"dynamodb_table": bucketName,
@@ -217,7 +281,7 @@ func TestRemoteClient_stateChecksum(t *testing.T) {
 	bucketName := fmt.Sprintf("%s-%x", testBucketPrefix, time.Now().Unix())
 	keyName := "testState"
 
-	b1 := backend.TestBackendConfig(t, New(), backend.TestWrapConfig(map[string]interface{}{
+	b1 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(map[string]interface{}{
 		"bucket":         bucketName,
 		"key":            keyName,
 		"dynamodb_table": bucketName,
@@ -239,18 +303,18 @@ func TestRemoteClient_stateChecksum(t *testing.T) {
 	s := statemgr.TestFullInitialState()
 	sf := &statefile.File{State: s}
 	var oldState bytes.Buffer
-	if err := statefile.Write(sf, &oldState); err != nil {
+	if err := statefile.Write(sf, &oldState, encryption.StateEncryptionDisabled()); err != nil {
 		t.Fatal(err)
 	}
 	sf.Serial++
 	var newState bytes.Buffer
-	if err := statefile.Write(sf, &newState); err != nil {
+	if err := statefile.Write(sf, &newState, encryption.StateEncryptionDisabled()); err != nil {
 		t.Fatal(err)
 	}
 
 	// Use b2 without a dynamodb_table to bypass the lock table to write the state directly.
 	// client2 will write the "incorrect" state, simulating s3 eventually consistency delays
-	b2 := backend.TestBackendConfig(t, New(), backend.TestWrapConfig(map[string]interface{}{
+	b2 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(map[string]interface{}{
 		"bucket": bucketName,
 		"key":    keyName,
 	})).(*Backend)
@@ -324,3 +388,395 @@ func TestRemoteClient_stateChecksum(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestRemoteClientLockFile(t *testing.T) {
+	srv := newFakeS3Server(t)
+	bucketName := "test-bucket"
+	keyName := "testState"
+
+	b1 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(srv.backendConfig(bucketName, keyName))).(*Backend)
+	b2 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(srv.backendConfig(bucketName, keyName))).(*Backend)
+
+	s1, err := b1.StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := b2.StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote.TestClient(t, s1.(*remote.State).Client)
+	remote.TestRemoteLocks(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
+
+	if srv.hasObject(bucketName, keyName+lockFileSuffix) {
+		t.Fatal("lock file was not removed after unlocking")
+	}
+}
+
+func TestRemoteClientHistory_fake(t *testing.T) {
+	srv := newFakeS3Server(t)
+	bucketName := "test-bucket"
+	keyName := "testState"
+
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(srv.backendConfig(bucketName, keyName))).(*Backend)
+
+	s, err := b.StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote.TestClientHistory(t, s.(*remote.State).Client)
+}
+
+func TestRemoteClientLockFile_wrongID(t *testing.T) {
+	srv := newFakeS3Server(t)
+	bucketName := "test-bucket"
+	keyName := "testState"
+
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(srv.backendConfig(bucketName, keyName))).(*Backend)
+
+	s, err := b.StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := statemgr.NewLockInfo()
+	info.Operation = "test"
+	info.Who = "clientA"
+
+	lockID, err := s.Lock(info)
+	if err != nil {
+		t.Fatal("unable to get initial lock:", err)
+	}
+	if !srv.hasObject(bucketName, keyName+lockFileSuffix) {
+		t.Fatal("lock file was not created")
+	}
+
+	err = s.Unlock("not-" + lockID)
+	if err == nil {
+		t.Fatal("unlocked with the wrong lock ID")
+	}
+	if lockErr, ok := err.(*statemgr.LockError); !ok {
+		t.Fatalf("expected a LockError, but was %T: %s", err, err)
+	} else if lockErr.Info == nil || lockErr.Info.ID != lockID {
+		t.Fatalf("wrong lock info in error: %#v", lockErr.Info)
+	}
+
+	if err := s.Unlock(lockID); err != nil {
+		t.Fatal("failed to unlock:", err)
+	}
+}
+
+func TestRemoteClientLockFile_dynamoDB(t *testing.T) {
+	srv := newFakeS3Server(t)
+	bucketName := "test-bucket"
+	keyName := "testState"
+	tableName := "test-table"
+	lockID := bucketName + "/" + keyName
+
+	b1 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(srv.dynamoDBBackendConfig(bucketName, keyName, tableName))).(*Backend)
+	b2 := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(srv.dynamoDBBackendConfig(bucketName, keyName, tableName))).(*Backend)
+
+	s1, err := b1.StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := b2.StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote.TestRemoteLocks(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
+
+	if srv.hasObject(bucketName, keyName+lockFileSuffix) {
+		t.Fatal("lock file was not removed after unlocking")
+	}
+	if srv.hasItem(lockID) {
+		t.Fatal("DynamoDB lock was not removed after unlocking")
+	}
+
+	// If the lock file can't be removed, the DynamoDB lock must still be
+	// released.
+	info := statemgr.NewLockInfo()
+	info.Operation = "test"
+	info.Who = "clientA"
+
+	id, err := s1.Lock(info)
+	if err != nil {
+		t.Fatal("unable to get initial lock:", err)
+	}
+	if !srv.hasObject(bucketName, keyName+lockFileSuffix) {
+		t.Fatal("lock file was not created")
+	}
+	if !srv.hasItem(lockID) {
+		t.Fatal("DynamoDB lock was not created")
+	}
+
+	srv.mu.Lock()
+	delete(srv.objects, bucketName+"/"+keyName+lockFileSuffix)
+	srv.mu.Unlock()
+	if err := s1.Unlock(id); err != nil {
+		t.Fatal("failed to unlock without a lock file:", err)
+	}
+	if srv.hasItem(lockID) {
+		t.Fatal("DynamoDB lock was not removed after unlocking")
+	}
+
+	id, err = s1.Lock(info)
+	if err != nil {
+		t.Fatal("unable to get lock:", err)
+	}
+	srv.mu.Lock()
+	srv.objects[bucketName+"/"+keyName+lockFileSuffix] = []byte("{}")
+	srv.mu.Unlock()
+	if err := s1.Unlock(id); err == nil {
+		t.Fatal("unlocked despite the lock file having the wrong lock ID")
+	}
+	if srv.hasItem(lockID) {
+		t.Fatal("DynamoDB lock was not removed after failing to remove the lock file")
+	}
+}
+
+// fakeS3Server is a minimal S3-compatible server that implements just enough
+// of the API for the backend's state storage and lock file, including
+// conditional writes and object versions.
+type fakeS3Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	objects  map[string][]byte
+	versions map[string][][]byte
+	items    map[string]map[string]interface{}
+}
+
+func newFakeS3Server(t *testing.T) *fakeS3Server {
+	t.Helper()
+
+	srv := &fakeS3Server{
+		objects:  map[string][]byte{},
+		versions: map[string][][]byte{},
+		items:    map[string]map[string]interface{}{},
+	}
+	srv.Server = httptest.NewServer(http.HandlerFunc(srv.serveHTTP))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func (s *fakeS3Server) backendConfig(bucketName, keyName string) map[string]interface{} {
+	return map[string]interface{}{
+		"bucket":                      bucketName,
+		"key":                         keyName,
+		"region":                      "us-east-1",
+		"access_key":                  "test",
+		"secret_key":                  "test",
+		"skip_credentials_validation": true,
+		"skip_requesting_account_id":  true,
+		"skip_metadata_api_check":     true,
+		"use_path_style":              true,
+		"use_lockfile":                true,
+		"endpoints": map[string]interface{}{
+			"s3": s.URL,
+		},
+	}
+}
+
+// dynamoDBBackendConfig is like backendConfig, but also uses the server's
+// fake DynamoDB API for locking.
+func (s *fakeS3Server) dynamoDBBackendConfig(bucketName, keyName, tableName string) map[string]interface{} {
+	config := s.backendConfig(bucketName, keyName)
+	config["dynamodb_table"] = tableName
+	config["endpoints"] = map[string]interface{}{
+		"s3":       s.URL,
+		"dynamodb": s.URL,
+	}
+	return config
+}
+
+func (s *fakeS3Server) hasItem(lockID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.items[lockID]
+	return ok
+}
+
+func (s *fakeS3Server) hasObject(bucketName, key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.objects[bucketName+"/"+key]
+	return ok
+}
+
+func (s *fakeS3Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// DynamoDB requests are all sent to the root path, with the operation
+	// in a header.
+	if target := r.Header.Get("X-Amz-Target"); target != "" {
+		s.serveDynamoDB(w, r, strings.TrimPrefix(target, "DynamoDB_20120810."))
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	bucketName, key, _ := strings.Cut(name, "/")
+
+	if key == "" && r.Method == http.MethodGet {
+		if r.URL.Query().Has("versions") {
+			s.listObjectVersions(w, bucketName, r.URL.Query().Get("prefix"))
+			return
+		}
+		s.listObjects(w, bucketName, r.URL.Query().Get("prefix"))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodHead, http.MethodGet:
+		data, ok := s.objects[name]
+		if versionID := r.URL.Query().Get("versionId"); versionID != "" {
+			var i int
+			_, err := fmt.Sscan(versionID, &i)
+			ok = err == nil && i >= 0 && i < len(s.versions[name])
+			if !ok {
+				writeFakeS3Error(w, http.StatusNotFound, "NoSuchVersion")
+				return
+			}
+			data = s.versions[name][i]
+		}
+		if !ok {
+			writeFakeS3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	case http.MethodPut:
+		if _, ok := s.objects[name]; ok && r.Header.Get("If-None-Match") == "*" {
+			writeFakeS3Error(w, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeFakeS3Error(w, http.StatusInternalServerError, "InternalError")
+			return
+		}
+		s.objects[name] = data
+		s.versions[name] = append(s.versions[name], data)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		delete(s.objects, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func (s *fakeS3Server) listObjects(w http.ResponseWriter, bucketName, prefix string) {
+	var keys []string
+	for name := range s.objects {
+		key := strings.TrimPrefix(name, bucketName+"/")
+		if key != name && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?><ListBucketResult>`)
+	fmt.Fprintf(&buf, "<Name>%s</Name><KeyCount>%d</KeyCount><IsTruncated>false</IsTruncated>", bucketName, len(keys))
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "<Contents><Key>%s</Key></Contents>", key)
+	}
+	buf.WriteString(`</ListBucketResult>`)
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+func (s *fakeS3Server) listObjectVersions(w http.ResponseWriter, bucketName, prefix string) {
+	var keys []string
+	for name := range s.versions {
+		key := strings.TrimPrefix(name, bucketName+"/")
+		if key != name && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?><ListVersionsResult>`)
+	fmt.Fprintf(&buf, "<Name>%s</Name><IsTruncated>false</IsTruncated>", bucketName)
+	for _, key := range keys {
+		versions := s.versions[bucketName+"/"+key]
+		for i := len(versions) - 1; i >= 0; i-- {
+			modified := time.Date(2024, 1, 1, 0, i, 0, 0, time.UTC).Format(time.RFC3339)
+			fmt.Fprintf(&buf, "<Version><Key>%s</Key><VersionId>%d</VersionId><IsLatest>%t</IsLatest><LastModified>%s</LastModified></Version>", key, i, i == len(versions)-1, modified)
+		}
+	}
+	buf.WriteString(`</ListVersionsResult>`)
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// serveDynamoDB implements just enough of the DynamoDB API for the items
+// used for locking, all of which are keyed by their LockID attribute.
+func (s *fakeS3Server) serveDynamoDB(w http.ResponseWriter, r *http.Request, operation string) {
+	var req struct {
+		Item                map[string]interface{}
+		Key                 map[string]interface{}
+		ConditionExpression string
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeFakeDynamoDBError(w, "SerializationException")
+		return
+	}
+	lockID := func(attrs map[string]interface{}) string {
+		attr, _ := attrs["LockID"].(map[string]interface{})
+		id, _ := attr["S"].(string)
+		return id
+	}
+
+	resp := map[string]interface{}{}
+	switch operation {
+	case "PutItem":
+		id := lockID(req.Item)
+		if _, ok := s.items[id]; ok && req.ConditionExpression == "attribute_not_exists(LockID)" {
+			writeFakeDynamoDBError(w, "ConditionalCheckFailedException")
+			return
+		}
+		s.items[id] = req.Item
+	case "GetItem":
+		if item, ok := s.items[lockID(req.Key)]; ok {
+			resp["Item"] = item
+		}
+	case "DeleteItem":
+		delete(s.items, lockID(req.Key))
+	default:
+		writeFakeDynamoDBError(w, "UnknownOperationException")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func writeFakeDynamoDBError(w http.ResponseWriter, code string) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	w.WriteHeader(http.StatusBadRequest)
+	fmt.Fprintf(w, `{"__type":"com.amazonaws.dynamodb.v20120810#%s","message":%q}`, code, code)
+}
+
+func writeFakeS3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>%s</Code><Message>%s</Message></Error>`, code, code)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/zclconf/go-cty/cty"
 
 	backendLocal "github.com/opentofu/opentofu/internal/backend/local"
+	"github.com/opentofu/opentofu/internal/encryption"
 )
 
 const (
@@ -372,7 +373,7 @@ func (b *Remote) Configure(obj cty.Value) tfdiags.Diagnostics {
 	}
 
 	// Configure a local backend for when we need to run operations locally.
-	b.local = backendLocal.NewWithBackend(b)
+	b.local = backendLocal.NewWithBackend(b, encryption.StateEncryptionDisabled())
 	b.forceLocal = b.forceLocal || !entitlements.Operations
 
 	// Enable retries for server errors as the backend is now fully configured.
@@ -702,18 +703,20 @@ func (b *Remote) StateMgr(name string) (statemgr.Full, error) {
 		runID: os.Getenv("TFE_RUN_ID"),
 	}
 
-	return &remote.State{
-		Client: client,
+	// The remote backend service must be able to read the state, so it's
+	// never encrypted.
+	state := remote.NewState(client, encryption.StateEncryptionDisabled())
 
-		// client.runID will be set if we're running a Terraform Cloud
-		// or Terraform Enterprise remote execution environment, in which
-		// case we'll disable intermediate snapshots to avoid extra storage
-		// costs for Terraform Enterprise customers.
-		// Other implementations of the remote state protocol should not run
-		// in contexts where there's a "TFE Run ID" and so are not affected
-		// by this special case.
-		DisableIntermediateSnapshots: client.runID != "",
-	}, nil
+	// client.runID will be set if we're running a Terraform Cloud
+	// or Terraform Enterprise remote execution environment, in which
+	// case we'll disable intermediate snapshots to avoid extra storage
+	// costs for Terraform Enterprise customers.
+	// Other implementations of the remote state protocol should not run
+	// in contexts where there's a "TFE Run ID" and so are not affected
+	// by this special case.
+	state.DisableIntermediateSnapshots = client.runID != ""
+
+	return state, nil
 }
 
 func isLocalExecutionMode(execMode string) bool {
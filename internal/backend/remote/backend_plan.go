@@ -77,6 +77,15 @@ func (b *Remote) opPlan(stopCtx, cancelCtx context.Context, op *backend.Operatio
 		))
 	}
 
+	if op.PolicyDir != "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Policy checks are not currently supported",
+			`The "remote" backend does not currently support checking planned changes `+
+				`against local policy files.`,
+		))
+	}
+
 	if b.hasExplicitVariableValues(op) {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
@@ -110,6 +119,30 @@ func (b *Remote) opPlan(stopCtx, cancelCtx context.Context, op *backend.Operatio
 	// equivalent to an API version < 2.3.
 	currentAPIVersion, parseErr := version.NewVersion(b.client.RemoteAPIVersion())
 
+	if len(op.Excludes) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Resource exclusion is not supported",
+			fmt.Sprintf(
+				`The host %s does not support the -exclude option for `+
+					`remote plans.`,
+				b.hostname,
+			),
+		))
+	}
+
+	if op.AllowDeferral {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Deferred changes are not supported",
+			fmt.Sprintf(
+				`The host %s does not support the -allow-deferral option for `+
+					`remote plans.`,
+				b.hostname,
+			),
+		))
+	}
+
 	if len(op.Targets) != 0 {
 		desiredAPIVersion, _ := version.NewVersion("2.3")
 
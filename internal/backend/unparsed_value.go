@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -28,6 +29,23 @@ type UnparsedVariableValue interface {
 	ParseVariableValue(mode configs.VariableParsingMode) (*tofu.InputValue, tfdiags.Diagnostics)
 }
 
+// StaticRootModuleCall returns a configs.StaticModuleCall for the root module
+// that takes the values of its input variables from the given unparsed values,
+// for the settings that are statically evaluated while loading configuration.
+func StaticRootModuleCall(vv map[string]UnparsedVariableValue) configs.StaticModuleCall {
+	return configs.NewStaticModuleCall(addrs.RootModule, func(v *configs.Variable) (cty.Value, hcl.Diagnostics) {
+		rv, ok := vv[v.Name]
+		if !ok {
+			return cty.NilVal, nil
+		}
+		val, diags := rv.ParseVariableValue(v.ParsingMode)
+		if diags.HasErrors() {
+			return cty.NilVal, diags.ToHCL()
+		}
+		return val.Value, diags.ToHCL()
+	})
+}
+
 // ParseUndeclaredVariableValues processes a map of unparsed variable values
 // and returns an input values map of the ones not declared in the specified
 // declaration map along with detailed diagnostics about values of undeclared
@@ -6,13 +6,21 @@ package tf
 import (
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/apparentlymart/go-dump/dump"
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/spf13/afero"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -288,12 +296,12 @@ func TestState_basic(t *testing.T) {
 				t.Fatalf("unexpected error: %s", err)
 			}
 
-			diags := dataSourceRemoteStateValidate(config)
+			diags := dataSourceRemoteStateValidate(config, encryption.StateEncryptionDisabled())
 
 			var got cty.Value
 			if !diags.HasErrors() && config.IsWhollyKnown() {
 				var moreDiags tfdiags.Diagnostics
-				got, moreDiags = dataSourceRemoteStateRead(config)
+				got, moreDiags = dataSourceRemoteStateRead(config, encryption.StateEncryptionDisabled())
 				diags = diags.Append(moreDiags)
 			}
 
@@ -318,7 +326,7 @@ func TestState_validation(t *testing.T) {
 	// the validation step in isolation does not attempt to configure
 	// the backend.
 	overrideBackendFactories = map[string]backend.InitFn{
-		"failsconfigure": func() backend.Backend {
+		"failsconfigure": func(encryption.StateEncryption) backend.Backend {
 			return backendFailsConfigure{}
 		},
 	}
@@ -336,12 +344,88 @@ func TestState_validation(t *testing.T) {
 		t.Fatalf("unexpected error: %s", err)
 	}
 
-	diags := dataSourceRemoteStateValidate(config)
+	diags := dataSourceRemoteStateValidate(config, encryption.StateEncryptionDisabled())
 	if diags.HasErrors() {
 		t.Fatalf("unexpected errors\n%s", diags.Err().Error())
 	}
 }
 
+func TestState_encrypted(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	src := `
+terraform {
+  encryption {
+    key_provider "pbkdf2" "main" {
+      passphrase = "correct-horse-battery-staple"
+      iterations = 200000
+    }
+    method "aes_gcm" "main" {
+      keys = key_provider.pbkdf2.main
+    }
+    state {
+      method = method.aes_gcm.main
+    }
+    remote_state {
+      method = method.aes_gcm.main
+    }
+  }
+}
+`
+	if err := afero.WriteFile(fs, "main.tf", []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file, hclDiags := configs.NewParser(fs).LoadConfigFile("main.tf")
+	if hclDiags.HasErrors() {
+		t.Fatal(hclDiags.Error())
+	}
+	enc, hclDiags := encryption.New(file.Encryptions[0])
+	if hclDiags.HasErrors() {
+		t.Fatal(hclDiags.Error())
+	}
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(addrs.RootModuleInstance.OutputValue("foo"), cty.StringVal("bar"), false)
+	})
+	statePath := filepath.Join(t.TempDir(), "encrypted.tfstate")
+	f, err := os.Create(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = statefile.Write(statefile.New(state, "lineage", 1), f, enc.State())
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := dataSourceRemoteStateGetSchema().Block
+	config, err := schema.CoerceValue(cty.ObjectVal(map[string]cty.Value{
+		"backend": cty.StringVal("local"),
+		"config": cty.ObjectVal(map[string]cty.Value{
+			"path": cty.StringVal(statePath),
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got, diags := dataSourceRemoteStateRead(config, enc.RemoteState())
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	want := cty.ObjectVal(map[string]cty.Value{
+		"foo": cty.StringVal("bar"),
+	})
+	if got := got.GetAttr("outputs"); !want.RawEquals(got) {
+		t.Errorf("wrong outputs\ngot:  %s\nwant: %s", dump.Value(got), dump.Value(want))
+	}
+
+	// Without the remote_state settings, the encrypted state is unreadable.
+	_, diags = dataSourceRemoteStateRead(config, encryption.StateEncryptionDisabled())
+	if !diags.HasErrors() {
+		t.Fatal("unexpected success reading encrypted state without encryption settings")
+	}
+}
+
 type backendFailsConfigure struct{}
 
 func (b backendFailsConfigure) ConfigSchema() *configschema.Block {
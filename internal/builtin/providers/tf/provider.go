@@ -7,15 +7,23 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/providers"
 )
 
 // Provider is an implementation of providers.Interface
-type Provider struct{}
+type Provider struct {
+	// remoteStateEncryption decrypts the state snapshots read by
+	// terraform_remote_state data sources.
+	remoteStateEncryption encryption.StateEncryption
+}
 
-// NewProvider returns a new tofu provider
-func NewProvider() providers.Interface {
-	return &Provider{}
+// NewProvider returns a new tofu provider, which uses the given encryption
+// settings to read remote state snapshots.
+func NewProvider(remoteStateEncryption encryption.StateEncryption) providers.Interface {
+	return &Provider{
+		remoteStateEncryption: remoteStateEncryption,
+	}
 }
 
 // GetSchema returns the complete schema for the provider.
@@ -52,7 +60,7 @@ func (p *Provider) ValidateDataResourceConfig(req providers.ValidateDataResource
 		return res
 	}
 
-	diags := dataSourceRemoteStateValidate(req.Config)
+	diags := dataSourceRemoteStateValidate(req.Config, p.remoteStateEncryption)
 	res.Diagnostics = diags
 
 	return res
@@ -77,7 +85,7 @@ func (p *Provider) ReadDataSource(req providers.ReadDataSourceRequest) providers
 		return res
 	}
 
-	newState, diags := dataSourceRemoteStateRead(req.Config)
+	newState, diags := dataSourceRemoteStateRead(req.Config, p.remoteStateEncryption)
 
 	res.State = newState
 	res.Diagnostics = diags
@@ -130,11 +138,34 @@ func (p *Provider) ImportResourceState(req providers.ImportResourceStateRequest)
 	panic("unimplemented - terraform_remote_state has no resources")
 }
 
+// MoveResourceState requests that an object of another resource type be
+// converted into one of this provider's resource types, which is not
+// supported by this provider.
+func (p *Provider) MoveResourceState(req providers.MoveResourceStateRequest) providers.MoveResourceStateResponse {
+	var resp providers.MoveResourceStateResponse
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported resource type %q as the target of a move", req.TargetTypeName))
+	return resp
+}
+
 // ValidateResourceConfig is used to to validate the resource configuration values.
 func (p *Provider) ValidateResourceConfig(req providers.ValidateResourceConfigRequest) providers.ValidateResourceConfigResponse {
 	return validateDataStoreResourceConfig(req)
 }
 
+// GetFunctions returns the functions exported by the provider, of which
+// there are currently none.
+func (p *Provider) GetFunctions() providers.GetFunctionsResponse {
+	return providers.GetFunctionsResponse{}
+}
+
+// CallFunction requests that the given function be called, which always fails
+// since the provider exports no functions.
+func (p *Provider) CallFunction(req providers.CallFunctionRequest) providers.CallFunctionResponse {
+	var res providers.CallFunctionResponse
+	res.Diagnostics = res.Diagnostics.Append(fmt.Errorf("unsupported function %q", req.Name))
+	return res
+}
+
 // Close is a noop for this provider, since it's run in-process.
 func (p *Provider) Close() error {
 	return nil
@@ -27,6 +27,7 @@ import (
 
 	"github.com/opentofu/opentofu/internal/backend/local"
 	"github.com/opentofu/opentofu/internal/command/jsonstate"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statefile"
@@ -202,7 +203,7 @@ func (s *State) PersistState(schemas *tofu.Schemas) error {
 	f := statefile.New(s.state, s.lineage, s.serial)
 
 	var buf bytes.Buffer
-	err := statefile.Write(f, &buf)
+	err := statefile.Write(f, &buf, encryption.StateEncryptionDisabled())
 	if err != nil {
 		return err
 	}
@@ -215,7 +216,7 @@ func (s *State) PersistState(schemas *tofu.Schemas) error {
 		}
 	}
 
-	stateFile, err := statefile.Read(bytes.NewReader(buf.Bytes()))
+	stateFile, err := statefile.Read(bytes.NewReader(buf.Bytes()), encryption.StateEncryptionDisabled())
 	if err != nil {
 		return fmt.Errorf("failed to read state: %w", err)
 	}
@@ -385,7 +386,7 @@ func (s *State) refreshState() error {
 		return nil
 	}
 
-	stateFile, err := statefile.Read(bytes.NewReader(payload.Data))
+	stateFile, err := statefile.Read(bytes.NewReader(payload.Data), encryption.StateEncryptionDisabled())
 	if err != nil {
 		return err
 	}
@@ -9,9 +9,11 @@ import (
 
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/cliconfig"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
 )
 
 // ApplyCommand is a Command implementation that applies a OpenTofu
@@ -71,8 +73,10 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
-	// Check for invalid combination of plan file and variable overrides
-	if planFile != nil && !args.Vars.Empty() {
+	// Check for invalid combination of plan file and variable overrides. A
+	// plan that uses ephemeral variables needs their values again, and the
+	// backend rejects any others that are set.
+	if planFile != nil && !args.Vars.Empty() && !planHasApplyTimeVariables(planFile) {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
 			"Can't set variables when applying a saved plan",
@@ -95,6 +99,10 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	// object state for now.
 	c.Meta.parallelism = args.Operation.Parallelism
 
+	// The variables are needed while loading the configuration, to
+	// statically evaluate the backend configuration and module sources.
+	c.Meta.applyVariableArgs(args.Vars)
+
 	// Prepare the backend, passing the plan file if present, and the
 	// backend-specific arguments
 	be, beDiags := c.PrepareBackend(planFile, args.State, args.ViewType)
@@ -105,7 +113,7 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	}
 
 	// Build the operation request
-	opReq, opDiags := c.OperationRequest(be, view, args.ViewType, planFile, args.Operation, args.AutoApprove)
+	opReq, opDiags := c.OperationRequest(be, view, args.ViewType, planFile, args.Operation, args.AutoApprove, args.PolicyDir)
 	diags = diags.Append(opDiags)
 
 	// Collect variable value and add them to the operation request
@@ -120,14 +128,30 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	}
 	diags = nil
 
+	// Run the external hooks from the CLI configuration, if any. A failing
+	// pre_apply hook prevents the operation from starting.
+	hooks := c.externalHooks()
+	if hooks != nil {
+		opReq.Hooks = append([]tofu.Hook{hooks}, opReq.Hooks...)
+	}
+	if diags := hooks.RunPre(cliconfig.HookEventPreApply); diags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
 	// Run the operation
 	op, err := c.RunOperation(be, opReq)
 	if err != nil {
 		diags = diags.Append(err)
+		diags = diags.Append(hooks.RunPost(cliconfig.HookEventPostApply, backend.OperationFailure))
 		view.Diagnostics(diags)
 		return 1
 	}
 
+	if diags := hooks.RunPost(cliconfig.HookEventPostApply, op.Result); len(diags) > 0 {
+		view.Diagnostics(diags)
+	}
+
 	if op.Result != backend.OperationSuccess {
 		return op.Result.ExitStatus()
 	}
@@ -254,6 +278,7 @@ func (c *ApplyCommand) OperationRequest(
 	planFile *planfile.WrappedPlanFile,
 	args *arguments.Operation,
 	autoApprove bool,
+	policyDir string,
 ) (*backend.Operation, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
@@ -271,7 +296,10 @@ func (c *ApplyCommand) OperationRequest(
 	opReq.PlanFile = planFile
 	opReq.PlanRefresh = args.Refresh
 	opReq.Targets = args.Targets
+	opReq.Excludes = args.Excludes
 	opReq.ForceReplace = args.ForceReplace
+	opReq.AllowDeferral = args.AllowDeferral
+	opReq.PolicyDir = policyDir
 	opReq.Type = backend.OperationTypeApply
 	opReq.View = view.Operation()
 
@@ -294,19 +322,28 @@ func (c *ApplyCommand) GatherVariables(opReq *backend.Operation, args *arguments
 	// Once all commands that gather variables have been converted to this
 	// structure, we could move the variable gathering code to the arguments
 	// package directly, removing this shim layer.
-
-	varArgs := args.All()
-	items := make([]rawFlag, len(varArgs))
-	for i := range varArgs {
-		items[i].Name = varArgs[i].Name
-		items[i].Value = varArgs[i].Value
-	}
-	c.Meta.variableArgs = rawFlags{items: &items}
+	c.Meta.applyVariableArgs(args)
 	opReq.Variables, diags = c.collectVariableValues()
 
 	return diags
 }
 
+// planHasApplyTimeVariables returns true if the given plan file is a local
+// plan that was created with ephemeral variables, whose values must be set
+// again when applying it.
+func planHasApplyTimeVariables(planFile *planfile.WrappedPlanFile) bool {
+	lp, ok := planFile.Local()
+	if !ok {
+		return false
+	}
+	plan, err := lp.ReadPlan()
+	if err != nil {
+		// Errors reading the plan are reported when it's applied.
+		return false
+	}
+	return len(plan.ApplyTimeVariables) != 0
+}
+
 func (c *ApplyCommand) Help() string {
 	if c.Destroy {
 		return c.helpDestroy()
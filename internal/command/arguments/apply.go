@@ -27,6 +27,10 @@ type Apply struct {
 	// PlanPath contains an optional path to a stored plan file
 	PlanPath string
 
+	// PolicyDir is an optional path to a directory of policy files whose
+	// policies the changes planned by an implicit plan must satisfy.
+	PolicyDir string
+
 	// ViewType specifies which output format to use
 	ViewType ViewType
 }
@@ -45,6 +49,7 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 	cmdFlags := extendedFlagSet("apply", apply.State, apply.Operation, apply.Vars)
 	cmdFlags.BoolVar(&apply.AutoApprove, "auto-approve", false, "auto-approve")
 	cmdFlags.BoolVar(&apply.InputEnabled, "input", true, "input")
+	cmdFlags.StringVar(&apply.PolicyDir, "policy-dir", "", "policy-dir")
 
 	var json bool
 	cmdFlags.BoolVar(&json, "json", false, "json")
@@ -71,6 +76,16 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 		))
 	}
 
+	// A saved plan was already checked against policies when it was created,
+	// and records any violations for us to enforce.
+	if apply.PlanPath != "" && apply.PolicyDir != "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible command line options",
+			"The -policy-dir option cannot be used when applying a saved plan file. Use it with \"tofu plan\" when creating the plan instead.",
+		))
+	}
+
 	// JSON view currently does not support input, so we disable it here.
 	if json {
 		apply.InputEnabled = false
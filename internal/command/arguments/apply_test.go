@@ -82,6 +82,22 @@ func TestParseApply_basicValid(t *testing.T) {
 				},
 			},
 		},
+		"policy dir": {
+			[]string{"-policy-dir=policies"},
+			&Apply{
+				AutoApprove:  false,
+				InputEnabled: true,
+				PolicyDir:    "policies",
+				ViewType:     ViewHuman,
+				State:        &State{Lock: true},
+				Vars:         &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
 	}
 
 	cmpOpts := cmpopts.IgnoreUnexported(Operation{}, Vars{}, State{})
@@ -165,6 +181,19 @@ func TestParseApply_tooManyArguments(t *testing.T) {
 	}
 }
 
+func TestParseApply_policyDirWithPlan(t *testing.T) {
+	got, diags := ParseApply([]string{"-policy-dir=policies", "saved.tfplan"})
+	if len(diags) == 0 {
+		t.Fatal("expected diags but got none")
+	}
+	if got, want := diags.Err().Error(), "The -policy-dir option cannot be used when applying a saved plan file"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+	if got.PolicyDir != "policies" {
+		t.Fatalf("wrong policy dir, got %q", got.PolicyDir)
+	}
+}
+
 func TestParseApply_targets(t *testing.T) {
 	foobarbaz, _ := addrs.ParseTargetStr("foo_bar.baz")
 	boop, _ := addrs.ParseTargetStr("module.boop")
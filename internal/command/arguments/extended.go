@@ -67,6 +67,10 @@ type Operation struct {
 	// their dependencies.
 	Targets []addrs.Targetable
 
+	// Excludes allow excluding a set of resource addresses, and everything
+	// that depends on them, from an operation.
+	Excludes []addrs.Targetable
+
 	// ForceReplace addresses cause OpenTofu to force a particular set of
 	// resource instances to generate "replace" actions in any plan where they
 	// would normally have generated "no-op" or "update" actions.
@@ -79,45 +83,41 @@ type Operation struct {
 	// learn a use-case for broader matching.
 	ForceReplace []addrs.AbsResourceInstance
 
+	// AllowDeferral allows a plan to defer the resources and modules whose
+	// count or for_each values won't be known until apply, along with
+	// everything that depends on them, instead of failing.
+	AllowDeferral bool
+
 	// These private fields are used only temporarily during decoding. Use
 	// method Parse to populate the exported fields from these, validating
 	// the raw values in the process.
 	targetsRaw      []string
+	excludesRaw     []string
 	forceReplaceRaw []string
 	destroyRaw      bool
 	refreshOnlyRaw  bool
 }
 
 // Parse must be called on Operation after initial flag parse. This processes
-// the raw target flags into addrs.Targetable values, returning diagnostics if
+// the raw target and exclude flags into addrs.Targetable values, returning diagnostics if
 // invalid.
 func (o *Operation) Parse() tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 
-	o.Targets = nil
+	var targetDiags tfdiags.Diagnostics
+	o.Targets, targetDiags = parseTargetables(o.targetsRaw, "target")
+	diags = diags.Append(targetDiags)
 
-	for _, tr := range o.targetsRaw {
-		traversal, syntaxDiags := hclsyntax.ParseTraversalAbs([]byte(tr), "", hcl.Pos{Line: 1, Column: 1})
-		if syntaxDiags.HasErrors() {
-			diags = diags.Append(tfdiags.Sourceless(
-				tfdiags.Error,
-				fmt.Sprintf("Invalid target %q", tr),
-				syntaxDiags[0].Detail,
-			))
-			continue
-		}
+	var excludeDiags tfdiags.Diagnostics
+	o.Excludes, excludeDiags = parseTargetables(o.excludesRaw, "exclude")
+	diags = diags.Append(excludeDiags)
 
-		target, targetDiags := addrs.ParseTarget(traversal)
-		if targetDiags.HasErrors() {
-			diags = diags.Append(tfdiags.Sourceless(
-				tfdiags.Error,
-				fmt.Sprintf("Invalid target %q", tr),
-				targetDiags[0].Description().Detail,
-			))
-			continue
-		}
-
-		o.Targets = append(o.Targets, target.Subject)
+	if len(o.targetsRaw) > 0 && len(o.excludesRaw) > 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible targeting options",
+			"The -target and -exclude options are mutually-exclusive.",
+		))
 	}
 
 	for _, raw := range o.forceReplaceRaw {
@@ -180,6 +180,40 @@ func (o *Operation) Parse() tfdiags.Diagnostics {
 	return diags
 }
 
+// parseTargetables parses the raw values of a repeatable option that accepts
+// resource and module addresses, such as -target, returning diagnostics for
+// any that are invalid.
+func parseTargetables(raws []string, option string) ([]addrs.Targetable, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	var ret []addrs.Targetable
+
+	for _, raw := range raws {
+		traversal, syntaxDiags := hclsyntax.ParseTraversalAbs([]byte(raw), "", hcl.Pos{Line: 1, Column: 1})
+		if syntaxDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid %s %q", option, raw),
+				syntaxDiags[0].Detail,
+			))
+			continue
+		}
+
+		target, targetDiags := addrs.ParseTarget(traversal)
+		if targetDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid %s %q", option, raw),
+				targetDiags[0].Description().Detail,
+			))
+			continue
+		}
+
+		ret = append(ret, target.Subject)
+	}
+
+	return ret, diags
+}
+
 // Vars describes arguments which specify non-default variable values. This
 // interfce is unfortunately obscure, because the order of the CLI arguments
 // determines the final value of the gathered variables. In future it might be
@@ -228,7 +262,9 @@ func extendedFlagSet(name string, state *State, operation *Operation, vars *Vars
 		f.BoolVar(&operation.destroyRaw, "destroy", false, "destroy")
 		f.BoolVar(&operation.refreshOnlyRaw, "refresh-only", false, "refresh-only")
 		f.Var((*flagStringSlice)(&operation.targetsRaw), "target", "target")
+		f.Var((*flagStringSlice)(&operation.excludesRaw), "exclude", "exclude")
 		f.Var((*flagStringSlice)(&operation.forceReplaceRaw), "replace", "replace")
+		f.BoolVar(&operation.AllowDeferral, "allow-deferral", false, "allow-deferral")
 	}
 
 	// Gather all -var and -var-file arguments into one heterogenous structure
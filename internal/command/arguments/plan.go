@@ -30,6 +30,10 @@ type Plan struct {
 	// be written to.
 	GenerateConfigPath string
 
+	// PolicyDir is an optional path to a directory of policy files whose
+	// policies the planned changes must satisfy.
+	PolicyDir string
+
 	// ViewType specifies which output format to use
 	ViewType ViewType
 }
@@ -50,6 +54,7 @@ func ParsePlan(args []string) (*Plan, tfdiags.Diagnostics) {
 	cmdFlags.BoolVar(&plan.InputEnabled, "input", true, "input")
 	cmdFlags.StringVar(&plan.OutPath, "out", "", "out")
 	cmdFlags.StringVar(&plan.GenerateConfigPath, "generate-config-out", "", "generate-config-out")
+	cmdFlags.StringVar(&plan.PolicyDir, "policy-dir", "", "policy-dir")
 
 	var json bool
 	cmdFlags.BoolVar(&json, "json", false, "json")
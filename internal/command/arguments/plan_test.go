@@ -35,18 +35,20 @@ func TestParsePlan_basicValid(t *testing.T) {
 			},
 		},
 		"setting all options": {
-			[]string{"-destroy", "-detailed-exitcode", "-input=false", "-out=saved.tfplan"},
+			[]string{"-destroy", "-detailed-exitcode", "-input=false", "-out=saved.tfplan", "-policy-dir=policies", "-allow-deferral"},
 			&Plan{
 				DetailedExitCode: true,
 				InputEnabled:     false,
 				OutPath:          "saved.tfplan",
+				PolicyDir:        "policies",
 				ViewType:         ViewHuman,
 				State:            &State{Lock: true},
 				Vars:             &Vars{},
 				Operation: &Operation{
-					PlanMode:    plans.DestroyMode,
-					Parallelism: 10,
-					Refresh:     true,
+					PlanMode:      plans.DestroyMode,
+					Parallelism:   10,
+					Refresh:       true,
+					AllowDeferral: true,
 				},
 			},
 		},
@@ -158,6 +160,62 @@ func TestParsePlan_targets(t *testing.T) {
 	}
 }
 
+func TestParsePlan_excludes(t *testing.T) {
+	foobarbaz, _ := addrs.ParseTargetStr("foo_bar.baz")
+	boop, _ := addrs.ParseTargetStr("module.boop")
+	testCases := map[string]struct {
+		args    []string
+		want    []addrs.Targetable
+		wantErr string
+	}{
+		"no excludes by default": {
+			args: nil,
+			want: nil,
+		},
+		"one exclude": {
+			args: []string{"-exclude=foo_bar.baz"},
+			want: []addrs.Targetable{foobarbaz.Subject},
+		},
+		"two excludes": {
+			args: []string{"-exclude=foo_bar.baz", "-exclude", "module.boop"},
+			want: []addrs.Targetable{foobarbaz.Subject, boop.Subject},
+		},
+		"invalid traversal": {
+			args:    []string{"-exclude=foo."},
+			want:    nil,
+			wantErr: "Dot must be followed by attribute name",
+		},
+		"invalid exclude": {
+			args:    []string{"-exclude=data[0].foo"},
+			want:    nil,
+			wantErr: "A data source name is required",
+		},
+		"with target": {
+			args:    []string{"-exclude=foo_bar.baz", "-target=module.boop"},
+			want:    []addrs.Targetable{foobarbaz.Subject},
+			wantErr: "The -target and -exclude options are mutually-exclusive",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParsePlan(tc.args)
+			if len(diags) > 0 {
+				if tc.wantErr == "" {
+					t.Fatalf("unexpected diags: %v", diags)
+				} else if got := diags.Err().Error(); !strings.Contains(got, tc.wantErr) {
+					t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.wantErr)
+				}
+			} else if tc.wantErr != "" {
+				t.Fatalf("expected diags but got none")
+			}
+			if !cmp.Equal(got.Operation.Excludes, tc.want) {
+				t.Fatalf("unexpected result\n%s", cmp.Diff(got.Operation.Excludes, tc.want))
+			}
+		})
+	}
+}
+
 func TestParsePlan_vars(t *testing.T) {
 	testCases := map[string]struct {
 		args []string
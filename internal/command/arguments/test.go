@@ -27,6 +27,16 @@ type Test struct {
 	// human-readable format or JSON for each run step depending on the
 	// ViewType.
 	Verbose bool
+
+	// Parallelism is the maximum number of test files and run blocks to
+	// execute at the same time. Run blocks only execute concurrently if they
+	// are marked as parallel.
+	Parallelism int
+
+	// JUnitXMLFile is the path of a file to write a JUnit XML report of the
+	// test results to, in addition to the selected view. If empty, no report
+	// is written.
+	JUnitXMLFile string
 }
 
 func ParseTest(args []string) (*Test, tfdiags.Diagnostics) {
@@ -42,6 +52,8 @@ func ParseTest(args []string) (*Test, tfdiags.Diagnostics) {
 	cmdFlags.StringVar(&test.TestDirectory, "test-directory", configs.DefaultTestDirectory, "test-directory")
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
 	cmdFlags.BoolVar(&test.Verbose, "verbose", false, "verbose")
+	cmdFlags.StringVar(&test.JUnitXMLFile, "junit-xml", "", "junit-xml")
+	cmdFlags.IntVar(&test.Parallelism, "parallelism", 1, "parallelism")
 
 	if err := cmdFlags.Parse(args); err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -74,6 +74,7 @@ func TestParseTest(t *testing.T) {
 				Filter:        nil,
 				TestDirectory: "tests",
 				ViewType:      ViewHuman,
+				Parallelism:   1,
 				Vars:          &Vars{},
 			},
 			wantDiags: nil,
@@ -84,6 +85,7 @@ func TestParseTest(t *testing.T) {
 				Filter:        []string{"one.tftest.hcl", "two.tftest.hcl"},
 				TestDirectory: "tests",
 				ViewType:      ViewHuman,
+				Parallelism:   1,
 				Vars:          &Vars{},
 			},
 			wantDiags: nil,
@@ -94,6 +96,7 @@ func TestParseTest(t *testing.T) {
 				Filter:        nil,
 				TestDirectory: "tests",
 				ViewType:      ViewJSON,
+				Parallelism:   1,
 				Vars:          &Vars{},
 			},
 			wantDiags: nil,
@@ -104,6 +107,7 @@ func TestParseTest(t *testing.T) {
 				Filter:        nil,
 				TestDirectory: "other",
 				ViewType:      ViewHuman,
+				Parallelism:   1,
 				Vars:          &Vars{},
 			},
 			wantDiags: nil,
@@ -115,6 +119,28 @@ func TestParseTest(t *testing.T) {
 				TestDirectory: "tests",
 				ViewType:      ViewHuman,
 				Verbose:       true,
+				Parallelism:   1,
+				Vars:          &Vars{},
+			},
+		},
+		"junit-xml": {
+			args: []string{"-junit-xml=results.xml"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				JUnitXMLFile:  "results.xml",
+				Parallelism:   1,
+				Vars:          &Vars{},
+			},
+		},
+		"parallelism": {
+			args: []string{"-parallelism=4"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Parallelism:   4,
 				Vars:          &Vars{},
 			},
 		},
@@ -124,6 +150,7 @@ func TestParseTest(t *testing.T) {
 				Filter:        nil,
 				TestDirectory: "tests",
 				ViewType:      ViewHuman,
+				Parallelism:   1,
 				Vars:          &Vars{},
 			},
 			wantDiags: tfdiags.Diagnostics{
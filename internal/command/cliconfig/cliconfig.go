@@ -29,6 +29,7 @@ import (
 
 const pluginCacheDirEnvVar = "TF_PLUGIN_CACHE_DIR"
 const pluginCacheMayBreakLockFileEnvVar = "TF_PLUGIN_CACHE_MAY_BREAK_DEPENDENCY_LOCK_FILE"
+const moduleCacheDirEnvVar = "TF_MODULE_CACHE_DIR"
 
 // Config is the structure of the configuration for the OpenTofu CLI.
 //
@@ -53,6 +54,10 @@ type Config struct {
 	// over the requirements of the dependency lock file.
 	PluginCacheMayBreakDependencyLockFile bool `hcl:"plugin_cache_may_break_dependency_lock_file"`
 
+	// If set, enables local caching of remote module packages in this
+	// directory to avoid repeatedly re-downloading them over the Internet.
+	ModuleCacheDir string `hcl:"module_cache_dir"`
+
 	Hosts map[string]*ConfigHost `hcl:"host"`
 
 	Credentials        map[string]map[string]interface{}   `hcl:"credentials"`
@@ -63,6 +68,10 @@ type Config struct {
 	// configuration, but we decode into a slice here so that we can handle
 	// that validation at validation time rather than initial decode time.
 	ProviderInstallation []*ProviderInstallation
+
+	// Hooks represents the entries of any hooks blocks in the
+	// configuration, in the order they were declared.
+	Hooks []*ConfigHook `hcl:"-"`
 }
 
 // ConfigHost is the structure of the "host" nested block within the CLI
@@ -177,6 +186,12 @@ func loadConfigFile(path string) (*Config, tfdiags.Diagnostics) {
 	diags = diags.Append(moreDiags)
 	result.ProviderInstallation = providerInstBlocks
 
+	// The hooks block is also decoded separately, because HCL 1's decoder
+	// can't preserve the order the hooks were declared in.
+	hooks, moreDiags := decodeHooksFromConfig(obj)
+	diags = diags.Append(moreDiags)
+	result.Hooks = hooks
+
 	// Replace all env vars
 	for k, v := range result.Providers {
 		result.Providers[k] = os.ExpandEnv(v)
@@ -188,6 +203,9 @@ func loadConfigFile(path string) (*Config, tfdiags.Diagnostics) {
 	if result.PluginCacheDir != "" {
 		result.PluginCacheDir = os.ExpandEnv(result.PluginCacheDir)
 	}
+	if result.ModuleCacheDir != "" {
+		result.ModuleCacheDir = os.ExpandEnv(result.ModuleCacheDir)
+	}
 
 	return result, diags
 }
@@ -241,6 +259,11 @@ func envConfig(env map[string]string) *Config {
 		config.PluginCacheDir = envPluginCacheDir
 	}
 
+	if envModuleCacheDir := env[moduleCacheDirEnvVar]; envModuleCacheDir != "" {
+		// As with TF_PLUGIN_CACHE_DIR, no ExpandEnv here.
+		config.ModuleCacheDir = envModuleCacheDir
+	}
+
 	if envMayBreak := env[pluginCacheMayBreakLockFileEnvVar]; envMayBreak != "" && envMayBreak != "0" {
 		// This is an environment variable analog to the
 		// plugin_cache_may_break_dependency_lock_file setting. If either this
@@ -329,6 +352,15 @@ func (c *Config) Validate() tfdiags.Diagnostics {
 		}
 	}
 
+	if c.ModuleCacheDir != "" {
+		_, err := os.Stat(c.ModuleCacheDir)
+		if err != nil {
+			diags = diags.Append(
+				fmt.Errorf("The specified module cache dir %s cannot be opened: %w", c.ModuleCacheDir, err),
+			)
+		}
+	}
+
 	return diags
 }
 
@@ -362,6 +394,11 @@ func (c *Config) Merge(c2 *Config) *Config {
 		result.PluginCacheDir = c2.PluginCacheDir
 	}
 
+	result.ModuleCacheDir = c.ModuleCacheDir
+	if result.ModuleCacheDir == "" {
+		result.ModuleCacheDir = c2.ModuleCacheDir
+	}
+
 	if c.PluginCacheMayBreakDependencyLockFile || c2.PluginCacheMayBreakDependencyLockFile {
 		// This setting saturates to "on"; once either configuration sets it,
 		// there is no way to override it back to off again.
@@ -406,6 +443,11 @@ func (c *Config) Merge(c2 *Config) *Config {
 		result.ProviderInstallation = append(result.ProviderInstallation, c2.ProviderInstallation...)
 	}
 
+	if (len(c.Hooks) + len(c2.Hooks)) > 0 {
+		result.Hooks = append(result.Hooks, c.Hooks...)
+		result.Hooks = append(result.Hooks, c2.Hooks...)
+	}
+
 	return &result
 }
 
@@ -115,6 +115,14 @@ func TestEnvConfig(t *testing.T) {
 			},
 			&Config{},
 		},
+		"TF_MODULE_CACHE_DIR=boop": {
+			map[string]string{
+				"TF_MODULE_CACHE_DIR": "boop",
+			},
+			&Config{
+				ModuleCacheDir: "boop",
+			},
+		},
 		"TF_PLUGIN_CACHE_DIR and TF_PLUGIN_CACHE_MAY_BREAK_DEPENDENCY_LOCK_FILE": {
 			map[string]string{
 				"TF_PLUGIN_CACHE_DIR":                            "beep",
@@ -363,6 +371,12 @@ func TestConfigValidate(t *testing.T) {
 			},
 			1, // The specified plugin cache dir %s cannot be opened
 		},
+		"module_cache_dir does not exist": {
+			&Config{
+				ModuleCacheDir: "fake",
+			},
+			1, // The specified module cache dir %s cannot be opened
+		},
 	}
 
 	for name, test := range tests {
@@ -416,6 +430,9 @@ func TestConfig_Merge(t *testing.T) {
 				},
 			},
 		},
+		Hooks: []*ConfigHook{
+			{Event: HookEventPreApply, Command: []string{"a"}},
+		},
 	}
 
 	c2 := &Config{
@@ -448,6 +465,9 @@ func TestConfig_Merge(t *testing.T) {
 				},
 			},
 		},
+		Hooks: []*ConfigHook{
+			{Event: HookEventPreApply, Command: []string{"b"}},
+		},
 		PluginCacheMayBreakDependencyLockFile: true,
 	}
 
@@ -503,6 +523,10 @@ func TestConfig_Merge(t *testing.T) {
 				},
 			},
 		},
+		Hooks: []*ConfigHook{
+			{Event: HookEventPreApply, Command: []string{"a"}},
+			{Event: HookEventPreApply, Command: []string{"b"}},
+		},
 		PluginCacheMayBreakDependencyLockFile: true,
 	}
 
@@ -511,3 +535,37 @@ func TestConfig_Merge(t *testing.T) {
 		t.Fatalf("wrong result\n%s", diff)
 	}
 }
+
+func TestLoadConfig_hooks(t *testing.T) {
+	c, diags := loadConfigFile(filepath.Join(fixtureDir, "hooks"))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	expected := []*ConfigHook{
+		{Event: HookEventPreApply, Command: []string{"/usr/local/bin/check-freeze-window"}},
+		{Event: HookEventPostApply, Command: []string{"/usr/local/bin/notify", "--channel", "infra"}},
+		{Event: HookEventPreApply, Command: []string{"/usr/local/bin/tag-costs"}},
+		{Event: HookEventPostResourceApply, Command: []string{"/usr/local/bin/audit"}},
+	}
+
+	if diff := cmp.Diff(expected, c.Hooks); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestLoadConfig_hooksErrors(t *testing.T) {
+	_, diags := loadConfigFile(filepath.Join(fixtureDir, "hooks-errors"))
+
+	var got []string
+	for _, diag := range diags {
+		got = append(got, diag.Description().Detail)
+	}
+	want := []string{
+		`The hooks block has an unsupported event "pre_destroy". The supported events are pre_plan, post_plan, pre_apply, post_apply, pre_resource_plan, post_resource_plan, pre_resource_apply and post_resource_apply.`,
+		"The post_plan hook must have at least one element: the path of the program to run.",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong diagnostics\n%s", diff)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl"
+	hclast "github.com/hashicorp/hcl/hcl/ast"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// HookEvent is the name of a point in the lifecycle of an operation at which
+// the external programs configured in the "hooks" block can run.
+type HookEvent string
+
+const (
+	// The operation-level events run once per plan or apply operation.
+	HookEventPrePlan   HookEvent = "pre_plan"
+	HookEventPostPlan  HookEvent = "post_plan"
+	HookEventPreApply  HookEvent = "pre_apply"
+	HookEventPostApply HookEvent = "post_apply"
+
+	// The resource-level events run once for each resource instance that
+	// is planned or applied.
+	HookEventPreResourcePlan   HookEvent = "pre_resource_plan"
+	HookEventPostResourcePlan  HookEvent = "post_resource_plan"
+	HookEventPreResourceApply  HookEvent = "pre_resource_apply"
+	HookEventPostResourceApply HookEvent = "post_resource_apply"
+)
+
+var hookEvents = map[HookEvent]bool{
+	HookEventPrePlan:           true,
+	HookEventPostPlan:          true,
+	HookEventPreApply:          true,
+	HookEventPostApply:         true,
+	HookEventPreResourcePlan:   true,
+	HookEventPostResourcePlan:  true,
+	HookEventPreResourceApply:  true,
+	HookEventPostResourceApply: true,
+}
+
+// IsPre returns true if the event happens before the action it describes,
+// in which case a failing hook aborts that action.
+func (e HookEvent) IsPre() bool {
+	switch e {
+	case HookEventPrePlan, HookEventPreApply, HookEventPreResourcePlan, HookEventPreResourceApply:
+		return true
+	default:
+		return false
+	}
+}
+
+// ConfigHook is an external program configured to run at a particular
+// event by an entry in a "hooks" block within the CLI configuration:
+//
+//	hooks {
+//	  pre_apply  = ["/usr/local/bin/check-freeze-window"]
+//	  post_apply = ["/usr/local/bin/notify", "--channel", "infra"]
+//	}
+type ConfigHook struct {
+	Event HookEvent
+
+	// Command is the path of the program to run followed by any arguments
+	// to pass to it.
+	Command []string
+}
+
+// decodeHooksFromConfig decodes the "hooks" blocks from the given file,
+// returning the hooks in the order they were declared.
+func decodeHooksFromConfig(hclFile *hclast.File) ([]*ConfigHook, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	// HCL 1's decoder splits each attribute of a block into its own map,
+	// which conveniently preserves the order the hooks were declared in.
+	var raw struct {
+		Hooks []map[string][]string `hcl:"hooks"`
+	}
+	if err := hcl.DecodeObject(&raw, hclFile); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid hooks block",
+			fmt.Sprintf("The hooks block must contain only lists of strings: %s.", err),
+		))
+		return nil, diags
+	}
+
+	var ret []*ConfigHook
+	for _, block := range raw.Hooks {
+		events := make([]string, 0, len(block))
+		for event := range block {
+			events = append(events, event)
+		}
+		sort.Strings(events)
+
+		for _, event := range events {
+			command := block[event]
+			if !hookEvents[HookEvent(event)] {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid hooks block",
+					fmt.Sprintf("The hooks block has an unsupported event %q. The supported events are pre_plan, post_plan, pre_apply, post_apply, pre_resource_plan, post_resource_plan, pre_resource_apply and post_resource_apply.", event),
+				))
+				continue
+			}
+			if len(command) == 0 || command[0] == "" {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid hooks block",
+					fmt.Sprintf("The %s hook must have at least one element: the path of the program to run.", event),
+				))
+				continue
+			}
+			ret = append(ret, &ConfigHook{
+				Event:   HookEvent(event),
+				Command: command,
+			})
+		}
+	}
+
+	return ret, diags
+}
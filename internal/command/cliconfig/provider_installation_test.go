@@ -33,6 +33,10 @@ func TestLoadConfig_providerInstallation(t *testing.T) {
 								Include:  []string{"registry.opentofu.org/*/*"},
 								Exclude:  []string{"registry.OpenTofu.org/foobar/*"},
 							},
+							{
+								Location: ProviderInstallationOCIMirror("registry.example.com/providers/${namespace}/${type}"),
+								Include:  []string{"registry.opentofu.org/*/*"},
+							},
 							{
 								Location: ProviderInstallationFilesystemMirror("/tmp/example2"),
 							},
@@ -59,15 +63,16 @@ func TestLoadConfig_providerInstallation(t *testing.T) {
 
 func TestLoadConfig_providerInstallationErrors(t *testing.T) {
 	_, diags := loadConfigFile(filepath.Join(fixtureDir, "provider-installation-errors"))
-	want := `7 problems:
+	want := `8 problems:
 
 - Invalid provider_installation method block: Unknown provider installation method "not_a_thing" at 2:3.
 - Invalid provider_installation method block: Invalid filesystem_mirror block at 1:1: "path" argument is required.
 - Invalid provider_installation method block: Invalid network_mirror block at 1:1: "url" argument is required.
+- Invalid provider_installation method block: Invalid oci_mirror block at 1:1: "repository_template" argument is required.
 - Invalid provider_installation method block: The items inside the provider_installation block at 1:1 must all be blocks.
 - Invalid provider_installation method block: The blocks inside the provider_installation block at 1:1 may not have any labels.
-- Invalid provider_installation block: The provider_installation block at 9:1 must not have any labels.
-- Invalid provider_installation block: The provider_installation block at 11:1 must not be introduced with an equals sign.`
+- Invalid provider_installation block: The provider_installation block at 10:1 must not have any labels.
+- Invalid provider_installation block: The provider_installation block at 12:1 must not be introduced with an equals sign.`
 
 	// The above error messages include only line/column location information
 	// and not file location information because HCL 1 does not store
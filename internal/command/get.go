@@ -92,5 +92,5 @@ func getModules(ctx context.Context, m *Meta, path string, testsDir string, upgr
 		Ui:             m.Ui,
 		ShowLocalPaths: true,
 	}
-	return m.installModules(ctx, path, testsDir, upgrade, true, hooks)
+	return m.installModules(ctx, path, testsDir, upgrade, true, false, hooks)
 }
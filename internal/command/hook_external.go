@@ -0,0 +1,260 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/cliconfig"
+	viewsjson "github.com/opentofu/opentofu/internal/command/views/json"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// externalHookEventEnvVar is set in the environment of each external hook
+// program to the name of the event it is running for.
+const externalHookEventEnvVar = "TOFU_HOOK_EVENT"
+
+// ExternalHooks runs the external programs configured in the "hooks" blocks
+// of the CLI configuration, writing a JSON description of each event to the
+// standard input of the program.
+//
+// ExternalHooks implements tofu.Hook to run the resource-level hooks, while
+// the operation-level hooks are run around the operation by RunPre and
+// RunPost. It also implements backend.OperationPlanHook, to run the plan
+// hooks for the plan that an apply operation creates itself.
+type ExternalHooks struct {
+	tofu.NilHook
+
+	hooks     []*cliconfig.ConfigHook
+	workspace string
+
+	mu       sync.Mutex
+	actions  map[string]plans.Action
+	warnings tfdiags.Diagnostics
+}
+
+var _ tofu.Hook = (*ExternalHooks)(nil)
+var _ backend.OperationPlanHook = (*ExternalHooks)(nil)
+
+// externalHookPayload is the JSON object written to the standard input of
+// each external hook program.
+type externalHookPayload struct {
+	Event     cliconfig.HookEvent `json:"event"`
+	Workspace string              `json:"workspace"`
+
+	// Success is set only for the operation-level post events.
+	Success *bool `json:"success,omitempty"`
+
+	// Resource and Action are set only for the resource-level events, and
+	// Error is set for a failed post_resource_apply.
+	Resource *viewsjson.ResourceAddr `json:"resource,omitempty"`
+	Action   viewsjson.ChangeAction  `json:"action,omitempty"`
+	Error    string                  `json:"error,omitempty"`
+}
+
+// externalHooks returns the external hooks configured in the CLI
+// configuration, or nil if there are none.
+func (m *Meta) externalHooks() *ExternalHooks {
+	if len(m.ExternalHooks) == 0 {
+		return nil
+	}
+
+	workspace, err := m.Workspace()
+	if err != nil {
+		// The workspace is only informational for the hooks, and any
+		// problem with it is reported by the operation itself.
+		log.Printf("[WARN] Failed to determine the workspace for external hooks: %s", err)
+	}
+
+	return &ExternalHooks{
+		hooks:     m.ExternalHooks,
+		workspace: workspace,
+		actions:   make(map[string]plans.Action),
+	}
+}
+
+// RunPre runs the hooks for the given operation-level pre event. If any of
+// them fail then the returned diagnostics contains errors and the operation
+// must not be started.
+func (h *ExternalHooks) RunPre(event cliconfig.HookEvent) tfdiags.Diagnostics {
+	if h == nil {
+		return nil
+	}
+	return h.run(externalHookPayload{Event: event})
+}
+
+// RunPost runs the hooks for the given operation-level post event. Any
+// failures are returned as warnings, along with any failures of the
+// resource-level post hooks that ran during the operation.
+func (h *ExternalHooks) RunPost(event cliconfig.HookEvent, result backend.OperationResult) tfdiags.Diagnostics {
+	if h == nil {
+		return nil
+	}
+	success := result == backend.OperationSuccess
+	diags := h.run(externalHookPayload{Event: event, Success: &success})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.warnings.Append(diags)
+}
+
+// PrePlan implements backend.OperationPlanHook.
+func (h *ExternalHooks) PrePlan() tfdiags.Diagnostics {
+	return h.RunPre(cliconfig.HookEventPrePlan)
+}
+
+// PostPlan implements backend.OperationPlanHook.
+func (h *ExternalHooks) PostPlan(success bool) tfdiags.Diagnostics {
+	return h.run(externalHookPayload{Event: cliconfig.HookEventPostPlan, Success: &success})
+}
+
+func (h *ExternalHooks) PreDiff(addr addrs.AbsResourceInstance, gen states.Generation, priorState, proposedNewState cty.Value) (tofu.HookAction, error) {
+	return h.runResource(externalHookPayload{Event: cliconfig.HookEventPreResourcePlan}, addr)
+}
+
+func (h *ExternalHooks) PostDiff(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (tofu.HookAction, error) {
+	return h.runResource(externalHookPayload{
+		Event:  cliconfig.HookEventPostResourcePlan,
+		Action: externalHookAction(addr, action),
+	}, addr)
+}
+
+func (h *ExternalHooks) PreApply(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (tofu.HookAction, error) {
+	// PostApply isn't told the action, so we remember it for the payload.
+	h.mu.Lock()
+	h.actions[externalHookKey(addr, gen)] = action
+	h.mu.Unlock()
+
+	return h.runResource(externalHookPayload{
+		Event:  cliconfig.HookEventPreResourceApply,
+		Action: externalHookAction(addr, action),
+	}, addr)
+}
+
+func (h *ExternalHooks) PostApply(addr addrs.AbsResourceInstance, gen states.Generation, newState cty.Value, err error) (tofu.HookAction, error) {
+	h.mu.Lock()
+	action, ok := h.actions[externalHookKey(addr, gen)]
+	h.mu.Unlock()
+
+	payload := externalHookPayload{Event: cliconfig.HookEventPostResourceApply}
+	if ok {
+		payload.Action = externalHookAction(addr, action)
+	}
+	if err != nil {
+		payload.Error = err.Error()
+	}
+	return h.runResource(payload, addr)
+}
+
+// runResource runs the hooks for a resource-level event. Failures of pre
+// hooks are returned as an error, which fails the action for the resource
+// instance, while failures of post hooks are kept as warnings to report once
+// the operation completes.
+func (h *ExternalHooks) runResource(payload externalHookPayload, addr addrs.AbsResourceInstance) (tofu.HookAction, error) {
+	if !h.has(payload.Event) {
+		return tofu.HookActionContinue, nil
+	}
+
+	payload.Resource = &externalHookChange(addr, plans.NoOp).Resource
+	diags := h.run(payload)
+	if diags.HasErrors() {
+		return tofu.HookActionHalt, diags.Err()
+	}
+
+	h.mu.Lock()
+	h.warnings = h.warnings.Append(diags)
+	h.mu.Unlock()
+	return tofu.HookActionContinue, nil
+}
+
+func (h *ExternalHooks) has(event cliconfig.HookEvent) bool {
+	for _, hook := range h.hooks {
+		if hook.Event == event {
+			return true
+		}
+	}
+	return false
+}
+
+// run runs each of the hooks for the event of the given payload in turn,
+// stopping at the first failure of a pre hook.
+func (h *ExternalHooks) run(payload externalHookPayload) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	payload.Workspace = h.workspace
+	input, err := json.Marshal(payload)
+	if err != nil {
+		// Should never happen, since we control the whole payload.
+		return diags.Append(fmt.Errorf("failed to encode the %s hook payload: %w", payload.Event, err))
+	}
+
+	for _, hook := range h.hooks {
+		if hook.Event != payload.Event {
+			continue
+		}
+
+		log.Printf("[DEBUG] Running %s hook %q", hook.Event, hook.Command[0])
+		cmd := exec.Command(hook.Command[0], hook.Command[1:]...)
+		cmd.Stdin = bytes.NewReader(input)
+		cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", externalHookEventEnvVar, hook.Event))
+		output, err := cmd.CombinedOutput()
+		if err == nil {
+			log.Printf("[DEBUG] The %s hook %q succeeded: %s", hook.Event, hook.Command[0], output)
+			continue
+		}
+
+		detail := fmt.Sprintf("The %s hook %q failed: %s.", hook.Event, hook.Command[0], err)
+		if output := strings.TrimSpace(string(output)); output != "" {
+			detail += "\n\n" + output
+		}
+
+		if !hook.Event.IsPre() {
+			diags = diags.Append(tfdiags.Sourceless(tfdiags.Warning, "External hook failed", detail))
+			continue
+		}
+		if payload.Resource != nil {
+			detail += fmt.Sprintf("\n\nOpenTofu will not continue with %s.", payload.Resource.Addr)
+		} else {
+			detail += "\n\nOpenTofu will not start the operation."
+		}
+		return diags.Append(tfdiags.Sourceless(tfdiags.Error, "External hook failed", detail))
+	}
+
+	return diags
+}
+
+// externalHookChange describes the given change in the same format as the
+// machine-readable UI.
+func externalHookChange(addr addrs.AbsResourceInstance, action plans.Action) *viewsjson.ResourceInstanceChange {
+	return viewsjson.NewResourceInstanceChange(&plans.ResourceInstanceChangeSrc{
+		Addr:        addr,
+		PrevRunAddr: addr,
+		ChangeSrc:   plans.ChangeSrc{Action: action},
+	})
+}
+
+func externalHookAction(addr addrs.AbsResourceInstance, action plans.Action) viewsjson.ChangeAction {
+	return externalHookChange(addr, action).Action
+}
+
+func externalHookKey(addr addrs.AbsResourceInstance, gen states.Generation) string {
+	if dk, ok := gen.(states.DeposedKey); ok {
+		return fmt.Sprintf("%s (deposed %s)", addr, dk)
+	}
+	return addr.String()
+}
@@ -0,0 +1,292 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/cliconfig"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// testExternalHookScript writes a shell script that records its standard
+// input and the hook event it was run for into the file at the given path,
+// and then exits with the given status.
+func testExternalHookScript(t *testing.T, record string, status string) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("external hook tests use shell scripts")
+	}
+
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	script := "#!/bin/sh\necho \"$TOFU_HOOK_EVENT\" >> \"" + record + "\"\ncat >> \"" + record + "\"\necho >> \"" + record + "\"\necho \"hook output\"\nexit " + status + "\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExternalHooks_resource(t *testing.T) {
+	record := filepath.Join(t.TempDir(), "record")
+	script := testExternalHookScript(t, record, "0")
+
+	h := &ExternalHooks{
+		hooks: []*cliconfig.ConfigHook{
+			{Event: cliconfig.HookEventPreResourceApply, Command: []string{script}},
+			{Event: cliconfig.HookEventPostResourceApply, Command: []string{script}},
+		},
+		workspace: "default",
+		actions:   make(map[string]plans.Action),
+	}
+
+	addr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_instance",
+		Name: "foo",
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+
+	action, err := h.PreApply(addr, states.CurrentGen, plans.Create, cty.NullVal(cty.EmptyObject), cty.EmptyObjectVal)
+	if err != nil || action != tofu.HookActionContinue {
+		t.Fatalf("unexpected result from PreApply: %v, %s", action, err)
+	}
+	// PreDiff has no hooks configured, so it shouldn't run anything.
+	if _, err := h.PreDiff(addr, states.CurrentGen, cty.NullVal(cty.EmptyObject), cty.EmptyObjectVal); err != nil {
+		t.Fatalf("unexpected error from PreDiff: %s", err)
+	}
+	if _, err := h.PostApply(addr, states.CurrentGen, cty.EmptyObjectVal, nil); err != nil {
+		t.Fatalf("unexpected error from PostApply: %s", err)
+	}
+
+	raw, err := os.ReadFile(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected two recorded hook runs, got:\n%s", raw)
+	}
+
+	for i, event := range []cliconfig.HookEvent{cliconfig.HookEventPreResourceApply, cliconfig.HookEventPostResourceApply} {
+		if got := lines[i*2]; got != string(event) {
+			t.Errorf("wrong event in environment: got %q, want %q", got, event)
+		}
+
+		var payload map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[i*2+1]), &payload); err != nil {
+			t.Fatalf("invalid payload %q: %s", lines[i*2+1], err)
+		}
+		resource := payload["resource"].(map[string]interface{})
+		got := map[string]interface{}{
+			"event":     payload["event"],
+			"workspace": payload["workspace"],
+			"action":    payload["action"],
+			"addr":      resource["addr"],
+		}
+		want := map[string]interface{}{
+			"event":     string(event),
+			"workspace": "default",
+			"action":    "create",
+			"addr":      "test_instance.foo",
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong payload for %s\n%s", event, diff)
+		}
+	}
+}
+
+func TestExternalHooks_failures(t *testing.T) {
+	record := filepath.Join(t.TempDir(), "record")
+	script := testExternalHookScript(t, record, "1")
+
+	h := &ExternalHooks{
+		hooks: []*cliconfig.ConfigHook{
+			{Event: cliconfig.HookEventPreApply, Command: []string{script}},
+			{Event: cliconfig.HookEventPreResourcePlan, Command: []string{script}},
+			{Event: cliconfig.HookEventPostResourcePlan, Command: []string{script}},
+			{Event: cliconfig.HookEventPostApply, Command: []string{script}},
+		},
+		workspace: "default",
+		actions:   make(map[string]plans.Action),
+	}
+
+	diags := h.RunPre(cliconfig.HookEventPreApply)
+	if !diags.HasErrors() {
+		t.Fatal("expected an error from the failing pre_apply hook")
+	}
+	if got, want := diags[0].Description().Detail, "hook output\n\nOpenTofu will not start the operation."; !strings.HasSuffix(got, want) {
+		t.Errorf("wrong detail %q; want suffix %q", got, want)
+	}
+
+	addr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_instance",
+		Name: "foo",
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+
+	action, err := h.PreDiff(addr, states.CurrentGen, cty.NullVal(cty.EmptyObject), cty.EmptyObjectVal)
+	if err == nil || action != tofu.HookActionHalt {
+		t.Fatalf("expected the failing pre_resource_plan hook to halt, got %v, %v", action, err)
+	}
+	if _, err := h.PostDiff(addr, states.CurrentGen, plans.Create, cty.NullVal(cty.EmptyObject), cty.EmptyObjectVal); err != nil {
+		t.Fatalf("failing post hooks must not fail the resource: %s", err)
+	}
+
+	// The failing post_resource_plan and post_apply hooks are both reported
+	// as warnings once the operation completes.
+	diags = h.RunPost(cliconfig.HookEventPostApply, backend.OperationSuccess)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if len(diags) != 2 {
+		t.Fatalf("expected two warnings, got %d", len(diags))
+	}
+	for _, diag := range diags {
+		if diag.Severity() != tfdiags.Warning {
+			t.Errorf("expected a warning, got %#v", diag)
+		}
+	}
+}
+
+func TestPlan_externalHooks(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	defer testChdir(t, td)()
+
+	record := filepath.Join(td, "record")
+	failing := testExternalHookScript(t, record, "1")
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+			ExternalHooks: []*cliconfig.ConfigHook{
+				{Event: cliconfig.HookEventPrePlan, Command: []string{failing}},
+			},
+		},
+	}
+
+	code := c.Run(nil)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected the failing pre_plan hook to abort the plan, got %d\n\n%s", code, output.All())
+	}
+	if got, want := output.Stderr(), "External hook failed"; !strings.Contains(got, want) {
+		t.Errorf("missing %q in output:\n%s", want, got)
+	}
+	if p.PlanResourceChangeCalled {
+		t.Error("the plan should not have started")
+	}
+}
+
+func TestApply_externalHooks(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+
+	statePath := testTempFile(t)
+	record := filepath.Join(td, "record")
+	script := testExternalHookScript(t, record, "0")
+
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+			ExternalHooks: []*cliconfig.ConfigHook{
+				{Event: cliconfig.HookEventPreApply, Command: []string{script}},
+				{Event: cliconfig.HookEventPrePlan, Command: []string{script}},
+				{Event: cliconfig.HookEventPostPlan, Command: []string{script}},
+				{Event: cliconfig.HookEventPostResourceApply, Command: []string{script}},
+				{Event: cliconfig.HookEventPostApply, Command: []string{script}},
+			},
+		},
+	}
+
+	code := c.Run([]string{"-state", statePath, "-auto-approve"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	raw, err := os.ReadFile(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []string
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	for i := 0; i < len(lines); i += 2 {
+		events = append(events, lines[i])
+	}
+	// The plan hooks run for the plan that apply creates itself.
+	want := []string{"pre_apply", "pre_plan", "post_plan", "post_resource_apply", "post_apply"}
+	if diff := cmp.Diff(want, events); diff != "" {
+		t.Errorf("wrong hook events\n%s", diff)
+	}
+	if got, want := lines[len(lines)-1], `"success":true`; !strings.Contains(got, want) {
+		t.Errorf("post_apply payload %s does not contain %s", got, want)
+	}
+}
+
+func TestApply_externalHooksPrePlanFailure(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+
+	statePath := testTempFile(t)
+	record := filepath.Join(td, "record")
+	script := testExternalHookScript(t, record, "0")
+	failing := testExternalHookScript(t, record, "1")
+
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+			ExternalHooks: []*cliconfig.ConfigHook{
+				{Event: cliconfig.HookEventPrePlan, Command: []string{failing}},
+				{Event: cliconfig.HookEventPostApply, Command: []string{script}},
+			},
+		},
+	}
+
+	code := c.Run([]string{"-state", statePath, "-auto-approve"})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected the failing pre_plan hook to abort the apply, got %d\n\n%s", code, output.All())
+	}
+	if p.PlanResourceChangeCalled {
+		t.Error("the plan should not have started")
+	}
+
+	raw, err := os.ReadFile(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	if got, want := lines[len(lines)-2], "post_apply"; got != want {
+		t.Fatalf("wrong last hook event %q; want %q", got, want)
+	}
+	if got, want := lines[len(lines)-1], `"success":false`; !strings.Contains(got, want) {
+		t.Errorf("post_apply payload %s does not contain %s", got, want)
+	}
+}
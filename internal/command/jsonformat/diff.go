@@ -9,6 +9,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/jsonformat/structured"
 	"github.com/opentofu/opentofu/internal/command/jsonformat/structured/attribute_path"
 	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/command/jsonprovider"
 	"github.com/opentofu/opentofu/internal/plans"
 )
 
@@ -55,10 +56,9 @@ func precomputeDiffs(plan Plan, mode plans.Mode) diffs {
 
 	for _, change := range plan.ResourceChanges {
 		schema := plan.getSchema(change)
-		structuredChange := structured.FromJsonChange(change.Change, attribute_path.AlwaysMatcher())
 		diffs.changes = append(diffs.changes, diff{
 			change: change,
-			diff:   differ.ComputeDiffForBlock(structuredChange, schema.Block),
+			diff:   computeDiffForResourceChange(change, schema.Block),
 		})
 	}
 
@@ -70,6 +70,20 @@ func precomputeDiffs(plan Plan, mode plans.Mode) diffs {
 	return diffs
 }
 
+// computeDiffForResourceChange computes the diff for a planned resource
+// change.
+func computeDiffForResourceChange(change jsonplan.ResourceChange, block *jsonprovider.Block) computed.Diff {
+	structuredChange := structured.FromJsonChange(change.Change, attribute_path.AlwaysMatcher())
+	if jsonplan.UnmarshalActions(change.Change.Actions) == plans.Forget {
+		// Forgetting an object doesn't change it, so we render the body
+		// as unchanged rather than as if every attribute was removed.
+		structuredChange.After = structuredChange.Before
+		structuredChange.AfterSensitive = structuredChange.BeforeSensitive
+		structuredChange.Unknown = false
+	}
+	return differ.ComputeDiffForBlock(structuredChange, block)
+}
+
 type diffs struct {
 	drift   []diff
 	changes []diff
@@ -78,7 +92,7 @@ type diffs struct {
 
 func (d diffs) Empty() bool {
 	for _, change := range d.changes {
-		if change.diff.Action != plans.NoOp || change.Moved() {
+		if change.diff.Action != plans.NoOp || change.Moved() || change.Forgotten() {
 			return false
 		}
 	}
@@ -101,6 +115,10 @@ func (d diff) Moved() bool {
 	return len(d.change.PreviousAddress) > 0 && d.change.PreviousAddress != d.change.Address
 }
 
+func (d diff) Forgotten() bool {
+	return jsonplan.UnmarshalActions(d.change.Change.Actions) == plans.Forget
+}
+
 func (d diff) Importing() bool {
 	return d.change.Change.Importing != nil
 }
@@ -30,6 +30,7 @@ type Plan struct {
 	ResourceChanges    []jsonplan.ResourceChange  `json:"resource_changes"`
 	ResourceDrift      []jsonplan.ResourceChange  `json:"resource_drift"`
 	RelevantAttributes []jsonplan.ResourceAttr    `json:"relevant_attributes"`
+	DeferredChanges    []jsonplan.DeferredChange  `json:"deferred_changes"`
 
 	ProviderFormatVersion string                            `json:"provider_format_version"`
 	ProviderSchemas       map[string]*jsonprovider.Provider `json:"provider_schemas"`
@@ -91,7 +92,7 @@ func (plan Plan) renderHuman(renderer Renderer, mode plans.Mode, opts ...plans.Q
 	// display the "there are no changes messages".
 	outputs := renderHumanDiffOutputs(renderer, diffs.outputs)
 
-	if len(changes) == 0 && len(outputs) == 0 {
+	if len(changes) == 0 && len(outputs) == 0 && len(plan.DeferredChanges) == 0 {
 		// If we didn't find any changes to report at all then this is a
 		// "No changes" plan. How we'll present this depends on whether
 		// the plan is "applyable" and, if so, whether it had refresh changes
@@ -202,6 +203,9 @@ func (plan Plan) renderHuman(renderer Renderer, mode plans.Mode, opts ...plans.Q
 		if counts[plans.Read] > 0 {
 			renderer.Streams.Println(renderer.Colorize.Color(actionDescription(plans.Read)))
 		}
+		if counts[plans.Forget] > 0 {
+			renderer.Streams.Println(renderer.Colorize.Color(actionDescription(plans.Forget)))
+		}
 	}
 
 	if len(changes) > 0 {
@@ -219,22 +223,38 @@ func (plan Plan) renderHuman(renderer Renderer, mode plans.Mode, opts ...plans.Q
 			}
 		}
 
+		// Forgotten and deferred objects are only mentioned in the summary
+		// when there are some, to keep the common case unchanged.
+		var extraSummary string
+		if counts[plans.Forget] > 0 {
+			extraSummary = fmt.Sprintf(", %d to forget", counts[plans.Forget])
+		}
+		if len(plan.DeferredChanges) > 0 {
+			extraSummary += fmt.Sprintf(", %d deferred", len(plan.DeferredChanges))
+		}
+
 		if importingCount > 0 {
 			renderer.Streams.Printf(
-				renderer.Colorize.Color("\n[bold]Plan:[reset] %d to import, %d to add, %d to change, %d to destroy.\n"),
+				renderer.Colorize.Color("\n[bold]Plan:[reset] %d to import, %d to add, %d to change, %d to destroy%s.\n"),
 				importingCount,
 				counts[plans.Create]+counts[plans.DeleteThenCreate]+counts[plans.CreateThenDelete],
 				counts[plans.Update],
-				counts[plans.Delete]+counts[plans.DeleteThenCreate]+counts[plans.CreateThenDelete])
+				counts[plans.Delete]+counts[plans.DeleteThenCreate]+counts[plans.CreateThenDelete],
+				extraSummary)
 		} else {
 			renderer.Streams.Printf(
-				renderer.Colorize.Color("\n[bold]Plan:[reset] %d to add, %d to change, %d to destroy.\n"),
+				renderer.Colorize.Color("\n[bold]Plan:[reset] %d to add, %d to change, %d to destroy%s.\n"),
 				counts[plans.Create]+counts[plans.DeleteThenCreate]+counts[plans.CreateThenDelete],
 				counts[plans.Update],
-				counts[plans.Delete]+counts[plans.DeleteThenCreate]+counts[plans.CreateThenDelete])
+				counts[plans.Delete]+counts[plans.DeleteThenCreate]+counts[plans.CreateThenDelete],
+				extraSummary)
 		}
 	}
 
+	if len(plan.DeferredChanges) > 0 {
+		renderHumanDeferredChanges(renderer, plan.DeferredChanges)
+	}
+
 	if len(outputs) > 0 {
 		renderer.Streams.Print("\nChanges to Outputs:\n")
 		renderer.Streams.Printf("%s\n", outputs)
@@ -251,6 +271,22 @@ func (plan Plan) renderHuman(renderer Renderer, mode plans.Mode, opts ...plans.Q
 	}
 }
 
+// renderHumanDeferredChanges lists the objects that were left out of a plan
+// created with deferral allowed, along with the reason for each.
+func renderHumanDeferredChanges(renderer Renderer, deferred []jsonplan.DeferredChange) {
+	renderer.Streams.Println(format.WordWrap(
+		"\nOpenTofu deferred the changes for the following objects, which can only be planned once the values they depend on are known:",
+		renderer.Streams.Stdout.Columns()))
+	for _, change := range deferred {
+		switch plans.DeferredReason(change.Reason) {
+		case plans.DeferredReasonInstanceCountUnknown:
+			renderer.Streams.Println(renderer.Colorize.Color(fmt.Sprintf("  [bold]%s[reset], because its count or for_each value is not known yet", change.Address)))
+		default:
+			renderer.Streams.Println(renderer.Colorize.Color(fmt.Sprintf("  [bold]%s[reset], because it depends on deferred objects", change.Address)))
+		}
+	}
+}
+
 func renderHumanDiffOutputs(renderer Renderer, outputs map[string]computed.Diff) string {
 	var rendered []string
 
@@ -455,6 +491,8 @@ func resourceChangeComment(resource jsonplan.ResourceChange, action plans.Action
 			// Some extra context about this unusual situation.
 			buf.WriteString("\n  # (left over from a partially-failed replacement of this instance)")
 		}
+	case plans.Forget:
+		buf.WriteString(fmt.Sprintf("[bold]  # %s[reset] will be removed from the OpenTofu state but will not be destroyed", dispAddr))
 	case plans.NoOp:
 		if len(resource.PreviousAddress) > 0 && resource.PreviousAddress != resource.Address {
 			buf.WriteString(fmt.Sprintf("[bold]  # %s[reset] has moved to [bold]%s[reset]", resource.PreviousAddress, dispAddr))
@@ -523,6 +561,8 @@ func actionDescription(action plans.Action) string {
 		return "[red]-[reset]/[green]+[reset] destroy and then create replacement"
 	case plans.Read:
 		return " [cyan]<=[reset] read (data resources)"
+	case plans.Forget:
+		return "  [red].[reset] forget"
 	default:
 		panic(fmt.Sprintf("unrecognized change type: %s", action.String()))
 	}
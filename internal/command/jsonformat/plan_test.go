@@ -13,9 +13,6 @@ import (
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
-	"github.com/opentofu/opentofu/internal/command/jsonformat/differ"
-	"github.com/opentofu/opentofu/internal/command/jsonformat/structured"
-	"github.com/opentofu/opentofu/internal/command/jsonformat/structured/attribute_path"
 	"github.com/opentofu/opentofu/internal/command/jsonplan"
 	"github.com/opentofu/opentofu/internal/command/jsonprovider"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
@@ -80,6 +77,39 @@ found no differences, so no changes are needed.
 	}
 }
 
+func TestRenderHuman_DeferredChanges(t *testing.T) {
+	color := &colorstring.Colorize{Colors: colorstring.DefaultColors, Disable: true}
+	streams, done := terminal.StreamsForTesting(t)
+
+	plan := Plan{
+		DeferredChanges: []jsonplan.DeferredChange{
+			{
+				Address: "module.child",
+				Reason:  string(plans.DeferredReasonInstanceCountUnknown),
+			},
+			{
+				Address: "test_instance.foo",
+				Reason:  string(plans.DeferredReasonDeferredPrereq),
+			},
+		},
+	}
+
+	renderer := Renderer{Colorize: color, Streams: streams}
+	plan.renderHuman(renderer, plans.NormalMode)
+
+	want := `
+OpenTofu deferred the changes for the following objects, which can only be
+planned once the values they depend on are known:
+  module.child, because its count or for_each value is not known yet
+  test_instance.foo, because it depends on deferred objects
+`
+
+	got := done(t).Stdout()
+	if diff := cmp.Diff(want, got); len(diff) > 0 {
+		t.Errorf("unexpected output\ngot:\n%s\nwant:\n%s\ndiff:\n%s", got, want, diff)
+	}
+}
+
 func TestRenderHuman_Imports(t *testing.T) {
 	color := &colorstring.Colorize{Colors: colorstring.DefaultColors, Disable: true}
 
@@ -526,6 +556,24 @@ func TestResourceChange_primitiveTypes(t *testing.T) {
 			ExpectedOutput: `  # test_instance.example will be destroyed
   - resource "test_instance" "example" {
       - id = "i-02ae66f368e8518a9" -> null
+    }`,
+		},
+		"forget": {
+			Action: plans.Forget,
+			Mode:   addrs.ManagedResourceMode,
+			Before: cty.ObjectVal(map[string]cty.Value{
+				"id": cty.StringVal("i-02ae66f368e8518a9"),
+			}),
+			After: cty.NullVal(cty.EmptyObject),
+			Schema: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"id": {Type: cty.String, Computed: true},
+				},
+			},
+			RequiredReplace: cty.NewPathSet(),
+			ExpectedOutput: `  # test_instance.example will be removed from the OpenTofu state but will not be destroyed
+  . resource "test_instance" "example" {
+        id = "i-02ae66f368e8518a9"
     }`,
 		},
 		"deletion of deposed object": {
@@ -7009,11 +7057,10 @@ func runTestCases(t *testing.T, testCases map[string]testCase) {
 			}
 
 			jsonschemas := jsonprovider.MarshalForRenderer(tfschemas)
-			change := structured.FromJsonChange(jsonchanges[0].Change, attribute_path.AlwaysMatcher())
 			renderer := Renderer{Colorize: color}
 			diff := diff{
 				change: jsonchanges[0],
-				diff:   differ.ComputeDiffForBlock(change, jsonschemas[jsonchanges[0].ProviderName].ResourceSchemas[jsonchanges[0].Type].Block),
+				diff:   computeDiffForResourceChange(jsonchanges[0], jsonschemas[jsonchanges[0].ProviderName].ResourceSchemas[jsonchanges[0].Type].Block),
 			}
 			output, _ := renderHumanDiff(renderer, diff, proposedChange)
 			if diff := cmp.Diff(output, tc.ExpectedOutput); diff != "" {
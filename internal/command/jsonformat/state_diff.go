@@ -0,0 +1,399 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonformat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/opentofu/opentofu/internal/command/format"
+	"github.com/opentofu/opentofu/internal/command/jsonformat/computed"
+	"github.com/opentofu/opentofu/internal/command/jsonformat/differ"
+	"github.com/opentofu/opentofu/internal/command/jsonformat/structured"
+	"github.com/opentofu/opentofu/internal/command/jsonprovider"
+	"github.com/opentofu/opentofu/internal/command/jsonstate"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// StateDiffFormatVersion is the version of the JSON representation of a
+// StateDiff returned by StateDiff.JSON.
+const StateDiffFormatVersion = "1.0"
+
+// StateDiff compares two snapshots of a state, each marshalled in the same
+// form as for RenderHumanState.
+type StateDiff struct {
+	Before State
+	After  State
+}
+
+// StateDiffJSON is the machine-readable representation of a StateDiff. It
+// only describes which attributes changed and never includes their values,
+// so it can't reveal sensitive values.
+type StateDiffJSON struct {
+	FormatVersion   string                  `json:"format_version"`
+	ResourceChanges []StateDiffResourceJSON `json:"resource_changes"`
+	OutputChanges   []StateDiffOutputJSON   `json:"output_changes"`
+}
+
+// StateDiffResourceJSON describes a resource instance object that differs
+// between the two snapshots.
+type StateDiffResourceJSON struct {
+	Address      string          `json:"address"`
+	Mode         string          `json:"mode"`
+	Type         string          `json:"type"`
+	Name         string          `json:"name"`
+	Index        json.RawMessage `json:"index,omitempty"`
+	Deposed      string          `json:"deposed,omitempty"`
+	ProviderName string          `json:"provider_name"`
+
+	// Action is "create" if the object only exists in the newer snapshot,
+	// "delete" if it only exists in the older snapshot, and "update"
+	// otherwise.
+	Action string `json:"action"`
+
+	// ChangedPaths lists the paths of the attributes whose values or
+	// sensitivity differ, for the "update" action only. Each path is a
+	// sequence of attribute names, map keys and list indexes.
+	ChangedPaths [][]interface{} `json:"changed_paths,omitempty"`
+
+	// TaintedChanged is true if the object was tainted in only one of the
+	// snapshots.
+	TaintedChanged bool `json:"tainted_changed,omitempty"`
+}
+
+// StateDiffOutputJSON describes a root module output value that differs
+// between the two snapshots.
+type StateDiffOutputJSON struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+}
+
+type stateDiffResource struct {
+	before, after *jsonstate.Resource
+}
+
+func (d stateDiffResource) resource() jsonstate.Resource {
+	if d.after != nil {
+		return *d.after
+	}
+	return *d.before
+}
+
+func (d stateDiffResource) action() plans.Action {
+	switch {
+	case d.before == nil:
+		return plans.Create
+	case d.after == nil:
+		return plans.Delete
+	case d.taintedChanged() || len(d.changedPaths()) > 0:
+		return plans.Update
+	default:
+		return plans.NoOp
+	}
+}
+
+func (d stateDiffResource) taintedChanged() bool {
+	return d.before != nil && d.after != nil && d.before.Tainted != d.after.Tainted
+}
+
+func (d stateDiffResource) changedPaths() [][]interface{} {
+	if d.before == nil || d.after == nil {
+		return nil
+	}
+	change := structured.FromJsonResources(d.before, d.after)
+
+	var ret [][]interface{}
+	seen := make(map[string]bool)
+	add := func(path []interface{}) {
+		key := fmt.Sprintf("%#v", path)
+		if !seen[key] {
+			seen[key] = true
+			ret = append(ret, path)
+		}
+	}
+	walkChangedValues(change.Before, change.After, []interface{}{}, add)
+	walkChangedSensitivity(change.BeforeSensitive, change.AfterSensitive, []interface{}{}, add)
+	return ret
+}
+
+// resources returns the resource instance objects from both snapshots, in
+// address order, paired by their address and deposed key.
+func (diff StateDiff) resources() []stateDiffResource {
+	type key struct {
+		address, deposed string
+	}
+	pairs := make(map[key]*stateDiffResource)
+	var keys []key
+
+	var collect func(module jsonstate.Module, after bool)
+	collect = func(module jsonstate.Module, after bool) {
+		for i := range module.Resources {
+			resource := &module.Resources[i]
+			k := key{resource.Address, resource.DeposedKey}
+			pair, ok := pairs[k]
+			if !ok {
+				pair = &stateDiffResource{}
+				pairs[k] = pair
+				keys = append(keys, k)
+			}
+			if after {
+				pair.after = resource
+			} else {
+				pair.before = resource
+			}
+		}
+		for _, child := range module.ChildModules {
+			collect(child, after)
+		}
+	}
+	collect(diff.Before.RootModule, false)
+	collect(diff.After.RootModule, true)
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].address != keys[j].address {
+			return keys[i].address < keys[j].address
+		}
+		return keys[i].deposed < keys[j].deposed
+	})
+
+	ret := make([]stateDiffResource, 0, len(keys))
+	for _, k := range keys {
+		ret = append(ret, *pairs[k])
+	}
+	return ret
+}
+
+// outputs returns the diffs of the root module output values that differ
+// between both snapshots.
+func (diff StateDiff) outputs() map[string]computed.Diff {
+	ret := make(map[string]computed.Diff)
+	for name, before := range diff.Before.RootModuleOutputs {
+		before := before
+		var after *jsonstate.Output
+		if output, ok := diff.After.RootModuleOutputs[name]; ok {
+			after = &output
+		}
+		if output := differ.ComputeDiffForOutput(structured.FromJsonOutputs(&before, after)); output.Action != plans.NoOp {
+			ret[name] = output
+		}
+	}
+	for name, after := range diff.After.RootModuleOutputs {
+		after := after
+		if _, ok := diff.Before.RootModuleOutputs[name]; ok {
+			continue
+		}
+		ret[name] = differ.ComputeDiffForOutput(structured.FromJsonOutputs(nil, &after))
+	}
+	return ret
+}
+
+func (diff StateDiff) getSchema(resource jsonstate.Resource) *jsonprovider.Schema {
+	if _, ok := diff.After.ProviderSchemas[resource.ProviderName]; ok {
+		return diff.After.GetSchema(resource)
+	}
+	return diff.Before.GetSchema(resource)
+}
+
+// JSON returns the machine-readable representation of the diff.
+func (diff StateDiff) JSON() StateDiffJSON {
+	ret := StateDiffJSON{
+		FormatVersion:   StateDiffFormatVersion,
+		ResourceChanges: []StateDiffResourceJSON{},
+		OutputChanges:   []StateDiffOutputJSON{},
+	}
+
+	for _, d := range diff.resources() {
+		action := d.action()
+		if action == plans.NoOp {
+			continue
+		}
+		resource := d.resource()
+		ret.ResourceChanges = append(ret.ResourceChanges, StateDiffResourceJSON{
+			Address:        resource.Address,
+			Mode:           resource.Mode,
+			Type:           resource.Type,
+			Name:           resource.Name,
+			Index:          resource.Index,
+			Deposed:        resource.DeposedKey,
+			ProviderName:   resource.ProviderName,
+			Action:         stateDiffAction(action),
+			ChangedPaths:   d.changedPaths(),
+			TaintedChanged: d.taintedChanged(),
+		})
+	}
+
+	outputs := diff.outputs()
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ret.OutputChanges = append(ret.OutputChanges, StateDiffOutputJSON{
+			Name:   name,
+			Action: stateDiffAction(outputs[name].Action),
+		})
+	}
+
+	return ret
+}
+
+func (renderer Renderer) RenderHumanStateDiff(diff StateDiff) {
+	if incompatibleVersions(jsonstate.FormatVersion, diff.Before.StateFormatVersion) || incompatibleVersions(jsonstate.FormatVersion, diff.After.StateFormatVersion) || incompatibleVersions(jsonprovider.FormatVersion, diff.After.ProviderFormatVersion) {
+		renderer.Streams.Println(format.WordWrap(
+			renderer.Colorize.Color("\n[bold][red]Warning:[reset][bold] This state was retrieved using a different version of OpenTofu, the differences presented here maybe missing representations of recent features."),
+			renderer.Streams.Stdout.Columns()))
+	}
+
+	var rendered []string
+	for _, d := range diff.resources() {
+		action := d.action()
+		if action == plans.NoOp {
+			continue
+		}
+		resource := d.resource()
+		schema := diff.getSchema(resource)
+
+		var buf bytes.Buffer
+		buf.WriteString(renderer.Colorize.Color(stateDiffComment(d, action)))
+		buf.WriteString("\n")
+
+		mode := "resource"
+		if resource.Mode != jsonstate.ManagedResourceMode {
+			mode = "data"
+		}
+		change := differ.ComputeDiffForBlock(structured.FromJsonResources(d.before, d.after), schema.Block)
+		buf.WriteString(fmt.Sprintf("%s %s %q %q %s", renderer.Colorize.Color(format.DiffActionSymbol(action)), mode, resource.Type, resource.Name, change.RenderHuman(0, computed.NewRenderHumanOpts(renderer.Colorize))))
+		rendered = append(rendered, buf.String())
+	}
+
+	outputs := renderHumanDiffOutputs(renderer, diff.outputs())
+
+	if len(rendered) == 0 && len(outputs) == 0 {
+		renderer.Streams.Println("No differences were found between the two states.")
+		return
+	}
+
+	for _, r := range rendered {
+		renderer.Streams.Println()
+		renderer.Streams.Println(r)
+	}
+
+	if len(outputs) > 0 {
+		renderer.Streams.Print("\nChanges to Outputs:\n")
+		renderer.Streams.Printf("%s\n", outputs)
+	}
+}
+
+func stateDiffComment(d stateDiffResource, action plans.Action) string {
+	resource := d.resource()
+	dispAddr := resource.Address
+	if len(resource.DeposedKey) != 0 {
+		dispAddr = fmt.Sprintf("%s (deposed object %s)", dispAddr, resource.DeposedKey)
+	}
+
+	switch action {
+	case plans.Create:
+		return fmt.Sprintf("[bold]  # %s[reset] has been added", dispAddr)
+	case plans.Delete:
+		return fmt.Sprintf("[bold]  # %s[reset] has been removed", dispAddr)
+	default:
+		switch {
+		case d.taintedChanged() && d.after.Tainted:
+			return fmt.Sprintf("[bold]  # %s[reset] has been marked as tainted", dispAddr)
+		case d.taintedChanged():
+			return fmt.Sprintf("[bold]  # %s[reset] is no longer tainted", dispAddr)
+		default:
+			return fmt.Sprintf("[bold]  # %s[reset] has changed", dispAddr)
+		}
+	}
+}
+
+func stateDiffAction(action plans.Action) string {
+	switch action {
+	case plans.Create:
+		return "create"
+	case plans.Delete:
+		return "delete"
+	default:
+		return "update"
+	}
+}
+
+// walkChangedValues calls add with the path of each value that differs
+// between before and after, descending into objects, maps and lists of the
+// same length.
+func walkChangedValues(before, after interface{}, path []interface{}, add func([]interface{})) {
+	switch before := before.(type) {
+	case map[string]interface{}:
+		if after, ok := after.(map[string]interface{}); ok {
+			for _, key := range unionKeys(before, after) {
+				walkChangedValues(before[key], after[key], appendPath(path, key), add)
+			}
+			return
+		}
+	case []interface{}:
+		if after, ok := after.([]interface{}); ok && len(before) == len(after) {
+			for i := range before {
+				walkChangedValues(before[i], after[i], appendPath(path, i), add)
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(before, after) {
+		add(path)
+	}
+}
+
+// walkChangedSensitivity calls add with the path of each value that is
+// sensitive in only one of the given sensitive_values structures, in which
+// sensitive values are marked with true and all other leaves are omitted.
+func walkChangedSensitivity(before, after interface{}, path []interface{}, add func([]interface{})) {
+	if (before == true) != (after == true) {
+		add(path)
+		return
+	}
+
+	beforeMap, _ := before.(map[string]interface{})
+	afterMap, _ := after.(map[string]interface{})
+	for _, key := range unionKeys(beforeMap, afterMap) {
+		walkChangedSensitivity(beforeMap[key], afterMap[key], appendPath(path, key), add)
+	}
+
+	beforeList, _ := before.([]interface{})
+	afterList, _ := after.([]interface{})
+	for i := 0; i < len(beforeList) || i < len(afterList); i++ {
+		var b, a interface{}
+		if i < len(beforeList) {
+			b = beforeList[i]
+		}
+		if i < len(afterList) {
+			a = afterList[i]
+		}
+		walkChangedSensitivity(b, a, appendPath(path, i), add)
+	}
+}
+
+func unionKeys(before, after map[string]interface{}) []string {
+	var keys []string
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func appendPath(path []interface{}, step interface{}) []interface{} {
+	ret := make([]interface{}, len(path), len(path)+1)
+	copy(ret, path)
+	return append(ret, step)
+}
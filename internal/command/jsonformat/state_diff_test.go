@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonformat
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/command/jsonstate"
+)
+
+func TestStateDiffChangedPaths(t *testing.T) {
+	tests := map[string]struct {
+		Before, After                   string
+		BeforeSensitive, AfterSensitive string
+		Want                            [][]interface{}
+	}{
+		"unchanged": {
+			Before: `{"id":"a","tags":{"Name":"a"}}`,
+			After:  `{"id":"a","tags":{"Name":"a"}}`,
+			Want:   nil,
+		},
+		"nested map key": {
+			Before: `{"id":"a","tags":{"Name":"a","Env":"dev"}}`,
+			After:  `{"id":"a","tags":{"Name":"b","Env":"dev","Team":"x"}}`,
+			Want:   [][]interface{}{{"tags", "Name"}, {"tags", "Team"}},
+		},
+		"list element": {
+			Before: `{"list":["a","b"]}`,
+			After:  `{"list":["a","c"]}`,
+			Want:   [][]interface{}{{"list", 1}},
+		},
+		"list length": {
+			Before: `{"list":["a","b"]}`,
+			After:  `{"list":["a"]}`,
+			Want:   [][]interface{}{{"list"}},
+		},
+		"sensitivity only": {
+			Before:          `{"password":"a"}`,
+			After:           `{"password":"a"}`,
+			BeforeSensitive: `{}`,
+			AfterSensitive:  `{"password":true}`,
+			Want:            [][]interface{}{{"password"}},
+		},
+		"sensitive value": {
+			Before:          `{"password":"a"}`,
+			After:           `{"password":"b"}`,
+			BeforeSensitive: `{"password":true}`,
+			AfterSensitive:  `{"password":true}`,
+			Want:            [][]interface{}{{"password"}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resource := func(values, sensitive string) *jsonstate.Resource {
+				var attrs jsonstate.AttributeValues
+				if err := json.Unmarshal([]byte(values), &attrs); err != nil {
+					t.Fatal(err)
+				}
+				r := &jsonstate.Resource{
+					Address:         "test_resource.foo",
+					AttributeValues: attrs,
+				}
+				if sensitive != "" {
+					r.SensitiveValues = json.RawMessage(sensitive)
+				}
+				return r
+			}
+
+			d := stateDiffResource{
+				before: resource(test.Before, test.BeforeSensitive),
+				after:  resource(test.After, test.AfterSensitive),
+			}
+			if diff := cmp.Diff(test.Want, d.changedPaths()); diff != "" {
+				t.Errorf("wrong changed paths\n%s", diff)
+			}
+		})
+	}
+}
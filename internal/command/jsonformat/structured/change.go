@@ -165,6 +165,56 @@ func FromJsonViewsOutput(output viewsjson.Output) Change {
 	}
 }
 
+// FromJsonResources unmarshals the raw values of two snapshots of the same
+// resource instance in the jsonstate.Resource structs into generic
+// interface{} types that can be reasoned about. Either snapshot may be nil if
+// the resource instance only exists in one of them.
+func FromJsonResources(before, after *jsonstate.Resource) Change {
+	change := Change{
+		// We don't have any unknown values in state.
+		Unknown: false,
+
+		// We don't display replacement data for resources, and all attributes
+		// are relevant.
+		ReplacePaths:       attribute_path.Empty(false),
+		RelevantAttributes: attribute_path.AlwaysMatcher(),
+	}
+	if before != nil {
+		change.Before = unwrapAttributeValues(before.AttributeValues)
+		change.BeforeSensitive = unmarshalGeneric(before.SensitiveValues)
+	}
+	if after != nil {
+		change.After = unwrapAttributeValues(after.AttributeValues)
+		change.AfterSensitive = unmarshalGeneric(after.SensitiveValues)
+	}
+	return change
+}
+
+// FromJsonOutputs unmarshals the raw values of two snapshots of the same
+// output in the jsonstate.Output structs into generic interface{} types that
+// can be reasoned about. Either snapshot may be nil if the output only exists
+// in one of them.
+func FromJsonOutputs(before, after *jsonstate.Output) Change {
+	change := Change{
+		// We don't have any unknown values in state.
+		Unknown: false,
+
+		// We don't display replacement data for outputs, and all attributes
+		// are relevant.
+		ReplacePaths:       attribute_path.Empty(false),
+		RelevantAttributes: attribute_path.AlwaysMatcher(),
+	}
+	if before != nil {
+		change.Before = unmarshalGeneric(before.Value)
+		change.BeforeSensitive = before.Sensitive
+	}
+	if after != nil {
+		change.After = unmarshalGeneric(after.Value)
+		change.AfterSensitive = after.Sensitive
+	}
+	return change
+}
+
 // CalculateAction does a very simple analysis to make the best guess at the
 // action this change describes. For complex types such as objects, maps, lists,
 // or sets it is likely more efficient to work out the action directly instead
@@ -62,6 +62,7 @@ type Plan struct {
 	Config             json.RawMessage   `json:"configuration,omitempty"`
 	RelevantAttributes []ResourceAttr    `json:"relevant_attributes,omitempty"`
 	Checks             json.RawMessage   `json:"checks,omitempty"`
+	DeferredChanges    []DeferredChange  `json:"deferred_changes,omitempty"`
 	Timestamp          string            `json:"timestamp,omitempty"`
 	Errored            bool              `json:"errored"`
 }
@@ -79,6 +80,19 @@ type ResourceAttr struct {
 	Attr     json.RawMessage `json:"attribute"`
 }
 
+// DeferredChange is the representation of a resource or module call whose
+// changes were left out of the plan, to be planned in a later round.
+type DeferredChange struct {
+	// Address is the absolute address of the deferred resource, or of the
+	// deferred module call.
+	Address string `json:"address"`
+
+	// Reason is why the changes were deferred. Valid values are:
+	//    "instance_count_unknown"
+	//    "deferred_prereq"
+	Reason string `json:"reason"`
+}
+
 // Change is the representation of a proposed change for an object.
 type Change struct {
 	// Actions are the actions that will be taken on the object selected by the
@@ -90,6 +104,7 @@ type Change struct {
 	//    ["delete", "create"]
 	//    ["create", "delete"]
 	//    ["delete"]
+	//    ["forget"]
 	// The two "replace" actions are represented in this way to allow callers to
 	// e.g. just scan the list for "delete" to recognize all three situations
 	// where the object will be deleted, allowing for any new deletion
@@ -280,6 +295,9 @@ func MarshalForLog(
 		output.Checks = jsonchecks.MarshalCheckStates(p.Checks)
 	}
 
+	// output.DeferredChanges
+	output.DeferredChanges = MarshalDeferredChanges(p.DeferredChanges)
+
 	// output.PriorState
 	if sf != nil && !sf.State.Empty() {
 		output.PriorState, err = jsonstate.Marshal(sf, schemas)
@@ -316,6 +334,11 @@ func (p *Plan) marshalPlanVariables(vars map[string]plans.DynamicValue, decls ma
 	p.Variables = make(Variables, len(vars))
 
 	for k, v := range vars {
+		if decl, ok := decls[k]; ok && decl.Ephemeral {
+			// The values of ephemeral variables are never saved, so they
+			// don't appear in any representation of the plan.
+			continue
+		}
 		val, err := v.Decode(cty.DynamicPseudoType)
 		if err != nil {
 			return err
@@ -345,7 +368,7 @@ func (p *Plan) marshalPlanVariables(vars map[string]plans.DynamicValue, decls ma
 	// on our old behavior and so we'll fake it here just in time so that
 	// outside consumers won't see a behavior change.
 	for name, decl := range decls {
-		if _, ok := p.Variables[name]; ok {
+		if _, ok := p.Variables[name]; ok || decl.Ephemeral {
 			continue
 		}
 		if val := decl.Default; val != cty.NilVal {
@@ -558,6 +581,19 @@ func MarshalResourceChanges(resources []*plans.ResourceInstanceChangeSrc, schema
 	return ret, nil
 }
 
+// MarshalDeferredChanges converts the given deferred changes into their
+// structured JSON representation, returning nil if there are none.
+func MarshalDeferredChanges(deferred []plans.DeferredChange) []DeferredChange {
+	var ret []DeferredChange
+	for _, change := range deferred {
+		ret = append(ret, DeferredChange{
+			Address: change.Addr.String(),
+			Reason:  string(change.Reason),
+		})
+	}
+	return ret
+}
+
 // MarshalOutputChanges converts the provided internal representation of
 // Changes objects into the structured JSON representation.
 //
@@ -831,6 +867,8 @@ func actionString(action string) []string {
 		return []string{"create"}
 	case action == "Delete":
 		return []string{"delete"}
+	case action == "Forget":
+		return []string{"forget"}
 	case action == "Update":
 		return []string{"update"}
 	case action == "CreateThenDelete":
@@ -862,6 +900,8 @@ func UnmarshalActions(actions []string) plans.Action {
 			return plans.Create
 		case "delete":
 			return plans.Delete
+		case "forget":
+			return plans.Forget
 		case "update":
 			return plans.Update
 		case "read":
@@ -0,0 +1,192 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package junit produces JUnit XML reports of the results of "tofu test", for
+// use by CI systems that don't understand OpenTofu's own output formats.
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/command/format"
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// MarshalTestSuite returns a JUnit XML report of the given test suite, which
+// must have finished executing.
+//
+// Each test file becomes a testsuite and each run block becomes a testcase
+// within it. Error diagnostics from failed runs become failure elements, and
+// those from runs that errored become error elements. Errors that belong to a
+// test file rather than to one of its runs are reported as error elements of
+// an additional testcase named after the file.
+//
+// The given sources are used to include snippets of the configuration in the
+// diagnostic messages, and may be nil.
+func MarshalTestSuite(suite *moduletest.Suite, sources map[string][]byte) ([]byte, error) {
+	var names []string
+	for name := range suite.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names) // the files are executed in alphabetical order
+
+	report := testSuites{}
+	for _, name := range names {
+		report.Suites = append(report.Suites, newTestSuite(suite.Files[name], sources))
+	}
+
+	ret, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(ret, '\n')...), nil
+}
+
+type testSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []*testSuite `xml:"testsuite"`
+}
+
+type testSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Time      string      `xml:"time,attr"`
+	Cases     []*testCase `xml:"testcase"`
+	SystemErr *text       `xml:"system-err,omitempty"`
+}
+
+type testCase struct {
+	Name      string     `xml:"name,attr"`
+	Classname string     `xml:"classname,attr"`
+	Time      string     `xml:"time,attr"`
+	Skipped   *skipped   `xml:"skipped,omitempty"`
+	Failures  []*failure `xml:"failure,omitempty"`
+	Errors    []*failure `xml:"error,omitempty"`
+	SystemOut *text      `xml:"system-out,omitempty"`
+}
+
+type skipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+type failure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",cdata"`
+}
+
+type text struct {
+	Body string `xml:",cdata"`
+}
+
+func newTestSuite(file *moduletest.File, sources map[string][]byte) *testSuite {
+	ret := &testSuite{
+		Name:  file.Name,
+		Tests: len(file.Runs),
+	}
+
+	var total float64
+	for _, run := range file.Runs {
+		tc := &testCase{
+			Name:      run.Name,
+			Classname: file.Name,
+			Time:      formatSeconds(run.Duration.Seconds()),
+		}
+		total += run.Duration.Seconds()
+
+		switch run.Status {
+		case moduletest.Skip, moduletest.Pending:
+			ret.Skipped++
+			tc.Skipped = &skipped{
+				Message: skippedMessage(run, file),
+			}
+		case moduletest.Fail:
+			ret.Failures++
+			tc.Failures = newFailures(run.Diagnostics, "assertion", sources)
+		case moduletest.Error:
+			ret.Errors++
+			tc.Errors = newFailures(run.Diagnostics, "error", sources)
+		}
+
+		if warnings := formatDiagnostics(run.Diagnostics, tfdiags.Warning, sources); warnings != "" {
+			tc.SystemOut = &text{Body: warnings}
+		}
+
+		ret.Cases = append(ret.Cases, tc)
+	}
+	ret.Time = formatSeconds(total)
+
+	if errs := formatDiagnostics(file.Diagnostics, tfdiags.Error, sources); errs != "" {
+		ret.SystemErr = &text{Body: errs}
+
+		// JUnit consumers only count errors reported within a testcase, so
+		// the file's own errors need a testcase of their own.
+		ret.Tests++
+		ret.Errors++
+		ret.Cases = append(ret.Cases, &testCase{
+			Name:      file.Name,
+			Classname: file.Name,
+			Time:      formatSeconds(0),
+			Errors:    newFailures(file.Diagnostics, "error", sources),
+		})
+	}
+
+	return ret
+}
+
+func newFailures(diags tfdiags.Diagnostics, typ string, sources map[string][]byte) []*failure {
+	var ret []*failure
+	for _, diag := range diags {
+		if diag.Severity() != tfdiags.Error {
+			continue
+		}
+		ret = append(ret, &failure{
+			Message: diag.Description().Summary,
+			Type:    typ,
+			Body:    strings.TrimSpace(format.DiagnosticPlain(diag, sources, 0)),
+		})
+	}
+
+	if len(ret) == 0 {
+		// JUnit consumers only count a testcase as failed if it has a
+		// failure or error element, so we always need at least one.
+		ret = append(ret, &failure{
+			Message: "Test run failed",
+			Type:    typ,
+		})
+	}
+	return ret
+}
+
+func formatDiagnostics(diags tfdiags.Diagnostics, severity tfdiags.Severity, sources map[string][]byte) string {
+	var buf strings.Builder
+	for _, diag := range diags {
+		if diag.Severity() != severity {
+			continue
+		}
+		buf.WriteString(format.DiagnosticPlain(diag, sources, 0))
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+func skippedMessage(run *moduletest.Run, file *moduletest.File) string {
+	switch {
+	case run.Status == moduletest.Pending:
+		return "Testing was interrupted before this run block was executed"
+	case file.Status == moduletest.Error:
+		return "Testing halted after an earlier error in this file"
+	default:
+		return ""
+	}
+}
+
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package junit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+func TestMarshalTestSuite(t *testing.T) {
+	var failDiags tfdiags.Diagnostics
+	failDiags = failDiags.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Test assertion failed",
+		Detail:   "invalid value",
+		Subject: &hcl.Range{
+			Filename: "main.tftest.hcl",
+			Start:    hcl.Pos{Line: 3, Column: 17, Byte: 50},
+			End:      hcl.Pos{Line: 3, Column: 52, Byte: 85},
+		},
+	})
+	var warnDiags tfdiags.Diagnostics
+	warnDiags = warnDiags.Append(tfdiags.Sourceless(tfdiags.Warning, "Something odd", "Something odd happened."))
+	var errDiags tfdiags.Diagnostics
+	errDiags = errDiags.Append(tfdiags.Sourceless(tfdiags.Error, "Failed to plan", "Planning failed."))
+	var fileDiags tfdiags.Diagnostics
+	fileDiags = fileDiags.Append(tfdiags.Sourceless(tfdiags.Error, "Failed to clean up", "Destroy failed."))
+
+	suite := &moduletest.Suite{
+		Status: moduletest.Error,
+		Files: map[string]*moduletest.File{
+			"b.tftest.hcl": {
+				Name:        "b.tftest.hcl",
+				Status:      moduletest.Error,
+				Diagnostics: fileDiags,
+				Runs: []*moduletest.Run{
+					{
+						Name:        "errors",
+						Status:      moduletest.Error,
+						Duration:    250 * time.Millisecond,
+						Diagnostics: errDiags,
+					},
+					{
+						Name:   "skipped",
+						Status: moduletest.Skip,
+					},
+				},
+			},
+			"a.tftest.hcl": {
+				Name:   "a.tftest.hcl",
+				Status: moduletest.Fail,
+				Runs: []*moduletest.Run{
+					{
+						Name:        "passes",
+						Status:      moduletest.Pass,
+						Duration:    1500 * time.Millisecond,
+						Diagnostics: warnDiags,
+					},
+					{
+						Name:        "fails",
+						Status:      moduletest.Fail,
+						Duration:    2 * time.Second,
+						Diagnostics: failDiags,
+					},
+				},
+			},
+		},
+	}
+
+	got, err := MarshalTestSuite(suite, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="a.tftest.hcl" tests="2" skipped="0" failures="1" errors="0" time="3.500">
+    <testcase name="passes" classname="a.tftest.hcl" time="1.500">
+      <system-out><![CDATA[Warning: Something odd
+
+Something odd happened.]]></system-out>
+    </testcase>
+    <testcase name="fails" classname="a.tftest.hcl" time="2.000">
+      <failure message="Test assertion failed" type="assertion"><![CDATA[Error: Test assertion failed
+
+  on main.tftest.hcl line 3:
+  (source code not available)
+
+invalid value]]></failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="b.tftest.hcl" tests="3" skipped="1" failures="0" errors="2" time="0.250">
+    <testcase name="errors" classname="b.tftest.hcl" time="0.250">
+      <error message="Failed to plan" type="error"><![CDATA[Error: Failed to plan
+
+Planning failed.]]></error>
+    </testcase>
+    <testcase name="skipped" classname="b.tftest.hcl" time="0.000">
+      <skipped message="Testing halted after an earlier error in this file"></skipped>
+    </testcase>
+    <testcase name="b.tftest.hcl" classname="b.tftest.hcl" time="0.000">
+      <error message="Failed to clean up" type="error"><![CDATA[Error: Failed to clean up
+
+Destroy failed.]]></error>
+    </testcase>
+    <system-err><![CDATA[Error: Failed to clean up
+
+Destroy failed.]]></system-err>
+  </testsuite>
+</testsuites>
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
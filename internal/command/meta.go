@@ -26,12 +26,14 @@ import (
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/backend/local"
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/cliconfig"
 	"github.com/opentofu/opentofu/internal/command/format"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/command/webbrowser"
 	"github.com/opentofu/opentofu/internal/command/workdir"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/getproviders"
 	legacy "github.com/opentofu/opentofu/internal/legacy/tofu"
 	"github.com/opentofu/opentofu/internal/providers"
@@ -106,6 +108,10 @@ type Meta struct {
 	// into the given directory.
 	PluginCacheDir string
 
+	// ModuleCacheDir, if non-empty, enables caching of downloaded remote
+	// module packages into the given directory.
+	ModuleCacheDir string
+
 	// PluginCacheMayBreakDependencyLockFile is a temporary CLI configuration-based
 	// opt out for the behavior of only using the plugin cache dir if its
 	// contents match checksums recorded in the dependency lock file.
@@ -122,6 +128,11 @@ type Meta struct {
 	// longer any compelling reasons for folks to not lock their dependencies.
 	PluginCacheMayBreakDependencyLockFile bool
 
+	// ExternalHooks are the external programs configured in the "hooks"
+	// blocks of the CLI configuration, which plan and apply run at points
+	// in the lifecycle of the operation.
+	ExternalHooks []*cliconfig.ConfigHook
+
 	// ProviderSource allows determining the available versions of a provider
 	// and determines where a distribution package for a particular
 	// provider version can be obtained.
@@ -203,6 +214,21 @@ type Meta struct {
 	// backendState is the currently active backend state
 	backendState *legacy.BackendState
 
+	// encryption is the root module's encryption configuration, cached by
+	// the Encryption method.
+	encryption encryption.Encryption
+
+	// workspaces is the root module's workspaces settings, cached by the
+	// workspacesConfig method. A root module without a workspaces block
+	// leaves it nil, so workspacesLoaded records whether it was loaded.
+	workspaces       *configs.Workspaces
+	workspacesLoaded bool
+
+	// backendWorkspace, if set, is the workspace whose backend overrides are
+	// applied when configuring the backend, instead of those of the current
+	// workspace. It is set only by workspaceBackend.
+	backendWorkspace string
+
 	// Variables for the context (private)
 	variableArgs rawFlags
 	input        bool
@@ -475,7 +501,7 @@ func (m *Meta) RunOperation(b backend.Enhanced, opReq *backend.Operation) (*back
 		opReq.ConfigDir = m.normalizePath(opReq.ConfigDir)
 	}
 
-	op, err := b.Operation(context.Background(), opReq)
+	op, err := b.Operation(m.CommandContext(), opReq)
 	if err != nil {
 		return nil, fmt.Errorf("error starting operation: %w", err)
 	}
@@ -810,6 +836,19 @@ func (m *Meta) applyStateArguments(args *arguments.State) {
 	m.backupPath = args.BackupPath
 }
 
+// FIXME: as with applyStateArguments, we apply the variable arguments
+// directly to the Meta object, where they're used both to gather the values
+// for an operation and for static evaluation while loading configuration.
+func (m *Meta) applyVariableArgs(args *arguments.Vars) {
+	varArgs := args.All()
+	items := make([]rawFlag, len(varArgs))
+	for i := range varArgs {
+		items[i].Name = varArgs[i].Name
+		items[i].Value = varArgs[i].Value
+	}
+	m.variableArgs = rawFlags{items: &items}
+}
+
 // checkRequiredVersion loads the config and check if the
 // core version requirements are satisfied.
 func (m *Meta) checkRequiredVersion() tfdiags.Diagnostics {
@@ -827,7 +866,7 @@ func (m *Meta) checkRequiredVersion() tfdiags.Diagnostics {
 		return diags
 	}
 
-	config, configDiags := loader.LoadConfig(pwd)
+	config, configDiags := loader.LoadConfig(pwd, m.rootModuleCall())
 	if configDiags.HasErrors() {
 		diags = diags.Append(configDiags)
 		return diags
@@ -18,7 +18,6 @@ import (
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 
@@ -28,6 +27,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/clistate"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -35,6 +35,7 @@ import (
 
 	backendInit "github.com/opentofu/opentofu/internal/backend/init"
 	backendLocal "github.com/opentofu/opentofu/internal/backend/local"
+	"github.com/opentofu/opentofu/internal/encryption"
 	legacy "github.com/opentofu/opentofu/internal/legacy/tofu"
 )
 
@@ -97,6 +98,12 @@ func (m *Meta) Backend(opts *BackendOpts) (backend.Enhanced, tfdiags.Diagnostics
 		opts = &BackendOpts{}
 	}
 
+	enc, encDiags := m.Encryption()
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		return nil, diags
+	}
+
 	// Initialize a backend from the config unless we're forcing a purely
 	// local operation.
 	var b backend.Backend
@@ -179,7 +186,7 @@ func (m *Meta) Backend(opts *BackendOpts) (backend.Enhanced, tfdiags.Diagnostics
 	}
 
 	// Build the local backend
-	local := backendLocal.NewWithBackend(b)
+	local := backendLocal.NewWithBackend(b, enc.State())
 	if err := local.CLIInit(cliOpts); err != nil {
 		// Local backend isn't allowed to fail. It would be a bug.
 		panic(err)
@@ -305,12 +312,18 @@ func (m *Meta) selectWorkspace(b backend.Backend) error {
 func (m *Meta) BackendForLocalPlan(settings plans.Backend) (backend.Enhanced, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
+	enc, encDiags := m.Encryption()
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		return nil, diags
+	}
+
 	f := backendInit.Backend(settings.Type)
 	if f == nil {
 		diags = diags.Append(fmt.Errorf(strings.TrimSpace(errBackendSavedUnknown), settings.Type))
 		return nil, diags
 	}
-	b := f()
+	b := f(enc.State())
 	log.Printf("[TRACE] Meta.BackendForLocalPlan: instantiated backend of type %T", b)
 
 	schema := b.ConfigSchema()
@@ -369,7 +382,7 @@ func (m *Meta) BackendForLocalPlan(settings plans.Backend) (backend.Enhanced, tf
 		return nil, diags
 	}
 	cliOpts.Validation = false // don't validate here in case config contains file(...) calls where the file doesn't exist
-	local := backendLocal.NewWithBackend(b)
+	local := backendLocal.NewWithBackend(b, enc.State())
 	if err := local.CLIInit(cliOpts); err != nil {
 		// Local backend should never fail, so this is always a bug.
 		panic(err)
@@ -378,6 +391,25 @@ func (m *Meta) BackendForLocalPlan(settings plans.Backend) (backend.Enhanced, tf
 	return local, diags
 }
 
+// planOutBackend returns the backend settings to record in a plan created
+// in the given workspace, which include any overrides for the workspace so
+// that the plan is applied using the same backend configuration.
+func (m *Meta) planOutBackend(schema *configschema.Block, workspace string) (*plans.Backend, error) {
+	if m.backendState == nil {
+		return nil, nil
+	}
+
+	configVal, err := m.backendState.Config(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode backend config: %w", err)
+	}
+	configVal, diags := m.workspaceBackendConfig(configVal, schema, workspace)
+	if diags.HasErrors() {
+		return nil, diags.Err()
+	}
+	return plans.NewBackend(m.backendState.Type, configVal, schema, workspace)
+}
+
 // backendCLIOpts returns a backend.CLIOpts object that should be passed to
 // a backend that supports local CLI operations.
 func (m *Meta) backendCLIOpts() (*backend.CLIOpts, error) {
@@ -412,7 +444,7 @@ func (m *Meta) Operation(b backend.Backend, vt arguments.ViewType) *backend.Oper
 		// here first is a bug, so panic.
 		panic(fmt.Sprintf("invalid workspace: %s", err))
 	}
-	planOutBackend, err := m.backendState.ForPlan(schema, workspace)
+	planOutBackend, err := m.planOutBackend(schema, workspace)
 	if err != nil {
 		// Always indicates an implementation error in practice, because
 		// errors here indicate invalid encoding of the backend configuration
@@ -437,8 +469,17 @@ func (m *Meta) Operation(b backend.Backend, vt arguments.ViewType) *backend.Oper
 		log.Printf("[WARN] Failed to load dependency locks while preparing backend operation (ignored): %s", diags.Err().Error())
 	}
 
+	enc, encDiags := m.Encryption()
+	if encDiags.HasErrors() {
+		// The encryption configuration is always loaded while creating the
+		// backend, and the caller should have already exited if that failed.
+		// Seeing the error here first is a bug, so panic.
+		panic(fmt.Sprintf("invalid encryption configuration: %s", encDiags.Err()))
+	}
+
 	return &backend.Operation{
 		PlanOutBackend:  planOutBackend,
+		Encryption:      enc,
 		Targets:         m.targets,
 		UIIn:            m.UIInput(),
 		UIOut:           m.Ui,
@@ -491,7 +532,8 @@ func (m *Meta) backendConfig(opts *BackendOpts) (*configs.Backend, int, tfdiags.
 		})
 		return nil, 0, diags
 	}
-	b := bf()
+	// We only need the backend's schema here, so encryption is irrelevant.
+	b := bf(encryption.StateEncryptionDisabled())
 
 	configSchema := b.ConfigSchema()
 	configBody := c.Config
@@ -776,6 +818,11 @@ func (m *Meta) determineInitReason(previousBackendType string, currentBackendTyp
 // there is no backend state or no backend configured.
 func (m *Meta) backendFromState(ctx context.Context) (backend.Backend, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
+	enc, encDiags := m.Encryption()
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		return nil, diags
+	}
 	// Get the path to where we store a local cache of backend configuration
 	// if we're using a remote backend. This may not yet exist which means
 	// we haven't used a non-local backend before. That is okay.
@@ -789,25 +836,25 @@ func (m *Meta) backendFromState(ctx context.Context) (backend.Backend, tfdiags.D
 	if s == nil {
 		// no state, so return a local backend
 		log.Printf("[TRACE] Meta.Backend: backend has not previously been initialized in this working directory")
-		return backendLocal.New(), diags
+		return backendLocal.New(enc.State()), diags
 	}
 	if s.Backend == nil {
 		// s.Backend is nil, so return a local backend
 		log.Printf("[TRACE] Meta.Backend: working directory was previously initialized but has no backend (is using legacy remote state?)")
-		return backendLocal.New(), diags
+		return backendLocal.New(enc.State()), diags
 	}
 	log.Printf("[TRACE] Meta.Backend: working directory was previously initialized for %q backend", s.Backend.Type)
 
 	//backend init function
 	if s.Backend.Type == "" {
-		return backendLocal.New(), diags
+		return backendLocal.New(enc.State()), diags
 	}
 	f := backendInit.Backend(s.Backend.Type)
 	if f == nil {
 		diags = diags.Append(fmt.Errorf(strings.TrimSpace(errBackendSavedUnknown), s.Backend.Type))
 		return nil, diags
 	}
-	b := f()
+	b := f(enc.State())
 
 	// The configuration saved in the working directory state file is used
 	// in this case, since it will contain any additional values that
@@ -823,6 +870,19 @@ func (m *Meta) backendFromState(ctx context.Context) (backend.Backend, tfdiags.D
 		return nil, diags
 	}
 
+	// Any overrides for the current workspace are applied on top of the
+	// saved configuration.
+	workspace, err := m.backendOverridesWorkspace()
+	if err != nil {
+		diags = diags.Append(err)
+		return nil, diags
+	}
+	configVal, moreDiags := m.workspaceBackendConfig(configVal, schema, workspace)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+
 	// Validate the config and then configure the backend
 	newVal, validDiags := b.PrepareConfig(configVal)
 	diags = diags.Append(validDiags)
@@ -1255,6 +1315,11 @@ func (m *Meta) backend_C_r_S_changed(c *configs.Backend, cHash int, sMgr *clista
 // specifically.
 func (m *Meta) savedBackend(sMgr *clistate.LocalState) (backend.Backend, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
+	enc, encDiags := m.Encryption()
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		return nil, diags
+	}
 
 	s := sMgr.State()
 
@@ -1264,7 +1329,7 @@ func (m *Meta) savedBackend(sMgr *clistate.LocalState) (backend.Backend, tfdiags
 		diags = diags.Append(fmt.Errorf(strings.TrimSpace(errBackendSavedUnknown), s.Backend.Type))
 		return nil, diags
 	}
-	b := f()
+	b := f(enc.State())
 
 	// The configuration saved in the working directory state file is used
 	// in this case, since it will contain any additional values that
@@ -1280,6 +1345,19 @@ func (m *Meta) savedBackend(sMgr *clistate.LocalState) (backend.Backend, tfdiags
 		return nil, diags
 	}
 
+	// Any overrides for the current workspace are applied on top of the
+	// saved configuration.
+	workspace, err := m.backendOverridesWorkspace()
+	if err != nil {
+		diags = diags.Append(err)
+		return nil, diags
+	}
+	configVal, moreDiags := m.workspaceBackendConfig(configVal, schema, workspace)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+
 	// Validate the config and then configure the backend
 	newVal, validDiags := b.PrepareConfig(configVal)
 	diags = diags.Append(validDiags)
@@ -1352,11 +1430,11 @@ func (m *Meta) backendConfigNeedsMigration(c *configs.Backend, s *legacy.Backend
 		log.Printf("[TRACE] backendConfigNeedsMigration: no backend of type %q, which migration codepath must handle", c.Type)
 		return true // let the migration codepath deal with the missing backend
 	}
-	b := f()
+	// We only need the backend's schema here, so encryption is irrelevant.
+	b := f(encryption.StateEncryptionDisabled())
 
 	schema := b.ConfigSchema()
-	decSpec := schema.NoneRequired().DecoderSpec()
-	givenVal, diags := hcldec.Decode(c.Config, decSpec, nil)
+	givenVal, diags := c.Decode(schema.NoneRequired())
 	if diags.HasErrors() {
 		log.Printf("[TRACE] backendConfigNeedsMigration: failed to decode given config; migration codepath must handle problem: %s", diags.Error())
 		return true // let the migration codepath deal with these errors
@@ -1383,17 +1461,22 @@ func (m *Meta) backendConfigNeedsMigration(c *configs.Backend, s *legacy.Backend
 func (m *Meta) backendInitFromConfig(c *configs.Backend) (backend.Backend, cty.Value, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
+	enc, encDiags := m.Encryption()
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		return nil, cty.NilVal, diags
+	}
+
 	// Get the backend
 	f := backendInit.Backend(c.Type)
 	if f == nil {
 		diags = diags.Append(fmt.Errorf(strings.TrimSpace(errBackendNewUnknown), c.Type))
 		return nil, cty.NilVal, diags
 	}
-	b := f()
+	b := f(enc.State())
 
 	schema := b.ConfigSchema()
-	decSpec := schema.NoneRequired().DecoderSpec()
-	configVal, hclDiags := hcldec.Decode(c.Config, decSpec, nil)
+	configVal, hclDiags := c.Decode(schema.NoneRequired())
 	diags = diags.Append(hclDiags)
 	if hclDiags.HasErrors() {
 		return nil, cty.NilVal, diags
@@ -1423,7 +1506,21 @@ func (m *Meta) backendInitFromConfig(c *configs.Backend) (backend.Backend, cty.V
 		}
 	}
 
-	newVal, validateDiags := b.PrepareConfig(configVal)
+	// Any overrides for the current workspace are applied only to the
+	// configuration of the backend, and not to the configuration we return
+	// to be saved in the working directory.
+	workspace, err := m.backendOverridesWorkspace()
+	if err != nil {
+		diags = diags.Append(err)
+		return nil, cty.NilVal, diags
+	}
+	workspaceVal, moreDiags := m.workspaceBackendConfig(configVal, schema, workspace)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, cty.NilVal, diags
+	}
+
+	newVal, validateDiags := b.PrepareConfig(workspaceVal)
 	diags = diags.Append(validateDiags.InConfigBody(c.Config, ""))
 	if validateDiags.HasErrors() {
 		return nil, cty.NilVal, diags
@@ -48,7 +48,7 @@ func (m *Meta) loadConfig(rootDir string) (*configs.Config, tfdiags.Diagnostics)
 		return nil, diags
 	}
 
-	config, hclDiags := loader.LoadConfig(rootDir)
+	config, hclDiags := loader.LoadConfig(rootDir, m.rootModuleCall())
 	diags = diags.Append(hclDiags)
 	return config, diags
 }
@@ -65,7 +65,7 @@ func (m *Meta) loadConfigWithTests(rootDir, testDir string) (*configs.Config, tf
 		return nil, diags
 	}
 
-	config, hclDiags := loader.LoadConfigWithTests(rootDir, testDir)
+	config, hclDiags := loader.LoadConfigWithTests(rootDir, testDir, m.rootModuleCall())
 	diags = diags.Append(hclDiags)
 	return config, diags
 }
@@ -88,7 +88,7 @@ func (m *Meta) loadSingleModule(dir string) (*configs.Module, tfdiags.Diagnostic
 		return nil, diags
 	}
 
-	module, hclDiags := loader.Parser().LoadConfigDir(dir)
+	module, hclDiags := loader.Parser().LoadConfigDir(dir, m.rootModuleCall())
 	diags = diags.Append(hclDiags)
 	return module, diags
 }
@@ -105,7 +105,7 @@ func (m *Meta) loadSingleModuleWithTests(dir string, testDir string) (*configs.M
 		return nil, diags
 	}
 
-	module, hclDiags := loader.Parser().LoadConfigDirWithTests(dir, testDir)
+	module, hclDiags := loader.Parser().LoadConfigDirWithTests(dir, testDir, m.rootModuleCall())
 	diags = diags.Append(hclDiags)
 	return module, diags
 }
@@ -183,7 +183,7 @@ func (m *Meta) loadHCLFile(filename string) (hcl.Body, tfdiags.Diagnostics) {
 // can then be relayed to the end-user. The uiModuleInstallHooks type in
 // this package has a reasonable implementation for displaying notifications
 // via a provided cli.Ui.
-func (m *Meta) installModules(ctx context.Context, rootDir, testsDir string, upgrade, installErrsOnly bool, hooks initwd.ModuleInstallHooks) (abort bool, diags tfdiags.Diagnostics) {
+func (m *Meta) installModules(ctx context.Context, rootDir, testsDir string, upgrade, installErrsOnly, readonlyLocks bool, hooks initwd.ModuleInstallHooks) (abort bool, diags tfdiags.Diagnostics) {
 	ctx, span := tracer.Start(ctx, "install modules")
 	defer span.End()
 
@@ -201,9 +201,20 @@ func (m *Meta) installModules(ctx context.Context, rootDir, testsDir string, upg
 		return true, diags
 	}
 
+	previousLocks, moreDiags := m.lockedDependencies()
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return true, diags
+	}
+	locks := previousLocks.DeepCopy()
+
 	inst := initwd.NewModuleInstaller(m.modulesDir(), loader, m.registryClient())
+	if m.ModuleCacheDir != "" {
+		inst.SetModuleCacheDir(m.ModuleCacheDir)
+	}
+	inst.SetDependencyLocks(locks)
 
-	_, moreDiags := inst.InstallModules(ctx, rootDir, testsDir, upgrade, installErrsOnly, hooks)
+	_, moreDiags = inst.InstallModules(ctx, rootDir, testsDir, upgrade, installErrsOnly, hooks, m.rootModuleCall())
 	diags = diags.Append(moreDiags)
 
 	if ctx.Err() == context.Canceled {
@@ -212,6 +223,21 @@ func (m *Meta) installModules(ctx context.Context, rootDir, testsDir string, upg
 		return true, diags
 	}
 
+	// The installer records the digests of any module packages that didn't
+	// have one yet, which we must save so that later installations can
+	// verify them.
+	if !diags.HasErrors() && !locks.EqualModules(previousLocks) {
+		if readonlyLocks {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Module dependency changes detected",
+				`Changes to the module package digests were detected, but the lock file is read-only. To record these digests, run "tofu init" without the "-lockfile=readonly" flag.`,
+			))
+			return true, diags
+		}
+		diags = diags.Append(m.replaceLockedDependencies(locks))
+	}
+
 	return false, diags
 }
 
@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Encryption returns the encryption settings declared in the root module in
+// the current working directory, which apply to all state snapshots and plan
+// files that the current command reads and writes.
+//
+// The result is cached after the first successful call, so it's cheap to call
+// this from multiple places during a single command.
+func (m *Meta) Encryption() (encryption.Encryption, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if m.encryption != nil {
+		return m.encryption, diags
+	}
+
+	// A directory without any configuration files, which is valid for
+	// some commands, just produces an empty module here.
+	mod, moreDiags := m.loadSingleModule(".")
+	if moreDiags.HasErrors() {
+		// We only return errors here, because any warnings will be
+		// reported again by whatever else loads the configuration.
+		return nil, moreDiags
+	}
+
+	enc, encDiags := encryption.New(mod.Encryption)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		return nil, diags
+	}
+
+	// The remote and cloud backends store state in a service that must be
+	// able to read it, so they never encrypt state. Rather than silently
+	// storing plaintext, we reject configurations that ask for encryption.
+	if mod.Encryption != nil && mod.Encryption.State != nil {
+		backendType := ""
+		switch {
+		case mod.CloudConfig != nil:
+			backendType = "cloud"
+		case mod.Backend != nil && mod.Backend.Type == "remote":
+			backendType = "remote"
+		}
+		if backendType != "" {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "State encryption is not supported by this backend",
+				Detail:   fmt.Sprintf("The %s backend stores state in a service that must be able to read it, so OpenTofu cannot encrypt it. Remove the state block from the encryption configuration, or use a different backend.", backendType),
+				Subject:  mod.Encryption.State.DeclRange.Ptr(),
+			})
+			return nil, diags
+		}
+	}
+
+	m.encryption = enc
+	return enc, diags
+}
@@ -0,0 +1,24 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMetaEncryption_remoteBackend(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("encryption-remote-backend"), td)
+	defer testChdir(t, td)()
+
+	m := testMetaBackend(t, nil)
+	_, diags := m.Encryption()
+	if !diags.HasErrors() {
+		t.Fatal("unexpected success")
+	}
+	if got, want := diags.Err().Error(), "State encryption is not supported by this backend"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -46,5 +46,10 @@ func (m *Meta) PlanFile(path string) (*planfile.WrappedPlanFile, error) {
 		return nil, nil
 	}
 
-	return planfile.OpenWrapped(path)
+	enc, encDiags := m.Encryption()
+	if encDiags.HasErrors() {
+		return nil, encDiags.Err()
+	}
+
+	return planfile.OpenWrapped(path, enc.Plan())
 }
@@ -340,7 +340,11 @@ func (m *Meta) providerFactories() (map[addrs.Provider]providers.Factory, error)
 func (m *Meta) internalProviders() map[string]providers.Factory {
 	return map[string]providers.Factory{
 		"terraform": func() (providers.Interface, error) {
-			return terraformProvider.NewProvider(), nil
+			enc, diags := m.Encryption()
+			if diags.HasErrors() {
+				return nil, diags.Err()
+			}
+			return terraformProvider.NewProvider(enc.RemoteState()), nil
 		},
 	}
 }
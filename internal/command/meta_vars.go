@@ -11,7 +11,9 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/zclconf/go-cty/cty"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -22,6 +24,39 @@ import (
 // for root module input variables.
 const VarEnvPrefix = "TF_VAR_"
 
+// rootModuleCall returns the call of the root module for the static
+// evaluation that happens while loading configuration, such as of the backend
+// configuration and module source addresses. Its input variables take the
+// values given on the command line, in variable files and in the environment,
+// which are only collected once something refers to them.
+func (m *Meta) rootModuleCall() configs.StaticModuleCall {
+	var vars map[string]backend.UnparsedVariableValue
+	collected := false
+
+	return configs.NewStaticModuleCall(addrs.RootModule, func(v *configs.Variable) (cty.Value, hcl.Diagnostics) {
+		var diags hcl.Diagnostics
+		if !collected {
+			// Any problems collecting the values are reported only once,
+			// rather than for every variable that's needed.
+			var moreDiags tfdiags.Diagnostics
+			vars, moreDiags = m.collectVariableValues()
+			diags = append(diags, moreDiags.ToHCL()...)
+			collected = true
+		}
+
+		rv, ok := vars[v.Name]
+		if !ok {
+			return cty.NilVal, diags
+		}
+		val, moreDiags := rv.ParseVariableValue(v.ParsingMode)
+		diags = append(diags, moreDiags.ToHCL()...)
+		if moreDiags.HasErrors() {
+			return cty.NilVal, diags
+		}
+		return val.Value, diags
+	})
+}
+
 // collectVariableValues inspects the various places that root module input variable
 // values can come from and constructs a map ready to be passed to the
 // backend as part of a backend.Operation.
@@ -85,6 +120,18 @@ func (m *Meta) collectVariableValues() (map[string]backend.UnparsedVariableValue
 		}
 	}
 
+	// If the root module has a "workspaces" block then we also load the
+	// variable files named after the current workspace. Any problems with the
+	// block itself are reported when the whole configuration is loaded.
+	if ws, wsDiags := m.workspacesConfig(); !wsDiags.HasErrors() {
+		if workspace, err := m.Workspace(); err == nil {
+			for _, filename := range workspaceVarFiles(ws, workspace) {
+				moreDiags := m.addVarsFromFile(filename, tofu.ValueFromAutoFile, ret)
+				diags = diags.Append(moreDiags)
+			}
+		}
+	}
+
 	// Finally we process values given explicitly on the command line, either
 	// as individual literal settings or as additional files to read.
 	for _, rawFlag := range m.variableArgs.AllItems() {
@@ -0,0 +1,209 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// workspacesConfig returns the settings from the "workspaces" block of the
+// root module in the current working directory, or nil if there isn't one.
+//
+// Only the "workspaces" block is decoded, so this can be used before the
+// values of the root module's input variables are known. The settings are
+// cached after they are first loaded successfully.
+func (m *Meta) workspacesConfig() (*configs.Workspaces, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if m.workspacesLoaded {
+		return m.workspaces, diags
+	}
+
+	loader, err := m.initConfigLoader()
+	if err != nil {
+		diags = diags.Append(err)
+		return nil, diags
+	}
+
+	ws, hclDiags := loader.Parser().LoadWorkspacesConfig(m.normalizePath("."))
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return ws, diags
+	}
+
+	m.workspaces = ws
+	m.workspacesLoaded = true
+	return ws, diags
+}
+
+// workspaceBackend is like Backend, except that any backend overrides in the
+// settings of the given workspace are applied instead of those of the current
+// workspace.
+//
+// The workspace commands use this to act on the backend that the state of
+// the given workspace is actually stored in, which can differ from the
+// backend of the current workspace.
+func (m *Meta) workspaceBackend(opts *BackendOpts, workspace string) (backend.Enhanced, tfdiags.Diagnostics) {
+	prev := m.backendWorkspace
+	m.backendWorkspace = workspace
+	defer func() {
+		m.backendWorkspace = prev
+	}()
+	return m.Backend(opts)
+}
+
+// backendOverridesWorkspace returns the name of the workspace whose backend
+// overrides are applied when configuring the backend.
+func (m *Meta) backendOverridesWorkspace() (string, error) {
+	if m.backendWorkspace != "" {
+		return m.backendWorkspace, nil
+	}
+	return m.Workspace()
+}
+
+// listWorkspaces returns the names of all existing workspaces, given the
+// backend used for workspaces without backend overrides.
+//
+// Each workspace with backend overrides is stored in its own backend, and so
+// is listed only if it exists there.
+func (m *Meta) listWorkspaces(b backend.Backend, opts *BackendOpts) ([]string, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	names, err := b.Workspaces()
+	if err != nil {
+		diags = diags.Append(err)
+		return nil, diags
+	}
+
+	ws, moreDiags := m.workspacesConfig()
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() || ws == nil {
+		return names, diags
+	}
+
+	var ret []string
+	for _, name := range names {
+		if w, exists := ws.Workspaces[name]; !exists || w.Backend == nil {
+			ret = append(ret, name)
+		}
+	}
+	for name, w := range ws.Workspaces {
+		if w.Backend == nil {
+			continue
+		}
+		wb, moreDiags := m.workspaceBackend(opts, name)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			return nil, diags
+		}
+		wNames, err := wb.Workspaces()
+		if err != nil {
+			diags = diags.Append(fmt.Errorf("failed to list workspaces in the backend of workspace %q: %w", name, err))
+			return nil, diags
+		}
+		for _, wName := range wNames {
+			if wName == name {
+				ret = append(ret, name)
+				break
+			}
+		}
+	}
+
+	// Backends list the default workspace first, and then the others in
+	// lexical order.
+	sort.Slice(ret, func(i, j int) bool {
+		switch {
+		case ret[i] == backend.DefaultStateName:
+			return ret[j] != backend.DefaultStateName
+		case ret[j] == backend.DefaultStateName:
+			return false
+		default:
+			return ret[i] < ret[j]
+		}
+	})
+	return ret, diags
+}
+
+// workspaceVarFiles returns the paths of the variable files named after the
+// given workspace that exist in the variable file directory of the given
+// workspaces settings, in the order they should be loaded.
+//
+// Variable files are only loaded for workspaces when the root module has a
+// "workspaces" block, so this returns nil if ws is nil.
+func workspaceVarFiles(ws *configs.Workspaces, workspace string) []string {
+	if ws == nil {
+		return nil
+	}
+
+	var ret []string
+	for _, suffix := range []string{".tfvars", ".tfvars.json"} {
+		filename := filepath.Join(ws.VarFileDir, workspace+suffix)
+		if _, err := os.Stat(filename); err == nil {
+			ret = append(ret, filename)
+		}
+	}
+	return ret
+}
+
+// workspaceBackendConfig returns the given backend configuration with any
+// overrides from the "backend" block in the settings of the given workspace
+// applied.
+//
+// The overrides are applied only when configuring a backend and are never
+// saved in the working directory, so selecting a different workspace doesn't
+// change the backend configuration hash and require reinitialization.
+func (m *Meta) workspaceBackendConfig(configVal cty.Value, schema *configschema.Block, workspace string) (cty.Value, tfdiags.Diagnostics) {
+	ws, diags := m.workspacesConfig()
+	if diags.HasErrors() || ws == nil {
+		return configVal, diags
+	}
+	w, exists := ws.Workspaces[workspace]
+	if !exists || w.Backend == nil {
+		return configVal, diags
+	}
+
+	overrideVal, hclDiags := hcldec.Decode(w.Backend.Config, schema.NoneRequired().DecoderSpec(), nil)
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return configVal, diags
+	}
+	log.Printf("[TRACE] Meta.workspaceBackendConfig: applying backend overrides for workspace %q", workspace)
+
+	vals := make(map[string]cty.Value)
+	if !configVal.IsNull() {
+		vals = configVal.AsValueMap()
+	}
+	for name := range schema.Attributes {
+		if v := overrideVal.GetAttr(name); !v.IsNull() {
+			vals[name] = v
+		}
+	}
+	for name := range schema.BlockTypes {
+		if v := overrideVal.GetAttr(name); !v.IsNull() && (!v.CanIterateElements() || v.LengthInt() != 0) {
+			vals[name] = v
+		}
+	}
+	for name, attrS := range schema.Attributes {
+		if _, exists := vals[name]; !exists {
+			vals[name] = cty.NullVal(attrS.ImpliedType())
+		}
+	}
+	for name, blockS := range schema.BlockTypes {
+		if _, exists := vals[name]; !exists {
+			vals[name] = cty.NullVal(blockS.ImpliedType())
+		}
+	}
+	return cty.ObjectVal(vals), diags
+}
@@ -6,10 +6,14 @@ package command
 import (
 	"fmt"
 
+	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/function"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/jsonfunction"
 	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
 var (
@@ -59,20 +63,82 @@ func (c *MetadataFunctionsCommand) Run(args []string) int {
 		filteredFuncs[k] = v
 	}
 
+	providerFuncs, diags := c.providerFunctions()
+	for k, v := range providerFuncs {
+		filteredFuncs[k] = v
+	}
+
 	jsonFunctions, marshalDiags := jsonfunction.Marshal(filteredFuncs)
-	if marshalDiags.HasErrors() {
-		c.showDiagnostics(marshalDiags)
+	diags = diags.Append(marshalDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
 		return 1
 	}
+	c.showDiagnostics(diags)
 	c.Ui.Output(string(jsonFunctions))
 
 	return 0
 }
 
+// providerFunctions returns the functions exported by the providers installed
+// in the current working directory, named as they would be in a module that
+// uses each provider's default local name.
+//
+// The returned functions can only be used to describe their signatures.
+func (c *MetadataFunctionsCommand) providerFunctions() (map[string]function.Function, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	var factories map[addrs.Provider]providers.Factory
+	if c.testingOverrides != nil {
+		factories = c.testingOverrides.Providers
+	} else {
+		var err error
+		factories, err = c.providerFactories()
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Some provider functions are unavailable",
+				fmt.Sprintf("Functions are listed only for the providers that are installed in the working directory: %s.", err),
+			))
+		}
+	}
+
+	ret := make(map[string]function.Function)
+	for addr, factory := range factories {
+		provider, err := factory()
+		if err != nil {
+			// The error for an unavailable provider was already reported
+			// above, as a result of providerFactories.
+			continue
+		}
+
+		resp := provider.GetFunctions()
+		provider.Close()
+		if resp.Diagnostics.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Failed to read provider functions",
+				fmt.Sprintf("Could not list the functions exported by provider %s: %s.", addr, resp.Diagnostics.Err()),
+			))
+			continue
+		}
+
+		for name, spec := range resp.Functions {
+			ret[lang.ProviderFunctionName(addr.Type, name)] = spec.AsFunction(func([]cty.Value) (cty.Value, error) {
+				return cty.NilVal, fmt.Errorf("provider functions cannot be called while listing their signatures")
+			})
+		}
+	}
+
+	return ret, diags
+}
+
 const metadataFunctionsCommandHelp = `
 Usage: tofu [global options] metadata functions -json
 
-  Prints out a json representation of the available function signatures.
+  Prints out a json representation of the available function signatures,
+  including those of the functions exported by the providers installed in
+  the current working directory.
 `
 
 func isIgnoredFunction(name string) bool {
@@ -8,6 +8,9 @@ import (
 	"testing"
 
 	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/providers"
 )
 
 func TestMetadataFunctions_error(t *testing.T) {
@@ -68,6 +71,56 @@ func TestMetadataFunctions_output(t *testing.T) {
 	}
 }
 
+func TestMetadataFunctions_providerFunctions(t *testing.T) {
+	p := testProvider()
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		Functions: map[string]providers.FunctionSpec{
+			"echo": {
+				Parameters: []providers.FunctionParameterSpec{
+					{
+						Name:        "input",
+						Type:        cty.String,
+						Description: "The value to return.",
+					},
+				},
+				Return:      cty.String,
+				Description: "Returns its argument.",
+			},
+		},
+	}
+
+	ui := new(cli.MockUi)
+	c := &MetadataFunctionsCommand{
+		Meta: Meta{
+			Ui:               ui,
+			testingOverrides: metaOverridesForProvider(p),
+		},
+	}
+
+	if code := c.Run([]string{"-json"}); code != 0 {
+		t.Fatalf("wrong exit status %d; want 0\nstderr: %s", code, ui.ErrorWriter.String())
+	}
+
+	var got functions
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	gotEcho, ok := got.Signatures["provider::test::echo"]
+	if !ok {
+		t.Fatal(`missing function signature for "provider::test::echo"`)
+	}
+	wantEcho := `{"description":"Returns its argument.","return_type":"string","parameters":[{"name":"input","description":"The value to return.","type":"string"}]}`
+	if string(gotEcho) != wantEcho {
+		t.Fatalf("wrong function signature for \"provider::test::echo\":\ngot: %q\nwant: %q", gotEcho, wantEcho)
+	}
+
+	// built-in functions must still be present
+	if _, ok := got.Signatures["max"]; !ok {
+		t.Fatal(`missing function signature for "max"`)
+	}
+}
+
 type functions struct {
 	FormatVersion string                     `json:"format_version"`
 	Signatures    map[string]json.RawMessage `json:"function_signatures,omitempty"`
@@ -9,8 +9,10 @@ import (
 
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/cliconfig"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
 )
 
 // PlanCommand is a Command implementation that compares a OpenTofu
@@ -66,6 +68,10 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 
 	diags = diags.Append(c.providerDevOverrideRuntimeWarnings())
 
+	// The variables are needed while loading the configuration, to
+	// statically evaluate the backend configuration and module sources.
+	c.Meta.applyVariableArgs(args.Vars)
+
 	// Prepare the backend with the backend-specific arguments
 	be, beDiags := c.PrepareBackend(args.State, args.ViewType)
 	diags = diags.Append(beDiags)
@@ -75,7 +81,7 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 	}
 
 	// Build the operation request
-	opReq, opDiags := c.OperationRequest(be, view, args.ViewType, args.Operation, args.OutPath, args.GenerateConfigPath)
+	opReq, opDiags := c.OperationRequest(be, view, args.ViewType, args.Operation, args.OutPath, args.GenerateConfigPath, args.PolicyDir)
 	diags = diags.Append(opDiags)
 	if diags.HasErrors() {
 		view.Diagnostics(diags)
@@ -95,14 +101,30 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 	view.Diagnostics(diags)
 	diags = nil
 
+	// Run the external hooks from the CLI configuration, if any. A failing
+	// pre_plan hook prevents the operation from starting.
+	hooks := c.externalHooks()
+	if hooks != nil {
+		opReq.Hooks = append([]tofu.Hook{hooks}, opReq.Hooks...)
+	}
+	if diags := hooks.RunPre(cliconfig.HookEventPrePlan); diags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
 	// Perform the operation
 	op, err := c.RunOperation(be, opReq)
 	if err != nil {
 		diags = diags.Append(err)
+		diags = diags.Append(hooks.RunPost(cliconfig.HookEventPostPlan, backend.OperationFailure))
 		view.Diagnostics(diags)
 		return 1
 	}
 
+	if diags := hooks.RunPost(cliconfig.HookEventPostPlan, op.Result); len(diags) > 0 {
+		view.Diagnostics(diags)
+	}
+
 	if op.Result != backend.OperationSuccess {
 		return op.Result.ExitStatus()
 	}
@@ -145,6 +167,7 @@ func (c *PlanCommand) OperationRequest(
 	args *arguments.Operation,
 	planOutPath string,
 	generateConfigOut string,
+	policyDir string,
 ) (*backend.Operation, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
@@ -156,8 +179,11 @@ func (c *PlanCommand) OperationRequest(
 	opReq.PlanRefresh = args.Refresh
 	opReq.PlanOutPath = planOutPath
 	opReq.GenerateConfigOut = generateConfigOut
+	opReq.PolicyDir = policyDir
 	opReq.Targets = args.Targets
+	opReq.Excludes = args.Excludes
 	opReq.ForceReplace = args.ForceReplace
+	opReq.AllowDeferral = args.AllowDeferral
 	opReq.Type = backend.OperationTypePlan
 	opReq.View = view.Operation()
 
@@ -180,14 +206,7 @@ func (c *PlanCommand) GatherVariables(opReq *backend.Operation, args *arguments.
 	// Once all commands that gather variables have been converted to this
 	// structure, we could move the variable gathering code to the arguments
 	// package directly, removing this shim layer.
-
-	varArgs := args.All()
-	items := make([]rawFlag, len(varArgs))
-	for i := range varArgs {
-		items[i].Name = varArgs[i].Name
-		items[i].Value = varArgs[i].Value
-	}
-	c.Meta.variableArgs = rawFlags{items: &items}
+	c.Meta.applyVariableArgs(args)
 	opReq.Variables, diags = c.collectVariableValues()
 
 	return diags
@@ -210,10 +229,22 @@ Plan Customization Options:
   can also use these options when you run "tofu apply" without passing
   it a saved plan, in order to plan and apply in a single command.
 
+  -allow-deferral     Defer the changes for resources and modules whose count
+                      or for_each arguments depend on values that won't be
+                      known until apply, along with everything that depends
+                      on them, instead of failing. Run plan again after
+                      applying to plan the deferred changes.
+
   -destroy            Select the "destroy" planning mode, which creates a plan
                       to destroy all objects currently managed by this
                       OpenTofu configuration instead of the usual behavior.
 
+  -exclude=resource   Exclude the given module, resource, or resource instance
+                      and everything that depends on it from the planning
+                      operation. You can use this option multiple times to
+                      exclude more than one object. This is for exceptional
+                      use only, and cannot be combined with -target.
+
   -refresh-only       Select the "refresh only" planning mode, which checks
                       whether remote objects still match the outcome of the
                       most recent OpenTofu apply but does not propose any
@@ -280,6 +311,11 @@ Other Options:
   -parallelism=n             Limit the number of concurrent operations. Defaults
                              to 10.
 
+  -policy-dir=path           Check the planned changes against the policies in
+                             the policy files in the given directory. A plan
+                             that violates a mandatory policy cannot be
+                             applied.
+
   -state=statefile           A legacy option used for the local backend only.
                              See the local backend's documentation for more
                              information.
@@ -22,6 +22,7 @@ import (
 	backendinit "github.com/opentofu/opentofu/internal/backend/init"
 	"github.com/opentofu/opentofu/internal/checks"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
@@ -477,7 +478,7 @@ func TestPlan_outBackend(t *testing.T) {
 		t.Errorf("wrong backend workspace %q; want %q", got, want)
 	}
 	{
-		httpBackend := backendinit.Backend("http")()
+		httpBackend := backendinit.Backend("http")(encryption.StateEncryptionDisabled())
 		schema := httpBackend.ConfigSchema()
 		got, err := plan.Backend.Config.Decode(schema.ImpliedType())
 		if err != nil {
@@ -1181,6 +1182,36 @@ func TestPlan_detailedExitcode_emptyDiff(t *testing.T) {
 	}
 }
 
+func TestPlan_detailedExitcode_deferred(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-deferred"), td)
+	defer testChdir(t, td)()
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{"-allow-deferral", "-detailed-exitcode"}
+	code := c.Run(args)
+	output := done(t)
+	if code != 2 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	got := output.Stdout()
+	if want := "test_instance.foo, because its count or for_each value is not known yet"; !strings.Contains(got, want) {
+		t.Errorf("missing deferred change\nwant output containing: %s\ngot:\n%s", want, got)
+	}
+	if strings.Contains(got, "No changes.") {
+		t.Errorf("plan with deferred changes reported as having no changes:\n%s", got)
+	}
+}
+
 func TestPlan_shutdown(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
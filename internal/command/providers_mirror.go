@@ -179,68 +179,11 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 				))
 				continue
 			}
-			urlStr, ok := meta.Location.(getproviders.PackageHTTPURL)
-			if !ok {
-				// We don't expect to get non-HTTP locations here because we're
-				// using the registry source, so this seems like a bug in the
-				// registry source.
-				diags = diags.Append(tfdiags.Sourceless(
-					tfdiags.Error,
-					"Provider release not available",
-					fmt.Sprintf("Failed to download %s v%s for %s: OpenTofu's provider registry client returned unexpected location type %T. This is a bug in OpenTofu.", provider.String(), selected.String(), platform.String(), meta.Location),
-				))
-				continue
-			}
-			urlObj, err := url.Parse(string(urlStr))
-			if err != nil {
-				// We don't expect to get non-HTTP locations here because we're
-				// using the registry source, so this seems like a bug in the
-				// registry source.
-				diags = diags.Append(tfdiags.Sourceless(
-					tfdiags.Error,
-					"Invalid URL for provider release",
-					fmt.Sprintf("The origin registry for %s returned an invalid URL for v%s on %s: %s.", provider.String(), selected.String(), platform.String(), err),
-				))
-				continue
-			}
-			// targetPath is the path where we ultimately want to place the
-			// downloaded archive, but we'll place it initially at stagingPath
-			// so we can verify its checksums and signatures before making
-			// it discoverable to mirror clients. (stagingPath intentionally
-			// does not follow the filesystem mirror file naming convention.)
-			targetPath := meta.PackedFilePath(outputDir)
-			stagingPath := filepath.Join(filepath.Dir(targetPath), "."+filepath.Base(targetPath))
-			err = httpGetter.GetFile(stagingPath, urlObj)
-			if err != nil {
-				diags = diags.Append(tfdiags.Sourceless(
-					tfdiags.Error,
-					"Cannot download provider release",
-					fmt.Sprintf("Failed to download %s v%s for %s: %s.", provider.String(), selected.String(), platform.String(), err),
-				))
-				continue
-			}
-			if meta.Authentication != nil {
-				result, err := meta.Authentication.AuthenticatePackage(getproviders.PackageLocalArchive(stagingPath))
-				if err != nil {
-					diags = diags.Append(tfdiags.Sourceless(
-						tfdiags.Error,
-						"Invalid provider package",
-						fmt.Sprintf("Failed to authenticate %s v%s for %s: %s.", provider.String(), selected.String(), platform.String(), err),
-					))
-					continue
-				}
+			result, moreDiags := downloadMirrorPackage(httpGetter, meta, outputDir)
+			diags = diags.Append(moreDiags)
+			if result != nil {
 				c.Ui.Output(fmt.Sprintf("  - Package authenticated: %s", result))
 			}
-			os.Remove(targetPath) // okay if it fails because we're going to try to rename over it next anyway
-			err = os.Rename(stagingPath, targetPath)
-			if err != nil {
-				diags = diags.Append(tfdiags.Sourceless(
-					tfdiags.Error,
-					"Cannot download provider release",
-					fmt.Sprintf("Failed to place %s package into mirror directory: %s.", provider.String(), err),
-				))
-				continue
-			}
 		}
 	}
 
@@ -351,6 +294,83 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 	return 0
 }
 
+// downloadMirrorPackage downloads the package described by the given metadata
+// into its location in the packed layout under outputDir, verifying its
+// checksums and signatures first if the source provided any.
+//
+// The result is the package authentication result, or nil if the source
+// did not provide any authentication for the package or if the download
+// failed.
+func downloadMirrorPackage(httpGetter getter.HttpGetter, meta getproviders.PackageMeta, outputDir string) (*getproviders.PackageAuthenticationResult, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	provider, selected, platform := meta.Provider, meta.Version, meta.TargetPlatform
+	var authResult *getproviders.PackageAuthenticationResult
+
+	urlStr, ok := meta.Location.(getproviders.PackageHTTPURL)
+	if !ok {
+		// We don't expect to get non-HTTP locations here because we're
+		// using the registry source, so this seems like a bug in the
+		// registry source.
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Provider release not available",
+			fmt.Sprintf("Failed to download %s v%s for %s: OpenTofu's provider registry client returned unexpected location type %T. This is a bug in OpenTofu.", provider.String(), selected.String(), platform.String(), meta.Location),
+		))
+		return nil, diags
+	}
+	urlObj, err := url.Parse(string(urlStr))
+	if err != nil {
+		// We don't expect to get non-HTTP locations here because we're
+		// using the registry source, so this seems like a bug in the
+		// registry source.
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid URL for provider release",
+			fmt.Sprintf("The origin registry for %s returned an invalid URL for v%s on %s: %s.", provider.String(), selected.String(), platform.String(), err),
+		))
+		return nil, diags
+	}
+	// targetPath is the path where we ultimately want to place the
+	// downloaded archive, but we'll place it initially at stagingPath
+	// so we can verify its checksums and signatures before making
+	// it discoverable to mirror clients. (stagingPath intentionally
+	// does not follow the filesystem mirror file naming convention.)
+	targetPath := meta.PackedFilePath(outputDir)
+	stagingPath := filepath.Join(filepath.Dir(targetPath), "."+filepath.Base(targetPath))
+	err = httpGetter.GetFile(stagingPath, urlObj)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot download provider release",
+			fmt.Sprintf("Failed to download %s v%s for %s: %s.", provider.String(), selected.String(), platform.String(), err),
+		))
+		return nil, diags
+	}
+	if meta.Authentication != nil {
+		result, err := meta.Authentication.AuthenticatePackage(getproviders.PackageLocalArchive(stagingPath))
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid provider package",
+				fmt.Sprintf("Failed to authenticate %s v%s for %s: %s.", provider.String(), selected.String(), platform.String(), err),
+			))
+			return nil, diags
+		}
+		authResult = result
+	}
+	os.Remove(targetPath) // okay if it fails because we're going to try to rename over it next anyway
+	err = os.Rename(stagingPath, targetPath)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot download provider release",
+			fmt.Sprintf("Failed to place %s package into mirror directory: %s.", provider.String(), err),
+		))
+		return nil, diags
+	}
+	return authResult, diags
+}
+
 func (c *ProvidersMirrorCommand) Help() string {
 	return `
 Usage: tofu [global options] providers mirror [options] <target-dir>
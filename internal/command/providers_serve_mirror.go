@@ -0,0 +1,592 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-getter"
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/httpclient"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ProvidersServeMirrorCommand is a Command implementation that implements the
+// "tofu providers serve-mirror" command, which serves a directory in the
+// layout produced by "tofu providers mirror" over the provider network
+// mirror protocol.
+type ProvidersServeMirrorCommand struct {
+	Meta
+}
+
+func (c *ProvidersServeMirrorCommand) Synopsis() string {
+	return "Serve a provider mirror directory as a network mirror"
+}
+
+func (c *ProvidersServeMirrorCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("providers serve-mirror")
+	var optAddress, optTLSCert, optTLSKey, optTokenFile string
+	var optUpstream bool
+	var optPlatforms, optUpstreamHosts FlagStringSlice
+	cmdFlags.StringVar(&optAddress, "address", "127.0.0.1:8080", "listen address")
+	cmdFlags.StringVar(&optTLSCert, "tls-cert", "", "TLS certificate file")
+	cmdFlags.StringVar(&optTLSKey, "tls-key", "", "TLS private key file")
+	cmdFlags.StringVar(&optTokenFile, "token-file", "", "bearer tokens file")
+	cmdFlags.BoolVar(&optUpstream, "upstream", false, "populate from origin registries")
+	cmdFlags.Var(&optPlatforms, "platform", "target platform")
+	cmdFlags.Var(&optUpstreamHosts, "upstream-host", "allowed origin registry hostname")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No mirror directory specified",
+			"The providers serve-mirror command requires a mirror directory as a command-line argument.",
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+	mirrorDir := args[0]
+
+	if (optTLSCert == "") != (optTLSKey == "") {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incomplete TLS configuration",
+			"The -tls-cert and -tls-key options must be used together.",
+		))
+	}
+
+	var tokens []string
+	if optTokenFile != "" {
+		var err error
+		tokens, err = readMirrorTokens(optTokenFile)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read tokens file",
+				fmt.Sprintf("Could not read the bearer tokens from %s: %s.", optTokenFile, err),
+			))
+		}
+	}
+
+	var platforms []getproviders.Platform
+	if len(optPlatforms) == 0 {
+		platforms = []getproviders.Platform{getproviders.CurrentPlatform}
+	} else {
+		platforms = make([]getproviders.Platform, 0, len(optPlatforms))
+		for _, platformStr := range optPlatforms {
+			platform, err := getproviders.ParsePlatform(platformStr)
+			if err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid target platform",
+					fmt.Sprintf("The string %q given in the -platform option is not a valid target platform: %s.", platformStr, err),
+				))
+				continue
+			}
+			platforms = append(platforms, platform)
+		}
+	}
+
+	// Only the allowed origin registries are contacted on behalf of clients,
+	// so that the server can't be used to make requests to arbitrary hosts
+	// or to fill the mirror directory with arbitrary packages.
+	upstreamHosts := map[svchost.Hostname]bool{}
+	if len(optUpstreamHosts) == 0 {
+		upstreamHosts[addrs.DefaultProviderRegistryHost] = true
+	}
+	for _, hostStr := range optUpstreamHosts {
+		host, err := svchost.ForComparison(hostStr)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid upstream registry hostname",
+				fmt.Sprintf("The string %q given in the -upstream-host option is not a valid hostname: %s.", hostStr, err),
+			))
+			continue
+		}
+		upstreamHosts[host] = true
+	}
+
+	if optUpstream {
+		// The mirror starts out empty when it's populated on demand.
+		if err := os.MkdirAll(mirrorDir, 0755); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to create mirror directory",
+				fmt.Sprintf("Could not create the mirror directory %s: %s.", mirrorDir, err),
+			))
+		}
+	} else if info, err := os.Stat(mirrorDir); err != nil || !info.IsDir() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid mirror directory",
+			fmt.Sprintf("The mirror directory %s does not exist or is not a directory.", mirrorDir),
+		))
+	}
+
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	server := &providerMirrorServer{
+		dir:    mirrorDir,
+		tokens: tokens,
+	}
+	if optUpstream {
+		// As with "tofu providers mirror", we always consult the origin
+		// registry for each provider, regardless of the installation methods
+		// in the CLI configuration.
+		server.upstream = getproviders.NewMemoizeSource(
+			getproviders.NewRegistrySource(c.Services),
+		)
+		server.upstreamHosts = upstreamHosts
+		server.platforms = platforms
+		server.httpGetter = getter.HttpGetter{
+			Client:                httpclient.New(),
+			Netrc:                 true,
+			XTerraformGetDisabled: true,
+		}
+	}
+
+	listener, err := net.Listen("tcp", optAddress)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to start mirror server",
+			fmt.Sprintf("Could not listen on %s: %s.", optAddress, err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	httpServer := &http.Server{
+		Handler:           server,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+
+	// The server runs until it's interrupted by SIGINT and similar.
+	ctx, done := c.InterruptibleContext(c.CommandContext())
+	defer done()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if optTLSCert != "" {
+			serveErr <- httpServer.ServeTLS(listener, optTLSCert, optTLSKey)
+		} else {
+			serveErr <- httpServer.Serve(listener)
+		}
+	}()
+
+	scheme := "https"
+	if optTLSCert == "" {
+		// OpenTofu only accepts network mirrors with https URLs, so a
+		// mirror served without TLS must be behind a TLS-terminating proxy.
+		scheme = "http"
+		c.Ui.Warn("Serving without TLS. OpenTofu only installs providers from network mirrors over HTTPS, so clients must reach this mirror through a proxy that terminates TLS.\n")
+	}
+	c.Ui.Output(fmt.Sprintf("Serving provider network mirror from %s at %s://%s/", mirrorDir, scheme, listener.Addr()))
+
+	select {
+	case err := <-serveErr:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Mirror server failed",
+			fmt.Sprintf("The provider mirror server stopped unexpectedly: %s.", err),
+		))
+	case <-ctx.Done():
+		c.Ui.Output("Shutting down provider network mirror...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("[WARN] Failed to shut down the provider mirror server cleanly: %s", err)
+		}
+	}
+
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+	return 0
+}
+
+func (c *ProvidersServeMirrorCommand) Help() string {
+	return `
+Usage: tofu [global options] providers serve-mirror [options] <mirror-dir>
+
+  Serves a directory of provider packages over the provider network mirror
+  protocol, so that it can be used by the "network_mirror" installation
+  method in the CLI configuration of other systems.
+
+  The directory uses the same layout as the directories created by
+  "tofu providers mirror". The JSON index documents are generated from the
+  packages present in the directory, so any index files in the directory
+  are not used.
+
+  The server runs until it's interrupted.
+
+Options:
+
+  -address=host:port   The address to listen on. Defaults to
+                       127.0.0.1:8080.
+
+  -tls-cert=path       The certificate and private key files to use to serve
+  -tls-key=path        the mirror over HTTPS. OpenTofu only uses network
+                       mirrors with https URLs, so without these options
+                       the mirror must be served through a proxy that
+                       terminates TLS.
+
+  -token-file=path     A file containing bearer tokens, one per line. If
+                       set, requests must include one of these tokens in
+                       their Authorization header, which OpenTofu sends when
+                       there are credentials for the mirror's hostname in
+                       the CLI configuration.
+
+  -upstream            Download packages from their origin registries when
+                       they are requested but not present in the mirror
+                       directory, and save them there for future requests.
+                       The mirror directory is created if it doesn't exist.
+
+  -upstream-host=host  Allow -upstream to download packages from the origin
+                       registry at the given hostname. Defaults to
+                       registry.opentofu.org. Use this flag multiple times
+                       to allow multiple registries. Packages for providers
+                       from other registries are served only if they are
+                       already present in the mirror directory.
+
+  -platform=os_arch    Choose which target platforms to download packages
+                       for when using -upstream. Defaults to the platform
+                       where you run this command. Use this flag multiple
+                       times to include packages for multiple target systems.
+`
+}
+
+// readMirrorTokens reads the bearer tokens from the file at the given path,
+// ignoring blank lines.
+func readMirrorTokens(path string) ([]string, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tokens []string
+	for _, line := range strings.Split(string(src), "\n") {
+		if token := strings.TrimSpace(line); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("the file does not contain any tokens")
+	}
+	return tokens, nil
+}
+
+// providerMirrorServer is an http.Handler implementing the provider network
+// mirror protocol, as consumed by getproviders.HTTPMirrorSource, for the
+// packages in a directory with the packed layout.
+type providerMirrorServer struct {
+	dir string
+
+	// tokens, if non-empty, are the bearer tokens that requests must include
+	// one of.
+	tokens []string
+
+	// upstream, if set, is used to find packages that are not present in the
+	// directory for the given platforms, which are then downloaded into the
+	// directory using httpGetter. Only providers whose origin registry is in
+	// upstreamHosts are looked up.
+	upstream      getproviders.Source
+	upstreamHosts map[svchost.Hostname]bool
+	platforms     []getproviders.Platform
+	httpGetter    getter.HttpGetter
+
+	// populateLocks prevent concurrent requests from downloading the same
+	// packages, with one lock for each provider version.
+	populateLocksMu sync.Mutex
+	populateLocks   map[string]*sync.Mutex
+}
+
+var _ http.Handler = (*providerMirrorServer)(nil)
+
+func (s *providerMirrorServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="provider mirror"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// All of the mirror protocol's URLs are of the form
+	// hostname/namespace/type/file, relative to the mirror's base URL.
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if len(parts) != 4 {
+		http.NotFound(w, r)
+		return
+	}
+	provider, diags := addrs.ParseProviderSourceString(strings.Join(parts[:3], "/"))
+	if diags.HasErrors() {
+		http.NotFound(w, r)
+		return
+	}
+	filename := parts[3]
+
+	switch {
+	case filename == "index.json":
+		s.serveVersions(w, r, provider)
+	case strings.HasSuffix(filename, ".json"):
+		version, err := getproviders.ParseVersion(strings.TrimSuffix(filename, ".json"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		s.serveArchives(w, r, provider, version)
+	case strings.HasSuffix(filename, ".zip"):
+		s.servePackage(w, r, provider, filename)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *providerMirrorServer) authorized(r *http.Request) bool {
+	if len(s.tokens) == 0 {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	for _, want := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// serveVersions responds with the index of the available versions of the
+// given provider.
+func (s *providerMirrorServer) serveVersions(w http.ResponseWriter, r *http.Request, provider addrs.Provider) {
+	metas, err := s.localPackages(provider)
+	if err != nil {
+		log.Printf("[ERROR] Failed to scan provider mirror directory: %s", err)
+		http.Error(w, "failed to scan mirror directory", http.StatusInternalServerError)
+		return
+	}
+	versions := map[string]interface{}{}
+	for _, meta := range metas {
+		versions[meta.Version.String()] = map[string]interface{}{}
+	}
+	if s.useUpstream(provider) {
+		// If the origin registry isn't reachable then we still serve the
+		// versions we already have.
+		available, _, err := s.upstream.AvailableVersions(r.Context(), provider)
+		if err != nil {
+			log.Printf("[WARN] Failed to query available versions of %s from its origin registry: %s", provider, err)
+		}
+		for _, version := range available {
+			versions[version.String()] = map[string]interface{}{}
+		}
+	}
+	if len(versions) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	writeMirrorJSON(w, map[string]interface{}{
+		"versions": versions,
+	})
+}
+
+// serveArchives responds with the index of the packages available for the
+// given provider version.
+func (s *providerMirrorServer) serveArchives(w http.ResponseWriter, r *http.Request, provider addrs.Provider, version getproviders.Version) {
+	if s.useUpstream(provider) {
+		s.populate(r.Context(), provider, version)
+	}
+	metas, err := s.localPackages(provider)
+	if err != nil {
+		log.Printf("[ERROR] Failed to scan provider mirror directory: %s", err)
+		http.Error(w, "failed to scan mirror directory", http.StatusInternalServerError)
+		return
+	}
+	archives := map[string]interface{}{}
+	for _, meta := range metas {
+		if meta.Version != version {
+			continue
+		}
+		hash, err := meta.Hash()
+		if err != nil {
+			log.Printf("[WARN] Failed to determine a hash value for %s v%s on %s: %s", provider, version, meta.TargetPlatform, err)
+			continue
+		}
+		archives[meta.TargetPlatform.String()] = map[string]interface{}{
+			"url":    meta.Filename,           // a relative URL from the index file's URL
+			"hashes": []string{hash.String()}, // an array to allow for additional hash formats in future
+		}
+	}
+	if len(archives) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	writeMirrorJSON(w, map[string]interface{}{
+		"archives": archives,
+	})
+}
+
+// servePackage responds with the content of the package archive with the
+// given filename.
+func (s *providerMirrorServer) servePackage(w http.ResponseWriter, r *http.Request, provider addrs.Provider, filename string) {
+	metas, err := s.localPackages(provider)
+	if err != nil {
+		log.Printf("[ERROR] Failed to scan provider mirror directory: %s", err)
+		http.Error(w, "failed to scan mirror directory", http.StatusInternalServerError)
+		return
+	}
+	// We only serve files that we'd list in an archives index, rather than
+	// whatever path the request asks for.
+	for _, meta := range metas {
+		if meta.Filename != filename {
+			continue
+		}
+		f, err := os.Open(meta.Location.String())
+		if err != nil {
+			log.Printf("[ERROR] Failed to open provider package %s: %s", meta.Location, err)
+			http.Error(w, "failed to open package", http.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			log.Printf("[ERROR] Failed to open provider package %s: %s", meta.Location, err)
+			http.Error(w, "failed to open package", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		http.ServeContent(w, r, filename, info.ModTime(), f)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// useUpstream returns true if packages for the given provider that are not
+// present in the mirror directory can be found upstream.
+func (s *providerMirrorServer) useUpstream(provider addrs.Provider) bool {
+	return s.upstream != nil && s.upstreamHosts[provider.Hostname]
+}
+
+// localPackages returns the package archives for the given provider that
+// are present in the mirror directory.
+func (s *providerMirrorServer) localPackages(provider addrs.Provider) (getproviders.PackageMetaList, error) {
+	metas, err := getproviders.SearchLocalDirectoryForProvider(s.dir, provider)
+	if err != nil {
+		return nil, err
+	}
+	var ret getproviders.PackageMetaList
+	for _, meta := range metas {
+		// Only archive files can be served by a network mirror.
+		if _, ok := meta.Location.(getproviders.PackageLocalArchive); !ok {
+			continue
+		}
+		meta.Filename = filepath.Base(meta.Location.String())
+		ret = append(ret, meta)
+	}
+	return ret, nil
+}
+
+// populate downloads the packages for the given provider version from the
+// upstream source for each of the server's platforms that isn't already
+// present in the mirror directory.
+//
+// Failures are only logged, because the server can still serve whatever
+// packages it already has.
+func (s *providerMirrorServer) populate(ctx context.Context, provider addrs.Provider, version getproviders.Version) {
+	lock := s.populateLock(provider, version)
+	lock.Lock()
+	defer lock.Unlock()
+
+	metas, err := s.localPackages(provider)
+	if err != nil {
+		log.Printf("[ERROR] Failed to scan provider mirror directory: %s", err)
+		return
+	}
+	present := map[getproviders.Platform]bool{}
+	for _, meta := range metas {
+		if meta.Version == version {
+			present[meta.TargetPlatform] = true
+		}
+	}
+
+	for _, platform := range s.platforms {
+		if present[platform] {
+			continue
+		}
+		meta, err := s.upstream.PackageMeta(ctx, provider, version, platform)
+		if err != nil {
+			var notSupported getproviders.ErrPlatformNotSupported
+			if !errors.As(err, &notSupported) {
+				log.Printf("[WARN] Failed to find %s v%s for %s in its origin registry: %s", provider, version, platform, err)
+			}
+			continue
+		}
+		log.Printf("[INFO] Downloading %s v%s for %s into the provider mirror", provider, version, platform)
+		if _, diags := downloadMirrorPackage(s.httpGetter, meta, s.dir); diags.HasErrors() {
+			log.Printf("[WARN] Failed to download %s v%s for %s: %s", provider, version, platform, diags.Err())
+		}
+	}
+}
+
+// populateLock returns the lock that must be held while downloading the
+// packages for the given provider version.
+func (s *providerMirrorServer) populateLock(provider addrs.Provider, version getproviders.Version) *sync.Mutex {
+	s.populateLocksMu.Lock()
+	defer s.populateLocksMu.Unlock()
+
+	key := provider.String() + " " + version.String()
+	if s.populateLocks == nil {
+		s.populateLocks = map[string]*sync.Mutex{}
+	}
+	lock, ok := s.populateLocks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.populateLocks[key] = lock
+	}
+	return lock
+}
+
+func writeMirrorJSON(w http.ResponseWriter, v interface{}) {
+	src, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		// Should never happen because the input here is entirely under
+		// our control.
+		panic(fmt.Sprintf("failed to encode mirror index: %s", err))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(src)
+}
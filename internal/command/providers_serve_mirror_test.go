@@ -0,0 +1,302 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-getter"
+	svchost "github.com/hashicorp/terraform-svchost"
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/httpclient"
+)
+
+// testProviderMirrorPackage writes a provider package archive containing a
+// fake executable at the given path.
+func testProviderMirrorPackage(t *testing.T, path string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("terraform-provider-null")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("not a real provider")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testProviderMirrorGet(t *testing.T, server *httptest.Server, path, token string) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequest("GET", server.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestProvidersServeMirror_server(t *testing.T) {
+	dir := t.TempDir()
+	nullProvider := addrs.NewDefaultProvider("null")
+	version := getproviders.MustParseVersion("2.1.0")
+	linux := getproviders.Platform{OS: "linux", Arch: "amd64"}
+	darwin := getproviders.Platform{OS: "darwin", Arch: "arm64"}
+	testProviderMirrorPackage(t, getproviders.PackedFilePathForPackage(dir, nullProvider, version, linux))
+	testProviderMirrorPackage(t, getproviders.PackedFilePathForPackage(dir, nullProvider, version, darwin))
+
+	server := httptest.NewServer(&providerMirrorServer{dir: dir})
+	defer server.Close()
+
+	t.Run("versions", func(t *testing.T) {
+		status, body := testProviderMirrorGet(t, server, "/registry.opentofu.org/hashicorp/null/index.json", "")
+		if status != http.StatusOK {
+			t.Fatalf("wrong status %d\n%s", status, body)
+		}
+		var got map[string]interface{}
+		if err := json.Unmarshal([]byte(body), &got); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]interface{}{
+			"versions": map[string]interface{}{
+				"2.1.0": map[string]interface{}{},
+			},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong response\n%s", diff)
+		}
+	})
+	t.Run("archives", func(t *testing.T) {
+		status, body := testProviderMirrorGet(t, server, "/registry.opentofu.org/hashicorp/null/2.1.0.json", "")
+		if status != http.StatusOK {
+			t.Fatalf("wrong status %d\n%s", status, body)
+		}
+		var got struct {
+			Archives map[string]struct {
+				URL    string   `json:"url"`
+				Hashes []string `json:"hashes"`
+			} `json:"archives"`
+		}
+		if err := json.Unmarshal([]byte(body), &got); err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Archives) != 2 {
+			t.Fatalf("wrong number of archives\n%s", body)
+		}
+		archive := got.Archives["linux_amd64"]
+		if want := "terraform-provider-null_2.1.0_linux_amd64.zip"; archive.URL != want {
+			t.Errorf("wrong url %q; want %q", archive.URL, want)
+		}
+		if len(archive.Hashes) != 1 || !strings.HasPrefix(archive.Hashes[0], "h1:") {
+			t.Errorf("wrong hashes %#v", archive.Hashes)
+		}
+	})
+	t.Run("package", func(t *testing.T) {
+		status, body := testProviderMirrorGet(t, server, "/registry.opentofu.org/hashicorp/null/terraform-provider-null_2.1.0_linux_amd64.zip", "")
+		if status != http.StatusOK {
+			t.Fatalf("wrong status %d\n%s", status, body)
+		}
+		want, err := os.ReadFile(getproviders.PackedFilePathForPackage(dir, nullProvider, version, linux))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body != string(want) {
+			t.Errorf("wrong package content")
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		for _, path := range []string{
+			"/registry.opentofu.org/hashicorp/random/index.json",
+			"/registry.opentofu.org/hashicorp/null/3.0.0.json",
+			"/registry.opentofu.org/hashicorp/null/terraform-provider-null_3.0.0_linux_amd64.zip",
+			"/registry.opentofu.org/hashicorp/null/../null/index.json",
+			"/index.json",
+		} {
+			if status, _ := testProviderMirrorGet(t, server, path, ""); status != http.StatusNotFound {
+				t.Errorf("wrong status %d for %s", status, path)
+			}
+		}
+	})
+}
+
+func TestProvidersServeMirror_tokens(t *testing.T) {
+	dir := t.TempDir()
+	nullProvider := addrs.NewDefaultProvider("null")
+	version := getproviders.MustParseVersion("2.1.0")
+	testProviderMirrorPackage(t, getproviders.PackedFilePathForPackage(dir, nullProvider, version, getproviders.CurrentPlatform))
+
+	server := httptest.NewServer(&providerMirrorServer{
+		dir:    dir,
+		tokens: []string{"first", "second"},
+	})
+	defer server.Close()
+
+	for token, want := range map[string]int{
+		"":       http.StatusUnauthorized,
+		"wrong":  http.StatusUnauthorized,
+		"first":  http.StatusOK,
+		"second": http.StatusOK,
+	} {
+		if status, _ := testProviderMirrorGet(t, server, "/registry.opentofu.org/hashicorp/null/index.json", token); status != want {
+			t.Errorf("wrong status %d for token %q; want %d", status, token, want)
+		}
+	}
+}
+
+func TestProvidersServeMirror_upstream(t *testing.T) {
+	nullProvider := addrs.NewDefaultProvider("null")
+	linux := getproviders.Platform{OS: "linux", Arch: "amd64"}
+	windows := getproviders.Platform{OS: "windows", Arch: "amd64"}
+
+	// The origin registry's packages are served by a separate server.
+	originDir := t.TempDir()
+	testProviderMirrorPackage(t, filepath.Join(originDir, "null_2.1.0_linux_amd64.zip"))
+	testProviderMirrorPackage(t, filepath.Join(originDir, "null_2.2.0_linux_amd64.zip"))
+	origin := httptest.NewServer(http.FileServer(http.Dir(originDir)))
+	defer origin.Close()
+
+	var packages []getproviders.PackageMeta
+	for _, v := range []string{"2.1.0", "2.2.0"} {
+		meta := getproviders.FakePackageMeta(nullProvider, getproviders.MustParseVersion(v), getproviders.VersionList{getproviders.MustParseVersion("5.0")}, linux)
+		meta.Location = getproviders.PackageHTTPURL(origin.URL + "/null_" + v + "_linux_amd64.zip")
+		packages = append(packages, meta)
+	}
+	upstream := getproviders.NewMockSource(packages, nil)
+
+	// The mirror already has 2.1.0, so it must not download it again.
+	dir := t.TempDir()
+	existing := getproviders.PackedFilePathForPackage(dir, nullProvider, getproviders.MustParseVersion("2.1.0"), linux)
+	testProviderMirrorPackage(t, existing)
+
+	server := httptest.NewServer(&providerMirrorServer{
+		dir:           dir,
+		upstream:      upstream,
+		upstreamHosts: map[svchost.Hostname]bool{addrs.DefaultProviderRegistryHost: true},
+		platforms:     []getproviders.Platform{linux, windows},
+		httpGetter:    getter.HttpGetter{Client: httpclient.New()},
+	})
+	defer server.Close()
+
+	status, body := testProviderMirrorGet(t, server, "/registry.opentofu.org/hashicorp/null/index.json", "")
+	if status != http.StatusOK {
+		t.Fatalf("wrong status %d\n%s", status, body)
+	}
+	if !strings.Contains(body, `"2.2.0"`) {
+		t.Fatalf("upstream version missing from index\n%s", body)
+	}
+
+	for _, v := range []string{"2.1.0", "2.2.0"} {
+		status, body := testProviderMirrorGet(t, server, "/registry.opentofu.org/hashicorp/null/"+v+".json", "")
+		if status != http.StatusOK {
+			t.Fatalf("wrong status %d for %s\n%s", status, v, body)
+		}
+		if !strings.Contains(body, "terraform-provider-null_"+v+"_linux_amd64.zip") {
+			t.Fatalf("package missing from %s index\n%s", v, body)
+		}
+		if strings.Contains(body, "windows_amd64") {
+			t.Fatalf("unexpected windows package in %s index\n%s", v, body)
+		}
+	}
+
+	downloaded := getproviders.PackedFilePathForPackage(dir, nullProvider, getproviders.MustParseVersion("2.2.0"), linux)
+	if _, err := os.Stat(downloaded); err != nil {
+		t.Errorf("package was not saved in the mirror directory: %s", err)
+	}
+
+	var fetched []string
+	for _, call := range upstream.CallLog() {
+		if call[0] == "PackageMeta" {
+			fetched = append(fetched, call[2].(getproviders.Version).String()+" "+call[3].(getproviders.Platform).String())
+		}
+	}
+	want := []string{"2.1.0 windows_amd64", "2.2.0 linux_amd64", "2.2.0 windows_amd64"}
+	if diff := cmp.Diff(want, fetched); diff != "" {
+		t.Errorf("wrong upstream package requests\n%s", diff)
+	}
+}
+
+func TestProvidersServeMirror_upstreamHosts(t *testing.T) {
+	otherProvider := addrs.NewProvider(svchost.Hostname("example.com"), "hashicorp", "null")
+	linux := getproviders.Platform{OS: "linux", Arch: "amd64"}
+
+	meta := getproviders.FakePackageMeta(otherProvider, getproviders.MustParseVersion("2.1.0"), getproviders.VersionList{getproviders.MustParseVersion("5.0")}, linux)
+	upstream := getproviders.NewMockSource([]getproviders.PackageMeta{meta}, nil)
+
+	server := httptest.NewServer(&providerMirrorServer{
+		dir:           t.TempDir(),
+		upstream:      upstream,
+		upstreamHosts: map[svchost.Hostname]bool{addrs.DefaultProviderRegistryHost: true},
+		platforms:     []getproviders.Platform{linux},
+		httpGetter:    getter.HttpGetter{Client: httpclient.New()},
+	})
+	defer server.Close()
+
+	// The provider's registry isn't allowed, so the mirror must not contact
+	// it on behalf of the client.
+	for _, path := range []string{"/example.com/hashicorp/null/index.json", "/example.com/hashicorp/null/2.1.0.json"} {
+		if status, body := testProviderMirrorGet(t, server, path, ""); status != http.StatusNotFound {
+			t.Errorf("wrong status %d for %s\n%s", status, path, body)
+		}
+	}
+	if calls := upstream.CallLog(); len(calls) != 0 {
+		t.Errorf("unexpected upstream requests: %#v", calls)
+	}
+}
+
+func TestProvidersServeMirror_args(t *testing.T) {
+	for name, args := range map[string][]string{
+		"no directory":      {},
+		"missing directory": {filepath.Join(t.TempDir(), "nonexistent")},
+		"incomplete tls":    {"-tls-cert=cert.pem", t.TempDir()},
+		"invalid upstream":  {"-upstream", "-upstream-host=not a hostname", t.TempDir()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ui := new(cli.MockUi)
+			c := &ProvidersServeMirrorCommand{
+				Meta: Meta{Ui: ui},
+			}
+			if code := c.Run(args); code != 1 {
+				t.Fatalf("wrong exit code. expected 1, got %d", code)
+			}
+			if !strings.Contains(ui.ErrorWriter.String(), "Error: ") {
+				t.Fatalf("missing error in output, got:\n%s", ui.ErrorWriter.String())
+			}
+		})
+	}
+}
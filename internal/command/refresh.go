@@ -66,6 +66,10 @@ func (c *RefreshCommand) Run(rawArgs []string) int {
 	// object state for now.
 	c.Meta.parallelism = args.Operation.Parallelism
 
+	// The variables are needed while loading the configuration, to
+	// statically evaluate the backend configuration and module sources.
+	c.Meta.applyVariableArgs(args.Vars)
+
 	// Prepare the backend with the backend-specific arguments
 	be, beDiags := c.PrepareBackend(args.State, args.ViewType)
 	diags = diags.Append(beDiags)
@@ -144,6 +148,7 @@ func (c *RefreshCommand) OperationRequest(be backend.Enhanced, view views.Refres
 	opReq.ConfigDir = "."
 	opReq.Hooks = view.Hooks()
 	opReq.Targets = args.Targets
+	opReq.Excludes = args.Excludes
 	opReq.Type = backend.OperationTypeRefresh
 	opReq.View = view.Operation()
 
@@ -166,14 +171,7 @@ func (c *RefreshCommand) GatherVariables(opReq *backend.Operation, args *argumen
 	// Once all commands that gather variables have been converted to this
 	// structure, we could move the variable gathering code to the arguments
 	// package directly, removing this shim layer.
-
-	varArgs := args.All()
-	items := make([]rawFlag, len(varArgs))
-	for i := range varArgs {
-		items[i].Name = varArgs[i].Name
-		items[i].Value = varArgs[i].Value
-	}
-	c.Meta.variableArgs = rawFlags{items: &items}
+	c.Meta.applyVariableArgs(args)
 	opReq.Variables, diags = c.collectVariableValues()
 
 	return diags
@@ -196,6 +194,10 @@ Options:
                       accompanied by errors, show them in a more compact form
                       that includes only the summary messages.
 
+  -exclude=resource   Resource to exclude. Operation will skip this resource
+                      and everything that depends on it. This flag can be
+                      used multiple times.
+
   -input=true         Ask for input for variables if not directly set.
 
   -lock=false         Don't hold a state lock during the operation. This is
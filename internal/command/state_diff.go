@@ -0,0 +1,224 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/jsonformat"
+	"github.com/opentofu/opentofu/internal/command/jsonprovider"
+	"github.com/opentofu/opentofu/internal/command/jsonstate"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// StateDiffCommand is a Command implementation that shows the differences
+// between two state snapshots.
+type StateDiffCommand struct {
+	Meta
+	StateMeta
+}
+
+func (c *StateDiffCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	var jsonOutput bool
+	cmdFlags := c.Meta.defaultFlagSet("state diff")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Streams.Eprintf("Error parsing command-line flags: %s\n", err.Error())
+		return 1
+	}
+	args = cmdFlags.Args()
+	if len(args) < 1 || len(args) > 2 {
+		c.Streams.Eprint("One or two arguments expected.\n")
+		return cli.RunResultHelp
+	}
+	if len(args) == 2 && args[0] == "-" && args[1] == "-" {
+		c.Streams.Eprint("Only one of the states can be read from stdin.\n")
+		return cli.RunResultHelp
+	}
+
+	if diags := c.Meta.checkRequiredVersion(); diags != nil {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Check for user-supplied plugin path
+	var err error
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		c.Streams.Eprintf("Error loading plugin path: %s\n", err)
+		return 1
+	}
+
+	enc, encDiags := c.Encryption()
+	if encDiags.HasErrors() {
+		c.showDiagnostics(encDiags)
+		return 1
+	}
+
+	// The newer state defaults to the latest snapshot for the current
+	// workspace when only the older one is given.
+	before, diags := c.readState(args[0], enc.State())
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+	var after *statefile.File
+	if len(args) == 2 {
+		after, diags = c.readState(args[1], enc.State())
+	} else {
+		after, diags = c.readState("", enc.State())
+	}
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Each state may refer to providers that the other doesn't, so we need
+	// the schemas for both of them.
+	schemas := &tofu.Schemas{}
+	for _, sf := range []*statefile.File{before, after} {
+		s, diags := c.MaybeGetSchemas(sf.State, nil)
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		if s == nil {
+			continue
+		}
+		if schemas.Providers == nil {
+			schemas.Providers = s.Providers
+			continue
+		}
+		for addr, schema := range s.Providers {
+			schemas.Providers[addr] = schema
+		}
+	}
+
+	jsonStates := make([]jsonformat.State, 0, 2)
+	for _, sf := range []*statefile.File{before, after} {
+		root, outputs, err := jsonstate.MarshalForRenderer(sf, schemas)
+		if err != nil {
+			c.Streams.Eprintf("Failed to marshal state to json: %s\n", err)
+			return 1
+		}
+		jsonStates = append(jsonStates, jsonformat.State{
+			StateFormatVersion:    jsonstate.FormatVersion,
+			ProviderFormatVersion: jsonprovider.FormatVersion,
+			RootModule:            root,
+			RootModuleOutputs:     outputs,
+			ProviderSchemas:       jsonprovider.MarshalForRenderer(schemas),
+		})
+	}
+	diff := jsonformat.StateDiff{
+		Before: jsonStates[0],
+		After:  jsonStates[1],
+	}
+
+	if jsonOutput {
+		out, err := json.Marshal(diff.JSON())
+		if err != nil {
+			c.Streams.Eprintf("Failed to marshal state diff to json: %s\n", err)
+			return 1
+		}
+		c.Streams.Println(string(out))
+		return 0
+	}
+
+	renderer := jsonformat.Renderer{
+		Streams:             c.Streams,
+		Colorize:            c.Colorize(),
+		RunningInAutomation: c.RunningInAutomation,
+	}
+	renderer.RenderHumanStateDiff(diff)
+	return 0
+}
+
+// readState reads the state snapshot named by one of the command arguments:
+// the path of a state file, "-" for stdin, or the empty string for the latest
+// snapshot for the current workspace.
+func (c *StateDiffCommand) readState(path string, enc encryption.StateEncryption) (*statefile.File, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	var sf *statefile.File
+	switch path {
+	case "":
+		b, backendDiags := c.Backend(nil)
+		diags = diags.Append(backendDiags)
+		if backendDiags.HasErrors() {
+			return nil, diags
+		}
+
+		// This is a read-only command
+		c.ignoreRemoteVersionConflict(b)
+
+		workspace, err := c.Workspace()
+		if err != nil {
+			diags = diags.Append(fmt.Errorf("error selecting workspace: %w", err))
+			return nil, diags
+		}
+		sf, err = getStateFromBackend(b, workspace)
+		if err != nil {
+			diags = diags.Append(err)
+			return nil, diags
+		}
+	case "-":
+		var err error
+		sf, err = statefile.Read(os.Stdin, enc)
+		if err != nil {
+			diags = diags.Append(fmt.Errorf("Error reading stdin as a statefile: %w", err))
+			return nil, diags
+		}
+	default:
+		var err error
+		sf, err = getStateFromPath(path, enc)
+		if err != nil {
+			diags = diags.Append(err)
+			return nil, diags
+		}
+	}
+
+	// A workspace without any state yet is compared as an empty state.
+	if sf == nil {
+		sf = statefile.New(states.NewState(), "", 0)
+	}
+	return sf, diags
+}
+
+func (c *StateDiffCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] state diff [options] OLD [NEW]
+
+  Shows the differences between two snapshots of the OpenTofu state.
+
+  Each of OLD and NEW is the path of a state file, such as one saved with
+  "tofu state pull", or "-" to read the state from stdin. If NEW is
+  omitted, OLD is compared with the latest state for the current workspace.
+
+  The output shows each resource instance that was added, removed or
+  changed, along with the attributes that changed, and the changes to the
+  root module outputs. Sensitive values are redacted.
+
+Options:
+
+  -json               If specified, output the differences in a
+                      machine-readable form, listing the addresses of the
+                      changed resource instances and the paths of their
+                      changed attributes, but not their values.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateDiffCommand) Synopsis() string {
+	return "Show the differences between two state snapshots"
+}
@@ -0,0 +1,283 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/jsonformat"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/terminal"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// testStateDiffStates returns two states with a resource instance that only
+// exists in the first, one that only exists in the second, and one that
+// exists in both with different values, including a sensitive one.
+func testStateDiffStates() (*states.State, *states.State) {
+	instance := func(name string) addrs.AbsResourceInstance {
+		return addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test_instance",
+			Name: name,
+		}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	}
+	provider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("test"),
+		Module:   addrs.RootModule,
+	}
+
+	before := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(instance("foo"), &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"id":"foo","foo":"value","password":"secret"}`),
+			Status:    states.ObjectReady,
+		}, provider)
+		s.SetResourceInstanceCurrent(instance("old"), &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"id":"old","foo":"value","password":"secret"}`),
+			Status:    states.ObjectReady,
+		}, provider)
+		s.SetOutputValue(addrs.OutputValue{Name: "unchanged"}.Absolute(addrs.RootModuleInstance), cty.StringVal("same"), false)
+		s.SetOutputValue(addrs.OutputValue{Name: "changed"}.Absolute(addrs.RootModuleInstance), cty.StringVal("before"), false)
+	})
+	after := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(instance("foo"), &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"id":"foo","foo":"changed","password":"hunter2"}`),
+			Status:    states.ObjectReady,
+		}, provider)
+		s.SetResourceInstanceCurrent(instance("new"), &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"id":"new","foo":"value","password":"secret"}`),
+			Status:    states.ObjectReady,
+		}, provider)
+		s.SetOutputValue(addrs.OutputValue{Name: "unchanged"}.Absolute(addrs.RootModuleInstance), cty.StringVal("same"), false)
+		s.SetOutputValue(addrs.OutputValue{Name: "changed"}.Absolute(addrs.RootModuleInstance), cty.StringVal("after"), false)
+	})
+	return before, after
+}
+
+func testStateDiffProvider() *tofu.MockProvider {
+	p := testProvider()
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"test_instance": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id":       {Type: cty.String, Optional: true, Computed: true},
+						"foo":      {Type: cty.String, Optional: true},
+						"password": {Type: cty.String, Optional: true, Sensitive: true},
+					},
+				},
+			},
+		},
+	}
+	return p
+}
+
+func TestStateDiff(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	before, after := testStateDiffStates()
+	beforePath := testStateFile(t, before)
+	afterPath := testStateFile(t, after)
+
+	streams, done := terminal.StreamsForTesting(t)
+	c := &StateDiffCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testStateDiffProvider()),
+			Streams:          streams,
+		},
+	}
+
+	code := c.Run([]string{beforePath, afterPath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	expected := testStateDiffOutput
+	if diff := cmp.Diff(expected, output.Stdout()); diff != "" {
+		t.Fatalf("wrong output\n%s", diff)
+	}
+	if strings.Contains(output.Stdout(), "hunter2") {
+		t.Fatalf("output contains a sensitive value:\n%s", output.Stdout())
+	}
+}
+
+func TestStateDiff_json(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	before, after := testStateDiffStates()
+	beforePath := testStateFile(t, before)
+	afterPath := testStateFile(t, after)
+
+	streams, done := terminal.StreamsForTesting(t)
+	c := &StateDiffCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testStateDiffProvider()),
+			Streams:          streams,
+		},
+	}
+
+	code := c.Run([]string{"-json", beforePath, afterPath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+	if strings.Contains(output.Stdout(), "hunter2") {
+		t.Fatalf("output contains a sensitive value:\n%s", output.Stdout())
+	}
+
+	var got jsonformat.StateDiffJSON
+	if err := json.Unmarshal([]byte(output.Stdout()), &got); err != nil {
+		t.Fatalf("invalid json output: %s\n%s", err, output.Stdout())
+	}
+	want := jsonformat.StateDiffJSON{
+		FormatVersion: jsonformat.StateDiffFormatVersion,
+		ResourceChanges: []jsonformat.StateDiffResourceJSON{
+			{
+				Address:      "test_instance.foo",
+				Mode:         "managed",
+				Type:         "test_instance",
+				Name:         "foo",
+				ProviderName: "registry.opentofu.org/hashicorp/test",
+				Action:       "update",
+				ChangedPaths: [][]interface{}{{"foo"}, {"password"}},
+			},
+			{
+				Address:      "test_instance.new",
+				Mode:         "managed",
+				Type:         "test_instance",
+				Name:         "new",
+				ProviderName: "registry.opentofu.org/hashicorp/test",
+				Action:       "create",
+			},
+			{
+				Address:      "test_instance.old",
+				Mode:         "managed",
+				Type:         "test_instance",
+				Name:         "old",
+				ProviderName: "registry.opentofu.org/hashicorp/test",
+				Action:       "delete",
+			},
+		},
+		OutputChanges: []jsonformat.StateDiffOutputJSON{
+			{Name: "changed", Action: "update"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("wrong output\n%s", diff)
+	}
+}
+
+func TestStateDiff_currentState(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	before, after := testStateDiffStates()
+	beforePath := testStateFile(t, before)
+	testStateFileDefault(t, after)
+
+	streams, done := terminal.StreamsForTesting(t)
+	c := &StateDiffCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testStateDiffProvider()),
+			Streams:          streams,
+		},
+	}
+
+	code := c.Run([]string{beforePath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	expected := testStateDiffOutput
+	if diff := cmp.Diff(expected, output.Stdout()); diff != "" {
+		t.Fatalf("wrong output\n%s", diff)
+	}
+}
+
+func TestStateDiff_noDifferences(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	before, _ := testStateDiffStates()
+	beforePath := testStateFile(t, before)
+
+	streams, done := terminal.StreamsForTesting(t)
+	c := &StateDiffCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testStateDiffProvider()),
+			Streams:          streams,
+		},
+	}
+
+	code := c.Run([]string{beforePath, beforePath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+	if got, want := output.Stdout(), "No differences were found between the two states.\n"; got != want {
+		t.Fatalf("wrong output\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestStateDiff_badArgs(t *testing.T) {
+	for name, args := range map[string][]string{
+		"none":       nil,
+		"too many":   {"a", "b", "c"},
+		"both stdin": {"-", "-"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+			c := &StateDiffCommand{
+				Meta: Meta{
+					Streams: streams,
+				},
+			}
+
+			code := c.Run(args)
+			output := done(t)
+			if code != cli.RunResultHelp {
+				t.Fatalf("expected help, got %d\n\n%s", code, output.Stderr())
+			}
+		})
+	}
+}
+
+const testStateDiffOutput = `
+  # test_instance.foo has changed
+  ~ resource "test_instance" "foo" {
+      ~ foo      = "value" -> "changed"
+        id       = "foo"
+      ~ password = (sensitive value)
+    }
+
+  # test_instance.new has been added
+  + resource "test_instance" "new" {
+      + foo      = "value"
+      + id       = "new"
+      + password = (sensitive value)
+    }
+
+  # test_instance.old has been removed
+  - resource "test_instance" "old" {
+      - foo      = "value" -> null
+      - id       = "old" -> null
+      - password = (sensitive value) -> null
+    }
+
+Changes to Outputs:
+  ~ changed = "before" -> "after"
+`
@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+// StateHistoryCommand is a Command implementation that lists the earlier
+// state snapshots retained by the backend.
+type StateHistoryCommand struct {
+	Meta
+	StateMeta
+}
+
+func (c *StateHistoryCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	var limit int
+	cmdFlags := c.Meta.defaultFlagSet("state history")
+	cmdFlags.IntVar(&limit, "limit", defaultStateHistoryLimit, "limit")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	if limit < 0 {
+		c.Ui.Error("The -limit option must not be negative.")
+		return 1
+	}
+
+	if diags := c.Meta.checkRequiredVersion(); diags != nil {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(nil)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(backendDiags)
+		return 1
+	}
+
+	// This is a read-only command
+	c.ignoreRemoteVersionConflict(b)
+
+	// Get the state manager for the current workspace
+	env, err := c.Workspace()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
+		return 1
+	}
+	stateMgr, err := b.StateMgr(env)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateLoadingState, err))
+		return 1
+	}
+
+	history, ok := stateMgr.(statemgr.History)
+	if !ok {
+		c.Ui.Error(errStateHistoryUnsupported)
+		return 1
+	}
+
+	versions, err := history.SnapshotVersions(limit)
+	if err != nil {
+		if errors.Is(err, statemgr.ErrHistoryUnsupported) {
+			c.Ui.Error(errStateHistoryUnsupported)
+		} else {
+			c.Ui.Error(fmt.Sprintf("Failed to list state versions: %s", err))
+		}
+		return 1
+	}
+
+	// Where the storage doesn't record the serial and lineage of each
+	// snapshot, we read the snapshot only as we print it to find them, so
+	// that there's never more than one of them in memory.
+	for _, v := range versions {
+		timestamp := "-"
+		if !v.Timestamp.IsZero() {
+			timestamp = v.Timestamp.UTC().Format(time.RFC3339)
+		}
+
+		serial, lineage := "-", "-"
+		if v.Lineage != "" {
+			serial, lineage = strconv.FormatUint(v.Serial, 10), v.Lineage
+		} else if sf, err := history.SnapshotVersion(v.VersionID); err == nil {
+			serial, lineage = strconv.FormatUint(sf.Serial, 10), sf.Lineage
+		} else {
+			c.Ui.Warn(fmt.Sprintf("Failed to read state version %s: %s", v.VersionID, err))
+		}
+		c.Ui.Output(fmt.Sprintf("%s\t%s\t%s\t%s", v.VersionID, serial, lineage, timestamp))
+	}
+
+	return 0
+}
+
+func (c *StateHistoryCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] state history [options]
+
+  List the state snapshots retained by the backend for the current
+  workspace, from newest to oldest.
+
+  Each line shows the version ID, serial, lineage and timestamp of a
+  snapshot, separated by tabs. Pass a version ID to "tofu state rollback"
+  to restore that snapshot.
+
+  Only some backends retain earlier snapshots: the local backend lists the
+  state file and its backup files, while others depend on the object
+  versioning or snapshot features of their storage being enabled.
+
+Options:
+
+  -limit=n            List only the n newest snapshots. Defaults to 20.
+                      Set to 0 to list all of the retained snapshots.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateHistoryCommand) Synopsis() string {
+	return "List the earlier state snapshots retained by the backend"
+}
+
+// defaultStateHistoryLimit is the number of snapshots that "tofu state history"
+// lists by default, because for most backends each one must be read to find
+// its serial and lineage.
+const defaultStateHistoryLimit = 20
+
+const errStateHistoryUnsupported = `The state storage for this workspace does not retain earlier state snapshots.
+
+Only some backends can retain earlier snapshots, and many of them require
+object versioning or snapshots to be enabled for their storage. Refer to the
+documentation for your backend for more information.`
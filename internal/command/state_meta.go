@@ -32,7 +32,11 @@ func (c *StateMeta) State() (statemgr.Full, error) {
 
 	// use the specified state
 	if c.statePath != "" {
-		realState = statemgr.NewFilesystem(c.statePath)
+		enc, encDiags := c.Encryption()
+		if encDiags.HasErrors() {
+			return nil, encDiags.Err()
+		}
+		realState = statemgr.NewFilesystem(c.statePath, enc.State())
 	} else {
 		// Load the backend
 		b, backendDiags := c.Backend(nil)
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 )
@@ -61,8 +62,10 @@ func (c *StatePullCommand) Run(args []string) int {
 	stateFile := statemgr.Export(stateMgr)
 
 	if stateFile != nil { // we produce no output if the statefile is nil
+		// The output is always the decrypted state, so that it can be
+		// inspected or edited and then written back with "tofu state push".
 		var buf bytes.Buffer
-		err = statefile.Write(stateFile, &buf)
+		err = statefile.Write(stateFile, &buf, encryption.StateEncryptionDisabled())
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Failed to write state: %s", err))
 			return 1
@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+// StateRollbackCommand is a Command implementation that restores one of the
+// earlier state snapshots retained by the backend.
+type StateRollbackCommand struct {
+	Meta
+	StateMeta
+}
+
+func (c *StateRollbackCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	var versionID string
+	cmdFlags := c.Meta.ignoreRemoteVersionFlagSet("state rollback")
+	cmdFlags.StringVar(&versionID, "to", "", "version")
+	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock state")
+	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	if len(cmdFlags.Args()) != 0 || versionID == "" {
+		c.Ui.Error("The -to option is required, and no other arguments are expected.\n")
+		return cli.RunResultHelp
+	}
+
+	if diags := c.Meta.checkRequiredVersion(); diags != nil {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(nil)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(backendDiags)
+		return 1
+	}
+
+	// Determine the workspace name
+	workspace, err := c.Workspace()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
+		return 1
+	}
+
+	// Check remote OpenTofu version is compatible
+	remoteVersionDiags := c.remoteVersionCheck(b, workspace)
+	c.showDiagnostics(remoteVersionDiags)
+	if remoteVersionDiags.HasErrors() {
+		return 1
+	}
+
+	// Get the state manager for the currently-selected workspace
+	stateMgr, err := b.StateMgr(workspace)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateLoadingState, err))
+		return 1
+	}
+
+	history, ok := stateMgr.(statemgr.History)
+	if !ok {
+		c.Ui.Error(errStateHistoryUnsupported)
+		return 1
+	}
+
+	if c.stateLock {
+		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		if diags := stateLocker.Lock(stateMgr, "state-rollback"); diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		defer func() {
+			if diags := stateLocker.Unlock(); diags.HasErrors() {
+				c.showDiagnostics(diags)
+			}
+		}()
+	}
+
+	if err := stateMgr.RefreshState(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to refresh state: %s", err))
+		return 1
+	}
+	current := statemgr.Export(stateMgr)
+
+	target, err := history.SnapshotVersion(versionID)
+	if err != nil {
+		if errors.Is(err, statemgr.ErrHistoryUnsupported) {
+			c.Ui.Error(errStateHistoryUnsupported)
+		} else {
+			c.Ui.Error(fmt.Sprintf("Failed to read state version %q: %s", versionID, err))
+		}
+		return 1
+	}
+
+	// Restoring a snapshot from another lineage would silently replace the
+	// state with one for unrelated infrastructure, so we don't allow it.
+	if current != nil && current.Lineage != "" && target.Lineage != current.Lineage {
+		c.Ui.Error(fmt.Sprintf(errStateRollbackLineage, versionID, target.Lineage, current.Lineage))
+		return 1
+	}
+
+	// We write the old snapshot's state through the usual methods rather than
+	// importing the snapshot itself, so that it's persisted with the next
+	// serial and any other process will see it as the newest snapshot.
+	if err := stateMgr.WriteState(target.State); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to write state: %s", err))
+		return 1
+	}
+	if err := stateMgr.PersistState(nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to persist state: %s", err))
+		return 1
+	}
+
+	msg := fmt.Sprintf("Rolled back the state to version %s (serial %d).", versionID, target.Serial)
+	if meta, ok := stateMgr.(statemgr.PersistentMeta); ok {
+		msg = fmt.Sprintf("Rolled back the state to version %s (serial %d) as the new serial %d.", versionID, target.Serial, meta.StateSnapshotMeta().Serial)
+	}
+	c.Ui.Output(c.Colorize().Color("[reset][bold][green]" + msg))
+	return 0
+}
+
+func (c *StateRollbackCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] state rollback [options] -to=VERSION
+
+  Restore one of the earlier state snapshots retained by the backend for
+  the current workspace.
+
+  The snapshot with the given version ID, as listed by "tofu state history",
+  is written as a new snapshot with the next serial, so the snapshots that
+  follow it are kept and can themselves be restored later. The snapshot must
+  have the same lineage as the current state.
+
+Options:
+
+  -to=VERSION         The version ID of the snapshot to restore. This option
+                      is required.
+
+  -lock=false         Don't hold a state lock during the operation. This is
+                      dangerous if others might concurrently run commands
+                      against the same workspace.
+
+  -lock-timeout=0s    Duration to retry a state lock.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateRollbackCommand) Synopsis() string {
+	return "Restore an earlier state snapshot retained by the backend"
+}
+
+const errStateRollbackLineage = `The state version %q has lineage %q, but the current state has lineage %q.
+
+A rollback can only restore an earlier snapshot of the current state, to
+avoid replacing it with the state of unrelated infrastructure.`
@@ -0,0 +1,187 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+)
+
+// testStateHistoryFiles writes a local state file and a backup of an earlier
+// snapshot of it into the current working directory, each with a "foo"
+// output set to the given values.
+func testStateHistoryFiles(t *testing.T, lineage, current, backup string) {
+	t.Helper()
+
+	write := func(path, value string, serial uint64, modTime time.Time) {
+		s := states.NewState()
+		s.RootModule().SetOutputValue("foo", cty.StringVal(value), false)
+
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := statefile.Write(statefile.New(s, lineage, serial), f, encryption.StateEncryptionDisabled()); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(DefaultStateFilename+DefaultBackupExtension, backup, 1, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	write(DefaultStateFilename, current, 2, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+}
+
+func TestStateHistory(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+	testStateHistoryFiles(t, "history", "b", "a")
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &StateHistoryCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	want := "terraform.tfstate\t2\thistory\t2024-01-02T00:00:00Z\n" +
+		"terraform.tfstate.backup\t1\thistory\t2024-01-01T00:00:00Z\n"
+	if got := ui.OutputWriter.String(); got != want {
+		t.Fatalf("wrong output\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestStateHistory_limit(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+	testStateHistoryFiles(t, "history", "b", "a")
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &StateHistoryCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	if code := c.Run([]string{"-limit=1"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	want := "terraform.tfstate\t2\thistory\t2024-01-02T00:00:00Z\n"
+	if got := ui.OutputWriter.String(); got != want {
+		t.Fatalf("wrong output\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestStateRollback(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+	testStateHistoryFiles(t, "history", "b", "a")
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &StateRollbackCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	args := []string{"-to=" + DefaultStateFilename + DefaultBackupExtension}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if got, want := ui.OutputWriter.String(), "as the new serial 3"; !strings.Contains(got, want) {
+		t.Errorf("missing %q in output:\n%s", want, got)
+	}
+
+	f, err := os.Open(DefaultStateFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	sf, err := statefile.Read(f, encryption.StateEncryptionDisabled())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sf.Lineage != "history" || sf.Serial != 3 {
+		t.Errorf("wrong lineage %q and serial %d; want %q and %d", sf.Lineage, sf.Serial, "history", 3)
+	}
+	if got, want := sf.State.RootModule().OutputValues["foo"].Value, cty.StringVal("a"); !got.RawEquals(want) {
+		t.Errorf("wrong output value %#v; want %#v", got, want)
+	}
+}
+
+func TestStateRollback_lineageMismatch(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+	testStateHistoryFiles(t, "history", "b", "a")
+
+	// Replace the current state with one from another lineage.
+	s := testState()
+	sf := statefile.New(s, "other", 5)
+	f, err := os.Create(DefaultStateFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := statefile.Write(sf, f, encryption.StateEncryptionDisabled()); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &StateRollbackCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	args := []string{"-to=" + DefaultStateFilename + DefaultBackupExtension}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("expected the rollback to fail, got %d", code)
+	}
+	if got, want := ui.ErrorWriter.String(), `has lineage "history", but the current state has lineage "other"`; !strings.Contains(got, want) {
+		t.Errorf("missing %q in error:\n%s", want, got)
+	}
+}
+
+func TestStateRollback_missingTo(t *testing.T) {
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &StateRollbackCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+
+	if code := c.Run(nil); code != cli.RunResultHelp {
+		t.Fatalf("expected help, got %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+}
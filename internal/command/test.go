@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"path"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/hcl/v2"
@@ -16,6 +18,7 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/junit"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/logging"
@@ -59,8 +62,14 @@ Options:
   -json                 If specified, machine readable output will be printed in
                         JSON format
 
+  -junit-xml=path       Write a JUnit XML report of the test results to the
+                        given file, in addition to the normal output.
+
   -no-color             If specified, output won't contain any color.
 
+  -parallelism=n        Execute up to n test files, and run blocks marked as
+                        parallel, at the same time. Defaults to 1.
+
   -test-directory=path  Set the OpenTofu test directory, defaults to "tests". When set, the
                         test command will search for test files in the current directory and
                         in the one specified by the flag.
@@ -242,7 +251,8 @@ func (c *TestCommand) Run(rawArgs []string) int {
 		Cancelled: false,
 		Stopped:   false,
 
-		Verbose: args.Verbose,
+		Verbose:     args.Verbose,
+		Parallelism: args.Parallelism,
 	}
 
 	view.Abstract(&suite)
@@ -288,19 +298,49 @@ func (c *TestCommand) Run(rawArgs []string) int {
 		// tests finished normally with no interrupts.
 	}
 
-	if runner.Cancelled {
+	if !runner.Cancelled {
 		// Don't print out the conclusion if the test was cancelled.
-		return 1
+		view.Conclusion(&suite)
 	}
 
-	view.Conclusion(&suite)
+	// We still write the report when cancelled, so that CI systems can show
+	// the runs that completed and those that were interrupted.
+	if args.JUnitXMLFile != "" {
+		if diags := c.writeJUnitXML(args.JUnitXMLFile, &suite); diags.HasErrors() {
+			view.Diagnostics(nil, nil, diags)
+			return 1
+		}
+	}
 
-	if suite.Status != moduletest.Pass {
+	if runner.Cancelled || suite.Status != moduletest.Pass {
 		return 1
 	}
 	return 0
 }
 
+// writeJUnitXML writes a JUnit XML report of the test suite to the given
+// file, including any runs that were interrupted before they completed.
+func (c *TestCommand) writeJUnitXML(filename string, suite *moduletest.Suite) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	src, err := junit.MarshalTestSuite(suite, c.configSources())
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to generate JUnit XML report",
+			fmt.Sprintf("OpenTofu failed to generate the JUnit XML report: %s.", err)))
+		return diags
+	}
+
+	if err := os.WriteFile(filename, src, 0644); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write JUnit XML report",
+			fmt.Sprintf("OpenTofu failed to write the JUnit XML report to %s: %s.", filename, err)))
+	}
+	return diags
+}
+
 // test runner
 
 type TestSuiteRunner struct {
@@ -332,6 +372,15 @@ type TestSuiteRunner struct {
 
 	// Verbose tells the runner to print out plan files during each test run.
 	Verbose bool
+
+	// Parallelism is the maximum number of test files and run blocks that
+	// are executed at the same time. Values less than one are treated as one.
+	Parallelism int
+
+	// slots limits the number of files and run blocks executing at once, and
+	// viewLock serialises the output of the files executing at the same time.
+	slots    chan struct{}
+	viewLock sync.Mutex
 }
 
 func (runner *TestSuiteRunner) Start(globals map[string]backend.UnparsedVariableValue) {
@@ -341,16 +390,25 @@ func (runner *TestSuiteRunner) Start(globals map[string]backend.UnparsedVariable
 	}
 	sort.Strings(files) // execute the files in alphabetical order
 
-	runner.Suite.Status = moduletest.Pass
-	for _, name := range files {
-		if runner.Cancelled {
-			return
-		}
-
-		file := runner.Suite.Files[name]
-
-		fileRunner := &TestFileRunner{
+	parallelism := runner.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	runner.slots = make(chan struct{}, parallelism)
+
+	// Each test file has its own state, so test files can always execute
+	// concurrently. The output for each file is collected while it executes
+	// and rendered in order once it has completed, so the output is the same
+	// as if the files had executed one after another.
+	fileRunners := make([]*TestFileRunner, len(files))
+	done := make([]chan struct{}, len(files))
+	for ix := range files {
+		fileRunners[ix] = &TestFileRunner{
 			Suite: runner,
+			View: &testFileView{
+				Test: runner.View,
+				lock: &runner.viewLock,
+			},
 			States: map[string]*TestFileState{
 				MainStateIdentifier: {
 					Run:   nil,
@@ -358,17 +416,66 @@ func (runner *TestSuiteRunner) Start(globals map[string]backend.UnparsedVariable
 				},
 			},
 		}
+		done[ix] = make(chan struct{})
+	}
+
+	go func() {
+		for ix, name := range files {
+			runner.slots <- struct{}{}
+			if runner.Cancelled {
+				<-runner.slots
+				close(done[ix])
+				continue
+			}
+
+			go func(fileRunner *TestFileRunner, file *moduletest.File, done chan struct{}) {
+				defer logging.PanicHandler()
+				defer close(done)
+				defer func() { <-runner.slots }()
 
-		fileRunner.ExecuteTestFile(file)
-		fileRunner.Cleanup(file)
-		runner.Suite.Status = runner.Suite.Status.Merge(file.Status)
+				fileRunner.ExecuteTestFile(file)
+				fileRunner.Cleanup(file)
+			}(fileRunners[ix], runner.Suite.Files[name], done[ix])
+		}
+	}()
+
+	runner.Suite.Status = moduletest.Pass
+	for ix, name := range files {
+		<-done[ix]
+
+		fileRunners[ix].View.flush()
+		runner.Suite.Status = runner.Suite.Status.Merge(runner.Suite.Files[name].Status)
 	}
 }
 
+// tryAcquireSlot reserves one of the runner's execution slots if one is
+// available right now, returning false if all the slots are in use. Slots
+// reserved by this function must be released with releaseSlot.
+func (runner *TestSuiteRunner) tryAcquireSlot() bool {
+	select {
+	case runner.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (runner *TestSuiteRunner) releaseSlot() {
+	<-runner.slots
+}
+
 type TestFileRunner struct {
 	Suite *TestSuiteRunner
 
+	// View receives the output for this file, and buffers it until the file
+	// has finished executing.
+	View *testFileView
+
+	// States holds the state for the main configuration and for each module
+	// loaded by a run block, keyed by the module source. It must only be
+	// accessed while holding lock, as run blocks may execute concurrently.
 	States map[string]*TestFileState
+	lock   sync.Mutex
 }
 
 type TestFileState struct {
@@ -376,11 +483,72 @@ type TestFileState struct {
 	State *states.State
 }
 
+// testFileView buffers the output for a single test file until flush is
+// called, so that the output of test files executing concurrently isn't
+// interleaved.
+//
+// FatalInterruptSummary is the exception, and is rendered straight away as
+// the process may exit before the file completes.
+type testFileView struct {
+	views.Test
+
+	// lock is shared by all the files in a suite, and held while writing to
+	// the underlying view.
+	lock *sync.Mutex
+
+	bufferLock sync.Mutex
+	buffer     []func(view views.Test)
+}
+
+var _ views.Test = (*testFileView)(nil)
+
+func (v *testFileView) File(file *moduletest.File) {
+	v.record(func(view views.Test) { view.File(file) })
+}
+
+func (v *testFileView) Run(run *moduletest.Run, file *moduletest.File) {
+	v.record(func(view views.Test) { view.Run(run, file) })
+}
+
+func (v *testFileView) DestroySummary(diags tfdiags.Diagnostics, run *moduletest.Run, file *moduletest.File, state *states.State) {
+	v.record(func(view views.Test) { view.DestroySummary(diags, run, file, state) })
+}
+
+func (v *testFileView) Diagnostics(run *moduletest.Run, file *moduletest.File, diags tfdiags.Diagnostics) {
+	v.record(func(view views.Test) { view.Diagnostics(run, file, diags) })
+}
+
+func (v *testFileView) FatalInterruptSummary(run *moduletest.Run, file *moduletest.File, states map[*moduletest.Run]*states.State, created []*plans.ResourceInstanceChangeSrc) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.Test.FatalInterruptSummary(run, file, states, created)
+}
+
+func (v *testFileView) record(fn func(view views.Test)) {
+	v.bufferLock.Lock()
+	defer v.bufferLock.Unlock()
+	v.buffer = append(v.buffer, fn)
+}
+
+// flush renders all the buffered output to the underlying view.
+func (v *testFileView) flush() {
+	v.bufferLock.Lock()
+	buffer := v.buffer
+	v.buffer = nil
+	v.bufferLock.Unlock()
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	for _, fn := range buffer {
+		fn(v.Test)
+	}
+}
+
 func (runner *TestFileRunner) ExecuteTestFile(file *moduletest.File) {
 	log.Printf("[TRACE] TestFileRunner: executing test file %s", file.Name)
 
 	file.Status = file.Status.Merge(moduletest.Pass)
-	for _, run := range file.Runs {
+	for ix := 0; ix < len(file.Runs); {
 		if runner.Suite.Cancelled {
 			// This means a hard stop has been requested, in this case we don't
 			// even stop to mark future tests as having been skipped. They'll
@@ -388,69 +556,163 @@ func (runner *TestFileRunner) ExecuteTestFile(file *moduletest.File) {
 			return
 		}
 
-		if runner.Suite.Stopped {
-			// Then the test was requested to be stopped, so we just mark each
-			// following test as skipped and move on.
-			run.Status = moduletest.Skip
-			continue
+		batch := parallelRunBatch(file.Runs[ix:])
+		if len(batch) == 1 {
+			runner.executeRun(batch[0], file)
+		} else {
+			runner.executeParallelRuns(batch, file)
 		}
+		ix += len(batch)
+	}
 
-		if file.Status == moduletest.Error {
-			// If the overall test file has errored, we don't keep trying to
-			// execute tests. Instead, we mark all remaining run blocks as
-			// skipped.
-			run.Status = moduletest.Skip
-			continue
+	if runner.Suite.Cancelled {
+		return
+	}
+
+	runner.View.File(file)
+	for _, run := range file.Runs {
+		runner.View.Run(run, file)
+	}
+}
+
+// parallelRunBatch returns the run blocks from the start of runs that can
+// execute at the same time: a sequence of run blocks marked as parallel that
+// all use different states. A run block that isn't marked as parallel always
+// executes on its own.
+func parallelRunBatch(runs []*moduletest.Run) []*moduletest.Run {
+	if !runs[0].Config.Parallel {
+		return runs[:1]
+	}
+
+	keys := make(map[string]bool)
+	for ix, run := range runs {
+		key := runStateKey(run)
+		if !run.Config.Parallel || keys[key] {
+			return runs[:ix]
 		}
+		keys[key] = true
+	}
+	return runs
+}
 
-		key := MainStateIdentifier
-		config := runner.Suite.Config
-		if run.Config.ConfigUnderTest != nil {
-			config = run.Config.ConfigUnderTest
-			// Then we need to load an alternate state and not the main one.
+// runStateKey returns the key of the state the given run block executes
+// against within TestFileRunner.States.
+func runStateKey(run *moduletest.Run) string {
+	if run.Config.ConfigUnderTest != nil {
+		return run.Config.Module.Source.String()
+	}
+	return MainStateIdentifier
+}
 
-			key = run.Config.Module.Source.String()
-			if key == MainStateIdentifier {
-				// This is bad. It means somehow the module we're loading has
-				// the same key as main state and we're about to corrupt things.
-
-				run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
-					Severity: hcl.DiagError,
-					Summary:  "Invalid module source",
-					Detail:   fmt.Sprintf("The source for the selected module evaluated to %s which should not be possible. This is a bug in OpenTofu - please report it!", key),
-					Subject:  run.Config.Module.DeclRange.Ptr(),
-				})
-
-				run.Status = moduletest.Error
-				file.Status = moduletest.Error
-				continue // Abort!
-			}
+// executeParallelRuns executes the given run blocks concurrently, using as
+// many of the suite's execution slots as are available in addition to the
+// one already held for this file.
+func (runner *TestFileRunner) executeParallelRuns(runs []*moduletest.Run, file *moduletest.File) {
+	queue := make(chan *moduletest.Run, len(runs))
+	for _, run := range runs {
+		queue <- run
+	}
+	close(queue)
 
-			if _, exists := runner.States[key]; !exists {
-				runner.States[key] = &TestFileState{
-					Run:   nil,
-					State: states.NewState(),
-				}
-			}
+	worker := func() {
+		for run := range queue {
+			runner.executeRun(run, file)
 		}
+	}
 
-		state, updatedState := runner.ExecuteTestRun(run, file, runner.States[key].State, config)
-		if updatedState {
-			// Only update the most recent run and state if the state was
-			// actually updated by this change. We want to use the run that
-			// most recently updated the tracked state as the cleanup
-			// configuration.
-			runner.States[key].State = state
-			runner.States[key].Run = run
+	var wg sync.WaitGroup
+	for range runs[1:] {
+		if !runner.Suite.tryAcquireSlot() {
+			break
 		}
 
-		file.Status = file.Status.Merge(run.Status)
+		wg.Add(1)
+		go func() {
+			defer logging.PanicHandler()
+			defer wg.Done()
+			defer runner.Suite.releaseSlot()
+
+			worker()
+		}()
 	}
 
-	runner.Suite.View.File(file)
-	for _, run := range file.Runs {
-		runner.Suite.View.Run(run, file)
+	worker()
+	wg.Wait()
+}
+
+// executeRun executes a single run block and records the state it produced.
+func (runner *TestFileRunner) executeRun(run *moduletest.Run, file *moduletest.File) {
+	if runner.Suite.Cancelled {
+		return
 	}
+
+	if runner.Suite.Stopped {
+		// Then the test was requested to be stopped, so we just mark each
+		// following test as skipped and move on.
+		run.Status = moduletest.Skip
+		return
+	}
+
+	runner.lock.Lock()
+	if file.Status == moduletest.Error {
+		// If the overall test file has errored, we don't keep trying to
+		// execute tests. Instead, we mark all remaining run blocks as
+		// skipped.
+		runner.lock.Unlock()
+		run.Status = moduletest.Skip
+		return
+	}
+
+	key := runStateKey(run)
+	config := runner.Suite.Config
+	if run.Config.ConfigUnderTest != nil {
+		config = run.Config.ConfigUnderTest
+		// Then we need to load an alternate state and not the main one.
+
+		if key == MainStateIdentifier {
+			// This is bad. It means somehow the module we're loading has
+			// the same key as main state and we're about to corrupt things.
+
+			run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid module source",
+				Detail:   fmt.Sprintf("The source for the selected module evaluated to %s which should not be possible. This is a bug in OpenTofu - please report it!", key),
+				Subject:  run.Config.Module.DeclRange.Ptr(),
+			})
+
+			run.Status = moduletest.Error
+			file.Status = moduletest.Error
+			runner.lock.Unlock()
+			return // Abort!
+		}
+
+		if _, exists := runner.States[key]; !exists {
+			runner.States[key] = &TestFileState{
+				Run:   nil,
+				State: states.NewState(),
+			}
+		}
+	}
+	state := runner.States[key].State
+	runner.lock.Unlock()
+
+	start := time.Now()
+	state, updatedState := runner.ExecuteTestRun(run, file, state, config)
+	run.Duration = time.Since(start)
+
+	runner.lock.Lock()
+	defer runner.lock.Unlock()
+
+	if updatedState {
+		// Only update the most recent run and state if the state was
+		// actually updated by this change. We want to use the run that
+		// most recently updated the tracked state as the cleanup
+		// configuration.
+		runner.States[key].State = state
+		runner.States[key].Run = run
+	}
+
+	file.Status = file.Status.Merge(run.Status)
 }
 
 func (runner *TestFileRunner) ExecuteTestRun(run *moduletest.Run, file *moduletest.File, state *states.State, config *configs.Config) (*states.State, bool) {
@@ -475,6 +737,10 @@ func (runner *TestFileRunner) ExecuteTestRun(run *moduletest.Run, file *modulete
 		return state, false
 	}
 
+	// Other run blocks may be executing against the same configuration, so
+	// we make our own copy before modifying it.
+	config = config.CopyForTest()
+
 	resetConfig, configDiags := config.TransformForTest(run.Config, file.Config)
 	defer resetConfig()
 
@@ -845,6 +1111,7 @@ func (runner *TestFileRunner) wait(ctx *tofu.Context, runningCtx context.Context
 	handleCancelled := func() {
 		log.Printf("[DEBUG] TestFileRunner: test execution cancelled during %s", identifier)
 
+		runner.lock.Lock()
 		states := make(map[*moduletest.Run]*states.State)
 		states[nil] = runner.States[MainStateIdentifier].State
 		for key, module := range runner.States {
@@ -853,7 +1120,8 @@ func (runner *TestFileRunner) wait(ctx *tofu.Context, runningCtx context.Context
 			}
 			states[module.Run] = module.State
 		}
-		runner.Suite.View.FatalInterruptSummary(run, file, states, created)
+		runner.lock.Unlock()
+		runner.View.FatalInterruptSummary(run, file, states, created)
 
 		cancelled = true
 		go ctx.Stop()
@@ -926,7 +1194,7 @@ func (runner *TestFileRunner) Cleanup(file *moduletest.File) {
 
 			var diags tfdiags.Diagnostics
 			diags = diags.Append(tfdiags.Sourceless(tfdiags.Error, "Inconsistent state", fmt.Sprintf("Found inconsistent state while cleaning up %s. This is a bug in OpenTofu - please report it", file.Name)))
-			runner.Suite.View.DestroySummary(diags, nil, file, state.State)
+			runner.View.DestroySummary(diags, nil, file, state.State)
 			continue
 		}
 
@@ -959,6 +1227,8 @@ func (runner *TestFileRunner) Cleanup(file *moduletest.File) {
 		} else {
 			runConfig = state.Run.Config.ConfigUnderTest
 		}
+		// Other test files may be executing against the same configuration.
+		runConfig = runConfig.CopyForTest()
 
 		reset, configDiags := runConfig.TransformForTest(state.Run.Config, file.Config)
 		diags = diags.Append(configDiags)
@@ -969,7 +1239,7 @@ func (runner *TestFileRunner) Cleanup(file *moduletest.File) {
 			updated, destroyDiags = runner.destroy(runConfig, state.State, state.Run, file)
 			diags = diags.Append(destroyDiags)
 		}
-		runner.Suite.View.DestroySummary(diags, state.Run, file, updated)
+		runner.View.DestroySummary(diags, state.Run, file, updated)
 
 		reset()
 	}
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"os"
 	"path"
 	"strings"
 	"testing"
@@ -12,6 +13,8 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	testing_command "github.com/opentofu/opentofu/internal/command/testing"
 	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/moduletest"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/terminal"
 )
@@ -156,6 +159,10 @@ func TestTest(t *testing.T) {
 			expected: "1 passed, 0 failed.",
 			code:     0,
 		},
+		"mock_provider": {
+			expected: "1 passed, 0 failed.",
+			code:     0,
+		},
 	}
 	for name, tc := range tcs {
 		t.Run(name, func(t *testing.T) {
@@ -624,6 +631,97 @@ Success! 2 passed, 0 failed.
 	}
 }
 
+func TestTest_JUnitXMLDoubleInterrupt(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "with_double_interrupt")), td)
+	defer testChdir(t, td)()
+
+	provider := testing_command.NewProvider(nil)
+	view, done := testView(t)
+
+	interrupt := make(chan struct{})
+	provider.Interrupt = interrupt
+
+	c := &TestCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+			ShutdownCh:       interrupt,
+		},
+	}
+
+	code := c.Run([]string{"-junit-xml=results.xml", "-no-color"})
+	done(t)
+
+	if code != 1 {
+		t.Errorf("expected status code 1 but got %d", code)
+	}
+
+	raw, err := os.ReadFile("results.xml")
+	if err != nil {
+		t.Fatalf("failed to read JUnit XML report: %s", err)
+	}
+	report := string(raw)
+
+	for _, want := range []string{
+		`<testsuite name="main.tftest.hcl"`,
+		`<skipped message="Testing was interrupted before this run block was executed">`,
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("missing %q in JUnit XML report:\n%s", want, report)
+		}
+	}
+}
+
+func TestTest_JUnitXML(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "simple_fail")), td)
+	defer testChdir(t, td)()
+
+	provider := testing_command.NewProvider(nil)
+	view, done := testView(t)
+
+	c := &TestCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-junit-xml=results.xml", "-no-color"})
+	output := done(t)
+
+	if code != 1 {
+		t.Errorf("expected status code 1 but got %d", code)
+	}
+
+	// The human view should still be rendered as normal.
+	if got, want := output.Stdout(), "main.tftest.hcl... fail"; !strings.Contains(got, want) {
+		t.Errorf("missing human output %q in:\n%s", want, got)
+	}
+
+	raw, err := os.ReadFile("results.xml")
+	if err != nil {
+		t.Fatalf("failed to read JUnit XML report: %s", err)
+	}
+	report := string(raw)
+
+	for _, want := range []string{
+		`<testsuite name="main.tftest.hcl" tests="1" skipped="0" failures="1" errors="0"`,
+		`<testcase name="validate_test_resource" classname="main.tftest.hcl"`,
+		`<failure message="Test assertion failed" type="assertion">`,
+		`invalid value`,
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("missing %q in JUnit XML report:\n%s", want, report)
+		}
+	}
+
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+	}
+}
+
 func TestTest_ValidatesBeforeExecution(t *testing.T) {
 	tcs := map[string]struct {
 		expectedOut string
@@ -798,6 +896,10 @@ func TestTest_Modules(t *testing.T) {
 			expected: "main.tftest.hcl... pass\n  run \"first\"... pass\n  run \"second\"... pass\n\nSuccess! 2 passed, 0 failed.\n",
 			code:     0,
 		},
+		"override_resources": {
+			expected: "main.tftest.hcl... pass\n  run \"file_overrides\"... pass\n  run \"run_overrides\"... pass\n\nSuccess! 2 passed, 0 failed.\n",
+			code:     0,
+		},
 	}
 
 	for name, tc := range tcs {
@@ -875,6 +977,71 @@ func TestTest_Modules(t *testing.T) {
 	}
 }
 
+func TestTest_Parallel(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "parallel")), td)
+	defer testChdir(t, td)()
+
+	provider := testing_command.NewProvider(nil)
+	providerSource, close := newMockProviderSource(t, map[string][]string{
+		"test": {"1.0.0"},
+	})
+	defer close()
+
+	streams, done := terminal.StreamsForTesting(t)
+	view := views.NewView(streams)
+	ui := new(cli.MockUi)
+	meta := Meta{
+		testingOverrides: metaOverridesForProvider(provider.Provider),
+		Ui:               ui,
+		View:             view,
+		Streams:          streams,
+		ProviderSource:   providerSource,
+	}
+
+	init := &InitCommand{
+		Meta: meta,
+	}
+
+	if code := init.Run(nil); code != 0 {
+		t.Fatalf("expected status code 0 but got %d: %s", code, ui.ErrorWriter)
+	}
+
+	command := &TestCommand{
+		Meta: meta,
+	}
+
+	code := command.Run([]string{"-no-color", "-parallelism=4"})
+	output := done(t)
+
+	if code != 0 {
+		t.Errorf("expected status code 0 but got %d: %s", code, output.All())
+	}
+
+	// Even though the files and runs execute concurrently, the output must
+	// still be rendered file by file and run by run in the usual order.
+	expected := `a.tftest.hcl... pass
+  run "main"... pass
+  run "setup"... pass
+  run "final"... pass
+b.tftest.hcl... pass
+  run "main"... pass
+  run "setup"... pass
+  run "final"... pass
+
+Success! 6 passed, 0 failed.
+`
+
+	actual := output.All()
+	if diff := cmp.Diff(actual, expected); len(diff) > 0 {
+		t.Errorf("output didn't match expected:\nexpected:\n%s\nactual:\n%s\ndiff:\n%s", expected, actual, diff)
+	}
+
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources on completion but left %s", provider.ResourceString())
+	}
+}
+
 func TestTest_StatePropagation(t *testing.T) {
 	td := t.TempDir()
 	testCopyDir(t, testFixturePath(path.Join("test", "state_propagation")), td)
@@ -1109,3 +1276,54 @@ Condition expression could not be evaluated at this time.
 		})
 	}
 }
+
+func TestParallelRunBatch(t *testing.T) {
+	run := func(name string, parallel bool, source string) *moduletest.Run {
+		config := &configs.TestRun{
+			Name:     name,
+			Parallel: parallel,
+		}
+		if len(source) > 0 {
+			config.ConfigUnderTest = &configs.Config{}
+			config.Module = &configs.TestRunModuleCall{
+				Source: addrs.ModuleSourceLocal(source),
+			}
+		}
+		return &moduletest.Run{Name: name, Config: config}
+	}
+
+	tcs := map[string]struct {
+		runs     []*moduletest.Run
+		expected []string
+	}{
+		"sequential": {
+			runs:     []*moduletest.Run{run("a", false, ""), run("b", true, "./setup")},
+			expected: []string{"a"},
+		},
+		"distinct states": {
+			runs:     []*moduletest.Run{run("a", true, ""), run("b", true, "./setup"), run("c", true, "./other")},
+			expected: []string{"a", "b", "c"},
+		},
+		"shared state": {
+			runs:     []*moduletest.Run{run("a", true, ""), run("b", true, "./setup"), run("c", true, "")},
+			expected: []string{"a", "b"},
+		},
+		"stops at sequential": {
+			runs:     []*moduletest.Run{run("a", true, ""), run("b", false, "./setup"), run("c", true, "./other")},
+			expected: []string{"a"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			var actual []string
+			for _, run := range parallelRunBatch(tc.runs) {
+				actual = append(actual, run.Name)
+			}
+
+			if diff := cmp.Diff(tc.expected, actual); len(diff) > 0 {
+				t.Errorf("wrong batch:\n%s", diff)
+			}
+		})
+	}
+}
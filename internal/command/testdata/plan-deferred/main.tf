@@ -0,0 +1,5 @@
+resource "test_instance" "foo" {
+  # Impure functions aren't known until apply, so this resource can only be
+  # planned with deferral allowed.
+  count = length(uuid())
+}
@@ -0,0 +1,7 @@
+resource "test_resource" "foo" {
+  value = "bar"
+}
+
+data "test_data_source" "foo" {
+  id = "missing"
+}
@@ -0,0 +1,30 @@
+mock_provider "test" {
+  mock_resource "test_resource" {
+    defaults = {
+      id = "mocked"
+    }
+  }
+
+  mock_data "test_data_source" {
+    defaults = {
+      value = "fake"
+    }
+  }
+}
+
+run "validate_mocked_values" {
+  assert {
+    condition     = test_resource.foo.id == "mocked"
+    error_message = "invalid id"
+  }
+
+  assert {
+    condition     = test_resource.foo.value == "bar"
+    error_message = "invalid value"
+  }
+
+  assert {
+    condition     = data.test_data_source.foo.value == "fake"
+    error_message = "invalid data value"
+  }
+}
@@ -0,0 +1,7 @@
+resource "test_resource" "child" {
+  value = "child"
+}
+
+output "value" {
+  value = test_resource.child.value
+}
@@ -0,0 +1,15 @@
+resource "test_resource" "foo" {
+  value = "bar"
+}
+
+data "test_data_source" "foo" {
+  id = "missing"
+}
+
+module "child" {
+  source = "./child"
+}
+
+output "child_value" {
+  value = module.child.value
+}
@@ -0,0 +1,63 @@
+override_resource {
+  target = test_resource.foo
+  values = {
+    id = "overridden"
+  }
+}
+
+override_data {
+  target = data.test_data_source.foo
+  values = {
+    value = "fake"
+  }
+}
+
+override_module {
+  target = module.child
+  outputs = {
+    value = "overridden"
+  }
+}
+
+run "file_overrides" {
+  assert {
+    condition     = test_resource.foo.id == "overridden"
+    error_message = "invalid id"
+  }
+
+  assert {
+    condition     = data.test_data_source.foo.value == "fake"
+    error_message = "invalid data value"
+  }
+
+  assert {
+    condition     = output.child_value == "overridden"
+    error_message = "invalid child value"
+  }
+}
+
+run "run_overrides" {
+  override_resource {
+    target = test_resource.foo
+    values = {
+      id = "run"
+    }
+  }
+
+  override_module {
+    target = module.child
+    outputs = {
+      value = "run"
+    }
+  }
+
+  assert {
+    condition     = test_resource.foo.id == "run"
+    error_message = "invalid id"
+  }
+
+  assert {
+    condition     = output.child_value == "run"
+    error_message = "invalid child value"
+  }
+}
@@ -0,0 +1,40 @@
+run "main" {
+  parallel = true
+
+  variables {
+    id = "a-main"
+  }
+
+  assert {
+    condition     = test_resource.foo.value == "main"
+    error_message = "bad value"
+  }
+}
+
+run "setup" {
+  parallel = true
+
+  module {
+    source = "./setup"
+  }
+
+  variables {
+    id = "a-setup"
+  }
+
+  assert {
+    condition     = test_resource.setup.value == "setup"
+    error_message = "bad value"
+  }
+}
+
+run "final" {
+  variables {
+    id = "a-main"
+  }
+
+  assert {
+    condition     = test_resource.foo.id == "a-main"
+    error_message = "bad id"
+  }
+}
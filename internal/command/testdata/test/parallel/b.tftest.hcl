@@ -0,0 +1,40 @@
+run "main" {
+  parallel = true
+
+  variables {
+    id = "b-main"
+  }
+
+  assert {
+    condition     = test_resource.foo.value == "main"
+    error_message = "bad value"
+  }
+}
+
+run "setup" {
+  parallel = true
+
+  module {
+    source = "./setup"
+  }
+
+  variables {
+    id = "b-setup"
+  }
+
+  assert {
+    condition     = test_resource.setup.value == "setup"
+    error_message = "bad value"
+  }
+}
+
+run "final" {
+  variables {
+    id = "b-main"
+  }
+
+  assert {
+    condition     = test_resource.foo.id == "b-main"
+    error_message = "bad id"
+  }
+}
@@ -0,0 +1,8 @@
+variable "id" {
+  type = string
+}
+
+resource "test_resource" "foo" {
+  id    = var.id
+  value = "main"
+}
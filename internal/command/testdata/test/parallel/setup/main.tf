@@ -0,0 +1,8 @@
+variable "id" {
+  type = string
+}
+
+resource "test_resource" "setup" {
+  id    = var.id
+  value = "setup"
+}
@@ -0,0 +1,19 @@
+terraform {
+  backend "local" {
+    path = "base.tfstate"
+  }
+
+  workspaces {
+    var_file_dir = "envs"
+
+    workspace "prod" {
+      backend {
+        workspace_dir = "prod-states"
+      }
+    }
+  }
+}
+
+variable "greeting" {
+  type = string
+}
@@ -69,6 +69,7 @@ const (
 	ActionUpdate  ChangeAction = "update"
 	ActionReplace ChangeAction = "replace"
 	ActionDelete  ChangeAction = "delete"
+	ActionForget  ChangeAction = "forget"
 	ActionImport  ChangeAction = "import"
 )
 
@@ -86,6 +87,8 @@ func changeAction(action plans.Action) ChangeAction {
 		return ActionReplace
 	case plans.Delete:
 		return ActionDelete
+	case plans.Forget:
+		return ActionForget
 	default:
 		return ActionNoOp
 	}
@@ -15,6 +15,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/jsonplan"
 	"github.com/opentofu/opentofu/internal/command/jsonprovider"
 	"github.com/opentofu/opentofu/internal/command/views/json"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -83,7 +84,7 @@ func (v *OperationHuman) Cancelled(planMode plans.Mode) {
 
 func (v *OperationHuman) EmergencyDumpState(stateFile *statefile.File) error {
 	stateBuf := new(bytes.Buffer)
-	jsonErr := statefile.Write(stateFile, stateBuf)
+	jsonErr := statefile.Write(stateFile, stateBuf, encryption.StateEncryptionDisabled())
 	if jsonErr != nil {
 		return jsonErr
 	}
@@ -112,6 +113,7 @@ func (v *OperationHuman) Plan(plan *plans.Plan, schemas *tofu.Schemas) {
 		ResourceDrift:         drift,
 		ProviderSchemas:       jsonprovider.MarshalForRenderer(schemas),
 		RelevantAttributes:    attrs,
+		DeferredChanges:       jsonplan.MarshalDeferredChanges(plan.DeferredChanges),
 	}
 
 	// Side load some data that we can't extract from the JSON plan.
@@ -199,7 +201,7 @@ func (v *OperationJSON) Cancelled(planMode plans.Mode) {
 
 func (v *OperationJSON) EmergencyDumpState(stateFile *statefile.File) error {
 	stateBuf := new(bytes.Buffer)
-	jsonErr := statefile.Write(stateFile, stateBuf)
+	jsonErr := statefile.Write(stateFile, stateBuf, encryption.StateEncryptionDisabled())
 	if jsonErr != nil {
 		return jsonErr
 	}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/lang/globalref"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/states"
@@ -530,7 +531,7 @@ func TestOperationJSON_emergencyDumpState(t *testing.T) {
 
 	stateFile := statefile.New(nil, "foo", 1)
 	stateBuf := new(bytes.Buffer)
-	err := statefile.Write(stateFile, stateBuf)
+	err := statefile.Write(stateFile, stateBuf, encryption.StateEncryptionDisabled())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -85,6 +85,7 @@ func (v *ShowHuman) Display(config *configs.Config, plan *plans.Plan, planJSON *
 			ResourceDrift:         drift,
 			ProviderSchemas:       jsonprovider.MarshalForRenderer(schemas),
 			RelevantAttributes:    attrs,
+			DeferredChanges:       jsonplan.MarshalDeferredChanges(plan.DeferredChanges),
 		}
 
 		var opts []plans.Quality
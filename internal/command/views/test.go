@@ -187,6 +187,7 @@ func (t *TestHuman) Run(run *moduletest.Run, file *moduletest.File) {
 					ResourceDrift:         drift,
 					ProviderSchemas:       jsonprovider.MarshalForRenderer(schemas),
 					RelevantAttributes:    attrs,
+					DeferredChanges:       jsonplan.MarshalDeferredChanges(run.Verbose.Plan.DeferredChanges),
 				}
 
 				var opts []plans.Quality
@@ -4,19 +4,24 @@
 package command
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/mitchellh/cli"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/backend/local"
 	"github.com/opentofu/opentofu/internal/backend/remote-state/inmem"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/zclconf/go-cty/cty"
 
+	"github.com/opentofu/opentofu/internal/encryption"
 	legacy "github.com/opentofu/opentofu/internal/legacy/tofu"
 )
 
@@ -178,6 +183,152 @@ func TestWorkspace_createAndShow(t *testing.T) {
 	}
 }
 
+func TestWorkspace_showJSON(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("workspace-settings"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	initCmd := &InitCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := initCmd.Run([]string{"-input=false"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	ui = new(cli.MockUi)
+	newCmd := &WorkspaceNewCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := newCmd.Run([]string{"prod"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	ui = new(cli.MockUi)
+	showCmd := &WorkspaceShowCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := showCmd.Run([]string{"-json"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, ui.OutputWriter.String())
+	}
+	want := map[string]interface{}{
+		"format_version": "1.0",
+		"workspace":      "prod",
+		"overridden":     false,
+		"var_files":      []interface{}{filepath.Join("envs", "prod.tfvars")},
+		"backend": map[string]interface{}{
+			"type": "local",
+			"config": map[string]interface{}{
+				"path":          "base.tfstate",
+				"workspace_dir": "prod-states",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong output\n%s", diff)
+	}
+
+	// The variable file named after the workspace is loaded automatically.
+	m := Meta{Ui: ui, View: view}
+	vals, diags := m.collectVariableValues()
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	val, diags := vals["greeting"].ParseVariableValue(configs.VariableParseLiteral)
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	if got, want := val.Value, cty.StringVal("hello prod"); !got.RawEquals(want) {
+		t.Errorf("wrong greeting %#v; want %#v", got, want)
+	}
+}
+
+func TestWorkspace_backendOverrides(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("workspace-settings"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	initCmd := &InitCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := initCmd.Run([]string{"-input=false"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	// The prod workspace is created in the backend configured by its
+	// overrides, which is where later operations in prod read its state.
+	ui = new(cli.MockUi)
+	newCmd := &WorkspaceNewCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := newCmd.Run([]string{"prod"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+	if _, err := os.Stat(filepath.Join("prod-states", "prod")); err != nil {
+		t.Fatalf("prod workspace not created in its own backend: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(local.DefaultWorkspaceDir, "prod")); !os.IsNotExist(err) {
+		t.Fatalf("prod workspace created in the default backend")
+	}
+
+	ui = new(cli.MockUi)
+	newCmd = &WorkspaceNewCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := newCmd.Run([]string{"staging"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+	if _, err := os.Stat(filepath.Join(local.DefaultWorkspaceDir, "staging")); err != nil {
+		t.Fatalf("staging workspace not created in the default backend: %s", err)
+	}
+
+	ui = new(cli.MockUi)
+	listCmd := &WorkspaceListCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := listCmd.Run(nil); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+	if got, want := strings.TrimSpace(ui.OutputWriter.String()), "default\n  prod\n* staging"; got != want {
+		t.Fatalf("wrong workspaces\ngot:\n%s\n\nwant:\n%s", got, want)
+	}
+
+	ui = new(cli.MockUi)
+	selectCmd := &WorkspaceSelectCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := selectCmd.Run([]string{"prod"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	ui = new(cli.MockUi)
+	selectCmd = &WorkspaceSelectCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := selectCmd.Run([]string{"default"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	ui = new(cli.MockUi)
+	deleteCmd := &WorkspaceDeleteCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := deleteCmd.Run([]string{"prod"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+	if _, err := os.Stat(filepath.Join("prod-states", "prod")); !os.IsNotExist(err) {
+		t.Fatalf("prod workspace not deleted from its own backend")
+	}
+}
+
 // Don't allow names that aren't URL safe
 func TestWorkspace_createInvalid(t *testing.T) {
 	// Create a temporary working directory that is empty
@@ -251,7 +402,7 @@ func TestWorkspace_createWithState(t *testing.T) {
 		)
 	})
 
-	err := statemgr.WriteAndPersist(statemgr.NewFilesystem("test.tfstate"), originalState, nil)
+	err := statemgr.WriteAndPersist(statemgr.NewFilesystem("test.tfstate", encryption.StateEncryptionDisabled()), originalState, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -268,13 +419,13 @@ func TestWorkspace_createWithState(t *testing.T) {
 	}
 
 	newPath := filepath.Join(local.DefaultWorkspaceDir, "test", DefaultStateFilename)
-	envState := statemgr.NewFilesystem(newPath)
+	envState := statemgr.NewFilesystem(newPath, encryption.StateEncryptionDisabled())
 	err = envState.RefreshState()
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	b := backend.TestBackendConfig(t, inmem.New(), nil)
+	b := backend.TestBackendConfig(t, inmem.New(encryption.StateEncryptionDisabled()), nil)
 	sMgr, err := b.StateMgr(workspace)
 	if err != nil {
 		t.Fatal(err)
@@ -61,10 +61,12 @@ func (c *WorkspaceDeleteCommand) Run(args []string) int {
 		return 1
 	}
 
-	// Load the backend
-	b, backendDiags := c.Backend(&BackendOpts{
+	workspace := args[0]
+
+	// Load the backend that the workspace's state is stored in
+	b, backendDiags := c.workspaceBackend(&BackendOpts{
 		Config: backendConfig,
-	})
+	}, workspace)
 	diags = diags.Append(backendDiags)
 	if backendDiags.HasErrors() {
 		c.showDiagnostics(diags)
@@ -80,7 +82,6 @@ func (c *WorkspaceDeleteCommand) Run(args []string) int {
 		return 1
 	}
 
-	workspace := args[0]
 	exists := false
 	for _, ws := range workspaces {
 		if workspace == ws {
@@ -58,10 +58,16 @@ func (c *WorkspaceSelectCommand) Run(args []string) int {
 		return 1
 	}
 
-	// Load the backend
-	b, backendDiags := c.Backend(&BackendOpts{
+	name := args[0]
+	if !validWorkspaceName(name) {
+		c.Ui.Error(fmt.Sprintf(envInvalidName, name))
+		return 1
+	}
+
+	// Load the backend that the selected workspace's state is stored in
+	b, backendDiags := c.workspaceBackend(&BackendOpts{
 		Config: backendConfig,
-	})
+	}, name)
 	diags = diags.Append(backendDiags)
 	if backendDiags.HasErrors() {
 		c.showDiagnostics(diags)
@@ -76,12 +82,6 @@ func (c *WorkspaceSelectCommand) Run(args []string) int {
 	// This command will not write state
 	c.ignoreRemoteVersionConflict(b)
 
-	name := args[0]
-	if !validWorkspaceName(name) {
-		c.Ui.Error(fmt.Sprintf(envInvalidName, name))
-		return 1
-	}
-
 	states, err := b.Workspaces()
 	if err != nil {
 		c.Ui.Error(err.Error())
@@ -4,19 +4,56 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/posener/complete"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	backendInit "github.com/opentofu/opentofu/internal/backend/init"
+	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
 type WorkspaceShowCommand struct {
 	Meta
 }
 
+// workspaceShowFormatVersion is the version of the JSON output of
+// "tofu workspace show -json".
+const workspaceShowFormatVersion = "1.0"
+
+// workspaceShowJSON is the JSON output of "tofu workspace show -json".
+type workspaceShowJSON struct {
+	FormatVersion string `json:"format_version"`
+
+	// Workspace is the name of the current workspace, and Overridden is true
+	// if it was selected with the TF_WORKSPACE environment variable.
+	Workspace  string `json:"workspace"`
+	Overridden bool   `json:"overridden"`
+
+	// VarFiles are the variable files named after the workspace that are
+	// loaded automatically.
+	VarFiles []string `json:"var_files"`
+
+	// Backend is the effective backend configuration for the workspace, or
+	// nil if the working directory hasn't been initialized with a backend.
+	Backend *workspaceShowBackendJSON `json:"backend"`
+}
+
+type workspaceShowBackendJSON struct {
+	Type   string                     `json:"type"`
+	Config map[string]json.RawMessage `json:"config"`
+}
+
 func (c *WorkspaceShowCommand) Run(args []string) int {
 	args = c.Meta.process(args)
+	var jsonOutput bool
 	cmdFlags := c.Meta.extendedFlagSet("workspace show")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -28,24 +65,118 @@ func (c *WorkspaceShowCommand) Run(args []string) int {
 		c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
 		return 1
 	}
-	c.Ui.Output(workspace)
+
+	if !jsonOutput {
+		c.Ui.Output(workspace)
+		return 0
+	}
+
+	output, diags := c.workspaceSettings(workspace)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+	raw, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error marshalling JSON: %s", err))
+		return 1
+	}
+	c.Ui.Output(string(raw))
 
 	return 0
 }
 
+// workspaceSettings returns the effective settings of the given workspace.
+func (c *WorkspaceShowCommand) workspaceSettings(workspace string) (*workspaceShowJSON, tfdiags.Diagnostics) {
+	_, overridden := c.WorkspaceOverridden()
+	ret := &workspaceShowJSON{
+		FormatVersion: workspaceShowFormatVersion,
+		Workspace:     workspace,
+		Overridden:    overridden,
+		VarFiles:      []string{},
+	}
+
+	ws, diags := c.workspacesConfig()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	ret.VarFiles = append(ret.VarFiles, workspaceVarFiles(ws, workspace)...)
+
+	// The backend configuration is the one saved by the most recent run of
+	// "tofu init", which includes any -backend-config arguments, with the
+	// overrides for the workspace applied.
+	sMgr := &clistate.LocalState{Path: filepath.Join(c.DataDir(), DefaultStateFilename)}
+	if err := sMgr.RefreshState(); err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to load state: %w", err))
+		return nil, diags
+	}
+	s := sMgr.State()
+	if s == nil || s.Backend.Empty() {
+		return ret, diags
+	}
+
+	f := backendInit.Backend(s.Backend.Type)
+	if f == nil {
+		diags = diags.Append(fmt.Errorf(strings.TrimSpace(errBackendSavedUnknown), s.Backend.Type))
+		return nil, diags
+	}
+	// We only need the backend's schema here, so encryption is irrelevant.
+	schema := f(encryption.StateEncryptionDisabled()).ConfigSchema()
+	configVal, err := s.Backend.Config(schema)
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to decode current backend config: %w", err))
+		return nil, diags
+	}
+	configVal, moreDiags := c.workspaceBackendConfig(configVal, schema, workspace)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+
+	ret.Backend = &workspaceShowBackendJSON{
+		Type:   s.Backend.Type,
+		Config: make(map[string]json.RawMessage),
+	}
+	for name, v := range configVal.AsValueMap() {
+		if v.IsNull() {
+			continue
+		}
+		if attrS, exists := schema.Attributes[name]; exists && attrS.Sensitive {
+			ret.Backend.Config[name] = json.RawMessage(`"(sensitive value)"`)
+			continue
+		}
+		raw, err := ctyjson.Marshal(v, v.Type())
+		if err != nil {
+			diags = diags.Append(fmt.Errorf("Failed to encode backend config: %w", err))
+			return nil, diags
+		}
+		ret.Backend.Config[name] = raw
+	}
+
+	return ret, diags
+}
+
 func (c *WorkspaceShowCommand) AutocompleteArgs() complete.Predictor {
 	return complete.PredictNothing
 }
 
 func (c *WorkspaceShowCommand) AutocompleteFlags() complete.Flags {
-	return nil
+	return complete.Flags{
+		"-json": complete.PredictNothing,
+	}
 }
 
 func (c *WorkspaceShowCommand) Help() string {
 	helpText := `
-Usage: tofu [global options] workspace show
+Usage: tofu [global options] workspace show [options]
 
   Show the name of the current workspace.
+
+Options:
+
+  -json       Show the effective settings of the current workspace as a JSON
+              object, including the variable files named after it and its
+              backend configuration.
 `
 	return strings.TrimSpace(helpText)
 }
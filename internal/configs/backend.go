@@ -4,8 +4,9 @@
 package configs
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -16,6 +17,10 @@ type Backend struct {
 	Type   string
 	Config hcl.Body
 
+	// Eval resolves any references to variables and locals in Config. It's
+	// set once the containing module is complete.
+	Eval *StaticEvaluator
+
 	TypeRange hcl.Range
 	DeclRange hcl.Range
 }
@@ -29,6 +34,13 @@ func decodeBackendBlock(block *hcl.Block) (*Backend, hcl.Diagnostics) {
 	}, nil
 }
 
+// Decode decodes the backend configuration using the given schema, statically
+// evaluating any references to variables and locals.
+func (b *Backend) Decode(schema *configschema.Block) (cty.Value, hcl.Diagnostics) {
+	ident := b.Eval.Ident(fmt.Sprintf("backend %q", b.Type), b.DeclRange)
+	return b.Eval.DecodeBlock(b.Config, schema.DecoderSpec(), ident)
+}
+
 // Hash produces a hash value for the reciever that covers the type and the
 // portions of the config that conform to the given schema.
 //
@@ -43,8 +55,7 @@ func (b *Backend) Hash(schema *configschema.Block) int {
 	// Don't fail if required attributes are not set. Instead, we'll just
 	// hash them as nulls.
 	schema = schema.NoneRequired()
-	spec := schema.DecoderSpec()
-	val, _ := hcldec.Decode(b.Config, spec, nil)
+	val, _ := b.Decode(schema)
 	if val == cty.NilVal {
 		val = cty.UnknownVal(schema.ImpliedType())
 	}
@@ -12,6 +12,10 @@ import (
 type CloudConfig struct {
 	Config hcl.Body
 
+	// Eval resolves any references to variables and locals in Config. It's
+	// set once the containing module is complete.
+	Eval *StaticEvaluator
+
 	DeclRange hcl.Range
 }
 
@@ -24,7 +28,9 @@ func decodeCloudBlock(block *hcl.Block) (*CloudConfig, hcl.Diagnostics) {
 
 func (c *CloudConfig) ToBackendConfig() Backend {
 	return Backend{
-		Type:   "cloud",
-		Config: c.Config,
+		Type:      "cloud",
+		Config:    c.Config,
+		Eval:      c.Eval,
+		DeclRange: c.DeclRange,
 	}
 }
@@ -868,6 +868,35 @@ func (c *Config) CheckCoreVersionRequirements() hcl.Diagnostics {
 	return diags
 }
 
+// CopyForTest returns a copy of the configuration tree that TransformForTest
+// and the test command can modify without affecting the receiver, so that
+// test run blocks can execute concurrently against the same configuration.
+//
+// Only the parts of each module that are modified for testing are copied,
+// everything else is shared with the receiver.
+func (c *Config) CopyForTest() *Config {
+	return c.copyForTest(nil, nil)
+}
+
+func (c *Config) copyForTest(root, parent *Config) *Config {
+	ret := new(Config)
+	*ret = *c
+
+	if root == nil {
+		root = ret
+	}
+	ret.Root = root
+	ret.Parent = parent
+	ret.Module = c.Module.copyForTest()
+
+	ret.Children = make(map[string]*Config, len(c.Children))
+	for name, child := range c.Children {
+		ret.Children[name] = child.copyForTest(root, ret)
+	}
+
+	return ret
+}
+
 // TransformForTest prepares the config to execute the given test.
 //
 // This function directly edits the config that is to be tested, and returns a
@@ -878,7 +907,8 @@ func (c *Config) CheckCoreVersionRequirements() hcl.Diagnostics {
 func (c *Config) TransformForTest(run *TestRun, file *TestFile) (func(), hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
-	// Currently, we only need to override the provider settings.
+	// We need to override the provider settings, and then apply any
+	// overrides for resources and modules.
 	//
 	// We can have a set of providers defined within the config, we can also
 	// have a set of providers defined within the test file. Then the run can
@@ -934,13 +964,15 @@ func (c *Config) TransformForTest(run *TestRun, file *TestFile) (func(), hcl.Dia
 			}
 
 			next[ref.InChild.String()] = &Provider{
-				Name:       ref.InChild.Name,
-				NameRange:  ref.InChild.NameRange,
-				Alias:      ref.InChild.Alias,
-				AliasRange: ref.InChild.AliasRange,
-				Version:    testProvider.Version,
-				Config:     testProvider.Config,
-				DeclRange:  testProvider.DeclRange,
+				Name:          ref.InChild.Name,
+				NameRange:     ref.InChild.NameRange,
+				Alias:         ref.InChild.Alias,
+				AliasRange:    ref.InChild.AliasRange,
+				Version:       testProvider.Version,
+				Config:        testProvider.Config,
+				DeclRange:     testProvider.DeclRange,
+				IsMocked:      testProvider.IsMocked,
+				MockResources: testProvider.MockResources,
 			}
 
 		}
@@ -953,8 +985,15 @@ func (c *Config) TransformForTest(run *TestRun, file *TestFile) (func(), hcl.Dia
 	}
 
 	c.Module.ProviderConfigs = next
+
+	// Finally, we mark any resources and modules targeted by override blocks
+	// so that the real providers are never called for them.
+	resetOverrides, overrideDiags := c.transformOverridesForTest(run, file)
+	diags = append(diags, overrideDiags...)
+
 	return func() {
 		// Reset the original config within the returned function.
 		c.Module.ProviderConfigs = previous
+		resetOverrides()
 	}, diags
 }
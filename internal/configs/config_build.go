@@ -80,6 +80,7 @@ func buildTestModules(root *Config, walker ModuleWalker) hcl.Diagnostics {
 				VersionConstraint: run.Module.Version,
 				Parent:            root,
 				CallRange:         run.Module.DeclRange,
+				Call:              NewStaticModuleCall(path, nil),
 			}
 
 			cfg, modDiags := loadModule(root, &req, walker)
@@ -141,6 +142,7 @@ func buildChildModules(parent *Config, walker ModuleWalker) (map[string]*Config,
 			VersionConstraint: call.Version,
 			Parent:            parent,
 			CallRange:         call.DeclRange,
+			Call:              NewStaticModuleCall(path, call.staticVariables(parent.Module.StaticEvaluator)),
 		}
 		child, modDiags := loadModule(parent.Root, &req, walker)
 		diags = append(diags, modDiags...)
@@ -297,6 +299,11 @@ type ModuleRequest struct {
 	// subject of an error diagnostic that relates to the module call itself,
 	// rather than to either its source address or its version number.
 	CallRange hcl.Range
+
+	// Call describes how to find the values of the module's input variables
+	// for static evaluation, and should be passed to the parser when the
+	// module is loaded.
+	Call StaticModuleCall
 }
 
 // DisabledModuleWalker is a ModuleWalker that doesn't support
@@ -603,6 +603,52 @@ Use the provider argument in the target resource block to configure the provider
 	})
 }
 
+func TestConfigCopyForTest(t *testing.T) {
+	parse := func(t *testing.T, name, content string) *Provider {
+		t.Helper()
+
+		file, diags := hclparse.NewParser().ParseHCL([]byte(content), name+".hcl")
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		return &Provider{Name: name, Config: file.Body}
+	}
+
+	original := parse(t, "foo", `source = "config"`)
+	config := &Config{
+		Module: &Module{
+			ProviderConfigs: map[string]*Provider{
+				"foo": original,
+			},
+		},
+	}
+	config.Root = config
+
+	file := &TestFile{
+		Providers: map[string]*Provider{
+			"foo": parse(t, "foo", `source = "testfile"`),
+		},
+	}
+
+	cpy := config.CopyForTest()
+	if cpy.Root != cpy {
+		t.Errorf("copied config should be its own root")
+	}
+
+	// Transform the copy without resetting it, the original configuration
+	// must not observe any of the changes.
+	if _, diags := cpy.TransformForTest(&TestRun{}, file); diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	if cpy.Module.ProviderConfigs["foo"] == original {
+		t.Errorf("expected the copy to use the provider from the test file")
+	}
+	if config.Module.ProviderConfigs["foo"] != original {
+		t.Errorf("transforming the copy modified the original configuration")
+	}
+}
+
 func TestTransformForTest(t *testing.T) {
 
 	str := func(providers map[string]string) string {
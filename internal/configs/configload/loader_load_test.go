@@ -25,7 +25,7 @@ func TestLoaderLoadConfig_okay(t *testing.T) {
 		t.Fatalf("unexpected error from NewLoader: %s", err)
 	}
 
-	cfg, diags := loader.LoadConfig(fixtureDir)
+	cfg, diags := loader.LoadConfig(fixtureDir, configs.RootModuleCallForTesting())
 	assertNoDiagnostics(t, diags)
 	if cfg == nil {
 		t.Fatalf("config is nil; want non-nil")
@@ -73,7 +73,7 @@ func TestLoaderLoadConfig_addVersion(t *testing.T) {
 		t.Fatalf("unexpected error from NewLoader: %s", err)
 	}
 
-	_, diags := loader.LoadConfig(fixtureDir)
+	_, diags := loader.LoadConfig(fixtureDir, configs.RootModuleCallForTesting())
 	if !diags.HasErrors() {
 		t.Fatalf("success; want error")
 	}
@@ -94,7 +94,7 @@ func TestLoaderLoadConfig_loadDiags(t *testing.T) {
 		t.Fatalf("unexpected error from NewLoader: %s", err)
 	}
 
-	cfg, diags := loader.LoadConfig(fixtureDir)
+	cfg, diags := loader.LoadConfig(fixtureDir, configs.RootModuleCallForTesting())
 	if !diags.HasErrors() {
 		t.Fatal("success; want error")
 	}
@@ -118,7 +118,7 @@ func TestLoaderLoadConfig_loadDiagsFromSubmodules(t *testing.T) {
 		t.Fatalf("unexpected error from NewLoader: %s", err)
 	}
 
-	cfg, diags := loader.LoadConfig(fixtureDir)
+	cfg, diags := loader.LoadConfig(fixtureDir, configs.RootModuleCallForTesting())
 	if !diags.HasErrors() {
 		t.Fatalf("loading succeeded; want an error")
 	}
@@ -168,7 +168,7 @@ func TestLoaderLoadConfig_childProviderGrandchildCount(t *testing.T) {
 			t.Fatalf("unexpected error from NewLoader: %s", err)
 		}
 
-		cfg, diags := loader.LoadConfig(fixtureDir)
+		cfg, diags := loader.LoadConfig(fixtureDir, configs.RootModuleCallForTesting())
 		assertNoDiagnostics(t, diags)
 		if cfg == nil {
 			t.Fatalf("config is nil; want non-nil")
@@ -198,7 +198,7 @@ func TestLoaderLoadConfig_childProviderGrandchildCount(t *testing.T) {
 			t.Fatalf("unexpected error from NewLoader: %s", err)
 		}
 
-		_, diags := loader.LoadConfig(fixtureDir)
+		_, diags := loader.LoadConfig(fixtureDir, configs.RootModuleCallForTesting())
 		if !diags.HasErrors() {
 			t.Fatalf("loading succeeded; want an error")
 		}
@@ -0,0 +1,332 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// Encryption represents an "encryption" block inside a "terraform" block in a
+// module or file.
+//
+// The configs package only deals with the structure of the block and with
+// references between its parts. The type-specific arguments of key providers
+// and methods are kept as undecoded bodies so that package encryption can
+// interpret them.
+type Encryption struct {
+	KeyProviders []*EncryptionKeyProvider
+	Methods      []*EncryptionMethod
+
+	// State and Plan describe how state snapshots and saved plan files
+	// respectively are to be protected. Either can be nil, in which case
+	// the corresponding artifact is written without encryption.
+	State *EncryptionTarget
+	Plan  *EncryptionTarget
+
+	// RemoteState describes how state snapshots read by
+	// terraform_remote_state data sources are decrypted. If nil, those
+	// snapshots must not be encrypted.
+	RemoteState *EncryptionTarget
+
+	DeclRange hcl.Range
+}
+
+// EncryptionKeyProvider represents a "key_provider" block inside an
+// "encryption" block.
+type EncryptionKeyProvider struct {
+	Type   string
+	Name   string
+	Config hcl.Body
+
+	// Eval resolves any references to variables and locals in Config. It's
+	// set once the containing module is complete.
+	Eval *StaticEvaluator
+
+	DeclRange hcl.Range
+}
+
+// Addr returns the string used to refer to the key provider from elsewhere
+// in the encryption block, such as "key_provider.pbkdf2.main".
+func (p *EncryptionKeyProvider) Addr() string {
+	return fmt.Sprintf("key_provider.%s.%s", p.Type, p.Name)
+}
+
+// DecodeConfig decodes the key provider's type-specific arguments into val, in
+// the same way as gohcl.DecodeBody, statically evaluating any references to
+// variables and locals. Values of sensitive variables are allowed, so that
+// secrets such as passphrases don't need to be written in the configuration.
+func (p *EncryptionKeyProvider) DecodeConfig(val any) hcl.Diagnostics {
+	return p.Eval.DecodeSecretBody(p.Config, p.Eval.Ident(p.Addr(), p.DeclRange), val)
+}
+
+// EncryptionMethod represents a "method" block inside an "encryption" block.
+type EncryptionMethod struct {
+	Type   string
+	Name   string
+	Config hcl.Body
+
+	DeclRange hcl.Range
+}
+
+// Addr returns the string used to refer to the method from elsewhere in the
+// encryption block, such as "method.aes_gcm.main".
+func (m *EncryptionMethod) Addr() string {
+	return fmt.Sprintf("method.%s.%s", m.Type, m.Name)
+}
+
+// EncryptionTarget represents a "state", "plan" or "remote_state" block inside
+// an "encryption" block, or a "fallback" block nested inside one of those.
+type EncryptionTarget struct {
+	// Method is the method used to encrypt new data and the first method
+	// tried when decrypting existing data.
+	Method *EncryptionMethod
+
+	// Enforced, when set, forbids the use of the "unencrypted" method as
+	// the primary method of this target.
+	Enforced bool
+
+	// Fallback, if non-nil, is tried when decrypting data that could not
+	// be decrypted using Method. This allows rotating keys or migrating
+	// between encrypted and unencrypted data without downtime.
+	Fallback *EncryptionTarget
+
+	DeclRange hcl.Range
+}
+
+func decodeEncryptionBlock(block *hcl.Block) (*Encryption, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	enc := &Encryption{
+		DeclRange: block.DefRange,
+	}
+
+	content, moreDiags := block.Body.Content(encryptionBlockSchema)
+	diags = append(diags, moreDiags...)
+
+	keyProviders := map[string]*EncryptionKeyProvider{}
+	methods := map[string]*EncryptionMethod{}
+	var targetBlocks []*hcl.Block
+
+	for _, inner := range content.Blocks {
+		switch inner.Type {
+		case "key_provider":
+			kp := &EncryptionKeyProvider{
+				Type:      inner.Labels[0],
+				Name:      inner.Labels[1],
+				Config:    inner.Body,
+				DeclRange: inner.DefRange,
+			}
+			diags = append(diags, checkEncryptionBlockLabels(inner)...)
+			if existing, exists := keyProviders[kp.Addr()]; exists {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate key_provider block",
+					Detail:   fmt.Sprintf("A key provider named %q of type %q was already declared at %s. Key provider names must be unique per type.", kp.Name, kp.Type, existing.DeclRange),
+					Subject:  &kp.DeclRange,
+				})
+				continue
+			}
+			keyProviders[kp.Addr()] = kp
+			enc.KeyProviders = append(enc.KeyProviders, kp)
+
+		case "method":
+			m := &EncryptionMethod{
+				Type:      inner.Labels[0],
+				Name:      inner.Labels[1],
+				Config:    inner.Body,
+				DeclRange: inner.DefRange,
+			}
+			diags = append(diags, checkEncryptionBlockLabels(inner)...)
+			if existing, exists := methods[m.Addr()]; exists {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate method block",
+					Detail:   fmt.Sprintf("A method named %q of type %q was already declared at %s. Method names must be unique per type.", m.Name, m.Type, existing.DeclRange),
+					Subject:  &m.DeclRange,
+				})
+				continue
+			}
+			methods[m.Addr()] = m
+			enc.Methods = append(enc.Methods, m)
+
+		case "state", "plan", "remote_state":
+			// Targets refer to methods, so we decode them once we've seen
+			// all of the method blocks.
+			targetBlocks = append(targetBlocks, inner)
+		}
+	}
+
+	for _, inner := range targetBlocks {
+		target, moreDiags := decodeEncryptionTargetBlock(inner, methods)
+		diags = append(diags, moreDiags...)
+
+		var existing **EncryptionTarget
+		switch inner.Type {
+		case "state":
+			existing = &enc.State
+		case "plan":
+			existing = &enc.Plan
+		case "remote_state":
+			existing = &enc.RemoteState
+		}
+		if *existing != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Duplicate %s block", inner.Type),
+				Detail:   fmt.Sprintf("An encryption block may have only one %q block. It was previously declared at %s.", inner.Type, (*existing).DeclRange),
+				Subject:  &inner.DefRange,
+			})
+			continue
+		}
+		*existing = target
+	}
+
+	return enc, diags
+}
+
+func decodeEncryptionTargetBlock(block *hcl.Block, methods map[string]*EncryptionMethod) (*EncryptionTarget, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	target := &EncryptionTarget{
+		DeclRange: block.DefRange,
+	}
+
+	schema := encryptionTargetBlockSchema
+	if block.Type == "fallback" {
+		schema = encryptionFallbackBlockSchema
+	}
+	content, moreDiags := block.Body.Content(schema)
+	diags = append(diags, moreDiags...)
+
+	if attr, exists := content.Attributes["method"]; exists {
+		traversal, travDiags := hcl.AbsTraversalForExpr(attr.Expr)
+		diags = append(diags, travDiags...)
+		if !travDiags.HasErrors() {
+			addr, ok := ParseEncryptionReference(traversal, "method")
+			switch {
+			case !ok:
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid encryption method reference",
+					Detail:   "The method argument must be a reference to a method block, like method.aes_gcm.example.",
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			case methods[addr] == nil:
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Reference to undeclared encryption method",
+					Detail:   fmt.Sprintf("There is no method block declared as %s in this encryption block.", addr),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			default:
+				target.Method = methods[addr]
+			}
+		}
+	}
+
+	if attr, exists := content.Attributes["enforced"]; exists {
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, nil, &target.Enforced)...)
+	}
+
+	for _, inner := range content.Blocks {
+		if target.Fallback != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate fallback block",
+				Detail:   fmt.Sprintf("Only one fallback block is allowed. It was previously declared at %s.", target.Fallback.DeclRange),
+				Subject:  &inner.DefRange,
+			})
+			continue
+		}
+		fallback, moreDiags := decodeEncryptionTargetBlock(inner, methods)
+		diags = append(diags, moreDiags...)
+		target.Fallback = fallback
+	}
+
+	return target, diags
+}
+
+// ParseEncryptionReference interprets a traversal of the form kind.type.name,
+// such as method.aes_gcm.example, returning it in its string form if it is
+// valid. Package encryption uses this to interpret references in the
+// type-specific arguments of methods.
+func ParseEncryptionReference(traversal hcl.Traversal, kind string) (string, bool) {
+	if len(traversal) != 3 || traversal.RootName() != kind {
+		return "", false
+	}
+	typeStep, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+	nameStep, ok := traversal[2].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("%s.%s.%s", kind, typeStep.Name, nameStep.Name), true
+}
+
+func checkEncryptionBlockLabels(block *hcl.Block) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for i, label := range block.Labels {
+		if !hclsyntax.ValidIdentifier(label) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Invalid %s block label", block.Type),
+				Detail:   badIdentifierDetail,
+				Subject:  &block.LabelRanges[i],
+			})
+		}
+	}
+	return diags
+}
+
+var encryptionBlockSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "key_provider",
+			LabelNames: []string{"type", "name"},
+		},
+		{
+			Type:       "method",
+			LabelNames: []string{"type", "name"},
+		},
+		{
+			Type: "state",
+		},
+		{
+			Type: "plan",
+		},
+		{
+			Type: "remote_state",
+		},
+	},
+}
+
+var encryptionTargetBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name:     "method",
+			Required: true,
+		},
+		{
+			Name: "enforced",
+		},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "fallback",
+		},
+	},
+}
+
+var encryptionFallbackBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name:     "method",
+			Required: true,
+		},
+	},
+}
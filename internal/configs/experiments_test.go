@@ -26,7 +26,7 @@ func TestExperimentsConfig(t *testing.T) {
 	t.Run("current", func(t *testing.T) {
 		parser := NewParser(nil)
 		parser.AllowLanguageExperiments(true)
-		mod, diags := parser.LoadConfigDir("testdata/experiments/current")
+		mod, diags := parser.LoadConfigDir("testdata/experiments/current", RootModuleCallForTesting())
 		if got, want := len(diags), 1; got != want {
 			t.Fatalf("wrong number of diagnostics %d; want %d", got, want)
 		}
@@ -54,7 +54,7 @@ func TestExperimentsConfig(t *testing.T) {
 	t.Run("concluded", func(t *testing.T) {
 		parser := NewParser(nil)
 		parser.AllowLanguageExperiments(true)
-		_, diags := parser.LoadConfigDir("testdata/experiments/concluded")
+		_, diags := parser.LoadConfigDir("testdata/experiments/concluded", RootModuleCallForTesting())
 		if got, want := len(diags), 1; got != want {
 			t.Fatalf("wrong number of diagnostics %d; want %d", got, want)
 		}
@@ -76,7 +76,7 @@ func TestExperimentsConfig(t *testing.T) {
 	t.Run("concluded", func(t *testing.T) {
 		parser := NewParser(nil)
 		parser.AllowLanguageExperiments(true)
-		_, diags := parser.LoadConfigDir("testdata/experiments/unknown")
+		_, diags := parser.LoadConfigDir("testdata/experiments/unknown", RootModuleCallForTesting())
 		if got, want := len(diags), 1; got != want {
 			t.Fatalf("wrong number of diagnostics %d; want %d", got, want)
 		}
@@ -98,7 +98,7 @@ func TestExperimentsConfig(t *testing.T) {
 	t.Run("invalid", func(t *testing.T) {
 		parser := NewParser(nil)
 		parser.AllowLanguageExperiments(true)
-		_, diags := parser.LoadConfigDir("testdata/experiments/invalid")
+		_, diags := parser.LoadConfigDir("testdata/experiments/invalid", RootModuleCallForTesting())
 		if got, want := len(diags), 1; got != want {
 			t.Fatalf("wrong number of diagnostics %d; want %d", got, want)
 		}
@@ -120,7 +120,7 @@ func TestExperimentsConfig(t *testing.T) {
 	t.Run("disallowed", func(t *testing.T) {
 		parser := NewParser(nil)
 		parser.AllowLanguageExperiments(false) // The default situation for release builds
-		_, diags := parser.LoadConfigDir("testdata/experiments/current")
+		_, diags := parser.LoadConfigDir("testdata/experiments/current", RootModuleCallForTesting())
 		if got, want := len(diags), 1; got != want {
 			t.Fatalf("wrong number of diagnostics %d; want %d", got, want)
 		}
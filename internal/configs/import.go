@@ -5,13 +5,29 @@ package configs
 
 import (
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 )
 
 type Import struct {
 	ID hcl.Expression
+
+	// To is the address of the resource instance to import into. If ForEach
+	// is set then any instance keys in the "to" argument that aren't
+	// constant are omitted from To, and the full address of each instance
+	// must be found by calling EvalTo with each.key and each.value in scope.
 	To addrs.AbsResourceInstance
 
+	// ToExpr is the original expression given in the "to" argument. It's
+	// retained only when ForEach is set, for use by EvalTo.
+	ToExpr hcl.Expression
+
+	// ForEach is the expression given in the optional for_each argument,
+	// or nil if it wasn't set.
+	ForEach hcl.Expression
+
 	ProviderConfigRef *ProviderConfigRef
 	Provider          addrs.Provider
 
@@ -32,8 +48,23 @@ func decodeImportBlock(block *hcl.Block) (*Import, hcl.Diagnostics) {
 		imp.ID = attr.Expr
 	}
 
+	if attr, exists := content.Attributes["for_each"]; exists {
+		imp.ForEach = attr.Expr
+	}
+
 	if attr, exists := content.Attributes["to"]; exists {
-		traversal, traversalDiags := hcl.AbsTraversalForExpr(attr.Expr)
+		var traversal hcl.Traversal
+		var traversalDiags hcl.Diagnostics
+		if imp.ForEach != nil {
+			imp.ToExpr = attr.Expr
+
+			// With for_each the instance keys may refer to each.key and
+			// each.value, so we can only decode the static part of the
+			// address until the keys are evaluated.
+			traversal, traversalDiags = importToTraversal(attr.Expr, nil)
+		} else {
+			traversal, traversalDiags = hcl.AbsTraversalForExpr(attr.Expr)
+		}
 		diags = append(diags, traversalDiags...)
 		if !traversalDiags.HasErrors() {
 			to, toDiags := addrs.ParseAbsResourceInstance(traversal)
@@ -56,16 +87,119 @@ func decodeImportBlock(block *hcl.Block) (*Import, hcl.Diagnostics) {
 		imp.ProviderConfigRef, providerDiags = decodeProviderConfigRef(attr.Expr, "provider")
 		imp.ProviderDeclRange = attr.Range
 		diags = append(diags, providerDiags...)
+		if imp.ProviderConfigRef != nil && imp.ProviderConfigRef.KeyExpression != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid import provider argument",
+				Detail:   "The provider argument of an import block cannot select an instance of a provider configuration with for_each.",
+				Subject:  imp.ProviderConfigRef.KeyExpression.Range().Ptr(),
+			})
+		}
 	}
 
 	return imp, diags
 }
 
+// EvalTo returns the full address of the resource instance to import into,
+// evaluating any instance keys in the "to" argument in the given context.
+//
+// This is only needed for import blocks that use for_each, since otherwise
+// the address is always static and available as To.
+func (i *Import) EvalTo(ctx *hcl.EvalContext) (addrs.AbsResourceInstance, hcl.Diagnostics) {
+	traversal, diags := importToTraversal(i.ToExpr, ctx)
+	if diags.HasErrors() {
+		return addrs.AbsResourceInstance{}, diags
+	}
+	to, toDiags := addrs.ParseAbsResourceInstance(traversal)
+	diags = append(diags, toDiags.ToHCL()...)
+	return to, diags
+}
+
+// ToKeyExprs returns the expressions for any instance keys in the "to"
+// argument that aren't constant, which are the parts of the address that
+// EvalTo evaluates. It returns nil if ForEach isn't set.
+func (i *Import) ToKeyExprs() []hcl.Expression {
+	var ret []hcl.Expression
+	expr := i.ToExpr
+	for expr != nil {
+		switch e := expr.(type) {
+		case *hclsyntax.RelativeTraversalExpr:
+			expr = e.Source
+		case *hclsyntax.IndexExpr:
+			ret = append(ret, e.Key)
+			expr = e.Collection
+		default:
+			expr = nil
+		}
+	}
+	return ret
+}
+
+// importToTraversal converts the "to" expression of an import block into an
+// absolute traversal, evaluating any instance keys that aren't constant in
+// the given context. If ctx is nil then such keys are left out of the result
+// instead.
+func importToTraversal(expr hcl.Expression, ctx *hcl.EvalContext) (hcl.Traversal, hcl.Diagnostics) {
+	switch e := expr.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		return e.Traversal, nil
+
+	case *hclsyntax.RelativeTraversalExpr:
+		traversal, diags := importToTraversal(e.Source, ctx)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return append(traversal, e.Traversal...), diags
+
+	case *hclsyntax.IndexExpr:
+		traversal, diags := importToTraversal(e.Collection, ctx)
+		if diags.HasErrors() || ctx == nil {
+			return traversal, diags
+		}
+
+		key, keyDiags := e.Key.Value(ctx)
+		diags = append(diags, keyDiags...)
+		if keyDiags.HasErrors() {
+			return nil, diags
+		}
+		if key.IsMarked() || !key.IsKnown() || key.IsNull() {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid import address",
+				Detail:   "An instance key in the \"to\" argument must be a known, non-null and non-sensitive value.",
+				Subject:  e.Key.Range().Ptr(),
+			})
+			return nil, diags
+		}
+		if key.Type() != cty.String && key.Type() != cty.Number {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid import address",
+				Detail:   "An instance key in the \"to\" argument must be a string or a number.",
+				Subject:  e.Key.Range().Ptr(),
+			})
+			return nil, diags
+		}
+		return append(traversal, hcl.TraverseIndex{
+			Key:      key,
+			SrcRange: e.Key.Range(),
+		}), diags
+
+	default:
+		// Anything else must be a plain static address, such as in the
+		// JSON syntax.
+		return hcl.AbsTraversalForExpr(expr)
+	}
+}
+
 var importBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{
 		{
 			Name: "provider",
 		},
+		{
+			Name: "for_each",
+		},
 		{
 			Name:     "id",
 			Required: true,
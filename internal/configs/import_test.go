@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hcltest"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/zclconf/go-cty/cty"
@@ -179,3 +180,52 @@ func mustAbsResourceInstanceAddr(str string) addrs.AbsResourceInstance {
 	}
 	return addr
 }
+
+func TestImportBlock_forEach(t *testing.T) {
+	src := `
+import {
+  for_each = var.ids
+  to       = module.child[each.key].test_instance.bar[each.value]
+  id       = each.value
+}
+`
+	file, diags := hclsyntax.ParseConfig([]byte(src), "mock.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	content, diags := file.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "import"}},
+	})
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	got, diags := decodeImportBlock(content.Blocks[0])
+	if diags.HasErrors() {
+		t.Fatalf("unexpected error: %s", diags.Error())
+	}
+	if got.ForEach == nil {
+		t.Fatal("for_each was not decoded")
+	}
+	if want := mustAbsResourceInstanceAddr("module.child.test_instance.bar"); !got.To.Equal(want) {
+		t.Errorf("wrong static address\ngot:  %s\nwant: %s", got.To, want)
+	}
+	if got, want := len(got.ToKeyExprs()), 2; got != want {
+		t.Errorf("wrong number of key expressions %d; want %d", got, want)
+	}
+
+	to, diags := got.EvalTo(&hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"each": cty.ObjectVal(map[string]cty.Value{
+				"key":   cty.StringVal("a"),
+				"value": cty.NumberIntVal(1),
+			}),
+		},
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected error: %s", diags.Error())
+	}
+	if want := mustAbsResourceInstanceAddr(`module.child["a"].test_instance.bar[1]`); !to.Equal(want) {
+		t.Errorf("wrong address\ngot:  %s\nwant: %s", to, want)
+	}
+}
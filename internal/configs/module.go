@@ -5,6 +5,7 @@ package configs
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/hashicorp/hcl/v2"
 
@@ -35,6 +36,8 @@ type Module struct {
 
 	Backend              *Backend
 	CloudConfig          *CloudConfig
+	Encryption           *Encryption
+	Workspaces           *Workspaces
 	ProviderConfigs      map[string]*Provider
 	ProviderRequirements *RequiredProviders
 	ProviderLocalNames   map[addrs.Provider]string
@@ -49,12 +52,20 @@ type Module struct {
 	ManagedResources map[string]*Resource
 	DataResources    map[string]*Resource
 
-	Moved  []*Moved
-	Import []*Import
+	Moved   []*Moved
+	Removed []*Removed
+	Import  []*Import
 
 	Checks map[string]*Check
 
 	Tests map[string]*TestFile
+
+	// StaticEvaluator evaluates the settings that must be known before any
+	// resources are, such as the backend configuration and module sources,
+	// in terms of the module's variables and locals.
+	StaticEvaluator *StaticEvaluator
+
+	staticCoreVersionAttrs []*hcl.Attribute
 }
 
 // File describes the contents of a single configuration file.
@@ -71,10 +82,17 @@ type Module struct {
 type File struct {
 	CoreVersionConstraints []VersionConstraint
 
+	// staticCoreVersionAttrs are the required_version arguments that refer
+	// to variables or locals, which are decoded into CoreVersionConstraints
+	// of the module once it's complete.
+	staticCoreVersionAttrs []*hcl.Attribute
+
 	ActiveExperiments experiments.Set
 
 	Backends          []*Backend
 	CloudConfigs      []*CloudConfig
+	Encryptions       []*Encryption
+	Workspaces        []*Workspaces
 	ProviderConfigs   []*Provider
 	ProviderMetas     []*ProviderMeta
 	RequiredProviders []*RequiredProviders
@@ -88,16 +106,17 @@ type File struct {
 	ManagedResources []*Resource
 	DataResources    []*Resource
 
-	Moved  []*Moved
-	Import []*Import
+	Moved   []*Moved
+	Removed []*Removed
+	Import  []*Import
 
 	Checks []*Check
 }
 
 // NewModuleWithTests matches NewModule except it will also load in the provided
 // test files.
-func NewModuleWithTests(primaryFiles, overrideFiles []*File, testFiles map[string]*TestFile) (*Module, hcl.Diagnostics) {
-	mod, diags := NewModule(primaryFiles, overrideFiles)
+func NewModuleWithTests(primaryFiles, overrideFiles []*File, testFiles map[string]*TestFile, call StaticModuleCall) (*Module, hcl.Diagnostics) {
+	mod, diags := NewModule(primaryFiles, overrideFiles, call)
 	if mod != nil {
 		mod.Tests = testFiles
 	}
@@ -112,7 +131,10 @@ func NewModuleWithTests(primaryFiles, overrideFiles []*File, testFiles map[strin
 // will be incomplete and error diagnostics will be returned. Careful static
 // analysis of the returned Module is still possible in this case, but the
 // module will probably not be semantically valid.
-func NewModule(primaryFiles, overrideFiles []*File) (*Module, hcl.Diagnostics) {
+//
+// The given call provides the values of the module's input variables for
+// static evaluation of settings such as module source addresses.
+func NewModule(primaryFiles, overrideFiles []*File, call StaticModuleCall) (*Module, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 	mod := &Module{
 		ProviderConfigs:    map[string]*Provider{},
@@ -178,9 +200,65 @@ func NewModule(primaryFiles, overrideFiles []*File) (*Module, hcl.Diagnostics) {
 	// Generate the FQN -> LocalProviderName map
 	mod.gatherProviderLocalNames()
 
+	// Now that all of the variables and locals are known, we can decode the
+	// settings that may statically refer to them.
+	mod.StaticEvaluator = NewStaticEvaluator(mod, call)
+	diags = append(diags, mod.decodeStaticFields()...)
+
 	return mod, diags
 }
 
+// decodeStaticFields decodes the parts of the module that were left pending
+// because they refer to variables or locals, evaluates the for_each arguments
+// of provider configurations, and prepares the backend and encryption key
+// provider configurations for static evaluation.
+func (m *Module) decodeStaticFields() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	eval := m.StaticEvaluator
+
+	for _, attr := range m.staticCoreVersionAttrs {
+		constraint, moreDiags := decodeStaticVersionConstraint(attr, eval, eval.Ident("terraform.required_version", attr.Range))
+		diags = append(diags, moreDiags...)
+		if !moreDiags.HasErrors() {
+			m.CoreVersionConstraints = append(m.CoreVersionConstraints, constraint)
+		}
+	}
+
+	names := make([]string, 0, len(m.ModuleCalls))
+	for name := range m.ModuleCalls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if mc := m.ModuleCalls[name]; mc.staticPending {
+			diags = append(diags, mc.decodeStaticFields(eval)...)
+		}
+	}
+
+	keys := make([]string, 0, len(m.ProviderConfigs))
+	for key := range m.ProviderConfigs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		diags = append(diags, m.ProviderConfigs[key].decodeStaticForEach(eval)...)
+	}
+
+	if m.Backend != nil {
+		m.Backend.Eval = eval
+	}
+	if m.CloudConfig != nil {
+		m.CloudConfig.Eval = eval
+	}
+	if m.Encryption != nil {
+		for _, kp := range m.Encryption.KeyProviders {
+			kp.Eval = eval
+		}
+	}
+
+	return diags
+}
+
 // ResourceByAddr returns the configuration for the resource with the given
 // address, or nil if there is no such resource.
 func (m *Module) ResourceByAddr(addr addrs.Resource) *Resource {
@@ -201,6 +279,7 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 	// If there are any conflicting requirements then we'll catch them
 	// when we actually check these constraints.
 	m.CoreVersionConstraints = append(m.CoreVersionConstraints, file.CoreVersionConstraints...)
+	m.staticCoreVersionAttrs = append(m.staticCoreVersionAttrs, file.staticCoreVersionAttrs...)
 
 	m.ActiveExperiments = experiments.SetUnion(m.ActiveExperiments, file.ActiveExperiments)
 
@@ -231,6 +310,21 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 		m.CloudConfig = c
 	}
 
+	for _, e := range file.Encryptions {
+		if m.Encryption != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate encryption configuration",
+				Detail:   fmt.Sprintf("A module may have only one encryption configuration. Encryption was previously configured at %s.", m.Encryption.DeclRange),
+				Subject:  &e.DeclRange,
+			})
+			continue
+		}
+		m.Encryption = e
+	}
+
+	diags = append(diags, m.appendWorkspaces(file)...)
+
 	if m.Backend != nil && m.CloudConfig != nil {
 		diags = append(diags, &hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -420,8 +514,17 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 	// runtime.)
 	m.Moved = append(m.Moved, file.Moved...)
 
+	// "Removed" blocks also just append, and are validated against the
+	// rest of the configuration once it's fully loaded.
+	m.Removed = append(m.Removed, file.Removed...)
+
 	for _, i := range file.Import {
 		for _, mi := range m.Import {
+			if i.ForEach != nil || mi.ForEach != nil {
+				// Import blocks using for_each can only be checked for
+				// duplicates once their addresses have been evaluated.
+				continue
+			}
 			if i.To.Equal(mi.To) {
 				diags = append(diags, &hcl.Diagnostic{
 					Severity: hcl.DiagError,
@@ -472,12 +575,14 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 func (m *Module) mergeFile(file *File) hcl.Diagnostics {
 	var diags hcl.Diagnostics
 
-	if len(file.CoreVersionConstraints) != 0 {
+	if len(file.CoreVersionConstraints) != 0 || len(file.staticCoreVersionAttrs) != 0 {
 		// This is a bit of a strange case for overriding since we normally
 		// would union together across multiple files anyway, but we'll
 		// allow it and have each override file clobber any existing list.
 		m.CoreVersionConstraints = nil
 		m.CoreVersionConstraints = append(m.CoreVersionConstraints, file.CoreVersionConstraints...)
+		m.staticCoreVersionAttrs = nil
+		m.staticCoreVersionAttrs = append(m.staticCoreVersionAttrs, file.staticCoreVersionAttrs...)
 	}
 
 	if len(file.Backends) != 0 {
@@ -514,6 +619,25 @@ func (m *Module) mergeFile(file *File) hcl.Diagnostics {
 		}
 	}
 
+	if len(file.Encryptions) != 0 {
+		switch len(file.Encryptions) {
+		case 1:
+			m.Encryption = file.Encryptions[0]
+		default:
+			// An override file with multiple encryption blocks is still
+			// invalid, even though it can override encryption blocks from
+			// _other_ files.
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate encryption configuration",
+				Detail:   fmt.Sprintf("Each override file may have only one encryption configuration. Encryption was previously configured at %s.", file.Encryptions[0].DeclRange),
+				Subject:  &file.Encryptions[1].DeclRange,
+			})
+		}
+	}
+
+	diags = append(diags, m.mergeWorkspaces(file)...)
+
 	for _, pc := range file.ProviderConfigs {
 		key := pc.moduleUniqueKey()
 		existing, exists := m.ProviderConfigs[key]
@@ -647,6 +771,15 @@ func (m *Module) mergeFile(file *File) hcl.Diagnostics {
 		})
 	}
 
+	for _, r := range file.Removed {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Cannot override 'removed' blocks",
+			Detail:   "Records of removed objects can appear only in normal files, not in override files.",
+			Subject:  r.DeclRange.Ptr(),
+		})
+	}
+
 	for _, m := range file.Import {
 		diags = append(diags, &hcl.Diagnostic{
 			Severity: hcl.DiagError,
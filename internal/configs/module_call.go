@@ -7,8 +7,8 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/getmodules"
@@ -35,6 +35,15 @@ type ModuleCall struct {
 	DependsOn []hcl.Traversal
 
 	DeclRange hcl.Range
+
+	// sourceAttr and versionAttr are the raw source and version arguments,
+	// which decodeStaticFields decodes into the fields above.
+	sourceAttr  *hcl.Attribute
+	versionAttr *hcl.Attribute
+
+	// staticPending is set if the source and version arguments refer to
+	// variables or locals, in which case they are decoded by NewModule.
+	staticPending bool
 }
 
 func decodeModuleBlock(block *hcl.Block, override bool) (*ModuleCall, hcl.Diagnostics) {
@@ -63,18 +72,103 @@ func decodeModuleBlock(block *hcl.Block, override bool) (*ModuleCall, hcl.Diagno
 		})
 	}
 
+	mc.versionAttr = content.Attributes["version"]
+	if attr, exists := content.Attributes["source"]; exists {
+		mc.SourceSet = true
+		mc.SourceAddrRange = attr.Expr.Range()
+		mc.sourceAttr = attr
+	}
+	if needsStaticEvaluation(mc.versionAttr) || needsStaticEvaluation(mc.sourceAttr) {
+		// These arguments refer to variables or locals, so they can only
+		// be decoded once the whole module is loaded.
+		mc.staticPending = true
+	} else {
+		diags = append(diags, mc.decodeStaticFields(nil)...)
+	}
+
+	if attr, exists := content.Attributes["count"]; exists {
+		mc.Count = attr.Expr
+	}
+
+	if attr, exists := content.Attributes["for_each"]; exists {
+		if mc.Count != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  `Invalid combination of "count" and "for_each"`,
+				Detail:   `The "count" and "for_each" meta-arguments are mutually-exclusive, only one should be used to be explicit about the number of resources to be created.`,
+				Subject:  &attr.NameRange,
+			})
+		}
+
+		mc.ForEach = attr.Expr
+	}
+
+	if attr, exists := content.Attributes["depends_on"]; exists {
+		deps, depsDiags := decodeDependsOn(attr)
+		diags = append(diags, depsDiags...)
+		mc.DependsOn = append(mc.DependsOn, deps...)
+	}
+
+	if attr, exists := content.Attributes["providers"]; exists {
+		providers, providerDiags := decodePassedProviderConfigs(attr)
+		diags = append(diags, providerDiags...)
+		mc.Providers = append(mc.Providers, providers...)
+	}
+
+	var seenEscapeBlock *hcl.Block
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "_":
+			if seenEscapeBlock != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate escaping block",
+					Detail: fmt.Sprintf(
+						"The special block type \"_\" can be used to force particular arguments to be interpreted as module input variables rather than as meta-arguments, but each module block can have only one such block. The first escaping block was at %s.",
+						seenEscapeBlock.DefRange,
+					),
+					Subject: &block.DefRange,
+				})
+				continue
+			}
+			seenEscapeBlock = block
+
+			// When there's an escaping block its content merges with the
+			// existing config we extracted earlier, so later decoding
+			// will see a blend of both.
+			mc.Config = hcl.MergeBodies([]hcl.Body{mc.Config, block.Body})
+
+		default:
+			// All of the other block types in our schema are reserved.
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Reserved block type name in module block",
+				Detail:   fmt.Sprintf("The block type name %q is reserved for use by OpenTofu in a future version.", block.Type),
+				Subject:  &block.TypeRange,
+			})
+		}
+	}
+
+	return mc, diags
+}
+
+// decodeStaticFields decodes the source and version arguments, which must be
+// known before any of the module's other arguments can be evaluated, using
+// the given evaluator to resolve any references to variables and locals in
+// the calling module.
+func (mc *ModuleCall) decodeStaticFields(eval *StaticEvaluator) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
 	haveVersionArg := false
-	if attr, exists := content.Attributes["version"]; exists {
+	if attr := mc.versionAttr; attr != nil {
 		var versionDiags hcl.Diagnostics
-		mc.Version, versionDiags = decodeVersionConstraint(attr)
+		mc.Version, versionDiags = decodeStaticVersionConstraint(attr, eval, eval.Ident(fmt.Sprintf("module.%s.version", mc.Name), attr.Range))
 		diags = append(diags, versionDiags...)
 		haveVersionArg = true
 	}
 
-	if attr, exists := content.Attributes["source"]; exists {
-		mc.SourceSet = true
-		mc.SourceAddrRange = attr.Expr.Range()
-		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &mc.SourceAddrRaw)
+	if attr := mc.sourceAttr; attr != nil {
+		valDiags := eval.DecodeExpression(attr.Expr, eval.Ident(fmt.Sprintf("module.%s.source", mc.Name), attr.Range), &mc.SourceAddrRaw)
 		diags = append(diags, valDiags...)
 		if !valDiags.HasErrors() {
 			var addr addrs.ModuleSource
@@ -134,70 +228,37 @@ func decodeModuleBlock(block *hcl.Block, override bool) (*ModuleCall, hcl.Diagno
 		}
 	}
 
-	if attr, exists := content.Attributes["count"]; exists {
-		mc.Count = attr.Expr
-	}
-
-	if attr, exists := content.Attributes["for_each"]; exists {
-		if mc.Count != nil {
-			diags = append(diags, &hcl.Diagnostic{
-				Severity: hcl.DiagError,
-				Summary:  `Invalid combination of "count" and "for_each"`,
-				Detail:   `The "count" and "for_each" meta-arguments are mutually-exclusive, only one should be used to be explicit about the number of resources to be created.`,
-				Subject:  &attr.NameRange,
-			})
-		}
-
-		mc.ForEach = attr.Expr
-	}
-
-	if attr, exists := content.Attributes["depends_on"]; exists {
-		deps, depsDiags := decodeDependsOn(attr)
-		diags = append(diags, depsDiags...)
-		mc.DependsOn = append(mc.DependsOn, deps...)
-	}
+	mc.staticPending = false
+	return diags
+}
 
-	if attr, exists := content.Attributes["providers"]; exists {
-		providers, providerDiags := decodePassedProviderConfigs(attr)
-		diags = append(diags, providerDiags...)
-		mc.Providers = append(mc.Providers, providers...)
+// staticVariables returns a StaticModuleVariables that statically evaluates
+// the arguments of this call, using the given evaluator for the calling
+// module. Each argument is evaluated only if the called module needs it.
+func (mc *ModuleCall) staticVariables(eval *StaticEvaluator) StaticModuleVariables {
+	if eval == nil {
+		return nil
 	}
-
-	var seenEscapeBlock *hcl.Block
-	for _, block := range content.Blocks {
-		switch block.Type {
-		case "_":
-			if seenEscapeBlock != nil {
-				diags = append(diags, &hcl.Diagnostic{
-					Severity: hcl.DiagError,
-					Summary:  "Duplicate escaping block",
-					Detail: fmt.Sprintf(
-						"The special block type \"_\" can be used to force particular arguments to be interpreted as module input variables rather than as meta-arguments, but each module block can have only one such block. The first escaping block was at %s.",
-						seenEscapeBlock.DefRange,
-					),
-					Subject: &block.DefRange,
-				})
-				continue
-			}
-			seenEscapeBlock = block
-
-			// When there's an escaping block its content merges with the
-			// existing config we extracted earlier, so later decoding
-			// will see a blend of both.
-			mc.Config = hcl.MergeBodies([]hcl.Body{mc.Config, block.Body})
-
-		default:
-			// All of the other block types in our schema are reserved.
-			diags = append(diags, &hcl.Diagnostic{
-				Severity: hcl.DiagError,
-				Summary:  "Reserved block type name in module block",
-				Detail:   fmt.Sprintf("The block type name %q is reserved for use by OpenTofu in a future version.", block.Type),
-				Subject:  &block.TypeRange,
-			})
+	return func(v *Variable) (cty.Value, hcl.Diagnostics) {
+		content, _, diags := mc.Config.PartialContent(&hcl.BodySchema{
+			Attributes: []hcl.AttributeSchema{{Name: v.Name}},
+		})
+		if diags.HasErrors() {
+			return cty.NilVal, diags
 		}
+		attr, exists := content.Attributes[v.Name]
+		if !exists {
+			return cty.NilVal, diags
+		}
+		val, moreDiags := eval.Evaluate(attr.Expr, eval.Ident(fmt.Sprintf("module.%s.%s", mc.Name, v.Name), attr.Range))
+		return val, append(diags, moreDiags...)
 	}
+}
 
-	return mc, diags
+// needsStaticEvaluation returns true if the given attribute, if present,
+// refers to anything and so cannot be evaluated without a StaticEvaluator.
+func needsStaticEvaluation(attr *hcl.Attribute) bool {
+	return attr != nil && len(attr.Expr.Variables()) != 0
 }
 
 // EntersNewPackage returns true if this call is to an external module, either
@@ -215,6 +276,10 @@ func (mc *ModuleCall) EntersNewPackage() bool {
 
 // PassedProviderConfig represents a provider config explicitly passed down to
 // a child module, possibly giving it a new local address in the process.
+//
+// InParent may select an instance of a provider configuration with for_each,
+// in which case its KeyExpression is evaluated separately for each instance
+// of the module call.
 type PassedProviderConfig struct {
 	InChild  *ProviderConfigRef
 	InParent *ProviderConfigRef
@@ -235,6 +300,15 @@ func decodePassedProviderConfigs(attr *hcl.Attribute) ([]PassedProviderConfig, h
 		if keyDiags.HasErrors() || valueDiags.HasErrors() {
 			continue
 		}
+		if key.KeyExpression != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid provider configuration reference",
+				Detail:   "A provider configuration in a child module cannot have instances, so the keys of the providers argument must not include instance keys.",
+				Subject:  key.KeyExpression.Range().Ptr(),
+			})
+			continue
+		}
 
 		matchKey := key.String()
 		if prev, exists := seen[matchKey]; exists {
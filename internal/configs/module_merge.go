@@ -33,6 +33,10 @@ func (p *Provider) merge(op *Provider) hcl.Diagnostics {
 		p.Version = op.Version
 	}
 
+	if op.ForEach != nil {
+		p.ForEach = op.ForEach
+	}
+
 	p.Config = MergeBodies(p.Config, op.Config)
 
 	return diags
@@ -49,6 +53,10 @@ func (v *Variable) merge(ov *Variable) hcl.Diagnostics {
 		v.Sensitive = ov.Sensitive
 		v.SensitiveSet = ov.SensitiveSet
 	}
+	if ov.EphemeralSet {
+		v.Ephemeral = ov.Ephemeral
+		v.EphemeralSet = ov.EphemeralSet
+	}
 	if ov.Default != cty.NilVal {
 		v.Default = ov.Default
 	}
@@ -148,6 +156,10 @@ func (o *Output) merge(oo *Output) hcl.Diagnostics {
 		o.Sensitive = oo.Sensitive
 		o.SensitiveSet = oo.SensitiveSet
 	}
+	if oo.EphemeralSet {
+		o.Ephemeral = oo.Ephemeral
+		o.EphemeralSet = oo.EphemeralSet
+	}
 
 	// We don't allow depends_on to be overridden because that is likely to
 	// cause confusing misbehavior.
@@ -171,6 +183,7 @@ func (mc *ModuleCall) merge(omc *ModuleCall) hcl.Diagnostics {
 		mc.SourceAddrRaw = omc.SourceAddrRaw
 		mc.SourceAddrRange = omc.SourceAddrRange
 		mc.SourceSet = omc.SourceSet
+		mc.sourceAttr = omc.sourceAttr
 	}
 
 	if omc.Count != nil {
@@ -181,10 +194,15 @@ func (mc *ModuleCall) merge(omc *ModuleCall) hcl.Diagnostics {
 		mc.ForEach = omc.ForEach
 	}
 
-	if len(omc.Version.Required) != 0 {
+	if len(omc.Version.Required) != 0 || (omc.staticPending && omc.versionAttr != nil) {
 		mc.Version = omc.Version
+		mc.versionAttr = omc.versionAttr
 	}
 
+	// If either call refers to variables or locals then the merged source
+	// and version are decoded together once the module is complete.
+	mc.staticPending = mc.staticPending || omc.staticPending
+
 	mc.Config = MergeBodies(mc.Config, omc.Config)
 
 	if len(omc.Providers) != 0 {
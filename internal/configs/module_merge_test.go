@@ -173,6 +173,11 @@ func TestModuleOverrideModule(t *testing.T) {
 	gotConfig := got.Config
 	got.Config = nil
 
+	// The raw attributes are kept only for static evaluation, so we'll
+	// disregard them here too.
+	got.sourceAttr = nil
+	got.versionAttr = nil
+
 	assertResultDeepEqual(t, got, want)
 
 	type content struct {
@@ -313,6 +318,21 @@ func TestModuleOverrideSensitiveVariable(t *testing.T) {
 	}
 }
 
+func TestModuleOverrideEphemeral(t *testing.T) {
+	mod, diags := testModuleFromDir("testdata/valid-modules/override-ephemeral")
+	assertNoDiagnostics(t, diags)
+
+	if got := mod.Variables["token"]; !got.Ephemeral || !got.EphemeralSet {
+		t.Errorf("variable \"token\" should be ephemeral after override")
+	}
+	if got := mod.Variables["name"]; got.Ephemeral || !got.EphemeralSet {
+		t.Errorf("variable \"name\" should not be ephemeral after override")
+	}
+	if got := mod.Outputs["token"]; !got.Ephemeral || !got.EphemeralSet {
+		t.Errorf("output \"token\" should be ephemeral after override")
+	}
+}
+
 func TestModuleOverrideResourceFQNs(t *testing.T) {
 	mod, diags := testModuleFromDir("testdata/valid-modules/override-resource-provider")
 	assertNoDiagnostics(t, diags)
@@ -36,8 +36,14 @@ type Variable struct {
 	Validations []*CheckRule
 	Sensitive   bool
 
+	// Ephemeral indicates that the value of this variable is only needed
+	// during the current operation, so it must never be saved in a plan or
+	// state file and must be set again when applying a saved plan.
+	Ephemeral bool
+
 	DescriptionSet bool
 	SensitiveSet   bool
+	EphemeralSet   bool
 
 	// Nullable indicates that null is a valid value for this variable. Setting
 	// Nullable to false means that the module can expect this variable to
@@ -120,6 +126,12 @@ func decodeVariableBlock(block *hcl.Block, override bool) (*Variable, hcl.Diagno
 		v.SensitiveSet = true
 	}
 
+	if attr, exists := content.Attributes["ephemeral"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Ephemeral)
+		diags = append(diags, valDiags...)
+		v.EphemeralSet = true
+	}
+
 	if attr, exists := content.Attributes["nullable"]; exists {
 		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Nullable)
 		diags = append(diags, valDiags...)
@@ -325,32 +337,29 @@ func (m VariableParsingMode) Parse(name, value string) (cty.Value, hcl.Diagnosti
 }
 
 // decodeVariableValidationBlock is a wrapper around decodeCheckRuleBlock
-// that imposes the additional rule that the condition expression can refer
-// only to an input variable of the given name.
+// that imposes the additional rule that the condition expression must refer
+// to the input variable of the given name, and that both expressions can
+// refer only to input variables, local values and data resources.
 func decodeVariableValidationBlock(varName string, block *hcl.Block, override bool) (*CheckRule, hcl.Diagnostics) {
 	vv, diags := decodeCheckRuleBlock(block, override)
 	if vv.Condition != nil {
-		// The validation condition can only refer to the variable itself,
-		// to ensure that the variable declaration can't create additional
-		// edges in the dependency graph.
 		goodRefs := 0
 		for _, traversal := range vv.Condition.Variables() {
 			ref, moreDiags := addrs.ParseRef(traversal)
 			if !moreDiags.HasErrors() {
-				if addr, ok := ref.Subject.(addrs.InputVariable); ok {
-					if addr.Name == varName {
-						goodRefs++
-						continue // Reference is valid
-					}
+				if addr, ok := ref.Subject.(addrs.InputVariable); ok && addr.Name == varName {
+					goodRefs++
+					continue // Reference is valid
 				}
 			}
-			// If we fall out here then the reference is invalid.
-			diags = diags.Append(&hcl.Diagnostic{
-				Severity: hcl.DiagError,
-				Summary:  "Invalid reference in variable validation",
-				Detail:   fmt.Sprintf("The condition for variable %q can only refer to the variable itself, using var.%s.", varName, varName),
-				Subject:  traversal.SourceRange().Ptr(),
-			})
+			if !validVariableValidationRef(traversal) {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid reference in variable validation",
+					Detail:   fmt.Sprintf("The condition for variable %q can only refer to input variables, local values and data resources.", varName),
+					Subject:  traversal.SourceRange().Ptr(),
+				})
+			}
 		}
 		if goodRefs < 1 {
 			diags = diags.Append(&hcl.Diagnostic{
@@ -366,30 +375,43 @@ func decodeVariableValidationBlock(varName string, block *hcl.Block, override bo
 		// The same applies to the validation error message, except that
 		// references are not required. A string literal is a valid error
 		// message.
-		goodRefs := 0
 		for _, traversal := range vv.ErrorMessage.Variables() {
-			ref, moreDiags := addrs.ParseRef(traversal)
-			if !moreDiags.HasErrors() {
-				if addr, ok := ref.Subject.(addrs.InputVariable); ok {
-					if addr.Name == varName {
-						goodRefs++
-						continue // Reference is valid
-					}
-				}
+			if !validVariableValidationRef(traversal) {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid reference in variable validation",
+					Detail:   fmt.Sprintf("The error message for variable %q can only refer to input variables, local values and data resources.", varName),
+					Subject:  traversal.SourceRange().Ptr(),
+				})
 			}
-			// If we fall out here then the reference is invalid.
-			diags = diags.Append(&hcl.Diagnostic{
-				Severity: hcl.DiagError,
-				Summary:  "Invalid reference in variable validation",
-				Detail:   fmt.Sprintf("The error message for variable %q can only refer to the variable itself, using var.%s.", varName, varName),
-				Subject:  traversal.SourceRange().Ptr(),
-			})
 		}
 	}
 
 	return vv, diags
 }
 
+// validVariableValidationRef returns true if the given traversal refers to
+// an object that a variable validation rule can depend on. Validation rules
+// check the values given for the inputs of a module, so they can refer to
+// other inputs and to values derived from them, but not to managed resources,
+// module calls or other objects that are decided by the plan itself.
+func validVariableValidationRef(traversal hcl.Traversal) bool {
+	ref, diags := addrs.ParseRef(traversal)
+	if diags.HasErrors() {
+		return false
+	}
+	switch addr := ref.Subject.(type) {
+	case addrs.InputVariable, addrs.LocalValue:
+		return true
+	case addrs.Resource:
+		return addr.Mode == addrs.DataResourceMode
+	case addrs.ResourceInstance:
+		return addr.Resource.Mode == addrs.DataResourceMode
+	default:
+		return false
+	}
+}
+
 // Output represents an "output" block in a module or file.
 type Output struct {
 	Name        string
@@ -398,10 +420,16 @@ type Output struct {
 	DependsOn   []hcl.Traversal
 	Sensitive   bool
 
+	// Ephemeral indicates that the output value may be derived from
+	// ephemeral values, and so is not saved in a plan or state file. Only
+	// outputs of child modules can be ephemeral.
+	Ephemeral bool
+
 	Preconditions []*CheckRule
 
 	DescriptionSet bool
 	SensitiveSet   bool
+	EphemeralSet   bool
 
 	DeclRange hcl.Range
 }
@@ -447,6 +475,12 @@ func decodeOutputBlock(block *hcl.Block, override bool) (*Output, hcl.Diagnostic
 		o.SensitiveSet = true
 	}
 
+	if attr, exists := content.Attributes["ephemeral"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &o.Ephemeral)
+		diags = append(diags, valDiags...)
+		o.EphemeralSet = true
+	}
+
 	if attr, exists := content.Attributes["depends_on"]; exists {
 		deps, depsDiags := decodeDependsOn(attr)
 		diags = append(diags, depsDiags...)
@@ -538,6 +572,9 @@ var variableBlockSchema = &hcl.BodySchema{
 		{
 			Name: "sensitive",
 		},
+		{
+			Name: "ephemeral",
+		},
 		{
 			Name: "nullable",
 		},
@@ -564,6 +601,9 @@ var outputBlockSchema = &hcl.BodySchema{
 		{
 			Name: "sensitive",
 		},
+		{
+			Name: "ephemeral",
+		},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{Type: "precondition"},
@@ -57,7 +57,7 @@ func (p *Parser) loadConfigFile(path string, override bool) (*File, hcl.Diagnost
 	file := &File{}
 
 	var reqDiags hcl.Diagnostics
-	file.CoreVersionConstraints, reqDiags = sniffCoreVersionRequirements(body)
+	file.CoreVersionConstraints, file.staticCoreVersionAttrs, reqDiags = sniffCoreVersionRequirements(body)
 	diags = append(diags, reqDiags...)
 
 	// We'll load the experiments first because other decoding logic in the
@@ -97,6 +97,20 @@ func (p *Parser) loadConfigFile(path string, override bool) (*File, hcl.Diagnost
 						file.CloudConfigs = append(file.CloudConfigs, cloudCfg)
 					}
 
+				case "encryption":
+					encCfg, cfgDiags := decodeEncryptionBlock(innerBlock)
+					diags = append(diags, cfgDiags...)
+					if encCfg != nil {
+						file.Encryptions = append(file.Encryptions, encCfg)
+					}
+
+				case "workspaces":
+					workspacesCfg, cfgDiags := decodeWorkspacesBlock(innerBlock)
+					diags = append(diags, cfgDiags...)
+					if workspacesCfg != nil {
+						file.Workspaces = append(file.Workspaces, workspacesCfg)
+					}
+
 				case "required_providers":
 					reqs, reqsDiags := decodeRequiredProvidersBlock(innerBlock)
 					diags = append(diags, reqsDiags...)
@@ -180,6 +194,13 @@ func (p *Parser) loadConfigFile(path string, override bool) (*File, hcl.Diagnost
 				file.Moved = append(file.Moved, cfg)
 			}
 
+		case "removed":
+			cfg, cfgDiags := decodeRemovedBlock(block)
+			diags = append(diags, cfgDiags...)
+			if cfg != nil {
+				file.Removed = append(file.Removed, cfg)
+			}
+
 		case "import":
 			cfg, cfgDiags := decodeImportBlock(block)
 			diags = append(diags, cfgDiags...)
@@ -216,10 +237,14 @@ func (p *Parser) loadConfigFile(path string, override bool) (*File, hcl.Diagnost
 // This is a "best effort" sort of method which will return constraints it is
 // able to find, but may return no constraints at all if the given body is
 // so invalid that it cannot be decoded at all.
-func sniffCoreVersionRequirements(body hcl.Body) ([]VersionConstraint, hcl.Diagnostics) {
+//
+// Any "required_version" attributes that refer to variables or locals are
+// returned separately, to be decoded once the whole module is loaded.
+func sniffCoreVersionRequirements(body hcl.Body) ([]VersionConstraint, []*hcl.Attribute, hcl.Diagnostics) {
 	rootContent, _, diags := body.PartialContent(configFileTerraformBlockSniffRootSchema)
 
 	var constraints []VersionConstraint
+	var pending []*hcl.Attribute
 
 	for _, block := range rootContent.Blocks {
 		content, _, blockDiags := block.Body.PartialContent(configFileVersionSniffBlockSchema)
@@ -229,6 +254,10 @@ func sniffCoreVersionRequirements(body hcl.Body) ([]VersionConstraint, hcl.Diagn
 		if !exists {
 			continue
 		}
+		if needsStaticEvaluation(attr) {
+			pending = append(pending, attr)
+			continue
+		}
 
 		constraint, constraintDiags := decodeVersionConstraint(attr)
 		diags = append(diags, constraintDiags...)
@@ -237,7 +266,7 @@ func sniffCoreVersionRequirements(body hcl.Body) ([]VersionConstraint, hcl.Diagn
 		}
 	}
 
-	return constraints, diags
+	return constraints, pending, diags
 }
 
 // configFileSchema is the schema for the top-level of a config file. We use
@@ -284,6 +313,9 @@ var configFileSchema = &hcl.BodySchema{
 		{
 			Type: "moved",
 		},
+		{
+			Type: "removed",
+		},
 		{
 			Type: "import",
 		},
@@ -310,6 +342,12 @@ var terraformBlockSchema = &hcl.BodySchema{
 		{
 			Type: "cloud",
 		},
+		{
+			Type: "encryption",
+		},
+		{
+			Type: "workspaces",
+		},
 		{
 			Type: "required_providers",
 		},
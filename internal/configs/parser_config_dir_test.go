@@ -35,7 +35,7 @@ func TestParserLoadConfigDirSuccess(t *testing.T) {
 			parser := NewParser(nil)
 			path := filepath.Join("testdata/valid-modules", name)
 
-			mod, diags := parser.LoadConfigDir(path)
+			mod, diags := parser.LoadConfigDir(path, RootModuleCallForTesting())
 			if len(diags) != 0 && len(mod.ActiveExperiments) != 0 {
 				// As a special case to reduce churn while we're working
 				// through experimental features, we'll ignore the warning
@@ -101,7 +101,7 @@ func TestParserLoadConfigDirSuccess(t *testing.T) {
 				"mod/" + name: string(src),
 			})
 
-			_, diags := parser.LoadConfigDir("mod")
+			_, diags := parser.LoadConfigDir("mod", RootModuleCallForTesting())
 			if diags.HasErrors() {
 				t.Errorf("unexpected error diagnostics")
 				for _, diag := range diags {
@@ -131,7 +131,7 @@ func TestParserLoadConfigDirWithTests(t *testing.T) {
 			}
 
 			parser := NewParser(nil)
-			mod, diags := parser.LoadConfigDirWithTests(directory, testDirectory)
+			mod, diags := parser.LoadConfigDirWithTests(directory, testDirectory, RootModuleCallForTesting())
 			if len(diags) > 0 { // We don't want any warnings or errors.
 				t.Errorf("unexpected diagnostics")
 				for _, diag := range diags {
@@ -148,7 +148,7 @@ func TestParserLoadConfigDirWithTests(t *testing.T) {
 
 func TestParserLoadConfigDirWithTests_ReturnsWarnings(t *testing.T) {
 	parser := NewParser(nil)
-	mod, diags := parser.LoadConfigDirWithTests("testdata/valid-modules/with-tests", "not_real")
+	mod, diags := parser.LoadConfigDirWithTests("testdata/valid-modules/with-tests", "not_real", RootModuleCallForTesting())
 	if len(diags) != 1 {
 		t.Errorf("expected exactly 1 diagnostic, but found %d", len(diags))
 	} else {
@@ -195,7 +195,7 @@ func TestParserLoadConfigDirFailure(t *testing.T) {
 			parser := NewParser(nil)
 			path := filepath.Join("testdata/invalid-modules", name)
 
-			_, diags := parser.LoadConfigDir(path)
+			_, diags := parser.LoadConfigDir(path, RootModuleCallForTesting())
 			if !diags.HasErrors() {
 				t.Errorf("no errors; want at least one")
 				for _, diag := range diags {
@@ -224,7 +224,7 @@ func TestParserLoadConfigDirFailure(t *testing.T) {
 				"mod/" + name: string(src),
 			})
 
-			_, diags := parser.LoadConfigDir("mod")
+			_, diags := parser.LoadConfigDir("mod", RootModuleCallForTesting())
 			if !diags.HasErrors() {
 				t.Errorf("no errors; want at least one")
 				for _, diag := range diags {
@@ -132,6 +132,16 @@ func TestParserLoadConfigFileFailureMessages(t *testing.T) {
 			hcl.DiagError,
 			"Unsuitable value type",
 		},
+		{
+			"invalid-files/encryption-undeclared-method.tf",
+			hcl.DiagError,
+			"Reference to undeclared encryption method",
+		},
+		{
+			"invalid-files/encryption-duplicate-method.tf",
+			hcl.DiagError,
+			"Duplicate method block",
+		},
 	}
 
 	for _, test := range tests {
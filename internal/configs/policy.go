@@ -0,0 +1,227 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// PolicyEnforcementLevel describes what happens when a planned change
+// violates a policy.
+type PolicyEnforcementLevel string
+
+const (
+	// PolicyMandatory policies prevent the plan from being applied when
+	// they are violated.
+	PolicyMandatory PolicyEnforcementLevel = "mandatory"
+
+	// PolicyAdvisory policies only produce warnings when they are violated.
+	PolicyAdvisory PolicyEnforcementLevel = "advisory"
+)
+
+// Policy represents a "policy" block in a policy file, which describes rules
+// that the planned changes to resource instances must satisfy.
+//
+// Each rule is evaluated once for each planned change to a resource instance
+// of the policy's resource type, or to any resource instance if the policy
+// has no resource type, with the symbol "resource" referring to an object
+// describing the change.
+type Policy struct {
+	Name             string
+	EnforcementLevel PolicyEnforcementLevel
+
+	// ResourceType, if not empty, is the type of the resources whose
+	// changes the policy applies to.
+	ResourceType string
+
+	Rules []*CheckRule
+
+	DeclRange hcl.Range
+}
+
+// LoadPolicyDir reads the policy files in the given directory, which are
+// those whose names end with ".tfpolicy.hcl" or ".tfpolicy.json", and returns
+// the policies they declare.
+//
+// The policies are returned in the order of the names of the files declaring
+// them and then in the order they are declared in each file. The names of the
+// policies must be unique across all of the files.
+func (p *Parser) LoadPolicyDir(dir string) ([]*Policy, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	infos, err := p.fs.ReadDir(dir)
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Failed to read policy directory",
+			Detail:   fmt.Sprintf("Policy directory %s does not exist or cannot be read.", dir),
+		})
+		return nil, diags
+	}
+
+	var paths []string
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || IsIgnoredFile(name) {
+			continue
+		}
+		if strings.HasSuffix(name, ".tfpolicy.hcl") || strings.HasSuffix(name, ".tfpolicy.json") {
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(paths)
+
+	var policies []*Policy
+	seen := make(map[string]*Policy)
+	for _, path := range paths {
+		body, fileDiags := p.LoadHCLFile(path)
+		diags = append(diags, fileDiags...)
+		if body == nil {
+			continue
+		}
+
+		filePolicies, fileDiags := loadPolicyFile(body)
+		diags = append(diags, fileDiags...)
+		for _, policy := range filePolicies {
+			if existing, exists := seen[policy.Name]; exists {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate policy",
+					Detail:   fmt.Sprintf("A policy named %q was already declared at %s. Policy names must be unique within a policy directory.", policy.Name, existing.DeclRange),
+					Subject:  policy.DeclRange.Ptr(),
+				})
+				continue
+			}
+			seen[policy.Name] = policy
+			policies = append(policies, policy)
+		}
+	}
+
+	if len(paths) == 0 {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "No policy files",
+			Detail:   fmt.Sprintf("Policy directory %s does not contain any .tfpolicy.hcl or .tfpolicy.json files, so no policies will be checked.", dir),
+		})
+	}
+
+	return policies, diags
+}
+
+func loadPolicyFile(body hcl.Body) ([]*Policy, hcl.Diagnostics) {
+	content, diags := body.Content(policyFileSchema)
+
+	var policies []*Policy
+	for _, block := range content.Blocks {
+		policy, policyDiags := decodePolicyBlock(block)
+		diags = append(diags, policyDiags...)
+		if !policyDiags.HasErrors() {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, diags
+}
+
+func decodePolicyBlock(block *hcl.Block) (*Policy, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	policy := &Policy{
+		Name:             block.Labels[0],
+		EnforcementLevel: PolicyMandatory,
+		DeclRange:        block.DefRange,
+	}
+
+	if !hclsyntax.ValidIdentifier(policy.Name) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid policy name",
+			Detail:   badIdentifierDetail,
+			Subject:  &block.LabelRanges[0],
+		})
+	}
+
+	content, moreDiags := block.Body.Content(policyBlockSchema)
+	diags = append(diags, moreDiags...)
+
+	if attr, exists := content.Attributes["enforcement_level"]; exists {
+		level, moreDiags := decodeConstantStringAttr(attr)
+		diags = append(diags, moreDiags...)
+		switch PolicyEnforcementLevel(level) {
+		case PolicyMandatory, PolicyAdvisory:
+			policy.EnforcementLevel = PolicyEnforcementLevel(level)
+		default:
+			if !moreDiags.HasErrors() {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid enforcement level",
+					Detail:   fmt.Sprintf("The enforcement level must be either %q or %q.", PolicyMandatory, PolicyAdvisory),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			}
+		}
+	}
+
+	if attr, exists := content.Attributes["resource_type"]; exists {
+		typeName, moreDiags := decodeConstantStringAttr(attr)
+		diags = append(diags, moreDiags...)
+		if !moreDiags.HasErrors() && !hclsyntax.ValidIdentifier(typeName) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid resource type",
+				Detail:   "The resource type must be a valid resource type name.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+		policy.ResourceType = typeName
+	}
+
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "rule":
+			rule, moreDiags := decodeCheckRuleBlock(block, false)
+			diags = append(diags, moreDiags...)
+			if !moreDiags.HasErrors() {
+				policy.Rules = append(policy.Rules, rule)
+			}
+		default:
+			// The schema should never let us get here.
+			panic(fmt.Sprintf("unexpected %s block in policy", block.Type))
+		}
+	}
+
+	if len(policy.Rules) == 0 && !diags.HasErrors() {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Missing policy rules",
+			Detail:   "A policy must have at least one rule block.",
+			Subject:  policy.DeclRange.Ptr(),
+		})
+	}
+
+	return policy, diags
+}
+
+var policyFileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "policy",
+			LabelNames: []string{"name"},
+		},
+	},
+}
+
+var policyBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "enforcement_level"},
+		{Name: "resource_type"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "rule"},
+	},
+}
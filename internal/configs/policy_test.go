@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"testing"
+)
+
+func TestParserLoadPolicyDir(t *testing.T) {
+	parser := testParser(map[string]string{
+		"policies/b.tfpolicy.hcl": `
+policy "tagged" {
+  enforcement_level = "advisory"
+
+  rule {
+    condition     = resource.after.tags != null
+    error_message = "Resources must be tagged."
+  }
+}
+`,
+		"policies/a.tfpolicy.hcl": `
+policy "private_buckets" {
+  resource_type = "aws_s3_bucket"
+
+  rule {
+    condition     = resource.after.acl != "public-read"
+    error_message = "Buckets must not be public."
+  }
+  rule {
+    condition     = resource.after.versioning
+    error_message = "Buckets must be versioned."
+  }
+}
+`,
+		"policies/ignored.tf": `
+this is not a policy file
+`,
+	})
+
+	policies, diags := parser.LoadPolicyDir("policies")
+	assertNoDiagnostics(t, diags)
+
+	if got, want := len(policies), 2; got != want {
+		t.Fatalf("wrong number of policies %d; want %d", got, want)
+	}
+	if got, want := policies[0].Name, "private_buckets"; got != want {
+		t.Errorf("wrong first policy %q; want %q", got, want)
+	}
+	if got, want := policies[0].EnforcementLevel, PolicyMandatory; got != want {
+		t.Errorf("wrong enforcement level %q; want %q", got, want)
+	}
+	if got, want := policies[0].ResourceType, "aws_s3_bucket"; got != want {
+		t.Errorf("wrong resource type %q; want %q", got, want)
+	}
+	if got, want := len(policies[0].Rules), 2; got != want {
+		t.Errorf("wrong number of rules %d; want %d", got, want)
+	}
+	if got, want := policies[1].EnforcementLevel, PolicyAdvisory; got != want {
+		t.Errorf("wrong enforcement level %q; want %q", got, want)
+	}
+	if got, want := policies[1].ResourceType, ""; got != want {
+		t.Errorf("wrong resource type %q; want %q", got, want)
+	}
+}
+
+func TestParserLoadPolicyDir_invalid(t *testing.T) {
+	parser := testParser(map[string]string{
+		"policies/main.tfpolicy.hcl": `
+policy "level" {
+  enforcement_level = "sometimes"
+
+  rule {
+    condition     = resource.after != null
+    error_message = "Unused."
+  }
+}
+
+policy "empty" {
+}
+
+policy "level" {
+  rule {
+    condition     = resource.after != null
+    error_message = "Unused."
+  }
+}
+
+policy "constant" {
+  rule {
+    condition     = true
+    error_message = "Unused."
+  }
+}
+`,
+	})
+
+	_, diags := parser.LoadPolicyDir("policies")
+	assertExactDiagnostics(t, diags, []string{
+		`policies/main.tfpolicy.hcl:3,23-34: Invalid enforcement level; The enforcement level must be either "mandatory" or "advisory".`,
+		`policies/main.tfpolicy.hcl:11,1-15: Missing policy rules; A policy must have at least one rule block.`,
+		`policies/main.tfpolicy.hcl:23,21-25: Invalid rule expression; The condition expression must refer to at least one object from elsewhere in the configuration, or else its result would not be checking anything.`,
+	})
+}
+
+func TestParserLoadPolicyDir_duplicate(t *testing.T) {
+	parser := testParser(map[string]string{
+		"policies/a.tfpolicy.hcl": `
+policy "dup" {
+  rule {
+    condition     = resource.after != null
+    error_message = "Unused."
+  }
+}
+`,
+		"policies/b.tfpolicy.hcl": `
+policy "dup" {
+  rule {
+    condition     = resource.after != null
+    error_message = "Unused."
+  }
+}
+`,
+	})
+
+	_, diags := parser.LoadPolicyDir("policies")
+	assertExactDiagnostics(t, diags, []string{
+		`policies/b.tfpolicy.hcl:2,1-13: Duplicate policy; A policy named "dup" was already declared at policies/a.tfpolicy.hcl:2,1-13. Policy names must be unique within a policy directory.`,
+	})
+}
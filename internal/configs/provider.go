@@ -9,8 +9,10 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/instances"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -25,10 +27,34 @@ type Provider struct {
 
 	Version VersionConstraint
 
+	// ForEach, if not nil, is the expression given in the "for_each"
+	// argument, which declares one instance of the provider configuration for
+	// each element of its value. Only configurations with an alias may have
+	// for_each.
+	ForEach hcl.Expression
+
+	// Instances are the instances of a provider configuration with for_each,
+	// by instance key, with the values of "each.key" and "each.value" for
+	// each of them. The for_each expression is evaluated statically when the
+	// module is loaded, so this is nil for configurations without for_each
+	// and for those whose for_each expression could not be evaluated.
+	Instances map[addrs.InstanceKey]instances.RepetitionData
+
 	Config hcl.Body
 
 	DeclRange hcl.Range
 
+	// IsMocked is true for providers declared by a "mock_provider" block in a
+	// test file. A mocked provider is never configured and never asked to
+	// plan, apply or read anything; it instead synthesizes values for
+	// computed attributes from the provider schema.
+	IsMocked bool
+
+	// MockResources holds the default values declared for resources and data
+	// sources of a mocked provider. It is only populated when IsMocked is
+	// true.
+	MockResources []*MockResource
+
 	// TODO: this may not be set in some cases, so it is not yet suitable for
 	// use outside of this package. We currently only use it for internal
 	// validation, but once we verify that this can be set in all cases, we can
@@ -37,6 +63,23 @@ type Provider struct {
 	providerType addrs.Provider
 }
 
+// decodeProviderAlias decodes and validates the "alias" argument of a provider
+// configuration block.
+func decodeProviderAlias(attr *hcl.Attribute) (string, hcl.Diagnostics) {
+	var alias string
+	diags := gohcl.DecodeExpression(attr.Expr, nil, &alias)
+
+	if !hclsyntax.ValidIdentifier(alias) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider configuration alias",
+			Detail:   fmt.Sprintf("An alias must be a valid name. %s", badIdentifierDetail),
+		})
+	}
+
+	return alias, diags
+}
+
 func decodeProviderBlock(block *hcl.Block) (*Provider, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
@@ -63,17 +106,10 @@ func decodeProviderBlock(block *hcl.Block) (*Provider, hcl.Diagnostics) {
 	}
 
 	if attr, exists := content.Attributes["alias"]; exists {
-		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &provider.Alias)
-		diags = append(diags, valDiags...)
+		alias, aliasDiags := decodeProviderAlias(attr)
+		diags = append(diags, aliasDiags...)
+		provider.Alias = alias
 		provider.AliasRange = attr.Expr.Range().Ptr()
-
-		if !hclsyntax.ValidIdentifier(provider.Alias) {
-			diags = append(diags, &hcl.Diagnostic{
-				Severity: hcl.DiagError,
-				Summary:  "Invalid provider configuration alias",
-				Detail:   fmt.Sprintf("An alias must be a valid name. %s", badIdentifierDetail),
-			})
-		}
 	}
 
 	if attr, exists := content.Attributes["version"]; exists {
@@ -88,8 +124,20 @@ func decodeProviderBlock(block *hcl.Block) (*Provider, hcl.Diagnostics) {
 		diags = append(diags, versionDiags...)
 	}
 
+	if attr, exists := content.Attributes["for_each"]; exists {
+		provider.ForEach = attr.Expr
+		if provider.Alias == "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid provider configuration for_each",
+				Detail:   "The for_each argument may only be used in a provider configuration that has an alias, so that the instances can be referred to by that alias and an instance key.",
+				Subject:  &attr.NameRange,
+			})
+		}
+	}
+
 	// Reserved attribute names
-	for _, name := range []string{"count", "depends_on", "for_each", "source"} {
+	for _, name := range []string{"count", "depends_on", "source"} {
 		if attr, exists := content.Attributes[name]; exists {
 			diags = append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
@@ -138,6 +186,71 @@ func decodeProviderBlock(block *hcl.Block) (*Provider, hcl.Diagnostics) {
 	return provider, diags
 }
 
+// decodeStaticForEach evaluates the for_each expression of the provider
+// configuration, if any, and records the resulting instances.
+func (p *Provider) decodeStaticForEach(eval *StaticEvaluator) hcl.Diagnostics {
+	if p.ForEach == nil {
+		return nil
+	}
+
+	ident := eval.Ident(fmt.Sprintf("provider.%s.for_each", p.moduleUniqueKey()), p.ForEach.Range())
+	val, diags := eval.Evaluate(p.ForEach, ident)
+	if !diags.HasErrors() {
+		diags = append(diags, checkStaticValueNotSensitive(val, ident)...)
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+
+	ty := val.Type()
+	if val.IsNull() || !(ty.IsMapType() || ty.IsObjectType() || ty.IsSetType()) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid for_each argument",
+			Detail:   fmt.Sprintf("The for_each argument of a provider configuration must be a map, or a set of strings, and you have provided a value of type %s.", ty.FriendlyName()),
+			Subject:  p.ForEach.Range().Ptr(),
+		})
+		return diags
+	}
+	if ty.IsSetType() && !ty.ElementType().Equals(cty.String) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid for_each set argument",
+			Detail:   fmt.Sprintf("The for_each argument of a provider configuration supports maps and sets of strings, but you have provided a set containing type %s.", ty.ElementType().FriendlyName()),
+			Subject:  p.ForEach.Range().Ptr(),
+		})
+		return diags
+	}
+	if ty.IsSetType() {
+		// A set of strings may contain null, which cannot be used as an
+		// instance key.
+		for it := val.ElementIterator(); it.Next(); {
+			if _, v := it.Element(); v.IsNull() {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid for_each set argument",
+					Detail:   "The for_each argument of a provider configuration must not contain null values.",
+					Subject:  p.ForEach.Range().Ptr(),
+				})
+				return diags
+			}
+		}
+	}
+
+	p.Instances = make(map[addrs.InstanceKey]instances.RepetitionData)
+	for it := val.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		if ty.IsSetType() {
+			k = v
+		}
+		p.Instances[addrs.StringKey(k.AsString())] = instances.RepetitionData{
+			EachKey:   k,
+			EachValue: v,
+		}
+	}
+	return diags
+}
+
 // Addr returns the address of the receiving provider configuration, relative
 // to its containing module.
 func (p *Provider) Addr() addrs.LocalProviderConfig {
@@ -240,11 +353,13 @@ var providerBlockSchema = &hcl.BodySchema{
 		{
 			Name: "version",
 		},
+		{
+			Name: "for_each",
+		},
 
 		// Attribute names reserved for future expansion.
 		{Name: "count"},
 		{Name: "depends_on"},
-		{Name: "for_each"},
 		{Name: "source"},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
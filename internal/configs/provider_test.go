@@ -28,10 +28,9 @@ func TestProviderReservedNames(t *testing.T) {
 		`config.tf:4,13-20: Version constraints inside provider configuration blocks are deprecated; OpenTofu 0.13 and earlier allowed provider version constraints inside the provider configuration block, but that is now deprecated and will be removed in a future version of OpenTofu. To silence this warning, move the provider version constraint into the required_providers block.`,
 		`config.tf:10,3-8: Reserved argument name in provider block; The provider argument name "count" is reserved for use by OpenTofu in a future version.`,
 		`config.tf:11,3-13: Reserved argument name in provider block; The provider argument name "depends_on" is reserved for use by OpenTofu in a future version.`,
-		`config.tf:12,3-11: Reserved argument name in provider block; The provider argument name "for_each" is reserved for use by OpenTofu in a future version.`,
-		`config.tf:14,3-12: Reserved block type name in provider block; The block type name "lifecycle" is reserved for use by OpenTofu in a future version.`,
-		`config.tf:15,3-9: Reserved block type name in provider block; The block type name "locals" is reserved for use by OpenTofu in a future version.`,
-		`config.tf:13,3-9: Reserved argument name in provider block; The provider argument name "source" is reserved for use by OpenTofu in a future version.`,
+		`config.tf:13,3-12: Reserved block type name in provider block; The block type name "lifecycle" is reserved for use by OpenTofu in a future version.`,
+		`config.tf:14,3-9: Reserved block type name in provider block; The block type name "locals" is reserved for use by OpenTofu in a future version.`,
+		`config.tf:12,3-9: Reserved argument name in provider block; The provider argument name "source" is reserved for use by OpenTofu in a future version.`,
 	})
 }
 
@@ -151,3 +150,95 @@ func TestParseProviderConfigCompactStr(t *testing.T) {
 		})
 	}
 }
+
+func TestProviderForEach(t *testing.T) {
+	parser := testParser(map[string]string{
+		"main.tf": `
+variable "regions" {
+  type    = map(string)
+  default = {
+    east = "us-east-1"
+    west = "us-west-2"
+  }
+}
+
+provider "aws" {
+  alias    = "by_region"
+  for_each = var.regions
+
+  region = each.value
+}
+
+resource "aws_instance" "static" {
+  provider = aws.by_region["east"]
+}
+
+resource "aws_instance" "dynamic" {
+  for_each = var.regions
+  provider = aws.by_region[each.key]
+}
+`,
+	})
+
+	mod, diags := parser.LoadConfigDir(".", RootModuleCallForTesting())
+	assertNoDiagnostics(t, diags)
+
+	pc := mod.ProviderConfigs["aws.by_region"]
+	if got, want := len(pc.Instances), 2; got != want {
+		t.Fatalf("wrong number of instances %d; want %d", got, want)
+	}
+	if got, want := pc.Instances[addrs.StringKey("west")].EachValue.AsString(), "us-west-2"; got != want {
+		t.Errorf("wrong each.value %q; want %q", got, want)
+	}
+
+	static := mod.ManagedResources["aws_instance.static"].ProviderConfigRef
+	if got, want := static.String(), "aws.by_region"; got != want {
+		t.Errorf("wrong provider reference %q; want %q", got, want)
+	}
+	key, diags := static.KeyExpression.Value(nil)
+	assertNoDiagnostics(t, diags)
+	if got, want := key.AsString(), "east"; got != want {
+		t.Errorf("wrong instance key %q; want %q", got, want)
+	}
+
+	dynamic := mod.ManagedResources["aws_instance.dynamic"].ProviderConfigRef
+	if got, want := len(dynamic.KeyExpression.Variables()), 1; got != want {
+		t.Fatalf("wrong number of references in key expression %d; want %d", got, want)
+	}
+	if got, want := dynamic.KeyExpression.Variables()[0].RootName(), "each"; got != want {
+		t.Errorf("wrong reference in key expression %q; want %q", got, want)
+	}
+}
+
+func TestProviderForEach_invalid(t *testing.T) {
+	parser := testParser(map[string]string{
+		"main.tf": `
+provider "aws" {
+  for_each = toset(["a"])
+}
+
+provider "aws" {
+  alias    = "list"
+  for_each = ["a"]
+}
+
+provider "aws" {
+  alias    = "numbers"
+  for_each = toset([1])
+}
+
+provider "aws" {
+  alias    = "nulls"
+  for_each = toset(["a", null])
+}
+`,
+	})
+
+	_, diags := parser.LoadConfigDir(".", RootModuleCallForTesting())
+	assertExactDiagnostics(t, diags, []string{
+		`main.tf:3,3-11: Invalid provider configuration for_each; The for_each argument may only be used in a provider configuration that has an alias, so that the instances can be referred to by that alias and an instance key.`,
+		`main.tf:8,14-19: Invalid for_each argument; The for_each argument of a provider configuration must be a map, or a set of strings, and you have provided a value of type tuple.`,
+		`main.tf:13,14-24: Invalid for_each set argument; The for_each argument of a provider configuration supports maps and sets of strings, but you have provided a set containing type number.`,
+		`main.tf:18,14-32: Invalid for_each set argument; The for_each argument of a provider configuration must not contain null values.`,
+	})
+}
@@ -454,6 +454,8 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 	checkImpliedProviderNames(mod.ManagedResources)
 	checkImpliedProviderNames(mod.DataResources)
 
+	diags = append(diags, validateProviderInstanceRefs(mod)...)
+
 	// collect providers passed from the parent
 	if parentCall != nil {
 		for _, passed := range parentCall.Providers {
@@ -792,6 +794,53 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 	return diags
 }
 
+// validateProviderInstanceRefs checks that the references to provider
+// configurations in the given module select an instance if and only if the
+// configuration they refer to has for_each.
+func validateProviderInstanceRefs(mod *Module) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	var refs []*ProviderConfigRef
+	for _, resources := range []map[string]*Resource{mod.ManagedResources, mod.DataResources} {
+		for _, r := range resources {
+			if r.ProviderConfigRef != nil {
+				refs = append(refs, r.ProviderConfigRef)
+			}
+		}
+	}
+	for _, mc := range mod.ModuleCalls {
+		for _, passed := range mc.Providers {
+			refs = append(refs, passed.InParent)
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].NameRange.String() < refs[j].NameRange.String()
+	})
+
+	for _, ref := range refs {
+		pc := mod.ProviderConfigs[providerName(ref.Name, ref.Alias)]
+		hasForEach := pc != nil && pc.ForEach != nil
+		switch {
+		case hasForEach && ref.KeyExpression == nil:
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Provider instance key required",
+				Detail:   fmt.Sprintf("The provider configuration %s has for_each, so a reference to it must select one of its instances, such as %s[each.key].", ref, ref),
+				Subject:  ref.NameRange.Ptr(),
+			})
+		case !hasForEach && ref.KeyExpression != nil:
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unexpected provider instance key",
+				Detail:   fmt.Sprintf("The provider configuration %s does not have for_each, so a reference to it must not include an instance key. Only provider configurations declared with for_each in the same module have instances.", ref),
+				Subject:  ref.KeyExpression.Range().Ptr(),
+			})
+		}
+	}
+
+	return diags
+}
+
 func providerName(name, alias string) string {
 	if alias != "" {
 		name = name + "." + alias
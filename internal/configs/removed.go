@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+// Removed represents a "removed" block in the configuration, which declares
+// that a resource or module call was intentionally removed from the
+// configuration.
+type Removed struct {
+	From *addrs.RemoveEndpoint
+
+	// Destroy is true if the removed objects should be destroyed, as they
+	// would be if there were no "removed" block at all. If it's false, which
+	// is the default, the objects are only removed from the state and left
+	// in place in the remote system.
+	Destroy bool
+
+	DeclRange hcl.Range
+}
+
+func decodeRemovedBlock(block *hcl.Block) (*Removed, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	removed := &Removed{
+		DeclRange: block.DefRange,
+	}
+
+	content, moreDiags := block.Body.Content(removedBlockSchema)
+	diags = append(diags, moreDiags...)
+
+	if attr, exists := content.Attributes["from"]; exists {
+		from, traversalDiags := hcl.AbsTraversalForExpr(attr.Expr)
+		diags = append(diags, traversalDiags...)
+		if !traversalDiags.HasErrors() {
+			from, fromDiags := addrs.ParseRemoveEndpoint(from)
+			diags = append(diags, fromDiags.ToHCL()...)
+			removed.From = from
+		}
+	}
+
+	var seenLifecycle *hcl.Block
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "lifecycle":
+			if seenLifecycle != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate lifecycle block",
+					Detail:   "This removed block already has a lifecycle block.",
+					Subject:  block.DefRange.Ptr(),
+				})
+				continue
+			}
+			seenLifecycle = block
+
+			lcContent, lcDiags := block.Body.Content(removedLifecycleBlockSchema)
+			diags = append(diags, lcDiags...)
+
+			if attr, exists := lcContent.Attributes["destroy"]; exists {
+				valDiags := gohcl.DecodeExpression(attr.Expr, nil, &removed.Destroy)
+				diags = append(diags, valDiags...)
+			}
+
+		default:
+			// Should never happen, because the above cases should always be
+			// exhaustive for all the valid block types.
+			panic("unsupported block type " + block.Type)
+		}
+	}
+
+	return removed, diags
+}
+
+var removedBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name:     "from",
+			Required: true,
+		},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "lifecycle",
+		},
+	},
+}
+
+var removedLifecycleBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name:     "destroy",
+			Required: true,
+		},
+	},
+}
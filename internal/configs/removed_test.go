@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"testing"
+)
+
+func TestRemovedBlock_decode(t *testing.T) {
+	tests := map[string]struct {
+		src         string
+		wantFrom    string
+		wantDestroy bool
+		wantErr     string
+	}{
+		"resource": {
+			src: `
+removed {
+  from = test_instance.foo
+}
+`,
+			wantFrom: "test_instance.foo",
+		},
+		"module call": {
+			src: `
+removed {
+  from = module.foo
+}
+`,
+			wantFrom: "module.foo",
+		},
+		"lifecycle destroy false": {
+			src: `
+removed {
+  from = test_instance.foo
+  lifecycle {
+    destroy = false
+  }
+}
+`,
+			wantFrom: "test_instance.foo",
+		},
+		"lifecycle destroy true": {
+			src: `
+removed {
+  from = module.foo.test_instance.bar
+  lifecycle {
+    destroy = true
+  }
+}
+`,
+			wantFrom:    "module.foo.test_instance.bar",
+			wantDestroy: true,
+		},
+		"missing from": {
+			src: `
+removed {
+}
+`,
+			wantErr: `Missing required argument`,
+		},
+		"instance key": {
+			src: `
+removed {
+  from = test_instance.foo[0]
+}
+`,
+			wantErr: `Resource instance keys not allowed`,
+		},
+		"duplicate lifecycle": {
+			src: `
+removed {
+  from = test_instance.foo
+  lifecycle {
+    destroy = false
+  }
+  lifecycle {
+    destroy = false
+  }
+}
+`,
+			wantErr: `Duplicate lifecycle block`,
+		},
+		"lifecycle missing destroy": {
+			src: `
+removed {
+  from = test_instance.foo
+  lifecycle {
+  }
+}
+`,
+			wantErr: `Missing required argument`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			parser := testParser(map[string]string{
+				"main.tf": test.src,
+			})
+			file, diags := parser.LoadConfigFile("main.tf")
+
+			if test.wantErr != "" {
+				if !diags.HasErrors() {
+					t.Fatalf("unexpected success\nwant error: %s", test.wantErr)
+				}
+				if got := diags[0].Summary; got != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("unexpected error: %s", diags.Error())
+			}
+			if len(file.Removed) != 1 {
+				t.Fatalf("wrong number of removed blocks %d; want 1", len(file.Removed))
+			}
+
+			got := file.Removed[0]
+			if got.From.String() != test.wantFrom {
+				t.Errorf("wrong from address\ngot:  %s\nwant: %s", got.From, test.wantFrom)
+			}
+			if got.Destroy != test.wantDestroy {
+				t.Errorf("wrong destroy %t; want %t", got.Destroy, test.wantDestroy)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/lang"
@@ -47,6 +48,14 @@ type Resource struct {
 	// If this is nil, then this resource is essentially public.
 	Container Container
 
+	// IsOverridden is set while a test run is executing if the resource is
+	// targeted by an override block, or if it belongs to an overridden
+	// module. OpenTofu never calls the real provider for an overridden
+	// resource, and instead synthesizes its values from OverrideValues and
+	// the provider schema.
+	IsOverridden   bool
+	OverrideValues map[string]cty.Value
+
 	DeclRange hcl.Range
 	TypeRange hcl.Range
 }
@@ -650,6 +659,12 @@ type ProviderConfigRef struct {
 	Alias      string
 	AliasRange *hcl.Range // nil if alias not set
 
+	// KeyExpression, if not nil, is the expression that selects an instance
+	// of a provider configuration that has for_each, as in
+	// aws.by_region[each.key]. It's evaluated separately for each instance of
+	// the object that refers to the provider configuration.
+	KeyExpression hcl.Expression
+
 	// TODO: this may not be set in some cases, so it is not yet suitable for
 	// use outside of this package. We currently only use it for internal
 	// validation, but once we verify that this can be set in all cases, we can
@@ -665,8 +680,24 @@ func decodeProviderConfigRef(expr hcl.Expression, argName string) (*ProviderConf
 	expr, shimDiags = shimTraversalInString(expr, false)
 	diags = append(diags, shimDiags...)
 
+	// An instance of a provider configuration with for_each may be selected
+	// by an arbitrary key expression, which we separate from the static
+	// traversal of the configuration address.
+	var keyExpr hcl.Expression
+	if indexExpr, ok := expr.(*hclsyntax.IndexExpr); ok {
+		expr = indexExpr.Collection
+		keyExpr = indexExpr.Key
+	}
+
 	traversal, travDiags := hcl.AbsTraversalForExpr(expr)
 
+	if keyExpr == nil && len(traversal) == 3 {
+		if indexStep, ok := traversal[2].(hcl.TraverseIndex); ok {
+			keyExpr = hcl.StaticExpr(indexStep.Key, indexStep.SrcRange)
+			traversal = traversal[:2]
+		}
+	}
+
 	// AbsTraversalForExpr produces only generic errors, so we'll discard
 	// the errors given and produce our own with extra context. If we didn't
 	// get any errors then we might still have warnings, though.
@@ -728,6 +759,19 @@ func decodeProviderConfigRef(expr hcl.Expression, argName string) (*ProviderConf
 		ret.AliasRange = aliasStep.SourceRange().Ptr()
 	}
 
+	if keyExpr != nil {
+		if ret.Alias == "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid provider configuration reference",
+				Detail:   "Only a provider configuration with an alias can have instances, so an instance key must follow a period and then a configuration alias.",
+				Subject:  keyExpr.Range().Ptr(),
+			})
+			return ret, diags
+		}
+		ret.KeyExpression = keyExpr
+	}
+
 	return ret, diags
 }
 
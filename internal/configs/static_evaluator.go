@@ -0,0 +1,411 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+// StaticModuleVariables returns the value given by the caller of a module for
+// one of its input variables, for use in static evaluation.
+//
+// It returns cty.NilVal if the caller did not set the variable, in which case
+// its default value is used instead.
+type StaticModuleVariables func(v *Variable) (cty.Value, hcl.Diagnostics)
+
+// StaticModuleCall describes the call of a module as far as it's known before
+// any resources are evaluated: its address and how to find the values of its
+// input variables.
+type StaticModuleCall struct {
+	addr addrs.Module
+	vars StaticModuleVariables
+}
+
+// NewStaticModuleCall returns a StaticModuleCall for the module at the given
+// address. If vars is nil then all of the module's variables take their
+// default values.
+func NewStaticModuleCall(addr addrs.Module, vars StaticModuleVariables) StaticModuleCall {
+	return StaticModuleCall{
+		addr: addr,
+		vars: vars,
+	}
+}
+
+// RootModuleCallForTesting returns a StaticModuleCall for a root module whose
+// variables all take their default values.
+func RootModuleCallForTesting() StaticModuleCall {
+	return NewStaticModuleCall(addrs.RootModule, nil)
+}
+
+// Addr returns the address of the called module.
+func (c StaticModuleCall) Addr() addrs.Module {
+	return c.addr
+}
+
+// StaticIdentifier identifies something that is statically evaluated, for use
+// in diagnostics and to detect self-references.
+type StaticIdentifier struct {
+	Module    addrs.Module
+	Subject   string
+	DeclRange hcl.Range
+}
+
+func (ref StaticIdentifier) String() string {
+	if ref.Module.IsRoot() {
+		return ref.Subject
+	}
+	return ref.Module.String() + "." + ref.Subject
+}
+
+// StaticEvaluator evaluates expressions in a module before any of its
+// resources exist, for settings such as the backend configuration and module
+// source addresses that OpenTofu needs before it can build a graph.
+//
+// Only input variables and local values may be referenced, and only as long
+// as they don't themselves depend on anything other than variables and local
+// values. Functions are available, except that impure functions produce
+// values that are not statically known.
+//
+// Values derived from sensitive input variables are marked as sensitive.
+// Evaluate preserves those marks, while the decoding methods other than
+// DecodeSecretBody reject sensitive values because the settings they decode
+// are not treated as secrets.
+type StaticEvaluator struct {
+	module *Module
+	call   StaticModuleCall
+
+	// funcs caches the functions for the module's source directory, which
+	// may be set only after the evaluator was created.
+	funcs    map[string]function.Function
+	funcsDir string
+
+	// locals caches the result of evaluating each local value, by address,
+	// for the module's source directory in localsDir.
+	locals    map[string]staticLocalValue
+	localsDir string
+}
+
+// staticLocalValue is the cached result of statically evaluating a local value.
+type staticLocalValue struct {
+	val   cty.Value
+	diags hcl.Diagnostics
+}
+
+// NewStaticEvaluator returns a StaticEvaluator for the given module, called
+// as described by call.
+func NewStaticEvaluator(module *Module, call StaticModuleCall) *StaticEvaluator {
+	return &StaticEvaluator{
+		module: module,
+		call:   call,
+	}
+}
+
+// Ident returns a StaticIdentifier for the given subject in the evaluator's
+// module. It's safe to call on a nil evaluator.
+func (s *StaticEvaluator) Ident(subject string, rng hcl.Range) StaticIdentifier {
+	ret := StaticIdentifier{
+		Module:    addrs.RootModule,
+		Subject:   subject,
+		DeclRange: rng,
+	}
+	if s != nil {
+		ret.Module = s.call.addr
+	}
+	return ret
+}
+
+// Evaluate returns the value of the given expression, which identifies
+// itself as ident in any diagnostics. The result may be marked as sensitive.
+//
+// A nil evaluator evaluates the expression without any variables or
+// functions.
+func (s *StaticEvaluator) Evaluate(expr hcl.Expression, ident StaticIdentifier) (cty.Value, hcl.Diagnostics) {
+	if s == nil {
+		return expr.Value(nil)
+	}
+	return s.evaluate(expr, ident, nil)
+}
+
+// DecodeExpression decodes the value of the given expression into val, in
+// the same way as gohcl.DecodeExpression.
+func (s *StaticEvaluator) DecodeExpression(expr hcl.Expression, ident StaticIdentifier, val any) hcl.Diagnostics {
+	if s == nil {
+		return gohcl.DecodeExpression(expr, nil, val)
+	}
+
+	ctx, diags := s.evalContext(expr.Variables(), ident, nil)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	v, moreDiags := expr.Value(ctx)
+	if !moreDiags.HasErrors() {
+		moreDiags = checkStaticValueNotSensitive(v, ident)
+		if moreDiags.HasErrors() {
+			return append(diags, moreDiags...)
+		}
+	}
+	return append(diags, gohcl.DecodeExpression(expr, ctx, val)...)
+}
+
+// DecodeBlock decodes the given body using the given spec, in the same way as
+// hcldec.Decode.
+func (s *StaticEvaluator) DecodeBlock(body hcl.Body, spec hcldec.Spec, ident StaticIdentifier) (cty.Value, hcl.Diagnostics) {
+	if s == nil {
+		return hcldec.Decode(body, spec, nil)
+	}
+
+	ctx, diags := s.evalContext(hcldec.Variables(body, spec), ident, nil)
+	if diags.HasErrors() {
+		return cty.DynamicVal, diags
+	}
+
+	val, moreDiags := hcldec.Decode(body, spec, ctx)
+	diags = append(diags, moreDiags...)
+	if !moreDiags.HasErrors() {
+		diags = append(diags, checkStaticValueKnown(val, ident)...)
+
+		sensitiveDiags := checkStaticValueNotSensitive(val, ident)
+		if sensitiveDiags.HasErrors() {
+			return cty.DynamicVal, append(diags, sensitiveDiags...)
+		}
+	}
+	return val, diags
+}
+
+// DecodeSecretBody decodes the attributes of the given body into val, in the
+// same way as gohcl.DecodeBody.
+//
+// Unlike the other decoding methods, it accepts values derived from sensitive
+// input variables and decodes them without their marks, because it's meant
+// for settings that are secrets themselves, such as the passphrase of an
+// encryption key provider.
+func (s *StaticEvaluator) DecodeSecretBody(body hcl.Body, ident StaticIdentifier, val any) hcl.Diagnostics {
+	if s == nil {
+		return gohcl.DecodeBody(body, nil, val)
+	}
+
+	// Any problems with the body's content itself are reported by
+	// gohcl.DecodeBody below, so here we only look for references.
+	schema, _ := gohcl.ImpliedBodySchema(val)
+	content, _, _ := body.PartialContent(schema)
+	var traversals []hcl.Traversal
+	for _, attr := range content.Attributes {
+		traversals = append(traversals, attr.Expr.Variables()...)
+	}
+
+	ctx, diags := s.evalContext(traversals, ident, nil)
+	if diags.HasErrors() {
+		return diags
+	}
+	for name, v := range ctx.Variables {
+		ctx.Variables[name], _ = v.UnmarkDeep()
+	}
+	return append(diags, gohcl.DecodeBody(body, ctx, val)...)
+}
+
+// evaluate is the recursive part of Evaluate, where stack holds everything
+// that's currently being evaluated so that self-references can be detected.
+func (s *StaticEvaluator) evaluate(expr hcl.Expression, ident StaticIdentifier, stack []StaticIdentifier) (cty.Value, hcl.Diagnostics) {
+	ctx, diags := s.evalContext(expr.Variables(), ident, stack)
+	if diags.HasErrors() {
+		return cty.DynamicVal, diags
+	}
+
+	val, moreDiags := expr.Value(ctx)
+	diags = append(diags, moreDiags...)
+	if !moreDiags.HasErrors() {
+		diags = append(diags, checkStaticValueKnown(val, ident)...)
+	}
+	return val, diags
+}
+
+func (s *StaticEvaluator) evalContext(traversals []hcl.Traversal, ident StaticIdentifier, stack []StaticIdentifier) (*hcl.EvalContext, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	for _, prev := range stack {
+		if prev.String() == ident.String() {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Circular reference",
+				Detail:   fmt.Sprintf("%s is self-referential, so its value cannot be determined.", ident),
+				Subject:  ident.DeclRange.Ptr(),
+			})
+			return nil, diags
+		}
+	}
+	stack = append(stack, ident)
+
+	refs, refDiags := lang.References(addrs.ParseRef, traversals)
+	diags = append(diags, refDiags.ToHCL()...)
+	if refDiags.HasErrors() {
+		return nil, diags
+	}
+
+	vars := map[string]cty.Value{}
+	locals := map[string]cty.Value{}
+	for _, ref := range refs {
+		switch subject := ref.Subject.(type) {
+		case addrs.InputVariable:
+			val, moreDiags := s.variableValue(subject, ref, ident)
+			diags = append(diags, moreDiags...)
+			vars[subject.Name] = val
+		case addrs.LocalValue:
+			val, moreDiags := s.localValue(subject, ref, ident, stack)
+			diags = append(diags, moreDiags...)
+			locals[subject.Name] = val
+		default:
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Dynamic value in static context",
+				Detail:   fmt.Sprintf("Unable to use %s in static context, which is required by %s. Only input variables and local values that don't depend on other objects may be used here.", ref.Subject, ident),
+				Subject:  ref.SourceRange.ToHCL().Ptr(),
+			})
+		}
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	if s.funcs == nil || s.funcsDir != s.module.SourceDir {
+		scope := &lang.Scope{
+			BaseDir:  s.module.SourceDir,
+			PureOnly: true,
+		}
+		s.funcs = scope.Functions()
+		s.funcsDir = s.module.SourceDir
+	}
+
+	return &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var":   cty.ObjectVal(vars),
+			"local": cty.ObjectVal(locals),
+		},
+		Functions: s.funcs,
+	}, diags
+}
+
+func (s *StaticEvaluator) variableValue(addr addrs.InputVariable, ref *addrs.Reference, ident StaticIdentifier) (cty.Value, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	v, exists := s.module.Variables[addr.Name]
+	if !exists {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Reference to undeclared input variable",
+			Detail:   fmt.Sprintf("An input variable with the name %q has not been declared. This variable can be declared with a variable %q {} block.", addr.Name, addr.Name),
+			Subject:  ref.SourceRange.ToHCL().Ptr(),
+		})
+		return cty.DynamicVal, diags
+	}
+
+	val := cty.NilVal
+	if s.call.vars != nil {
+		var moreDiags hcl.Diagnostics
+		val, moreDiags = s.call.vars(v)
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			return cty.DynamicVal, diags
+		}
+	}
+	if val == cty.NilVal || (val.IsNull() && !v.Nullable) {
+		val = v.Default
+	}
+	if val == cty.NilVal {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Variable value not provided",
+			Detail:   fmt.Sprintf("The input variable %q is required by %s, which is evaluated statically, but no value was given for it and it has no default value.", addr.Name, ident),
+			Subject:  ref.SourceRange.ToHCL().Ptr(),
+		})
+		return cty.DynamicVal, diags
+	}
+
+	// The caller's value may be sensitive, so we set aside its marks while
+	// we apply defaults and convert it.
+	val, pvm := val.UnmarkDeepWithPaths()
+	if v.TypeDefaults != nil && !val.IsNull() {
+		val = v.TypeDefaults.Apply(val)
+	}
+	val, err := convert.Convert(val, v.ConstraintType)
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid value for input variable",
+			Detail:   fmt.Sprintf("The value given for input variable %q is not suitable: %s.", addr.Name, err),
+			Subject:  v.DeclRange.Ptr(),
+		})
+		return cty.DynamicVal, diags
+	}
+	val = val.MarkWithPaths(pvm)
+	if v.Sensitive {
+		val = val.Mark(marks.Sensitive)
+	}
+	return val, diags
+}
+
+func (s *StaticEvaluator) localValue(addr addrs.LocalValue, ref *addrs.Reference, ident StaticIdentifier, stack []StaticIdentifier) (cty.Value, hcl.Diagnostics) {
+	l, exists := s.module.Locals[addr.Name]
+	if !exists {
+		return cty.DynamicVal, hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Reference to undeclared local value",
+			Detail:   fmt.Sprintf("A local value with the name %q has not been declared.", addr.Name),
+			Subject:  ref.SourceRange.ToHCL().Ptr(),
+		}}
+	}
+
+	if s.locals == nil || s.localsDir != s.module.SourceDir {
+		s.locals = make(map[string]staticLocalValue)
+		s.localsDir = s.module.SourceDir
+	}
+	key := addr.String()
+	if cached, ok := s.locals[key]; ok {
+		return cached.val, cached.diags
+	}
+
+	val, diags := s.evaluate(l.Expr, s.Ident(key, l.DeclRange), stack)
+	s.locals[key] = staticLocalValue{val: val, diags: diags}
+	return val, diags
+}
+
+// checkStaticValueKnown returns an error if the given value isn't wholly
+// known, which happens when it depends on an impure function.
+func checkStaticValueKnown(val cty.Value, ident StaticIdentifier) hcl.Diagnostics {
+	if val.IsWhollyKnown() {
+		return nil
+	}
+	return hcl.Diagnostics{{
+		Severity: hcl.DiagError,
+		Summary:  "Value not statically known",
+		Detail:   fmt.Sprintf("The value of %s must be known before any resources are created, but it depends on values that can only be determined during apply.", ident),
+		Subject:  ident.DeclRange.Ptr(),
+	}}
+}
+
+// checkStaticValueNotSensitive returns an error if the given value contains
+// anything derived from a sensitive input variable, because the settings that
+// are decoded statically are saved and displayed without redaction.
+func checkStaticValueNotSensitive(val cty.Value, ident StaticIdentifier) hcl.Diagnostics {
+	if !marks.Contains(val, marks.Sensitive) {
+		return nil
+	}
+	return hcl.Diagnostics{{
+		Severity: hcl.DiagError,
+		Summary:  "Sensitive value in static context",
+		Detail:   fmt.Sprintf("The value of %s is derived from a sensitive input variable, but it will be saved and displayed without redaction, so it cannot use sensitive values.", ident),
+		Subject:  ident.DeclRange.Ptr(),
+	}}
+}
@@ -0,0 +1,340 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+func TestStaticEvaluator_Evaluate(t *testing.T) {
+	src := `
+variable "str" {
+  type    = string
+  default = "default"
+}
+
+variable "num" {
+  type = number
+}
+
+variable "obj" {
+  type = object({
+    a = optional(string, "a")
+  })
+  default = {}
+}
+
+variable "secret" {
+  type      = string
+  default   = "hunter2"
+  sensitive = true
+}
+
+locals {
+  static  = "${var.str}-${var.num}"
+  nested  = upper(local.static)
+  with_obj = var.obj.a
+  dynamic = aws_instance.foo.id
+  impure  = timestamp()
+  cycle_a = local.cycle_b
+  cycle_b = local.cycle_a
+  missing = var.undeclared
+  secret  = "password: ${var.secret}"
+}
+
+resource "aws_instance" "foo" {}
+`
+	parser := testParser(map[string]string{"eval.tf": src})
+	file, diags := parser.LoadConfigFile("eval.tf")
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	vars := func(v *Variable) (cty.Value, hcl.Diagnostics) {
+		if v.Name == "num" {
+			return cty.StringVal("2"), nil
+		}
+		return cty.NilVal, nil
+	}
+	mod, diags := NewModule([]*File{file}, nil, NewStaticModuleCall(addrs.RootModule, vars))
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	tests := map[string]struct {
+		expr    string
+		want    cty.Value
+		wantErr string
+	}{
+		"literal": {
+			expr: `"hello"`,
+			want: cty.StringVal("hello"),
+		},
+		"variable from caller": {
+			expr: `var.num`,
+			want: cty.NumberIntVal(2),
+		},
+		"variable default": {
+			expr: `var.str`,
+			want: cty.StringVal("default"),
+		},
+		"variable type defaults": {
+			expr: `var.obj.a`,
+			want: cty.StringVal("a"),
+		},
+		"locals": {
+			expr: `local.nested`,
+			want: cty.StringVal("DEFAULT-2"),
+		},
+		"resource reference": {
+			expr:    `aws_instance.foo.id`,
+			wantErr: "Dynamic value in static context",
+		},
+		"indirect resource reference": {
+			expr:    `local.dynamic`,
+			wantErr: "Dynamic value in static context",
+		},
+		"impure function": {
+			expr:    `local.impure`,
+			wantErr: "Value not statically known",
+		},
+		"circular reference": {
+			expr:    `local.cycle_a`,
+			wantErr: "Circular reference",
+		},
+		"undeclared variable": {
+			expr:    `local.missing`,
+			wantErr: "Reference to undeclared input variable",
+		},
+		"sensitive variable": {
+			expr: `var.secret`,
+			want: cty.StringVal("hunter2").Mark(marks.Sensitive),
+		},
+		"sensitive local": {
+			expr: `local.secret`,
+			want: cty.StringVal("password: hunter2").Mark(marks.Sensitive),
+		},
+		"undeclared local": {
+			expr:    `local.nope`,
+			wantErr: "Reference to undeclared local value",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			expr, diags := hclsyntax.ParseExpression([]byte(test.expr), "test.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			got, diags := mod.StaticEvaluator.Evaluate(expr, mod.StaticEvaluator.Ident("test", expr.Range()))
+			if test.wantErr != "" {
+				if !diags.HasErrors() {
+					t.Fatalf("unexpected success; want error containing %q", test.wantErr)
+				}
+				if !strings.Contains(diags.Error(), test.wantErr) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", diags.Error(), test.wantErr)
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+			if !got.RawEquals(test.want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestStaticEvaluator_localsCached(t *testing.T) {
+	parser := testParser(map[string]string{"eval.tf": `
+locals {
+  a = "a"
+  b = "${local.a}-b"
+}
+`})
+	file, diags := parser.LoadConfigFile("eval.tf")
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	mod, diags := NewModule([]*File{file}, nil, RootModuleCallForTesting())
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	evaluate := func() cty.Value {
+		t.Helper()
+		expr, diags := hclsyntax.ParseExpression([]byte(`local.b`), "test.tf", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		got, diags := mod.StaticEvaluator.Evaluate(expr, mod.StaticEvaluator.Ident("test", expr.Range()))
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		return got
+	}
+
+	want := cty.StringVal("a-b")
+	if got := evaluate(); !got.RawEquals(want) {
+		t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	// Once evaluated, the locals are not evaluated again.
+	mod.Locals["a"].Expr = hcl.StaticExpr(cty.StringVal("changed"), hcl.Range{})
+	mod.Locals["b"].Expr = hcl.StaticExpr(cty.StringVal("changed"), hcl.Range{})
+	if got := evaluate(); !got.RawEquals(want) {
+		t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestStaticEvaluator_sensitive(t *testing.T) {
+	parser := testParser(map[string]string{"root/main.tf": `
+variable "secret" {
+  type      = string
+  default   = "example"
+  sensitive = true
+}
+
+locals {
+  path = "${var.secret}.tfstate"
+}
+
+terraform {
+  backend "local" {
+    path = local.path
+  }
+}
+
+module "child" {
+  source = "./${var.secret}"
+}
+`})
+	mod, diags := parser.LoadConfigDir("root", RootModuleCallForTesting())
+	if !diags.HasErrors() {
+		t.Fatal("unexpected success decoding sensitive module source")
+	}
+	if got, want := diags.Error(), "Sensitive value in static context"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"path": {Type: cty.String, Optional: true},
+		},
+	}
+	_, diags = mod.Backend.Decode(schema)
+	if !diags.HasErrors() {
+		t.Fatal("unexpected success decoding sensitive backend configuration")
+	}
+	if got, want := diags.Error(), "Sensitive value in static context"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestStaticEvaluator_variableNotProvided(t *testing.T) {
+	mod, diags := testModuleFromDir("testdata/invalid-modules/static-variable-not-provided")
+	if !diags.HasErrors() {
+		t.Fatal("unexpected success")
+	}
+	if got, want := diags.Error(), "Variable value not provided"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+	if mod == nil {
+		t.Fatal("no module returned")
+	}
+}
+
+func TestStaticEvaluator_module(t *testing.T) {
+	mod, diags := testModuleFromDir("testdata/valid-modules/static-evaluation")
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	if got, want := len(mod.CoreVersionConstraints), 1; got != want {
+		t.Fatalf("wrong number of core version constraints %d; want %d", got, want)
+	}
+	if got, want := mod.CoreVersionConstraints[0].Required.String(), "~> 1.0"; got != want {
+		t.Errorf("wrong core version constraint %q; want %q", got, want)
+	}
+
+	call := mod.ModuleCalls["foo"]
+	if got, want := call.SourceAddr.String(), "./modules/example"; got != want {
+		t.Errorf("wrong module source %q; want %q", got, want)
+	}
+
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"path": {Type: cty.String, Optional: true},
+		},
+	}
+	val, diags := mod.Backend.Decode(schema)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	if got, want := val.GetAttr("path"), cty.StringVal("example.tfstate"); !got.RawEquals(want) {
+		t.Errorf("wrong backend path %#v; want %#v", got, want)
+	}
+}
+
+func TestStaticEvaluator_nestedModule(t *testing.T) {
+	parser := testParser(map[string]string{
+		"root/main.tf": `
+variable "env" {
+  type    = string
+  default = "prod"
+}
+
+module "child" {
+  source = "./child"
+  env    = var.env
+}
+`,
+		"root/child/main.tf": `
+variable "env" {
+  type = string
+}
+
+module "grandchild" {
+  source = "./${var.env}"
+}
+`,
+		"root/child/prod/main.tf": ``,
+	})
+
+	mod, diags := parser.LoadConfigDir("root", RootModuleCallForTesting())
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	cfg, diags := BuildConfig(mod, ModuleWalkerFunc(
+		func(req *ModuleRequest) (*Module, *version.Version, hcl.Diagnostics) {
+			dir := "root/" + strings.Join(req.Path, "/")
+			if len(req.Path) == 2 {
+				dir = "root/child/prod"
+			}
+			mod, diags := parser.LoadConfigDir(dir, req.Call)
+			return mod, nil, diags
+		},
+	))
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	grandchild := cfg.Children["child"].Module.ModuleCalls["grandchild"]
+	if got, want := grandchild.SourceAddr.String(), "./prod"; got != want {
+		t.Errorf("wrong module source %q; want %q", got, want)
+	}
+}
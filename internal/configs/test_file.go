@@ -52,6 +52,12 @@ type TestFile struct {
 	// test.
 	Providers map[string]*Provider
 
+	// OverrideResources and OverrideModules define the resources, data
+	// sources and modules that are overridden with fixed values for every run
+	// block within the test file.
+	OverrideResources []*OverrideResource
+	OverrideModules   []*OverrideModule
+
 	// Runs defines the sequential list of run blocks that should be executed in
 	// order.
 	Runs []*TestRun
@@ -119,6 +125,17 @@ type TestRun struct {
 	// run.
 	ExpectFailures []hcl.Traversal
 
+	// OverrideResources and OverrideModules define the resources, data
+	// sources and modules that are overridden with fixed values for this run
+	// block only. They take precedence over overrides defined by the file.
+	OverrideResources []*OverrideResource
+	OverrideModules   []*OverrideModule
+
+	// Parallel marks the run block as safe to execute at the same time as
+	// neighbouring run blocks that are also marked as parallel, as long as
+	// they use different states.
+	Parallel bool
+
 	NameDeclRange      hcl.Range
 	VariablesDeclRange hcl.Range
 	DeclRange          hcl.Range
@@ -227,9 +244,36 @@ func loadTestFile(body hcl.Body) (*TestFile, hcl.Diagnostics) {
 		case "provider":
 			provider, providerDiags := decodeProviderBlock(block)
 			diags = append(diags, providerDiags...)
+			if provider != nil && provider.ForEach != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid provider configuration for_each",
+					Detail:   "The for_each argument is not supported in provider configurations in test files.",
+					Subject:  provider.ForEach.Range().Ptr(),
+				})
+				provider = nil
+			}
+			if provider != nil {
+				tf.Providers[provider.moduleUniqueKey()] = provider
+			}
+		case "mock_provider":
+			provider, providerDiags := decodeMockProviderBlock(block)
+			diags = append(diags, providerDiags...)
 			if provider != nil {
 				tf.Providers[provider.moduleUniqueKey()] = provider
 			}
+		case "override_resource", "override_data":
+			override, overrideDiags := decodeOverrideResourceBlock(block)
+			diags = append(diags, overrideDiags...)
+			if !overrideDiags.HasErrors() {
+				tf.OverrideResources = append(tf.OverrideResources, override)
+			}
+		case "override_module":
+			override, overrideDiags := decodeOverrideModuleBlock(block)
+			diags = append(diags, overrideDiags...)
+			if !overrideDiags.HasErrors() {
+				tf.OverrideModules = append(tf.OverrideModules, override)
+			}
 		}
 	}
 
@@ -305,6 +349,18 @@ func decodeTestRunBlock(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
 			if !moduleDiags.HasErrors() {
 				r.Module = module
 			}
+		case "override_resource", "override_data":
+			override, overrideDiags := decodeOverrideResourceBlock(block)
+			diags = append(diags, overrideDiags...)
+			if !overrideDiags.HasErrors() {
+				r.OverrideResources = append(r.OverrideResources, override)
+			}
+		case "override_module":
+			override, overrideDiags := decodeOverrideModuleBlock(block)
+			diags = append(diags, overrideDiags...)
+			if !overrideDiags.HasErrors() {
+				r.OverrideModules = append(r.OverrideModules, override)
+			}
 		}
 	}
 
@@ -354,6 +410,11 @@ func decodeTestRunBlock(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
 		r.ExpectFailures = failures
 	}
 
+	if attr, exists := content.Attributes["parallel"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &r.Parallel)
+		diags = append(diags, valDiags...)
+	}
+
 	return &r, diags
 }
 
@@ -531,9 +592,22 @@ var testFileSchema = &hcl.BodySchema{
 			Type:       "provider",
 			LabelNames: []string{"name"},
 		},
+		{
+			Type:       "mock_provider",
+			LabelNames: []string{"name"},
+		},
 		{
 			Type: "variables",
 		},
+		{
+			Type: "override_resource",
+		},
+		{
+			Type: "override_data",
+		},
+		{
+			Type: "override_module",
+		},
 	},
 }
 
@@ -542,6 +616,7 @@ var testRunBlockSchema = &hcl.BodySchema{
 		{Name: "command"},
 		{Name: "providers"},
 		{Name: "expect_failures"},
+		{Name: "parallel"},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{
@@ -556,6 +631,15 @@ var testRunBlockSchema = &hcl.BodySchema{
 		{
 			Type: "module",
 		},
+		{
+			Type: "override_resource",
+		},
+		{
+			Type: "override_data",
+		},
+		{
+			Type: "override_module",
+		},
 	},
 }
 
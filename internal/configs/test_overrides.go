@@ -0,0 +1,430 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+// MockResource represents a "mock_resource" or "mock_data" block within a
+// "mock_provider" block in a test file.
+//
+// The Defaults are used in place of generated values for computed attributes
+// whenever the mocked provider produces an object of the given type.
+type MockResource struct {
+	Mode     addrs.ResourceMode
+	Type     string
+	Defaults map[string]cty.Value
+
+	DeclRange hcl.Range
+	TypeRange hcl.Range
+}
+
+// OverrideResource represents an "override_resource" or "override_data" block
+// within a test file or a run block.
+//
+// An overridden resource is never planned, applied or read by its real
+// provider. OpenTofu instead uses the fixed Values given here, synthesizing
+// values for any other computed attributes from the provider schema.
+type OverrideResource struct {
+	Target       hcl.Traversal
+	TargetParsed addrs.ConfigResource
+
+	Values map[string]cty.Value
+
+	DeclRange hcl.Range
+}
+
+// OverrideModule represents an "override_module" block within a test file or
+// a run block.
+//
+// None of the resources within an overridden module are passed to their real
+// providers, and the outputs of the module are replaced with the fixed
+// Outputs given here. Outputs that are not specified are null.
+type OverrideModule struct {
+	Target       hcl.Traversal
+	TargetParsed addrs.Module
+
+	Outputs map[string]cty.Value
+
+	DeclRange hcl.Range
+}
+
+func decodeMockProviderBlock(block *hcl.Block) (*Provider, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, config, contentDiags := block.Body.PartialContent(mockProviderBlockSchema)
+	diags = append(diags, contentDiags...)
+
+	name := block.Labels[0]
+	nameDiags := checkProviderNameNormalized(name, block.DefRange)
+	diags = append(diags, nameDiags...)
+	if nameDiags.HasErrors() {
+		return nil, diags
+	}
+
+	provider := &Provider{
+		Name:      name,
+		NameRange: block.LabelRanges[0],
+		Config:    config,
+		DeclRange: block.DefRange,
+		IsMocked:  true,
+	}
+
+	if attr, exists := content.Attributes["alias"]; exists {
+		alias, aliasDiags := decodeProviderAlias(attr)
+		diags = append(diags, aliasDiags...)
+		provider.Alias = alias
+		provider.AliasRange = attr.Expr.Range().Ptr()
+	}
+
+	seen := make(map[string]*MockResource)
+	for _, block := range content.Blocks {
+		resource := &MockResource{
+			Type:      block.Labels[0],
+			DeclRange: block.DefRange,
+			TypeRange: block.LabelRanges[0],
+		}
+
+		switch block.Type {
+		case "mock_resource":
+			resource.Mode = addrs.ManagedResourceMode
+		case "mock_data":
+			resource.Mode = addrs.DataResourceMode
+		}
+
+		key := fmt.Sprintf("%s.%s", resource.Mode, resource.Type)
+		if existing, exists := seen[key]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Duplicate %q block", block.Type),
+				Detail:   fmt.Sprintf("A %s block for %q was already declared at %s.", block.Type, resource.Type, existing.DeclRange),
+				Subject:  block.DefRange.Ptr(),
+			})
+			continue
+		}
+		seen[key] = resource
+
+		content, contentDiags := block.Body.Content(mockResourceBlockSchema)
+		diags = append(diags, contentDiags...)
+
+		if attr, exists := content.Attributes["defaults"]; exists {
+			defaults, valueDiags := decodeOverrideObject(attr)
+			diags = append(diags, valueDiags...)
+			resource.Defaults = defaults
+		}
+
+		provider.MockResources = append(provider.MockResources, resource)
+	}
+
+	return provider, diags
+}
+
+func decodeOverrideResourceBlock(block *hcl.Block) (*OverrideResource, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := block.Body.Content(overrideResourceBlockSchema)
+	diags = append(diags, contentDiags...)
+
+	override := &OverrideResource{
+		DeclRange: block.DefRange,
+	}
+
+	if attr, exists := content.Attributes["target"]; exists {
+		traversal, traversalDiags := hcl.AbsTraversalForExpr(attr.Expr)
+		diags = append(diags, traversalDiags...)
+		if !traversalDiags.HasErrors() {
+			override.Target = traversal
+
+			target, targetDiags := addrs.ParseTarget(traversal)
+			diags = append(diags, targetDiags.ToHCL()...)
+			if !targetDiags.HasErrors() {
+				resource, ok := target.Subject.(addrs.AbsResource)
+				if !ok || resource.Module.Module().String() != resource.Module.String() {
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid override target",
+						Detail:   fmt.Sprintf("The target of an %s block must be a resource address without any instance keys.", block.Type),
+						Subject:  attr.Expr.Range().Ptr(),
+					})
+				} else {
+					override.TargetParsed = resource.Config()
+
+					wantMode := addrs.ManagedResourceMode
+					if block.Type == "override_data" {
+						wantMode = addrs.DataResourceMode
+					}
+					if override.TargetParsed.Resource.Mode != wantMode {
+						diags = append(diags, &hcl.Diagnostic{
+							Severity: hcl.DiagError,
+							Summary:  "Invalid override target",
+							Detail:   fmt.Sprintf("An override_resource block can only target managed resources, and an override_data block can only target data sources; %s is not valid here.", override.TargetParsed),
+							Subject:  attr.Expr.Range().Ptr(),
+						})
+					}
+				}
+			}
+		}
+	}
+
+	if attr, exists := content.Attributes["values"]; exists {
+		values, valueDiags := decodeOverrideObject(attr)
+		diags = append(diags, valueDiags...)
+		override.Values = values
+	}
+
+	return override, diags
+}
+
+func decodeOverrideModuleBlock(block *hcl.Block) (*OverrideModule, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := block.Body.Content(overrideModuleBlockSchema)
+	diags = append(diags, contentDiags...)
+
+	override := &OverrideModule{
+		DeclRange: block.DefRange,
+	}
+
+	if attr, exists := content.Attributes["target"]; exists {
+		traversal, traversalDiags := hcl.AbsTraversalForExpr(attr.Expr)
+		diags = append(diags, traversalDiags...)
+		if !traversalDiags.HasErrors() {
+			override.Target = traversal
+
+			target, targetDiags := addrs.ParseTarget(traversal)
+			diags = append(diags, targetDiags.ToHCL()...)
+			if !targetDiags.HasErrors() {
+				module, ok := target.Subject.(addrs.ModuleInstance)
+				if !ok || module.IsRoot() || module.Module().String() != module.String() {
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid override target",
+						Detail:   "The target of an override_module block must be a module call address without any instance keys.",
+						Subject:  attr.Expr.Range().Ptr(),
+					})
+				} else {
+					override.TargetParsed = module.Module()
+				}
+			}
+		}
+	}
+
+	if attr, exists := content.Attributes["outputs"]; exists {
+		outputs, valueDiags := decodeOverrideObject(attr)
+		diags = append(diags, valueDiags...)
+		override.Outputs = outputs
+	}
+
+	return override, diags
+}
+
+// decodeOverrideObject decodes the given attribute as a constant object value,
+// returning its attributes.
+func decodeOverrideObject(attr *hcl.Attribute) (map[string]cty.Value, hcl.Diagnostics) {
+	val, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	if val.IsNull() {
+		return nil, diags
+	}
+
+	if !val.Type().IsObjectType() && !val.Type().IsMapType() || !val.IsWhollyKnown() {
+		return nil, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Invalid %q argument", attr.Name),
+			Detail:   fmt.Sprintf("The %q argument must be an object.", attr.Name),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+	}
+
+	return val.AsValueMap(), diags
+}
+
+// transformOverridesForTest marks the resources and modules targeted by the
+// override blocks in the given test file and run block as overridden, and
+// returns a function that reverts those changes.
+//
+// Overrides from the run block take precedence over overrides from the file,
+// and overrides of individual resources take precedence over the overrides of
+// the modules containing them.
+func (c *Config) transformOverridesForTest(run *TestRun, file *TestFile) (func(), hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	var resets []func()
+
+	overrideModules := file.OverrideModules
+	overrideResources := file.OverrideResources
+	if run != nil {
+		overrideModules = append(overrideModules[:len(overrideModules):len(overrideModules)], run.OverrideModules...)
+		overrideResources = append(overrideResources[:len(overrideResources):len(overrideResources)], run.OverrideResources...)
+	}
+
+	for _, override := range overrideModules {
+		module := c.Descendent(override.TargetParsed)
+		if module == nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Override target not found",
+				Detail:   fmt.Sprintf("The configuration under test does not contain %s, so this override has no effect.", override.TargetParsed),
+				Subject:  override.DeclRange.Ptr(),
+			})
+			continue
+		}
+
+		for name := range override.Outputs {
+			if _, exists := module.Module.Outputs[name]; !exists {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid override output",
+					Detail:   fmt.Sprintf("The module %s does not declare an output named %q.", override.TargetParsed, name),
+					Subject:  override.DeclRange.Ptr(),
+				})
+			}
+		}
+
+		for _, output := range module.Module.Outputs {
+			output := output
+			previous := output.Expr
+
+			value, exists := override.Outputs[output.Name]
+			if !exists {
+				value = cty.NullVal(cty.DynamicPseudoType)
+			}
+			output.Expr = hcl.StaticExpr(value, override.DeclRange)
+			resets = append(resets, func() {
+				output.Expr = previous
+			})
+		}
+
+		module.DeepEach(func(descendent *Config) {
+			for _, resource := range descendent.Module.ManagedResources {
+				resets = append(resets, overrideResourceForTest(resource, nil))
+			}
+			for _, resource := range descendent.Module.DataResources {
+				resets = append(resets, overrideResourceForTest(resource, nil))
+			}
+		})
+	}
+
+	for _, override := range overrideResources {
+		var resource *Resource
+		if module := c.Descendent(override.TargetParsed.Module); module != nil {
+			resource = module.Module.ResourceByAddr(override.TargetParsed.Resource)
+		}
+		if resource == nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Override target not found",
+				Detail:   fmt.Sprintf("The configuration under test does not contain %s, so this override has no effect.", override.TargetParsed),
+				Subject:  override.DeclRange.Ptr(),
+			})
+			continue
+		}
+
+		resets = append(resets, overrideResourceForTest(resource, override.Values))
+	}
+
+	return func() {
+		// Revert in reverse order so that resources overridden more than once
+		// end up back in their original state.
+		for ix := len(resets) - 1; ix >= 0; ix-- {
+			resets[ix]()
+		}
+	}, diags
+}
+
+func overrideResourceForTest(resource *Resource, values map[string]cty.Value) func() {
+	previousIsOverridden, previousValues := resource.IsOverridden, resource.OverrideValues
+	resource.IsOverridden = true
+	resource.OverrideValues = values
+	return func() {
+		resource.IsOverridden = previousIsOverridden
+		resource.OverrideValues = previousValues
+	}
+}
+
+var mockProviderBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "alias"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "mock_resource",
+			LabelNames: []string{"type"},
+		},
+		{
+			Type:       "mock_data",
+			LabelNames: []string{"type"},
+		},
+	},
+}
+
+var mockResourceBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "defaults"},
+	},
+}
+
+var overrideResourceBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "target", Required: true},
+		{Name: "values"},
+	},
+}
+
+var overrideModuleBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "target", Required: true},
+		{Name: "outputs"},
+	},
+}
+
+// copyForTest returns a copy of the module with its own copies of everything
+// that TransformForTest and the test command modify: the provider
+// configurations, variables, outputs and resources.
+func (m *Module) copyForTest() *Module {
+	if m == nil {
+		return nil
+	}
+
+	ret := new(Module)
+	*ret = *m
+
+	ret.ProviderConfigs = make(map[string]*Provider, len(m.ProviderConfigs))
+	for key, provider := range m.ProviderConfigs {
+		ret.ProviderConfigs[key] = provider
+	}
+
+	ret.Variables = make(map[string]*Variable, len(m.Variables))
+	for name, variable := range m.Variables {
+		ret.Variables[name] = variable
+	}
+
+	ret.Outputs = make(map[string]*Output, len(m.Outputs))
+	for name, output := range m.Outputs {
+		output := *output
+		ret.Outputs[name] = &output
+	}
+
+	ret.ManagedResources = copyResourcesForTest(m.ManagedResources)
+	ret.DataResources = copyResourcesForTest(m.DataResources)
+
+	return ret
+}
+
+func copyResourcesForTest(resources map[string]*Resource) map[string]*Resource {
+	ret := make(map[string]*Resource, len(resources))
+	for key, resource := range resources {
+		resource := *resource
+		ret[key] = &resource
+	}
+	return ret
+}
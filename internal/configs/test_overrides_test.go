@@ -0,0 +1,216 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+func TestLoadTestFile_mocksAndOverrides(t *testing.T) {
+	parser := testParser(map[string]string{
+		"main.tftest.hcl": `
+mock_provider "aws" {
+  alias = "mocked"
+
+  mock_resource "aws_instance" {
+    defaults = {
+      id = "i-mocked"
+    }
+  }
+
+  mock_data "aws_ami" {}
+}
+
+override_resource {
+  target = module.child.aws_instance.web
+  values = {
+    id = "i-overridden"
+  }
+}
+
+override_data {
+  target = data.aws_ami.ubuntu
+}
+
+run "test" {
+  override_module {
+    target = module.child
+    outputs = {
+      name = "overridden"
+    }
+  }
+}
+`,
+	})
+
+	file, diags := parser.LoadTestFile("main.tftest.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	provider, ok := file.Providers["aws.mocked"]
+	if !ok {
+		t.Fatalf("missing mocked provider")
+	}
+	if !provider.IsMocked {
+		t.Errorf("provider should be mocked")
+	}
+	if got, want := len(provider.MockResources), 2; got != want {
+		t.Fatalf("wrong number of mock resources %d; want %d", got, want)
+	}
+	if got := provider.MockResources[0]; got.Mode != addrs.ManagedResourceMode || got.Type != "aws_instance" || !got.Defaults["id"].RawEquals(cty.StringVal("i-mocked")) {
+		t.Errorf("wrong mock resource %#v", got)
+	}
+	if got := provider.MockResources[1]; got.Mode != addrs.DataResourceMode || got.Type != "aws_ami" || got.Defaults != nil {
+		t.Errorf("wrong mock data source %#v", got)
+	}
+
+	if got, want := len(file.OverrideResources), 2; got != want {
+		t.Fatalf("wrong number of resource overrides %d; want %d", got, want)
+	}
+	if got, want := file.OverrideResources[0].TargetParsed.String(), "module.child.aws_instance.web"; got != want {
+		t.Errorf("wrong target %q; want %q", got, want)
+	}
+	if got, want := file.OverrideResources[1].TargetParsed.String(), "data.aws_ami.ubuntu"; got != want {
+		t.Errorf("wrong target %q; want %q", got, want)
+	}
+
+	run := file.Runs[0]
+	if got, want := len(run.OverrideModules), 1; got != want {
+		t.Fatalf("wrong number of module overrides %d; want %d", got, want)
+	}
+	if got, want := run.OverrideModules[0].TargetParsed.String(), "module.child"; got != want {
+		t.Errorf("wrong target %q; want %q", got, want)
+	}
+	if got, want := run.OverrideModules[0].Outputs["name"], cty.StringVal("overridden"); !got.RawEquals(want) {
+		t.Errorf("wrong output value %#v; want %#v", got, want)
+	}
+}
+
+func TestLoadTestFile_invalidOverrides(t *testing.T) {
+	tcs := map[string]struct {
+		src  string
+		want string
+	}{
+		"data target for resource": {
+			src: `
+override_resource {
+  target = data.aws_ami.ubuntu
+}
+`,
+			want: "Invalid override target",
+		},
+		"instance key": {
+			src: `
+override_resource {
+  target = aws_instance.web[0]
+}
+`,
+			want: "Invalid override target",
+		},
+		"module instance key": {
+			src: `
+override_module {
+  target = module.child["a"]
+}
+`,
+			want: "Invalid override target",
+		},
+		"values not an object": {
+			src: `
+override_resource {
+  target = aws_instance.web
+  values = "nope"
+}
+`,
+			want: "Invalid \"values\" argument",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			parser := testParser(map[string]string{
+				"main.tftest.hcl": tc.src,
+			})
+
+			_, diags := parser.LoadTestFile("main.tftest.hcl")
+			if !diags.HasErrors() {
+				t.Fatalf("expected errors, got none")
+			}
+			if got := diags[0].Summary; got != tc.want {
+				t.Errorf("wrong error %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTransformForTest_overrides(t *testing.T) {
+	cfg, diags := testNestedModuleConfigFromDir(t, "testdata/valid-modules/nested-providers-fqns")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	parser := testParser(map[string]string{
+		"main.tftest.hcl": `
+override_resource {
+  target = test_instance.explicit
+  values = {
+    id = "file"
+  }
+}
+
+override_module {
+  target = module.child
+}
+
+run "test" {
+  override_resource {
+    target = test_instance.explicit
+    values = {
+      id = "run"
+    }
+  }
+}
+`,
+	})
+	file, diags := parser.LoadTestFile("main.tftest.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	resource := cfg.Module.ResourceByAddr(addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "test_instance", Name: "explicit"})
+	child := cfg.Children["child"]
+
+	reset, diags := cfg.TransformForTest(file.Runs[0], file)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	if !resource.IsOverridden {
+		t.Errorf("resource should be overridden")
+	}
+	if got, want := resource.OverrideValues["id"], cty.StringVal("run"); !got.RawEquals(want) {
+		t.Errorf("wrong override value %#v; want %#v", got, want)
+	}
+	for _, r := range child.Module.ManagedResources {
+		if !r.IsOverridden {
+			t.Errorf("%s in overridden module should be overridden", r.Addr())
+		}
+	}
+
+	reset()
+
+	if resource.IsOverridden || resource.OverrideValues != nil {
+		t.Errorf("resource should have been reset")
+	}
+	for _, r := range child.Module.ManagedResources {
+		if r.IsOverridden {
+			t.Errorf("%s should have been reset", r.Addr())
+		}
+	}
+}
@@ -0,0 +1,31 @@
+provider "aws" {
+  alias    = "by_region"
+  for_each = toset(["us-east-1", "us-west-2"])
+
+  region = each.key
+}
+
+provider "aws" {
+  alias  = "single"
+  region = "eu-west-1"
+}
+
+resource "aws_instance" "keyed" {
+  for_each = toset(["us-east-1"])
+  provider = aws.by_region[each.key]
+}
+
+resource "aws_instance" "missing_key" {
+  provider = aws.by_region
+}
+
+resource "aws_instance" "unexpected_key" {
+  provider = aws.single["eu-west-1"]
+}
+
+module "mod" {
+  source = "./mod"
+  providers = {
+    aws = aws.by_region["us-west-2"]
+  }
+}
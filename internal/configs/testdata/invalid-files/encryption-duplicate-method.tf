@@ -0,0 +1,6 @@
+terraform {
+  encryption {
+    method "unencrypted" "migrate" {}
+    method "unencrypted" "migrate" {}
+  }
+}
@@ -1,11 +1,6 @@
-
-locals {
-  foo = 1
-}
-
 variable "validation" {
   validation {
-    condition     = local.foo == var.validation # ERROR: Invalid reference in variable validation
+    condition     = aws_instance.foo.id == var.validation # ERROR: Invalid reference in variable validation
     error_message = "Must be five."
   }
 }
@@ -13,6 +8,6 @@ variable "validation" {
 variable "validation_error_expression" {
   validation {
     condition     = var.validation_error_expression != 1
-    error_message = "Cannot equal ${local.foo}." # ERROR: Invalid reference in variable validation
+    error_message = "Cannot equal ${module.foo.bar}." # ERROR: Invalid reference in variable validation
   }
 }
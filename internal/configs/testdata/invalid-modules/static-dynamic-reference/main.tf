@@ -0,0 +1,5 @@
+resource "null_resource" "example" {}
+
+module "foo" {
+  source = "./modules/${null_resource.example.id}"
+}
@@ -0,0 +1,8 @@
+variable "module_version" {
+  type = string
+}
+
+module "foo" {
+  source  = "hashicorp/foo/null"
+  version = var.module_version
+}
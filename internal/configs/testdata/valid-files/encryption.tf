@@ -0,0 +1,39 @@
+terraform {
+  encryption {
+    key_provider "pbkdf2" "current" {
+      passphrase = "correct-horse-battery-staple"
+    }
+    key_provider "pbkdf2" "previous" {
+      passphrase = "previous-horse-battery-staple"
+    }
+
+    method "aes_gcm" "current" {
+      keys = key_provider.pbkdf2.current
+    }
+    method "aes_gcm" "previous" {
+      keys = key_provider.pbkdf2.previous
+    }
+    method "unencrypted" "migrate" {}
+
+    state {
+      method   = method.aes_gcm.current
+      enforced = true
+
+      fallback {
+        method = method.aes_gcm.previous
+      }
+    }
+
+    plan {
+      method = method.aes_gcm.current
+
+      fallback {
+        method = method.unencrypted.migrate
+      }
+    }
+
+    remote_state {
+      method = method.aes_gcm.previous
+    }
+  }
+}
@@ -23,3 +23,8 @@ output "π" {
     pizza.cheese,
   ]
 }
+
+output "ephemeral" {
+  value     = var.ephemeral
+  ephemeral = true
+}
@@ -0,0 +1,11 @@
+removed {
+  from = test_instance.foo
+}
+
+removed {
+  from = module.bar
+
+  lifecycle {
+    destroy = false
+  }
+}
@@ -20,3 +20,35 @@ variable "validation_error_expression" {
     error_message = "Too long (${length(var.validation_error_expression)} is greater than 10)."
   }
 }
+
+variable "validation_min_size" {
+  type = number
+}
+
+variable "validation_max_size" {
+  type = number
+  validation {
+    condition     = var.validation_max_size >= var.validation_min_size
+    error_message = "Must be at least ${var.validation_min_size}."
+  }
+}
+
+locals {
+  validation_sizes = ["small", "large"]
+}
+
+variable "validation_local" {
+  type = string
+  validation {
+    condition     = contains(local.validation_sizes, var.validation_local)
+    error_message = "Must be one of ${join(", ", local.validation_sizes)}."
+  }
+}
+
+variable "validation_data" {
+  type = string
+  validation {
+    condition     = contains(data.test_sizes.all.names, var.validation_data)
+    error_message = "Must be a known size."
+  }
+}
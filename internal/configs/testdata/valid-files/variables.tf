@@ -42,3 +42,8 @@ variable "nullable_default_null" {
   nullable = true
   default = null
 }
+
+variable "ephemeral" {
+  type      = string
+  ephemeral = true
+}
@@ -0,0 +1,6 @@
+variable "module_version" { default = "1.0.0" }
+
+module "foo" {
+  source  = "hashicorp/foo/null"
+  version = var.module_version
+}
@@ -0,0 +1,11 @@
+variable "token" {
+  ephemeral = true
+}
+
+variable "name" {
+  ephemeral = false
+}
+
+output "token" {
+  ephemeral = true
+}
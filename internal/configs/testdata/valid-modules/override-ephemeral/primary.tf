@@ -0,0 +1,12 @@
+variable "token" {
+  type = string
+}
+
+variable "name" {
+  type      = string
+  ephemeral = true
+}
+
+output "token" {
+  value = var.token
+}
@@ -0,0 +1,24 @@
+variable "name" {
+  type    = string
+  default = "example"
+}
+
+locals {
+  source = "./modules/${var.name}"
+}
+
+terraform {
+  required_version = "~> ${local.major}"
+
+  backend "local" {
+    path = "${var.name}.tfstate"
+  }
+}
+
+locals {
+  major = "1.0"
+}
+
+module "foo" {
+  source = local.source
+}
@@ -4,8 +4,11 @@
 package configs
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // exprIsNativeQuotedString determines whether the given expression looks like
@@ -64,3 +67,22 @@ func schemaWithDynamic(schema *hcl.BodySchema) *hcl.BodySchema {
 
 	return ret
 }
+
+// decodeConstantStringAttr decodes the value of an attribute which must be
+// a constant string.
+func decodeConstantStringAttr(attr *hcl.Attribute) (string, hcl.Diagnostics) {
+	val, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() {
+		return "", diags
+	}
+	if val.IsNull() || !val.Type().Equals(cty.String) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Invalid %s", attr.Name),
+			Detail:   fmt.Sprintf("The %s argument must be a string.", attr.Name),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+		return "", diags
+	}
+	return val.AsString(), diags
+}
@@ -22,11 +22,22 @@ type VersionConstraint struct {
 }
 
 func decodeVersionConstraint(attr *hcl.Attribute) (VersionConstraint, hcl.Diagnostics) {
+	return decodeStaticVersionConstraint(attr, nil, StaticIdentifier{})
+}
+
+// decodeStaticVersionConstraint is like decodeVersionConstraint, except that
+// the constraint expression may refer to variables and locals that the given
+// StaticEvaluator can resolve. The constraint identifies itself as ident in
+// any diagnostics about static evaluation.
+func decodeStaticVersionConstraint(attr *hcl.Attribute, eval *StaticEvaluator, ident StaticIdentifier) (VersionConstraint, hcl.Diagnostics) {
 	ret := VersionConstraint{
 		DeclRange: attr.Range,
 	}
 
-	val, diags := attr.Expr.Value(nil)
+	val, diags := eval.Evaluate(attr.Expr, ident)
+	if !diags.HasErrors() {
+		diags = append(diags, checkStaticValueNotSensitive(val, ident)...)
+	}
 	if diags.HasErrors() {
 		return ret, diags
 	}
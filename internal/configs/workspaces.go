@@ -0,0 +1,200 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Workspaces represents a "workspaces" block inside a "terraform" block in
+// a root module, which customizes the settings of individual workspaces.
+//
+// The settings are needed before the values of the root module's input
+// variables are known, so all of the arguments must be literal values.
+type Workspaces struct {
+	// VarFileDir is the directory, relative to the root module, that contains
+	// the variable files named after workspaces. When a workspace is
+	// selected, the file named after it with the suffix ".tfvars" or
+	// ".tfvars.json" is loaded automatically if it exists.
+	VarFileDir string
+
+	// Workspaces are the settings of individual workspaces, by name.
+	Workspaces map[string]*Workspace
+
+	DeclRange hcl.Range
+}
+
+// Workspace represents a "workspace" block inside a "workspaces" block.
+type Workspace struct {
+	Name string
+
+	// Backend, if not nil, overrides the arguments of the root module's
+	// backend configuration while this workspace is selected.
+	Backend *WorkspaceBackend
+
+	DeclRange hcl.Range
+}
+
+// WorkspaceBackend represents a "backend" block inside a "workspace" block.
+//
+// Its body is decoded later using the schema of the backend that it
+// overrides.
+type WorkspaceBackend struct {
+	Config hcl.Body
+
+	DeclRange hcl.Range
+}
+
+// DefaultWorkspaceVarFileDir is the directory that contains the variable
+// files named after workspaces if a "workspaces" block doesn't set one.
+const DefaultWorkspaceVarFileDir = "."
+
+// LoadWorkspacesConfig reads the configuration files in the given directory
+// and returns the settings from its "workspaces" block, or nil if it doesn't
+// have one.
+//
+// Unlike LoadConfigDir, this only decodes the "workspaces" block, and does
+// not evaluate anything, so it can be used before the root module's input
+// variables are known.
+func (p *Parser) LoadWorkspacesConfig(path string) (*Workspaces, hcl.Diagnostics) {
+	primaryPaths, overridePaths, _, diags := p.dirFiles(path, "")
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	primary, fDiags := p.loadFiles(primaryPaths, false)
+	diags = append(diags, fDiags...)
+	override, fDiags := p.loadFiles(overridePaths, true)
+	diags = append(diags, fDiags...)
+
+	mod := &Module{}
+	for _, file := range primary {
+		diags = append(diags, mod.appendWorkspaces(file)...)
+	}
+	for _, file := range override {
+		diags = append(diags, mod.mergeWorkspaces(file)...)
+	}
+	return mod.Workspaces, diags
+}
+
+func (m *Module) appendWorkspaces(file *File) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, w := range file.Workspaces {
+		if m.Workspaces != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate workspaces configuration",
+				Detail:   fmt.Sprintf("A module may have only one workspaces block. Workspaces were previously configured at %s.", m.Workspaces.DeclRange),
+				Subject:  &w.DeclRange,
+			})
+			continue
+		}
+		m.Workspaces = w
+	}
+	return diags
+}
+
+func (m *Module) mergeWorkspaces(file *File) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	switch len(file.Workspaces) {
+	case 0:
+		// Nothing to override
+	case 1:
+		m.Workspaces = file.Workspaces[0]
+	default:
+		// An override file with multiple workspaces blocks is still invalid,
+		// even though it can override workspaces blocks from _other_ files.
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Duplicate workspaces configuration",
+			Detail:   fmt.Sprintf("Each override file may have only one workspaces block. Workspaces were previously configured at %s.", file.Workspaces[0].DeclRange),
+			Subject:  &file.Workspaces[1].DeclRange,
+		})
+	}
+	return diags
+}
+
+func decodeWorkspacesBlock(block *hcl.Block) (*Workspaces, hcl.Diagnostics) {
+	ret := &Workspaces{
+		VarFileDir: DefaultWorkspaceVarFileDir,
+		Workspaces: make(map[string]*Workspace),
+		DeclRange:  block.DefRange,
+	}
+
+	content, diags := block.Body.Content(workspacesBlockSchema)
+
+	if attr, exists := content.Attributes["var_file_dir"]; exists {
+		dir, valDiags := decodeConstantStringAttr(attr)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() {
+			ret.VarFileDir = dir
+		}
+	}
+
+	for _, block := range content.Blocks {
+		w, wDiags := decodeWorkspaceBlock(block)
+		diags = append(diags, wDiags...)
+		if w == nil {
+			continue
+		}
+		if existing, exists := ret.Workspaces[w.Name]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate workspace settings",
+				Detail:   fmt.Sprintf("The settings of workspace %q were already declared at %s.", w.Name, existing.DeclRange),
+				Subject:  &w.DeclRange,
+			})
+			continue
+		}
+		ret.Workspaces[w.Name] = w
+	}
+
+	return ret, diags
+}
+
+func decodeWorkspaceBlock(block *hcl.Block) (*Workspace, hcl.Diagnostics) {
+	ret := &Workspace{
+		Name:      block.Labels[0],
+		DeclRange: block.DefRange,
+	}
+
+	content, diags := block.Body.Content(workspaceBlockSchema)
+	for _, block := range content.Blocks {
+		if ret.Backend != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate backend settings",
+				Detail:   fmt.Sprintf("The backend settings of workspace %q were already declared at %s.", ret.Name, ret.Backend.DeclRange),
+				Subject:  &block.DefRange,
+			})
+			continue
+		}
+		ret.Backend = &WorkspaceBackend{
+			Config:    block.Body,
+			DeclRange: block.DefRange,
+		}
+	}
+
+	return ret, diags
+}
+
+var workspacesBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "var_file_dir"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "workspace",
+			LabelNames: []string{"name"},
+		},
+	},
+}
+
+var workspaceBlockSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "backend"},
+	},
+}
@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"testing"
+)
+
+func TestParserLoadWorkspacesConfig(t *testing.T) {
+	parser := testParser(map[string]string{
+		"mod/main.tf": `
+variable "unused" {
+  type = string
+}
+
+terraform {
+  backend "local" {
+    path = var.unused
+  }
+
+  workspaces {
+    var_file_dir = "envs"
+
+    workspace "prod" {
+      backend {
+        workspace_dir = "prod-states"
+      }
+    }
+    workspace "staging" {
+    }
+  }
+}
+`,
+		"mod/main_override.tf": `
+terraform {
+  workspaces {
+    workspace "dev" {
+    }
+  }
+}
+`,
+	})
+
+	got, diags := parser.LoadWorkspacesConfig("mod")
+	assertNoDiagnostics(t, diags)
+	if got == nil {
+		t.Fatal("no workspaces configuration")
+	}
+
+	// The override file replaces the whole block.
+	if got, want := got.VarFileDir, DefaultWorkspaceVarFileDir; got != want {
+		t.Errorf("wrong var file dir %q; want %q", got, want)
+	}
+	if _, exists := got.Workspaces["dev"]; !exists || len(got.Workspaces) != 1 {
+		t.Errorf("wrong workspaces %#v", got.Workspaces)
+	}
+}
+
+func TestParserLoadWorkspacesConfig_settings(t *testing.T) {
+	parser := testParser(map[string]string{
+		"mod/main.tf": `
+terraform {
+  workspaces {
+    var_file_dir = "envs"
+
+    workspace "prod" {
+      backend {
+        workspace_dir = "prod-states"
+      }
+    }
+    workspace "staging" {
+    }
+  }
+}
+`,
+	})
+
+	got, diags := parser.LoadWorkspacesConfig("mod")
+	assertNoDiagnostics(t, diags)
+
+	if got, want := got.VarFileDir, "envs"; got != want {
+		t.Errorf("wrong var file dir %q; want %q", got, want)
+	}
+	if got, want := len(got.Workspaces), 2; got != want {
+		t.Fatalf("wrong number of workspaces %d; want %d", got, want)
+	}
+	if got.Workspaces["staging"].Backend != nil {
+		t.Errorf("unexpected backend settings for staging")
+	}
+	backend := got.Workspaces["prod"].Backend
+	if backend == nil {
+		t.Fatalf("no backend settings for prod")
+	}
+	attrs, diags := backend.Config.JustAttributes()
+	assertNoDiagnostics(t, diags)
+	if _, exists := attrs["workspace_dir"]; !exists {
+		t.Errorf("backend settings don't include workspace_dir")
+	}
+}
+
+func TestParserLoadWorkspacesConfig_invalid(t *testing.T) {
+	parser := testParser(map[string]string{
+		"mod/a.tf": `
+variable "dir" {
+}
+
+terraform {
+  workspaces {
+    var_file_dir = var.dir
+
+    workspace "prod" {
+      backend {
+      }
+      backend {
+      }
+    }
+    workspace "prod" {
+    }
+  }
+}
+`,
+		"mod/b.tf": `
+terraform {
+  workspaces {
+  }
+}
+`,
+	})
+
+	_, diags := parser.LoadWorkspacesConfig("mod")
+	assertExactDiagnostics(t, diags, []string{
+		`mod/a.tf:7,20-23: Variables not allowed; Variables may not be used here.`,
+		`mod/a.tf:12,7-14: Duplicate backend settings; The backend settings of workspace "prod" were already declared at mod/a.tf:10,7-14.`,
+		`mod/a.tf:15,5-21: Duplicate workspace settings; The settings of workspace "prod" were already declared at mod/a.tf:9,5-21.`,
+		`mod/b.tf:3,3-13: Duplicate workspaces configuration; A module may have only one workspaces block. Workspaces were previously configured at mod/a.tf:6,3-13.`,
+	})
+}
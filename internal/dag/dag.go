@@ -4,6 +4,7 @@
 package dag
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -21,6 +22,10 @@ type AcyclicGraph struct {
 // WalkFunc is the callback used for walking the graph.
 type WalkFunc func(Vertex) tfdiags.Diagnostics
 
+// ContextWalkFunc is the callback used for walking the graph with
+// WalkContext. The given context carries the trace span for the vertex.
+type ContextWalkFunc func(context.Context, Vertex) tfdiags.Diagnostics
+
 // DepthWalkFunc is a walk function that also receives the current depth of the
 // walk as an argument
 type DepthWalkFunc func(Vertex, int) error
@@ -168,6 +173,15 @@ func (g *AcyclicGraph) Walk(cb WalkFunc) tfdiags.Diagnostics {
 	return w.Wait()
 }
 
+// WalkContext is like Walk, but records the trace span for each vertex as a
+// child of the span in the given context, and passes the vertex's span to
+// the callback so that any work it does can be recorded under it.
+func (g *AcyclicGraph) WalkContext(ctx context.Context, cb ContextWalkFunc) tfdiags.Diagnostics {
+	w := &Walker{ContextCallback: cb, Reverse: true, Context: ctx}
+	w.Update(g)
+	return w.Wait()
+}
+
 // simple convenience helper for converting a dag.Set to a []Vertex
 func AsVertexList(s Set) []Vertex {
 	vertexList := make([]Vertex, 0, len(s))
@@ -0,0 +1,25 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package dag
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer trace.Tracer
+
+func init() {
+	tracer = otel.Tracer("github.com/opentofu/opentofu/internal/dag")
+}
+
+// TracedVertex is an optional interface that can be implemented by Vertex
+// to add attributes to the trace span recorded when the vertex is walked.
+//
+// TraceAttributes is called after the walk callback for the vertex returns.
+type TracedVertex interface {
+	Vertex
+	TraceAttributes() []attribute.KeyValue
+}
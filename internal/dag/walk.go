@@ -4,11 +4,14 @@
 package dag
 
 import (
+	"context"
 	"errors"
 	"log"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/codes"
+
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -40,6 +43,14 @@ type Walker struct {
 	// Callback is what is called for each vertex
 	Callback WalkFunc
 
+	// ContextCallback, if set, is called for each vertex instead of
+	// Callback, with a context carrying the trace span for the vertex.
+	ContextCallback ContextWalkFunc
+
+	// Context, if set, is the parent of the trace span recorded around the
+	// callback for each vertex.
+	Context context.Context
+
 	// Reverse, if true, causes the source of an edge to depend on a target.
 	// When false (default), the target depends on the source.
 	Reverse bool
@@ -381,7 +392,7 @@ func (w *Walker) walkVertex(v Vertex, info *walkerVertex) {
 	var diags tfdiags.Diagnostics
 	var upstreamFailed bool
 	if depsSuccess {
-		diags = w.Callback(v)
+		diags = w.walkCallback(v)
 	} else {
 		log.Printf("[TRACE] dag/walk: upstream of %q errored, so skipping", VertexName(v))
 		// This won't be displayed to the user because we'll set upstreamFailed,
@@ -407,6 +418,34 @@ func (w *Walker) walkVertex(v Vertex, info *walkerVertex) {
 	w.diagsLock.Unlock()
 }
 
+// walkCallback calls the walk callback for the given vertex within a trace
+// span named after the vertex.
+func (w *Walker) walkCallback(v Vertex) tfdiags.Diagnostics {
+	ctx := w.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, span := tracer.Start(ctx, VertexName(v))
+	defer span.End()
+
+	var diags tfdiags.Diagnostics
+	if w.ContextCallback != nil {
+		diags = w.ContextCallback(ctx, v)
+	} else {
+		diags = w.Callback(v)
+	}
+
+	// The attributes are collected after the callback so that the vertex
+	// can describe what it actually did, such as the action it took.
+	if tv, ok := v.(TracedVertex); ok {
+		span.SetAttributes(tv.TraceAttributes()...)
+	}
+	if diags.HasErrors() {
+		span.SetStatus(codes.Error, diags.Err().Error())
+	}
+	return diags
+}
+
 func (w *Walker) waitDeps(
 	v Vertex,
 	deps map[Vertex]<-chan struct{},
@@ -4,12 +4,20 @@
 package dag
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"sync"
 	"testing"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -299,3 +307,72 @@ func walkCbRecord(order *[]interface{}) WalkFunc {
 		return nil
 	}
 }
+
+type tracedTestVertex string
+
+func (v tracedTestVertex) TraceAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.String("test.vertex", string(v))}
+}
+
+func TestWalker_traceSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+	})
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+
+	var g AcyclicGraph
+	g.Add(tracedTestVertex("a"))
+	g.Add("b")
+	g.Connect(BasicEdge("b", tracedTestVertex("a")))
+
+	callbackSpans := make(map[Vertex]trace.SpanContext)
+	var callbackSpansLock sync.Mutex
+	diags := g.WalkContext(ctx, func(ctx context.Context, v Vertex) tfdiags.Diagnostics {
+		callbackSpansLock.Lock()
+		callbackSpans[v] = trace.SpanContextFromContext(ctx)
+		callbackSpansLock.Unlock()
+		if v == "b" {
+			return tfdiags.Diagnostics{}.Append(fmt.Errorf("failed"))
+		}
+		return nil
+	})
+	parent.End()
+	if !diags.HasErrors() {
+		t.Fatal("expected the error from vertex b")
+	}
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+
+	for _, name := range []string{"a", "b"} {
+		span, ok := spans[name]
+		if !ok {
+			t.Fatalf("no span recorded for vertex %q", name)
+		}
+		if got, want := span.Parent().SpanID(), parent.SpanContext().SpanID(); got != want {
+			t.Errorf("span %q has wrong parent %s; want %s", name, got, want)
+		}
+	}
+
+	// The callback receives the vertex's own span, so that work done for
+	// the vertex is recorded under it.
+	if got, want := callbackSpans["b"].SpanID(), spans["b"].SpanContext().SpanID(); got != want {
+		t.Errorf("callback for vertex b got span %s; want %s", got, want)
+	}
+
+	if got, want := spans["a"].Attributes(), []attribute.KeyValue{attribute.String("test.vertex", "a")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong attributes for vertex a\ngot:  %#v\nwant: %#v", got, want)
+	}
+	if got := spans["a"].Status().Code; got != codes.Unset {
+		t.Errorf("wrong status for vertex a: %s", got)
+	}
+	if got := spans["b"].Status().Code; got != codes.Error {
+		t.Errorf("wrong status for vertex b: %s", got)
+	}
+}
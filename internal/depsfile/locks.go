@@ -35,11 +35,13 @@ type Locks struct {
 	// settings, environment variables, or whatever similar sources.
 	overriddenProviders map[addrs.Provider]struct{}
 
-	// TODO: In future we'll also have module locks, but the design of that
-	// still needs some more work and we're deferring that to get the
-	// provider locking capability out sooner, because it's more common to
-	// directly depend on providers maintained outside your organization than
-	// modules maintained outside your organization.
+	// modules are the locks for module packages whose content can be
+	// identified by a digest, keyed by their package address.
+	//
+	// TODO: In future we might also lock the selected versions of modules
+	// from module registries, but the design of that still needs some more
+	// work.
+	modules map[string]*ModuleLock
 
 	// sources is a copy of the map of source buffers produced by the HCL
 	// parser during loading, which we retain only so that the caller can
@@ -52,6 +54,7 @@ type Locks struct {
 func NewLocks() *Locks {
 	return &Locks{
 		providers: make(map[addrs.Provider]*ProviderLock),
+		modules:   make(map[string]*ModuleLock),
 
 		// no "sources" here, because that's only for locks objects loaded
 		// from files.
@@ -118,6 +121,41 @@ func (l *Locks) RemoveProvider(addr addrs.Provider) {
 	delete(l.providers, addr)
 }
 
+// Module returns the stored lock for the module package with the given
+// address, or nil if that package currently has no lock.
+//
+// packageAddr must be formatted as if it were the result of an
+// addrs.ModulePackage.String() call.
+func (l *Locks) Module(packageAddr string) *ModuleLock {
+	return l.modules[packageAddr]
+}
+
+// AllModules returns a map describing all of the module package locks in the
+// receiver, keyed by package address.
+func (l *Locks) AllModules() map[string]*ModuleLock {
+	ret := make(map[string]*ModuleLock, len(l.modules))
+	for k, v := range l.modules {
+		ret[k] = v
+	}
+	return ret
+}
+
+// SetModule creates a new lock or replaces the existing lock for the module
+// package with the given address, recording the given digest of its
+// content.
+//
+// SetModule returns the newly-created module lock object, which invalidates
+// any ModuleLock object previously returned from Module or SetModule for the
+// given package address.
+func (l *Locks) SetModule(packageAddr string, digest string) *ModuleLock {
+	new := &ModuleLock{
+		packageAddr: packageAddr,
+		digest:      digest,
+	}
+	l.modules[packageAddr] = new
+	return new
+}
+
 // SetProviderOverridden records that this particular OpenTofu process will
 // not pay attention to the recorded lock entry for the given provider, and
 // will instead access that provider's functionality in some other special
@@ -276,6 +314,21 @@ func (l *Locks) Equal(other *Locks) bool {
 	// We don't need to worry about providers that are in "other" but not
 	// in the receiver, because we tested the lengths being equal above.
 
+	return l.EqualModules(other)
+}
+
+// EqualModules returns true if the given Locks have the same module package
+// locks as the receiver, regardless of their provider locks.
+func (l *Locks) EqualModules(other *Locks) bool {
+	if len(l.modules) != len(other.modules) {
+		return false
+	}
+	for addr, thisLock := range l.modules {
+		otherLock, ok := other.modules[addr]
+		if !ok || thisLock.digest != otherLock.digest {
+			return false
+		}
+	}
 	return true
 }
 
@@ -301,7 +354,7 @@ func (l *Locks) EqualProviderAddress(other *Locks) bool {
 // UI code might wish to use this to distinguish a lock file being
 // written for the first time from subsequent updates to that lock file.
 func (l *Locks) Empty() bool {
-	return len(l.providers) == 0
+	return len(l.providers) == 0 && len(l.modules) == 0
 }
 
 // DeepCopy creates a new Locks that represents the same information as the
@@ -322,6 +375,9 @@ func (l *Locks) DeepCopy() *Locks {
 		}
 		ret.SetProvider(addr, lock.version, lock.versionConstraints, hashes)
 	}
+	for addr, lock := range l.modules {
+		ret.SetModule(addr, lock.digest)
+	}
 	return ret
 }
 
@@ -440,3 +496,28 @@ func (l *ProviderLock) ContainsAll(target *ProviderLock) bool {
 func (l *ProviderLock) PreferredHashes() []getproviders.Hash {
 	return getproviders.PreferredHashes(l.hashes)
 }
+
+// ModuleLock represents lock information for a module package whose content
+// can be identified by a digest, which is currently only the case for
+// packages in OCI registries.
+type ModuleLock struct {
+	// packageAddr is the address of the module package this lock applies
+	// to, as written in the configuration.
+	packageAddr string
+
+	// digest identifies the content that was installed for the package,
+	// such as "sha256:" followed by the hex-encoded SHA256 hash of the
+	// manifest of an OCI artifact.
+	digest string
+}
+
+// PackageAddr returns the address of the module package this lock applies to.
+func (l *ModuleLock) PackageAddr() string {
+	return l.packageAddr
+}
+
+// Digest returns the digest of the content that was previously installed for
+// the corresponding module package.
+func (l *ModuleLock) Digest() string {
+	return l.digest
+}
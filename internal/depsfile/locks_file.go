@@ -6,6 +6,7 @@ package depsfile
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/oci"
 	"github.com/opentofu/opentofu/internal/replacefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/version"
@@ -161,6 +163,19 @@ func SaveLocksToBytes(locks *Locks) ([]byte, tfdiags.Diagnostics) {
 		}
 	}
 
+	modules := make([]string, 0, len(locks.modules))
+	for addr := range locks.modules {
+		modules = append(modules, addr)
+	}
+	sort.Strings(modules)
+
+	for _, addr := range modules {
+		lock := locks.modules[addr]
+		rootBody.AppendNewline()
+		block := rootBody.AppendNewBlock("module", []string{lock.packageAddr})
+		block.Body().SetAttributeValue("digest", cty.StringVal(lock.digest))
+	}
+
 	return f.Bytes(), diags
 }
 
@@ -173,21 +188,16 @@ func decodeLocksFromHCL(locks *Locks, body hcl.Body) tfdiags.Diagnostics {
 				Type:       "provider",
 				LabelNames: []string{"source_addr"},
 			},
-
-			// "module" is just a placeholder for future enhancement, so we
-			// can mostly-ignore the this block type we intend to add in
-			// future, but warn in case someone tries to use one e.g. if they
-			// downgraded to an earlier version of OpenTofu.
 			{
 				Type:       "module",
-				LabelNames: []string{"path"},
+				LabelNames: []string{"package_addr"},
 			},
 		},
 	})
 	diags = diags.Append(hclDiags)
 
 	seenProviders := make(map[addrs.Provider]hcl.Range)
-	seenModule := false
+	seenModules := make(map[string]hcl.Range)
 	for _, block := range content.Blocks {
 
 		switch block.Type {
@@ -210,19 +220,22 @@ func decodeLocksFromHCL(locks *Locks, body hcl.Body) tfdiags.Diagnostics {
 			seenProviders[lock.addr] = block.DefRange
 
 		case "module":
-			// We'll just take the first module block to use for a single warning,
-			// because that's sufficient to get the point across without swamping
-			// the output with warning noise.
-			if !seenModule {
-				currentVersion := version.SemVer.String()
+			lock, moreDiags := decodeModuleLockFromHCL(block)
+			diags = diags.Append(moreDiags)
+			if lock == nil {
+				continue
+			}
+			if previousRng, exists := seenModules[lock.packageAddr]; exists {
 				diags = diags.Append(&hcl.Diagnostic{
-					Severity: hcl.DiagWarning,
-					Summary:  "Dependency locks for modules are not yet supported",
-					Detail:   fmt.Sprintf("OpenTofu v%s only supports dependency locks for providers, not for modules. This configuration may be intended for a later version of OpenTofu that also supports dependency locks for modules.", currentVersion),
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate module lock",
+					Detail:   fmt.Sprintf("This lockfile already declared a lock for module package %s at %s.", lock.packageAddr, previousRng.String()),
 					Subject:  block.TypeRange.Ptr(),
 				})
-				seenModule = true
+				continue
 			}
+			locks.modules[lock.packageAddr] = lock
+			seenModules[lock.packageAddr] = block.DefRange
 
 		default:
 			// Shouldn't get here because this should be exhaustive for
@@ -318,6 +331,57 @@ func decodeProviderLockFromHCL(block *hcl.Block) (*ProviderLock, tfdiags.Diagnos
 	return ret, diags
 }
 
+func decodeModuleLockFromHCL(block *hcl.Block) (*ModuleLock, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	// Only packages from OCI registries can currently be locked, because
+	// they are the only ones whose content we can identify by a digest.
+	// Other lock blocks might be from a later version of OpenTofu that can
+	// lock other kinds of modules, so we'll warn about and ignore those.
+	addr := block.Labels[0]
+	if !strings.HasPrefix(addr, "oci://") {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Unsupported module lock",
+			Detail:   fmt.Sprintf("OpenTofu v%s only supports dependency locks for module packages from OCI registries, so this lock will be ignored. This lock file may be intended for a later version of OpenTofu.", version.SemVer.String()),
+			Subject:  block.LabelRanges[0].Ptr(),
+		})
+		return nil, diags
+	}
+
+	content, hclDiags := block.Body.Content(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "digest", Required: true},
+		},
+	})
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return nil, diags
+	}
+
+	expr := content.Attributes["digest"].Expr
+	var digest string
+	hclDiags = gohcl.DecodeExpression(expr, nil, &digest)
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return nil, diags
+	}
+	if !oci.ValidDigest(digest) {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid module package digest",
+			Detail:   fmt.Sprintf("The recorded digest for module package %s must be \"sha256:\" followed by 64 lowercase hexadecimal digits.", addr),
+			Subject:  expr.Range().Ptr(),
+		})
+		return nil, diags
+	}
+
+	return &ModuleLock{
+		packageAddr: addr,
+		digest:      digest,
+	}, diags
+}
+
 func decodeProviderVersionArgument(provider addrs.Provider, attr *hcl.Attribute) (getproviders.Version, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	if attr == nil {
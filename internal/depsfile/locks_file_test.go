@@ -105,6 +105,16 @@ func TestLoadLocksFromFile(t *testing.T) {
 					t.Errorf("wrong number of providers %d; want %d", got, want)
 				}
 
+			case "module-locks.hcl":
+				if got, want := len(locks.modules), 1; got != want {
+					t.Errorf("wrong number of modules %d; want %d", got, want)
+				}
+				if lock := locks.Module("oci://example.com/modules/network:v1"); lock == nil {
+					t.Errorf("missing lock for module package")
+				} else if got, want := lock.Digest(), "sha256:"+strings.Repeat("a", 64); got != want {
+					t.Errorf("wrong digest\ngot:  %s\nwant: %s", got, want)
+				}
+
 			case "valid-provider-locks.hcl":
 				if got, want := len(locks.providers), 3; got != want {
 					t.Errorf("wrong number of providers %d; want %d", got, want)
@@ -221,6 +231,8 @@ func TestSaveLocksToFile(t *testing.T) {
 	locks.SetProvider(barProvider, oneDotTwo, pessimisticOneDotOh, nil)
 	locks.SetProvider(bazProvider, oneDotTwo, nil, nil)
 	locks.SetProvider(booProvider, oneDotTwo, abbreviatedOneDotTwo, nil)
+	locks.SetModule("oci://example.com/modules/network:v1", "sha256:"+strings.Repeat("b", 64))
+	locks.SetModule("oci://example.com/modules/compute:v2", "sha256:"+strings.Repeat("a", 64))
 
 	dir := t.TempDir()
 
@@ -269,6 +281,14 @@ provider "registry.opentofu.org/test/foo" {
     "test:cccccccccccccccccccccccccccccccccccccccccccccccc",
   ]
 }
+
+module "oci://example.com/modules/compute:v2" {
+  digest = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+}
+
+module "oci://example.com/modules/network:v1" {
+  digest = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+}
 `
 	if diff := cmp.Diff(wantContent, gotContent); diff != "" {
 		t.Errorf("wrong result\n%s", diff)
@@ -4,6 +4,7 @@
 package depsfile
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -83,6 +84,27 @@ func TestLocksEqual(t *testing.T) {
 		b.SetProvider(boopProvider, v2, v2EqConstraints, hashesB)
 		nonEqualBothWays(t, a, b)
 	})
+	t.Run("an extra module lock", func(t *testing.T) {
+		a := NewLocks()
+		b := NewLocks()
+		b.SetModule("oci://example.com/modules/boop:v1", "sha256:"+strings.Repeat("a", 64))
+		nonEqualBothWays(t, a, b)
+	})
+	t.Run("both have boop module with different digests", func(t *testing.T) {
+		a := NewLocks()
+		b := NewLocks()
+		a.SetModule("oci://example.com/modules/boop:v1", "sha256:"+strings.Repeat("a", 64))
+		b.SetModule("oci://example.com/modules/boop:v1", "sha256:"+strings.Repeat("b", 64))
+		nonEqualBothWays(t, a, b)
+	})
+	t.Run("both have boop module with same digest", func(t *testing.T) {
+		a := NewLocks()
+		b := NewLocks()
+		a.SetModule("oci://example.com/modules/boop:v1", "sha256:"+strings.Repeat("a", 64))
+		b.SetModule("oci://example.com/modules/boop:v1", "sha256:"+strings.Repeat("a", 64))
+		equalBothWays(t, a, b)
+		equalBothWays(t, a, b.DeepCopy())
+	})
 }
 
 func TestLocksEqualProviderAddress(t *testing.T) {
@@ -0,0 +1,15 @@
+module "oci://example.com/modules/network:v1" {
+  digest = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+}
+
+module "oci://example.com/modules/invalid:v1" {
+  digest = "sha256:abc" # ERROR: Invalid module package digest
+}
+
+module "oci://example.com/modules/network:v1" { # ERROR: Duplicate module lock
+  digest = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+}
+
+module "git::https://example.com/network.git" { # WARNING: Unsupported module lock
+  digest = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+}
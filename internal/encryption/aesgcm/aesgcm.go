@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package aesgcm contains the AES-GCM primitives shared by the encryption
+// method and the key providers that wrap data keys locally.
+package aesgcm
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the size in bytes of the keys expected by Seal and Open, which
+// selects AES-256.
+const KeySize = 32
+
+// Seal encrypts and authenticates the given plaintext using the given key,
+// returning a randomly-chosen nonce followed by the ciphertext.
+//
+// additionalData, which may be nil, is authenticated but not encrypted, and
+// the same value must be passed to Open.
+func Seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// Open reverses the effect of Seal, returning an error if the given key is
+// not the one the data was sealed with or if the data or the additional data
+// has been tampered with.
+func Open(key, sealed, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("encrypted data is too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		// The underlying error is deliberately vague, so we'll add some
+		// context about the likely causes.
+		return nil, fmt.Errorf("decryption failed; the key is incorrect or the data is corrupt: %w", err)
+	}
+	return plaintext, nil
+}
+
+// NewKey returns a new randomly-generated key of size KeySize.
+func NewKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid key length %d; must be %d bytes", len(key), KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
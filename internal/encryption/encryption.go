@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package encryption implements client-side encryption of state snapshots
+// and saved plan files, as configured by the "encryption" block inside the
+// root module's "terraform" block.
+//
+// Encryption happens at the level of serialized artifacts: callers encrypt
+// the bytes produced by packages statefile and planfile just before they are
+// stored, and decrypt them just after they are retrieved. That means every
+// state storage implementation, including all of the remote state backends,
+// gets encryption without any backend-specific code.
+package encryption
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// Encryption gives access to the encryption settings for each kind of
+// artifact that can be encrypted.
+type Encryption interface {
+	State() StateEncryption
+	Plan() PlanEncryption
+
+	// RemoteState returns the settings used to decrypt the state snapshots
+	// of other configurations, read by terraform_remote_state data sources.
+	RemoteState() StateEncryption
+}
+
+type encryption struct {
+	state       *target
+	plan        *target
+	remoteState *target
+}
+
+// New builds the encryption settings described by the given configuration.
+//
+// If cfg is nil then the result is equivalent to Disabled.
+func New(cfg *configs.Encryption) (Encryption, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	if cfg == nil {
+		return Disabled(), diags
+	}
+
+	keyProviders := make(map[string]keyprovider.KeyProvider, len(cfg.KeyProviders))
+	for _, kpCfg := range cfg.KeyProviders {
+		kp, moreDiags := newKeyProvider(kpCfg)
+		diags = append(diags, moreDiags...)
+		if kp != nil {
+			keyProviders[kpCfg.Addr()] = kp
+		}
+	}
+
+	methods := make(map[string]method, len(cfg.Methods))
+	for _, mCfg := range cfg.Methods {
+		m, moreDiags := newMethod(mCfg, keyProviders)
+		diags = append(diags, moreDiags...)
+		if m != nil {
+			methods[mCfg.Addr()] = m
+		}
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	ret := &encryption{}
+	var moreDiags hcl.Diagnostics
+	ret.state, moreDiags = newTarget("state", cfg.State, methods)
+	diags = append(diags, moreDiags...)
+	ret.plan, moreDiags = newTarget("plan", cfg.Plan, methods)
+	diags = append(diags, moreDiags...)
+	ret.remoteState, moreDiags = newTarget("remote state", cfg.RemoteState, methods)
+	diags = append(diags, moreDiags...)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return ret, diags
+}
+
+// Disabled returns an Encryption that doesn't encrypt anything, and which
+// rejects any encrypted data it is asked to decrypt.
+func Disabled() Encryption {
+	return &encryption{
+		state:       disabledTarget("state"),
+		plan:        disabledTarget("plan"),
+		remoteState: disabledTarget("remote state"),
+	}
+}
+
+func (e *encryption) State() StateEncryption {
+	return &stateEncryption{target: e.state}
+}
+
+func (e *encryption) Plan() PlanEncryption {
+	return &planEncryption{target: e.plan}
+}
+
+func (e *encryption) RemoteState() StateEncryption {
+	return &stateEncryption{target: e.remoteState}
+}
+
+func newTarget(name string, cfg *configs.EncryptionTarget, methods map[string]method) (*target, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	if cfg == nil {
+		return disabledTarget(name), diags
+	}
+
+	ret := &target{
+		name:     name,
+		method:   methods[cfg.Method.Addr()],
+		enforced: cfg.Enforced,
+	}
+	if ret.enforced && isUnencrypted(ret.method) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unencrypted method is forbidden",
+			Detail:   fmt.Sprintf("The %s encryption is enforced, so it cannot use the unencrypted method %s.", name, cfg.Method.Addr()),
+			Subject:  cfg.DeclRange.Ptr(),
+		})
+	}
+	if cfg.Fallback != nil {
+		fallback, moreDiags := newTarget(name, cfg.Fallback, methods)
+		diags = append(diags, moreDiags...)
+		ret.fallback = fallback
+	}
+	return ret, diags
+}
@@ -0,0 +1,448 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/spf13/afero"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+)
+
+const testPassphrase = "correct-horse-battery-staple"
+
+func testEncryption(t *testing.T, src string) Encryption {
+	t.Helper()
+
+	enc, diags := testEncryptionDiags(t, src)
+	if diags != "" {
+		t.Fatalf("unexpected errors: %s", diags)
+	}
+	return enc
+}
+
+func testEncryptionDiags(t *testing.T, src string) (Encryption, string) {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "main.tf", []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	parser := configs.NewParser(fs)
+	file, diags := parser.LoadConfigFile("main.tf")
+	if diags.HasErrors() {
+		return nil, diags.Error()
+	}
+	var cfg *configs.Encryption
+	if len(file.Encryptions) > 0 {
+		cfg = file.Encryptions[0]
+	}
+	enc, diags := New(cfg)
+	if diags.HasErrors() {
+		return nil, diags.Error()
+	}
+	return enc, ""
+}
+
+func TestEncryption_roundTrip(t *testing.T) {
+	enc := testEncryption(t, `
+terraform {
+  encryption {
+    key_provider "pbkdf2" "main" {
+      passphrase = "`+testPassphrase+`"
+      iterations = 200000
+    }
+    method "aes_gcm" "main" {
+      keys = key_provider.pbkdf2.main
+    }
+    state {
+      method = method.aes_gcm.main
+    }
+    plan {
+      method = method.aes_gcm.main
+    }
+  }
+}
+`)
+
+	plaintext := []byte(`{"version": 4}`)
+
+	encrypted, err := enc.State().EncryptState(plaintext)
+	if err != nil {
+		t.Fatalf("unexpected error encrypting state: %s", err)
+	}
+	if bytes.Contains(encrypted, plaintext) {
+		t.Fatalf("encrypted state contains the plaintext:\n%s", encrypted)
+	}
+	got, err := enc.State().DecryptState(encrypted)
+	if err != nil {
+		t.Fatalf("unexpected error decrypting state: %s", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("wrong result\ngot:  %s\nwant: %s", got, plaintext)
+	}
+
+	encrypted, err = enc.Plan().EncryptPlan(plaintext)
+	if err != nil {
+		t.Fatalf("unexpected error encrypting plan: %s", err)
+	}
+	got, err = enc.Plan().DecryptPlan(encrypted)
+	if err != nil {
+		t.Fatalf("unexpected error decrypting plan: %s", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("wrong result\ngot:  %s\nwant: %s", got, plaintext)
+	}
+
+	// Encrypted data must not be readable without the encryption settings.
+	_, err = Disabled().State().DecryptState(encrypted)
+	if err == nil || !strings.Contains(err.Error(), "encrypted") {
+		t.Fatalf("expected an error about encrypted data, got: %v", err)
+	}
+}
+
+func TestEncryption_staticReferences(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	src := `
+variable "passphrase" {
+  type      = string
+  sensitive = true
+}
+
+locals {
+  iterations = 100000 * 2
+}
+
+terraform {
+  encryption {
+    key_provider "pbkdf2" "main" {
+      passphrase = var.passphrase
+      iterations = local.iterations
+    }
+    method "aes_gcm" "main" {
+      keys = key_provider.pbkdf2.main
+    }
+    state {
+      method = method.aes_gcm.main
+    }
+  }
+}
+`
+	if err := afero.WriteFile(fs, "root/main.tf", []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	call := configs.NewStaticModuleCall(addrs.RootModule, func(v *configs.Variable) (cty.Value, hcl.Diagnostics) {
+		return cty.StringVal(testPassphrase), nil
+	})
+	mod, diags := configs.NewParser(fs).LoadConfigDir("root", call)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	enc, diags := New(mod.Encryption)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	plaintext := []byte(`{"version": 4}`)
+	encrypted, err := enc.State().EncryptState(plaintext)
+	if err != nil {
+		t.Fatalf("unexpected error encrypting state: %s", err)
+	}
+
+	// The same settings written literally must be able to decrypt the result.
+	literal := testEncryption(t, `
+terraform {
+  encryption {
+    key_provider "pbkdf2" "main" {
+      passphrase = "`+testPassphrase+`"
+      iterations = 200000
+    }
+    method "aes_gcm" "main" {
+      keys = key_provider.pbkdf2.main
+    }
+    state {
+      method = method.aes_gcm.main
+    }
+  }
+}
+`)
+	got, err := literal.State().DecryptState(encrypted)
+	if err != nil {
+		t.Fatalf("unexpected error decrypting state: %s", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("wrong result\ngot:  %s\nwant: %s", got, plaintext)
+	}
+}
+
+func TestEncryption_unencryptedMigration(t *testing.T) {
+	plaintext := []byte(`{"version": 4}`)
+
+	enc := testEncryption(t, `
+terraform {
+  encryption {
+    key_provider "pbkdf2" "main" {
+      passphrase = "`+testPassphrase+`"
+      iterations = 200000
+    }
+    method "aes_gcm" "main" {
+      keys = key_provider.pbkdf2.main
+    }
+    state {
+      method = method.aes_gcm.main
+    }
+  }
+}
+`)
+	if _, err := enc.State().DecryptState(plaintext); err == nil {
+		t.Fatal("unexpected success reading unencrypted state without an unencrypted fallback")
+	}
+
+	enc = testEncryption(t, `
+terraform {
+  encryption {
+    key_provider "pbkdf2" "main" {
+      passphrase = "`+testPassphrase+`"
+      iterations = 200000
+    }
+    method "aes_gcm" "main" {
+      keys = key_provider.pbkdf2.main
+    }
+    method "unencrypted" "migrate" {}
+    state {
+      method = method.aes_gcm.main
+      fallback {
+        method = method.unencrypted.migrate
+      }
+    }
+  }
+}
+`)
+	got, err := enc.State().DecryptState(plaintext)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("wrong result\ngot:  %s\nwant: %s", got, plaintext)
+	}
+
+	// Plans have no encryption configured, so they stay unencrypted.
+	got, err = enc.Plan().EncryptPlan(plaintext)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("plan was encrypted, but should not have been\n%s", got)
+	}
+}
+
+func TestEncryption_keyRotation(t *testing.T) {
+	plaintext := []byte(`{"version": 4}`)
+
+	old := testEncryption(t, `
+terraform {
+  encryption {
+    key_provider "pbkdf2" "old" {
+      passphrase = "`+testPassphrase+`"
+      iterations = 200000
+    }
+    method "aes_gcm" "old" {
+      keys = key_provider.pbkdf2.old
+    }
+    state {
+      method = method.aes_gcm.old
+    }
+  }
+}
+`)
+	encrypted, err := old.State().EncryptState(plaintext)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	rotated := testEncryption(t, `
+terraform {
+  encryption {
+    key_provider "pbkdf2" "old" {
+      passphrase = "`+testPassphrase+`"
+      iterations = 200000
+    }
+    key_provider "pbkdf2" "new" {
+      passphrase = "a-completely-different-passphrase"
+      iterations = 200000
+    }
+    method "aes_gcm" "old" {
+      keys = key_provider.pbkdf2.old
+    }
+    method "aes_gcm" "new" {
+      keys = key_provider.pbkdf2.new
+    }
+    state {
+      method = method.aes_gcm.new
+      fallback {
+        method = method.aes_gcm.old
+      }
+    }
+  }
+}
+`)
+	got, err := rotated.State().DecryptState(encrypted)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("wrong result\ngot:  %s\nwant: %s", got, plaintext)
+	}
+
+	// New snapshots use the new key, so the old settings can't read them.
+	reencrypted, err := rotated.State().EncryptState(got)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := old.State().DecryptState(reencrypted); err == nil {
+		t.Fatal("unexpected success decrypting with the old key")
+	}
+}
+
+func TestEncryption_tamperedHeader(t *testing.T) {
+	enc := testEncryption(t, `
+terraform {
+  encryption {
+    key_provider "pbkdf2" "main" {
+      passphrase = "`+testPassphrase+`"
+      iterations = 200000
+    }
+    method "aes_gcm" "main" {
+      keys = key_provider.pbkdf2.main
+    }
+    state {
+      method = method.aes_gcm.main
+    }
+  }
+}
+`)
+
+	encrypted, err := enc.State().EncryptState([]byte(`{"version": 4}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The header is authenticated along with the data, so even a change
+	// that doesn't affect unwrapping the data key must be detected.
+	env := parseEnvelope(encrypted)
+	if env == nil {
+		t.Fatalf("encrypted state is not an envelope:\n%s", encrypted)
+	}
+	env.Encryption.WrappedKey.Meta["extra"] = "tampered"
+	tampered, err := json.Marshal(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := enc.State().DecryptState(tampered); err == nil {
+		t.Fatal("unexpected success decrypting state with a tampered header")
+	}
+}
+
+func TestEncryption_invalid(t *testing.T) {
+	tests := map[string]struct {
+		src  string
+		want string
+	}{
+		"enforced unencrypted": {
+			`
+terraform {
+  encryption {
+    method "unencrypted" "none" {}
+    state {
+      method   = method.unencrypted.none
+      enforced = true
+    }
+  }
+}
+`,
+			"Unencrypted method is forbidden",
+		},
+		"short passphrase": {
+			`
+terraform {
+  encryption {
+    key_provider "pbkdf2" "main" {
+      passphrase = "short"
+    }
+  }
+}
+`,
+			"at least 16 characters",
+		},
+		"undeclared key provider": {
+			`
+terraform {
+  encryption {
+    method "aes_gcm" "main" {
+      keys = key_provider.pbkdf2.missing
+    }
+  }
+}
+`,
+			"Reference to undeclared key provider",
+		},
+		"undeclared method": {
+			`
+terraform {
+  encryption {
+    state {
+      method = method.aes_gcm.missing
+    }
+  }
+}
+`,
+			"undeclared encryption method",
+		},
+		"unsupported key provider": {
+			`
+terraform {
+  encryption {
+    key_provider "magic" "main" {}
+  }
+}
+`,
+			"Unsupported key provider",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, diags := testEncryptionDiags(t, test.src)
+			if !strings.Contains(diags, test.want) {
+				t.Fatalf("wrong errors\ngot:  %s\nwant: %s", diags, test.want)
+			}
+		})
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	plaintext := []byte(`{"version": 4}`)
+
+	got, err := Disabled().State().EncryptState(plaintext)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("wrong result\ngot:  %s\nwant: %s", got, plaintext)
+	}
+	got, err = Disabled().State().DecryptState(plaintext)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("wrong result\ngot:  %s\nwant: %s", got, plaintext)
+	}
+}
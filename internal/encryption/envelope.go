@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// envelopeVersion is the current version of the encrypted file format.
+const envelopeVersion = 1
+
+// envelope is the serialization of an encrypted artifact. It's JSON so that
+// encrypted state remains valid text for backends that expect that, but
+// none of the original content is visible in it.
+type envelope struct {
+	Encryption    *envelopeHeader `json:"encryption"`
+	EncryptedData []byte          `json:"encrypted_data"`
+}
+
+type envelopeHeader struct {
+	Version     int                     `json:"version"`
+	Method      string                  `json:"method"`
+	KeyProvider string                  `json:"key_provider"`
+	WrappedKey  *keyprovider.WrappedKey `json:"wrapped_key"`
+}
+
+// parseEnvelope returns the envelope represented by the given data, or nil
+// if the data doesn't seem to be encrypted.
+func parseEnvelope(data []byte) *envelope {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte("{")) {
+		return nil
+	}
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil
+	}
+	if env.Encryption == nil || env.EncryptedData == nil {
+		return nil
+	}
+	return &env
+}
+
+// additionalData returns the serialized header, which is authenticated
+// alongside the encrypted data so that the method, key provider and wrapped
+// key metadata can't be altered without detection.
+//
+// The encoding is deterministic, so re-serializing a parsed header produces
+// the same bytes that were used when encrypting.
+func (h *envelopeHeader) additionalData() ([]byte, error) {
+	return json.Marshal(h)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+type pbkdf2Config struct {
+	Passphrase   string  `hcl:"passphrase"`
+	Iterations   *int    `hcl:"iterations,optional"`
+	SaltLength   *int    `hcl:"salt_length,optional"`
+	HashFunction *string `hcl:"hash_function,optional"`
+}
+
+type gcpKMSConfig struct {
+	KMSEncryptionKey string  `hcl:"kms_encryption_key"`
+	Credentials      *string `hcl:"credentials,optional"`
+}
+
+func newKeyProvider(cfg *configs.EncryptionKeyProvider) (keyprovider.KeyProvider, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	switch cfg.Type {
+	case "pbkdf2":
+		var raw pbkdf2Config
+		diags = append(diags, cfg.DecodeConfig(&raw)...)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		kp := keyprovider.NewPBKDF2(raw.Passphrase)
+		if raw.Iterations != nil {
+			kp.Iterations = *raw.Iterations
+		}
+		if raw.SaltLength != nil {
+			kp.SaltLength = *raw.SaltLength
+		}
+		if raw.HashFunction != nil {
+			kp.HashFunction = *raw.HashFunction
+		}
+		if err := kp.Validate(); err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid pbkdf2 key provider configuration",
+				Detail:   fmt.Sprintf("Invalid configuration for %s: %s.", cfg.Addr(), err),
+				Subject:  cfg.DeclRange.Ptr(),
+			})
+			return nil, diags
+		}
+		return kp, diags
+
+	case "gcp_kms":
+		var raw gcpKMSConfig
+		diags = append(diags, cfg.DecodeConfig(&raw)...)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		credentials := ""
+		if raw.Credentials != nil {
+			credentials = *raw.Credentials
+		}
+		client, err := keyprovider.NewGCPKMSClient(context.TODO(), credentials)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to configure gcp_kms key provider",
+				Detail:   fmt.Sprintf("Could not configure %s: %s.", cfg.Addr(), err),
+				Subject:  cfg.DeclRange.Ptr(),
+			})
+			return nil, diags
+		}
+		return keyprovider.NewKMS(client, raw.KMSEncryptionKey), diags
+
+	default:
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported key provider",
+			Detail:   fmt.Sprintf("There is no key provider of type %q. The supported key providers are \"pbkdf2\" and \"gcp_kms\".", cfg.Type),
+			Subject:  cfg.DeclRange.Ptr(),
+		})
+		return nil, diags
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package keyprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	"google.golang.org/api/option"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+
+	"github.com/opentofu/opentofu/internal/httpclient"
+	"github.com/opentofu/opentofu/version"
+)
+
+// GCPKMSClient is a KMSClient backed by Google Cloud Key Management Service.
+// Key IDs are full resource names of crypto keys, like
+// projects/P/locations/L/keyRings/R/cryptoKeys/K.
+type GCPKMSClient struct {
+	client *kms.KeyManagementClient
+}
+
+var _ KMSClient = (*GCPKMSClient)(nil)
+
+// NewGCPKMSClient creates a client for Google Cloud KMS. If credentials is
+// empty then Application Default Credentials are used, and otherwise it must
+// be either the contents of a service account key file or a path to one.
+func NewGCPKMSClient(ctx context.Context, credentials string) (*GCPKMSClient, error) {
+	opts := []option.ClientOption{
+		option.WithUserAgent(httpclient.OpenTofuUserAgent(version.Version)),
+	}
+	if credentials != "" {
+		contents := credentials
+		if !json.Valid([]byte(credentials)) {
+			raw, err := os.ReadFile(credentials)
+			if err != nil {
+				return nil, fmt.Errorf("the credentials value is neither valid JSON nor a readable file path: %w", err)
+			}
+			contents = string(raw)
+		}
+		opts = append(opts, option.WithCredentialsJSON([]byte(contents)))
+	}
+
+	client, err := kms.NewKeyManagementClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Google Cloud KMS client: %w", err)
+	}
+	return &GCPKMSClient{client: client}, nil
+}
+
+func (c *GCPKMSClient) Encrypt(ctx context.Context, keyID string, plaintext []byte) ([]byte, error) {
+	resp, err := c.client.Encrypt(ctx, &kmspb.EncryptRequest{
+		Name:      keyID,
+		Plaintext: plaintext,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Ciphertext, nil
+}
+
+func (c *GCPKMSClient) Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error) {
+	resp, err := c.client.Decrypt(ctx, &kmspb.DecryptRequest{
+		Name:       keyID,
+		Ciphertext: ciphertext,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
@@ -0,0 +1,37 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package keyprovider contains the key providers that can be used to protect
+// the data keys of encrypted state and plan files.
+//
+// Encryption uses an envelope scheme: every encrypted artifact has its own
+// randomly-generated data key, and the key provider is only responsible for
+// wrapping (encrypting) that data key so that it can be stored alongside
+// the encrypted data, and for unwrapping it again when the data is read.
+package keyprovider
+
+import (
+	"context"
+)
+
+// KeyProvider is the interface implemented by each of the supported key
+// providers.
+type KeyProvider interface {
+	// WrapKey encrypts the given data key, returning a WrappedKey that
+	// includes everything (apart from the key provider's own configuration)
+	// needed to recover the data key later.
+	WrapKey(ctx context.Context, dataKey []byte) (*WrappedKey, error)
+
+	// UnwrapKey recovers a data key previously wrapped with WrapKey.
+	UnwrapKey(ctx context.Context, wrapped *WrappedKey) ([]byte, error)
+}
+
+// WrappedKey is an encrypted data key along with any key-provider-specific
+// metadata needed to decrypt it.
+//
+// Both fields are stored in the clear alongside the encrypted data, so they
+// must not contain any secret information.
+type WrappedKey struct {
+	Ciphertext []byte            `json:"ciphertext"`
+	Meta       map[string]string `json:"meta,omitempty"`
+}
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package keyprovider
+
+import (
+	"context"
+	"fmt"
+)
+
+// KMSClient is the small subset of a cloud key management service's API
+// that the KMS key provider needs. The key material never leaves the
+// service: data keys are sent to it for encryption and decryption.
+type KMSClient interface {
+	Encrypt(ctx context.Context, keyID string, plaintext []byte) ([]byte, error)
+	Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error)
+}
+
+// KMS is a key provider that wraps data keys using a remote key management
+// service.
+type KMS struct {
+	Client KMSClient
+	KeyID  string
+}
+
+var _ KeyProvider = (*KMS)(nil)
+
+// NewKMS returns a key provider that wraps data keys using the given key in
+// the given key management service.
+func NewKMS(client KMSClient, keyID string) *KMS {
+	return &KMS{
+		Client: client,
+		KeyID:  keyID,
+	}
+}
+
+func (k *KMS) WrapKey(ctx context.Context, dataKey []byte) (*WrappedKey, error) {
+	ciphertext, err := k.Client.Encrypt(ctx, k.KeyID, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt the data key using %s: %w", k.KeyID, err)
+	}
+	return &WrappedKey{
+		Ciphertext: ciphertext,
+		Meta: map[string]string{
+			"key_id": k.KeyID,
+		},
+	}, nil
+}
+
+func (k *KMS) UnwrapKey(ctx context.Context, wrapped *WrappedKey) ([]byte, error) {
+	// We use the key recorded at encryption time if there is one, so that
+	// data written before the configured key changed remains readable as long
+	// as the old key is still available in the service.
+	keyID := wrapped.Meta["key_id"]
+	if keyID == "" {
+		keyID = k.KeyID
+	}
+	dataKey, err := k.Client.Decrypt(ctx, keyID, wrapped.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the data key using %s: %w", keyID, err)
+	}
+	return dataKey, nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package keyprovider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+)
+
+// fakeKMSClient "encrypts" by prefixing the key ID, which is enough to
+// check that the right key is used in each direction.
+type fakeKMSClient struct {
+	keys map[string]bool
+}
+
+func (c *fakeKMSClient) Encrypt(_ context.Context, keyID string, plaintext []byte) ([]byte, error) {
+	if !c.keys[keyID] {
+		return nil, fmt.Errorf("no key %q", keyID)
+	}
+	return append([]byte(keyID+":"), plaintext...), nil
+}
+
+func (c *fakeKMSClient) Decrypt(_ context.Context, keyID string, ciphertext []byte) ([]byte, error) {
+	if !c.keys[keyID] {
+		return nil, fmt.Errorf("no key %q", keyID)
+	}
+	prefix := []byte(keyID + ":")
+	if !bytes.HasPrefix(ciphertext, prefix) {
+		return nil, fmt.Errorf("ciphertext was not encrypted with %q", keyID)
+	}
+	return ciphertext[len(prefix):], nil
+}
+
+func TestKMS_roundTrip(t *testing.T) {
+	client := &fakeKMSClient{keys: map[string]bool{"old": true, "new": true}}
+	dataKey := []byte("data-key")
+
+	wrapped, err := NewKMS(client, "old").WrapKey(context.Background(), dataKey)
+	if err != nil {
+		t.Fatalf("unexpected error wrapping key: %s", err)
+	}
+	if got, want := wrapped.Meta["key_id"], "old"; got != want {
+		t.Fatalf("wrong key_id %q; want %q", got, want)
+	}
+
+	// The key recorded in the wrapped key is used for unwrapping, even if
+	// the configured key has since changed.
+	got, err := NewKMS(client, "new").UnwrapKey(context.Background(), wrapped)
+	if err != nil {
+		t.Fatalf("unexpected error unwrapping key: %s", err)
+	}
+	if !bytes.Equal(got, dataKey) {
+		t.Fatalf("wrong data key\ngot:  %s\nwant: %s", got, dataKey)
+	}
+
+	delete(client.keys, "old")
+	if _, err := NewKMS(client, "new").UnwrapKey(context.Background(), wrapped); err == nil {
+		t.Fatal("unexpected success unwrapping with a deleted key")
+	}
+}
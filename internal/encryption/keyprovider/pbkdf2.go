@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package keyprovider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"strconv"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/opentofu/opentofu/internal/encryption/aesgcm"
+)
+
+const (
+	// PBKDF2MinPassphraseLength is the shortest passphrase accepted by the
+	// pbkdf2 key provider.
+	PBKDF2MinPassphraseLength = 16
+
+	// PBKDF2MinIterations is the lowest iteration count accepted in
+	// configuration. The default is higher, following current OWASP
+	// recommendations for PBKDF2-HMAC-SHA512.
+	PBKDF2MinIterations     = 200000
+	PBKDF2DefaultIterations = 600000
+
+	// PBKDF2MaxIterations is the highest iteration count accepted, both in
+	// configuration and in wrapped keys, so that a tampered file can't make
+	// key derivation take an unreasonable amount of time.
+	PBKDF2MaxIterations = 10000000
+
+	PBKDF2DefaultSaltLength = 32
+	PBKDF2DefaultHash       = "sha512"
+)
+
+var pbkdf2HashFunctions = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// PBKDF2 is a key provider that derives a key-encryption key from a
+// passphrase using PBKDF2, and uses it to wrap data keys with AES-GCM.
+//
+// A new random salt is chosen for each wrapped key, and the salt, iteration
+// count and hash function are recorded in the wrapped key's metadata so that
+// changing those settings later doesn't prevent decrypting existing data.
+type PBKDF2 struct {
+	Passphrase   string
+	Iterations   int
+	SaltLength   int
+	HashFunction string
+}
+
+var _ KeyProvider = (*PBKDF2)(nil)
+
+// NewPBKDF2 returns a PBKDF2 key provider for the given passphrase, using the
+// default settings for everything else.
+func NewPBKDF2(passphrase string) *PBKDF2 {
+	return &PBKDF2{
+		Passphrase:   passphrase,
+		Iterations:   PBKDF2DefaultIterations,
+		SaltLength:   PBKDF2DefaultSaltLength,
+		HashFunction: PBKDF2DefaultHash,
+	}
+}
+
+// Validate returns an error if the receiver's settings are not acceptable.
+func (p *PBKDF2) Validate() error {
+	if len(p.Passphrase) < PBKDF2MinPassphraseLength {
+		return fmt.Errorf("the passphrase must be at least %d characters long", PBKDF2MinPassphraseLength)
+	}
+	if p.Iterations < PBKDF2MinIterations {
+		return fmt.Errorf("the iteration count must be at least %d", PBKDF2MinIterations)
+	}
+	if p.Iterations > PBKDF2MaxIterations {
+		return fmt.Errorf("the iteration count must be at most %d", PBKDF2MaxIterations)
+	}
+	if p.SaltLength < 16 {
+		return fmt.Errorf("the salt length must be at least 16 bytes")
+	}
+	if _, ok := pbkdf2HashFunctions[p.HashFunction]; !ok {
+		return fmt.Errorf("unsupported hash function %q; must be either \"sha256\" or \"sha512\"", p.HashFunction)
+	}
+	return nil
+}
+
+func (p *PBKDF2) WrapKey(_ context.Context, dataKey []byte) (*WrappedKey, error) {
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	kek, err := p.deriveKey(salt, p.Iterations, p.HashFunction)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := aesgcm.Seal(kek, dataKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &WrappedKey{
+		Ciphertext: ciphertext,
+		Meta: map[string]string{
+			"salt":          base64.StdEncoding.EncodeToString(salt),
+			"iterations":    strconv.Itoa(p.Iterations),
+			"hash_function": p.HashFunction,
+		},
+	}, nil
+}
+
+func (p *PBKDF2) UnwrapKey(_ context.Context, wrapped *WrappedKey) ([]byte, error) {
+	salt, err := base64.StdEncoding.DecodeString(wrapped.Meta["salt"])
+	if err != nil || len(salt) == 0 {
+		return nil, fmt.Errorf("the wrapped key has an invalid salt")
+	}
+	// The settings recorded in the wrapped key are not trusted until the
+	// data key has been unwrapped, so they must be within the same limits
+	// as the configuration before we spend any time deriving a key.
+	iterations, err := strconv.Atoi(wrapped.Meta["iterations"])
+	if err != nil || iterations < PBKDF2MinIterations || iterations > PBKDF2MaxIterations {
+		return nil, fmt.Errorf("the wrapped key has an invalid iteration count; must be between %d and %d", PBKDF2MinIterations, PBKDF2MaxIterations)
+	}
+	hashFunction := wrapped.Meta["hash_function"]
+	if _, ok := pbkdf2HashFunctions[hashFunction]; !ok {
+		return nil, fmt.Errorf("the wrapped key has an unsupported hash function %q", hashFunction)
+	}
+	kek, err := p.deriveKey(salt, iterations, hashFunction)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := aesgcm.Open(kek, wrapped.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap the data key, which usually means the passphrase is incorrect: %w", err)
+	}
+	return dataKey, nil
+}
+
+func (p *PBKDF2) deriveKey(salt []byte, iterations int, hashFunction string) ([]byte, error) {
+	h, ok := pbkdf2HashFunctions[hashFunction]
+	if !ok {
+		return nil, fmt.Errorf("unsupported hash function %q", hashFunction)
+	}
+	return pbkdf2.Key([]byte(p.Passphrase), salt, iterations, aesgcm.KeySize, h), nil
+}
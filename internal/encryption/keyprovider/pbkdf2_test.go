@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package keyprovider
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPBKDF2_roundTrip(t *testing.T) {
+	kp := NewPBKDF2("correct-horse-battery-staple")
+	kp.Iterations = PBKDF2MinIterations
+	kp.HashFunction = "sha256"
+	if err := kp.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+
+	dataKey := bytes.Repeat([]byte{0x42}, 32)
+	wrapped, err := kp.WrapKey(context.Background(), dataKey)
+	if err != nil {
+		t.Fatalf("unexpected error wrapping key: %s", err)
+	}
+	if bytes.Contains(wrapped.Ciphertext, dataKey) {
+		t.Fatal("wrapped key contains the data key")
+	}
+
+	// Changing the settings must not prevent unwrapping existing keys,
+	// because the settings used are recorded in the wrapped key.
+	kp.Iterations = PBKDF2DefaultIterations
+	kp.HashFunction = "sha512"
+	got, err := kp.UnwrapKey(context.Background(), wrapped)
+	if err != nil {
+		t.Fatalf("unexpected error unwrapping key: %s", err)
+	}
+	if !bytes.Equal(got, dataKey) {
+		t.Fatalf("wrong data key\ngot:  %x\nwant: %x", got, dataKey)
+	}
+
+	wrong := NewPBKDF2("an-incorrect-passphrase")
+	if _, err := wrong.UnwrapKey(context.Background(), wrapped); err == nil {
+		t.Fatal("unexpected success unwrapping with the wrong passphrase")
+	}
+}
+
+func TestPBKDF2_Validate(t *testing.T) {
+	tests := map[string]func(*PBKDF2){
+		"short passphrase": func(kp *PBKDF2) { kp.Passphrase = "short" },
+		"few iterations":   func(kp *PBKDF2) { kp.Iterations = 1000 },
+		"many iterations":  func(kp *PBKDF2) { kp.Iterations = PBKDF2MaxIterations + 1 },
+		"short salt":       func(kp *PBKDF2) { kp.SaltLength = 8 },
+		"unsupported hash": func(kp *PBKDF2) { kp.HashFunction = "md5" },
+	}
+
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			kp := NewPBKDF2("correct-horse-battery-staple")
+			modify(kp)
+			if err := kp.Validate(); err == nil {
+				t.Fatal("unexpected success")
+			}
+		})
+	}
+}
+
+func TestPBKDF2_UnwrapKeyInvalidMeta(t *testing.T) {
+	kp := NewPBKDF2("correct-horse-battery-staple")
+	kp.Iterations = PBKDF2MinIterations
+	wrapped, err := kp.WrapKey(context.Background(), bytes.Repeat([]byte{0x42}, 32))
+	if err != nil {
+		t.Fatalf("unexpected error wrapping key: %s", err)
+	}
+
+	tests := map[string]struct {
+		key, value string
+		want       string
+	}{
+		"few iterations":   {"iterations", "1", "invalid iteration count"},
+		"many iterations":  {"iterations", strconv.Itoa(PBKDF2MaxIterations + 1), "invalid iteration count"},
+		"unsupported hash": {"hash_function", "md5", "unsupported hash function"},
+		"missing hash":     {"hash_function", "", "unsupported hash function"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			meta := make(map[string]string, len(wrapped.Meta))
+			for k, v := range wrapped.Meta {
+				meta[k] = v
+			}
+			meta[test.key] = test.value
+			tampered := &WrappedKey{Ciphertext: wrapped.Ciphertext, Meta: meta}
+
+			_, err := kp.UnwrapKey(context.Background(), tampered)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Fatalf("wrong error\ngot:  %v\nwant: %s", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/aesgcm"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// method is implemented by each of the supported encryption methods.
+type method interface {
+	// addr returns the address of the method block this method was
+	// configured by, for use in error messages.
+	addr() string
+
+	encrypt(data []byte) (*envelope, error)
+	decrypt(env *envelope) ([]byte, error)
+}
+
+func newMethod(cfg *configs.EncryptionMethod, keyProviders map[string]keyprovider.KeyProvider) (method, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	switch cfg.Type {
+	case "aes_gcm":
+		content, moreDiags := cfg.Config.Content(aesGCMMethodSchema)
+		diags = append(diags, moreDiags...)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		attr := content.Attributes["keys"]
+		traversal, moreDiags := hcl.AbsTraversalForExpr(attr.Expr)
+		diags = append(diags, moreDiags...)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		kpAddr, ok := configs.ParseEncryptionReference(traversal, "key_provider")
+		if !ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid key provider reference",
+				Detail:   "The keys argument must be a reference to a key_provider block, like key_provider.pbkdf2.example.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+			return nil, diags
+		}
+		kp, ok := keyProviders[kpAddr]
+		if !ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Reference to undeclared key provider",
+				Detail:   fmt.Sprintf("There is no key_provider block declared as %s in this encryption block.", kpAddr),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+			return nil, diags
+		}
+		return &aesGCMMethod{
+			methodAddr:      cfg.Addr(),
+			keyProviderAddr: kpAddr,
+			keyProvider:     kp,
+		}, diags
+
+	case "unencrypted":
+		_, moreDiags := cfg.Config.Content(&hcl.BodySchema{})
+		diags = append(diags, moreDiags...)
+		return unencryptedMethod{methodAddr: cfg.Addr()}, diags
+
+	default:
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported encryption method",
+			Detail:   fmt.Sprintf("There is no encryption method of type %q. The supported methods are \"aes_gcm\" and \"unencrypted\".", cfg.Type),
+			Subject:  cfg.DeclRange.Ptr(),
+		})
+		return nil, diags
+	}
+}
+
+var aesGCMMethodSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "keys", Required: true},
+	},
+}
+
+// aesGCMMethod encrypts data with AES-256-GCM, using a new random data key
+// for each encryption. The data key is wrapped by the configured key
+// provider and stored alongside the encrypted data.
+type aesGCMMethod struct {
+	methodAddr      string
+	keyProviderAddr string
+	keyProvider     keyprovider.KeyProvider
+}
+
+const aesGCMMethodType = "aes_gcm"
+
+func (m *aesGCMMethod) addr() string {
+	return m.methodAddr
+}
+
+func (m *aesGCMMethod) encrypt(data []byte) (*envelope, error) {
+	dataKey, err := aesgcm.NewKey()
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := m.keyProvider.WrapKey(context.TODO(), dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", m.keyProviderAddr, err)
+	}
+	header := &envelopeHeader{
+		Version:     envelopeVersion,
+		Method:      aesGCMMethodType,
+		KeyProvider: m.keyProviderAddr,
+		WrappedKey:  wrapped,
+	}
+	ad, err := header.additionalData()
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := aesgcm.Seal(dataKey, data, ad)
+	if err != nil {
+		return nil, err
+	}
+	return &envelope{
+		Encryption:    header,
+		EncryptedData: ciphertext,
+	}, nil
+}
+
+func (m *aesGCMMethod) decrypt(env *envelope) ([]byte, error) {
+	header := env.Encryption
+	if header.Version != envelopeVersion {
+		return nil, fmt.Errorf("unsupported encryption format version %d; this data may have been written by a newer version of OpenTofu", header.Version)
+	}
+	if header.Method != aesGCMMethodType {
+		return nil, fmt.Errorf("the data was encrypted using the %q method", header.Method)
+	}
+	if header.KeyProvider != m.keyProviderAddr {
+		return nil, fmt.Errorf("the data key was wrapped by %s, but this method uses %s", header.KeyProvider, m.keyProviderAddr)
+	}
+	if header.WrappedKey == nil {
+		return nil, fmt.Errorf("the encrypted data has no data key")
+	}
+	dataKey, err := m.keyProvider.UnwrapKey(context.TODO(), header.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", m.keyProviderAddr, err)
+	}
+	ad, err := header.additionalData()
+	if err != nil {
+		return nil, err
+	}
+	return aesgcm.Open(dataKey, env.EncryptedData, ad)
+}
+
+// unencryptedMethod is a placeholder method that stores data as plaintext.
+// It's used to explicitly opt in to reading unencrypted data when migrating
+// to encryption, or to writing unencrypted data when migrating away from it.
+type unencryptedMethod struct {
+	methodAddr string
+}
+
+func (m unencryptedMethod) addr() string {
+	return m.methodAddr
+}
+
+func (m unencryptedMethod) encrypt(data []byte) (*envelope, error) {
+	// Should never be called, because targets check isUnencrypted first.
+	panic("encrypt called on the unencrypted method")
+}
+
+func (m unencryptedMethod) decrypt(env *envelope) ([]byte, error) {
+	// Should never be called, because targets check isUnencrypted first.
+	panic("decrypt called on the unencrypted method")
+}
+
+func isUnencrypted(m method) bool {
+	_, ok := m.(unencryptedMethod)
+	return ok
+}
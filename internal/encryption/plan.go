@@ -0,0 +1,33 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+// PlanEncryption encrypts and decrypts saved plan files.
+type PlanEncryption interface {
+	// EncryptPlan encrypts the given plan file content, or returns it
+	// unchanged if plan encryption is not configured.
+	EncryptPlan(data []byte) ([]byte, error)
+
+	// DecryptPlan reverses the effect of EncryptPlan, trying each of the
+	// configured fallbacks in turn if necessary.
+	DecryptPlan(data []byte) ([]byte, error)
+}
+
+// PlanEncryptionDisabled returns a PlanEncryption that leaves plan files
+// unencrypted, and which refuses to read encrypted plan files.
+func PlanEncryptionDisabled() PlanEncryption {
+	return Disabled().Plan()
+}
+
+type planEncryption struct {
+	target *target
+}
+
+func (p *planEncryption) EncryptPlan(data []byte) ([]byte, error) {
+	return p.target.encrypt(data)
+}
+
+func (p *planEncryption) DecryptPlan(data []byte) ([]byte, error) {
+	return p.target.decrypt(data)
+}
@@ -0,0 +1,33 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+// StateEncryption encrypts and decrypts serialized state snapshots.
+type StateEncryption interface {
+	// EncryptState encrypts the given serialized state snapshot, or returns
+	// it unchanged if state encryption is not configured.
+	EncryptState(data []byte) ([]byte, error)
+
+	// DecryptState reverses the effect of EncryptState, trying each of the
+	// configured fallbacks in turn if necessary.
+	DecryptState(data []byte) ([]byte, error)
+}
+
+// StateEncryptionDisabled returns a StateEncryption that leaves state
+// snapshots unencrypted, and which refuses to read encrypted snapshots.
+func StateEncryptionDisabled() StateEncryption {
+	return Disabled().State()
+}
+
+type stateEncryption struct {
+	target *target
+}
+
+func (s *stateEncryption) EncryptState(data []byte) ([]byte, error) {
+	return s.target.encrypt(data)
+}
+
+func (s *stateEncryption) DecryptState(data []byte) ([]byte, error) {
+	return s.target.decrypt(data)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// target is the encryption configuration for one kind of artifact, along
+// with the chain of fallbacks used when decrypting.
+type target struct {
+	// name is the kind of artifact, like "state" or "plan", used in error
+	// messages.
+	name string
+
+	method   method
+	enforced bool
+	fallback *target
+}
+
+func disabledTarget(name string) *target {
+	return &target{
+		name:   name,
+		method: unencryptedMethod{},
+	}
+}
+
+func (t *target) encrypt(data []byte) ([]byte, error) {
+	if isUnencrypted(t.method) {
+		return data, nil
+	}
+	env, err := t.method.encrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt %s using %s: %w", t.name, t.method.addr(), err)
+	}
+	return json.Marshal(env)
+}
+
+func (t *target) decrypt(data []byte) ([]byte, error) {
+	env := parseEnvelope(data)
+
+	var errs []error
+	encrypted := false
+	for current := t; current != nil; current = current.fallback {
+		if isUnencrypted(current.method) {
+			if env == nil {
+				return data, nil
+			}
+			continue
+		}
+		encrypted = true
+		if env == nil {
+			continue
+		}
+		plaintext, err := current.method.decrypt(env)
+		if err == nil {
+			return plaintext, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", current.method.addr(), err))
+	}
+
+	switch {
+	case !encrypted:
+		return nil, fmt.Errorf("the %s is encrypted, but there is no encryption configuration for it; add an encryption block with a %q block that can decrypt it", t.name, t.name)
+	case env == nil:
+		return nil, fmt.Errorf("the %s is not encrypted, but encryption is configured for it; to migrate existing unencrypted data, add a fallback block that uses an \"unencrypted\" method", t.name)
+	default:
+		return nil, fmt.Errorf("failed to decrypt the %s using any of the configured methods: %w", t.name, errors.Join(errs...))
+	}
+}
@@ -78,6 +78,7 @@ var goGetterGetters = map[string]getter.Getter{
 	"gcs":   new(getter.GCSGetter),
 	"git":   new(getter.GitGetter),
 	"hg":    new(getter.HgGetter),
+	"oci":   new(ociGetter),
 	"s3":    new(getter.S3Getter),
 	"http":  getterHTTPGetter,
 	"https": getterHTTPGetter,
@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package getmodules
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+
+	getter "github.com/hashicorp/go-getter"
+
+	"github.com/opentofu/opentofu/internal/oci"
+)
+
+// Media types of the layer containing a module package in an OCI artifact.
+// We also accept the standard media type for an image layer archive, so that
+// module packages can be pushed with general-purpose tools.
+const (
+	ociModuleLayerTarGzip = "application/vnd.opentofu.modulepkg.v1.tar+gzip"
+	ociModuleLayerZip     = "application/vnd.opentofu.modulepkg.v1.zip"
+	ociImageLayerTarGzip  = "application/vnd.oci.image.layer.v1.tar+gzip"
+)
+
+// ociGetter is a go-getter getter for module packages stored as artifacts in
+// registries implementing the OCI distribution specification, with addresses
+// like oci://example.com/repository:tag or
+// oci://example.com/repository@sha256:digest.
+//
+// The artifact must have an image manifest with a single layer, which is
+// an archive of the module package. Credentials for the registry are taken
+// from the same Docker-style configuration file that "docker login" writes,
+// whose location can be overridden by the DOCKER_CONFIG environment
+// variable.
+type ociGetter struct {
+	client *getter.Client
+}
+
+var _ getter.Getter = (*ociGetter)(nil)
+
+func (g *ociGetter) ClientMode(*url.URL) (getter.ClientMode, error) {
+	return getter.ClientModeDir, nil
+}
+
+func (g *ociGetter) SetClient(c *getter.Client) {
+	g.client = c
+}
+
+func (g *ociGetter) GetFile(string, *url.URL) error {
+	return fmt.Errorf("OCI artifacts can only be installed as module packages")
+}
+
+func (g *ociGetter) Get(dst string, u *url.URL) error {
+	ctx := context.Background()
+	if g.client != nil && g.client.Ctx != nil {
+		ctx = g.client.Ctx
+	}
+
+	ref, err := parseOCIPackageAddr(u)
+	if err != nil {
+		return err
+	}
+	client, err := newOCIClient()
+	if err != nil {
+		return err
+	}
+
+	manifest, _, err := client.GetManifest(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if manifest.IsIndex() {
+		return fmt.Errorf("%s is an image index, but a module package must be an image manifest", ref)
+	}
+	if len(manifest.Layers) != 1 {
+		return fmt.Errorf("%s has %d layers, but a module package must have exactly one", ref, len(manifest.Layers))
+	}
+	layer := manifest.Layers[0]
+	var decompressor getter.Decompressor
+	switch layer.MediaType {
+	case ociModuleLayerTarGzip, ociImageLayerTarGzip:
+		decompressor = goGetterDecompressors["tar.gz"]
+	case ociModuleLayerZip:
+		decompressor = goGetterDecompressors["zip"]
+	default:
+		return fmt.Errorf("%s has a layer of unsupported media type %q", ref, layer.MediaType)
+	}
+
+	f, err := os.CreateTemp("", "tofu-oci-module")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	log.Printf("[TRACE] getmodules: downloading layer %s of %s to %s", layer.Digest, ref, f.Name())
+	err = client.FetchBlob(ctx, ref, layer, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", ref, err)
+	}
+
+	var umask os.FileMode
+	if g.client != nil {
+		umask = g.client.Umask
+	}
+	return decompressor.Decompress(dst, f.Name(), true, umask)
+}
+
+// parseOCIPackageAddr returns the reference to the artifact identified by
+// the given oci: package address, which must have either a tag or a digest.
+func parseOCIPackageAddr(u *url.URL) (oci.Reference, error) {
+	if u.RawQuery != "" || u.User != nil {
+		return oci.Reference{}, fmt.Errorf("OCI module package address %s must not have query string arguments or credentials", u.Redacted())
+	}
+	ref, err := oci.ParseReference(u.Host + u.Path)
+	if err != nil {
+		return ref, fmt.Errorf("invalid OCI module package address: %w", err)
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		return ref, fmt.Errorf("OCI module package address %s must include a tag or a digest", u.Redacted())
+	}
+	return ref, nil
+}
+
+func newOCIClient() (*oci.Client, error) {
+	creds, err := oci.DefaultCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return oci.NewClient(creds), nil
+}
@@ -0,0 +1,211 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package getmodules
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/oci"
+)
+
+// gitCommitPattern matches a full git commit id, using either SHA-1 or
+// SHA-256 object names.
+var gitCommitPattern = regexp.MustCompile(`^(?:[0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// ResolvePackageRevision returns an address for the exact revision of the
+// package at the given address, which is suitable for use as a key for
+// caching the package's content across installations.
+//
+// For git packages, this is the package address with the requested ref, or
+// the remote's default branch if there is none, resolved to a commit. This
+// requires querying the remote repository, but without fetching its content.
+//
+// If the package at the given address can't be identified by an exact
+// revision then the result is an empty string and no error. This includes
+// all packages other than git repositories, whose content at a particular
+// address might change over time.
+//
+// For OCI artifacts, this is the package address with the tag, if any,
+// resolved to the digest of the artifact's manifest.
+//
+// packageAddr must be formatted as if it were the result of an
+// addrs.ModulePackage.String() call.
+func ResolvePackageRevision(ctx context.Context, packageAddr string) (string, error) {
+	if IsOCIPackage(packageAddr) {
+		return resolveOCIRevision(ctx, packageAddr)
+	}
+	rawURL, ok := strings.CutPrefix(packageAddr, "git::")
+	if !ok {
+		return "", nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid git repository address: %w", err)
+	}
+	query := u.Query()
+	if query.Has("sshkey") {
+		// The key is part of the address, so we must not use it as
+		// a cache key that might be persisted somewhere.
+		return "", nil
+	}
+	ref := query.Get("ref")
+	query.Del("ref")
+	query.Del("depth") // only affects the history, which we don't use
+	u.RawQuery = ""
+
+	commit := ref
+	if !gitCommitPattern.MatchString(ref) {
+		commit, err = resolveGitRef(ctx, u.String(), ref)
+		if err != nil {
+			return "", err
+		}
+		if commit == "" {
+			// The ref might be an abbreviated commit id, which we can't
+			// resolve without fetching the repository.
+			return "", nil
+		}
+	}
+
+	// Any credentials in the address are needed to query the remote above,
+	// but must not be part of a cache key that might be persisted somewhere.
+	u.User = nil
+	query.Set("ref", commit)
+	u.RawQuery = query.Encode()
+	return "git::" + u.String(), nil
+}
+
+// resolveGitRef uses "git ls-remote" to find the commit that the given ref
+// refers to in the given remote repository, or that its default branch
+// refers to if ref is empty.
+//
+// The result is an empty string if the remote has no ref with the given
+// name.
+func resolveGitRef(ctx context.Context, remote, ref string) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git must be available and on the PATH")
+	}
+	// The commit that an annotated tag refers to is reported with a "^{}"
+	// suffix, which we must ask for explicitly.
+	patterns := []string{ref, ref + "^{}"}
+	if ref == "" {
+		patterns = []string{"HEAD"}
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", append([]string{"ls-remote", "--", remote}, patterns...)...)
+	// Never prompt for credentials, because there might not be a terminal
+	// to prompt on. The subsequent fetch will report any authentication
+	// problems.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		displayRemote := remote
+		if u, err := url.Parse(remote); err == nil {
+			displayRemote = u.Redacted()
+		}
+		return "", fmt.Errorf("failed to query %s: %w\n%s", displayRemote, err, strings.TrimSpace(stderr.String()))
+	}
+
+	// "git ls-remote" matches the patterns against the end of each ref
+	// name, so it might return several refs. We prefer exact matches of
+	// branches and tags, and for annotated tags we need the commit that
+	// the tag refers to rather than the tag itself.
+	candidates := map[string]string{}
+	sc := bufio.NewScanner(&stdout)
+	for sc.Scan() {
+		commit, name, ok := strings.Cut(sc.Text(), "\t")
+		if !ok {
+			continue
+		}
+		candidates[name] = commit
+	}
+	names := []string{"HEAD"}
+	if ref != "" {
+		names = []string{
+			ref + "^{}",
+			ref,
+			"refs/heads/" + ref,
+			"refs/tags/" + ref + "^{}",
+			"refs/tags/" + ref,
+		}
+	}
+	for _, name := range names {
+		if commit, ok := candidates[name]; ok {
+			return commit, nil
+		}
+	}
+	return "", nil
+}
+
+// resolveOCIRevision resolves the tag in the given oci: package address to
+// the digest of the manifest it currently refers to.
+func resolveOCIRevision(ctx context.Context, packageAddr string) (string, error) {
+	digest, err := ResolveOCIPackageDigest(ctx, packageAddr)
+	if err != nil {
+		return "", err
+	}
+	return OCIPackageAddrWithDigest(packageAddr, digest)
+}
+
+// IsOCIPackage returns true if the given package address refers to an
+// artifact in an OCI registry, whose content can be identified by a digest.
+//
+// packageAddr must be formatted as if it were the result of an
+// addrs.ModulePackage.String() call.
+func IsOCIPackage(packageAddr string) bool {
+	return strings.HasPrefix(packageAddr, "oci://")
+}
+
+// ResolveOCIPackageDigest returns the digest of the manifest of the OCI
+// artifact at the given package address. If the address has a tag then this
+// requires querying the registry.
+func ResolveOCIPackageDigest(ctx context.Context, packageAddr string) (string, error) {
+	u, err := url.Parse(packageAddr)
+	if err != nil {
+		return "", fmt.Errorf("invalid OCI module package address: %w", err)
+	}
+	ref, err := parseOCIPackageAddr(u)
+	if err != nil {
+		return "", err
+	}
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+	client, err := newOCIClient()
+	if err != nil {
+		return "", err
+	}
+	_, desc, err := client.GetManifest(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return desc.Digest, nil
+}
+
+// OCIPackageAddrWithDigest returns the address of the artifact with the given
+// digest in the same repository as the given OCI package address. Installing
+// a package from the result verifies that its manifest has that digest.
+func OCIPackageAddrWithDigest(packageAddr, digest string) (string, error) {
+	u, err := url.Parse(packageAddr)
+	if err != nil {
+		return "", fmt.Errorf("invalid OCI module package address: %w", err)
+	}
+	ref, err := parseOCIPackageAddr(u)
+	if err != nil {
+		return "", err
+	}
+	if !oci.ValidDigest(digest) {
+		return "", fmt.Errorf("invalid digest %q for OCI module package %s", digest, packageAddr)
+	}
+	return "oci://" + ref.WithDigest(digest).String(), nil
+}
@@ -23,6 +23,25 @@ import (
 // management in the "internal/providercache" package, to use the same
 // directory structure conventions.
 func SearchLocalDirectory(baseDir string) (map[addrs.Provider]PackageMetaList, error) {
+	return searchLocalDirectory(baseDir, "")
+}
+
+// SearchLocalDirectoryForProvider is like SearchLocalDirectory, but only
+// scans the part of the directory that can contain packages for the given
+// provider, and returns just those packages.
+func SearchLocalDirectoryForProvider(baseDir string, provider addrs.Provider) (PackageMetaList, error) {
+	subDir := filepath.Join(provider.Hostname.ForDisplay(), provider.Namespace, provider.Type)
+	ret, err := searchLocalDirectory(baseDir, subDir)
+	if err != nil {
+		return nil, err
+	}
+	return ret[provider], nil
+}
+
+// searchLocalDirectory scans the given subdirectory of the given base
+// directory, interpreting the paths it finds relative to the base directory.
+// If subDir doesn't exist then the result is empty.
+func searchLocalDirectory(baseDir, subDir string) (map[addrs.Provider]PackageMetaList, error) {
 	ret := make(map[addrs.Provider]PackageMetaList)
 
 	// We don't support symlinks at intermediate points inside the directory
@@ -45,7 +64,15 @@ func SearchLocalDirectory(baseDir string) (map[addrs.Provider]PackageMetaList, e
 		log.Printf("[TRACE] getproviders.SearchLocalDirectory: failed to resolve symlinks for %s: %s", baseDir, err)
 	}
 
-	err := filepath.Walk(baseDir, func(fullPath string, info os.FileInfo, err error) error {
+	walkDir := baseDir
+	if subDir != "" {
+		walkDir = filepath.Join(baseDir, subDir)
+		if _, err := os.Stat(walkDir); os.IsNotExist(err) {
+			return ret, nil
+		}
+	}
+
+	err := filepath.Walk(walkDir, func(fullPath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return fmt.Errorf("cannot search %s: %w", fullPath, err)
 		}
@@ -117,6 +144,16 @@ func SearchLocalDirectory(baseDir string) (map[addrs.Provider]PackageMetaList, e
 			providerAddr = addrs.NewProvider(hostname, namespace, typeName)
 		}
 
+		// Hidden entries alongside the unpacked packages belong to the
+		// provider installer, such as the lock files and temporary
+		// directories it uses while installing a package.
+		if len(parts) == 5 && strings.HasPrefix(parts[4], ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		// The "info" passed to our function is an Lstat result, so it might
 		// be referring to a symbolic link. We'll do a full "Stat" on it
 		// now to make sure we're making tests against the real underlying
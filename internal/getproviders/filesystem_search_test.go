@@ -53,3 +53,34 @@ func TestSearchLocalDirectory(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchLocalDirectoryForProvider(t *testing.T) {
+	baseDir := "testdata/search-local-directory/symlinks/symlink"
+
+	provider := addrs.MustParseProviderSourceString("example.com/foo/bar")
+	got, err := SearchLocalDirectoryForProvider(baseDir, provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := PackageMetaList{
+		{
+			Provider:       provider,
+			Version:        MustParseVersion("1.0.0"),
+			TargetPlatform: Platform{OS: "linux", Arch: "amd64"},
+			Filename:       "terraform-provider-bar_1.0.0_linux_amd64.zip",
+			Location:       PackageLocalDir("testdata/search-local-directory/symlinks/real/example.com/foo/bar/1.0.0/linux_amd64"),
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	// A provider without a directory has no packages.
+	got, err = SearchLocalDirectoryForProvider(baseDir, addrs.MustParseProviderSourceString("example.com/foo/baz"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("unexpected packages: %#v", got)
+	}
+}
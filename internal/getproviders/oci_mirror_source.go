@@ -0,0 +1,228 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package getproviders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/oci"
+)
+
+// Media types of the layer containing a provider's distribution archive in
+// an OCI artifact.
+const (
+	ociProviderLayerMediaType = "application/vnd.opentofu.provider.v1.zip"
+	ociZipLayerMediaType      = "application/zip"
+)
+
+// ociTitleAnnotation is the standard annotation giving the filename of the
+// content of a layer.
+const ociTitleAnnotation = "org.opencontainers.image.title"
+
+// OCIMirrorSource is a source that reads provider packages from artifacts in
+// a registry implementing the OCI distribution specification.
+//
+// Each provider has its own repository, whose name is given by a template
+// which may refer to the provider's hostname, namespace and type. Each
+// version of the provider is an image index tagged with the version number,
+// which has an image manifest for each platform the version is available
+// for. Each of those image manifests has a single layer, which is the
+// provider's distribution archive for that platform.
+//
+// Because the digest of each layer is the SHA256 checksum of the
+// distribution archive, the source reports it as a "zh:" hash, so that it's
+// verified on installation and recorded in the dependency lock file.
+type OCIMirrorSource struct {
+	repositoryTemplate string
+	client             *oci.Client
+}
+
+var _ Source = (*OCIMirrorSource)(nil)
+
+// NewOCIMirrorSource constructs and returns a new OCI mirror source whose
+// repositories are named by the given template, which must be a registry
+// hostname and repository name that may include the placeholders
+// ${hostname}, ${namespace} and ${type}.
+//
+// The given credentials are used to authenticate to the registry, and may be
+// nil if the registry allows anonymous access.
+func NewOCIMirrorSource(repositoryTemplate string, creds oci.CredentialsSource) (*OCIMirrorSource, error) {
+	s := &OCIMirrorSource{
+		repositoryTemplate: repositoryTemplate,
+		client:             oci.NewClient(creds),
+	}
+	// We check the template by substituting some placeholder values for
+	// a provider, so that invalid templates are reported before any
+	// provider is requested.
+	ref, err := s.repository(addrs.NewDefaultProvider("example"))
+	if err != nil {
+		return nil, err
+	}
+	if ref.Tag != "" || ref.Digest != "" {
+		return nil, fmt.Errorf("invalid repository template %q: must not include a tag or digest", repositoryTemplate)
+	}
+	return s, nil
+}
+
+// AvailableVersions retrieves the available versions for the given provider
+// from the tags of its repository.
+func (s *OCIMirrorSource) AvailableVersions(ctx context.Context, provider addrs.Provider) (VersionList, Warnings, error) {
+	ref, err := s.repository(provider)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Printf("[DEBUG] Querying available versions of provider %s at OCI mirror %s", provider, ref)
+
+	tags, err := s.client.ListTags(ctx, ref)
+	if errors.Is(err, oci.ErrNotFound) {
+		return nil, nil, ErrProviderNotFound{
+			Provider: provider,
+		}
+	}
+	if err != nil {
+		return nil, nil, s.errQueryFailed(provider, ref, err)
+	}
+
+	ret := make(VersionList, 0, len(tags))
+	for _, tag := range tags {
+		version, err := ParseVersion(tag)
+		if err != nil {
+			// Repositories can have other tags, such as "latest", which
+			// aren't provider versions.
+			log.Printf("[TRACE] Ignoring tag %q in %s, which is not a version number", tag, ref)
+			continue
+		}
+		ret = append(ret, version)
+	}
+	ret.Sort()
+	return ret, nil, nil
+}
+
+// PackageMeta retrieves metadata for the requested provider package from the
+// image index tagged with the given version in the provider's repository.
+func (s *OCIMirrorSource) PackageMeta(ctx context.Context, provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	repo, err := s.repository(provider)
+	if err != nil {
+		return PackageMeta{}, err
+	}
+	ref := repo.WithTag(version.String())
+	log.Printf("[DEBUG] Finding package for %s v%s on %s via OCI mirror %s", provider, version, target, ref)
+
+	index, _, err := s.client.GetManifest(ctx, ref)
+	if err != nil {
+		return PackageMeta{}, s.errQueryFailed(provider, repo, err)
+	}
+	if !index.IsIndex() {
+		return PackageMeta{}, s.errQueryFailed(provider, repo, fmt.Errorf("%s must be an image index with a manifest for each platform", ref))
+	}
+	var platformManifest *oci.Descriptor
+	for i, desc := range index.Manifests {
+		if desc.Platform != nil && desc.Platform.OS == target.OS && desc.Platform.Architecture == target.Arch {
+			platformManifest = &index.Manifests[i]
+			break
+		}
+	}
+	if platformManifest == nil {
+		return PackageMeta{}, ErrPlatformNotSupported{
+			Provider:  provider,
+			Version:   version,
+			Platform:  target,
+			MirrorURL: ociRepositoryURL(repo),
+		}
+	}
+
+	manifest, _, err := s.client.GetManifest(ctx, repo.WithDigest(platformManifest.Digest))
+	if err != nil {
+		return PackageMeta{}, s.errQueryFailed(provider, repo, err)
+	}
+	if manifest.IsIndex() || len(manifest.Layers) != 1 {
+		return PackageMeta{}, s.errQueryFailed(provider, repo, fmt.Errorf("the manifest of %s for %s must have exactly one layer", ref, target))
+	}
+	layer := manifest.Layers[0]
+	switch layer.MediaType {
+	case ociProviderLayerMediaType, ociZipLayerMediaType:
+		// Okay
+	default:
+		return PackageMeta{}, s.errQueryFailed(provider, repo, fmt.Errorf("the layer of %s for %s has unsupported media type %q", ref, target, layer.MediaType))
+	}
+	if !oci.ValidDigest(layer.Digest) {
+		return PackageMeta{}, s.errQueryFailed(provider, repo, fmt.Errorf("the layer of %s for %s has unsupported digest %q", ref, target, layer.Digest))
+	}
+	digest := strings.TrimPrefix(layer.Digest, "sha256:")
+
+	filename := path.Base(layer.Annotations[ociTitleAnnotation])
+	if !strings.HasSuffix(filename, ".zip") {
+		filename = fmt.Sprintf("terraform-provider-%s_%s_%s.zip", provider.Type, version, target)
+	}
+
+	return PackageMeta{
+		Provider:       provider,
+		Version:        version,
+		TargetPlatform: target,
+
+		Location: PackageOCIBlob{
+			Client:     s.client,
+			Repository: repo,
+			Digest:     layer.Digest,
+			Size:       layer.Size,
+		},
+		Filename: filename,
+
+		// The layer digest is the SHA256 checksum of the archive, which is
+		// exactly what the "zh:" hash scheme records.
+		Authentication: NewPackageHashAuthentication(target, []Hash{HashSchemeZip.New(digest)}),
+	}, nil
+}
+
+// ForDisplay returns a string description of the source for user-facing output.
+func (s *OCIMirrorSource) ForDisplay(provider addrs.Provider) string {
+	if ref, err := s.repository(provider); err == nil {
+		return "OCI mirror at " + ociRepositoryURL(ref).String()
+	}
+	return "OCI mirror at " + s.repositoryTemplate
+}
+
+// repository returns a reference to the repository for the given provider.
+func (s *OCIMirrorSource) repository(provider addrs.Provider) (oci.Reference, error) {
+	raw := strings.NewReplacer(
+		"${hostname}", provider.Hostname.String(),
+		"${namespace}", provider.Namespace,
+		"${type}", provider.Type,
+	).Replace(s.repositoryTemplate)
+	ref, err := oci.ParseReference(raw)
+	if err != nil {
+		return ref, fmt.Errorf("invalid repository template %q: %w", s.repositoryTemplate, err)
+	}
+	return ref, nil
+}
+
+func (s *OCIMirrorSource) errQueryFailed(provider addrs.Provider, repo oci.Reference, err error) error {
+	if errors.Is(err, context.Canceled) {
+		// This one has a special error type so that callers can
+		// handle it in a different way.
+		return ErrRequestCanceled{}
+	}
+	return ErrQueryFailed{
+		Provider:  provider,
+		Wrapped:   err,
+		MirrorURL: ociRepositoryURL(repo),
+	}
+}
+
+// ociRepositoryURL returns an oci: URL for the given repository, for use in
+// error messages.
+func ociRepositoryURL(repo oci.Reference) *url.URL {
+	return &url.URL{
+		Scheme: "oci",
+		Host:   repo.Registry,
+		Path:   "/" + repo.Repository,
+	}
+}
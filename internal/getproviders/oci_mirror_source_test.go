@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package getproviders
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/oci"
+)
+
+func TestOCIMirrorSource(t *testing.T) {
+	registry := oci.NewTestRegistry(t)
+	tosPlatform := Platform{OS: "tos", Arch: "m68k"}
+	archive := []byte("not really a zip file")
+	layer := testPushOCIProvider(t, registry, "providers/test/exists", "1.0.0", tosPlatform, archive)
+	testPushOCIProvider(t, registry, "providers/test/exists", "1.0.1", tosPlatform, archive)
+	registry.PushManifest("providers/test/exists", "latest", &oci.Manifest{
+		SchemaVersion: 2,
+		MediaType:     oci.MediaTypeImageIndex,
+	})
+
+	source, err := NewOCIMirrorSource(registry.Host()+"/providers/${namespace}/${type}", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	existingProvider := addrs.MustParseProviderSourceString("terraform.io/test/exists")
+	missingProvider := addrs.MustParseProviderSourceString("terraform.io/test/missing")
+
+	t.Run("AvailableVersions for provider that exists", func(t *testing.T) {
+		got, _, err := source.AvailableVersions(context.Background(), existingProvider)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		want := VersionList{
+			MustParseVersion("1.0.0"),
+			MustParseVersion("1.0.1"),
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong result\n%s", diff)
+		}
+	})
+	t.Run("AvailableVersions for provider that doesn't exist", func(t *testing.T) {
+		_, _, err := source.AvailableVersions(context.Background(), missingProvider)
+		if _, ok := err.(ErrProviderNotFound); !ok {
+			t.Fatalf("wrong error type %T: %s", err, err)
+		}
+	})
+	t.Run("PackageMeta for a version that exists and has a hash", func(t *testing.T) {
+		version := MustParseVersion("1.0.0")
+		got, err := source.PackageMeta(context.Background(), existingProvider, version, tosPlatform)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		sum := sha256.Sum256(archive)
+		wantHash := HashSchemeZip.New(hex.EncodeToString(sum[:]))
+		want := PackageMeta{
+			Provider:       existingProvider,
+			Version:        version,
+			TargetPlatform: tosPlatform,
+			Filename:       "terraform-provider-exists_1.0.0_tos_m68k.zip",
+			Location: PackageOCIBlob{
+				Client: source.client,
+				Repository: oci.Reference{
+					Registry:   registry.Host(),
+					Repository: "providers/test/exists",
+				},
+				Digest: layer.Digest,
+				Size:   layer.Size,
+			},
+			Authentication: packageHashAuthentication{
+				RequiredHashes: []Hash{wantHash},
+				AllHashes:      []Hash{wantHash},
+				Platform:       tosPlatform,
+			},
+		}
+		if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b *oci.Client) bool { return a == b })); diff != "" {
+			t.Errorf("wrong result\n%s", diff)
+		}
+
+		// The hash is recorded in lock files.
+		if diff := cmp.Diff([]Hash{wantHash}, got.AcceptableHashes()); diff != "" {
+			t.Errorf("wrong acceptable hashes\n%s", diff)
+		}
+	})
+	t.Run("PackageMeta for a version that exists but not for the platform", func(t *testing.T) {
+		version := MustParseVersion("1.0.0")
+		_, err := source.PackageMeta(context.Background(), existingProvider, version, Platform{OS: "weird", Arch: "nonsense"})
+		if _, ok := err.(ErrPlatformNotSupported); !ok {
+			t.Fatalf("wrong error type %T: %s", err, err)
+		}
+	})
+	t.Run("PackageMeta for a tag that isn't a provider version", func(t *testing.T) {
+		registry.PushManifest("providers/test/exists", "2.0.0", &oci.Manifest{
+			SchemaVersion: 2,
+			MediaType:     oci.MediaTypeImageManifest,
+		})
+		version := MustParseVersion("2.0.0")
+		_, err := source.PackageMeta(context.Background(), existingProvider, version, tosPlatform)
+		if _, ok := err.(ErrQueryFailed); !ok {
+			t.Fatalf("wrong error type %T: %s", err, err)
+		}
+		if got, want := err.Error(), "must be an image index"; !strings.Contains(got, want) {
+			t.Errorf("wrong error\ngot:  %s\nwant: containing %q", got, want)
+		}
+	})
+}
+
+func TestNewOCIMirrorSource_invalid(t *testing.T) {
+	tests := map[string]string{
+		"example.com":                         `must start with a registry hostname`,
+		"example.com/${type}:latest":          `must not include a tag or digest`,
+		"example.com/${namespace}/${unknown}": `invalid repository name`,
+	}
+	for template, want := range tests {
+		t.Run(template, func(t *testing.T) {
+			_, err := NewOCIMirrorSource(template, nil)
+			if err == nil {
+				t.Fatal("unexpected success")
+			}
+			if got := err.Error(); !strings.Contains(got, want) {
+				t.Errorf("wrong error\ngot:  %s\nwant: containing %q", got, want)
+			}
+		})
+	}
+}
+
+// testPushOCIProvider pushes the given archive as the only package of a
+// provider version in the given repository, returning the descriptor of its
+// layer.
+func testPushOCIProvider(t *testing.T, registry *oci.TestRegistry, repository, version string, platform Platform, archive []byte) oci.Descriptor {
+	t.Helper()
+
+	layer := registry.PushBlob("application/vnd.opentofu.provider.v1.zip", archive)
+	manifest := registry.PushManifest(repository, "", &oci.Manifest{
+		SchemaVersion: 2,
+		MediaType:     oci.MediaTypeImageManifest,
+		Layers:        []oci.Descriptor{layer},
+	})
+	manifest.Platform = &oci.Platform{OS: platform.OS, Architecture: platform.Arch}
+	registry.PushManifest(repository, version, &oci.Manifest{
+		SchemaVersion: 2,
+		MediaType:     oci.MediaTypeImageIndex,
+		Manifests:     []oci.Descriptor{manifest},
+	})
+	return layer
+}
@@ -4,7 +4,9 @@
 package getproviders
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"runtime"
 	"sort"
 	"strings"
@@ -13,6 +15,7 @@ import (
 	"github.com/apparentlymart/go-versions/versions/constraints"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/oci"
 )
 
 // Version represents a particular single version of a provider.
@@ -282,7 +285,8 @@ func (m PackageMeta) AcceptableHashes() []Hash {
 
 // PackageLocation represents a location where a provider distribution package
 // can be obtained. A value of this type contains one of the following
-// concrete types: PackageLocalArchive, PackageLocalDir, or PackageHTTPURL.
+// concrete types: PackageLocalArchive, PackageLocalDir, PackageHTTPURL, or
+// PackageOCIBlob.
 type PackageLocation interface {
 	packageLocation()
 	String() string
@@ -313,6 +317,35 @@ type PackageHTTPURL string
 func (p PackageHTTPURL) packageLocation() {}
 func (p PackageHTTPURL) String() string   { return string(p) }
 
+// PackageOCIBlob is a provider package location in a registry implementing
+// the OCI distribution specification: a blob with the given digest and size
+// in the given repository, which contains the provider's distribution
+// archive.
+type PackageOCIBlob struct {
+	Client     *oci.Client
+	Repository oci.Reference
+	Digest     string
+	Size       int64
+}
+
+func (p PackageOCIBlob) packageLocation() {}
+func (p PackageOCIBlob) String() string {
+	return "oci://" + p.Repository.WithDigest(p.Digest).String()
+}
+
+// Fetch writes the content of the blob to the given writer, verifying that
+// it matches the blob's size and digest.
+//
+// If the verification fails then some or all of the content might already
+// have been written before the error is returned, so callers must discard
+// it.
+func (p PackageOCIBlob) Fetch(ctx context.Context, w io.Writer) error {
+	return p.Client.FetchBlob(ctx, p.Repository, oci.Descriptor{
+		Digest: p.Digest,
+		Size:   p.Size,
+	}, w)
+}
+
 // PackageMetaList is a list of PackageMeta. It's just []PackageMeta with
 // some methods for convenient sorting and filtering.
 type PackageMetaList []PackageMeta
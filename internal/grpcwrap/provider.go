@@ -6,6 +6,7 @@ package grpcwrap
 import (
 	"context"
 
+	"fmt"
 	"github.com/opentofu/opentofu/internal/plugin/convert"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/tfplugin5"
@@ -62,8 +63,16 @@ func (p *provider) GetSchema(_ context.Context, req *tfplugin5.GetProviderSchema
 		}
 	}
 
+	if len(p.schema.Functions) > 0 {
+		resp.Functions = make(map[string]*tfplugin5.Function, len(p.schema.Functions))
+	}
+	for name, fn := range p.schema.Functions {
+		resp.Functions[name] = convert.FunctionSpecToProto(fn)
+	}
+
 	resp.ServerCapabilities = &tfplugin5.GetProviderSchema_ServerCapabilities{
-		PlanDestroy: p.schema.ServerCapabilities.PlanDestroy,
+		PlanDestroy:       p.schema.ServerCapabilities.PlanDestroy,
+		MoveResourceState: p.schema.ServerCapabilities.MoveResourceState,
 	}
 
 	// include any diagnostics from the original GetSchema call
@@ -350,6 +359,38 @@ func (p *provider) ImportResourceState(_ context.Context, req *tfplugin5.ImportR
 	return resp, nil
 }
 
+func (p *provider) MoveResourceState(_ context.Context, req *tfplugin5.MoveResourceState_Request) (*tfplugin5.MoveResourceState_Response, error) {
+	resp := &tfplugin5.MoveResourceState_Response{}
+
+	moveReq := providers.MoveResourceStateRequest{
+		SourceProviderAddress: req.SourceProviderAddress,
+		SourceTypeName:        req.SourceTypeName,
+		SourceSchemaVersion:   req.SourceSchemaVersion,
+		SourcePrivate:         req.SourcePrivate,
+		TargetTypeName:        req.TargetTypeName,
+	}
+	if req.SourceState != nil {
+		moveReq.SourceStateJSON = req.SourceState.Json
+	}
+
+	moveResp := p.provider.MoveResourceState(moveReq)
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, moveResp.Diagnostics)
+	if moveResp.Diagnostics.HasErrors() {
+		return resp, nil
+	}
+	resp.TargetPrivate = moveResp.TargetPrivate
+
+	ty := p.schema.ResourceTypes[req.TargetTypeName].Block.ImpliedType()
+	targetState, err := encodeDynamicValue(moveResp.TargetState, ty)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		return resp, nil
+	}
+	resp.TargetState = targetState
+
+	return resp, nil
+}
+
 func (p *provider) ReadDataSource(_ context.Context, req *tfplugin5.ReadDataSource_Request) (*tfplugin5.ReadDataSource_Response, error) {
 	resp := &tfplugin5.ReadDataSource_Response{}
 	ty := p.schema.DataSources[req.TypeName].Block.ImpliedType()
@@ -386,6 +427,83 @@ func (p *provider) ReadDataSource(_ context.Context, req *tfplugin5.ReadDataSour
 	return resp, nil
 }
 
+func (p *provider) GetFunctions(context.Context, *tfplugin5.GetFunctions_Request) (*tfplugin5.GetFunctions_Response, error) {
+	resp := &tfplugin5.GetFunctions_Response{}
+
+	functionsResp := p.provider.GetFunctions()
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, functionsResp.Diagnostics)
+	if functionsResp.Diagnostics.HasErrors() {
+		return resp, nil
+	}
+
+	resp.Functions = make(map[string]*tfplugin5.Function, len(functionsResp.Functions))
+	for name, fn := range functionsResp.Functions {
+		resp.Functions[name] = convert.FunctionSpecToProto(fn)
+	}
+
+	return resp, nil
+}
+
+func (p *provider) CallFunction(_ context.Context, req *tfplugin5.CallFunction_Request) (*tfplugin5.CallFunction_Response, error) {
+	resp := &tfplugin5.CallFunction_Response{}
+
+	spec, ok := p.schema.Functions[req.Name]
+	if !ok {
+		resp.Error = &tfplugin5.FunctionError{
+			Text: fmt.Sprintf("unknown function %q", req.Name),
+		}
+		return resp, nil
+	}
+
+	args := make([]cty.Value, len(req.Arguments))
+	for i, arg := range req.Arguments {
+		argIdx := int64(i)
+		var paramTy cty.Type
+		switch {
+		case i < len(spec.Parameters):
+			paramTy = spec.Parameters[i].Type
+		case spec.VariadicParameter != nil:
+			paramTy = spec.VariadicParameter.Type
+		default:
+			resp.Error = &tfplugin5.FunctionError{
+				Text:             fmt.Sprintf("too many arguments for function %q", req.Name),
+				FunctionArgument: &argIdx,
+			}
+			return resp, nil
+		}
+
+		val, err := decodeDynamicValue(arg, paramTy)
+		if err != nil {
+			resp.Error = &tfplugin5.FunctionError{
+				Text:             err.Error(),
+				FunctionArgument: &argIdx,
+			}
+			return resp, nil
+		}
+		args[i] = val
+	}
+
+	callResp := p.provider.CallFunction(providers.CallFunctionRequest{
+		Name:      req.Name,
+		Arguments: args,
+	})
+	resp.Error = convert.FunctionErrorToProto(callResp.Diagnostics)
+	if resp.Error != nil {
+		return resp, nil
+	}
+
+	result, err := encodeDynamicValue(callResp.Result, spec.Return)
+	if err != nil {
+		resp.Error = &tfplugin5.FunctionError{
+			Text: err.Error(),
+		}
+		return resp, nil
+	}
+	resp.Result = result
+
+	return resp, nil
+}
+
 func (p *provider) Stop(context.Context, *tfplugin5.Stop_Request) (*tfplugin5.Stop_Response, error) {
 	resp := &tfplugin5.Stop_Response{}
 	err := p.provider.Stop()
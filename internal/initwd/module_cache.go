@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package initwd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/sumdb/dirhash"
+
+	"github.com/opentofu/opentofu/internal/copy"
+)
+
+// moduleCache is a directory containing copies of remote module packages
+// that can be shared between many working directories, so that each
+// package needs to be downloaded only once.
+//
+// Each entry is identified by a key that must uniquely identify the content
+// of the package, such as a registry module address along with the selected
+// version, or a git repository address with a commit id. Each entry is
+// stored in a subdirectory named after a hash of its key, containing the
+// package itself in a "package" subdirectory, the key in a "source" file
+// and a hash of the package's content in a "hash" file, which we verify
+// before using the entry.
+type moduleCache struct {
+	dir string
+}
+
+func (c *moduleCache) entryDir(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// install copies the package with the given key from the cache into
+// instPath, which must not already exist.
+//
+// The result is false if the cache has no entry for the given key. An entry
+// whose content doesn't match its recorded hash, such as one that was
+// modified after it was added to the cache, is removed from the cache and
+// also produces a false result.
+func (c *moduleCache) install(key, instPath string) (bool, error) {
+	entryDir := c.entryDir(key)
+	packageDir := filepath.Join(entryDir, "package")
+
+	want, err := os.ReadFile(filepath.Join(entryDir, "hash"))
+	if errors.Is(err, os.ErrNotExist) {
+		// Entries are renamed into place along with their hash file, so an
+		// entry without one can only be the result of tampering. We remove
+		// it so that it can be replaced.
+		if err := os.RemoveAll(entryDir); err != nil {
+			return false, fmt.Errorf("failed to remove corrupt module cache entry: %w", err)
+		}
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to read module cache entry: %w", err)
+	}
+	got, err := dirhash.HashDir(packageDir, "", dirhash.Hash1)
+	if err != nil || got != strings.TrimSpace(string(want)) {
+		log.Printf("[WARN] Module cache entry %s for %s is corrupt, so removing it", entryDir, key)
+		if err := os.RemoveAll(entryDir); err != nil {
+			return false, fmt.Errorf("failed to remove corrupt module cache entry: %w", err)
+		}
+		return false, nil
+	}
+
+	if err := os.MkdirAll(instPath, os.ModePerm); err != nil {
+		return false, fmt.Errorf("failed to create directory %s: %w", instPath, err)
+	}
+	if err := copy.CopyDir(instPath, packageDir); err != nil {
+		return false, fmt.Errorf("failed to copy from %s to %s: %w", packageDir, instPath, err)
+	}
+	return true, nil
+}
+
+// store adds a copy of the package that was installed at instPath to the
+// cache with the given key.
+//
+// Entries are created in a temporary directory and then renamed into place,
+// so concurrent installations sharing the same cache directory never see
+// a partially-written entry. If another installation stores the same key
+// first then its entry is kept.
+func (c *moduleCache) store(key, instPath string) error {
+	if err := os.MkdirAll(c.dir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create module cache directory: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp(c.dir, ".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to create module cache entry: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := os.Chmod(tmpDir, 0755); err != nil {
+		return fmt.Errorf("failed to create module cache entry: %w", err)
+	}
+
+	packageDir := filepath.Join(tmpDir, "package")
+	if err := os.Mkdir(packageDir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create module cache entry: %w", err)
+	}
+	if err := copy.CopyDir(packageDir, instPath); err != nil {
+		return fmt.Errorf("failed to copy from %s to %s: %w", instPath, packageDir, err)
+	}
+	hash, err := dirhash.HashDir(packageDir, "", dirhash.Hash1)
+	if err != nil {
+		return fmt.Errorf("failed to hash module package: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "hash"), []byte(hash+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to create module cache entry: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "source"), []byte(key+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to create module cache entry: %w", err)
+	}
+
+	// Renaming a directory fails if the destination already exists and
+	// isn't empty, rather than replacing it.
+	entryDir := c.entryDir(key)
+	if err := os.Rename(tmpDir, entryDir); err != nil {
+		if _, statErr := os.Stat(entryDir); statErr == nil {
+			// Another installation got there first.
+			return nil
+		}
+		return fmt.Errorf("failed to create module cache entry: %w", err)
+	}
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/getmodules"
 	"github.com/opentofu/opentofu/internal/modsdir"
 	"github.com/opentofu/opentofu/internal/registry"
@@ -41,6 +42,14 @@ type ModuleInstaller struct {
 	// The keys in moduleVersionsUrl are the moduleVersion struct below and
 	// addresses and the values are underlying remote source addresses.
 	registryPackageSources map[moduleVersion]addrs.ModuleSourceRemote
+
+	// cache, if set, is a directory of module packages shared with other
+	// working directories.
+	cache *moduleCache
+
+	// locks, if set, records the digests of the module packages that can be
+	// identified by one, which are verified when they are installed again.
+	locks *depsfile.Locks
 }
 
 type moduleVersion struct {
@@ -58,6 +67,33 @@ func NewModuleInstaller(modsDir string, loader *configload.Loader, reg *registry
 	}
 }
 
+// SetModuleCacheDir activates caching of remote module packages in the given
+// directory, which can be shared between many working directories so that
+// each package needs to be downloaded only once.
+//
+// Only packages whose content can be identified exactly are cached: modules
+// from a module registry, keyed by their address and selected version, and
+// git repositories, keyed by their address and the commit that the requested
+// ref currently refers to. Packages from the cache are copied into the
+// modules directory, after verifying that they haven't been modified since
+// they were added to the cache.
+func (i *ModuleInstaller) SetModuleCacheDir(dir string) {
+	i.cache = &moduleCache{dir: dir}
+}
+
+// SetDependencyLocks activates dependency locking for module packages whose
+// content can be identified by a digest, which is currently only packages in
+// OCI registries.
+//
+// When such a package is installed, it is installed from the digest recorded
+// for it in the given locks, which verifies that its content hasn't changed.
+// If there is no digest recorded yet, or if InstallModules is called with
+// the upgrade flag set, the digest of the package's current content is
+// recorded in the given locks instead, which the caller should then save.
+func (i *ModuleInstaller) SetDependencyLocks(locks *depsfile.Locks) {
+	i.locks = locks
+}
+
 // InstallModules analyses the root module in the given directory and installs
 // all of its direct and transitive dependencies into the given modules
 // directory, which must already exist.
@@ -92,11 +128,14 @@ func NewModuleInstaller(modsDir string, loader *configload.Loader, reg *registry
 // If successful (the returned diagnostics contains no errors) then the
 // first return value is the early configuration tree that was constructed by
 // the installation process.
-func (i *ModuleInstaller) InstallModules(ctx context.Context, rootDir, testsDir string, upgrade, installErrsOnly bool, hooks ModuleInstallHooks) (*configs.Config, tfdiags.Diagnostics) {
+//
+// The given call provides the values of the root module's input variables,
+// which module source addresses and versions may refer to.
+func (i *ModuleInstaller) InstallModules(ctx context.Context, rootDir, testsDir string, upgrade, installErrsOnly bool, hooks ModuleInstallHooks, call configs.StaticModuleCall) (*configs.Config, tfdiags.Diagnostics) {
 	log.Printf("[TRACE] ModuleInstaller: installing child modules for %s into %s", rootDir, i.modsDir)
 	var diags tfdiags.Diagnostics
 
-	rootMod, mDiags := i.loader.Parser().LoadConfigDirWithTests(rootDir, testsDir)
+	rootMod, mDiags := i.loader.Parser().LoadConfigDirWithTests(rootDir, testsDir, call)
 	if rootMod == nil {
 		// We drop the diagnostics here because we only want to report module
 		// loading errors after checking the core version constraints, which we
@@ -238,7 +277,7 @@ func (i *ModuleInstaller) moduleInstallWalker(ctx context.Context, manifest mods
 				// keep our existing record.
 				info, err := os.Stat(record.Dir)
 				if err == nil && info.IsDir() {
-					mod, mDiags := i.loader.Parser().LoadConfigDir(record.Dir)
+					mod, mDiags := i.loader.Parser().LoadConfigDir(record.Dir, req.Call)
 					if mod == nil {
 						// nil indicates an unreadable module, which should never happen,
 						// so we return the full loader diagnostics here.
@@ -278,7 +317,7 @@ func (i *ModuleInstaller) moduleInstallWalker(ctx context.Context, manifest mods
 
 			case addrs.ModuleSourceRemote:
 				log.Printf("[TRACE] ModuleInstaller: %s address %q will be handled by go-getter", key, addr.String())
-				mod, mDiags := i.installGoGetterModule(ctx, req, key, instPath, manifest, hooks, fetcher)
+				mod, mDiags := i.installGoGetterModule(ctx, req, key, instPath, upgrade, manifest, hooks, fetcher)
 				diags = append(diags, mDiags...)
 				return mod, nil, diags
 
@@ -379,7 +418,7 @@ func (i *ModuleInstaller) installLocalModule(req *configs.ModuleRequest, key str
 	}
 
 	// Finally we are ready to try actually loading the module.
-	mod, mDiags := i.loader.Parser().LoadConfigDir(newDir)
+	mod, mDiags := i.loader.Parser().LoadConfigDir(newDir, req.Call)
 	if mod == nil {
 		// nil indicates missing or unreadable directory, so we'll
 		// discard the returned diags and return a more specific
@@ -625,7 +664,10 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 
 	log.Printf("[TRACE] ModuleInstaller: %s %s %s is available at %q", key, packageAddr, latestMatch, dlAddr.Package)
 
-	err := fetcher.FetchPackage(ctx, instPath, dlAddr.Package.String())
+	// A registry module's content is determined by its address and
+	// version, regardless of where the registry says to download it from.
+	cacheKey := packageAddr.String() + " " + latestMatch.String()
+	err := i.fetchPackage(ctx, fetcher, instPath, dlAddr.Package.String(), cacheKey)
 	if errors.Is(err, context.Canceled) {
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -661,7 +703,7 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 	log.Printf("[TRACE] ModuleInstaller: %s should now be at %s", key, modDir)
 
 	// Finally we are ready to try actually loading the module.
-	mod, mDiags := i.loader.Parser().LoadConfigDir(modDir)
+	mod, mDiags := i.loader.Parser().LoadConfigDir(modDir, req.Call)
 	if mod == nil {
 		// nil indicates missing or unreadable directory, so we'll
 		// discard the returned diags and return a more specific
@@ -695,7 +737,7 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 	return mod, latestMatch, diags
 }
 
-func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *configs.ModuleRequest, key string, instPath string, manifest modsdir.Manifest, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) (*configs.Module, hcl.Diagnostics) {
+func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *configs.ModuleRequest, key string, instPath string, upgrade bool, manifest modsdir.Manifest, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) (*configs.Module, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
 	// Report up to the caller that we're about to start downloading.
@@ -713,7 +755,28 @@ func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *config
 		return nil, diags
 	}
 
-	err := fetcher.FetchPackage(ctx, instPath, packageAddr.String())
+	fetchAddr := packageAddr.String()
+	if i.locks != nil && getmodules.IsOCIPackage(fetchAddr) {
+		var moreDiags hcl.Diagnostics
+		fetchAddr, moreDiags = i.lockPackageDigest(ctx, req, fetchAddr, upgrade)
+		diags = diags.Extend(moreDiags)
+		if moreDiags.HasErrors() {
+			return nil, diags
+		}
+	}
+
+	var cacheKey string
+	if i.cache != nil {
+		var err error
+		cacheKey, err = getmodules.ResolvePackageRevision(ctx, fetchAddr)
+		if err != nil {
+			// We'll still try to download the package, which will report
+			// a more specific error if the problem isn't just with
+			// resolving its revision.
+			log.Printf("[WARN] ModuleInstaller: not using the module cache for %s: %s", packageAddr, err)
+		}
+	}
+	err := i.fetchPackage(ctx, fetcher, instPath, fetchAddr, cacheKey)
 	if err != nil {
 		// go-getter generates a poor error for an invalid relative path, so
 		// we'll detect that case and generate a better one.
@@ -762,7 +825,7 @@ func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *config
 	log.Printf("[TRACE] ModuleInstaller: %s %q was downloaded to %s", key, addr, modDir)
 
 	// Finally we are ready to try actually loading the module.
-	mod, mDiags := i.loader.Parser().LoadConfigDir(modDir)
+	mod, mDiags := i.loader.Parser().LoadConfigDir(modDir, req.Call)
 	if mod == nil {
 		// nil indicates missing or unreadable directory, so we'll
 		// discard the returned diags and return a more specific
@@ -795,6 +858,78 @@ func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *config
 	return mod, diags
 }
 
+// lockPackageDigest returns the address to install the package at the given
+// address from, which identifies its content by the digest recorded for it in
+// the installer's dependency locks, so that installing it verifies that its
+// content hasn't changed since the digest was recorded.
+//
+// If there is no recorded digest, or if upgrade is set, the digest of the
+// package's current content is recorded first.
+func (i *ModuleInstaller) lockPackageDigest(ctx context.Context, req *configs.ModuleRequest, packageAddr string, upgrade bool) (string, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	lock := i.locks.Module(packageAddr)
+	if lock == nil || upgrade {
+		digest, err := getmodules.ResolveOCIPackageDigest(ctx, packageAddr)
+		if err != nil {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to resolve module package digest",
+				Detail:   fmt.Sprintf("Could not find the digest of module %q (%s:%d) source code at %q, which must be recorded in the dependency lock file: %s.", req.Name, req.CallRange.Filename, req.CallRange.Start.Line, packageAddr, err),
+				Subject:  req.CallRange.Ptr(),
+			})
+			return "", diags
+		}
+		log.Printf("[TRACE] ModuleInstaller: recording digest %s for %s", digest, packageAddr)
+		lock = i.locks.SetModule(packageAddr, digest)
+	}
+
+	fetchAddr, err := getmodules.OCIPackageAddrWithDigest(packageAddr, lock.Digest())
+	if err != nil {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid module package digest",
+			Detail:   fmt.Sprintf("Cannot install module %q (%s:%d) from the digest recorded in the dependency lock file: %s.", req.Name, req.CallRange.Filename, req.CallRange.Start.Line, err),
+			Subject:  req.CallRange.Ptr(),
+		})
+		return "", diags
+	}
+	return fetchAddr, diags
+}
+
+// fetchPackage retrieves the package at the given address into instPath,
+// using the module cache if there is one and the given cache key is not
+// empty. An empty cache key means that the package's content can't be
+// identified exactly, so it must always be retrieved from its source.
+func (i *ModuleInstaller) fetchPackage(ctx context.Context, fetcher *getmodules.PackageFetcher, instPath, packageAddr, cacheKey string) error {
+	if i.cache == nil || cacheKey == "" {
+		return fetcher.FetchPackage(ctx, instPath, packageAddr)
+	}
+
+	found, err := i.cache.install(cacheKey, instPath)
+	if err != nil {
+		// We'll fall back to retrieving the package from its source, so
+		// we need to remove anything we partially installed.
+		log.Printf("[WARN] ModuleInstaller: failed to install %s from the module cache: %s", cacheKey, err)
+		if err := os.RemoveAll(instPath); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", instPath, err)
+		}
+	}
+	if found {
+		log.Printf("[TRACE] ModuleInstaller: installed %s from the module cache into %s", cacheKey, instPath)
+		return nil
+	}
+
+	if err := fetcher.FetchPackage(ctx, instPath, packageAddr); err != nil {
+		return err
+	}
+	if err := i.cache.store(cacheKey, instPath); err != nil {
+		// The package is installed, so failing to cache it isn't fatal.
+		log.Printf("[WARN] ModuleInstaller: failed to add %s to the module cache: %s", cacheKey, err)
+	}
+	return nil
+}
+
 func (i *ModuleInstaller) packageInstallPath(modulePath addrs.Module) string {
 	return filepath.Join(i.modsDir, strings.Join(modulePath, "."))
 }
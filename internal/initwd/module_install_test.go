@@ -4,11 +4,14 @@
 package initwd
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -23,6 +26,8 @@ import (
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
 	"github.com/opentofu/opentofu/internal/copy"
+	"github.com/opentofu/opentofu/internal/depsfile"
+	"github.com/opentofu/opentofu/internal/oci"
 	"github.com/opentofu/opentofu/internal/registry"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 
@@ -45,7 +50,7 @@ func TestModuleInstaller(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, nil)
-	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks)
+	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks, configs.RootModuleCallForTesting())
 	assertNoDiagnostics(t, diags)
 
 	wantCalls := []testInstallHookCall{
@@ -76,7 +81,7 @@ func TestModuleInstaller(t *testing.T) {
 
 	// Make sure the configuration is loadable now.
 	// (This ensures that correct information is recorded in the manifest.)
-	config, loadDiags := loader.LoadConfig(".")
+	config, loadDiags := loader.LoadConfig(".", configs.RootModuleCallForTesting())
 	assertNoDiagnostics(t, tfdiags.Diagnostics{}.Append(loadDiags))
 
 	wantTraces := map[string]string{
@@ -109,7 +114,7 @@ func TestModuleInstaller_error(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, nil)
-	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks)
+	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks, configs.RootModuleCallForTesting())
 
 	if !diags.HasErrors() {
 		t.Fatal("expected error")
@@ -130,7 +135,7 @@ func TestModuleInstaller_emptyModuleName(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, nil)
-	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks)
+	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks, configs.RootModuleCallForTesting())
 
 	if !diags.HasErrors() {
 		t.Fatal("expected error")
@@ -151,7 +156,7 @@ func TestModuleInstaller_invalidModuleName(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, registry.NewClient(nil, nil))
-	_, diags := inst.InstallModules(context.Background(), dir, "tests", false, false, hooks)
+	_, diags := inst.InstallModules(context.Background(), dir, "tests", false, false, hooks, configs.RootModuleCallForTesting())
 	if !diags.HasErrors() {
 		t.Fatal("expected error")
 	} else {
@@ -188,7 +193,7 @@ func TestModuleInstaller_packageEscapeError(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, nil)
-	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks)
+	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks, configs.RootModuleCallForTesting())
 
 	if !diags.HasErrors() {
 		t.Fatal("expected error")
@@ -226,7 +231,7 @@ func TestModuleInstaller_explicitPackageBoundary(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, nil)
-	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks)
+	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks, configs.RootModuleCallForTesting())
 
 	if diags.HasErrors() {
 		t.Fatalf("unexpected errors\n%s", diags.Err().Error())
@@ -249,7 +254,7 @@ func TestModuleInstaller_ExactMatchPrerelease(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, registry.NewClient(nil, nil))
-	cfg, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks)
+	cfg, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks, configs.RootModuleCallForTesting())
 
 	if diags.HasErrors() {
 		t.Fatalf("found unexpected errors: %s", diags.Err())
@@ -276,7 +281,7 @@ func TestModuleInstaller_PartialMatchPrerelease(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, registry.NewClient(nil, nil))
-	cfg, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks)
+	cfg, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks, configs.RootModuleCallForTesting())
 
 	if diags.HasErrors() {
 		t.Fatalf("found unexpected errors: %s", diags.Err())
@@ -299,7 +304,7 @@ func TestModuleInstaller_invalid_version_constraint_error(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, nil)
-	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks)
+	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks, configs.RootModuleCallForTesting())
 
 	if !diags.HasErrors() {
 		t.Fatal("expected error")
@@ -325,7 +330,7 @@ func TestModuleInstaller_invalidVersionConstraintGetter(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, nil)
-	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks)
+	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks, configs.RootModuleCallForTesting())
 
 	if !diags.HasErrors() {
 		t.Fatal("expected error")
@@ -351,7 +356,7 @@ func TestModuleInstaller_invalidVersionConstraintLocal(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, nil)
-	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks)
+	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks, configs.RootModuleCallForTesting())
 
 	if !diags.HasErrors() {
 		t.Fatal("expected error")
@@ -377,7 +382,7 @@ func TestModuleInstaller_symlink(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, nil)
-	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks)
+	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks, configs.RootModuleCallForTesting())
 	assertNoDiagnostics(t, diags)
 
 	wantCalls := []testInstallHookCall{
@@ -408,7 +413,7 @@ func TestModuleInstaller_symlink(t *testing.T) {
 
 	// Make sure the configuration is loadable now.
 	// (This ensures that correct information is recorded in the manifest.)
-	config, loadDiags := loader.LoadConfig(".")
+	config, loadDiags := loader.LoadConfig(".", configs.RootModuleCallForTesting())
 	assertNoDiagnostics(t, tfdiags.Diagnostics{}.Append(loadDiags))
 
 	wantTraces := map[string]string{
@@ -453,7 +458,7 @@ func TestLoaderInstallModules_registry(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, registry.NewClient(nil, nil))
-	_, diags := inst.InstallModules(context.Background(), dir, "tests", false, false, hooks)
+	_, diags := inst.InstallModules(context.Background(), dir, "tests", false, false, hooks, configs.RootModuleCallForTesting())
 	assertNoDiagnostics(t, diags)
 
 	v := version.Must(version.NewVersion("0.0.1"))
@@ -567,7 +572,7 @@ func TestLoaderInstallModules_registry(t *testing.T) {
 
 	// Make sure the configuration is loadable now.
 	// (This ensures that correct information is recorded in the manifest.)
-	config, loadDiags := loader.LoadConfig(".")
+	config, loadDiags := loader.LoadConfig(".", configs.RootModuleCallForTesting())
 	assertNoDiagnostics(t, tfdiags.Diagnostics{}.Append(loadDiags))
 
 	wantTraces := map[string]string{
@@ -616,7 +621,7 @@ func TestLoaderInstallModules_goGetter(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, registry.NewClient(nil, nil))
-	_, diags := inst.InstallModules(context.Background(), dir, "tests", false, false, hooks)
+	_, diags := inst.InstallModules(context.Background(), dir, "tests", false, false, hooks, configs.RootModuleCallForTesting())
 	assertNoDiagnostics(t, diags)
 
 	wantCalls := []testInstallHookCall{
@@ -697,7 +702,7 @@ func TestLoaderInstallModules_goGetter(t *testing.T) {
 
 	// Make sure the configuration is loadable now.
 	// (This ensures that correct information is recorded in the manifest.)
-	config, loadDiags := loader.LoadConfig(".")
+	config, loadDiags := loader.LoadConfig(".", configs.RootModuleCallForTesting())
 	assertNoDiagnostics(t, tfdiags.Diagnostics{}.Append(loadDiags))
 
 	wantTraces := map[string]string{
@@ -723,6 +728,317 @@ func TestLoaderInstallModules_goGetter(t *testing.T) {
 
 }
 
+func TestModuleInstaller_moduleCache(t *testing.T) {
+	repoDir, commit := testModuleCacheRepo(t)
+	cacheDir := t.TempDir()
+
+	install := func(t *testing.T, source string) string {
+		t.Helper()
+
+		dir := t.TempDir()
+		config := fmt.Sprintf("module \"child\" {\n  source = %q\n}\n", source)
+		if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		modulesDir := filepath.Join(dir, ".terraform/modules")
+		if err := os.MkdirAll(modulesDir, os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		loader, close := configload.NewLoaderForTests(t)
+		defer close()
+		inst := NewModuleInstaller(modulesDir, loader, nil)
+		inst.SetModuleCacheDir(cacheDir)
+		_, diags := inst.InstallModules(context.Background(), dir, "tests", false, false, nil, configs.RootModuleCallForTesting())
+		assertNoDiagnostics(t, diags)
+
+		src, err := os.ReadFile(filepath.Join(modulesDir, "child", "main.tf"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(src)
+	}
+	cacheEntries := func(t *testing.T) []string {
+		t.Helper()
+
+		entries, err := os.ReadDir(cacheDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ret []string
+		for _, entry := range entries {
+			ret = append(ret, entry.Name())
+		}
+		return ret
+	}
+
+	t.Run("ref resolved to a commit", func(t *testing.T) {
+		source := "git::file://" + filepath.ToSlash(repoDir) + "?ref=v1.0.0"
+		if got := install(t, source); !strings.Contains(got, "in child module") {
+			t.Fatalf("wrong module content:\n%s", got)
+		}
+		entries := cacheEntries(t)
+		if len(entries) != 1 {
+			t.Fatalf("wrong cache entries %#v", entries)
+		}
+
+		// Modifying the cached package must cause it to be downloaded again,
+		// rather than the modified content being used.
+		cached := filepath.Join(cacheDir, entries[0], "package", "main.tf")
+		if err := os.WriteFile(cached, []byte("# modified\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got := install(t, source); !strings.Contains(got, "in child module") {
+			t.Fatalf("modified cache entry was used:\n%s", got)
+		}
+		if got, err := os.ReadFile(cached); err != nil || !strings.Contains(string(got), "in child module") {
+			t.Fatalf("cache entry was not repaired: %s\n%s", err, got)
+		}
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		source := "git::file://" + filepath.ToSlash(repoDir) + "?ref=" + commit
+		install(t, source)
+
+		// The tag in the previous test refers to the same commit, so this
+		// must use the same cache entry.
+		if entries := cacheEntries(t); len(entries) != 1 {
+			t.Fatalf("wrong cache entries %#v", entries)
+		}
+
+		// Once the package is cached, installing it again doesn't need
+		// the repository at all.
+		if err := os.RemoveAll(repoDir); err != nil {
+			t.Fatal(err)
+		}
+		if got := install(t, source); !strings.Contains(got, "in child module") {
+			t.Fatalf("wrong module content:\n%s", got)
+		}
+	})
+}
+
+// testModuleCacheRepo creates a git repository containing a module with a
+// single commit tagged as v1.0.0, returning its path and the commit id.
+func testModuleCacheRepo(t *testing.T) (string, string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte("variable \"v\" {\n  description = \"in child module\"\n  default     = \"\"\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git := func(args ...string) string {
+		t.Helper()
+
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false", "-c", "tag.gpgsign=false"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %s\n%s", strings.Join(args, " "), err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q")
+	git("add", "main.tf")
+	git("commit", "-q", "-m", "initial")
+	git("tag", "-a", "v1.0.0", "-m", "v1.0.0")
+	return dir, git("rev-parse", "HEAD")
+}
+
+func TestModuleInstaller_ociModule(t *testing.T) {
+	// The registry doesn't need credentials, but we must not use whatever
+	// Docker configuration the user running the tests has.
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	registry := oci.NewTestRegistry(t)
+	layer := registry.PushBlob("application/vnd.opentofu.modulepkg.v1.tar+gzip", testModuleTarGz(t, map[string]string{
+		"main.tf":       "variable \"v\" {\n  description = \"in child module\"\n  default     = \"\"\n}\n",
+		"inner/main.tf": "variable \"v\" {\n  description = \"in inner module\"\n  default     = \"\"\n}\n",
+	}))
+	registry.PushManifest("example/child", "v1.0.0", &oci.Manifest{
+		SchemaVersion: 2,
+		MediaType:     oci.MediaTypeImageManifest,
+		Layers:        []oci.Descriptor{layer},
+	})
+	cacheDir := t.TempDir()
+
+	install := func(t *testing.T, source string) (string, tfdiags.Diagnostics) {
+		t.Helper()
+
+		dir := t.TempDir()
+		config := fmt.Sprintf("module \"child\" {\n  source = %q\n}\n", source)
+		if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		modulesDir := filepath.Join(dir, ".terraform/modules")
+		if err := os.MkdirAll(modulesDir, os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		loader, close := configload.NewLoaderForTests(t)
+		defer close()
+		inst := NewModuleInstaller(modulesDir, loader, nil)
+		inst.SetModuleCacheDir(cacheDir)
+		cfg, diags := inst.InstallModules(context.Background(), dir, "tests", false, false, nil, configs.RootModuleCallForTesting())
+		if diags.HasErrors() {
+			return "", diags
+		}
+		return cfg.Children["child"].Module.Variables["v"].Description, diags
+	}
+
+	got, diags := install(t, "oci://"+registry.Host()+"/example/child:v1.0.0")
+	assertNoDiagnostics(t, diags)
+	if got != "in child module" {
+		t.Errorf("wrong module installed: %q", got)
+	}
+
+	got, diags = install(t, "oci://"+registry.Host()+"/example/child:v1.0.0//inner")
+	assertNoDiagnostics(t, diags)
+	if got != "in inner module" {
+		t.Errorf("wrong module installed: %q", got)
+	}
+
+	// Both installations used the same package, which was cached by the
+	// digest of its manifest.
+	if entries, err := os.ReadDir(cacheDir); err != nil || len(entries) != 1 {
+		t.Errorf("wrong cache entries %v (%v)", entries, err)
+	}
+
+	_, diags = install(t, "oci://"+registry.Host()+"/example/child:v2.0.0")
+	if !diags.HasErrors() {
+		t.Fatal("unexpected success installing missing tag")
+	}
+	if got := diags.Err().Error(); !strings.Contains(got, "not found") {
+		t.Errorf("wrong error: %s", got)
+	}
+
+	// A layer whose content doesn't match its digest must be rejected.
+	registry.CorruptBlob(layer.Digest)
+	if err := os.RemoveAll(cacheDir); err != nil {
+		t.Fatal(err)
+	}
+	_, diags = install(t, "oci://"+registry.Host()+"/example/child:v1.0.0")
+	if !diags.HasErrors() {
+		t.Fatal("unexpected success installing corrupt package")
+	}
+	if got := diags.Err().Error(); !strings.Contains(got, "has the wrong digest") {
+		t.Errorf("wrong error: %s", got)
+	}
+}
+
+func TestModuleInstaller_ociModuleLocks(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	registry := oci.NewTestRegistry(t)
+	push := func(description string) oci.Descriptor {
+		t.Helper()
+		layer := registry.PushBlob("application/vnd.opentofu.modulepkg.v1.tar+gzip", testModuleTarGz(t, map[string]string{
+			"main.tf": fmt.Sprintf("variable \"v\" {\n  description = %q\n  default     = \"\"\n}\n", description),
+		}))
+		return registry.PushManifest("example/child", "v1.0.0", &oci.Manifest{
+			SchemaVersion: 2,
+			MediaType:     oci.MediaTypeImageManifest,
+			Layers:        []oci.Descriptor{layer},
+		})
+	}
+	source := "oci://" + registry.Host() + "/example/child:v1.0.0"
+	locks := depsfile.NewLocks()
+
+	install := func(t *testing.T, upgrade bool) (string, tfdiags.Diagnostics) {
+		t.Helper()
+
+		dir := t.TempDir()
+		config := fmt.Sprintf("module \"child\" {\n  source = %q\n}\n", source)
+		if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		modulesDir := filepath.Join(dir, ".terraform/modules")
+		if err := os.MkdirAll(modulesDir, os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		loader, close := configload.NewLoaderForTests(t)
+		defer close()
+		inst := NewModuleInstaller(modulesDir, loader, nil)
+		inst.SetDependencyLocks(locks)
+		cfg, diags := inst.InstallModules(context.Background(), dir, "tests", upgrade, false, nil, configs.RootModuleCallForTesting())
+		if diags.HasErrors() {
+			return "", diags
+		}
+		return cfg.Children["child"].Module.Variables["v"].Description, diags
+	}
+
+	// The digest of the first installation is recorded.
+	first := push("first")
+	got, diags := install(t, false)
+	assertNoDiagnostics(t, diags)
+	if got != "first" {
+		t.Errorf("wrong module installed: %q", got)
+	}
+	if lock := locks.Module(source); lock == nil || lock.Digest() != first.Digest {
+		t.Fatalf("wrong lock %#v; want digest %s", lock, first.Digest)
+	}
+
+	// Moving the tag doesn't change what's installed, because the package
+	// is installed by the recorded digest.
+	second := push("second")
+	got, diags = install(t, false)
+	assertNoDiagnostics(t, diags)
+	if got != "first" {
+		t.Errorf("wrong module installed: %q", got)
+	}
+
+	// Upgrading installs and records the tag's new digest.
+	got, diags = install(t, true)
+	assertNoDiagnostics(t, diags)
+	if got != "second" {
+		t.Errorf("wrong module installed: %q", got)
+	}
+	if lock := locks.Module(source); lock == nil || lock.Digest() != second.Digest {
+		t.Fatalf("wrong lock %#v; want digest %s", lock, second.Digest)
+	}
+
+	// A package that doesn't have the recorded digest must be rejected.
+	locks.SetModule(source, "sha256:"+strings.Repeat("0", 64))
+	_, diags = install(t, false)
+	if !diags.HasErrors() {
+		t.Fatal("unexpected success installing package with the wrong digest")
+	}
+}
+
+// testModuleTarGz returns a gzip-compressed tar archive containing the given
+// files.
+func testModuleTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
 func TestModuleInstaller_fromTests(t *testing.T) {
 	fixtureDir := filepath.Clean("testdata/local-module-from-test")
 	dir, done := tempChdir(t, fixtureDir)
@@ -734,7 +1050,7 @@ func TestModuleInstaller_fromTests(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, nil)
-	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks)
+	_, diags := inst.InstallModules(context.Background(), ".", "tests", false, false, hooks, configs.RootModuleCallForTesting())
 	assertNoDiagnostics(t, diags)
 
 	wantCalls := []testInstallHookCall{
@@ -759,7 +1075,7 @@ func TestModuleInstaller_fromTests(t *testing.T) {
 
 	// Make sure the configuration is loadable now.
 	// (This ensures that correct information is recorded in the manifest.)
-	config, loadDiags := loader.LoadConfigWithTests(".", "tests")
+	config, loadDiags := loader.LoadConfigWithTests(".", "tests", configs.RootModuleCallForTesting())
 	assertNoDiagnostics(t, tfdiags.Diagnostics{}.Append(loadDiags))
 
 	if config.Module.Tests["tests/main.tftest.hcl"].Runs[0].ConfigUnderTest == nil {
@@ -791,7 +1107,7 @@ func TestLoadInstallModules_registryFromTest(t *testing.T) {
 	loader, close := configload.NewLoaderForTests(t)
 	defer close()
 	inst := NewModuleInstaller(modulesDir, loader, registry.NewClient(nil, nil))
-	_, diags := inst.InstallModules(context.Background(), dir, "tests", false, false, hooks)
+	_, diags := inst.InstallModules(context.Background(), dir, "tests", false, false, hooks, configs.RootModuleCallForTesting())
 	assertNoDiagnostics(t, diags)
 
 	v := version.Must(version.NewVersion("0.0.1"))
@@ -868,7 +1184,7 @@ func TestLoadInstallModules_registryFromTest(t *testing.T) {
 
 	// Make sure the configuration is loadable now.
 	// (This ensures that correct information is recorded in the manifest.)
-	config, loadDiags := loader.LoadConfigWithTests(".", "tests")
+	config, loadDiags := loader.LoadConfigWithTests(".", "tests", configs.RootModuleCallForTesting())
 	assertNoDiagnostics(t, tfdiags.Diagnostics{}.Append(loadDiags))
 
 	if config.Module.Tests["main.tftest.hcl"].Runs[0].ConfigUnderTest == nil {
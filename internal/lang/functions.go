@@ -186,12 +186,25 @@ func (s *Scope) Functions() map[string]function.Function {
 		for name, f := range s.funcs {
 			s.funcs[name] = funcs.WithDescription(name, f)
 		}
+
+		// Provider-defined functions are always pure, so they need no special
+		// handling for PureOnly, and they bring their own descriptions.
+		for name, f := range s.ProviderFunctions {
+			s.funcs[name] = f
+		}
 	}
 	s.funcsLock.Unlock()
 
 	return s.funcs
 }
 
+// ProviderFunctionName returns the name under which the function with the
+// given name, exported by the provider with the given module-local name, can
+// be called from expressions.
+func ProviderFunctionName(providerLocalName, functionName string) string {
+	return "provider::" + providerLocalName + "::" + functionName
+}
+
 // experimentalFunction checks whether the given experiment is enabled for
 // the recieving scope. If so, it will return the given function verbatim.
 // If not, it will return a placeholder function that just returns an
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
 
 	"github.com/opentofu/opentofu/internal/experiments"
 	"github.com/opentofu/opentofu/internal/lang/marks"
@@ -1310,6 +1312,41 @@ func TestFunctions(t *testing.T) {
 	}
 }
 
+func TestFunctions_providerFunctions(t *testing.T) {
+	upper := function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "input", Type: cty.String},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return cty.StringVal(strings.ToUpper(args[0].AsString())), nil
+		},
+	})
+
+	scope := &Scope{
+		ProviderFunctions: map[string]function.Function{
+			ProviderFunctionName("example", "upper"): upper,
+		},
+	}
+
+	expr, parseDiags := hclsyntax.ParseExpression([]byte(`provider::example::upper("hello")`), "test.tf", hcl.InitialPos)
+	if parseDiags.HasErrors() {
+		t.Fatal(parseDiags.Error())
+	}
+	got, diags := scope.EvalExpr(expr, cty.String)
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	if want := cty.StringVal("HELLO"); !want.RawEquals(got) {
+		t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	// Built-in functions must remain available alongside.
+	if _, ok := scope.Functions()["upper"]; !ok {
+		t.Fatal("built-in function missing")
+	}
+}
+
 const (
 	CipherBase64 = "eczGaDhXDbOFRZGhjx2etVzWbRqWDlmq0bvNt284JHVbwCgObiuyX9uV0LSAMY707IEgMkExJqXmsB4OWKxvB7epRB9G/3+F+pcrQpODlDuL9oDUAsa65zEpYF0Wbn7Oh7nrMQncyUPpyr9WUlALl0gRWytOA23S+y5joa4M34KFpawFgoqTu/2EEH4Xl1zo+0fy73fEto+nfkUY+meuyGZ1nUx/+DljP7ZqxHBFSlLODmtuTMdswUbHbXbWneW51D7Jm7xB8nSdiA2JQNK5+Sg5x8aNfgvFTt/m2w2+qpsyFa5Wjeu6fZmXSl840CA07aXbk9vN4I81WmJyblD/ZA=="
 	PrivateKey   = `
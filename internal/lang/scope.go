@@ -54,6 +54,12 @@ type Scope struct {
 	// then differ during apply.
 	PureOnly bool
 
+	// ProviderFunctions are the functions exported by the providers that are
+	// available in this scope, keyed by their namespaced names as returned by
+	// ProviderFunctionName. They are made available alongside the built-in
+	// functions.
+	ProviderFunctions map[string]function.Function
+
 	funcs     map[string]function.Function
 	funcsLock sync.Mutex
 
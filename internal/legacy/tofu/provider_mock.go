@@ -10,6 +10,7 @@ import (
 	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 
+	"fmt"
 	"github.com/opentofu/opentofu/internal/configs/hcl2shim"
 	"github.com/opentofu/opentofu/internal/providers"
 )
@@ -82,6 +83,11 @@ type MockProvider struct {
 	// ImportResourceStateResponse if set
 	ImportStateReturn []*InstanceState
 
+	MoveResourceStateCalled   bool
+	MoveResourceStateResponse providers.MoveResourceStateResponse
+	MoveResourceStateRequest  providers.MoveResourceStateRequest
+	MoveResourceStateFn       func(providers.MoveResourceStateRequest) providers.MoveResourceStateResponse
+
 	ReadDataSourceCalled   bool
 	ReadDataSourceResponse providers.ReadDataSourceResponse
 	ReadDataSourceRequest  providers.ReadDataSourceRequest
@@ -346,6 +352,19 @@ func (p *MockProvider) ImportResourceState(r providers.ImportResourceStateReques
 	return p.ImportResourceStateResponse
 }
 
+func (p *MockProvider) MoveResourceState(r providers.MoveResourceStateRequest) providers.MoveResourceStateResponse {
+	p.Lock()
+	defer p.Unlock()
+
+	p.MoveResourceStateCalled = true
+	p.MoveResourceStateRequest = r
+	if p.MoveResourceStateFn != nil {
+		return p.MoveResourceStateFn(r)
+	}
+
+	return p.MoveResourceStateResponse
+}
+
 func (p *MockProvider) ReadDataSource(r providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
 	p.Lock()
 	defer p.Unlock()
@@ -360,6 +379,15 @@ func (p *MockProvider) ReadDataSource(r providers.ReadDataSourceRequest) provide
 	return p.ReadDataSourceResponse
 }
 
+func (p *MockProvider) GetFunctions() providers.GetFunctionsResponse {
+	return providers.GetFunctionsResponse{}
+}
+
+func (p *MockProvider) CallFunction(r providers.CallFunctionRequest) (resp providers.CallFunctionResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported function %q", r.Name))
+	return resp
+}
+
 func (p *MockProvider) Close() error {
 	p.CloseCalled = true
 	return p.CloseError
@@ -2,6 +2,7 @@ package moduletest
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 
@@ -23,6 +24,10 @@ type Run struct {
 	Index  int
 	Status Status
 
+	// Duration is how long the run block took to execute, including any
+	// time spent planning and applying.
+	Duration time.Duration
+
 	Diagnostics tfdiags.Diagnostics
 }
 
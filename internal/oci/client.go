@@ -0,0 +1,448 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/opentofu/opentofu/internal/httpclient"
+)
+
+// maxManifestSize is the largest manifest we'll accept, which is the limit
+// that registries are encouraged to enforce by the distribution
+// specification.
+const maxManifestSize = 4 * 1024 * 1024
+
+// manifestMediaTypes are the media types we accept for manifests.
+var manifestMediaTypes = []string{
+	MediaTypeImageManifest,
+	MediaTypeImageIndex,
+	MediaTypeDockerManifest,
+	MediaTypeDockerManifestList,
+}
+
+// ErrNotFound is returned when a registry reports that a repository,
+// manifest or blob doesn't exist.
+var ErrNotFound = errors.New("not found")
+
+// Client is a client for registries implementing the OCI distribution
+// specification.
+//
+// A Client is safe for concurrent use, and remembers the access tokens it
+// obtains from registries' token services, so it should be reused for
+// requests to the same registries.
+type Client struct {
+	httpClient *http.Client
+	creds      CredentialsSource
+
+	mu     sync.Mutex
+	tokens map[string]string // keyed by registry and scope
+}
+
+// NewClient returns a client that uses the given credentials, which may be
+// nil if registries should only be accessed anonymously.
+func NewClient(creds CredentialsSource) *Client {
+	return &Client{
+		httpClient: httpclient.New(),
+		creds:      creds,
+		tokens:     make(map[string]string),
+	}
+}
+
+// GetManifest retrieves the manifest that the given reference refers to,
+// returning it along with a descriptor for the manifest itself.
+//
+// If the reference has a digest then the manifest's content is verified
+// against it. Otherwise the content is verified against the digest reported
+// by the registry, if any, and the returned descriptor has the manifest's
+// actual digest, which can be used to retrieve the same manifest again.
+func (c *Client) GetManifest(ctx context.Context, ref Reference) (*Manifest, Descriptor, error) {
+	identifier := ref.Digest
+	if identifier == "" {
+		identifier = ref.Tag
+	}
+	if identifier == "" {
+		return nil, Descriptor{}, fmt.Errorf("reference %s must have a tag or a digest", ref)
+	}
+
+	resp, err := c.get(ctx, ref, "manifests/"+identifier, strings.Join(manifestMediaTypes, ", "))
+	if err != nil {
+		return nil, Descriptor{}, err
+	}
+	defer resp.Body.Close()
+
+	src, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, Descriptor{}, fmt.Errorf("failed to read manifest %s: %w", ref, err)
+	}
+	if len(src) > maxManifestSize {
+		return nil, Descriptor{}, fmt.Errorf("manifest %s is too large", ref)
+	}
+	sum := sha256.Sum256(src)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	if ref.Digest != "" && digest != ref.Digest {
+		return nil, Descriptor{}, fmt.Errorf("manifest %s has the wrong digest %s", ref, digest)
+	}
+	if reported := resp.Header.Get("Docker-Content-Digest"); strings.HasPrefix(reported, "sha256:") && reported != digest {
+		return nil, Descriptor{}, fmt.Errorf("manifest %s has digest %s, but the registry reported %s", ref, digest, reported)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(src, &manifest); err != nil {
+		return nil, Descriptor{}, fmt.Errorf("invalid manifest %s: %w", ref, err)
+	}
+	if manifest.SchemaVersion != 2 {
+		return nil, Descriptor{}, fmt.Errorf("manifest %s has unsupported schema version %d", ref, manifest.SchemaVersion)
+	}
+	mediaType := manifest.MediaType
+	if ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil && mediaType == "" {
+		mediaType = ct
+		manifest.MediaType = ct
+	}
+
+	return &manifest, Descriptor{
+		MediaType: mediaType,
+		Digest:    digest,
+		Size:      int64(len(src)),
+	}, nil
+}
+
+// FetchBlob writes the content of the blob with the given descriptor from
+// the repository of the given reference to the given writer, verifying that
+// the content matches the descriptor's size and digest.
+//
+// If the verification fails then some or all of the content might already
+// have been written before the error is returned, so callers must discard
+// it.
+func (c *Client) FetchBlob(ctx context.Context, ref Reference, desc Descriptor, w io.Writer) error {
+	if !ValidDigest(desc.Digest) {
+		return fmt.Errorf("unsupported digest %q", desc.Digest)
+	}
+	resp, err := c.get(ctx, ref, "blobs/"+desc.Digest, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, h), io.LimitReader(resp.Body, desc.Size+1))
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to download blob %s: %w", desc.Digest, err)
+	}
+	if n != desc.Size {
+		return fmt.Errorf("blob %s has the wrong size: expected %d bytes, but got %d bytes", desc.Digest, desc.Size, n)
+	}
+	if got := "sha256:" + hex.EncodeToString(h.Sum(nil)); got != desc.Digest {
+		return fmt.Errorf("blob %s has the wrong digest %s", desc.Digest, got)
+	}
+	return nil
+}
+
+// ListTags returns all of the tags in the repository of the given reference.
+func (c *Client) ListTags(ctx context.Context, ref Reference) ([]string, error) {
+	var tags []string
+	next := "tags/list"
+	for next != "" {
+		resp, err := c.get(ctx, ref, next, "application/json")
+		if err != nil {
+			return nil, err
+		}
+		var body struct {
+			Tags []string `json:"tags"`
+		}
+		err = json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag list for %s: %w", ref.WithTag(""), err)
+		}
+		tags = append(tags, body.Tags...)
+
+		// Registries may paginate the tags, giving the URL of the next page
+		// in a Link header.
+		next, err = nextPage(resp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag list for %s: %w", ref.WithTag(""), err)
+		}
+	}
+	return tags, nil
+}
+
+// nextPage returns the path of the next page of a paginated response,
+// relative to the repository's base URL, or an empty string if there are no
+// more pages.
+func nextPage(resp *http.Response) (string, error) {
+	link := resp.Header.Get("Link")
+	if link == "" {
+		return "", nil
+	}
+	target, params, ok := strings.Cut(link, ";")
+	if !ok || !strings.Contains(params, `rel="next"`) {
+		return "", nil
+	}
+	target = strings.Trim(strings.TrimSpace(target), "<>")
+	u, err := resp.Request.URL.Parse(target)
+	if err != nil {
+		return "", err
+	}
+	// The link is an absolute path, so we make it relative to the
+	// repository's base URL again.
+	prefix := resp.Request.URL.Path
+	prefix = prefix[:strings.LastIndex(prefix, "/tags/")+1]
+	rel, ok := strings.CutPrefix(u.Path, prefix)
+	if !ok {
+		return "", fmt.Errorf("next page link %q is outside of the repository", target)
+	}
+	if u.RawQuery != "" {
+		rel += "?" + u.RawQuery
+	}
+	return rel, nil
+}
+
+// get makes a GET request for the given path relative to the base URL of
+// the repository of the given reference, authenticating if the registry
+// requires it. The response body must be closed if the error is nil.
+func (c *Client) get(ctx context.Context, ref Reference, path, accept string) (*http.Response, error) {
+	u := &url.URL{
+		Scheme: registryScheme(ref.Registry),
+		Host:   ref.Registry,
+		Path:   "/v2/" + ref.Repository + "/",
+	}
+	target, err := u.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	scope := "repository:" + ref.Repository + ":pull"
+
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		return req, nil
+	}
+
+	req, err := newRequest()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	token := c.tokens[ref.Registry+" "+scope]
+	c.mu.Unlock()
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, c.requestError(ctx, ref, err)
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		req, err = newRequest()
+		if err != nil {
+			return nil, err
+		}
+		if err := c.authenticate(ctx, req, ref.Registry, scope, challenge); err != nil {
+			return nil, err
+		}
+		resp, err = c.httpClient.Do(req)
+		if err != nil {
+			return nil, c.requestError(ctx, ref, err)
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s in %s: %w", strings.TrimSuffix(path, "/list"), ref.WithTag(""), ErrNotFound)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		resp.Body.Close()
+		return nil, fmt.Errorf("registry %s denied access to %s", ref.Registry, ref.WithTag(""))
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("unsuccessful request to %s: %s", target.Redacted(), resp.Status)
+	}
+}
+
+func (c *Client) requestError(ctx context.Context, ref Reference, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("failed to request %s: %w", ref, err)
+}
+
+// authenticate adds an Authorization header to the given request in response
+// to the given challenge from the registry, as described in the
+// WWW-Authenticate header of an earlier response.
+func (c *Client) authenticate(ctx context.Context, req *http.Request, registry, scope, challenge string) error {
+	var creds *Credentials
+	if c.creds != nil {
+		var err error
+		creds, err = c.creds.ForRegistry(registry)
+		if err != nil {
+			return fmt.Errorf("failed to get credentials for %s: %w", registry, err)
+		}
+	}
+
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if creds == nil || creds.Username == "" {
+			return fmt.Errorf("registry %s requires credentials", registry)
+		}
+		req.SetBasicAuth(creds.Username, creds.Password)
+		return nil
+	case "bearer":
+		token, err := c.fetchToken(ctx, params, scope, creds)
+		if err != nil {
+			return fmt.Errorf("failed to authenticate to %s: %w", registry, err)
+		}
+		c.mu.Lock()
+		c.tokens[registry+" "+scope] = token
+		c.mu.Unlock()
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	default:
+		return fmt.Errorf("registry %s requested unsupported authentication scheme %q", registry, scheme)
+	}
+}
+
+// fetchToken obtains an access token from the token service described by
+// the given challenge parameters, as described in the Docker registry token
+// authentication specification.
+func (c *Client) fetchToken(ctx context.Context, params map[string]string, scope string, creds *Credentials) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || (realm.Scheme != "https" && realm.Scheme != "http") {
+		return "", fmt.Errorf("invalid token service realm %q", params["realm"])
+	}
+	if realm.Scheme == "http" && registryScheme(realm.Host) != "http" {
+		// We don't want to send credentials in cleartext to a token
+		// service on the network.
+		return "", fmt.Errorf("token service %s must use https", realm.Redacted())
+	}
+	if s := params["scope"]; s != "" {
+		scope = s
+	}
+
+	var req *http.Request
+	if creds != nil && creds.IdentityToken != "" {
+		form := url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {creds.IdentityToken},
+			"service":       {params["service"]},
+			"scope":         {scope},
+			"client_id":     {"opentofu"},
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, realm.String(), strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		query := realm.Query()
+		if service := params["service"]; service != "" {
+			query.Set("service", service)
+		}
+		query.Set("scope", scope)
+		realm.RawQuery = query.Encode()
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		if creds != nil && creds.Username != "" {
+			req.SetBasicAuth(creds.Username, creds.Password)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token service returned %s", resp.Status)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid response from token service: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	log.Printf("[WARN] Token service %s returned no token", realm.Redacted())
+	return "", fmt.Errorf("token service returned no token")
+}
+
+// parseChallenge parses the value of a WWW-Authenticate header, returning
+// the authentication scheme in lowercase and its parameters.
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := make(map[string]string)
+	for rest != "" {
+		rest = strings.TrimLeft(rest, " ,")
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				params[key] = value[1:]
+				break
+			}
+			params[key] = value[1 : end+1]
+			rest = value[end+2:]
+		} else {
+			value, rest, _ = strings.Cut(value, ",")
+			params[key] = strings.TrimSpace(value)
+		}
+	}
+	return strings.ToLower(scheme), params
+}
+
+// registryScheme returns the URL scheme to use for the registry with the
+// given hostname. As with other OCI tools, registries on the loopback
+// interface are accessed over plain HTTP and all others over HTTPS.
+func registryScheme(registry string) string {
+	host := registry
+	if h, _, err := net.SplitHostPort(registry); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return "http"
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return "http"
+	}
+	return "https"
+}
@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type staticCredentials map[string]*Credentials
+
+func (c staticCredentials) ForRegistry(registry string) (*Credentials, error) {
+	return c[registry], nil
+}
+
+func TestClient(t *testing.T) {
+	registry := NewTestRegistry(t)
+	layer := registry.PushBlob("application/octet-stream", []byte("hello"))
+	desc := registry.PushManifest("example/thing", "v1", &Manifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageManifest,
+		Layers:        []Descriptor{layer},
+	})
+	ctx := context.Background()
+	client := NewClient(nil)
+	ref := Reference{Registry: registry.Host(), Repository: "example/thing", Tag: "v1"}
+
+	manifest, gotDesc, err := client.GetManifest(ctx, ref)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(desc, gotDesc); diff != "" {
+		t.Errorf("wrong descriptor\n%s", diff)
+	}
+	if len(manifest.Layers) != 1 || manifest.IsIndex() {
+		t.Fatalf("wrong manifest: %#v", manifest)
+	}
+
+	// The same manifest can be retrieved by its digest.
+	if _, _, err := client.GetManifest(ctx, ref.WithDigest(desc.Digest)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := client.FetchBlob(ctx, ref, manifest.Layers[0], &buf); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := buf.String(), "hello"; got != want {
+		t.Errorf("wrong blob content %q; want %q", got, want)
+	}
+
+	_, _, err = client.GetManifest(ctx, ref.WithTag("v2"))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("wrong error for missing tag: %v", err)
+	}
+}
+
+func TestClient_corrupt(t *testing.T) {
+	registry := NewTestRegistry(t)
+	layer := registry.PushBlob("application/octet-stream", []byte("hello"))
+	registry.CorruptBlob(layer.Digest)
+	desc := registry.PushManifest("example/thing", "v1", &Manifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageManifest,
+		Layers:        []Descriptor{layer},
+	})
+	ctx := context.Background()
+	client := NewClient(nil)
+	ref := Reference{Registry: registry.Host(), Repository: "example/thing"}
+
+	err := client.FetchBlob(ctx, ref, layer, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "has the wrong digest") {
+		t.Errorf("wrong error for corrupt blob: %v", err)
+	}
+
+	// A manifest whose content doesn't match the requested digest is
+	// rejected too.
+	registry.PushRawManifest("example/thing", desc.Digest, MediaTypeImageManifest, []byte(`{"schemaVersion":2}`))
+	_, _, err = client.GetManifest(ctx, ref.WithDigest(desc.Digest))
+	if err == nil || !strings.Contains(err.Error(), "has the wrong digest") {
+		t.Errorf("wrong error for corrupt manifest: %v", err)
+	}
+}
+
+func TestClient_auth(t *testing.T) {
+	registry := NewTestRegistry(t)
+	registry.RequireAuth("user", "pass")
+	registry.PushManifest("example/thing", "v1", &Manifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageManifest,
+	})
+	ctx := context.Background()
+	ref := Reference{Registry: registry.Host(), Repository: "example/thing", Tag: "v1"}
+
+	_, _, err := NewClient(nil).GetManifest(ctx, ref)
+	if err == nil {
+		t.Fatal("unexpected success without credentials")
+	}
+
+	client := NewClient(staticCredentials{
+		registry.Host(): {Username: "user", Password: "pass"},
+	})
+	if _, _, err := client.GetManifest(ctx, ref); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// The second request reuses the token from the first.
+	if _, err := client.ListTags(ctx, ref); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestClient_ListTags(t *testing.T) {
+	registry := NewTestRegistry(t)
+	registry.SetPageSize(2)
+	for _, tag := range []string{"v1", "v2", "v3", "v4", "v5"} {
+		registry.PushManifest("example/thing", tag, &Manifest{
+			SchemaVersion: 2,
+			MediaType:     MediaTypeImageManifest,
+			Annotations:   map[string]string{"tag": tag},
+		})
+	}
+	ref := Reference{Registry: registry.Host(), Repository: "example/thing"}
+
+	got, err := NewClient(nil).ListTags(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []string{"v1", "v2", "v3", "v4", "v5"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong tags\n%s", diff)
+	}
+}
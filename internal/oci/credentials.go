@@ -0,0 +1,186 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package oci
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Credentials are the credentials to use to authenticate to a registry.
+type Credentials struct {
+	Username string
+	Password string
+
+	// IdentityToken, if set, is an OAuth2 refresh token to use to obtain
+	// access tokens from the registry's token service, instead of the
+	// username and password.
+	IdentityToken string
+}
+
+// CredentialsSource provides the credentials for registries.
+type CredentialsSource interface {
+	// ForRegistry returns the credentials for the registry with the given
+	// hostname, or nil if there are none.
+	ForRegistry(registry string) (*Credentials, error)
+}
+
+// DockerCredentials is a CredentialsSource using the credentials in a
+// Docker-style configuration file, as created by "docker login" and similar
+// tools, including the credential helpers it refers to.
+type DockerCredentials struct {
+	auths       map[string]dockerAuth
+	credsStore  string
+	credHelpers map[string]string
+}
+
+var _ CredentialsSource = (*DockerCredentials)(nil)
+
+type dockerConfig struct {
+	Auths       map[string]dockerAuth `json:"auths"`
+	CredsStore  string                `json:"credsStore"`
+	CredHelpers map[string]string     `json:"credHelpers"`
+}
+
+type dockerAuth struct {
+	Auth          string `json:"auth"`
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	IdentityToken string `json:"identitytoken"`
+}
+
+// DefaultDockerConfigFile returns the path of the Docker configuration file
+// that Docker itself would use, which is in the directory given in the
+// DOCKER_CONFIG environment variable or otherwise in the .docker directory
+// in the user's home directory.
+func DefaultDockerConfigFile() (string, error) {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".docker", "config.json"), nil
+}
+
+// LoadDockerCredentials reads the Docker-style configuration file at the
+// given path. A file that doesn't exist is treated as having no credentials.
+func LoadDockerCredentials(path string) (*DockerCredentials, error) {
+	ret := &DockerCredentials{}
+	src, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ret, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var config dockerConfig
+	if err := json.Unmarshal(src, &config); err != nil {
+		return nil, fmt.Errorf("invalid Docker configuration file %s: %w", path, err)
+	}
+	// The keys in "auths" may be URLs rather than hostnames, for historical
+	// reasons.
+	ret.auths = make(map[string]dockerAuth, len(config.Auths))
+	for key, auth := range config.Auths {
+		ret.auths[normalizeDockerRegistry(key)] = auth
+	}
+	ret.credsStore = config.CredsStore
+	ret.credHelpers = config.CredHelpers
+	return ret, nil
+}
+
+// DefaultCredentials returns a CredentialsSource using the credentials in
+// the default Docker configuration file.
+func DefaultCredentials() (CredentialsSource, error) {
+	path, err := DefaultDockerConfigFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Docker configuration file: %w", err)
+	}
+	return LoadDockerCredentials(path)
+}
+
+// ForRegistry implements CredentialsSource.
+func (c *DockerCredentials) ForRegistry(registry string) (*Credentials, error) {
+	// As with Docker itself, a credential helper specific to a registry
+	// takes priority, then the credentials stored directly in the file and
+	// finally the default credentials store.
+	if helper, ok := c.credHelpers[registry]; ok {
+		return credentialsFromHelper(helper, registry)
+	}
+	if auth, ok := c.auths[registry]; ok {
+		creds := &Credentials{
+			Username:      auth.Username,
+			Password:      auth.Password,
+			IdentityToken: auth.IdentityToken,
+		}
+		if auth.Auth != "" {
+			raw, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid credentials for %s in Docker configuration file: %w", registry, err)
+			}
+			username, password, ok := strings.Cut(string(raw), ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid credentials for %s in Docker configuration file: must be a username and password separated by a colon", registry)
+			}
+			creds.Username = username
+			creds.Password = password
+		}
+		if creds.Username != "" || creds.Password != "" || creds.IdentityToken != "" {
+			return creds, nil
+		}
+	}
+	if c.credsStore != "" {
+		return credentialsFromHelper(c.credsStore, registry)
+	}
+	return nil, nil
+}
+
+// credentialsFromHelper runs the Docker credential helper program with the
+// given name to get the credentials for the given registry.
+func credentialsFromHelper(helper, registry string) (*Credentials, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(registry)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// Helpers report that they have no credentials for a registry
+		// with this message on stdout.
+		if strings.Contains(stdout.String(), "credentials not found") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("credential helper %q failed: %w\n%s", helper, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var result struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return nil, fmt.Errorf("credential helper %q returned invalid response: %w", helper, err)
+	}
+	// Helpers indicate an identity token with this special username.
+	if result.Username == "<token>" {
+		return &Credentials{IdentityToken: result.Secret}, nil
+	}
+	return &Credentials{
+		Username: result.Username,
+		Password: result.Secret,
+	}, nil
+}
+
+func normalizeDockerRegistry(key string) string {
+	key = strings.TrimPrefix(key, "https://")
+	key = strings.TrimPrefix(key, "http://")
+	key, _, _ = strings.Cut(key, "/")
+	return key
+}
@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package oci
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadDockerCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(path, []byte(`{
+		"auths": {
+			"https://example.com/v1/": {"auth": "dXNlcjpwYXNz"},
+			"example.net": {"identitytoken": "refresh"}
+		}
+	}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	creds, err := LoadDockerCredentials(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got, err := creds.ForRegistry("example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got == nil || got.Username != "user" || got.Password != "pass" {
+		t.Errorf("wrong credentials for example.com: %#v", got)
+	}
+
+	got, err = creds.ForRegistry("example.net")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got == nil || got.IdentityToken != "refresh" {
+		t.Errorf("wrong credentials for example.net: %#v", got)
+	}
+
+	got, err = creds.ForRegistry("example.org")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != nil {
+		t.Errorf("unexpected credentials for example.org: %#v", got)
+	}
+}
+
+func TestLoadDockerCredentials_missing(t *testing.T) {
+	creds, err := LoadDockerCredentials(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got, err := creds.ForRegistry("example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != nil {
+		t.Errorf("unexpected credentials: %#v", got)
+	}
+}
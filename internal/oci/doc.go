@@ -0,0 +1,12 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package oci is a small client for registries implementing the OCI
+// distribution specification, which OpenTofu can use as a source for module
+// packages and provider packages stored as OCI artifacts.
+//
+// This package only implements the subset of the specification that
+// OpenTofu needs to retrieve artifacts: resolving manifests, listing tags and
+// downloading blobs, with verification of their digests. It does not
+// support pushing artifacts.
+package oci
@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package oci
+
+// Media types of the manifests that we can retrieve.
+const (
+	MediaTypeImageManifest = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeImageIndex    = "application/vnd.oci.image.index.v1+json"
+
+	// The equivalent Docker media types are accepted too, because some
+	// registries convert OCI manifests into them.
+	MediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+)
+
+// Descriptor describes content stored in a registry, as defined by the OCI
+// image specification.
+type Descriptor struct {
+	MediaType    string            `json:"mediaType"`
+	Digest       string            `json:"digest"`
+	Size         int64             `json:"size"`
+	ArtifactType string            `json:"artifactType,omitempty"`
+	Platform     *Platform         `json:"platform,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
+// Platform describes the platform that the content of an image index entry
+// is for.
+type Platform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// Manifest is either an image manifest or an image index, as defined by the
+// OCI image specification. An image manifest has Layers, while an image
+// index has Manifests.
+type Manifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType,omitempty"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Config        *Descriptor       `json:"config,omitempty"`
+	Layers        []Descriptor      `json:"layers,omitempty"`
+	Manifests     []Descriptor      `json:"manifests,omitempty"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// IsIndex returns true if the manifest is an image index.
+func (m *Manifest) IsIndex() bool {
+	switch m.MediaType {
+	case MediaTypeImageIndex, MediaTypeDockerManifestList:
+		return true
+	case MediaTypeImageManifest, MediaTypeDockerManifest:
+		return false
+	default:
+		// The media type is optional in image manifests and image indexes,
+		// so we fall back on what they contain.
+		return len(m.Manifests) > 0 && len(m.Layers) == 0
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package oci
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// repositoryPattern matches a repository name, as defined by the
+	// distribution specification.
+	repositoryPattern = regexp.MustCompile(`^[a-z0-9]+(?:(?:\.|_|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:\.|_|__|-+)[a-z0-9]+)*)*$`)
+
+	// tagPattern matches a tag name, as defined by the distribution
+	// specification.
+	tagPattern = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+
+	// digestPattern matches the digests we support, which are only those
+	// using the SHA-256 algorithm that registries are required to support.
+	digestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+// Reference identifies an artifact in a registry, either by tag or by
+// digest.
+type Reference struct {
+	// Registry is the hostname of the registry, optionally with a port
+	// number.
+	Registry string
+
+	// Repository is the name of the repository within the registry.
+	Repository string
+
+	// Tag and Digest identify the artifact within the repository. At most
+	// one of them is set, and if neither is set then the reference is to
+	// a whole repository, rather than to a particular artifact.
+	Tag    string
+	Digest string
+}
+
+// ParseReference parses a reference of the form
+// registry/repository:tag or registry/repository@digest, or just
+// registry/repository to refer to a whole repository.
+func ParseReference(raw string) (Reference, error) {
+	var ret Reference
+
+	registry, rest, ok := strings.Cut(raw, "/")
+	if !ok || registry == "" {
+		return ret, fmt.Errorf("reference %q must start with a registry hostname", raw)
+	}
+	if strings.ContainsAny(registry, "@/") || strings.ToLower(registry) != registry {
+		return ret, fmt.Errorf("invalid registry hostname %q", registry)
+	}
+	ret.Registry = registry
+
+	if repo, digest, ok := strings.Cut(rest, "@"); ok {
+		if !digestPattern.MatchString(digest) {
+			return ret, fmt.Errorf("invalid digest %q: must be a sha256 digest", digest)
+		}
+		rest = repo
+		ret.Digest = digest
+	} else if i := strings.LastIndex(rest, ":"); i >= 0 {
+		tag := rest[i+1:]
+		if !tagPattern.MatchString(tag) {
+			return ret, fmt.Errorf("invalid tag %q", tag)
+		}
+		rest = rest[:i]
+		ret.Tag = tag
+	}
+
+	if !repositoryPattern.MatchString(rest) {
+		return ret, fmt.Errorf("invalid repository name %q", rest)
+	}
+	ret.Repository = rest
+	return ret, nil
+}
+
+// String returns the reference in the syntax accepted by ParseReference.
+func (r Reference) String() string {
+	ret := r.Registry + "/" + r.Repository
+	switch {
+	case r.Digest != "":
+		ret += "@" + r.Digest
+	case r.Tag != "":
+		ret += ":" + r.Tag
+	}
+	return ret
+}
+
+// WithTag returns a reference to the artifact with the given tag in the same
+// repository.
+func (r Reference) WithTag(tag string) Reference {
+	return Reference{
+		Registry:   r.Registry,
+		Repository: r.Repository,
+		Tag:        tag,
+	}
+}
+
+// WithDigest returns a reference to the artifact with the given digest in the
+// same repository.
+func (r Reference) WithDigest(digest string) Reference {
+	return Reference{
+		Registry:   r.Registry,
+		Repository: r.Repository,
+		Digest:     digest,
+	}
+}
+
+// ValidDigest returns true if the given string is a digest of a kind that
+// this package supports.
+func ValidDigest(digest string) bool {
+	return digestPattern.MatchString(digest)
+}
@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package oci
+
+import (
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	digest := "sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	tests := map[string]struct {
+		want    Reference
+		wantErr string
+	}{
+		"example.com/foo": {
+			want: Reference{Registry: "example.com", Repository: "foo"},
+		},
+		"example.com:5000/foo/bar:v1.0.0": {
+			want: Reference{Registry: "example.com:5000", Repository: "foo/bar", Tag: "v1.0.0"},
+		},
+		"example.com/foo@" + digest: {
+			want: Reference{Registry: "example.com", Repository: "foo", Digest: digest},
+		},
+		"foo": {
+			wantErr: `reference "foo" must start with a registry hostname`,
+		},
+		"Example.com/foo": {
+			wantErr: `invalid registry hostname "Example.com"`,
+		},
+		"example.com/Foo": {
+			wantErr: `invalid repository name "Foo"`,
+		},
+		"example.com/foo:-bad": {
+			wantErr: `invalid tag "-bad"`,
+		},
+		"example.com/foo@md5:abc": {
+			wantErr: `invalid digest "md5:abc": must be a sha256 digest`,
+		},
+	}
+
+	for raw, test := range tests {
+		t.Run(raw, func(t *testing.T) {
+			got, err := ParseReference(raw)
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success; want error: %s", test.wantErr)
+				}
+				if err.Error() != test.wantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.want {
+				t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, test.want)
+			}
+			if got.String() != raw {
+				t.Fatalf("wrong string\ngot:  %s\nwant: %s", got.String(), raw)
+			}
+		})
+	}
+}
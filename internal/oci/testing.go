@@ -0,0 +1,239 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestRegistry is an in-memory registry implementing the parts of the
+// distribution specification that Client uses, for use in tests.
+type TestRegistry struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string]map[string]testManifest // repository, then tag or digest
+	username  string
+	password  string
+	pageSize  int
+}
+
+type testManifest struct {
+	mediaType string
+	content   []byte
+}
+
+const testRegistryToken = "test-registry-token"
+
+// NewTestRegistry starts a new empty registry, which is shut down when the
+// given test completes.
+func NewTestRegistry(t *testing.T) *TestRegistry {
+	t.Helper()
+	r := &TestRegistry{
+		blobs:     make(map[string][]byte),
+		manifests: make(map[string]map[string]testManifest),
+	}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+// Host returns the hostname and port of the registry, which is always on
+// the loopback interface and so is accessed by Client over plain HTTP.
+func (r *TestRegistry) Host() string {
+	u, _ := url.Parse(r.server.URL)
+	return u.Host
+}
+
+// RequireAuth makes the registry require a bearer token for all requests,
+// which its token service issues in return for the given username and
+// password.
+func (r *TestRegistry) RequireAuth(username, password string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.username = username
+	r.password = password
+}
+
+// SetPageSize makes the registry paginate tag lists with the given number
+// of tags on each page.
+func (r *TestRegistry) SetPageSize(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pageSize = n
+}
+
+// PushBlob stores the given content as a blob, returning a descriptor for
+// it with the given media type.
+func (r *TestRegistry) PushBlob(mediaType string, content []byte) Descriptor {
+	digest := testDigest(content)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.blobs[digest] = content
+	return Descriptor{
+		MediaType: mediaType,
+		Digest:    digest,
+		Size:      int64(len(content)),
+	}
+}
+
+// PushManifest stores the given manifest in the given repository, with the
+// given tag if it isn't empty, returning a descriptor for the manifest.
+func (r *TestRegistry) PushManifest(repository, tag string, manifest *Manifest) Descriptor {
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		panic(err)
+	}
+	return r.PushRawManifest(repository, tag, manifest.MediaType, content)
+}
+
+// PushRawManifest is like PushManifest but stores the given content without
+// any validation, to allow testing invalid manifests.
+func (r *TestRegistry) PushRawManifest(repository, tag, mediaType string, content []byte) Descriptor {
+	digest := testDigest(content)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.manifests[repository] == nil {
+		r.manifests[repository] = make(map[string]testManifest)
+	}
+	m := testManifest{mediaType: mediaType, content: content}
+	r.manifests[repository][digest] = m
+	if tag != "" {
+		r.manifests[repository][tag] = m
+	}
+	return Descriptor{
+		MediaType: mediaType,
+		Digest:    digest,
+		Size:      int64(len(content)),
+	}
+}
+
+// CorruptBlob replaces the content of the blob with the given digest, so
+// that it no longer matches its digest.
+func (r *TestRegistry) CorruptBlob(digest string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	content := append([]byte(nil), r.blobs[digest]...)
+	if len(content) > 0 {
+		content[len(content)-1] ^= 0xff
+	}
+	r.blobs[digest] = content
+}
+
+func (r *TestRegistry) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if req.URL.Path == "/token" {
+		username, password, ok := req.BasicAuth()
+		if !ok || username != r.username || password != r.password {
+			http.Error(w, "invalid credentials", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"token":%q}`, testRegistryToken)
+		return
+	}
+
+	path, ok := strings.CutPrefix(req.URL.Path, "/v2/")
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	var repository, kind, identifier string
+	switch {
+	case strings.HasSuffix(path, "/tags/list"):
+		repository, kind = strings.TrimSuffix(path, "/tags/list"), "tags"
+	case strings.Contains(path, "/manifests/"):
+		i := strings.LastIndex(path, "/manifests/")
+		repository, kind, identifier = path[:i], "manifests", path[i+len("/manifests/"):]
+	case strings.Contains(path, "/blobs/"):
+		i := strings.LastIndex(path, "/blobs/")
+		repository, kind, identifier = path[:i], "blobs", path[i+len("/blobs/"):]
+	default:
+		http.NotFound(w, req)
+		return
+	}
+
+	if r.username != "" && req.Header.Get("Authorization") != "Bearer "+testRegistryToken {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+			`Bearer realm="%s/token",service="test",scope="repository:%s:pull"`,
+			r.server.URL, repository,
+		))
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch kind {
+	case "tags":
+		manifests, ok := r.manifests[repository]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		var tags []string
+		for key := range manifests {
+			if !ValidDigest(key) {
+				tags = append(tags, key)
+			}
+		}
+		sort.Strings(tags)
+		if last := req.URL.Query().Get("last"); last != "" {
+			i := sort.SearchStrings(tags, last)
+			if i < len(tags) && tags[i] == last {
+				i++
+			}
+			tags = tags[i:]
+		}
+		n := r.pageSize
+		if s := req.URL.Query().Get("n"); s != "" {
+			n, _ = strconv.Atoi(s)
+		}
+		if n > 0 && len(tags) > n {
+			tags = tags[:n]
+			w.Header().Set("Link", fmt.Sprintf(`</v2/%s/tags/list?last=%s&n=%d>; rel="next"`, repository, url.QueryEscape(tags[n-1]), n))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name": repository,
+			"tags": tags,
+		})
+	case "manifests":
+		m, ok := r.manifests[repository][identifier]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		if m.mediaType != "" {
+			w.Header().Set("Content-Type", m.mediaType)
+		}
+		w.Header().Set("Docker-Content-Digest", testDigest(m.content))
+		w.Write(m.content)
+	case "blobs":
+		content, ok := r.blobs[identifier]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(content)
+	}
+}
+
+func testDigest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -13,6 +13,7 @@ const (
 	DeleteThenCreate Action = '∓'
 	CreateThenDelete Action = '±'
 	Delete           Action = '-'
+	Forget           Action = '.'
 )
 
 //go:generate go run golang.org/x/tools/cmd/stringer -type Action
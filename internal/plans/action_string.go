@@ -15,26 +15,32 @@ func _() {
 	_ = x[DeleteThenCreate-8723]
 	_ = x[CreateThenDelete-177]
 	_ = x[Delete-45]
+	_ = x[Forget-46]
 }
 
 const (
 	_Action_name_0 = "NoOp"
 	_Action_name_1 = "Create"
-	_Action_name_2 = "Delete"
+	_Action_name_2 = "DeleteForget"
 	_Action_name_3 = "Update"
 	_Action_name_4 = "CreateThenDelete"
 	_Action_name_5 = "Read"
 	_Action_name_6 = "DeleteThenCreate"
 )
 
+var (
+	_Action_index_2 = [...]uint8{0, 6, 12}
+)
+
 func (i Action) String() string {
 	switch {
 	case i == 0:
 		return _Action_name_0
 	case i == 43:
 		return _Action_name_1
-	case i == 45:
-		return _Action_name_2
+	case 45 <= i && i <= 46:
+		i -= 45
+		return _Action_name_2[_Action_index_2[i]:_Action_index_2[i+1]]
 	case i == 126:
 		return _Action_name_3
 	case i == 177:
@@ -0,0 +1,43 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plans
+
+import (
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+// DeferredReason describes why the planning of an object was deferred to a
+// later plan/apply round.
+type DeferredReason string
+
+const (
+	// DeferredReasonInstanceCountUnknown means that the count or for_each
+	// argument of the object couldn't be evaluated until after apply, so
+	// OpenTofu can't yet know which instances it has.
+	DeferredReasonInstanceCountUnknown DeferredReason = "instance_count_unknown"
+
+	// DeferredReasonDeferredPrereq means that the object depends on another
+	// object whose planning was deferred.
+	DeferredReasonDeferredPrereq DeferredReason = "deferred_prereq"
+)
+
+// DeferredChange records a resource or module call whose changes were left
+// out of a plan created with deferral allowed, because they can't be planned
+// until some other changes have been applied.
+type DeferredChange struct {
+	// Addr is the address of the deferred object. It is either an
+	// addrs.AbsResource, or an addrs.ModuleInstance whose last step is the
+	// deferred module call, without an instance key.
+	Addr addrs.Targetable
+
+	// Reason is the reason why the object was deferred.
+	Reason DeferredReason
+}
+
+// HasDeferredChanges returns true if the planning of some objects was deferred
+// when creating the receiving plan, in which case another plan/apply round is
+// needed after applying it to converge on the configuration.
+func (p *Plan) HasDeferredChanges() bool {
+	return len(p.DeferredChanges) > 0
+}
@@ -40,10 +40,20 @@ type Plan struct {
 	// checked carefully against existing destroy behaviors.
 	UIMode Mode
 
-	VariableValues    map[string]DynamicValue
+	// VariableValues are the values of the root module input variables that
+	// were set when creating the plan, including those of ephemeral
+	// variables, which are never written to a plan file.
+	VariableValues map[string]DynamicValue
+	// ApplyTimeVariables are the names of the ephemeral input variables
+	// whose values are in VariableValues. A plan read from a plan file
+	// doesn't have values for them, so they must be set again before the
+	// plan can be applied.
+	ApplyTimeVariables []string
+
 	Changes           *Changes
 	DriftedResources  []*ResourceInstanceChangeSrc
 	TargetAddrs       []addrs.Targetable
+	ExcludeAddrs      []addrs.Targetable
 	ForceReplaceAddrs []addrs.AbsResourceInstance
 	Backend           Backend
 
@@ -71,6 +81,17 @@ type Plan struct {
 	// including anything that would be subject to compatibility constraints.
 	RelevantAttributes []globalref.ResourceAttr
 
+	// PolicyViolations records the policy rules that the planned changes
+	// don't satisfy, if the plan was checked against policies. A plan with
+	// any mandatory policy violations must not be applied.
+	PolicyViolations []*PolicyViolation
+
+	// DeferredChanges records the resources and module calls that were left
+	// out of this plan because their count or for_each arguments, or those
+	// of something they depend on, won't be known until after apply. This
+	// can only be populated if deferral was allowed when creating the plan.
+	DeferredChanges []DeferredChange
+
 	// PrevRunState and PriorState both describe the situation that the plan
 	// was derived from:
 	//
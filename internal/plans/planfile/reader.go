@@ -10,9 +10,14 @@ import (
 	"io"
 	"os"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
 	"github.com/opentofu/opentofu/internal/depsfile"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -48,23 +53,36 @@ func (e *ErrUnusableLocalPlan) Unwrap() error {
 // be used to access the individual portions of the file for further
 // processing.
 type Reader struct {
-	zip *zip.ReadCloser
+	zip *zip.Reader
 }
 
 // Open creates a Reader for the file at the given filename, or returns an error
 // if the file doesn't seem to be a planfile. NOTE: Most commands that accept a
 // plan file should use OpenWrapped instead, so they can support both local and
 // cloud plan files.
-func Open(filename string) (*Reader, error) {
-	r, err := zip.OpenReader(filename)
+//
+// The given encryption is used to decrypt the file before reading it. Use
+// encryption.PlanEncryptionDisabled to read plan files that are never
+// encrypted.
+func Open(filename string, enc encryption.PlanEncryption) (*Reader, error) {
+	raw, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// To give a better error message, we'll sniff to see if this looks
+	// like our old plan format from versions prior to 0.12.
+	if bytes.HasPrefix(raw, []byte("tfplan")) {
+		return nil, errUnusable(fmt.Errorf("the given plan file was created by an earlier version of OpenTofu, or an earlier version of Terraform; plan files cannot be shared between different OpenTofu or Terraform versions"))
+	}
+
+	raw, err = enc.DecryptPlan(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt plan file: %w", err)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
 	if err != nil {
-		// To give a better error message, we'll sniff to see if this looks
-		// like our old plan format from versions prior to 0.12.
-		if b, sErr := os.ReadFile(filename); sErr == nil {
-			if bytes.HasPrefix(b, []byte("tfplan")) {
-				return nil, errUnusable(fmt.Errorf("the given plan file was created by an earlier version of OpenTofu, or an earlier version of Terraform; plan files cannot be shared between different OpenTofu or Terraform versions"))
-			}
-		}
 		return nil, err
 	}
 
@@ -143,6 +161,22 @@ func (r *Reader) ReadPlan() (*plans.Plan, error) {
 	ret.PrevRunState = prevRunStateFile.State
 	ret.PriorState = priorStateFile.State
 
+	for _, file := range r.zip.File {
+		if file.Name != tfpolicyFilename {
+			continue
+		}
+		pr, err := file.Open()
+		if err != nil {
+			return nil, errUnusable(fmt.Errorf("failed to retrieve policy violations from plan file: %w", err))
+		}
+		defer pr.Close()
+		ret.PolicyViolations, err = readTfpolicy(pr)
+		if err != nil {
+			return nil, errUnusable(err)
+		}
+		break
+	}
+
 	return ret, nil
 }
 
@@ -158,7 +192,9 @@ func (r *Reader) ReadStateFile() (*statefile.File, error) {
 			if err != nil {
 				return nil, errUnusable(fmt.Errorf("failed to extract state from plan file: %w", err))
 			}
-			return statefile.Read(r)
+			// The state snapshots inside a plan file are not encrypted
+			// separately, because the plan file as a whole is.
+			return statefile.Read(r, encryption.StateEncryptionDisabled())
 		}
 	}
 	return nil, errUnusable(statefile.ErrNoState)
@@ -176,7 +212,9 @@ func (r *Reader) ReadPrevStateFile() (*statefile.File, error) {
 			if err != nil {
 				return nil, errUnusable(fmt.Errorf("failed to extract previous state from plan file: %w", err))
 			}
-			return statefile.Read(r)
+			// The state snapshots inside a plan file are not encrypted
+			// separately, because the plan file as a whole is.
+			return statefile.Read(r, encryption.StateEncryptionDisabled())
 		}
 	}
 	return nil, errUnusable(statefile.ErrNoState)
@@ -188,7 +226,7 @@ func (r *Reader) ReadPrevStateFile() (*statefile.File, error) {
 // This is a lower-level alternative to ReadConfig that just extracts the
 // source files, without attempting to parse them.
 func (r *Reader) ReadConfigSnapshot() (*configload.Snapshot, error) {
-	return readConfigSnapshot(&r.zip.Reader)
+	return readConfigSnapshot(r.zip)
 }
 
 // ReadConfig reads the configuration embedded in the plan file.
@@ -196,9 +234,21 @@ func (r *Reader) ReadConfigSnapshot() (*configload.Snapshot, error) {
 // Internally this function delegates to the configs/configload package to
 // parse the embedded configuration and so it returns diagnostics (rather than
 // a native Go error as with other methods on Reader).
+//
+// The root module's input variables take the values recorded in the plan.
 func (r *Reader) ReadConfig() (*configs.Config, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
+	plan, err := r.ReadPlan()
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read plan from plan file",
+			fmt.Sprintf("The plan in the plan file could not be read: %s.", err),
+		))
+		return nil, diags
+	}
+
 	snap, err := r.ReadConfigSnapshot()
 	if err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -211,12 +261,33 @@ func (r *Reader) ReadConfig() (*configs.Config, tfdiags.Diagnostics) {
 
 	loader := configload.NewLoaderFromSnapshot(snap)
 	rootDir := snap.Modules[""].Dir // Root module base directory
-	config, configDiags := loader.LoadConfig(rootDir)
+	config, configDiags := loader.LoadConfig(rootDir, StaticRootModuleCall(plan))
 	diags = diags.Append(configDiags)
 
 	return config, diags
 }
 
+// StaticRootModuleCall returns the call of the root module that the given
+// plan was created with, for loading the configuration it was created from.
+func StaticRootModuleCall(plan *plans.Plan) configs.StaticModuleCall {
+	return configs.NewStaticModuleCall(addrs.RootModule, func(v *configs.Variable) (cty.Value, hcl.Diagnostics) {
+		raw, ok := plan.VariableValues[v.Name]
+		if !ok {
+			return cty.NilVal, nil
+		}
+		val, err := raw.Decode(cty.DynamicPseudoType)
+		if err != nil {
+			return cty.NilVal, hcl.Diagnostics{{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid variable value in plan",
+				Detail:   fmt.Sprintf("Failed to decode the value of input variable %q recorded in the plan: %s.", v.Name, err),
+				Subject:  v.DeclRange.Ptr(),
+			}}
+		}
+		return val, nil
+	})
+}
+
 // ReadDependencyLocks reads the dependency lock information embedded in
 // the plan file.
 //
@@ -263,5 +334,7 @@ func (r *Reader) ReadDependencyLocks() (*depsfile.Locks, tfdiags.Diagnostics) {
 
 // Close closes the file, after which no other operations may be performed.
 func (r *Reader) Close() error {
-	return r.zip.Close()
+	// The whole file is read into memory by Open, so there's nothing to
+	// release here.
+	return nil
 }
@@ -221,6 +221,22 @@ func readTfplan(r io.Reader) (*plans.Plan, error) {
 		plan.TargetAddrs = append(plan.TargetAddrs, target.Subject)
 	}
 
+	for _, rawExcludeAddr := range rawPlan.ExcludeAddrs {
+		exclude, diags := addrs.ParseTargetStr(rawExcludeAddr)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("plan contains invalid exclude address %q: %w", rawExcludeAddr, diags.Err())
+		}
+		plan.ExcludeAddrs = append(plan.ExcludeAddrs, exclude.Subject)
+	}
+
+	for _, rawDeferred := range rawPlan.DeferredChanges {
+		deferred, err := deferredChangeFromTfplan(rawDeferred)
+		if err != nil {
+			return nil, err
+		}
+		plan.DeferredChanges = append(plan.DeferredChanges, deferred)
+	}
+
 	for _, rawReplaceAddr := range rawPlan.ForceReplaceAddrs {
 		addr, diags := addrs.ParseAbsResourceInstanceStr(rawReplaceAddr)
 		if diags.HasErrors() {
@@ -236,6 +252,7 @@ func readTfplan(r io.Reader) (*plans.Plan, error) {
 		}
 		plan.VariableValues[name] = val
 	}
+	plan.ApplyTimeVariables = rawPlan.ApplyTimeVariables
 
 	if rawBackend := rawPlan.Backend; rawBackend == nil {
 		return nil, fmt.Errorf("plan file has no backend settings; backend settings are required")
@@ -389,6 +406,9 @@ func changeFromTfplan(rawChange *planproto.Change) (*plans.ChangeSrc, error) {
 	case planproto.Action_DELETE:
 		ret.Action = plans.Delete
 		beforeIdx = 0
+	case planproto.Action_FORGET:
+		ret.Action = plans.Forget
+		beforeIdx = 0
 	case planproto.Action_CREATE_THEN_DELETE:
 		ret.Action = plans.CreateThenDelete
 		beforeIdx = 0
@@ -605,13 +625,35 @@ func writeTfplan(plan *plans.Plan, w io.Writer) error {
 		rawPlan.TargetAddrs = append(rawPlan.TargetAddrs, targetAddr.String())
 	}
 
+	for _, excludeAddr := range plan.ExcludeAddrs {
+		rawPlan.ExcludeAddrs = append(rawPlan.ExcludeAddrs, excludeAddr.String())
+	}
+
+	for _, deferred := range plan.DeferredChanges {
+		rawDeferred, err := deferredChangeToTfplan(deferred)
+		if err != nil {
+			return err
+		}
+		rawPlan.DeferredChanges = append(rawPlan.DeferredChanges, rawDeferred)
+	}
+
 	for _, replaceAddr := range plan.ForceReplaceAddrs {
 		rawPlan.ForceReplaceAddrs = append(rawPlan.ForceReplaceAddrs, replaceAddr.String())
 	}
 
+	// The values of ephemeral variables must never be saved, so only their
+	// names are recorded, to ask for them again when applying the plan.
+	applyTimeVars := make(map[string]bool, len(plan.ApplyTimeVariables))
+	for _, name := range plan.ApplyTimeVariables {
+		applyTimeVars[name] = true
+	}
 	for name, val := range plan.VariableValues {
+		if applyTimeVars[name] {
+			continue
+		}
 		rawPlan.Variables[name] = valueToTfplan(val)
 	}
+	rawPlan.ApplyTimeVariables = plan.ApplyTimeVariables
 
 	if plan.Backend.Type == "" || plan.Backend.Config == nil {
 		// This suggests a bug in the code that created the plan, since it
@@ -674,6 +716,49 @@ func resourceAttrFromTfplan(ra *planproto.PlanResourceAttr) (globalref.ResourceA
 	return res, nil
 }
 
+func deferredChangeFromTfplan(rawDeferred *planproto.DeferredChange) (plans.DeferredChange, error) {
+	var ret plans.DeferredChange
+
+	target, diags := addrs.ParseTargetStr(rawDeferred.Addr)
+	if diags.HasErrors() {
+		return ret, fmt.Errorf("plan contains invalid deferred address %q: %w", rawDeferred.Addr, diags.Err())
+	}
+	switch addr := target.Subject.(type) {
+	case addrs.AbsResource, addrs.ModuleInstance:
+		ret.Addr = addr
+	default:
+		return ret, fmt.Errorf("plan contains invalid deferred address %q: must be a resource or module call", rawDeferred.Addr)
+	}
+
+	switch rawDeferred.Reason {
+	case planproto.DeferredChange_INSTANCE_COUNT_UNKNOWN:
+		ret.Reason = plans.DeferredReasonInstanceCountUnknown
+	case planproto.DeferredChange_DEFERRED_PREREQ:
+		ret.Reason = plans.DeferredReasonDeferredPrereq
+	default:
+		return ret, fmt.Errorf("deferred change for %s has unsupported reason %s", rawDeferred.Addr, rawDeferred.Reason)
+	}
+
+	return ret, nil
+}
+
+func deferredChangeToTfplan(deferred plans.DeferredChange) (*planproto.DeferredChange, error) {
+	ret := &planproto.DeferredChange{
+		Addr: deferred.Addr.String(),
+	}
+
+	switch deferred.Reason {
+	case plans.DeferredReasonInstanceCountUnknown:
+		ret.Reason = planproto.DeferredChange_INSTANCE_COUNT_UNKNOWN
+	case plans.DeferredReasonDeferredPrereq:
+		ret.Reason = planproto.DeferredChange_DEFERRED_PREREQ
+	default:
+		return nil, fmt.Errorf("deferred change for %s has unsupported reason %q", deferred.Addr, deferred.Reason)
+	}
+
+	return ret, nil
+}
+
 func resourceChangeToTfplan(change *plans.ResourceInstanceChangeSrc) (*planproto.ResourceInstanceChange, error) {
 	ret := &planproto.ResourceInstanceChange{}
 
@@ -793,6 +878,9 @@ func changeToTfplan(change *plans.ChangeSrc) (*planproto.Change, error) {
 	case plans.Delete:
 		ret.Action = planproto.Action_DELETE
 		ret.Values = []*planproto.DynamicValue{before}
+	case plans.Forget:
+		ret.Action = planproto.Action_FORGET
+		ret.Values = []*planproto.DynamicValue{before}
 	case plans.DeleteThenCreate:
 		ret.Action = planproto.Action_DELETE_THEN_CREATE
 		ret.Values = []*planproto.DynamicValue{before, after}
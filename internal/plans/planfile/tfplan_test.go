@@ -5,6 +5,7 @@ package planfile
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 
 	"github.com/go-test/deep"
@@ -122,6 +123,28 @@ func TestTFPlanRoundTrip(t *testing.T) {
 						}), objTy),
 					},
 				},
+				{
+					Addr: addrs.Resource{
+						Mode: addrs.ManagedResourceMode,
+						Type: "test_thing",
+						Name: "forgotten",
+					}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+					PrevRunAddr: addrs.Resource{
+						Mode: addrs.ManagedResourceMode,
+						Type: "test_thing",
+						Name: "forgotten",
+					}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+					ProviderAddr: addrs.AbsProviderConfig{
+						Provider: addrs.NewDefaultProvider("test"),
+						Module:   addrs.RootModule,
+					},
+					ChangeSrc: plans.ChangeSrc{
+						Action: plans.Forget,
+						Before: mustNewDynamicValue(cty.ObjectVal(map[string]cty.Value{
+							"id": cty.StringVal("forget-me"),
+						}), objTy),
+					},
+				},
 				{
 					Addr: addrs.Resource{
 						Mode: addrs.ManagedResourceMode,
@@ -254,6 +277,23 @@ func TestTFPlanRoundTrip(t *testing.T) {
 				Name: "woot",
 			}.Absolute(addrs.RootModuleInstance),
 		},
+		ExcludeAddrs: []addrs.Targetable{
+			addrs.RootModuleInstance.Child("broken", addrs.NoKey),
+		},
+		DeferredChanges: []plans.DeferredChange{
+			{
+				Addr: addrs.Resource{
+					Mode: addrs.ManagedResourceMode,
+					Type: "test_thing",
+					Name: "later",
+				}.Absolute(addrs.RootModuleInstance),
+				Reason: plans.DeferredReasonInstanceCountUnknown,
+			},
+			{
+				Addr:   addrs.RootModuleInstance.Child("deferred", addrs.NoKey),
+				Reason: plans.DeferredReasonDeferredPrereq,
+			},
+		},
 		Backend: plans.Backend{
 			Type: "local",
 			Config: mustNewDynamicValue(
@@ -418,3 +458,47 @@ func TestTFPlanRoundTripDestroy(t *testing.T) {
 		}
 	}
 }
+
+// TestTFPlanRoundTripEphemeralVariables ensures that the values of ephemeral
+// variables are never written to a plan file, while the plan still records
+// which variables must be set again when applying it.
+func TestTFPlanRoundTripEphemeralVariables(t *testing.T) {
+	plan := &plans.Plan{
+		VariableValues: map[string]plans.DynamicValue{
+			"foo":   mustNewDynamicValueStr("foo value"),
+			"token": mustNewDynamicValueStr("secret"),
+		},
+		ApplyTimeVariables: []string{"token"},
+		Changes:            plans.NewChanges(),
+		DriftedResources:   []*plans.ResourceInstanceChangeSrc{},
+		Backend: plans.Backend{
+			Type: "local",
+			Config: mustNewDynamicValue(
+				cty.EmptyObjectVal,
+				cty.EmptyObject,
+			),
+			Workspace: "default",
+		},
+	}
+
+	var buf bytes.Buffer
+	err := writeTfplan(plan, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newPlan, err := readTfplan(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := newPlan.VariableValues["token"]; ok {
+		t.Errorf("ephemeral variable value was written to the plan")
+	}
+	if _, ok := newPlan.VariableValues["foo"]; !ok {
+		t.Errorf("missing value for variable \"foo\"")
+	}
+	if got, want := newPlan.ApplyTimeVariables, []string{"token"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong apply-time variables\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
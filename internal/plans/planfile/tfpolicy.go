@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package planfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// tfpolicyFilename is the name of the file recording the policy violations of
+// a plan, which is only present in plan files for plans that were checked
+// against policies and violated at least one of their rules.
+const tfpolicyFilename = "tfpolicy"
+
+type tfpolicyFile struct {
+	Violations []tfpolicyViolation `json:"violations"`
+}
+
+type tfpolicyViolation struct {
+	Policy    string `json:"policy"`
+	Rule      int    `json:"rule"`
+	Address   string `json:"address"`
+	Mandatory bool   `json:"mandatory"`
+	Message   string `json:"message"`
+}
+
+// readTfpolicy reads the policy violations recorded in a "tfpolicy" file.
+func readTfpolicy(r io.Reader) ([]*plans.PolicyViolation, error) {
+	var file tfpolicyFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse policy violations: %w", err)
+	}
+
+	ret := make([]*plans.PolicyViolation, 0, len(file.Violations))
+	for _, raw := range file.Violations {
+		addr, diags := addrs.ParseAbsResourceInstanceStr(raw.Address)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("invalid resource instance address %q in policy violations: %w", raw.Address, diags.Err())
+		}
+		ret = append(ret, &plans.PolicyViolation{
+			Policy:    raw.Policy,
+			Rule:      raw.Rule,
+			Addr:      addr,
+			Mandatory: raw.Mandatory,
+			Message:   raw.Message,
+		})
+	}
+	return ret, nil
+}
+
+// writeTfpolicy writes the given policy violations as a "tfpolicy" file.
+func writeTfpolicy(violations []*plans.PolicyViolation, w io.Writer) error {
+	file := tfpolicyFile{
+		Violations: make([]tfpolicyViolation, 0, len(violations)),
+	}
+	for _, v := range violations {
+		file.Violations = append(file.Violations, tfpolicyViolation{
+			Policy:    v.Policy,
+			Rule:      v.Rule,
+			Address:   v.Addr.String(),
+			Mandatory: v.Mandatory,
+			Message:   v.Message,
+		})
+	}
+	return json.NewEncoder(w).Encode(file)
+}
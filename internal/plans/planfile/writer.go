@@ -5,12 +5,14 @@ package planfile
 
 import (
 	"archive/zip"
+	"bytes"
 	"fmt"
 	"os"
 	"time"
 
 	"github.com/opentofu/opentofu/internal/configs/configload"
 	"github.com/opentofu/opentofu/internal/depsfile"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 )
@@ -51,15 +53,12 @@ type CreateArgs struct {
 // state file in addition to the plan itself, so that OpenTofu can detect
 // if the world has changed since the plan was created and thus refuse to
 // apply it.
-func Create(filename string, args CreateArgs) error {
-	f, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	zw := zip.NewWriter(f)
-	defer zw.Close()
+//
+// The whole file is encrypted using the given encryption before it is
+// written.
+func Create(filename string, args CreateArgs, enc encryption.PlanEncryption) error {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
 
 	// tfplan file
 	{
@@ -77,6 +76,22 @@ func Create(filename string, args CreateArgs) error {
 		}
 	}
 
+	// tfpolicy file, only present when the plan violates policies
+	if args.Plan != nil && len(args.Plan.PolicyViolations) != 0 {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     tfpolicyFilename,
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create embedded policy violations file: %w", err)
+		}
+		err = writeTfpolicy(args.Plan.PolicyViolations, w)
+		if err != nil {
+			return fmt.Errorf("failed to write policy violations: %w", err)
+		}
+	}
+
 	// tfstate file
 	{
 		w, err := zw.CreateHeader(&zip.FileHeader{
@@ -87,7 +102,7 @@ func Create(filename string, args CreateArgs) error {
 		if err != nil {
 			return fmt.Errorf("failed to create embedded tfstate file: %w", err)
 		}
-		err = statefile.Write(args.StateFile, w)
+		err = statefile.Write(args.StateFile, w, encryption.StateEncryptionDisabled())
 		if err != nil {
 			return fmt.Errorf("failed to write state snapshot: %w", err)
 		}
@@ -103,7 +118,7 @@ func Create(filename string, args CreateArgs) error {
 		if err != nil {
 			return fmt.Errorf("failed to create embedded tfstate-prev file: %w", err)
 		}
-		err = statefile.Write(args.PreviousRunStateFile, w)
+		err = statefile.Write(args.PreviousRunStateFile, w, encryption.StateEncryptionDisabled())
 		if err != nil {
 			return fmt.Errorf("failed to write previous state snapshot: %w", err)
 		}
@@ -138,5 +153,13 @@ func Create(filename string, args CreateArgs) error {
 		}
 	}
 
-	return nil
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize plan file: %w", err)
+	}
+
+	src, err := enc.EncryptPlan(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, src, 0666)
 }
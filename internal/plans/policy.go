@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plans
+
+import (
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+// PolicyViolation describes a planned change to a resource instance that
+// doesn't satisfy one of the rules of a policy.
+type PolicyViolation struct {
+	// Policy is the name of the violated policy.
+	Policy string
+
+	// Rule is the index of the violated rule within the policy.
+	Rule int
+
+	// Addr is the address of the resource instance whose planned change
+	// violates the rule.
+	Addr addrs.AbsResourceInstance
+
+	// Mandatory is true if the violated policy must be satisfied for the plan
+	// to be applied, or false if it's only advisory.
+	Mandatory bool
+
+	// Message is the error message of the violated rule.
+	Message string
+}
+
+// HasMandatoryPolicyViolations returns true if any of the policy violations
+// recorded in the receiving plan are for mandatory policies, in which case
+// the plan must not be applied.
+func (p *Plan) HasMandatoryPolicyViolations() bool {
+	for _, v := range p.PolicyViolations {
+		if v.Mandatory {
+			return true
+		}
+	}
+	return false
+}
@@ -15,6 +15,8 @@ import (
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 	"github.com/zclconf/go-cty/cty/msgpack"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/logging"
@@ -68,13 +70,45 @@ type GRPCProvider struct {
 	// plugin process ends.
 	ctx context.Context
 
+	// traceCtx, if set, carries the trace span that the spans for calls
+	// made through this provider are recorded under. It's set only on the
+	// views returned by WithTraceContext, whose base is the provider that
+	// they were created from.
+	traceCtx context.Context
+	base     *GRPCProvider
+
 	// schema stores the schema for this provider. This is used to properly
 	// serialize the requests for schemas.
 	mu     sync.Mutex
 	schema providers.GetProviderSchemaResponse
 }
 
+var _ providers.Traceable = (*GRPCProvider)(nil)
+
+// WithTraceContext returns a view of the provider that records the trace
+// spans for its calls as children of the span in the given context.
+func (p *GRPCProvider) WithTraceContext(ctx context.Context) providers.Interface {
+	base := p
+	if p.base != nil {
+		base = p.base
+	}
+	return &GRPCProvider{
+		PluginClient: base.PluginClient,
+		TestServer:   base.TestServer,
+		Addr:         base.Addr,
+		client:       base.client,
+		ctx:          base.ctx,
+		traceCtx:     ctx,
+		base:         base,
+	}
+}
+
 func (p *GRPCProvider) GetProviderSchema() (resp providers.GetProviderSchemaResponse) {
+	if p.base != nil {
+		// The schema is cached by the provider this view was created from.
+		return p.base.GetProviderSchema()
+	}
+
 	logger.Trace("GRPCProvider: GetProviderSchema")
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -110,7 +144,9 @@ func (p *GRPCProvider) GetProviderSchema() (resp providers.GetProviderSchemaResp
 	// size much higher on the server side, which is the supported method for
 	// determining payload size.
 	const maxRecvSize = 64 << 20
-	protoResp, err := p.client.GetSchema(p.ctx, new(proto.GetProviderSchema_Request), grpc.MaxRecvMsgSizeCallOption{MaxRecvMsgSize: maxRecvSize})
+	ctx, span := p.startSpan("GetSchema")
+	protoResp, err := p.client.GetSchema(ctx, new(proto.GetProviderSchema_Request), grpc.MaxRecvMsgSizeCallOption{MaxRecvMsgSize: maxRecvSize})
+	endSpan(span, err)
 	if err != nil {
 		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
 		return resp
@@ -142,9 +178,22 @@ func (p *GRPCProvider) GetProviderSchema() (resp providers.GetProviderSchemaResp
 		resp.DataSources[name] = convert.ProtoToProviderSchema(data)
 	}
 
+	if len(protoResp.Functions) > 0 {
+		resp.Functions = make(map[string]providers.FunctionSpec, len(protoResp.Functions))
+	}
+	for name, fn := range protoResp.Functions {
+		spec, err := convert.ProtoToFunctionSpec(fn)
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("invalid signature for function %q: %w", name, err))
+			continue
+		}
+		resp.Functions[name] = spec
+	}
+
 	if protoResp.ServerCapabilities != nil {
 		resp.ServerCapabilities.PlanDestroy = protoResp.ServerCapabilities.PlanDestroy
 		resp.ServerCapabilities.GetProviderSchemaOptional = protoResp.ServerCapabilities.GetProviderSchemaOptional
+		resp.ServerCapabilities.MoveResourceState = protoResp.ServerCapabilities.MoveResourceState
 	}
 
 	// Set the global provider cache so that future calls to this provider can use the cached value.
@@ -188,7 +237,9 @@ func (p *GRPCProvider) ValidateProviderConfig(r providers.ValidateProviderConfig
 		Config: &proto.DynamicValue{Msgpack: mp},
 	}
 
-	protoResp, err := p.client.PrepareProviderConfig(p.ctx, protoReq)
+	ctx, span := p.startSpan("PrepareProviderConfig")
+	protoResp, err := p.client.PrepareProviderConfig(ctx, protoReq)
+	endSpan(span, err)
 	if err != nil {
 		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
 		return resp
@@ -231,7 +282,9 @@ func (p *GRPCProvider) ValidateResourceConfig(r providers.ValidateResourceConfig
 		Config:   &proto.DynamicValue{Msgpack: mp},
 	}
 
-	protoResp, err := p.client.ValidateResourceTypeConfig(p.ctx, protoReq)
+	ctx, span := p.startSpan("ValidateResourceTypeConfig", traceAttrResourceType.String(r.TypeName))
+	protoResp, err := p.client.ValidateResourceTypeConfig(ctx, protoReq)
+	endSpan(span, err)
 	if err != nil {
 		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
 		return resp
@@ -267,7 +320,9 @@ func (p *GRPCProvider) ValidateDataResourceConfig(r providers.ValidateDataResour
 		Config:   &proto.DynamicValue{Msgpack: mp},
 	}
 
-	protoResp, err := p.client.ValidateDataSourceConfig(p.ctx, protoReq)
+	ctx, span := p.startSpan("ValidateDataSourceConfig", traceAttrResourceType.String(r.TypeName))
+	protoResp, err := p.client.ValidateDataSourceConfig(ctx, protoReq)
+	endSpan(span, err)
 	if err != nil {
 		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
 		return resp
@@ -300,7 +355,9 @@ func (p *GRPCProvider) UpgradeResourceState(r providers.UpgradeResourceStateRequ
 		},
 	}
 
-	protoResp, err := p.client.UpgradeResourceState(p.ctx, protoReq)
+	ctx, span := p.startSpan("UpgradeResourceState", traceAttrResourceType.String(r.TypeName))
+	protoResp, err := p.client.UpgradeResourceState(ctx, protoReq)
+	endSpan(span, err)
 	if err != nil {
 		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
 		return resp
@@ -348,7 +405,9 @@ func (p *GRPCProvider) ConfigureProvider(r providers.ConfigureProviderRequest) (
 		},
 	}
 
-	protoResp, err := p.client.Configure(p.ctx, protoReq)
+	ctx, span := p.startSpan("Configure")
+	protoResp, err := p.client.Configure(ctx, protoReq)
+	endSpan(span, err)
 	if err != nil {
 		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
 		return resp
@@ -360,7 +419,9 @@ func (p *GRPCProvider) ConfigureProvider(r providers.ConfigureProviderRequest) (
 func (p *GRPCProvider) Stop() error {
 	logger.Trace("GRPCProvider: Stop")
 
-	resp, err := p.client.Stop(p.ctx, new(proto.Stop_Request))
+	ctx, span := p.startSpan("Stop")
+	resp, err := p.client.Stop(ctx, new(proto.Stop_Request))
+	endSpan(span, err)
 	if err != nil {
 		return err
 	}
@@ -409,7 +470,9 @@ func (p *GRPCProvider) ReadResource(r providers.ReadResourceRequest) (resp provi
 		protoReq.ProviderMeta = &proto.DynamicValue{Msgpack: metaMP}
 	}
 
-	protoResp, err := p.client.ReadResource(p.ctx, protoReq)
+	ctx, span := p.startSpan("ReadResource", traceAttrResourceType.String(r.TypeName))
+	protoResp, err := p.client.ReadResource(ctx, protoReq)
+	endSpan(span, err)
 	if err != nil {
 		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
 		return resp
@@ -488,7 +551,9 @@ func (p *GRPCProvider) PlanResourceChange(r providers.PlanResourceChangeRequest)
 		protoReq.ProviderMeta = &proto.DynamicValue{Msgpack: metaMP}
 	}
 
-	protoResp, err := p.client.PlanResourceChange(p.ctx, protoReq)
+	ctx, span := p.startSpan("PlanResourceChange", traceAttrResourceType.String(r.TypeName))
+	protoResp, err := p.client.PlanResourceChange(ctx, protoReq)
+	endSpan(span, err)
 	if err != nil {
 		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
 		return resp
@@ -563,7 +628,9 @@ func (p *GRPCProvider) ApplyResourceChange(r providers.ApplyResourceChangeReques
 		protoReq.ProviderMeta = &proto.DynamicValue{Msgpack: metaMP}
 	}
 
-	protoResp, err := p.client.ApplyResourceChange(p.ctx, protoReq)
+	ctx, span := p.startSpan("ApplyResourceChange", traceAttrResourceType.String(r.TypeName))
+	protoResp, err := p.client.ApplyResourceChange(ctx, protoReq)
+	endSpan(span, err)
 	if err != nil {
 		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
 		return resp
@@ -598,7 +665,9 @@ func (p *GRPCProvider) ImportResourceState(r providers.ImportResourceStateReques
 		Id:       r.ID,
 	}
 
-	protoResp, err := p.client.ImportResourceState(p.ctx, protoReq)
+	ctx, span := p.startSpan("ImportResourceState", traceAttrResourceType.String(r.TypeName))
+	protoResp, err := p.client.ImportResourceState(ctx, protoReq)
+	endSpan(span, err)
 	if err != nil {
 		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
 		return resp
@@ -629,6 +698,55 @@ func (p *GRPCProvider) ImportResourceState(r providers.ImportResourceStateReques
 	return resp
 }
 
+func (p *GRPCProvider) MoveResourceState(r providers.MoveResourceStateRequest) (resp providers.MoveResourceStateResponse) {
+	logger.Trace("GRPCProvider: MoveResourceState")
+
+	schema := p.GetProviderSchema()
+	if schema.Diagnostics.HasErrors() {
+		resp.Diagnostics = schema.Diagnostics
+		return resp
+	}
+
+	resSchema, ok := schema.ResourceTypes[r.TargetTypeName]
+	if !ok {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unknown resource type %q", r.TargetTypeName))
+		return resp
+	}
+
+	protoReq := &proto.MoveResourceState_Request{
+		SourceProviderAddress: r.SourceProviderAddress,
+		SourceTypeName:        r.SourceTypeName,
+		SourceSchemaVersion:   r.SourceSchemaVersion,
+		SourceState: &proto.RawState{
+			Json: r.SourceStateJSON,
+		},
+		SourcePrivate:  r.SourcePrivate,
+		TargetTypeName: r.TargetTypeName,
+	}
+
+	ctx, span := p.startSpan("MoveResourceState", traceAttrResourceType.String(r.TargetTypeName))
+	protoResp, err := p.client.MoveResourceState(ctx, protoReq)
+	endSpan(span, err)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
+	if resp.Diagnostics.HasErrors() {
+		return resp
+	}
+
+	state, err := decodeDynamicValue(protoResp.TargetState, resSchema.Block.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	resp.TargetState = state
+	resp.TargetPrivate = protoResp.TargetPrivate
+
+	return resp
+}
+
 func (p *GRPCProvider) ReadDataSource(r providers.ReadDataSourceRequest) (resp providers.ReadDataSourceResponse) {
 	logger.Trace("GRPCProvider: ReadDataSource")
 
@@ -667,7 +785,9 @@ func (p *GRPCProvider) ReadDataSource(r providers.ReadDataSourceRequest) (resp p
 		protoReq.ProviderMeta = &proto.DynamicValue{Msgpack: metaMP}
 	}
 
-	protoResp, err := p.client.ReadDataSource(p.ctx, protoReq)
+	ctx, span := p.startSpan("ReadDataSource", traceAttrResourceType.String(r.TypeName))
+	protoResp, err := p.client.ReadDataSource(ctx, protoReq)
+	endSpan(span, err)
 	if err != nil {
 		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
 		return resp
@@ -684,6 +804,115 @@ func (p *GRPCProvider) ReadDataSource(r providers.ReadDataSourceRequest) (resp p
 	return resp
 }
 
+func (p *GRPCProvider) GetFunctions() (resp providers.GetFunctionsResponse) {
+	logger.Trace("GRPCProvider: GetFunctions")
+
+	ctx, span := p.startSpan("GetFunctions")
+	protoResp, err := p.client.GetFunctions(ctx, new(proto.GetFunctions_Request))
+	endSpan(span, err)
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			// Providers built against earlier protocol versions don't
+			// implement this call, in which case the functions (if any) can
+			// only come from the provider schema.
+			logger.Trace("GRPCProvider: GetFunctions: not implemented, using provider schema")
+			schema := p.GetProviderSchema()
+			resp.Functions = schema.Functions
+			resp.Diagnostics = schema.Diagnostics
+			return resp
+		}
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
+	if resp.Diagnostics.HasErrors() {
+		return resp
+	}
+
+	resp.Functions = make(map[string]providers.FunctionSpec, len(protoResp.Functions))
+	for name, fn := range protoResp.Functions {
+		spec, err := convert.ProtoToFunctionSpec(fn)
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("invalid signature for function %q: %w", name, err))
+			continue
+		}
+		resp.Functions[name] = spec
+	}
+
+	return resp
+}
+
+func (p *GRPCProvider) CallFunction(r providers.CallFunctionRequest) (resp providers.CallFunctionResponse) {
+	logger.Trace("GRPCProvider: CallFunction")
+
+	schema := p.GetProviderSchema()
+	if schema.Diagnostics.HasErrors() {
+		resp.Diagnostics = schema.Diagnostics
+		return resp
+	}
+
+	spec, ok := schema.Functions[r.Name]
+	if !ok {
+		functions := p.GetFunctions()
+		if functions.Diagnostics.HasErrors() {
+			resp.Diagnostics = functions.Diagnostics
+			return resp
+		}
+		spec, ok = functions.Functions[r.Name]
+		if !ok {
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unknown function %q", r.Name))
+			return resp
+		}
+	}
+
+	protoReq := &proto.CallFunction_Request{
+		Name:      r.Name,
+		Arguments: make([]*proto.DynamicValue, len(r.Arguments)),
+	}
+	for i, arg := range r.Arguments {
+		var paramTy cty.Type
+		switch {
+		case i < len(spec.Parameters):
+			paramTy = spec.Parameters[i].Type
+		case spec.VariadicParameter != nil:
+			paramTy = spec.VariadicParameter.Type
+		default:
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("too many arguments for function %q", r.Name))
+			return resp
+		}
+
+		mp, err := msgpack.Marshal(arg, paramTy)
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(err)
+			return resp
+		}
+		protoReq.Arguments[i] = &proto.DynamicValue{
+			Msgpack: mp,
+		}
+	}
+
+	ctx, span := p.startSpan("CallFunction", traceAttrFunctionName.String(r.Name))
+	protoResp, err := p.client.CallFunction(ctx, protoReq)
+	endSpan(span, err)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToFunctionError(protoResp.Error, spec))
+	if resp.Diagnostics.HasErrors() {
+		return resp
+	}
+
+	result, err := decodeDynamicValue(protoResp.Result, spec.Return)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	resp.Result = result
+
+	return resp
+}
+
 // closing the grpc connection is final, and tofu will call it at the end of every phase.
 func (p *GRPCProvider) Close() error {
 	logger.Trace("GRPCProvider: Close")
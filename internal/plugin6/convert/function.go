@@ -0,0 +1,150 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	proto "github.com/opentofu/opentofu/internal/tfplugin6"
+)
+
+// FunctionSpecToProto takes a providers.FunctionSpec and converts it to a
+// proto.Function for a grpc response.
+func FunctionSpecToProto(spec providers.FunctionSpec) *proto.Function {
+	fn := &proto.Function{
+		Return: &proto.Function_Return{
+			Type: marshalFunctionType(spec.Return),
+		},
+		Summary:            spec.Summary,
+		Description:        spec.Description,
+		DescriptionKind:    protoStringKind(spec.DescriptionKind),
+		DeprecationMessage: spec.DeprecationMessage,
+	}
+
+	for _, param := range spec.Parameters {
+		fn.Parameters = append(fn.Parameters, protoFunctionParameter(param))
+	}
+	if spec.VariadicParameter != nil {
+		fn.VariadicParameter = protoFunctionParameter(*spec.VariadicParameter)
+	}
+
+	return fn
+}
+
+func protoFunctionParameter(param providers.FunctionParameterSpec) *proto.Function_Parameter {
+	return &proto.Function_Parameter{
+		Name:               param.Name,
+		Type:               marshalFunctionType(param.Type),
+		AllowNullValue:     param.AllowNullValue,
+		AllowUnknownValues: param.AllowUnknownValues,
+		Description:        param.Description,
+		DescriptionKind:    protoStringKind(param.DescriptionKind),
+	}
+}
+
+func marshalFunctionType(ty cty.Type) []byte {
+	raw, err := json.Marshal(ty)
+	if err != nil {
+		panic(err)
+	}
+	return raw
+}
+
+// ProtoToFunctionSpec takes a proto.Function from a grpc response and converts
+// it to a providers.FunctionSpec.
+func ProtoToFunctionSpec(fn *proto.Function) (providers.FunctionSpec, error) {
+	spec := providers.FunctionSpec{
+		Summary:            fn.Summary,
+		Description:        fn.Description,
+		DescriptionKind:    schemaStringKind(fn.DescriptionKind),
+		DeprecationMessage: fn.DeprecationMessage,
+	}
+
+	if fn.Return == nil {
+		return spec, fmt.Errorf("missing return type")
+	}
+	if err := json.Unmarshal(fn.Return.Type, &spec.Return); err != nil {
+		return spec, fmt.Errorf("invalid return type: %w", err)
+	}
+
+	for _, param := range fn.Parameters {
+		p, err := functionParameterSpec(param)
+		if err != nil {
+			return spec, err
+		}
+		spec.Parameters = append(spec.Parameters, p)
+	}
+	if fn.VariadicParameter != nil {
+		p, err := functionParameterSpec(fn.VariadicParameter)
+		if err != nil {
+			return spec, err
+		}
+		spec.VariadicParameter = &p
+	}
+
+	return spec, nil
+}
+
+func functionParameterSpec(param *proto.Function_Parameter) (providers.FunctionParameterSpec, error) {
+	p := providers.FunctionParameterSpec{
+		Name:               param.Name,
+		AllowNullValue:     param.AllowNullValue,
+		AllowUnknownValues: param.AllowUnknownValues,
+		Description:        param.Description,
+		DescriptionKind:    schemaStringKind(param.DescriptionKind),
+	}
+	if err := json.Unmarshal(param.Type, &p.Type); err != nil {
+		return p, fmt.Errorf("invalid type for parameter %q: %w", param.Name, err)
+	}
+	return p, nil
+}
+
+// FunctionErrorToProto takes the diagnostics from a function call and
+// converts any errors to a proto.FunctionError for a grpc response. Warnings
+// can't be represented in the response, and so are discarded.
+func FunctionErrorToProto(diags tfdiags.Diagnostics) *proto.FunctionError {
+	if !diags.HasErrors() {
+		return nil
+	}
+	return &proto.FunctionError{
+		Text: diags.Err().Error(),
+	}
+}
+
+// ProtoToFunctionError takes a proto.FunctionError from a grpc response and
+// converts it to diagnostics. If the error relates to a particular argument,
+// the diagnostic names the corresponding parameter from the given spec.
+func ProtoToFunctionError(e *proto.FunctionError, spec providers.FunctionSpec) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if e == nil {
+		return diags
+	}
+
+	if e.FunctionArgument == nil {
+		return diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Error in function call",
+			e.Text,
+		))
+	}
+
+	idx := int(*e.FunctionArgument)
+	detail := fmt.Sprintf("Invalid value for argument %d: %s", idx+1, e.Text)
+	switch {
+	case idx >= 0 && idx < len(spec.Parameters):
+		detail = fmt.Sprintf("Invalid value for %q parameter: %s", spec.Parameters[idx].Name, e.Text)
+	case idx >= len(spec.Parameters) && spec.VariadicParameter != nil:
+		detail = fmt.Sprintf("Invalid value for %q parameter: %s", spec.VariadicParameter.Name, e.Text)
+	}
+	return diags.Append(tfdiags.Sourceless(
+		tfdiags.Error,
+		"Invalid function argument",
+		detail,
+	))
+}
@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	proto "github.com/opentofu/opentofu/internal/tfplugin6"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Test that we can convert function specs to protobuf types and back again.
+func TestConvertFunctionSpec(t *testing.T) {
+	tests := map[string]providers.FunctionSpec{
+		"no parameters": {
+			Return: cty.String,
+		},
+		"parameters": {
+			Parameters: []providers.FunctionParameterSpec{
+				{
+					Name:            "first",
+					Type:            cty.List(cty.String),
+					AllowNullValue:  true,
+					Description:     "The first parameter.",
+					DescriptionKind: configschema.StringPlain,
+				},
+				{
+					Name:               "second",
+					Type:               cty.DynamicPseudoType,
+					AllowUnknownValues: true,
+					Description:        "The *second* parameter.",
+					DescriptionKind:    configschema.StringMarkdown,
+				},
+			},
+			VariadicParameter: &providers.FunctionParameterSpec{
+				Name: "rest",
+				Type: cty.Number,
+			},
+			Return:             cty.Object(map[string]cty.Type{"a": cty.Bool}),
+			Summary:            "A function.",
+			Description:        "A function with parameters.",
+			DescriptionKind:    configschema.StringPlain,
+			DeprecationMessage: "Use another function.",
+		},
+	}
+
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ProtoToFunctionSpec(FunctionSpecToProto(want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(got, want, typeComparer, equateEmpty) {
+				t.Fatal(cmp.Diff(got, want, typeComparer, equateEmpty))
+			}
+		})
+	}
+}
+
+func TestProtoToFunctionError(t *testing.T) {
+	spec := providers.FunctionSpec{
+		Parameters: []providers.FunctionParameterSpec{
+			{Name: "first", Type: cty.String},
+		},
+		VariadicParameter: &providers.FunctionParameterSpec{
+			Name: "rest",
+			Type: cty.String,
+		},
+		Return: cty.String,
+	}
+	argument := func(i int64) *int64 {
+		return &i
+	}
+
+	tests := map[string]struct {
+		Error *proto.FunctionError
+		Want  []tfdiags.Description
+	}{
+		"nil": {
+			nil,
+			nil,
+		},
+		"function error": {
+			&proto.FunctionError{Text: "oops"},
+			[]tfdiags.Description{
+				{Summary: "Error in function call", Detail: "oops"},
+			},
+		},
+		"parameter error": {
+			&proto.FunctionError{Text: "oops", FunctionArgument: argument(0)},
+			[]tfdiags.Description{
+				{Summary: "Invalid function argument", Detail: `Invalid value for "first" parameter: oops`},
+			},
+		},
+		"variadic parameter error": {
+			&proto.FunctionError{Text: "oops", FunctionArgument: argument(2)},
+			[]tfdiags.Description{
+				{Summary: "Invalid function argument", Detail: `Invalid value for "rest" parameter: oops`},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []tfdiags.Description
+			for _, diag := range ProtoToFunctionError(test.Error, spec) {
+				if diag.Severity() != tfdiags.Error {
+					t.Errorf("wrong severity %s", diag.Severity())
+				}
+				got = append(got, diag.Description())
+			}
+			if !cmp.Equal(got, test.Want) {
+				t.Fatal(cmp.Diff(got, test.Want))
+			}
+		})
+	}
+}
+
+func TestFunctionErrorToProto(t *testing.T) {
+	var diags tfdiags.Diagnostics
+	if got := FunctionErrorToProto(diags); got != nil {
+		t.Fatalf("unexpected error %#v", got)
+	}
+
+	diags = diags.Append(tfdiags.Sourceless(tfdiags.Error, "Bad input", "The input was bad."))
+	got := FunctionErrorToProto(diags)
+	if want := "Bad input: The input was bad."; got.Text != want {
+		t.Fatalf("wrong text\ngot:  %s\nwant: %s", got.Text, want)
+	}
+	if got.FunctionArgument != nil {
+		t.Fatalf("unexpected function argument %d", *got.FunctionArgument)
+	}
+}
@@ -5,6 +5,7 @@ package plugin6
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"testing"
 
@@ -17,6 +18,11 @@ import (
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/zclconf/go-cty/cty"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	mockproto "github.com/opentofu/opentofu/internal/plugin6/mock_proto"
 	proto "github.com/opentofu/opentofu/internal/tfplugin6"
@@ -101,6 +107,19 @@ func providerProtoSchema() *proto.GetProviderSchema_Response {
 				},
 			},
 		},
+		Functions: map[string]*proto.Function{
+			"fun": {
+				Parameters: []*proto.Function_Parameter{
+					{
+						Name: "input",
+						Type: []byte(`"string"`),
+					},
+				},
+				Return: &proto.Function_Return{
+					Type: []byte(`"string"`),
+				},
+			},
+		},
 	}
 }
 
@@ -816,6 +835,45 @@ func TestGRPCProvider_ImportResourceStateJSON(t *testing.T) {
 	}
 }
 
+func TestGRPCProvider_MoveResourceState(t *testing.T) {
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	expectedTargetPrivate := []byte(`{"target": "private"}`)
+	expectedTargetState := cty.ObjectVal(map[string]cty.Value{
+		"attr": cty.StringVal("bar"),
+	})
+
+	client.EXPECT().MoveResourceState(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&proto.MoveResourceState_Response{
+		TargetState: &proto.DynamicValue{
+			Msgpack: []byte("\x81\xa4attr\xa3bar"),
+		},
+		TargetPrivate: expectedTargetPrivate,
+	}, nil)
+
+	resp := p.MoveResourceState(providers.MoveResourceStateRequest{
+		SourceProviderAddress: "registry.opentofu.org/example/other",
+		SourceTypeName:        "other_resource",
+		SourceSchemaVersion:   1,
+		SourceStateJSON:       []byte(`{"old_attr":"bar"}`),
+		SourcePrivate:         []byte(`{"source": "private"}`),
+		TargetTypeName:        "resource",
+	})
+	checkDiags(t, resp.Diagnostics)
+
+	if !cmp.Equal(expectedTargetState, resp.TargetState, typeComparer, valueComparer, equateEmpty) {
+		t.Error(cmp.Diff(expectedTargetState, resp.TargetState, typeComparer, valueComparer, equateEmpty))
+	}
+	if !bytes.Equal(expectedTargetPrivate, resp.TargetPrivate) {
+		t.Errorf("wrong private data\ngot:  %s\nwant: %s", resp.TargetPrivate, expectedTargetPrivate)
+	}
+}
+
 func TestGRPCProvider_ReadDataSource(t *testing.T) {
 	client := mockProviderClient(t)
 	p := &GRPCProvider{
@@ -881,3 +939,173 @@ func TestGRPCProvider_ReadDataSourceJSON(t *testing.T) {
 		t.Fatal(cmp.Diff(expected, resp.State, typeComparer, valueComparer, equateEmpty))
 	}
 }
+
+func TestGRPCProvider_GetFunctions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mockproto.NewMockProviderClient(ctrl)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	client.EXPECT().GetFunctions(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&proto.GetFunctions_Response{
+		Functions: map[string]*proto.Function{
+			"other": {
+				Return: &proto.Function_Return{
+					Type: []byte(`"bool"`),
+				},
+			},
+		},
+	}, nil)
+
+	resp := p.GetFunctions()
+	checkDiags(t, resp.Diagnostics)
+
+	expected := map[string]providers.FunctionSpec{
+		"other": {
+			Return: cty.Bool,
+		},
+	}
+	if !cmp.Equal(expected, resp.Functions, typeComparer) {
+		t.Fatal(cmp.Diff(expected, resp.Functions, typeComparer))
+	}
+}
+
+// Providers that predate GetFunctions can still export functions in their
+// schema.
+func TestGRPCProvider_GetFunctionsUnimplemented(t *testing.T) {
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	client.EXPECT().GetFunctions(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(nil, status.Error(codes.Unimplemented, "method GetFunctions not implemented"))
+
+	resp := p.GetFunctions()
+	checkDiags(t, resp.Diagnostics)
+
+	if _, ok := resp.Functions["fun"]; !ok {
+		t.Fatalf("missing function from schema, got %#v", resp.Functions)
+	}
+}
+
+func TestGRPCProvider_CallFunction(t *testing.T) {
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	client.EXPECT().CallFunction(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&proto.CallFunction_Response{
+		Result: &proto.DynamicValue{
+			Msgpack: []byte("\xa3bar"),
+		},
+	}, nil)
+
+	resp := p.CallFunction(providers.CallFunctionRequest{
+		Name:      "fun",
+		Arguments: []cty.Value{cty.StringVal("foo")},
+	})
+	checkDiags(t, resp.Diagnostics)
+
+	expected := cty.StringVal("bar")
+	if !expected.RawEquals(resp.Result) {
+		t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", resp.Result, expected)
+	}
+}
+
+func TestGRPCProvider_CallFunctionError(t *testing.T) {
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	argument := int64(0)
+	client.EXPECT().CallFunction(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&proto.CallFunction_Response{
+		Error: &proto.FunctionError{
+			Text:             "must not be empty",
+			FunctionArgument: &argument,
+		},
+	}, nil)
+
+	resp := p.CallFunction(providers.CallFunctionRequest{
+		Name:      "fun",
+		Arguments: []cty.Value{cty.StringVal("")},
+	})
+	checkDiagsHasError(t, resp.Diagnostics)
+
+	got := resp.Diagnostics[0].Description().Detail
+	if want := `Invalid value for "input" parameter: must not be empty`; got != want {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestGRPCProvider_CallFunctionUnknown(t *testing.T) {
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	client.EXPECT().GetFunctions(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&proto.GetFunctions_Response{}, nil)
+
+	resp := p.CallFunction(providers.CallFunctionRequest{
+		Name:      "missing",
+		Arguments: []cty.Value{cty.StringVal("foo")},
+	})
+	checkDiagsHasError(t, resp.Diagnostics)
+}
+
+func TestGRPCProvider_WithTraceContext(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+	})
+
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+		ctx:    context.Background(),
+	}
+
+	client.EXPECT().ValidateResourceConfig(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&proto.ValidateResourceConfig_Response{}, nil)
+
+	traceCtx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	view := p.WithTraceContext(traceCtx)
+	resp := view.ValidateResourceConfig(providers.ValidateResourceConfigRequest{
+		TypeName: "resource",
+		Config:   hcl2shim.HCL2ValueFromConfigValue(map[string]interface{}{"attr": "value"}),
+	})
+	checkDiags(t, resp.Diagnostics)
+	parent.End()
+
+	var span sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.Name() == "tfplugin6.Provider/ValidateResourceConfig" {
+			span = s
+		}
+	}
+	if span == nil {
+		t.Fatal("no span recorded for the provider call")
+	}
+	if got, want := span.Parent().SpanID(), parent.SpanContext().SpanID(); got != want {
+		t.Errorf("provider call span has wrong parent %s; want %s", got, want)
+	}
+}
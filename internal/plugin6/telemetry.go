@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plugin6
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer trace.Tracer
+
+func init() {
+	tracer = otel.Tracer("github.com/opentofu/opentofu/internal/plugin6")
+}
+
+// Attribute keys for the provider call spans, in addition to the standard
+// rpc.* attributes.
+const (
+	traceAttrProviderAddress = attribute.Key("opentofu.provider.address")
+	traceAttrResourceType    = attribute.Key("opentofu.resource.type")
+	traceAttrFunctionName    = attribute.Key("opentofu.function.name")
+)
+
+// startSpan starts a span covering a single gRPC call to the provider. The
+// returned context must be used for the call so that the span covers it.
+func (p *GRPCProvider) startSpan(method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		semconv.RPCSystemKey.String("grpc"),
+		semconv.RPCServiceKey.String("tfplugin6.Provider"),
+		semconv.RPCMethodKey.String(method),
+	)
+	if !p.Addr.IsZero() {
+		attrs = append(attrs, traceAttrProviderAddress.String(p.Addr.String()))
+	}
+	ctx := p.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if p.traceCtx != nil {
+		// The call still uses the plugin's context, so that it's canceled
+		// if the plugin exits, but the span is recorded under the caller's.
+		ctx = trace.ContextWithSpan(ctx, trace.SpanFromContext(p.traceCtx))
+	}
+	return tracer.Start(ctx, "tfplugin6.Provider/"+method, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends a span started by startSpan, recording the given error if
+// the call failed.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
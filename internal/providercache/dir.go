@@ -39,16 +39,19 @@ type Dir struct {
 	// We intentionally don't make effort to detect modifications to the
 	// directory made by other codepaths because the contract for NewDir
 	// explicitly defines using the same directory for multiple purposes
-	// as undefined behavior.
+	// as undefined behavior. Packages installed by other processes sharing
+	// the directory may be missing from a stale cache, which at worst
+	// causes us to install them again.
 	metaCache map[addrs.Provider][]CachedProvider
 }
 
 // NewDir creates and returns a new Dir object that will read and write
 // provider plugins in the given filesystem directory.
 //
-// If two instances of Dir are concurrently operating on a particular base
-// directory, or if a Dir base directory is also used as a filesystem mirror
-// source directory, the behavior is undefined.
+// Several instances of Dir, including in different processes, may install
+// packages into the same base directory concurrently, because each package is
+// locked while it's being installed. If a Dir base directory is also used as
+// a filesystem mirror source directory, the behavior is undefined.
 func NewDir(baseDir string) *Dir {
 	return &Dir{
 		baseDir:        baseDir,
@@ -0,0 +1,132 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package providercache
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/getproviders"
+)
+
+// packageLockRetryMax is the longest we'll wait between attempts to acquire
+// a package lock that is held by another process.
+const packageLockRetryMax = 500 * time.Millisecond
+
+// packageLocks serializes the package locks held within this process, because
+// the operating system file locks we use are either associated with the whole
+// process or with a particular file handle, and so don't exclude other
+// goroutines opening the same lock file.
+var packageLocks = struct {
+	sync.Mutex
+	held map[string]chan struct{}
+}{
+	held: make(map[string]chan struct{}),
+}
+
+// packageLockPath returns the path of the lock file for the unpacked package
+// directory at the given path. The lock file is a hidden sibling of the
+// package directory, so that it doesn't affect the package checksum and is
+// ignored when scanning the cache directory.
+func packageLockPath(packageDir string) string {
+	return filepath.Join(filepath.Dir(packageDir), "."+filepath.Base(packageDir)+".lock")
+}
+
+// lockPackage acquires an exclusive lock on the package for the given
+// provider version in the receiving directory, waiting until any other
+// process holding it releases it or until the given context is cancelled.
+//
+// InstallPackage and LinkFromOtherCache already lock the package they
+// install, so callers need only use lockPackage to prevent a package from
+// being replaced while they read it, such as while linking it from a shared
+// cache directory with LinkFromOtherCache. The caller must call the returned
+// function to release the lock, and must not install the same package into
+// the receiving directory while holding it.
+func (d *Dir) lockPackage(ctx context.Context, provider addrs.Provider, version getproviders.Version) (func(), error) {
+	return lockPackageDir(ctx, getproviders.UnpackedDirectoryPathForPackage(
+		d.baseDir, provider, version, d.targetPlatform,
+	))
+}
+
+// lockPackageDir acquires an exclusive lock on the unpacked package directory
+// at the given path, which need not exist yet, waiting until any other process
+// or goroutine holding it releases it or until the given context is
+// cancelled.
+//
+// The lock allows several processes to share a cache directory, such as a
+// global plugin cache used by concurrent "tofu init" runs, without observing
+// or overwriting a package that another process is installing. The caller
+// must call the returned function to release the lock.
+func lockPackageDir(ctx context.Context, packageDir string) (func(), error) {
+	lockPath := packageLockPath(packageDir)
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory for %s: %w", lockPath, err)
+	}
+
+	// The same directory can be reached through different paths, such as
+	// when a cache directory is a symlink, so we key the in-process locks
+	// by the real path of the lock file.
+	key := lockPath
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(lockPath)); err == nil {
+		key = filepath.Join(dir, filepath.Base(lockPath))
+	}
+	packageLocks.Lock()
+	sem, ok := packageLocks.held[key]
+	if !ok {
+		sem = make(chan struct{}, 1)
+		packageLocks.held[key] = sem
+	}
+	packageLocks.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("interrupted while waiting for lock on %s", packageDir)
+	}
+	release := func() { <-sem }
+
+	f, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("failed to open lock file %s: %w", lockPath, err)
+	}
+
+	wait := 10 * time.Millisecond
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			release()
+			return nil, fmt.Errorf("failed to lock %s: %w", lockPath, err)
+		}
+		if locked {
+			break
+		}
+		log.Printf("[TRACE] providercache: waiting for another process to release %s", lockPath)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			f.Close()
+			release()
+			return nil, fmt.Errorf("interrupted while waiting for lock on %s", packageDir)
+		}
+		if wait *= 2; wait > packageLockRetryMax {
+			wait = packageLockRetryMax
+		}
+	}
+
+	return func() {
+		if err := unlockFile(f); err != nil {
+			log.Printf("[WARN] providercache: failed to unlock %s: %s", lockPath, err)
+		}
+		f.Close()
+		release()
+	}, nil
+}
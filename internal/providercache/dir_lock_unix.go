@@ -0,0 +1,43 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !windows
+// +build !windows
+
+package providercache
+
+import (
+	"errors"
+	"io"
+	"os"
+	"syscall"
+)
+
+// tryLockFile attempts to take an exclusive lock on the given file without
+// waiting, returning false if another process already holds it.
+//
+// We use fcntl POSIX locks for consistency with the state file locks, and
+// for their compatibility with network filesystems.
+func tryLockFile(f *os.File) (bool, error) {
+	flock := &syscall.Flock_t{
+		Type:   syscall.F_WRLCK,
+		Whence: int16(io.SeekStart),
+		Start:  0,
+		Len:    0,
+	}
+	err := syscall.FcntlFlock(f.Fd(), syscall.F_SETLK, flock)
+	if errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EACCES) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func unlockFile(f *os.File) error {
+	flock := &syscall.Flock_t{
+		Type:   syscall.F_UNLCK,
+		Whence: int16(io.SeekStart),
+		Start:  0,
+		Len:    0,
+	}
+	return syscall.FcntlFlock(f.Fd(), syscall.F_SETLK, flock)
+}
@@ -7,8 +7,13 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/opentofu/opentofu/internal/copy"
 	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/replacefile"
 )
 
 // InstallPackage takes a metadata object describing a package available for
@@ -19,6 +24,11 @@ import (
 // in the set must match the package that "entry" refers to. If none of the
 // hashes match then the returned error message assumes that the hashes came
 // from a lock file.
+//
+// InstallPackage is safe to call concurrently with other processes installing
+// the same package into the same directory: it holds a lock on the package
+// while installing it, and moves the new package into place only once it's
+// complete.
 func (d *Dir) InstallPackage(ctx context.Context, meta getproviders.PackageMeta, allowedHashes []getproviders.Hash) (*getproviders.PackageAuthenticationResult, error) {
 	if meta.TargetPlatform != d.targetPlatform {
 		return nil, fmt.Errorf("can't install %s package into cache directory expecting %s", meta.TargetPlatform, d.targetPlatform)
@@ -31,19 +41,33 @@ func (d *Dir) InstallPackage(ctx context.Context, meta getproviders.PackageMeta,
 	// incorporate any changes we make here.
 	d.metaCache = nil
 
-	log.Printf("[TRACE] providercache.Dir.InstallPackage: installing %s v%s from %s", meta.Provider, meta.Version, meta.Location)
-	switch meta.Location.(type) {
-	case getproviders.PackageHTTPURL:
-		return installFromHTTPURL(ctx, meta, newPath, allowedHashes)
-	case getproviders.PackageLocalArchive:
-		return installFromLocalArchive(ctx, meta, newPath, allowedHashes)
-	case getproviders.PackageLocalDir:
-		return installFromLocalDir(ctx, meta, newPath, allowedHashes)
-	default:
-		// Should not get here, because the above should be exhaustive for
-		// all implementations of getproviders.Location.
-		return nil, fmt.Errorf("don't know how to install from a %T location", meta.Location)
+	// We install into a temporary directory below, so the check that
+	// installFromLocalDir makes for this wouldn't see the final path.
+	if sourceDir, ok := meta.Location.(getproviders.PackageLocalDir); ok {
+		if same, err := copy.SameFile(newPath, string(sourceDir)); same {
+			return nil, fmt.Errorf("cannot install existing provider directory %s to itself", newPath)
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to determine if %s and %s are the same: %w", sourceDir, newPath, err)
+		}
 	}
+
+	log.Printf("[TRACE] providercache.Dir.InstallPackage: installing %s v%s from %s", meta.Provider, meta.Version, meta.Location)
+	return installPackageDir(ctx, newPath, true, func(tmpPath string) (*getproviders.PackageAuthenticationResult, error) {
+		switch meta.Location.(type) {
+		case getproviders.PackageHTTPURL:
+			return installFromHTTPURL(ctx, meta, tmpPath, allowedHashes)
+		case getproviders.PackageOCIBlob:
+			return installFromOCIBlob(ctx, meta, tmpPath, allowedHashes)
+		case getproviders.PackageLocalArchive:
+			return installFromLocalArchive(ctx, meta, tmpPath, allowedHashes)
+		case getproviders.PackageLocalDir:
+			return installFromLocalDir(ctx, meta, tmpPath, allowedHashes)
+		default:
+			// Should not get here, because the above should be exhaustive for
+			// all implementations of getproviders.Location.
+			return nil, fmt.Errorf("don't know how to install from a %T location", meta.Location)
+		}
+	})
 }
 
 // LinkFromOtherCache takes a CachedProvider value produced from another Dir
@@ -61,6 +85,10 @@ func (d *Dir) InstallPackage(ctx context.Context, meta getproviders.PackageMeta,
 // in the set must match the package that "entry" refers to. If none of the
 // hashes match then the returned error message assumes that the hashes came
 // from a lock file.
+//
+// If the other Dir is shared with other processes then the caller should hold
+// the other Dir's lock for the entry's package, so that the package
+// can't be replaced while it's being checked and linked.
 func (d *Dir) LinkFromOtherCache(entry *CachedProvider, allowedHashes []getproviders.Hash) error {
 	if len(allowedHashes) > 0 {
 		if matches, err := entry.MatchesAnyHash(allowedHashes); err != nil {
@@ -105,6 +133,136 @@ func (d *Dir) LinkFromOtherCache(entry *CachedProvider, allowedHashes []getprovi
 	}
 	// No further hash check here because we already checked the hash
 	// of the source directory above.
-	_, err := installFromLocalDir(context.TODO(), meta, newPath, nil)
+	_, err := installPackageDir(context.TODO(), newPath, false, func(tmpPath string) (*getproviders.PackageAuthenticationResult, error) {
+		return installFromLocalDir(context.TODO(), meta, tmpPath, nil)
+	})
 	return err
 }
+
+// installPackageDir holds a lock on the package directory at newPath while
+// calling install to install a package into a temporary directory alongside
+// it, and then moves the result into place. This ensures that neither a
+// concurrent installation nor an interruption can leave a partially-written
+// package at newPath.
+//
+// If recordHash is set then installPackageDir also records the checksum of
+// the new package, for checkInstalledHash.
+func installPackageDir(ctx context.Context, newPath string, recordHash bool, install func(tmpPath string) (*getproviders.PackageAuthenticationResult, error)) (*getproviders.PackageAuthenticationResult, error) {
+	unlock, err := lockPackageDir(ctx, newPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	tmpPath, err := os.MkdirTemp(filepath.Dir(newPath), "."+filepath.Base(newPath)+".tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory for %s: %w", newPath, err)
+	}
+	defer os.RemoveAll(tmpPath) // no-op if we successfully move it into place
+
+	authResult, err := install(tmpPath)
+	if err != nil {
+		return authResult, err
+	}
+
+	var hash getproviders.Hash
+	if recordHash {
+		hash, err = getproviders.PackageHashV1(getproviders.PackageLocalDir(tmpPath))
+		if err != nil {
+			return authResult, fmt.Errorf("failed to calculate checksum for package at %s: %w", newPath, err)
+		}
+
+		// If another process already installed this same package then we'll
+		// keep its copy, rather than replacing it underneath anything that
+		// might be using it.
+		if installedHashMatches(newPath, hash) {
+			log.Printf("[TRACE] providercache: keeping identical existing package at %s", newPath)
+			return authResult, nil
+		}
+	}
+
+	if err := replacePackageDir(tmpPath, newPath); err != nil {
+		return authResult, err
+	}
+
+	if recordHash {
+		if err := replacefile.AtomicWriteFile(installedHashPath(newPath), []byte(hash.String()+"\n"), 0644); err != nil {
+			return authResult, fmt.Errorf("failed to record checksum for package at %s: %w", newPath, err)
+		}
+	}
+	return authResult, nil
+}
+
+// replacePackageDir moves the package directory at tmpPath to newPath,
+// replacing any package that is already there.
+func replacePackageDir(tmpPath, newPath string) error {
+	// The checksum we recorded for the previous package no longer applies.
+	if err := os.Remove(installedHashPath(newPath)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove recorded checksum for %s: %w", newPath, err)
+	}
+
+	// A directory can't be renamed over another one, so we first move the
+	// existing package aside, which keeps the time when there's nothing at
+	// newPath as short as possible.
+	oldPath := tmpPath + ".old"
+	if err := os.Rename(newPath, oldPath); err == nil {
+		defer os.RemoveAll(oldPath)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to move existing %s aside: %w", newPath, err)
+	}
+
+	if err := os.Rename(tmpPath, newPath); err != nil {
+		return fmt.Errorf("failed to move new package into place at %s: %w", newPath, err)
+	}
+	return nil
+}
+
+// installedHashPath returns the path of the file in which InstallPackage
+// records the checksum of the package it installed at the given path. Like
+// the package's lock file, it's a hidden sibling of the package directory.
+func installedHashPath(packageDir string) string {
+	return filepath.Join(filepath.Dir(packageDir), "."+filepath.Base(packageDir)+".hash")
+}
+
+// readInstalledHash returns the checksum that InstallPackage recorded for
+// the package it installed at the given path.
+func readInstalledHash(packageDir string) (getproviders.Hash, error) {
+	raw, err := os.ReadFile(installedHashPath(packageDir))
+	if err != nil {
+		return "", err
+	}
+	return getproviders.ParseHash(strings.TrimSpace(string(raw)))
+}
+
+// installedHashMatches returns true if the package at the given path has the
+// given checksum, both as recorded when it was installed and as calculated
+// from its current contents.
+func installedHashMatches(packageDir string, want getproviders.Hash) bool {
+	if recorded, err := readInstalledHash(packageDir); err != nil || recorded != want {
+		return false
+	}
+	got, err := getproviders.PackageHashV1(getproviders.PackageLocalDir(packageDir))
+	return err == nil && got == want
+}
+
+// checkInstalledHash returns an error if the given cached package doesn't
+// match the checksum recorded when it was installed by InstallPackage, which
+// means that it was modified or only partially written since, or if there's
+// no recorded checksum at all.
+func checkInstalledHash(entry *CachedProvider) error {
+	want, err := readInstalledHash(entry.PackageDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("there is no record of %s %s being completely installed", entry.Provider, entry.Version)
+		}
+		return fmt.Errorf("invalid recorded checksum for %s %s: %w", entry.Provider, entry.Version, err)
+	}
+	got, err := entry.Hash()
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("%s %s doesn't match the checksum recorded when it was installed", entry.Provider, entry.Version)
+	}
+	return nil
+}
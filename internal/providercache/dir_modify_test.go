@@ -5,14 +5,19 @@ package providercache
 
 import (
 	"context"
+	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/apparentlymart/go-versions/versions"
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/oci"
 )
 
 func TestInstallPackage(t *testing.T) {
@@ -68,6 +73,70 @@ func TestInstallPackage(t *testing.T) {
 	}
 }
 
+func TestInstallPackage_ociBlob(t *testing.T) {
+	tmpDirPath, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linuxPlatform := getproviders.Platform{
+		OS:   "linux",
+		Arch: "amd64",
+	}
+	nullProvider := addrs.NewProvider(
+		addrs.DefaultProviderRegistryHost, "hashicorp", "null",
+	)
+	archive, err := os.ReadFile("testdata/provider-null_2.1.0_linux_amd64.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := oci.NewTestRegistry(t)
+	layer := registry.PushBlob("application/vnd.opentofu.provider.v1.zip", archive)
+	manifest := registry.PushManifest("hashicorp/null", "", &oci.Manifest{
+		SchemaVersion: 2,
+		MediaType:     oci.MediaTypeImageManifest,
+		Layers:        []oci.Descriptor{layer},
+	})
+	manifest.Platform = &oci.Platform{OS: "linux", Architecture: "amd64"}
+	registry.PushManifest("hashicorp/null", "2.1.0", &oci.Manifest{
+		SchemaVersion: 2,
+		MediaType:     oci.MediaTypeImageIndex,
+		Manifests:     []oci.Descriptor{manifest},
+	})
+	source, err := getproviders.NewOCIMirrorSource(registry.Host()+"/${namespace}/${type}", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta, err := source.PackageMeta(context.Background(), nullProvider, versions.MustParseVersion("2.1.0"), linuxPlatform)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := NewDirWithPlatform(tmpDirPath, linuxPlatform)
+	result, err := tmpDir.InstallPackage(context.Background(), meta, nil)
+	if err != nil {
+		t.Fatalf("InstallPackage failed: %s", err)
+	}
+	if got, want := result.String(), "verified checksum"; got != want {
+		t.Errorf("wrong authentication result %q; want %q", got, want)
+	}
+	if got := tmpDir.ProviderVersion(nullProvider, versions.MustParseVersion("2.1.0")); got == nil {
+		t.Fatalf("provider was not installed")
+	}
+
+	// A package that doesn't match the digest in its manifest is rejected.
+	registry.CorruptBlob(layer.Digest)
+	otherDir := NewDirWithPlatform(t.TempDir(), linuxPlatform)
+	_, err = otherDir.InstallPackage(context.Background(), meta, nil)
+	if err == nil {
+		t.Fatal("InstallPackage succeeded with a corrupt package")
+	}
+	if got, want := err.Error(), "has the wrong digest"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: containing %q", got, want)
+	}
+}
+
 func TestLinkFromOtherCache(t *testing.T) {
 	srcDirPath := "testdata/cachedir"
 	tmpDirPath, err := filepath.EvalSymlinks(t.TempDir())
@@ -144,3 +213,157 @@ func TestLinkFromOtherCache(t *testing.T) {
 		t.Errorf("wrong cache contents after link\n%s", diff)
 	}
 }
+
+func TestInstallPackage_concurrent(t *testing.T) {
+	tmpDirPath, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linuxPlatform := getproviders.Platform{
+		OS:   "linux",
+		Arch: "amd64",
+	}
+	nullProvider := addrs.NewProvider(
+		addrs.DefaultProviderRegistryHost, "hashicorp", "null",
+	)
+	meta := getproviders.PackageMeta{
+		Provider: nullProvider,
+		Version:  versions.MustParseVersion("2.1.0"),
+
+		ProtocolVersions: getproviders.VersionList{versions.MustParseVersion("5.0.0")},
+		TargetPlatform:   linuxPlatform,
+
+		Filename: "provider-null_2.1.0_linux_amd64.zip",
+		Location: getproviders.PackageLocalArchive("testdata/provider-null_2.1.0_linux_amd64.zip"),
+	}
+
+	// Each installation uses its own Dir, as separate "tofu init" processes
+	// sharing a global cache directory would.
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = NewDirWithPlatform(tmpDirPath, linuxPlatform).InstallPackage(context.Background(), meta, nil)
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("installation %d failed: %s", i, err)
+		}
+	}
+
+	entry := NewDirWithPlatform(tmpDirPath, linuxPlatform).ProviderVersion(nullProvider, meta.Version)
+	if entry == nil {
+		t.Fatalf("package not installed")
+	}
+	if err := checkInstalledHash(entry); err != nil {
+		t.Errorf("installed package is not intact: %s", err)
+	}
+
+	// Only the package and the installer's own bookkeeping files should be
+	// left behind, and no temporary directories.
+	entries, err := os.ReadDir(filepath.Dir(entry.PackageDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	want := []string{".linux_amd64.hash", ".linux_amd64.lock", "linux_amd64"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong version directory contents\n%s", diff)
+	}
+}
+
+func TestInstallPackage_replacesModified(t *testing.T) {
+	tmpDirPath, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linuxPlatform := getproviders.Platform{
+		OS:   "linux",
+		Arch: "amd64",
+	}
+	nullProvider := addrs.NewProvider(
+		addrs.DefaultProviderRegistryHost, "hashicorp", "null",
+	)
+	meta := getproviders.PackageMeta{
+		Provider: nullProvider,
+		Version:  versions.MustParseVersion("2.1.0"),
+
+		ProtocolVersions: getproviders.VersionList{versions.MustParseVersion("5.0.0")},
+		TargetPlatform:   linuxPlatform,
+
+		Filename: "provider-null_2.1.0_linux_amd64.zip",
+		Location: getproviders.PackageLocalArchive("testdata/provider-null_2.1.0_linux_amd64.zip"),
+	}
+
+	tmpDir := NewDirWithPlatform(tmpDirPath, linuxPlatform)
+	if _, err := tmpDir.InstallPackage(context.Background(), meta, nil); err != nil {
+		t.Fatalf("InstallPackage failed: %s", err)
+	}
+	entry := tmpDir.ProviderVersion(nullProvider, meta.Version)
+	if err := checkInstalledHash(entry); err != nil {
+		t.Fatalf("installed package is not intact: %s", err)
+	}
+
+	// Truncating the executable simulates a package that was only partially
+	// written, which we must detect.
+	exe, err := entry.ExecutableFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(exe, 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkInstalledHash(entry); err == nil {
+		t.Fatalf("modified package was not detected")
+	}
+
+	// Installing the package again repairs it.
+	if _, err := tmpDir.InstallPackage(context.Background(), meta, nil); err != nil {
+		t.Fatalf("InstallPackage failed: %s", err)
+	}
+	entry = tmpDir.ProviderVersion(nullProvider, meta.Version)
+	if err := checkInstalledHash(entry); err != nil {
+		t.Errorf("reinstalled package is not intact: %s", err)
+	}
+}
+
+func TestLockPackage(t *testing.T) {
+	tmpDirPath := t.TempDir()
+	linuxPlatform := getproviders.Platform{
+		OS:   "linux",
+		Arch: "amd64",
+	}
+	nullProvider := addrs.NewProvider(
+		addrs.DefaultProviderRegistryHost, "hashicorp", "null",
+	)
+	version := versions.MustParseVersion("2.1.0")
+
+	unlock, err := NewDirWithPlatform(tmpDirPath, linuxPlatform).lockPackage(context.Background(), nullProvider, version)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another Dir for the same directory must wait for the lock.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := NewDirWithPlatform(tmpDirPath, linuxPlatform).lockPackage(ctx, nullProvider, version); err == nil {
+		t.Fatalf("acquired a lock that is already held")
+	}
+
+	unlock()
+
+	unlock, err = NewDirWithPlatform(tmpDirPath, linuxPlatform).lockPackage(context.Background(), nullProvider, version)
+	if err != nil {
+		t.Fatalf("failed to acquire a released lock: %s", err)
+	}
+	unlock()
+}
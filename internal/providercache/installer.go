@@ -103,6 +103,10 @@ func (i *Installer) ProviderSource() getproviders.Source {
 // The global cache directory for an installer must never be the same as its
 // target directory, and must not be used as one of its provider sources.
 // If these overlap then undefined behavior will result.
+//
+// The same global cache directory may be shared by several installers, in
+// the same process or in separate processes, at the same time. Each package
+// is locked while it is being installed or linked from the cache.
 func (i *Installer) SetGlobalCacheDir(cacheDir *Dir) {
 	// A little safety check to catch straightforward mistakes where the
 	// directories overlap. Better to panic early than to do
@@ -371,6 +375,20 @@ NeedProvider:
 		if i.globalCacheDir != nil {
 			// Step 3a: If our global cache already has this version available then
 			// we'll just link it in.
+			//
+			// The global cache may be shared with other concurrent OpenTofu
+			// processes, so we hold the lock for the package while we check
+			// and link it, and we look for it only once we have the lock
+			// in case another process just installed or replaced it.
+			unlockCached, err := i.globalCacheDir.lockPackage(ctx, provider, version)
+			if err != nil {
+				errs[provider] = err
+				if cb := evts.LinkFromCacheFailure; cb != nil {
+					cb(provider, version, err)
+				}
+				continue
+			}
+			i.globalCacheDir.metaCache = nil
 			if cached := i.globalCacheDir.ProviderVersion(provider, version); cached != nil {
 				// An existing cache entry is only an acceptable choice
 				// if there is already a lock file entry for this provider
@@ -428,11 +446,23 @@ NeedProvider:
 					// there's no problem, but in that case we wouldn't enter
 					// this branch because acceptablePackage would already be
 					// true from the check above.
-					log.Printf(
-						"[WARN] plugin_cache_may_break_dependency_lock_file: Using global cache dir package for %s v%s even though it doesn't match this configuration's dependency lock file",
-						provider.String(), version.String(),
-					)
-					acceptablePackage = true
+					//
+					// Without any checksums to verify it against, we'll
+					// still only accept the cached package if it's exactly
+					// what was installed there, so that we replace rather
+					// than reuse a package that was modified or only
+					// partially written. This also replaces packages cached
+					// by older versions of OpenTofu, which didn't record
+					// their checksums.
+					if err := checkInstalledHash(cached); err != nil {
+						log.Printf("[WARN] Not using %s v%s from the global cache dir: %s", provider.String(), version.String(), err)
+					} else {
+						log.Printf(
+							"[WARN] plugin_cache_may_break_dependency_lock_file: Using global cache dir package for %s v%s even though it doesn't match this configuration's dependency lock file",
+							provider.String(), version.String(),
+						)
+						acceptablePackage = true
+					}
 				}
 
 				// TODO: Should we emit an event through the events object
@@ -446,6 +476,7 @@ NeedProvider:
 						cb(provider, version, i.globalCacheDir.baseDir)
 					}
 					if _, err := cached.ExecutableFile(); err != nil {
+						unlockCached()
 						err := fmt.Errorf("provider binary not found: %w", err)
 						errs[provider] = err
 						if cb := evts.LinkFromCacheFailure; cb != nil {
@@ -455,6 +486,7 @@ NeedProvider:
 					}
 
 					err := i.targetDir.LinkFromOtherCache(cached, preferredHashes)
+					unlockCached()
 					if err != nil {
 						errs[provider] = err
 						if cb := evts.LinkFromCacheFailure; cb != nil {
@@ -536,6 +568,7 @@ NeedProvider:
 					continue // Don't need to do full install, then.
 				}
 			}
+			unlockCached()
 		}
 
 		// Step 3b: Get the package metadata for the selected version from our
@@ -609,7 +642,20 @@ NeedProvider:
 			// series here (and that's why we use FetchPackageFailure below).
 			// We also don't do a hash check here because we already did that
 			// as part of the installTo.InstallPackage call above.
-			err := linkTo.LinkFromOtherCache(new, nil)
+			//
+			// As in step 3a, we hold the lock for the package in the global
+			// cache while we link it, so that no other process can replace
+			// it while we're reading it.
+			unlockCached, err := installTo.lockPackage(ctx, provider, version)
+			if err != nil {
+				errs[provider] = err
+				if cb := evts.FetchPackageFailure; cb != nil {
+					cb(provider, version, err)
+				}
+				continue
+			}
+			err = linkTo.LinkFromOtherCache(new, nil)
+			unlockCached()
 			if err != nil {
 				errs[provider] = err
 				if cb := evts.FetchPackageFailure; cb != nil {
@@ -689,21 +735,32 @@ NeedProvider:
 			signedHashes = append(signedHashes, meta.AcceptableHashes()...)
 		}
 
+		localHashes := []getproviders.Hash{newHash}
+		if _, ok := meta.Location.(getproviders.PackageOCIBlob); ok && authResult != nil {
+			// The digest of an OCI artifact layer is the checksum of the
+			// archive we just downloaded and verified against it, so it's
+			// as trustworthy as the hash we calculated ourselves.
+			localHashes = append(localHashes, meta.AcceptableHashes()...)
+		}
+
 		var newHashes []getproviders.Hash
-		newHashes = append(newHashes, newHash)
+		newHashes = append(newHashes, localHashes...)
 		newHashes = append(newHashes, priorHashes...)
 		newHashes = append(newHashes, signedHashes...)
 
 		locks.SetProvider(provider, version, reqs[provider], newHashes)
 		if cb := evts.ProvidersLockUpdated; cb != nil {
-			// newHash and priorHashes are already sorted.
-			// But we do need to sort signedHashes so we can reason about it
-			// sensibly.
+			// priorHashes are already sorted.
+			// But we do need to sort localHashes and signedHashes so we can
+			// reason about them sensibly.
+			sort.Slice(localHashes, func(i, j int) bool {
+				return string(localHashes[i]) < string(localHashes[j])
+			})
 			sort.Slice(signedHashes, func(i, j int) bool {
 				return string(signedHashes[i]) < string(signedHashes[j])
 			})
 
-			cb(provider, version, []getproviders.Hash{newHash}, signedHashes, priorHashes)
+			cb(provider, version, localHashes, signedHashes, priorHashes)
 		}
 
 		if cb := evts.FetchPackageSuccess; cb != nil {
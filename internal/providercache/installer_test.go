@@ -10,6 +10,7 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -24,6 +25,7 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/oci"
 )
 
 func TestEnsureProviderVersions(t *testing.T) {
@@ -951,6 +953,143 @@ func TestEnsureProviderVersions(t *testing.T) {
 				}
 			},
 		},
+		"successful initial install of one provider through a global cache with a partially-written entry while allowing the cache to break the lock file": {
+			Source: getproviders.NewMockSource(
+				[]getproviders.PackageMeta{
+					{
+						Provider:       beepProvider,
+						Version:        getproviders.MustParseVersion("2.1.0"),
+						TargetPlatform: fakePlatform,
+						Location:       beepProviderDir,
+					},
+				},
+				nil,
+			),
+			LockFile: `
+				# (intentionally empty)
+			`,
+			Prepare: func(t *testing.T, inst *Installer, dir *Dir) {
+				globalCacheDirPath := tmpDir(t)
+				globalCacheDir := NewDirWithPlatform(globalCacheDirPath, fakePlatform)
+
+				// This simulates a package that an earlier process was
+				// interrupted while writing, without any record of its
+				// checksum, which the installer must replace rather than
+				// reuse even though it can't check it against the lock file.
+				packageDir := filepath.Join(globalCacheDirPath, "example.com/foo/beep/2.1.0/bleep_bloop")
+				if err := os.MkdirAll(packageDir, 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(packageDir, "terraform-provider-beep"), []byte("trunc"), 0755); err != nil {
+					t.Fatal(err)
+				}
+
+				inst.SetGlobalCacheDir(globalCacheDir)
+				inst.SetGlobalCacheDirMayBreakDependencyLockFile(true)
+			},
+			Mode: InstallNewProvidersOnly,
+			Reqs: getproviders.Requirements{
+				beepProvider: getproviders.MustParseVersionConstraints(">= 2.0.0"),
+			},
+			Check: func(t *testing.T, dir *Dir, locks *depsfile.Locks) {
+				gotLock := locks.Provider(beepProvider)
+				wantLock := depsfile.NewProviderLock(
+					beepProvider,
+					getproviders.MustParseVersion("2.1.0"),
+					getproviders.MustParseVersionConstraints(">= 2.0.0"),
+					[]getproviders.Hash{beepProviderHash},
+				)
+				if diff := cmp.Diff(wantLock, gotLock, depsfile.ProviderLockComparer); diff != "" {
+					t.Errorf("wrong lock entry\n%s", diff)
+				}
+
+				// The target directory links to the global cache entry, so
+				// this also checks that the entry was repaired.
+				entry := dir.ProviderLatestVersion(beepProvider)
+				if entry == nil {
+					t.Fatalf("no cache entry for %s", beepProvider)
+				}
+				if got, err := entry.Hash(); err != nil {
+					t.Errorf("failed to hash cache entry: %s", err)
+				} else if got != beepProviderHash {
+					t.Errorf("wrong cache entry hash %s; want %s", got, beepProviderHash)
+				}
+			},
+			WantEvents: func(inst *Installer, dir *Dir) map[addrs.Provider][]*testInstallerEventLogItem {
+				return map[addrs.Provider][]*testInstallerEventLogItem{
+					noProvider: {
+						{
+							Event: "PendingProviders",
+							Args: map[addrs.Provider]getproviders.VersionConstraints{
+								beepProvider: getproviders.MustParseVersionConstraints(">= 2.0.0"),
+							},
+						},
+						{
+							Event: "ProvidersFetched",
+							Args: map[addrs.Provider]*getproviders.PackageAuthenticationResult{
+								beepProvider: nil,
+							},
+						},
+					},
+					beepProvider: {
+						{
+							Event:    "QueryPackagesBegin",
+							Provider: beepProvider,
+							Args: struct {
+								Constraints string
+								Locked      bool
+							}{">= 2.0.0", false},
+						},
+						{
+							Event:    "QueryPackagesSuccess",
+							Provider: beepProvider,
+							Args:     "2.1.0",
+						},
+						{
+							Event:    "FetchPackageMeta",
+							Provider: beepProvider,
+							Args:     "2.1.0",
+						},
+						{
+							Event:    "FetchPackageBegin",
+							Provider: beepProvider,
+							Args: struct {
+								Version  string
+								Location getproviders.PackageLocation
+							}{"2.1.0", beepProviderDir},
+						},
+						{
+							Event:    "ProvidersLockUpdated",
+							Provider: beepProvider,
+							Args: struct {
+								Version string
+								Local   []getproviders.Hash
+								Signed  []getproviders.Hash
+								Prior   []getproviders.Hash
+							}{
+								"2.1.0",
+								[]getproviders.Hash{"h1:2y06Ykj0FRneZfGCTxI9wRTori8iB7ZL5kQ6YyEnh84="},
+								nil,
+								nil,
+							},
+						},
+						{
+							Event:    "FetchPackageSuccess",
+							Provider: beepProvider,
+							Args: struct {
+								Version    string
+								LocalDir   string
+								AuthResult string
+							}{
+								"2.1.0",
+								filepath.Join(dir.BasePath(), "example.com/foo/beep/2.1.0/bleep_bloop"),
+								"unauthenticated",
+							},
+						},
+					},
+				}
+			},
+		},
 		"failing install of one provider through a warm global cache with an incorrect locked checksum while allowing the cache to break the lock file": {
 			Source: getproviders.NewMockSource(
 				[]getproviders.PackageMeta{
@@ -2367,6 +2506,65 @@ func TestEnsureProviderVersions_local_source(t *testing.T) {
 	}
 }
 
+func TestEnsureProviderVersions_ociSource(t *testing.T) {
+	archivePath := "testdata/provider-null_2.1.0_linux_amd64.zip"
+	archive, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := oci.NewTestRegistry(t)
+	layer := registry.PushBlob("application/vnd.opentofu.provider.v1.zip", archive)
+	manifest := registry.PushManifest("hashicorp/null", "", &oci.Manifest{
+		SchemaVersion: 2,
+		MediaType:     oci.MediaTypeImageManifest,
+		Layers:        []oci.Descriptor{layer},
+	})
+	manifest.Platform = &oci.Platform{OS: "linux", Architecture: "amd64"}
+	registry.PushManifest("hashicorp/null", "2.1.0", &oci.Manifest{
+		SchemaVersion: 2,
+		MediaType:     oci.MediaTypeImageIndex,
+		Manifests:     []oci.Descriptor{manifest},
+	})
+	source, err := getproviders.NewOCIMirrorSource(registry.Host()+"/${namespace}/${type}", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	platform := getproviders.Platform{OS: "linux", Arch: "amd64"}
+	dir := NewDirWithPlatform(t.TempDir(), platform)
+	installer := NewInstaller(dir, source)
+
+	provider := addrs.MustParseProviderSourceString("hashicorp/null")
+	reqs := getproviders.Requirements{
+		provider: getproviders.MustParseVersionConstraints("2.1.0"),
+	}
+	newLocks, err := installer.EnsureProviderVersions(context.Background(), depsfile.NewLocks(), reqs, InstallNewProvidersOnly)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The lock records the layer digest as a "zh:" hash, along with the
+	// "h1:" hash of the installed package.
+	h1, err := getproviders.PackageHashV1(getproviders.PackageLocalArchive(archivePath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantLocks := map[addrs.Provider]*depsfile.ProviderLock{
+		provider: depsfile.NewProviderLock(
+			provider,
+			getproviders.MustParseVersion("2.1.0"),
+			getproviders.MustParseVersionConstraints("2.1.0"),
+			[]getproviders.Hash{
+				h1,
+				getproviders.HashSchemeZip.New(strings.TrimPrefix(layer.Digest, "sha256:")),
+			},
+		),
+	}
+	if diff := cmp.Diff(wantLocks, newLocks.AllProviders(), depsfile.ProviderLockComparer); diff != "" {
+		t.Errorf("wrong locks\n%s", diff)
+	}
+}
+
 // This test only verifies protocol errors and does not try for successful
 // installation (at the time of writing, the test files aren't signed so the
 // signature verification fails); that's left to the e2e tests.
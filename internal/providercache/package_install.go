@@ -72,7 +72,36 @@ func installFromHTTPURL(ctx context.Context, meta getproviders.PackageMeta, targ
 		return nil, err
 	}
 
-	archiveFilename := f.Name()
+	return installFromDownloadedArchive(ctx, meta, f.Name(), targetDir, allowedHashes)
+}
+
+func installFromOCIBlob(ctx context.Context, meta getproviders.PackageMeta, targetDir string, allowedHashes []getproviders.Hash) (*getproviders.PackageAuthenticationResult, error) {
+	blob := meta.Location.(getproviders.PackageOCIBlob)
+
+	// As with installFromHTTPURL, we fetch the archive into a temporary file
+	// and then delegate to installFromLocalArchive to extract it.
+	f, err := os.CreateTemp("", "terraform-provider")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open temporary file to download from %s: %w", blob, err)
+	}
+	defer f.Close()
+	defer os.Remove(f.Name())
+
+	if err := blob.Fetch(ctx, f); err != nil {
+		if ctx.Err() == context.Canceled {
+			return nil, fmt.Errorf("provider download was interrupted")
+		}
+		return nil, fmt.Errorf("failed to download %s: %w", blob, err)
+	}
+
+	return installFromDownloadedArchive(ctx, meta, f.Name(), targetDir, allowedHashes)
+}
+
+// installFromDownloadedArchive authenticates the archive that was downloaded
+// from the location in the given meta to the given local file, and then
+// extracts it into the target directory.
+func installFromDownloadedArchive(ctx context.Context, meta getproviders.PackageMeta, archiveFilename string, targetDir string, allowedHashes []getproviders.Hash) (*getproviders.PackageAuthenticationResult, error) {
+	var err error
 	localLocation := getproviders.PackageLocalArchive(archiveFilename)
 
 	var authResult *getproviders.PackageAuthenticationResult
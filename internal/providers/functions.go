@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package providers
+
+import (
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// FunctionSpec describes the signature of a function exported by a provider.
+type FunctionSpec struct {
+	// Parameters are the positional parameters of the function, in order.
+	Parameters []FunctionParameterSpec
+
+	// VariadicParameter, if set, describes the parameter that accepts any
+	// number of additional arguments after the positional parameters.
+	VariadicParameter *FunctionParameterSpec
+
+	// Return is the type of the function result.
+	Return cty.Type
+
+	Summary            string
+	Description        string
+	DescriptionKind    configschema.StringKind
+	DeprecationMessage string
+}
+
+// FunctionParameterSpec describes a single parameter of a function exported by
+// a provider.
+type FunctionParameterSpec struct {
+	Name string
+	Type cty.Type
+
+	// AllowNullValue indicates that the function can accept a null argument
+	// for this parameter. Otherwise the caller rejects null arguments before
+	// the function is called.
+	AllowNullValue bool
+
+	// AllowUnknownValues indicates that the function can accept an unknown
+	// argument for this parameter. Otherwise the caller returns an unknown
+	// result without calling the function.
+	AllowUnknownValues bool
+
+	Description     string
+	DescriptionKind configschema.StringKind
+}
+
+type GetFunctionsResponse struct {
+	// Functions maps the function name to that function's signature.
+	Functions map[string]FunctionSpec
+
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
+type CallFunctionRequest struct {
+	// Name is the name of the function to call, without any namespace prefix.
+	Name string
+
+	// Arguments are the arguments to the function, in the order of the
+	// parameters, followed by any variadic arguments.
+	Arguments []cty.Value
+}
+
+type CallFunctionResponse struct {
+	// Result is the value returned by the function.
+	Result cty.Value
+
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
+// AsFunction returns a cty function with the signature described by the
+// receiver, whose implementation delegates to the given call function.
+//
+// Arguments are checked against the parameter types, and null or unknown
+// arguments are handled according to the parameter settings, before call is
+// ever reached.
+func (spec FunctionSpec) AsFunction(call func(args []cty.Value) (cty.Value, error)) function.Function {
+	params := make([]function.Parameter, len(spec.Parameters))
+	for i, param := range spec.Parameters {
+		params[i] = param.asParameter()
+	}
+
+	var varParam *function.Parameter
+	if spec.VariadicParameter != nil {
+		p := spec.VariadicParameter.asParameter()
+		varParam = &p
+	}
+
+	description := spec.Description
+	if description == "" {
+		description = spec.Summary
+	}
+
+	return function.New(&function.Spec{
+		Description: description,
+		Params:      params,
+		VarParam:    varParam,
+		Type:        function.StaticReturnType(spec.Return),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return call(args)
+		},
+	})
+}
+
+func (param FunctionParameterSpec) asParameter() function.Parameter {
+	return function.Parameter{
+		Name:             param.Name,
+		Description:      param.Description,
+		Type:             param.Type,
+		AllowNull:        param.AllowNullValue,
+		AllowUnknown:     param.AllowUnknownValues,
+		AllowDynamicType: true,
+	}
+}
@@ -4,6 +4,8 @@
 package providers
 
 import (
+	"context"
+
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/configs/configschema"
@@ -67,13 +69,37 @@ type Interface interface {
 	// ImportResourceState requests that the given resource be imported.
 	ImportResourceState(ImportResourceStateRequest) ImportResourceStateResponse
 
+	// MoveResourceState requests that the given object of a resource type,
+	// possibly managed by another provider, be converted into an object of
+	// one of this provider's resource types. This is only supported by
+	// providers that declare the MoveResourceState server capability.
+	MoveResourceState(MoveResourceStateRequest) MoveResourceStateResponse
+
 	// ReadDataSource returns the data source's current state.
 	ReadDataSource(ReadDataSourceRequest) ReadDataSourceResponse
 
+	// GetFunctions returns the full list of functions exported by the
+	// provider, keyed by function name.
+	GetFunctions() GetFunctionsResponse
+
+	// CallFunction requests that the given provider-defined function be
+	// called with the given arguments.
+	CallFunction(CallFunctionRequest) CallFunctionResponse
+
 	// Close shuts down the plugin process if applicable.
 	Close() error
 }
 
+// Traceable is an optional interface implemented by providers that record
+// trace spans for their calls.
+type Traceable interface {
+	// WithTraceContext returns a view of the provider that records the
+	// spans for its calls as children of the span in the given context.
+	// The result shares the underlying plugin with the receiver, and so
+	// it must not be closed separately.
+	WithTraceContext(ctx context.Context) Interface
+}
+
 // GetProviderSchemaResponse is the return type for GetProviderSchema, and
 // should only be used when handling a value for that method. The handling of
 // of schemas in any other context should always use ProviderSchema, so that
@@ -92,6 +118,9 @@ type GetProviderSchemaResponse struct {
 	// DataSources maps the data source name to that data source's schema.
 	DataSources map[string]Schema
 
+	// Functions maps the function name to that function's signature.
+	Functions map[string]FunctionSpec
+
 	// Diagnostics contains any warnings or errors from the method call.
 	Diagnostics tfdiags.Diagnostics
 
@@ -126,6 +155,11 @@ type ServerCapabilities struct {
 	// In other words, the providers for which GetProviderSchemaOptional is false
 	// require their schema to be read after EVERY instantiation to function normally.
 	GetProviderSchemaOptional bool
+
+	// MoveResourceState signals that this provider supports the
+	// MoveResourceState method, and so can convert objects of other
+	// resource types into its own resource types.
+	MoveResourceState bool
 }
 
 type ValidateProviderConfigRequest struct {
@@ -398,6 +432,44 @@ func (ir ImportedResource) AsInstanceObject() *states.ResourceInstanceObject {
 	}
 }
 
+type MoveResourceStateRequest struct {
+	// SourceProviderAddress is the address of the provider that managed the
+	// object being moved, such as "registry.opentofu.org/hashicorp/aws".
+	SourceProviderAddress string
+
+	// SourceTypeName is the name of the resource type of the object being
+	// moved.
+	SourceTypeName string
+
+	// SourceSchemaVersion is the version of the schema of the source resource
+	// type that created the object being moved.
+	SourceSchemaVersion int64
+
+	// SourceStateJSON is the object being moved, in the raw JSON encoding
+	// stored in the state. Because the source schema may belong to another
+	// provider, the receiving provider must decode it itself.
+	SourceStateJSON []byte
+
+	// SourcePrivate is the private data stored with the object being moved.
+	SourcePrivate []byte
+
+	// TargetTypeName is the name of the resource type the object is being
+	// moved to.
+	TargetTypeName string
+}
+
+type MoveResourceStateResponse struct {
+	// TargetState is the moved object, conforming to the schema of the
+	// target resource type.
+	TargetState cty.Value
+
+	// TargetPrivate is the private data to store with the moved object.
+	TargetPrivate []byte
+
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
 type ReadDataSourceRequest struct {
 	// TypeName is the name of the data source type to Read.
 	TypeName string
@@ -26,9 +26,16 @@ import (
 // a separate concern applied to the configuration, because validity of
 // moves is always dependent only on the configuration, not on the state.
 //
+// Objects can only move to a resource of a different type if the provider
+// of the destination resource can convert them into its own resource type,
+// as reported by the given CrossTypeMoveFunc, which may be nil if no
+// provider supports that. Other moves across resource types are ignored in
+// the same way as other invalid moves. The objects are then converted by
+// the provider while planning the destination resource.
+//
 // ApplyMoves expects exclusive access to the given state while it's running.
 // Don't read or write any part of the state structure until ApplyMoves returns.
-func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
+func ApplyMoves(stmts []MoveStatement, state *states.State, crossTypeMoves CrossTypeMoveFunc) MoveResults {
 	ret := makeMoveResults()
 
 	if len(stmts) == 0 {
@@ -70,17 +77,19 @@ func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
 
 	log.Printf("[TRACE] refactoring.ApplyMoves: Processing 'moved' statements in the configuration\n%s", logging.Indent(g.String()))
 
-	recordOldAddr := func(oldAddr, newAddr addrs.AbsResourceInstance) {
+	recordOldAddr := func(oldAddr, newAddr addrs.AbsResourceInstance, provider addrs.AbsProviderConfig) {
 		if prevMove, exists := ret.Changes.GetOk(oldAddr); exists {
 			// If the old address was _already_ the result of a move then
 			// we'll replace that entry so that our results summarize a chain
 			// of moves into a single entry.
 			ret.Changes.Remove(oldAddr)
 			oldAddr = prevMove.From
+			provider = prevMove.FromProvider
 		}
 		ret.Changes.Put(newAddr, MoveSuccess{
-			From: oldAddr,
-			To:   newAddr,
+			From:         oldAddr,
+			To:           newAddr,
+			FromProvider: provider,
 		})
 	}
 	recordBlockage := func(newAddr, wantedAddr addrs.AbsMoveable) {
@@ -121,7 +130,7 @@ func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
 						for key := range rs.Instances {
 							oldInst := relAddr.Instance(key).Absolute(modAddr)
 							newInst := relAddr.Instance(key).Absolute(newAddr)
-							recordOldAddr(oldInst, newInst)
+							recordOldAddr(oldInst, newInst, rs.ProviderConfig)
 						}
 					}
 
@@ -152,10 +161,15 @@ func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
 							continue
 						}
 
+						if !crossTypeMoveAllowed(rAddr, newAddr, crossTypeMoves) {
+							log.Printf("[WARN] Skipped moving %s to %s, because the destination provider can't convert objects of another resource type", rAddr, newAddr)
+							continue
+						}
+
 						for key := range rs.Instances {
 							oldInst := rAddr.Instance(key)
 							newInst := newAddr.Instance(key)
-							recordOldAddr(oldInst, newInst)
+							recordOldAddr(oldInst, newInst, rs.ProviderConfig)
 						}
 						state.MoveAbsResource(rAddr, newAddr)
 						continue
@@ -174,7 +188,12 @@ func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
 								continue
 							}
 
-							recordOldAddr(iAddr, newAddr)
+							if !crossTypeMoveAllowed(iAddr, newAddr, crossTypeMoves) {
+								log.Printf("[WARN] Skipped moving %s to %s, because the destination provider can't convert objects of another resource type", iAddr, newAddr)
+								continue
+							}
+
+							recordOldAddr(iAddr, newAddr, rs.ProviderConfig)
 
 							state.MoveAbsResourceInstance(iAddr, newAddr)
 							continue
@@ -190,6 +209,25 @@ func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
 	return ret
 }
 
+// CrossTypeMoveFunc reports whether the provider of the given resource can
+// convert objects of other resource types into objects of the resource's own
+// type, so that they can move into it from a resource of a different type.
+type CrossTypeMoveFunc func(addr addrs.ConfigResource) bool
+
+// crossTypeMoveAllowed returns true if an object can move from the given
+// source address to the given destination address, which is always the case
+// unless they belong to resources of different types.
+func crossTypeMoveAllowed(from, to addrs.AbsMoveable, crossTypeMoves CrossTypeMoveFunc) bool {
+	if !resourceTypesDiffer(from, to) {
+		return true
+	}
+	if crossTypeMoves == nil {
+		return false
+	}
+	toRes := to.(addrs.AbsMoveableResource).AffectedAbsResource()
+	return crossTypeMoves(toRes.Config())
+}
+
 // buildMoveStatementGraph constructs a dependency graph of the given move
 // statements, where the nodes are all pointers to statements in the given
 // slice and the edges represent either chaining or nesting relationships.
@@ -317,6 +355,16 @@ func makeMoveResults() MoveResults {
 type MoveSuccess struct {
 	From addrs.AbsResourceInstance
 	To   addrs.AbsResourceInstance
+
+	// FromProvider is the provider configuration that managed the object
+	// at its original address, before any moves.
+	FromProvider addrs.AbsProviderConfig
+}
+
+// ChangesType returns true if the object moved to a resource of a different
+// type, and so must be converted by the provider of its new resource type.
+func (s MoveSuccess) ChangesType() bool {
+	return s.From.Resource.Resource.Type != s.To.Resource.Resource.Type
 }
 
 type MoveBlocked struct {
@@ -81,8 +81,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("foo.to"), MoveSuccess{
-						From: mustParseInstAddr("foo.from"),
-						To:   mustParseInstAddr("foo.to"),
+						From:         mustParseInstAddr("foo.from"),
+						To:           mustParseInstAddr("foo.to"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -108,8 +109,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("foo.to[0]"), MoveSuccess{
-						From: mustParseInstAddr("foo.from[0]"),
-						To:   mustParseInstAddr("foo.to[0]"),
+						From:         mustParseInstAddr("foo.from[0]"),
+						To:           mustParseInstAddr("foo.to[0]"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -136,8 +138,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("foo.to"), MoveSuccess{
-						From: mustParseInstAddr("foo.from"),
-						To:   mustParseInstAddr("foo.to"),
+						From:         mustParseInstAddr("foo.from"),
+						To:           mustParseInstAddr("foo.to"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -164,8 +167,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("module.boo.foo.to[0]"), MoveSuccess{
-						From: mustParseInstAddr("foo.from[0]"),
-						To:   mustParseInstAddr("module.boo.foo.to[0]"),
+						From:         mustParseInstAddr("foo.from[0]"),
+						To:           mustParseInstAddr("module.boo.foo.to[0]"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -192,8 +196,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("module.bar[0].foo.to[0]"), MoveSuccess{
-						From: mustParseInstAddr("module.boo.foo.from[0]"),
-						To:   mustParseInstAddr("module.bar[0].foo.to[0]"),
+						From:         mustParseInstAddr("module.boo.foo.from[0]"),
+						To:           mustParseInstAddr("module.bar[0].foo.to[0]"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -228,12 +233,14 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("module.bar.foo.from"), MoveSuccess{
-						From: mustParseInstAddr("module.boo.foo.from"),
-						To:   mustParseInstAddr("module.bar.foo.from"),
+						From:         mustParseInstAddr("module.boo.foo.from"),
+						To:           mustParseInstAddr("module.bar.foo.from"),
+						FromProvider: providerAddr,
 					}),
 					addrs.MakeMapElem(mustParseInstAddr("module.bar.module.hoo.foo.from"), MoveSuccess{
-						From: mustParseInstAddr("module.boo.module.hoo.foo.from"),
-						To:   mustParseInstAddr("module.bar.module.hoo.foo.from"),
+						From:         mustParseInstAddr("module.boo.module.hoo.foo.from"),
+						To:           mustParseInstAddr("module.bar.module.hoo.foo.from"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -261,8 +268,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("module.bar[0].foo.from[0]"), MoveSuccess{
-						From: mustParseInstAddr("module.boo.foo.from[0]"),
-						To:   mustParseInstAddr("module.bar[0].foo.from[0]"),
+						From:         mustParseInstAddr("module.boo.foo.from[0]"),
+						To:           mustParseInstAddr("module.bar[0].foo.from[0]"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -290,8 +298,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("module.bar[0].foo.to[0]"), MoveSuccess{
-						From: mustParseInstAddr("module.boo.foo.from[0]"),
-						To:   mustParseInstAddr("module.bar[0].foo.to[0]"),
+						From:         mustParseInstAddr("module.boo.foo.from[0]"),
+						To:           mustParseInstAddr("module.bar[0].foo.to[0]"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -319,8 +328,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("module.bar[0].foo.to[0]"), MoveSuccess{
-						From: mustParseInstAddr("module.boo.foo.from[0]"),
-						To:   mustParseInstAddr("module.bar[0].foo.to[0]"),
+						From:         mustParseInstAddr("module.boo.foo.from[0]"),
+						To:           mustParseInstAddr("module.bar[0].foo.to[0]"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -473,8 +483,9 @@ func TestApplyMoves(t *testing.T) {
 			MoveResults{
 				Changes: addrs.MakeMap(
 					addrs.MakeMapElem(mustParseInstAddr("module.bar[0].foo.to"), MoveSuccess{
-						From: mustParseInstAddr("module.boo.foo.from"),
-						To:   mustParseInstAddr("module.bar[0].foo.to"),
+						From:         mustParseInstAddr("module.boo.foo.from"),
+						To:           mustParseInstAddr("module.bar[0].foo.to"),
+						FromProvider: providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -512,10 +523,12 @@ func TestApplyMoves(t *testing.T) {
 					addrs.MakeMapElem(mustParseInstAddr("module.boo.foo.from"), MoveSuccess{
 						mustParseInstAddr("foo.from"),
 						mustParseInstAddr("module.boo.foo.from"),
+						providerAddr,
 					}),
 					addrs.MakeMapElem(mustParseInstAddr("module.boo.foo.to"), MoveSuccess{
 						mustParseInstAddr("module.bar[0].foo.to"),
 						mustParseInstAddr("module.boo.foo.to"),
+						providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -555,10 +568,12 @@ func TestApplyMoves(t *testing.T) {
 					addrs.MakeMapElem(mustParseInstAddr("module.bar[0].foo.to"), MoveSuccess{
 						mustParseInstAddr("foo.from"),
 						mustParseInstAddr("module.bar[0].foo.to"),
+						providerAddr,
 					}),
 					addrs.MakeMapElem(mustParseInstAddr("module.bar[0].bar.to"), MoveSuccess{
 						mustParseInstAddr("bar.from"),
 						mustParseInstAddr("module.bar[0].bar.to"),
+						providerAddr,
 					}),
 				),
 				Blocked: emptyResults.Blocked,
@@ -597,6 +612,7 @@ func TestApplyMoves(t *testing.T) {
 					addrs.MakeMapElem(mustParseInstAddr("module.boo.foo.from"), MoveSuccess{
 						mustParseInstAddr("module.bar[0].foo.from"),
 						mustParseInstAddr("module.boo.foo.from"),
+						providerAddr,
 					}),
 				),
 				Blocked: addrs.MakeMap(
@@ -627,7 +643,7 @@ func TestApplyMoves(t *testing.T) {
 			t.Logf("resource instances in prior state:\n%s", spew.Sdump(allResourceInstanceAddrsInState(test.State)))
 
 			state := test.State.DeepCopy() // don't modify the test case in-place
-			gotResults := ApplyMoves(test.Stmts, state)
+			gotResults := ApplyMoves(test.Stmts, state, nil)
 
 			if diff := cmp.Diff(test.WantResults, gotResults); diff != "" {
 				t.Errorf("wrong results\n%s", diff)
@@ -641,6 +657,71 @@ func TestApplyMoves(t *testing.T) {
 	}
 }
 
+func TestApplyMoves_crossType(t *testing.T) {
+	providerAddr := addrs.AbsProviderConfig{
+		Module:   addrs.RootModule,
+		Provider: addrs.MustParseProviderSourceString("example.com/foo/bar"),
+	}
+
+	fromAddr := addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "foo", "from", addrs.NoKey)
+	toAddr := addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "bar", "to", addrs.NoKey)
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			fromAddr,
+			&states.ResourceInstanceObjectSrc{
+				Status:    states.ObjectReady,
+				AttrsJSON: []byte(`{}`),
+			},
+			providerAddr,
+		)
+	})
+	stmts := []MoveStatement{
+		testMoveStatement(t, "", "foo.from", "bar.to"),
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		state := state.DeepCopy()
+		results := ApplyMoves(stmts, state, func(addrs.ConfigResource) bool {
+			return false
+		})
+
+		if results.Changes.Len() != 0 {
+			t.Errorf("unexpected move results: %s", spew.Sdump(results.Changes.Values()))
+		}
+		gotInstAddrs := allResourceInstanceAddrsInState(state)
+		if diff := cmp.Diff([]string{`foo.from`}, gotInstAddrs); diff != "" {
+			t.Errorf("wrong resource instances in final state\n%s", diff)
+		}
+	})
+
+	t.Run("supported", func(t *testing.T) {
+		state := state.DeepCopy()
+		results := ApplyMoves(stmts, state, func(addr addrs.ConfigResource) bool {
+			return addr.Resource.Type == "bar"
+		})
+
+		want := MoveSuccess{
+			From:         fromAddr,
+			To:           toAddr,
+			FromProvider: providerAddr,
+		}
+		got, ok := results.Changes.GetOk(toAddr)
+		if !ok {
+			t.Fatalf("no move result for %s", toAddr)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong move result\n%s", diff)
+		}
+		if !got.ChangesType() {
+			t.Errorf("move result doesn't report a change of resource type")
+		}
+		gotInstAddrs := allResourceInstanceAddrsInState(state)
+		if diff := cmp.Diff([]string{`bar.to`}, gotInstAddrs); diff != "" {
+			t.Errorf("wrong resource instances in final state\n%s", diff)
+		}
+	})
+}
+
 func testMoveStatement(t *testing.T, module string, from string, to string) MoveStatement {
 	t.Helper()
 
@@ -33,7 +33,11 @@ import (
 // construct in incorrect plan (because it'll be starting from the wrong
 // prior state) but ValidateMoves will block actually showing that invalid
 // plan to the user.
-func ValidateMoves(stmts []MoveStatement, rootCfg *configs.Config, declaredInsts instances.Set) tfdiags.Diagnostics {
+//
+// Moves between resources of different types are valid only if the
+// given CrossTypeMoveFunc reports that the provider of the destination
+// resource can convert the moved objects, which is never the case if it's nil.
+func ValidateMoves(stmts []MoveStatement, rootCfg *configs.Config, declaredInsts instances.Set, crossTypeMoves CrossTypeMoveFunc) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 
 	if len(stmts) == 0 {
@@ -159,13 +163,14 @@ func ValidateMoves(stmts []MoveStatement, rootCfg *configs.Config, declaredInsts
 				})
 			}
 
-			// Resource types must match.
-			if resourceTypesDiffer(absFrom, absTo) {
+			// Resource types must match, unless the provider of the
+			// destination resource can convert the objects.
+			if !crossTypeMoveAllowed(absFrom, absTo, crossTypeMoves) {
 				diags = diags.Append(&hcl.Diagnostic{
 					Severity: hcl.DiagError,
 					Summary:  "Resource type mismatch",
 					Detail: fmt.Sprintf(
-						"This statement declares a move from %s to %s, which is a %s of a different type.", absFrom, absTo, noun,
+						"This statement declares a move from %s to %s, which is a %s of a different type, and the provider of %s doesn't support moving objects from other resource types.", absFrom, absTo, noun, absTo,
 					),
 				})
 			}
@@ -25,8 +25,9 @@ func TestValidateMoves(t *testing.T) {
 	rootCfg, instances := loadRefactoringFixture(t, "testdata/move-validate-zoo")
 
 	tests := map[string]struct {
-		Statements []MoveStatement
-		WantError  string
+		Statements     []MoveStatement
+		CrossTypeMoves CrossTypeMoveFunc
+		WantError      string
 	}{
 		"no move statements": {
 			Statements: nil,
@@ -441,7 +442,7 @@ Each resource can have moved from only one source resource.`,
 					`other.single`,
 				),
 			},
-			WantError: `Resource type mismatch: This statement declares a move from test.nonexist1 to other.single, which is a resource of a different type.`,
+			WantError: `Resource type mismatch: This statement declares a move from test.nonexist1 to other.single, which is a resource of a different type, and the provider of other.single doesn't support moving objects from other resource types.`,
 		},
 		"resource instance type mismatch": {
 			Statements: []MoveStatement{
@@ -450,7 +451,30 @@ Each resource can have moved from only one source resource.`,
 					`other.single`,
 				),
 			},
-			WantError: `Resource type mismatch: This statement declares a move from test.nonexist1[0] to other.single, which is a resource instance of a different type.`,
+			WantError: `Resource type mismatch: This statement declares a move from test.nonexist1[0] to other.single, which is a resource instance of a different type, and the provider of other.single doesn't support moving objects from other resource types.`,
+		},
+		"resource type mismatch with provider support": {
+			Statements: []MoveStatement{
+				makeTestMoveStmt(t, ``,
+					`test.nonexist1`,
+					`other.single`,
+				),
+			},
+			CrossTypeMoves: func(addr addrs.ConfigResource) bool {
+				return addr.Resource.Type == "other"
+			},
+		},
+		"resource type mismatch without provider support": {
+			Statements: []MoveStatement{
+				makeTestMoveStmt(t, ``,
+					`test.nonexist1`,
+					`other.single`,
+				),
+			},
+			CrossTypeMoves: func(addr addrs.ConfigResource) bool {
+				return addr.Resource.Type == "test"
+			},
+			WantError: `Resource type mismatch: This statement declares a move from test.nonexist1 to other.single, which is a resource of a different type, and the provider of other.single doesn't support moving objects from other resource types.`,
 		},
 		"crossing nested statements": {
 			// overlapping nested moves will result in a cycle.
@@ -508,7 +532,7 @@ A chain of move statements must end with an address that doesn't appear in any o
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			gotDiags := ValidateMoves(test.Statements, rootCfg, instances)
+			gotDiags := ValidateMoves(test.Statements, rootCfg, instances, test.CrossTypeMoves)
 
 			switch {
 			case test.WantError != "":
@@ -538,7 +562,7 @@ func loadRefactoringFixture(t *testing.T, dir string) (*configs.Config, instance
 	defer cleanup()
 
 	inst := initwd.NewModuleInstaller(loader.ModulesDir(), loader, registry.NewClient(nil, nil))
-	_, instDiags := inst.InstallModules(context.Background(), dir, "tests", true, false, initwd.ModuleInstallHooksImpl{})
+	_, instDiags := inst.InstallModules(context.Background(), dir, "tests", true, false, initwd.ModuleInstallHooksImpl{}, configs.RootModuleCallForTesting())
 	if instDiags.HasErrors() {
 		t.Fatal(instDiags.Err())
 	}
@@ -549,7 +573,7 @@ func loadRefactoringFixture(t *testing.T, dir string) (*configs.Config, instance
 		t.Fatalf("failed to refresh modules after installation: %s", err)
 	}
 
-	rootCfg, diags := loader.LoadConfig(dir)
+	rootCfg, diags := loader.LoadConfig(dir, configs.RootModuleCallForTesting())
 	if diags.HasErrors() {
 		t.Fatalf("failed to load root module: %s", diags.Error())
 	}
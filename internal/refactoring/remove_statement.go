@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactoring
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// RemoveStatement is the fully-resolved form of a "removed" block in the
+// configuration, with its target made absolute relative to the root module.
+type RemoveStatement struct {
+	From addrs.ConfigRemovable
+
+	// Destroy is true if objects matching this statement should be
+	// destroyed, rather than just removed from the state.
+	Destroy bool
+
+	DeclRange tfdiags.SourceRange
+}
+
+// FindRemoveStatements recurses through the modules of the given
+// configuration and returns a flat set of all "removed" blocks defined within,
+// in a deterministic but undefined order.
+//
+// It also returns error diagnostics for any statement whose target is still
+// declared in the configuration, because it's contradictory to both declare
+// an object and declare that it was removed.
+func FindRemoveStatements(rootCfg *configs.Config) ([]RemoveStatement, tfdiags.Diagnostics) {
+	stmts := findRemoveStatements(rootCfg, nil)
+	diags := validateRemoveStatements(rootCfg, stmts)
+	return stmts, diags
+}
+
+func findRemoveStatements(cfg *configs.Config, into []RemoveStatement) []RemoveStatement {
+	modAddr := cfg.Path
+	for _, rc := range cfg.Module.Removed {
+		into = append(into, RemoveStatement{
+			From:      rc.From.ConfigRemovable(modAddr),
+			Destroy:   rc.Destroy,
+			DeclRange: tfdiags.SourceRangeFromHCL(rc.DeclRange),
+		})
+	}
+
+	for _, childCfg := range cfg.Children {
+		into = findRemoveStatements(childCfg, into)
+	}
+
+	return into
+}
+
+func validateRemoveStatements(rootCfg *configs.Config, stmts []RemoveStatement) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	for _, stmt := range stmts {
+		switch addr := stmt.From.(type) {
+		case addrs.ConfigResource:
+			modCfg := rootCfg.Descendent(addr.Module)
+			if modCfg == nil || modCfg.Module.ResourceByAddr(addr.Resource) == nil {
+				continue
+			}
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Removed resource block still exists",
+				Detail: fmt.Sprintf(
+					"This statement declares that %s was removed, but it is still declared in the configuration. Either remove the resource block or remove this \"removed\" block.",
+					addr,
+				),
+				Subject: stmt.DeclRange.ToHCL().Ptr(),
+			})
+		case addrs.Module:
+			if rootCfg.Descendent(addr) == nil {
+				continue
+			}
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Removed module block still exists",
+				Detail: fmt.Sprintf(
+					"This statement declares that %s was removed, but it is still declared in the configuration. Either remove the module block or remove this \"removed\" block.",
+					addr,
+				),
+				Subject: stmt.DeclRange.ToHCL().Ptr(),
+			})
+		default:
+			panic(fmt.Sprintf("unsupported removable address type %T", addr))
+		}
+	}
+
+	return diags
+}
+
+// FindRemoveStatement returns the statement from the given set that applies
+// to the given resource instance, or nil if there is none.
+//
+// If more than one statement applies, which can happen when both a module
+// and a resource inside it are declared as removed, the most specific
+// statement wins.
+func FindRemoveStatement(stmts []RemoveStatement, addr addrs.AbsResourceInstance) *RemoveStatement {
+	var ret *RemoveStatement
+	for i := range stmts {
+		stmt := &stmts[i]
+		if !stmt.From.TargetContains(addr) {
+			continue
+		}
+		if ret == nil || removeStatementDepth(stmt) > removeStatementDepth(ret) {
+			ret = stmt
+		}
+	}
+	return ret
+}
+
+// removeStatementDepth returns the number of address steps in the target of
+// the given statement, which gives an ordering of statements from least to
+// most specific among those that contain a particular resource instance.
+func removeStatementDepth(stmt *RemoveStatement) int {
+	switch addr := stmt.From.(type) {
+	case addrs.ConfigResource:
+		return len(addr.Module) + 1
+	case addrs.Module:
+		return len(addr)
+	default:
+		panic(fmt.Sprintf("unsupported removable address type %T", addr))
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactoring
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+func TestFindRemoveStatements(t *testing.T) {
+	rootCfg, _ := loadRefactoringFixture(t, "testdata/remove-statement")
+
+	stmts, diags := FindRemoveStatements(rootCfg)
+
+	var gotStmts []string
+	for _, stmt := range stmts {
+		s := stmt.From.String()
+		if stmt.Destroy {
+			s += " (destroy)"
+		}
+		gotStmts = append(gotStmts, s)
+	}
+	sort.Strings(gotStmts)
+	wantStmts := []string{
+		"foo.gone",
+		"foo.kept",
+		"module.child",
+		"module.child.foo.gone",
+		"module.child.foo.kept",
+		"module.gone (destroy)",
+		"module.gone.foo.forgotten",
+	}
+	if diff := cmp.Diff(wantStmts, gotStmts); diff != "" {
+		t.Errorf("wrong statements\n%s", diff)
+	}
+
+	var gotErrs []string
+	for _, diag := range diags {
+		gotErrs = append(gotErrs, diag.Description().Summary+": "+diag.Source().Subject.StartString())
+	}
+	sort.Strings(gotErrs)
+	wantErrs := []string{
+		"Removed module block still exists: testdata/remove-statement/main.tf:28,1",
+		"Removed resource block still exists: testdata/remove-statement/child/main.tf:8,1",
+		"Removed resource block still exists: testdata/remove-statement/main.tf:24,1",
+	}
+	if diff := cmp.Diff(wantErrs, gotErrs); diff != "" {
+		t.Errorf("wrong errors\n%s", diff)
+	}
+}
+
+func TestFindRemoveStatement(t *testing.T) {
+	rootCfg, _ := loadRefactoringFixture(t, "testdata/remove-statement")
+	stmts, _ := FindRemoveStatements(rootCfg)
+
+	resourceAddr := func(module addrs.ModuleInstance, name string) addrs.AbsResourceInstance {
+		return addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "foo",
+			Name: name,
+		}.Instance(addrs.IntKey(0)).Absolute(module)
+	}
+	gone := addrs.RootModuleInstance.Child("gone", addrs.StringKey("a"))
+
+	tests := []struct {
+		Addr addrs.AbsResourceInstance
+		Want string
+	}{
+		{resourceAddr(addrs.RootModuleInstance, "gone"), "foo.gone"},
+		{resourceAddr(addrs.RootModuleInstance, "other"), ""},
+		{resourceAddr(gone, "other"), "module.gone"},
+		{resourceAddr(gone, "forgotten"), "module.gone.foo.forgotten"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Addr.String(), func(t *testing.T) {
+			got := ""
+			if stmt := FindRemoveStatement(stmts, test.Addr); stmt != nil {
+				got = stmt.From.String()
+			}
+			if got != test.Want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+resource "foo" "kept" {
+}
+
+removed {
+  from = foo.gone
+}
+
+removed {
+  from = foo.kept
+}
@@ -0,0 +1,30 @@
+module "child" {
+  source = "./child"
+}
+
+resource "foo" "kept" {
+}
+
+removed {
+  from = foo.gone
+}
+
+removed {
+  from = module.gone
+
+  lifecycle {
+    destroy = true
+  }
+}
+
+removed {
+  from = module.gone.foo.forgotten
+}
+
+removed {
+  from = foo.kept
+}
+
+removed {
+  from = module.child
+}
@@ -23,6 +23,13 @@ func FormatValue(v cty.Value, indent int) string {
 	if v.HasMark(marks.Sensitive) {
 		return "(sensitive value)"
 	}
+	if v.HasMark(marks.Ephemeral) {
+		// The console never saves values anywhere, so ephemeral values are
+		// shown like any other.
+		unmarked, valMarks := v.Unmark()
+		delete(valMarks, marks.Ephemeral)
+		v = unmarked.WithMarks(valMarks)
+	}
 	if v.IsNull() {
 		ty := v.Type()
 		switch {
@@ -62,6 +62,13 @@ type ResourceInstanceObjectSrc struct {
 	Status              ObjectStatus
 	Dependencies        []addrs.ConfigResource
 	CreateBeforeDestroy bool
+
+	// ProviderKey is the instance key of the provider configuration instance
+	// that manages the object, if its provider configuration has for_each,
+	// or addrs.NoKey otherwise. It's recorded so that the object can still be
+	// refreshed and destroyed by the same provider instance after the
+	// resource instance has been removed from the configuration.
+	ProviderKey addrs.InstanceKey
 }
 
 // Decode unmarshals the raw representation of the object attributes. Pass the
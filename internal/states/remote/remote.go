@@ -4,6 +4,8 @@
 package remote
 
 import (
+	"time"
+
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 )
 
@@ -31,6 +33,36 @@ type ClientLocker interface {
 	statemgr.Locker
 }
 
+// ClientHistory is an optional interface that allows a remote state
+// backend to list and fetch the earlier versions of the state that its
+// storage retains, such as object versions or snapshots.
+type ClientHistory interface {
+	Client
+
+	// Versions returns the versions of the state retained by the storage,
+	// including the latest one, ordered from newest to oldest. If limit is
+	// greater than zero then only that many of the newest versions are
+	// returned. It returns statemgr.ErrHistoryUnsupported if the storage is
+	// not configured to retain earlier versions.
+	Versions(limit int) ([]*PayloadVersion, error)
+
+	// GetVersion returns the payload of the version with the given ID, or
+	// nil if there is no such version.
+	GetVersion(id string) (*Payload, error)
+}
+
+// PayloadVersion describes a version of the state retained by the remote
+// state storage.
+type PayloadVersion struct {
+	ID        string
+	Timestamp time.Time
+
+	// Serial and Lineage are those of the state in this version, if the
+	// storage records them. Lineage is empty otherwise.
+	Serial  uint64
+	Lineage string
+}
+
 // Payload is the return value from the remote state storage.
 type Payload struct {
 	MD5  []byte
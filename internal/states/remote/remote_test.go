@@ -6,13 +6,16 @@ package remote
 import (
 	"crypto/md5"
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"testing"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/encryption"
 )
 
 func TestRemoteClient_noPayload(t *testing.T) {
-	s := &State{
-		Client: nilClient{},
-	}
+	s := NewState(nilClient{}, encryption.StateEncryptionDisabled())
 	if err := s.RefreshState(); err != nil {
 		t.Fatal("error refreshing empty remote state")
 	}
@@ -131,3 +134,50 @@ func (c *mockClientForcePusher) appendLog(method string, content []byte) {
 	}
 	c.log = append(c.log, mockClientRequest{method, contentVal})
 }
+
+// mockClientHistory is like mockClient, but also retains each of the
+// snapshots written to it, to test the ClientHistory behavior.
+type mockClientHistory struct {
+	versions [][]byte
+}
+
+func (c *mockClientHistory) Get() (*Payload, error) {
+	if len(c.versions) == 0 {
+		return nil, nil
+	}
+	return c.GetVersion(fmt.Sprint(len(c.versions) - 1))
+}
+
+func (c *mockClientHistory) Put(data []byte) error {
+	c.versions = append(c.versions, data)
+	return nil
+}
+
+func (c *mockClientHistory) Delete() error {
+	c.versions = nil
+	return nil
+}
+
+// Implements remote.ClientHistory
+func (c *mockClientHistory) Versions(limit int) ([]*PayloadVersion, error) {
+	var ret []*PayloadVersion
+	for i := len(c.versions) - 1; i >= 0 && (limit <= 0 || len(ret) < limit); i-- {
+		ret = append(ret, &PayloadVersion{
+			ID:        fmt.Sprint(i),
+			Timestamp: time.Date(2024, 1, 1, i, 0, 0, 0, time.UTC),
+		})
+	}
+	return ret, nil
+}
+
+func (c *mockClientHistory) GetVersion(id string) (*Payload, error) {
+	i, err := strconv.Atoi(id)
+	if err != nil || i < 0 || i >= len(c.versions) {
+		return nil, nil
+	}
+	checksum := md5.Sum(c.versions[i])
+	return &Payload{
+		Data: c.versions[i],
+		MD5:  checksum[:],
+	}, nil
+}
@@ -5,6 +5,7 @@ package remote
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log"
 	"sync"
@@ -12,6 +13,7 @@ import (
 	uuid "github.com/hashicorp/go-uuid"
 
 	"github.com/opentofu/opentofu/internal/backend/local"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -46,11 +48,32 @@ type State struct {
 	// progress. Otherwise (by default) it will accept persistent snapshots
 	// using the default rules defined in the local backend.
 	DisableIntermediateSnapshots bool
+
+	encryption encryption.StateEncryption
+
+	spanParent statemgr.SpanParent
+}
+
+// NewState returns a state manager that stores snapshots using the given
+// client, encrypting them with the given encryption before they are sent
+// and decrypting them after they are retrieved.
+func NewState(client Client, enc encryption.StateEncryption) *State {
+	return &State{
+		Client:     client,
+		encryption: enc,
+	}
 }
 
 var _ statemgr.Full = (*State)(nil)
 var _ statemgr.Migrator = (*State)(nil)
+var _ statemgr.History = (*State)(nil)
 var _ local.IntermediateStateConditionalPersister = (*State)(nil)
+var _ statemgr.Traceable = (*State)(nil)
+
+// statemgr.Traceable impl.
+func (s *State) SetTraceContext(ctx context.Context) {
+	s.spanParent.Set(ctx)
+}
 
 // statemgr.Reader impl.
 func (s *State) State() *states.State {
@@ -82,7 +105,10 @@ func (s *State) StateForMigration() *statefile.File {
 }
 
 // statemgr.Writer impl.
-func (s *State) WriteState(state *states.State) error {
+func (s *State) WriteState(state *states.State) (err error) {
+	span := s.spanParent.StartSpan("remote", "write")
+	defer func() { statemgr.EndSpan(span, err) }()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -125,7 +151,10 @@ func (s *State) WriteStateForMigration(f *statefile.File, force bool) error {
 }
 
 // statemgr.Refresher impl.
-func (s *State) RefreshState() error {
+func (s *State) RefreshState() (err error) {
+	span := s.spanParent.StartSpan("remote", "refresh")
+	defer func() { statemgr.EndSpan(span, err) }()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	return s.refreshState()
@@ -148,7 +177,7 @@ func (s *State) refreshState() error {
 		return nil
 	}
 
-	stateFile, err := statefile.Read(bytes.NewReader(payload.Data))
+	stateFile, err := statefile.Read(bytes.NewReader(payload.Data), s.encryption)
 	if err != nil {
 		return err
 	}
@@ -166,7 +195,10 @@ func (s *State) refreshState() error {
 }
 
 // statemgr.Persister impl.
-func (s *State) PersistState(schemas *tofu.Schemas) error {
+func (s *State) PersistState(schemas *tofu.Schemas) (err error) {
+	span := s.spanParent.StartSpan("remote", "persist")
+	defer func() { statemgr.EndSpan(span, err) }()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -205,7 +237,7 @@ func (s *State) PersistState(schemas *tofu.Schemas) error {
 	f := statefile.New(s.state, s.lineage, s.serial)
 
 	var buf bytes.Buffer
-	err := statefile.Write(f, &buf)
+	err = statefile.Write(f, &buf, s.encryption)
 	if err != nil {
 		return err
 	}
@@ -236,7 +268,10 @@ func (s *State) ShouldPersistIntermediateState(info *local.IntermediateStatePers
 }
 
 // Lock calls the Client's Lock method if it's implemented.
-func (s *State) Lock(info *statemgr.LockInfo) (string, error) {
+func (s *State) Lock(info *statemgr.LockInfo) (id string, err error) {
+	span := s.spanParent.StartSpan("remote", "lock")
+	defer func() { statemgr.EndSpan(span, err) }()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -251,7 +286,10 @@ func (s *State) Lock(info *statemgr.LockInfo) (string, error) {
 }
 
 // Unlock calls the Client's Unlock method if it's implemented.
-func (s *State) Unlock(id string) error {
+func (s *State) Unlock(id string) (err error) {
+	span := s.spanParent.StartSpan("remote", "unlock")
+	defer func() { statemgr.EndSpan(span, err) }()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -282,3 +320,57 @@ func (s *State) StateSnapshotMeta() statemgr.SnapshotMeta {
 		Serial:  s.serial,
 	}
 }
+
+// SnapshotVersions is an implementation of statemgr.History, for clients that
+// implement ClientHistory.
+func (s *State) SnapshotVersions(limit int) ([]statemgr.SnapshotVersion, error) {
+	c, ok := s.Client.(ClientHistory)
+	if !ok {
+		return nil, statemgr.ErrHistoryUnsupported
+	}
+
+	versions, err := c.Versions(limit)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(versions) > limit {
+		versions = versions[:limit]
+	}
+
+	ret := make([]statemgr.SnapshotVersion, 0, len(versions))
+	for _, version := range versions {
+		ret = append(ret, statemgr.SnapshotVersion{
+			VersionID: version.ID,
+			Timestamp: version.Timestamp,
+			Serial:    version.Serial,
+			Lineage:   version.Lineage,
+		})
+	}
+	return ret, nil
+}
+
+// SnapshotVersion is an implementation of statemgr.History, for clients that
+// implement ClientHistory.
+func (s *State) SnapshotVersion(versionID string) (*statefile.File, error) {
+	c, ok := s.Client.(ClientHistory)
+	if !ok {
+		return nil, statemgr.ErrHistoryUnsupported
+	}
+
+	stateFile, err := s.readVersion(c, versionID)
+	if err == statefile.ErrNoState {
+		return nil, fmt.Errorf("there is no state snapshot with version %q", versionID)
+	}
+	return stateFile, err
+}
+
+func (s *State) readVersion(c ClientHistory, id string) (*statefile.File, error) {
+	payload, err := c.GetVersion(id)
+	if err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		return nil, statefile.ErrNoState
+	}
+	return statefile.Read(bytes.NewReader(payload.Data), s.encryption)
+}
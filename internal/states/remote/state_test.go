@@ -4,15 +4,19 @@
 package remote
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -30,9 +34,7 @@ func TestState_impl(t *testing.T) {
 }
 
 func TestStateRace(t *testing.T) {
-	s := &State{
-		Client: nilClient{},
-	}
+	s := NewState(nilClient{}, encryption.StateEncryptionDisabled())
 
 	current := states.NewState()
 
@@ -325,9 +327,7 @@ func TestStatePersist(t *testing.T) {
 	// Initial setup of state just to give us a fixed starting point for our
 	// test assertions below, or else we'd need to deal with
 	// random lineage.
-	mgr := &State{
-		Client: &mockClient{},
-	}
+	mgr := NewState(&mockClient{}, encryption.StateEncryptionDisabled())
 
 	// In normal use (during a OpenTofu operation) we always refresh and read
 	// before any writes would happen, so we'll mimic that here for realism.
@@ -387,9 +387,8 @@ func TestStatePersist(t *testing.T) {
 
 func TestState_GetRootOutputValues(t *testing.T) {
 	// Initial setup of state with outputs already defined
-	mgr := &State{
-		Client: &mockClient{
-			current: []byte(`
+	mgr := NewState(&mockClient{
+		current: []byte(`
 				{
 					"version": 4,
 					"lineage": "mock-lineage",
@@ -399,8 +398,7 @@ func TestState_GetRootOutputValues(t *testing.T) {
 					"resources": []
 				}
 			`),
-		},
-	}
+	}, encryption.StateEncryptionDisabled())
 
 	outputs, err := mgr.GetRootOutputValues()
 	if err != nil {
@@ -412,6 +410,127 @@ func TestState_GetRootOutputValues(t *testing.T) {
 	}
 }
 
+// testStateEncryption is a trivial StateEncryption that wraps snapshots in
+// a JSON object, so that tests can see whether they were encrypted.
+type testStateEncryption struct{}
+
+func (testStateEncryption) EncryptState(data []byte) ([]byte, error) {
+	return json.Marshal(map[string][]byte{"encrypted": data})
+}
+
+func (testStateEncryption) DecryptState(data []byte) ([]byte, error) {
+	var wrapper map[string][]byte
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, err
+	}
+	ret, ok := wrapper["encrypted"]
+	if !ok {
+		return nil, fmt.Errorf("state is not encrypted")
+	}
+	return ret, nil
+}
+
+func TestState_encryption(t *testing.T) {
+	client := &mockClient{}
+	mgr := NewState(client, testStateEncryption{})
+	if err := mgr.RefreshState(); err != nil {
+		t.Fatalf("failed to RefreshState: %s", err)
+	}
+
+	s := states.NewState()
+	s.RootModule().SetOutputValue("foo", cty.StringVal("bar"), false)
+	if err := mgr.WriteState(s); err != nil {
+		t.Fatalf("failed to WriteState: %s", err)
+	}
+	if err := mgr.PersistState(nil); err != nil {
+		t.Fatalf("failed to PersistState: %s", err)
+	}
+
+	// The client must only ever see the encrypted snapshot.
+	var stored map[string]interface{}
+	if err := json.Unmarshal(client.current, &stored); err != nil {
+		t.Fatalf("stored state is not valid JSON: %s", err)
+	}
+	if _, ok := stored["encrypted"]; !ok || len(stored) != 1 {
+		t.Fatalf("stored state was not encrypted:\n%s", client.current)
+	}
+
+	mgr = NewState(client, testStateEncryption{})
+	if err := mgr.RefreshState(); err != nil {
+		t.Fatalf("failed to RefreshState: %s", err)
+	}
+	outputs, err := mgr.GetRootOutputValues()
+	if err != nil {
+		t.Fatalf("failed to GetRootOutputValues: %s", err)
+	}
+	if got, want := outputs["foo"].Value, cty.StringVal("bar"); !got.RawEquals(want) {
+		t.Fatalf("wrong output value %#v; want %#v", got, want)
+	}
+
+	mgr = NewState(client, encryption.StateEncryptionDisabled())
+	if err := mgr.RefreshState(); err == nil {
+		t.Fatal("unexpected success reading encrypted state without encryption")
+	}
+}
+
+func TestState_history(t *testing.T) {
+	client := &mockClientHistory{}
+	mgr := NewState(client, testStateEncryption{})
+	if err := mgr.RefreshState(); err != nil {
+		t.Fatalf("failed to RefreshState: %s", err)
+	}
+
+	for _, v := range []string{"a", "b", "c"} {
+		s := states.NewState()
+		s.RootModule().SetOutputValue("foo", cty.StringVal(v), false)
+		if err := mgr.WriteState(s); err != nil {
+			t.Fatalf("failed to WriteState: %s", err)
+		}
+		if err := mgr.PersistState(nil); err != nil {
+			t.Fatalf("failed to PersistState: %s", err)
+		}
+	}
+
+	versions, err := mgr.SnapshotVersions(0)
+	if err != nil {
+		t.Fatalf("failed to list snapshot versions: %s", err)
+	}
+	want := []statemgr.SnapshotVersion{
+		{VersionID: "2", Timestamp: time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)},
+		{VersionID: "1", Timestamp: time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)},
+		{VersionID: "0", Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	if diff := cmp.Diff(want, versions); diff != "" {
+		t.Fatalf("wrong snapshot versions\n%s", diff)
+	}
+
+	versions, err = mgr.SnapshotVersions(1)
+	if err != nil {
+		t.Fatalf("failed to list snapshot versions: %s", err)
+	}
+	if diff := cmp.Diff(want[:1], versions); diff != "" {
+		t.Fatalf("wrong limited snapshot versions\n%s", diff)
+	}
+
+	// Earlier snapshots are decrypted just like the latest one.
+	f, err := mgr.SnapshotVersion("0")
+	if err != nil {
+		t.Fatalf("failed to read snapshot version: %s", err)
+	}
+	if got, want := f.State.RootModule().OutputValues["foo"].Value, cty.StringVal("a"); !got.RawEquals(want) {
+		t.Fatalf("wrong output value %#v; want %#v", got, want)
+	}
+
+	if _, err := mgr.SnapshotVersion("5"); err == nil {
+		t.Fatal("unexpected success reading a nonexistent snapshot version")
+	}
+
+	mgr = NewState(&mockClient{}, encryption.StateEncryptionDisabled())
+	if _, err := mgr.SnapshotVersions(0); err != statemgr.ErrHistoryUnsupported {
+		t.Fatalf("wrong error for a client without history: %v", err)
+	}
+}
+
 type migrationTestCase struct {
 	name string
 	// A function to generate a statefile
@@ -425,9 +544,8 @@ type migrationTestCase struct {
 }
 
 func TestWriteStateForMigration(t *testing.T) {
-	mgr := &State{
-		Client: &mockClient{
-			current: []byte(`
+	mgr := NewState(&mockClient{
+		current: []byte(`
 				{
 					"version": 4,
 					"lineage": "mock-lineage",
@@ -437,8 +555,7 @@ func TestWriteStateForMigration(t *testing.T) {
 					"resources": []
 				}
 			`),
-		},
-	}
+	}, encryption.StateEncryptionDisabled())
 
 	testCases := []migrationTestCase{
 		// Refreshing state before we run the test loop causes a GET
@@ -581,9 +698,8 @@ func TestWriteStateForMigration(t *testing.T) {
 // us to test that -force continues to work for backends without
 // this interface, but that this interface works for those that do.
 func TestWriteStateForMigrationWithForcePushClient(t *testing.T) {
-	mgr := &State{
-		Client: &mockClientForcePusher{
-			current: []byte(`
+	mgr := NewState(&mockClientForcePusher{
+		current: []byte(`
 				{
 					"version": 4,
 					"lineage": "mock-lineage",
@@ -593,8 +709,7 @@ func TestWriteStateForMigrationWithForcePushClient(t *testing.T) {
 					"resources": []
 				}
 			`),
-		},
-	}
+	}, encryption.StateEncryptionDisabled())
 
 	testCases := []migrationTestCase{
 		// Refreshing state before we run the test loop causes a GET
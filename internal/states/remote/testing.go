@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 )
@@ -16,7 +17,7 @@ func TestClient(t *testing.T, c Client) {
 	var buf bytes.Buffer
 	s := statemgr.TestFullInitialState()
 	sf := statefile.New(s, "stub-lineage", 2)
-	err := statefile.Write(sf, &buf)
+	err := statefile.Write(sf, &buf, encryption.StateEncryptionDisabled())
 	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
@@ -47,6 +48,71 @@ func TestClient(t *testing.T, c Client) {
 	}
 }
 
+// TestClientHistory is a generic function to test any client that retains
+// earlier versions of the state. The storage for the given client must be
+// configured to retain versions.
+func TestClientHistory(t *testing.T, c Client) {
+	h, ok := c.(ClientHistory)
+	if !ok {
+		t.Fatal("client is not a remote.ClientHistory")
+	}
+
+	before, err := h.Versions(0)
+	if err != nil {
+		t.Fatalf("versions: %s", err)
+	}
+
+	var snapshots [][]byte
+	for serial := uint64(1); serial <= 2; serial++ {
+		var buf bytes.Buffer
+		sf := statefile.New(statemgr.TestFullInitialState(), "stub-lineage", serial)
+		if err := statefile.Write(sf, &buf, encryption.StateEncryptionDisabled()); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := c.Put(buf.Bytes()); err != nil {
+			t.Fatalf("put: %s", err)
+		}
+		snapshots = append(snapshots, buf.Bytes())
+	}
+
+	versions, err := h.Versions(0)
+	if err != nil {
+		t.Fatalf("versions: %s", err)
+	}
+	if len(versions) != len(before)+len(snapshots) {
+		t.Fatalf("expected %d versions, got %d", len(before)+len(snapshots), len(versions))
+	}
+
+	limited, err := h.Versions(1)
+	if err != nil {
+		t.Fatalf("versions: %s", err)
+	}
+	if len(limited) != 1 || limited[0].ID != versions[0].ID {
+		t.Fatalf("expected only the newest version %q, got %d versions", versions[0].ID, len(limited))
+	}
+
+	// The versions are ordered newest first.
+	for i, version := range versions[:len(snapshots)] {
+		p, err := h.GetVersion(version.ID)
+		if err != nil {
+			t.Fatalf("get version %q: %s", version.ID, err)
+		}
+		want := snapshots[len(snapshots)-1-i]
+		if p == nil || !bytes.Equal(p.Data, want) {
+			t.Fatalf("wrong data for version %q\n\nexpected: %q", version.ID, want)
+		}
+		// Clients need only report the serial and lineage if their storage
+		// records them.
+		if wantSerial := uint64(len(snapshots) - i); version.Lineage != "" && (version.Lineage != "stub-lineage" || version.Serial != wantSerial) {
+			t.Fatalf("wrong serial and lineage for version %q: got %d %q, want %d %q", version.ID, version.Serial, version.Lineage, wantSerial, "stub-lineage")
+		}
+	}
+
+	if err := c.Delete(); err != nil {
+		t.Fatalf("delete: %s", err)
+	}
+}
+
 // Test the lock implementation for a remote.Client.
 // This test requires 2 client instances, in oder to have multiple remote
 // clients since some implementations may tie the client to the lock, or may
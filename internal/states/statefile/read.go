@@ -12,6 +12,7 @@ import (
 
 	version "github.com/hashicorp/go-version"
 
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	tfversion "github.com/opentofu/opentofu/version"
 )
@@ -44,10 +45,13 @@ func (e *ErrUnusableState) Unwrap() error {
 // contain object attributes in the deprecated "flatmap" format and so must
 // be upgraded by the caller before use.
 //
+// The given encryption is used to decrypt the state before parsing it. Use
+// encryption.StateEncryptionDisabled to read state that is never encrypted.
+//
 // If the state file is empty, the special error value ErrNoState is returned.
 // Otherwise, the returned error might be a wrapper around tfdiags.Diagnostics
 // potentially describing multiple errors.
-func Read(r io.Reader) (*File, error) {
+func Read(r io.Reader, enc encryption.StateEncryption) (*File, error) {
 	// Some callers provide us a "typed nil" *os.File here, which would
 	// cause us to panic below if we tried to use it.
 	if f, ok := r.(*os.File); ok && f == nil {
@@ -73,6 +77,16 @@ func Read(r io.Reader) (*File, error) {
 		return nil, ErrNoState
 	}
 
+	src, err = enc.DecryptState(src)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to decrypt state",
+			fmt.Sprintf("The state could not be decrypted: %s.", err),
+		))
+		return nil, diags.Err()
+	}
+
 	state, err := readState(src)
 	if err != nil {
 		return nil, err
@@ -140,6 +140,9 @@ func prepareStateV4(sV4 *stateV4) (*File, tfdiags.Diagnostics) {
 				SchemaVersion:       isV4.SchemaVersion,
 				CreateBeforeDestroy: isV4.CreateBeforeDestroy,
 			}
+			if isV4.ProviderKey != "" {
+				obj.ProviderKey = addrs.StringKey(isV4.ProviderKey)
+			}
 
 			{
 				// Instance attributes
@@ -489,6 +492,20 @@ func appendInstanceObjectStateV4(rs *states.Resource, is *states.ResourceInstanc
 		}
 	}
 
+	var providerKey string
+	switch tk := obj.ProviderKey.(type) {
+	case addrs.StringKey:
+		providerKey = string(tk)
+	default:
+		if obj.ProviderKey != addrs.NoKey {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to serialize resource instance in state",
+				fmt.Sprintf("Instance %s has an unsupported provider instance key: %#v.", rs.Addr.Instance(key), obj.ProviderKey),
+			))
+		}
+	}
+
 	// Extract paths from path value marks
 	var paths []cty.Path
 	for _, vm := range obj.AttrSensitivePaths {
@@ -510,6 +527,7 @@ func appendInstanceObjectStateV4(rs *states.Resource, is *states.ResourceInstanc
 		PrivateRaw:              privateRaw,
 		Dependencies:            deps,
 		CreateBeforeDestroy:     obj.CreateBeforeDestroy,
+		ProviderKey:             providerKey,
 	}), diags
 }
 
@@ -723,6 +741,8 @@ type instanceObjectStateV4 struct {
 	Dependencies []string `json:"dependencies,omitempty"`
 
 	CreateBeforeDestroy bool `json:"create_before_destroy,omitempty"`
+
+	ProviderKey string `json:"provider_key,omitempty"`
 }
 
 type checkResultsV4 struct {
@@ -4,19 +4,30 @@
 package statefile
 
 import (
+	"bytes"
 	"io"
 
+	"github.com/opentofu/opentofu/internal/encryption"
 	tfversion "github.com/opentofu/opentofu/version"
 )
 
 // Write writes the given state to the given writer in the current state
-// serialization format.
-func Write(s *File, w io.Writer) error {
+// serialization format, encrypting it using the given encryption.
+func Write(s *File, w io.Writer, enc encryption.StateEncryption) error {
 	// Always record the current tofu version in the state.
 	s.TerraformVersion = tfversion.SemVer
 
-	diags := writeStateV4(s, w)
-	return diags.Err()
+	var buf bytes.Buffer
+	if diags := writeStateV4(s, &buf); diags.HasErrors() {
+		return diags.Err()
+	}
+
+	encrypted, err := enc.EncryptState(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(encrypted)
+	return err
 }
 
 // WriteForTest writes the given state to the given writer in the current state
@@ -5,6 +5,7 @@ package statemgr
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,6 +17,7 @@ import (
 
 	multierror "github.com/hashicorp/go-multierror"
 
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -60,10 +62,15 @@ type Filesystem struct {
 	readFile      *statefile.File
 	backupFile    *statefile.File
 	writtenBackup bool
+
+	encryption encryption.StateEncryption
+
+	spanParent SpanParent
 }
 
 var (
 	_ Full           = (*Filesystem)(nil)
+	_ Traceable      = (*Filesystem)(nil)
 	_ PersistentMeta = (*Filesystem)(nil)
 	_ Migrator       = (*Filesystem)(nil)
 )
@@ -73,20 +80,25 @@ var (
 //
 // This is equivalent to calling NewFileSystemBetweenPaths with statePath as
 // both of the path arguments.
-func NewFilesystem(statePath string) *Filesystem {
+//
+// Both the state file and any backup files are encrypted using the given
+// encryption.
+func NewFilesystem(statePath string, enc encryption.StateEncryption) *Filesystem {
 	return &Filesystem{
-		path:     statePath,
-		readPath: statePath,
+		path:       statePath,
+		readPath:   statePath,
+		encryption: enc,
 	}
 }
 
 // NewFilesystemBetweenPaths creates a filesystem-based state manager that
 // reads an initial snapshot from readPath and then writes all new snapshots to
 // writePath.
-func NewFilesystemBetweenPaths(readPath, writePath string) *Filesystem {
+func NewFilesystemBetweenPaths(readPath, writePath string, enc encryption.StateEncryption) *Filesystem {
 	return &Filesystem{
-		path:     writePath,
-		readPath: readPath,
+		path:       writePath,
+		readPath:   readPath,
+		encryption: enc,
 	}
 }
 
@@ -105,6 +117,11 @@ func (s *Filesystem) SetBackupPath(path string) {
 	s.writtenBackup = false
 }
 
+// SetTraceContext is an implementation of Traceable.
+func (s *Filesystem) SetTraceContext(ctx context.Context) {
+	s.spanParent.Set(ctx)
+}
+
 // BackupPath returns the manager's backup path if backup files are enabled,
 // or an empty string otherwise.
 func (s *Filesystem) BackupPath() string {
@@ -121,7 +138,10 @@ func (s *Filesystem) State() *states.State {
 }
 
 // WriteState is an implementation of Writer.
-func (s *Filesystem) WriteState(state *states.State) error {
+func (s *Filesystem) WriteState(state *states.State) (err error) {
+	span := s.spanParent.StartSpan("filesystem", "write", traceAttrStatePath.String(s.path))
+	defer func() { EndSpan(span, err) }()
+
 	defer s.mutex()()
 
 	if s.readFile == nil {
@@ -152,7 +172,10 @@ func (s *Filesystem) writeState(state *states.State, meta *SnapshotMeta) error {
 }
 
 // PersistState writes state to a tfstate file.
-func (s *Filesystem) PersistState(schemas *tofu.Schemas) error {
+func (s *Filesystem) PersistState(schemas *tofu.Schemas) (err error) {
+	span := s.spanParent.StartSpan("filesystem", "persist", traceAttrStatePath.String(s.path))
+	defer func() { EndSpan(span, err) }()
+
 	defer s.mutex()()
 
 	return s.persistState(schemas)
@@ -185,7 +208,7 @@ func (s *Filesystem) persistState(schemas *tofu.Schemas) error {
 			}
 			defer bfh.Close()
 
-			err = statefile.Write(s.backupFile, bfh)
+			err = statefile.Write(s.backupFile, bfh, s.encryption)
 			if err != nil {
 				return fmt.Errorf("failed to write to local state backup file: %w", err)
 			}
@@ -229,7 +252,7 @@ func (s *Filesystem) persistState(schemas *tofu.Schemas) error {
 	}
 
 	log.Printf("[TRACE] statemgr.Filesystem: writing snapshot at %s", s.path)
-	if err := statefile.Write(s.file, s.stateFileOut); err != nil {
+	if err := statefile.Write(s.file, s.stateFileOut, s.encryption); err != nil {
 		return err
 	}
 
@@ -239,7 +262,10 @@ func (s *Filesystem) persistState(schemas *tofu.Schemas) error {
 }
 
 // RefreshState is an implementation of Refresher.
-func (s *Filesystem) RefreshState() error {
+func (s *Filesystem) RefreshState() (err error) {
+	span := s.spanParent.StartSpan("filesystem", "refresh", traceAttrStatePath.String(s.path))
+	defer func() { EndSpan(span, err) }()
+
 	defer s.mutex()()
 	return s.refreshState()
 }
@@ -301,7 +327,7 @@ func (s *Filesystem) refreshState() error {
 		reader = s.stateFileOut
 	}
 
-	f, err := statefile.Read(reader)
+	f, err := statefile.Read(reader, s.encryption)
 	// if there's no state then a nil file is fine
 	if err != nil {
 		if err != statefile.ErrNoState {
@@ -321,7 +347,10 @@ func (s *Filesystem) refreshState() error {
 }
 
 // Lock implements Locker using filesystem discretionary locks.
-func (s *Filesystem) Lock(info *LockInfo) (string, error) {
+func (s *Filesystem) Lock(info *LockInfo) (id string, err error) {
+	span := s.spanParent.StartSpan("filesystem", "lock", traceAttrStatePath.String(s.path))
+	defer func() { EndSpan(span, err) }()
+
 	defer s.mutex()()
 
 	if s.stateFileOut == nil {
@@ -353,7 +382,10 @@ func (s *Filesystem) Lock(info *LockInfo) (string, error) {
 }
 
 // Unlock is the companion to Lock, completing the implemention of Locker.
-func (s *Filesystem) Unlock(id string) error {
+func (s *Filesystem) Unlock(id string) (err error) {
+	span := s.spanParent.StartSpan("filesystem", "unlock", traceAttrStatePath.String(s.path))
+	defer func() { EndSpan(span, err) }()
+
 	defer s.mutex()()
 
 	if s.lockID == "" {
@@ -374,7 +406,7 @@ func (s *Filesystem) Unlock(id string) error {
 	}
 
 	lockInfoPath := s.lockInfoPath()
-	err := os.Remove(lockInfoPath)
+	err = os.Remove(lockInfoPath)
 	if err != nil {
 		log.Printf(
 			"[ERROR] statemgr.Filesystem: error removing lock metadata file %q: %s",
@@ -490,7 +522,7 @@ func (s *Filesystem) createStateFiles() error {
 
 	// If the file already existed with content then that'll be the content
 	// of our backup file if we write a change later.
-	s.backupFile, err = statefile.Read(s.stateFileOut)
+	s.backupFile, err = statefile.Read(s.stateFileOut, s.encryption)
 	if err != nil {
 		if err != statefile.ErrNoState {
 			return err
@@ -0,0 +1,126 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemgr
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/states/statefile"
+)
+
+// backupFileSuffix is the suffix of the backup files that OpenTofu creates
+// alongside a local state file, both for the single backup written by
+// operations and the timestamped backups written by the state subcommands.
+const backupFileSuffix = ".backup"
+
+var _ History = (*Filesystem)(nil)
+
+// SnapshotVersions is an implementation of History.
+//
+// The local filesystem doesn't retain earlier versions of a file, so the
+// snapshots are the state file itself and any backup files alongside it. The
+// version ID of each snapshot is the path of its file.
+func (s *Filesystem) SnapshotVersions(limit int) ([]SnapshotVersion, error) {
+	defer s.mutex()()
+
+	var ret []SnapshotVersion
+	for _, path := range s.historyPaths() {
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if info.Size() == 0 {
+			// An empty state file has no snapshot in it yet.
+			continue
+		}
+
+		ret = append(ret, SnapshotVersion{
+			VersionID: path,
+			Timestamp: info.ModTime(),
+		})
+	}
+
+	// historyPaths returns the state file before its backups, which we
+	// preserve for snapshots with the same timestamp.
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Timestamp.After(ret[j].Timestamp)
+	})
+	if limit > 0 && len(ret) > limit {
+		ret = ret[:limit]
+	}
+	return ret, nil
+}
+
+// SnapshotVersion is an implementation of History.
+func (s *Filesystem) SnapshotVersion(versionID string) (*statefile.File, error) {
+	defer s.mutex()()
+
+	// We only accept the paths we'd return from SnapshotVersions, so that
+	// this can't be used to read arbitrary files.
+	for _, path := range s.historyPaths() {
+		if path != versionID {
+			continue
+		}
+		file, err := s.readHistoryFile(path)
+		if err != nil {
+			if os.IsNotExist(err) || err == statefile.ErrNoState {
+				break
+			}
+			return nil, err
+		}
+		return file, nil
+	}
+	return nil, fmt.Errorf("there is no state snapshot with version %q", versionID)
+}
+
+// historyPaths returns the paths of the files that may contain snapshots of
+// this manager's state: the state file and its backup files.
+func (s *Filesystem) historyPaths() []string {
+	paths := []string{s.path}
+	seen := map[string]bool{s.path: true}
+
+	if s.backupPath != "" {
+		paths = append(paths, s.backupPath)
+		seen[s.backupPath] = true
+	}
+
+	// The pattern can only be invalid if the state path contains glob
+	// syntax, in which case we'll just not find any timestamped backups.
+	matches, _ := filepath.Glob(s.path + ".*")
+	for _, path := range matches {
+		if !strings.HasSuffix(path, backupFileSuffix) || seen[path] {
+			continue
+		}
+		paths = append(paths, path)
+		seen[path] = true
+	}
+
+	return paths
+}
+
+func (s *Filesystem) readHistoryFile(path string) (*statefile.File, error) {
+	// The state file itself may be locked, in which case we must read it
+	// through the handle we already hold.
+	if path == s.path && s.stateFileOut != nil {
+		if _, err := s.stateFileOut.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return statefile.Read(s.stateFileOut, s.encryption)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return statefile.Read(f, s.encryption)
+}
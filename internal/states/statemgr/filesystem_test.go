@@ -4,18 +4,24 @@
 package statemgr
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-test/deep"
 	version "github.com/hashicorp/go-version"
 	"github.com/zclconf/go-cty/cty"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	tfversion "github.com/opentofu/opentofu/version"
@@ -46,6 +52,38 @@ func TestFilesystemRace(t *testing.T) {
 	wg.Wait()
 }
 
+func TestFilesystem_traceContext(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+	})
+
+	ls := testFilesystem(t)
+	defer os.Remove(ls.readPath)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	SetTraceContext(ls, ctx)
+	if err := ls.RefreshState(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	parent.End()
+
+	var span sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.Name() == "state refresh" {
+			span = s
+		}
+	}
+	if span == nil {
+		t.Fatal("no span recorded for the refresh")
+	}
+	if got, want := span.Parent().SpanID(), parent.SpanContext().SpanID(); got != want {
+		t.Errorf("refresh span has wrong parent %s; want %s", got, want)
+	}
+}
+
 func TestFilesystemLocks(t *testing.T) {
 	defer testOverrideVersion(t, "1.2.3")()
 	s := testFilesystem(t)
@@ -167,7 +205,7 @@ func TestFilesystem_backup(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	bf, err := statefile.Read(bfh)
+	bf, err := statefile.Read(bfh, encryption.StateEncryptionDisabled())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -208,7 +246,7 @@ func TestFilesystem_backupAndReadPath(t *testing.T) {
 		Serial:           0,
 		TerraformVersion: version.Must(version.NewVersion("1.2.3")),
 		State:            outState,
-	}, outFile)
+	}, outFile, encryption.StateEncryptionDisabled())
 	if err != nil {
 		t.Fatalf("failed to write initial outfile state to %s: %s", outFile.Name(), err)
 	}
@@ -230,14 +268,14 @@ func TestFilesystem_backupAndReadPath(t *testing.T) {
 		Serial:           0,
 		TerraformVersion: version.Must(version.NewVersion("1.2.3")),
 		State:            inState,
-	}, inFile)
+	}, inFile, encryption.StateEncryptionDisabled())
 	if err != nil {
 		t.Fatalf("failed to write initial infile state to %s: %s", inFile.Name(), err)
 	}
 
 	backupPath := outFile.Name() + ".backup"
 
-	ls := NewFilesystemBetweenPaths(inFile.Name(), outFile.Name())
+	ls := NewFilesystemBetweenPaths(inFile.Name(), outFile.Name(), encryption.StateEncryptionDisabled())
 	ls.SetBackupPath(backupPath)
 
 	newState := states.BuildState(func(ss *states.SyncState) {
@@ -260,7 +298,7 @@ func TestFilesystem_backupAndReadPath(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		bf, err := statefile.Read(bfh)
+		bf, err := statefile.Read(bfh, encryption.StateEncryptionDisabled())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -274,7 +312,7 @@ func TestFilesystem_backupAndReadPath(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		of, err := statefile.Read(ofh)
+		of, err := statefile.Read(ofh, encryption.StateEncryptionDisabled())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -285,9 +323,84 @@ func TestFilesystem_backupAndReadPath(t *testing.T) {
 	})
 }
 
+func TestFilesystem_snapshotVersions(t *testing.T) {
+	defer testOverrideVersion(t, "1.2.3")()
+	td := t.TempDir()
+	statePath := filepath.Join(td, "terraform.tfstate")
+
+	// The state file, the backup written by operations and a timestamped
+	// backup written by a state subcommand, each older than the last.
+	files := []struct {
+		path    string
+		serial  uint64
+		modTime time.Time
+	}{
+		{statePath, 3, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{statePath + ".backup", 2, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{statePath + ".1704067200.backup", 1, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, file := range files {
+		f, err := os.Create(file.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = statefile.Write(statefile.New(TestFullInitialState(), "lineage", file.serial), f, encryption.StateEncryptionDisabled())
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file.path, file.modTime, file.modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Files that aren't backups of this state must be ignored.
+	if err := os.WriteFile(statePath+".lock.info", []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ls := NewFilesystem(statePath, encryption.StateEncryptionDisabled())
+	ls.SetBackupPath(statePath + ".backup")
+
+	versions, err := ls.SnapshotVersions(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []SnapshotVersion
+	for _, file := range files {
+		want = append(want, SnapshotVersion{
+			VersionID: file.path,
+			Timestamp: file.modTime,
+		})
+	}
+	for _, diff := range deep.Equal(versions, want) {
+		t.Error(diff)
+	}
+
+	versions, err = ls.SnapshotVersions(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, diff := range deep.Equal(versions, want[:2]) {
+		t.Error(diff)
+	}
+
+	f, err := ls.SnapshotVersion(statePath + ".1704067200.backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Serial != 1 {
+		t.Errorf("wrong serial %d; want 1", f.Serial)
+	}
+
+	if _, err := ls.SnapshotVersion(statePath + ".lock.info"); err == nil {
+		t.Error("unexpected success reading a file that isn't a snapshot")
+	}
+}
+
 func TestFilesystem_nonExist(t *testing.T) {
 	defer testOverrideVersion(t, "1.2.3")()
-	ls := NewFilesystem("ishouldntexist")
+	ls := NewFilesystem("ishouldntexist", encryption.StateEncryptionDisabled())
 	if err := ls.RefreshState(); err != nil {
 		t.Fatalf("err: %s", err)
 	}
@@ -354,13 +467,13 @@ func testFilesystem(t *testing.T) *Filesystem {
 		Serial:           0,
 		TerraformVersion: version.Must(version.NewVersion("1.2.3")),
 		State:            TestFullInitialState(),
-	}, f)
+	}, f, encryption.StateEncryptionDisabled())
 	if err != nil {
 		t.Fatalf("failed to write initial state to %s: %s", f.Name(), err)
 	}
 	f.Close()
 
-	ls := NewFilesystem(f.Name())
+	ls := NewFilesystem(f.Name(), encryption.StateEncryptionDisabled())
 	if err := ls.RefreshState(); err != nil {
 		t.Fatalf("initial refresh failed: %s", err)
 	}
@@ -381,13 +494,13 @@ func TestFilesystem_refreshWhileLocked(t *testing.T) {
 		Serial:           0,
 		TerraformVersion: version.Must(version.NewVersion("1.2.3")),
 		State:            TestFullInitialState(),
-	}, f)
+	}, f, encryption.StateEncryptionDisabled())
 	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
 	f.Close()
 
-	s := NewFilesystem(f.Name())
+	s := NewFilesystem(f.Name(), encryption.StateEncryptionDisabled())
 	defer os.Remove(s.path)
 
 	// lock first
@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemgr
+
+import (
+	"errors"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/states/statefile"
+)
+
+// ErrHistoryUnsupported is returned by the methods of History when the
+// storage behind a state manager does not retain earlier snapshots, such as
+// when object versioning is disabled for a bucket.
+var ErrHistoryUnsupported = errors.New("the state storage does not retain earlier state snapshots")
+
+// History is an optional extension to Persistent for managers whose storage
+// retains the snapshots that were persisted before the latest one, such as
+// in object versions or in backup files.
+//
+// The snapshots returned by History are read-only. To restore one of them,
+// callers write its state through the usual Writer and Persister methods, so
+// that it becomes a new snapshot with the next serial.
+type History interface {
+	// SnapshotVersions returns the snapshots retained in storage, including
+	// the latest one, ordered from newest to oldest. If limit is greater than
+	// zero then only that many of the newest snapshots are returned.
+	//
+	// Listing the snapshots doesn't read them, so callers that need the
+	// content of a snapshot must request it with SnapshotVersion.
+	SnapshotVersions(limit int) ([]SnapshotVersion, error)
+
+	// SnapshotVersion returns the snapshot with the given version ID, as
+	// returned in a previous call to SnapshotVersions.
+	SnapshotVersion(versionID string) (*statefile.File, error)
+}
+
+// SnapshotVersion describes one of the snapshots retained by a state manager
+// that implements History.
+type SnapshotVersion struct {
+	// VersionID identifies the snapshot within the storage. Its format
+	// depends on the storage, so callers must treat it as opaque.
+	VersionID string
+
+	// Timestamp is the time when the snapshot was persisted, as recorded by
+	// the storage.
+	Timestamp time.Time
+
+	// Serial and Lineage are those of the snapshot, for storage that records
+	// them alongside it. Lineage is empty if the storage doesn't, in which
+	// case callers must read the snapshot with SnapshotVersion to find them.
+	Serial  uint64
+	Lineage string
+}
@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemgr
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer trace.Tracer
+
+func init() {
+	tracer = otel.Tracer("github.com/opentofu/opentofu/internal/states/statemgr")
+}
+
+// TraceAttrStateManager is the trace attribute naming the kind of state
+// manager a span was recorded for.
+const TraceAttrStateManager = attribute.Key("opentofu.state.manager")
+
+// traceAttrStatePath is the trace attribute for the path of a local state file.
+const traceAttrStatePath = attribute.Key("opentofu.state.path")
+
+// Traceable is an optional interface implemented by state managers that
+// record trace spans for their calls.
+type Traceable interface {
+	// SetTraceContext sets the context whose span is the parent of the
+	// spans recorded for subsequent calls to the state manager.
+	SetTraceContext(ctx context.Context)
+}
+
+// SetTraceContext records the spans for subsequent calls to the given state
+// manager as children of the span in the given context, if the state manager
+// implements Traceable.
+func SetTraceContext(mgr Transient, ctx context.Context) {
+	if t, ok := mgr.(Traceable); ok {
+		t.SetTraceContext(ctx)
+	}
+}
+
+// SpanParent holds the context that a state manager records its trace spans
+// under. The zero value records each span in a new trace.
+//
+// It's exported so that the state manager implementations outside of this
+// package can implement Traceable consistently.
+type SpanParent struct {
+	mu  sync.Mutex
+	ctx context.Context
+}
+
+// Set sets the context whose span is the parent of subsequent spans.
+func (p *SpanParent) Set(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ctx = ctx
+}
+
+// StartSpan starts a trace span covering a single read, write or lock call
+// on a state manager.
+func (p *SpanParent) StartSpan(manager, method string, attrs ...attribute.KeyValue) trace.Span {
+	p.mu.Lock()
+	ctx := p.ctx
+	p.mu.Unlock()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	attrs = append(attrs, TraceAttrStateManager.String(manager))
+	_, span := tracer.Start(ctx, "state "+method, trace.WithAttributes(attrs...))
+	return span
+}
+
+// EndSpan ends a span started by StartSpan, recording the given error if the
+// call failed.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -43,6 +43,11 @@ type ContextOpts struct {
 	Provisioners map[string]provisioners.Factory
 
 	UIInput UIInput
+
+	// TraceContext, if set, carries the trace span that the spans for the
+	// graph walks of this context are recorded under, so that they appear
+	// as part of the caller's trace.
+	TraceContext context.Context
 }
 
 // ContextMeta is metadata about the running context. This is information
@@ -87,6 +92,7 @@ type Context struct {
 	runCond             *sync.Cond
 	runContext          context.Context
 	runContextCancel    context.CancelFunc
+	traceContext        context.Context
 }
 
 // (additional methods on Context can be found in context_*.go files.)
@@ -142,6 +148,7 @@ func NewContext(opts *ContextOpts) (*Context, tfdiags.Diagnostics) {
 		parallelSem:         NewSemaphore(par),
 		providerInputConfig: make(map[string]map[string]cty.Value),
 		sh:                  sh,
+		traceContext:        opts.TraceContext,
 	}, diags
 }
 
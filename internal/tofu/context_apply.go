@@ -70,6 +70,10 @@ func (c *Context) Apply(plan *plans.Plan, config *configs.Config) (*states.State
 
 		// We also want to propagate the timestamp from the plan file.
 		PlanTimeTimestamp: plan.Timestamp,
+
+		// The objects deferred during planning have no planned changes, and
+		// their count or for_each values might still be unknown.
+		Deferrals: loadDeferrals(plan.DeferredChanges),
 	})
 	diags = diags.Append(walker.NonFatalDiagnostics)
 	diags = diags.Append(walkDiags)
@@ -99,6 +103,26 @@ Note that the -target option is not suitable for routine use, and is provided on
 		))
 	}
 
+	if len(plan.ExcludeAddrs) > 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Applied changes may be incomplete",
+			`The plan was created with the -exclude option in effect, so some changes requested in the configuration may have been ignored and the output values may not be fully updated. Run the following command to verify that no other changes are pending:
+    tofu plan
+
+Note that the -exclude option is not suitable for routine use, and is provided only for exceptional situations such as recovering from errors or mistakes, or when OpenTofu specifically suggests to use it as part of an error message.`,
+		))
+	}
+
+	if plan.HasDeferredChanges() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Applied changes are incomplete",
+			`The plan deferred some changes because their count or for_each arguments depended on values that were not known until apply. Run the following command to plan the remaining changes:
+    tofu plan`,
+		))
+	}
+
 	// FIXME: we cannot check for an empty plan for refresh-only, because root
 	// outputs are always stored as changes. The final condition of the state
 	// also depends on some cleanup which happens during the apply walk. It
@@ -137,6 +161,18 @@ func (c *Context) applyGraph(plan *plans.Plan, config *configs.Config, validate
 			SourceType: ValueFromPlan,
 		}
 	}
+
+	// Ephemeral variables are never saved in a plan file, so the caller must
+	// supply them again for the apply step.
+	for _, name := range plan.ApplyTimeVariables {
+		if _, ok := variables[name]; !ok {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Missing ephemeral variable value",
+				fmt.Sprintf("The variable %q is ephemeral, so its value was not saved in the plan and must be set again when applying it.", name),
+			))
+		}
+	}
 	if diags.HasErrors() {
 		return nil, walkApply, diags
 	}
@@ -175,6 +211,7 @@ func (c *Context) applyGraph(plan *plans.Plan, config *configs.Config, validate
 		RootVariableValues: variables,
 		Plugins:            c.plugins,
 		Targets:            plan.TargetAddrs,
+		Excludes:           plan.ExcludeAddrs,
 		ForceReplace:       plan.ForceReplaceAddrs,
 		Operation:          operation,
 		ExternalReferences: plan.ExternalReferences,
@@ -2244,3 +2244,104 @@ locals {
 		t.Errorf("expected local value to be \"foo\" but was \"%s\"", module.LocalValues["local_value"].AsString())
 	}
 }
+
+func TestContext2Apply_removedResource(t *testing.T) {
+	addr := mustResourceInstanceAddr("module.child.test_object.a")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			removed {
+				from = module.child.test_object.a
+				lifecycle {
+					destroy = false
+				}
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(addr, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"test_string":"foo"}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`))
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	state, diags = ctx.Apply(plan, m)
+	assertNoErrors(t, diags)
+
+	if p.ApplyResourceChangeCalled {
+		t.Error("provider was asked to apply a change, but the object should only have been removed from the state")
+	}
+	if !state.Empty() {
+		t.Fatalf("expected an empty state, got:\n%s", state)
+	}
+}
+
+func TestContext2Apply_ephemeralVariables(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+variable "token" {
+  type      = string
+  ephemeral = true
+}
+
+provider "test" {
+  test_string = var.token
+}
+
+resource "test_object" "a" {
+  test_string = "static"
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, states.NewState(), &PlanOpts{
+		Mode: plans.NormalMode,
+		SetVariables: InputValues{
+			"token": &InputValue{
+				Value:      cty.StringVal("secret"),
+				SourceType: ValueFromCaller,
+			},
+		},
+	})
+	assertNoErrors(t, diags)
+
+	// A plan read from a plan file has no values for its ephemeral
+	// variables until the caller sets them again.
+	delete(plan.VariableValues, "token")
+	_, diags = ctx.Apply(plan, m)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want error")
+	}
+	if got, want := diags.Err().Error(), "Missing ephemeral variable value"; !strings.Contains(got, want) {
+		t.Fatalf("missing expected error %q in:\n%s", want, got)
+	}
+
+	dv, err := plans.NewDynamicValue(cty.StringVal("secret"), cty.DynamicPseudoType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan.VariableValues["token"] = dv
+	_, diags = ctx.Apply(plan, m)
+	assertNoErrors(t, diags)
+
+	if got := p.ConfigureProviderRequest.Config.GetAttr("test_string"); !got.RawEquals(cty.StringVal("secret")) {
+		t.Errorf("wrong provider configuration value %#v", got)
+	}
+}
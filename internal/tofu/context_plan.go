@@ -63,6 +63,16 @@ type PlanOpts struct {
 	// warnings as part of the planning result.
 	Targets []addrs.Targetable
 
+	// If Excludes has a non-zero length then it activates excluded planning
+	// mode, which is the inverse of targeted planning mode: OpenTofu will
+	// take no actions for the resource instances mentioned in this set, nor
+	// for any other objects that depend on those resource instances.
+	//
+	// Like targeting, excluded planning mode is intended for exceptional use
+	// only, and so populating this field will cause OpenTofu to generate
+	// extra warnings as part of the planning result.
+	Excludes []addrs.Targetable
+
 	// ForceReplace is a set of resource instance addresses whose corresponding
 	// objects should be forced planned for replacement if the provider's
 	// plan would otherwise have been to either update the object in-place or
@@ -83,6 +93,14 @@ type PlanOpts struct {
 	// will be added to the plan graph.
 	ImportTargets []*ImportTarget
 
+	// AllowDeferral activates partial planning: a resource or module call
+	// whose count or for_each value can't be known until apply is then
+	// deferred, along with everything that depends on it, rather than
+	// making the whole plan fail. The deferred objects are recorded in the
+	// plan, and another plan/apply round is needed after applying it to
+	// converge on the configuration.
+	AllowDeferral bool
+
 	// GenerateConfig tells OpenTofu where to write any generated configuration
 	// for any ImportTargets that do not have configuration already.
 	//
@@ -188,6 +206,16 @@ The -target option is not for routine use, and is provided only for exceptional
 		))
 	}
 
+	if len(opts.Excludes) > 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Resource exclusion is in effect",
+			`You are creating a plan with the -exclude option, which means that the result of this plan may not represent all of the changes requested by the current configuration.
+
+The -exclude option is not for routine use, and is provided only for exceptional situations such as recovering from errors or mistakes, or when OpenTofu specifically suggests to use it as part of an error message.`,
+		))
+	}
+
 	var plan *plans.Plan
 	var planDiags tfdiags.Diagnostics
 	switch opts.Mode {
@@ -208,10 +236,16 @@ The -target option is not for routine use, and is provided only for exceptional
 
 	// convert the variables into the format expected for the plan
 	varVals := make(map[string]plans.DynamicValue, len(opts.SetVariables))
+	var applyTimeVars []string
 	for k, iv := range opts.SetVariables {
 		if iv.Value == cty.NilVal {
 			continue // We only record values that the caller actually set
 		}
+		if vc, ok := config.Module.Variables[k]; ok && vc.Ephemeral {
+			// The plan keeps ephemeral values in memory so that it can be
+			// applied right away, but they're never saved in a plan file.
+			applyTimeVars = append(applyTimeVars, k)
+		}
 
 		// We use cty.DynamicPseudoType here so that we'll save both the
 		// value _and_ its dynamic type in the plan, so we can recover
@@ -232,7 +266,10 @@ The -target option is not for routine use, and is provided only for exceptional
 	// targets and provider SHAs.
 	if plan != nil {
 		plan.VariableValues = varVals
+		sort.Strings(applyTimeVars)
+		plan.ApplyTimeVariables = applyTimeVars
 		plan.TargetAddrs = opts.Targets
+		plan.ExcludeAddrs = opts.Excludes
 	} else if !diags.HasErrors() {
 		panic("nil plan but no errors")
 	}
@@ -243,6 +280,10 @@ The -target option is not for routine use, and is provided only for exceptional
 		plan.RelevantAttributes = relevantAttrs
 	}
 
+	if plan != nil && plan.HasDeferredChanges() {
+		diags = diags.Append(deferredChangesWarningDiag(plan.DeferredChanges))
+	}
+
 	if diags.HasErrors() {
 		// We can't proceed further with an invalid plan, because an invalid
 		// plan isn't applyable by definition.
@@ -447,10 +488,32 @@ func (c *Context) prePlanFindAndApplyMoves(config *configs.Config, prevRunState
 		moveStmts = append(moveStmts, explicitMoveStmts...)
 		moveStmts = append(moveStmts, implicitMoveStmts...)
 	}
-	moveResults := refactoring.ApplyMoves(moveStmts, prevRunState)
+	moveResults := refactoring.ApplyMoves(moveStmts, prevRunState, c.crossTypeMoves(config))
 	return moveStmts, moveResults
 }
 
+// crossTypeMoves returns a function that reports whether the provider of a
+// resource in the given configuration can convert objects moved from a
+// resource of another type into the resource's own type.
+func (c *Context) crossTypeMoves(config *configs.Config) refactoring.CrossTypeMoveFunc {
+	return func(addr addrs.ConfigResource) bool {
+		modCfg := config.Descendent(addr.Module)
+		if modCfg == nil {
+			return false
+		}
+		rc := modCfg.Module.ResourceByAddr(addr.Resource)
+		if rc == nil {
+			return false
+		}
+		schema, err := c.plugins.ProviderSchema(rc.Provider)
+		if err != nil {
+			// Errors loading the schema are reported elsewhere.
+			return false
+		}
+		return schema.ServerCapabilities.MoveResourceState
+	}
+}
+
 func (c *Context) prePlanVerifyTargetedMoves(moveResults refactoring.MoveResults, targets []addrs.Targetable) tfdiags.Diagnostics {
 	if len(targets) < 1 {
 		return nil // the following only matters when targeting
@@ -519,16 +582,71 @@ func (c *Context) prePlanVerifyTargetedMoves(moveResults refactoring.MoveResults
 	return diags
 }
 
+// prePlanVerifyExcludedMoves is the -exclude counterpart of
+// prePlanVerifyTargetedMoves: moved resource instances must be planned, so
+// neither their old nor their new addresses may be excluded.
+func (c *Context) prePlanVerifyExcludedMoves(moveResults refactoring.MoveResults, excludes []addrs.Targetable) tfdiags.Diagnostics {
+	if len(excludes) < 1 {
+		return nil // the following only matters when excluding
+	}
+
+	var diags tfdiags.Diagnostics
+
+	var excluded []addrs.AbsResourceInstance
+	for _, result := range moveResults.Changes.Values() {
+		for _, excludeAddr := range excludes {
+			if excludeAddr.TargetContains(result.From) || excludeAddr.TargetContains(result.To) {
+				excluded = append(excluded, result.To)
+				break
+			}
+		}
+	}
+	if len(excluded) > 0 {
+		sort.Slice(excluded, func(i, j int) bool {
+			return excluded[i].Less(excluded[j])
+		})
+
+		var listBuf strings.Builder
+		var prevResourceAddr addrs.AbsResource
+		for _, instAddr := range excluded {
+			// As for targeting, we show whole resource addresses here to
+			// avoid listing each instance separately.
+			resourceAddr := instAddr.ContainingResource()
+			if resourceAddr.Equal(prevResourceAddr) {
+				continue
+			}
+			fmt.Fprintf(&listBuf, "\n  %s", resourceAddr.String())
+			prevResourceAddr = resourceAddr
+		}
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Moved resource instances excluded by -exclude",
+			fmt.Sprintf(
+				"Resource instances in your current state have moved to new addresses in the latest configuration. OpenTofu must include those resource instances while planning in order to ensure a correct result, but your -exclude=... options exclude some of them:%s\n\nTo create a valid plan, change your -exclude=... options so that they don't cover any of these resources.",
+				listBuf.String(),
+			),
+		))
+	}
+
+	return diags
+}
+
 func (c *Context) postPlanValidateMoves(config *configs.Config, stmts []refactoring.MoveStatement, allInsts instances.Set) tfdiags.Diagnostics {
-	return refactoring.ValidateMoves(stmts, config, allInsts)
+	return refactoring.ValidateMoves(stmts, config, allInsts, c.crossTypeMoves(config))
 }
 
 // All import target addresses with a key must already exist in config.
 // When we are able to generate config for expanded resources, this rule can be
 // relaxed.
-func (c *Context) postPlanValidateImports(config *configs.Config, importTargets []*ImportTarget, allInst instances.Set) tfdiags.Diagnostics {
+func (c *Context) postPlanValidateImports(config *configs.Config, importTargets []*ImportTarget, allInst instances.Set, deferrals *deferrals) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 	for _, it := range importTargets {
+		if it.Config != nil && it.Config.ForEach != nil {
+			// Import blocks using for_each are validated as they are
+			// expanded, because only then are their addresses known.
+			continue
+		}
+
 		// We only care about import target addresses that have a key.
 		// If the address does not have a key, we don't need it to be in config
 		// because are able to generate config.
@@ -536,6 +654,12 @@ func (c *Context) postPlanValidateImports(config *configs.Config, importTargets
 			continue
 		}
 
+		// The instances of a deferred resource aren't known yet, so the
+		// import will be planned in a later round instead.
+		if deferrals.resourceDeferred(it.Addr.ContainingResource()) {
+			continue
+		}
+
 		if !allInst.HasResourceInstance(it.Addr) {
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
@@ -555,6 +679,18 @@ func (c *Context) postPlanValidateImports(config *configs.Config, importTargets
 func (c *Context) findImportTargets(config *configs.Config, priorState *states.State) []*ImportTarget {
 	var importTargets []*ImportTarget
 	for _, ic := range config.Module.Import {
+		if ic.ForEach != nil {
+			// The addresses of import blocks using for_each aren't known
+			// until they are expanded during the graph walk, so for now the
+			// target only records the static part of the address and the
+			// prior state is checked for each instance after expansion.
+			importTargets = append(importTargets, &ImportTarget{
+				Addr:   ic.To,
+				ID:     ic.ID,
+				Config: ic,
+			})
+			continue
+		}
 		if priorState.ResourceInstance(ic.To) == nil {
 			importTargets = append(importTargets, &ImportTarget{
 				Addr:   ic.To,
@@ -576,6 +712,7 @@ func (c *Context) planWalk(config *configs.Config, prevRunState *states.State, o
 	// If resource targeting is in effect then it might conflict with the
 	// move result.
 	diags = diags.Append(c.prePlanVerifyTargetedMoves(moveResults, opts.Targets))
+	diags = diags.Append(c.prePlanVerifyExcludedMoves(moveResults, opts.Excludes))
 	if diags.HasErrors() {
 		// We'll return early here, because if we have any moved resource
 		// instances excluded by targeting then planning is likely to encounter
@@ -594,19 +731,21 @@ func (c *Context) planWalk(config *configs.Config, prevRunState *states.State, o
 	// If we get here then we should definitely have a non-nil "graph", which
 	// we can now walk.
 	changes := plans.NewChanges()
+	deferrals := newDeferrals(opts.AllowDeferral)
 	walker, walkDiags := c.walk(graph, walkOp, &graphWalkOpts{
 		Config:            config,
 		InputState:        prevRunState,
 		Changes:           changes,
 		MoveResults:       moveResults,
 		PlanTimeTimestamp: timestamp,
+		Deferrals:         deferrals,
 	})
 	diags = diags.Append(walker.NonFatalDiagnostics)
 	diags = diags.Append(walkDiags)
 
 	allInsts := walker.InstanceExpander.AllInstances()
 
-	importValidateDiags := c.postPlanValidateImports(config, opts.ImportTargets, allInsts)
+	importValidateDiags := c.postPlanValidateImports(config, opts.ImportTargets, allInsts, deferrals)
 	if importValidateDiags.HasErrors() {
 		return nil, importValidateDiags
 	}
@@ -656,6 +795,7 @@ func (c *Context) planWalk(config *configs.Config, prevRunState *states.State, o
 		PlannedState:       walker.State.Close(),
 		ExternalReferences: opts.ExternalReferences,
 		Checks:             states.NewCheckResults(walker.Checks),
+		DeferredChanges:    deferrals.changes(),
 		Timestamp:          timestamp,
 
 		// Other fields get populated by Context.Plan after we return
@@ -666,12 +806,18 @@ func (c *Context) planWalk(config *configs.Config, prevRunState *states.State, o
 func (c *Context) planGraph(config *configs.Config, prevRunState *states.State, opts *PlanOpts) (*Graph, walkOperation, tfdiags.Diagnostics) {
 	switch mode := opts.Mode; mode {
 	case plans.NormalMode:
-		graph, diags := (&PlanGraphBuilder{
+		removeStmts, diags := refactoring.FindRemoveStatements(config)
+		if diags.HasErrors() {
+			return nil, walkPlan, diags
+		}
+
+		graph, moreDiags := (&PlanGraphBuilder{
 			Config:             config,
 			State:              prevRunState,
 			RootVariableValues: opts.SetVariables,
 			Plugins:            c.plugins,
 			Targets:            opts.Targets,
+			Excludes:           opts.Excludes,
 			ForceReplace:       opts.ForceReplace,
 			skipRefresh:        opts.SkipRefresh,
 			preDestroyRefresh:  opts.PreDestroyRefresh,
@@ -679,7 +825,9 @@ func (c *Context) planGraph(config *configs.Config, prevRunState *states.State,
 			ExternalReferences: opts.ExternalReferences,
 			ImportTargets:      opts.ImportTargets,
 			GenerateConfigPath: opts.GenerateConfigPath,
+			RemoveStatements:   removeStmts,
 		}).Build(addrs.RootModuleInstance)
+		diags = diags.Append(moreDiags)
 		return graph, walkPlan, diags
 	case plans.RefreshOnlyMode:
 		graph, diags := (&PlanGraphBuilder{
@@ -688,6 +836,7 @@ func (c *Context) planGraph(config *configs.Config, prevRunState *states.State,
 			RootVariableValues: opts.SetVariables,
 			Plugins:            c.plugins,
 			Targets:            opts.Targets,
+			Excludes:           opts.Excludes,
 			skipRefresh:        opts.SkipRefresh,
 			skipPlanChanges:    true, // this activates "refresh only" mode.
 			Operation:          walkPlan,
@@ -701,6 +850,7 @@ func (c *Context) planGraph(config *configs.Config, prevRunState *states.State,
 			RootVariableValues: opts.SetVariables,
 			Plugins:            c.plugins,
 			Targets:            opts.Targets,
+			Excludes:           opts.Excludes,
 			skipRefresh:        opts.SkipRefresh,
 			Operation:          walkPlanDestroy,
 		}).Build(addrs.RootModuleInstance)
@@ -896,6 +1046,27 @@ func blockedMovesWarningDiag(results refactoring.MoveResults) tfdiags.Diagnostic
 	)
 }
 
+func deferredChangesWarningDiag(changes []plans.DeferredChange) tfdiags.Diagnostic {
+	var itemsBuf bytes.Buffer
+	for _, change := range changes {
+		switch change.Reason {
+		case plans.DeferredReasonInstanceCountUnknown:
+			fmt.Fprintf(&itemsBuf, "\n  - %s, because its count or for_each value is not known yet", change.Addr)
+		default:
+			fmt.Fprintf(&itemsBuf, "\n  - %s, because it depends on deferred objects", change.Addr)
+		}
+	}
+
+	return tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Some changes were deferred",
+		fmt.Sprintf(
+			"The following objects were left out of this plan:%s\n\nAfter applying this plan, run the plan again to plan the changes for these objects.",
+			itemsBuf.String(),
+		),
+	)
+}
+
 // referenceAnalyzer returns a globalref.Analyzer object to help with
 // global analysis of references within the configuration that's attached
 // to the receiving context.
@@ -5,8 +5,10 @@ package tofu
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -1306,6 +1308,231 @@ func TestContext2Plan_movedResourceBasic(t *testing.T) {
 	})
 }
 
+func TestContext2Plan_movedResourceCrossType(t *testing.T) {
+	addrA := mustResourceInstanceAddr("test_object.a")
+	addrB := mustResourceInstanceAddr("test_other.b")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "test_other" "b" {
+				test_string = "converted"
+			}
+
+			moved {
+				from = test_object.a
+				to   = test_other.b
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(addrA, &states.ResourceInstanceObjectSrc{
+			AttrsJSON:     []byte(`{"test_string":"original"}`),
+			SchemaVersion: 2,
+			Private:       []byte(`source`),
+			Status:        states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`))
+	})
+
+	newProvider := func(moveSupported bool) *MockProvider {
+		return &MockProvider{
+			GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+				Provider: providers.Schema{Block: simpleTestSchema()},
+				ResourceTypes: map[string]providers.Schema{
+					"test_object": {Block: simpleTestSchema()},
+					"test_other":  {Block: simpleTestSchema()},
+				},
+				ServerCapabilities: providers.ServerCapabilities{
+					MoveResourceState: moveSupported,
+				},
+			},
+			MoveResourceStateFn: func(req providers.MoveResourceStateRequest) (resp providers.MoveResourceStateResponse) {
+				var source map[string]string
+				if err := json.Unmarshal(req.SourceStateJSON, &source); err != nil {
+					resp.Diagnostics = resp.Diagnostics.Append(err)
+					return resp
+				}
+				resp.TargetState = cty.ObjectVal(map[string]cty.Value{
+					"test_string": cty.StringVal(source["test_string"] + "-converted"),
+					"test_number": cty.NullVal(cty.Number),
+					"test_bool":   cty.NullVal(cty.Bool),
+					"test_list":   cty.NullVal(cty.List(cty.String)),
+					"test_map":    cty.NullVal(cty.Map(cty.String)),
+				})
+				resp.TargetPrivate = []byte(`target`)
+				return resp
+			},
+		}
+	}
+
+	t.Run("supported", func(t *testing.T) {
+		p := newProvider(true)
+		ctx := testContext2(t, &ContextOpts{
+			Providers: map[addrs.Provider]providers.Factory{
+				addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+			},
+		})
+
+		plan, diags := ctx.Plan(m, state, DefaultPlanOpts)
+		assertNoErrors(t, diags)
+
+		if !p.MoveResourceStateCalled {
+			t.Fatalf("provider was not asked to move the resource state")
+		}
+		req := p.MoveResourceStateRequest
+		if got, want := req.SourceProviderAddress, "registry.opentofu.org/hashicorp/test"; got != want {
+			t.Errorf("wrong source provider address\ngot:  %s\nwant: %s", got, want)
+		}
+		if got, want := req.SourceTypeName, "test_object"; got != want {
+			t.Errorf("wrong source type name\ngot:  %s\nwant: %s", got, want)
+		}
+		if got, want := req.SourceSchemaVersion, int64(2); got != want {
+			t.Errorf("wrong source schema version\ngot:  %d\nwant: %d", got, want)
+		}
+		if got, want := string(req.SourcePrivate), "source"; got != want {
+			t.Errorf("wrong source private data\ngot:  %s\nwant: %s", got, want)
+		}
+		if got, want := req.TargetTypeName, "test_other"; got != want {
+			t.Errorf("wrong target type name\ngot:  %s\nwant: %s", got, want)
+		}
+
+		if instPlan := plan.Changes.ResourceInstance(addrA); instPlan != nil {
+			t.Fatalf("unexpected plan for %s; should've moved to %s", addrA, addrB)
+		}
+		instPlan := plan.Changes.ResourceInstance(addrB)
+		if instPlan == nil {
+			t.Fatalf("no plan for %s at all", addrB)
+		}
+		if got, want := instPlan.PrevRunAddr, addrA; !got.Equal(want) {
+			t.Errorf("wrong previous run address\ngot:  %s\nwant: %s", got, want)
+		}
+		if got, want := instPlan.Action, plans.Update; got != want {
+			t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+		}
+
+		prevRunObj := plan.PrevRunState.ResourceInstance(addrB)
+		if prevRunObj == nil || prevRunObj.Current == nil {
+			t.Fatalf("no previous run state for %s", addrB)
+		}
+		if got, want := string(prevRunObj.Current.Private), "target"; got != want {
+			t.Errorf("wrong private data\ngot:  %s\nwant: %s", got, want)
+		}
+		obj, err := prevRunObj.Current.Decode(simpleTestSchema().ImpliedType())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := obj.Value.GetAttr("test_string"), cty.StringVal("original-converted"); !got.RawEquals(want) {
+			t.Errorf("wrong converted value\ngot:  %#v\nwant: %#v", got, want)
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		p := newProvider(false)
+		ctx := testContext2(t, &ContextOpts{
+			Providers: map[addrs.Provider]providers.Factory{
+				addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+			},
+		})
+
+		_, diags := ctx.Plan(m, state, DefaultPlanOpts)
+		if !diags.HasErrors() {
+			t.Fatalf("unexpected success")
+		}
+		if got, want := diags.Err().Error(), "Resource type mismatch"; !strings.Contains(got, want) {
+			t.Errorf("wrong error\ngot:  %s\nwant message containing: %s", got, want)
+		}
+		if p.MoveResourceStateCalled {
+			t.Errorf("provider was asked to move the resource state")
+		}
+	})
+}
+
+func TestContext2Plan_movedResourceCrossTypeDeposed(t *testing.T) {
+	addrA := mustResourceInstanceAddr("test_object.a")
+	addrB := mustResourceInstanceAddr("test_other.b")
+	deposedKey := states.DeposedKey("00000001")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "test_other" "b" {
+				test_string = "current-converted"
+			}
+
+			moved {
+				from = test_object.a
+				to   = test_other.b
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(addrA, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"test_string":"current"}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`))
+		s.SetResourceInstanceDeposed(addrA, deposedKey, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"test_string":"deposed"}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`))
+	})
+
+	// The source type has a different schema than the target type, so the
+	// deposed object must be converted by the provider like the current one.
+	sourceSchema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"test_string": {Type: cty.String, Optional: true},
+		},
+	}
+	p := &MockProvider{
+		GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+			Provider: providers.Schema{Block: simpleTestSchema()},
+			ResourceTypes: map[string]providers.Schema{
+				"test_object": {Block: sourceSchema},
+				"test_other":  {Block: simpleTestSchema()},
+			},
+			ServerCapabilities: providers.ServerCapabilities{
+				MoveResourceState: true,
+			},
+		},
+		MoveResourceStateFn: func(req providers.MoveResourceStateRequest) (resp providers.MoveResourceStateResponse) {
+			var source map[string]string
+			if err := json.Unmarshal(req.SourceStateJSON, &source); err != nil {
+				resp.Diagnostics = resp.Diagnostics.Append(err)
+				return resp
+			}
+			resp.TargetState = cty.ObjectVal(map[string]cty.Value{
+				"test_string": cty.StringVal(source["test_string"] + "-converted"),
+				"test_number": cty.NullVal(cty.Number),
+				"test_bool":   cty.NullVal(cty.Bool),
+				"test_list":   cty.NullVal(cty.List(cty.String)),
+				"test_map":    cty.NullVal(cty.Map(cty.String)),
+			})
+			return resp
+		},
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	change := plan.Changes.ResourceInstanceDeposed(addrB, deposedKey)
+	if change == nil {
+		t.Fatalf("no plan for %s deposed object %s", addrB, deposedKey)
+	}
+	if got, want := change.Action, plans.Delete; got != want {
+		t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+	}
+	decoded, err := change.Decode(simpleTestSchema().ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := decoded.Before.GetAttr("test_string"), cty.StringVal("deposed-converted"); !got.RawEquals(want) {
+		t.Errorf("wrong converted value\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
 func TestContext2Plan_movedResourceCollision(t *testing.T) {
 	addrNoKey := mustResourceInstanceAddr("test_object.a")
 	addrZeroKey := mustResourceInstanceAddr("test_object.a[0]")
@@ -5261,3 +5488,1195 @@ locals {
 		t.Errorf("expected resource to be in planned state")
 	}
 }
+
+func TestContext2Plan_removedResource(t *testing.T) {
+	addrA := mustResourceInstanceAddr("test_object.a")
+	addrB := mustResourceInstanceAddr("test_object.b")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			removed {
+				from = test_object.a
+				lifecycle {
+					destroy = false
+				}
+			}
+
+			removed {
+				from = test_object.b
+				lifecycle {
+					destroy = true
+				}
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(addrA, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`))
+		s.SetResourceInstanceCurrent(addrB, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`))
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	for addr, want := range map[string]plans.Action{
+		addrA.String(): plans.Forget,
+		addrB.String(): plans.Delete,
+	} {
+		t.Run(addr, func(t *testing.T) {
+			instPlan := plan.Changes.ResourceInstance(mustResourceInstanceAddr(addr))
+			if instPlan == nil {
+				t.Fatalf("no plan for %s at all", addr)
+			}
+			if got := instPlan.Action; got != want {
+				t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestContext2Plan_removedModule(t *testing.T) {
+	addr := mustResourceInstanceAddr("module.child.test_object.a")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			removed {
+				from = module.child
+				lifecycle {
+					destroy = false
+				}
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(addr, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`))
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	instPlan := plan.Changes.ResourceInstance(addr)
+	if instPlan == nil {
+		t.Fatalf("no plan for %s at all", addr)
+	}
+	if got, want := instPlan.Action, plans.Forget; got != want {
+		t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestContext2Plan_removedResourceStillDeclared(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "test_object" "a" {
+			}
+
+			removed {
+				from = test_object.a
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want errors")
+	}
+	if got, want := diags.Err().Error(), "Removed resource block still exists"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+	}
+}
+
+func TestContext2Plan_importForEach(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+locals {
+  buckets = {
+    a = "id-a"
+    b = "id-b"
+    c = "id-c"
+  }
+}
+
+resource "test_object" "a" {
+  for_each    = local.buckets
+  test_string = "foo"
+}
+
+import {
+  for_each = local.buckets
+  to       = test_object.a[each.key]
+  id       = each.value
+}
+`,
+	})
+
+	// test_object.a["c"] was already imported by an earlier run, so it
+	// must not be imported again.
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(mustResourceInstanceAddr(`test_object.a["c"]`), &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"test_string":"foo"}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`))
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+	p.ReadResourceResponse = &providers.ReadResourceResponse{
+		NewState: cty.ObjectVal(map[string]cty.Value{
+			"test_string": cty.StringVal("foo"),
+		}),
+	}
+	p.ImportResourceStateResponse = &providers.ImportResourceStateResponse{
+		ImportedResources: []providers.ImportedResource{
+			{
+				TypeName: "test_object",
+				State: cty.ObjectVal(map[string]cty.Value{
+					"test_string": cty.StringVal("foo"),
+				}),
+			},
+		},
+	}
+
+	plan, diags := ctx.Plan(m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	for addr, wantID := range map[string]string{
+		`test_object.a["a"]`: "id-a",
+		`test_object.a["b"]`: "id-b",
+		`test_object.a["c"]`: "",
+	} {
+		t.Run(addr, func(t *testing.T) {
+			instPlan := plan.Changes.ResourceInstance(mustResourceInstanceAddr(addr))
+			if instPlan == nil {
+				t.Fatalf("no plan for %s at all", addr)
+			}
+			if got, want := instPlan.Action, plans.NoOp; got != want {
+				t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+			}
+			switch {
+			case wantID == "" && instPlan.Importing != nil:
+				t.Errorf("unexpected import of %q", instPlan.Importing.ID)
+			case wantID != "" && instPlan.Importing == nil:
+				t.Errorf("expected import change from %q, got non-import change", wantID)
+			case wantID != "" && instPlan.Importing.ID != wantID:
+				t.Errorf("wrong import ID\ngot:  %s\nwant: %s", instPlan.Importing.ID, wantID)
+			}
+		})
+	}
+}
+
+func TestContext2Plan_importForEachTargetDoesNotExist(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  for_each    = toset(["a"])
+  test_string = "foo"
+}
+
+import {
+  for_each = toset(["a", "b"])
+  to       = test_object.a[each.key]
+  id       = "id-${each.key}"
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want errors")
+	}
+	if got, want := diags.Err().Error(), `Importing to resource address test_object.a["b"] is not possible`; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+	}
+}
+
+func TestContext2Plan_importForEachConfigGen(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+import {
+  for_each = toset(["a", "b"])
+  to       = test_object.a[each.key]
+  id       = each.key
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+	p.ImportResourceStateFn = func(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+		return providers.ImportResourceStateResponse{
+			ImportedResources: []providers.ImportedResource{
+				{
+					TypeName: "test_object",
+					State: cty.ObjectVal(map[string]cty.Value{
+						"test_string": cty.StringVal(req.ID),
+						"test_number": cty.NullVal(cty.Number),
+						"test_bool":   cty.NullVal(cty.Bool),
+						"test_list":   cty.NullVal(cty.List(cty.String)),
+						"test_map":    cty.NullVal(cty.Map(cty.String)),
+					}),
+				},
+			},
+		}
+	}
+
+	plan, diags := ctx.Plan(m, states.NewState(), &PlanOpts{
+		Mode:               plans.NormalMode,
+		GenerateConfigPath: "generated.tf", // Actual value here doesn't matter, as long as it is not empty.
+	})
+	assertNoErrors(t, diags)
+
+	for _, key := range []string{"a", "b"} {
+		addr := mustResourceInstanceAddr(fmt.Sprintf("test_object.a[%q]", key))
+		t.Run(addr.String(), func(t *testing.T) {
+			instPlan := plan.Changes.ResourceInstance(addr)
+			if instPlan == nil {
+				t.Fatalf("no plan for %s at all", addr)
+			}
+			if instPlan.Importing == nil || instPlan.Importing.ID != key {
+				t.Errorf("expected import change from %q, got %#v", key, instPlan.Importing)
+			}
+
+			want := fmt.Sprintf(`resource "test_object" "a" {
+  test_bool   = null
+  test_list   = null
+  test_map    = null
+  test_number = null
+  test_string = %q
+}`, key)
+			got := instPlan.GeneratedConfig
+			if diff := cmp.Diff(want, got); len(diff) > 0 {
+				t.Errorf("got:\n%s\nwant:\n%s\ndiff:\n%s", got, want, diff)
+			}
+		})
+	}
+}
+
+func TestContext2Plan_excludes(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  count = 2
+}
+
+resource "test_object" "b" {
+  test_string = test_object.a[0].test_string
+}
+
+resource "test_object" "c" {
+}
+
+output "b" {
+  value = test_object.b.test_string
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	tests := map[string]struct {
+		excludes []addrs.Targetable
+		want     []string
+	}{
+		"whole resource": {
+			excludes: []addrs.Targetable{mustResourceInstanceAddr("test_object.a").ContainingResource()},
+			want:     []string{"test_object.c"},
+		},
+		"single instance": {
+			excludes: []addrs.Targetable{mustResourceInstanceAddr("test_object.a[1]")},
+			want:     []string{"test_object.a[0]", "test_object.c"},
+		},
+		"dependent resource": {
+			excludes: []addrs.Targetable{mustResourceInstanceAddr("test_object.b").ContainingResource()},
+			want:     []string{"test_object.a[0]", "test_object.a[1]", "test_object.c"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			plan, diags := ctx.Plan(m, states.NewState(), &PlanOpts{
+				Mode:     plans.NormalMode,
+				Excludes: test.excludes,
+			})
+			assertNoErrors(t, diags)
+
+			var got []string
+			for _, c := range plan.Changes.Resources {
+				got = append(got, c.Addr.String())
+			}
+			sort.Strings(got)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong planned resources\n%s", diff)
+			}
+			if len(plan.Changes.Outputs) != 0 {
+				t.Errorf("unexpected planned outputs: %#v", plan.Changes.Outputs)
+			}
+
+			wantDiags := tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Warning,
+					"Resource exclusion is in effect",
+					`You are creating a plan with the -exclude option, which means that the result of this plan may not represent all of the changes requested by the current configuration.
+
+The -exclude option is not for routine use, and is provided only for exceptional situations such as recovering from errors or mistakes, or when OpenTofu specifically suggests to use it as part of an error message.`,
+				),
+			}
+			assertDiagnosticsMatch(t, diags, wantDiags)
+		})
+	}
+}
+
+func TestContext2Plan_providerForEach(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+provider "test" {
+  alias    = "by_region"
+  for_each = toset(["a", "b"])
+
+  test_string = each.key
+}
+
+resource "test_object" "x" {
+  for_each = toset(["a", "b"])
+  provider = test.by_region[each.key]
+
+  test_string = each.key
+}
+`,
+	})
+
+	// Each provider instance remembers its own configuration, so that we
+	// can check that every resource instance was planned, and every orphan
+	// refreshed, by the provider instance it belongs to.
+	var mu sync.Mutex
+	var problems []string
+	factory := func() (providers.Interface, error) {
+		p := simpleMockProvider()
+		var region string
+		p.ConfigureProviderFn = func(req providers.ConfigureProviderRequest) (resp providers.ConfigureProviderResponse) {
+			region = req.Config.GetAttr("test_string").AsString()
+			return resp
+		}
+		check := func(addr string, obj cty.Value) {
+			if got := obj.GetAttr("test_string").AsString(); got != region {
+				mu.Lock()
+				problems = append(problems, fmt.Sprintf("%s with test_string %q handled by provider instance %q", addr, got, region))
+				mu.Unlock()
+			}
+		}
+		p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+			if req.ProposedNewState.IsNull() {
+				check("destroyed object", req.PriorState)
+			} else {
+				check("planned object", req.ProposedNewState)
+			}
+			resp.PlannedState = req.ProposedNewState
+			return resp
+		}
+		p.ReadResourceFn = func(req providers.ReadResourceRequest) (resp providers.ReadResourceResponse) {
+			check("refreshed object", req.PriorState)
+			resp.NewState = req.PriorState
+			return resp
+		}
+		return p, nil
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): factory,
+		},
+	})
+
+	providerAddr := mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"].by_region`)
+
+	t.Run("orphan uses the provider instance from state", func(t *testing.T) {
+		problems = nil
+		state := states.BuildState(func(s *states.SyncState) {
+			s.SetResourceInstanceCurrent(
+				mustResourceInstanceAddr(`test_object.x["c"]`),
+				&states.ResourceInstanceObjectSrc{
+					Status:      states.ObjectReady,
+					AttrsJSON:   []byte(`{"test_string":"b"}`),
+					ProviderKey: addrs.StringKey("b"),
+				},
+				providerAddr,
+			)
+		})
+
+		plan, diags := ctx.Plan(m, state, DefaultPlanOpts)
+		assertNoErrors(t, diags)
+
+		got := map[string]plans.Action{}
+		for _, c := range plan.Changes.Resources {
+			got[c.Addr.String()] = c.Action
+		}
+		want := map[string]plans.Action{
+			`test_object.x["a"]`: plans.Create,
+			`test_object.x["b"]`: plans.Create,
+			`test_object.x["c"]`: plans.Delete,
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong planned changes\n%s", diff)
+		}
+		for _, problem := range problems {
+			t.Error(problem)
+		}
+	})
+
+	t.Run("orphan whose provider instance was removed", func(t *testing.T) {
+		problems = nil
+		state := states.BuildState(func(s *states.SyncState) {
+			s.SetResourceInstanceCurrent(
+				mustResourceInstanceAddr(`test_object.x["c"]`),
+				&states.ResourceInstanceObjectSrc{
+					Status:      states.ObjectReady,
+					AttrsJSON:   []byte(`{"test_string":"c"}`),
+					ProviderKey: addrs.StringKey("c"),
+				},
+				providerAddr,
+			)
+		})
+
+		_, diags := ctx.Plan(m, state, DefaultPlanOpts)
+		if !diags.HasErrors() {
+			t.Fatal("succeeded; want error")
+		}
+		if got, want := diags.Err().Error(), "Provider instance not present"; !strings.Contains(got, want) {
+			t.Errorf("missing expected error %q in:\n%s", want, got)
+		}
+	})
+}
+
+func TestContext2Plan_providerForEachModule(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+provider "test" {
+  alias    = "by_region"
+  for_each = toset(["a", "b"])
+
+  test_string = each.key
+}
+
+module "child" {
+  source   = "./child"
+  for_each = toset(["a", "b"])
+
+  providers = {
+    test = test.by_region[each.key]
+  }
+  region = each.key
+}
+`,
+		"child/main.tf": `
+variable "region" {
+  type = string
+}
+
+resource "test_object" "x" {
+  test_string = var.region
+}
+`,
+	})
+
+	var mu sync.Mutex
+	var problems []string
+	factory := func() (providers.Interface, error) {
+		p := simpleMockProvider()
+		var region string
+		p.ConfigureProviderFn = func(req providers.ConfigureProviderRequest) (resp providers.ConfigureProviderResponse) {
+			region = req.Config.GetAttr("test_string").AsString()
+			return resp
+		}
+		p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+			if got := req.ProposedNewState.GetAttr("test_string").AsString(); got != region {
+				mu.Lock()
+				problems = append(problems, fmt.Sprintf("object with test_string %q planned by provider instance %q", got, region))
+				mu.Unlock()
+			}
+			resp.PlannedState = req.ProposedNewState
+			return resp
+		}
+		return p, nil
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): factory,
+		},
+	})
+
+	plan, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	if got, want := len(plan.Changes.Resources), 2; got != want {
+		t.Errorf("wrong number of planned changes %d; want %d", got, want)
+	}
+	for _, problem := range problems {
+		t.Error(problem)
+	}
+}
+
+func TestContext2Plan_ephemeralVariables(t *testing.T) {
+	tests := map[string]struct {
+		config  string
+		wantErr string
+	}{
+		"ephemeral module output in resource argument": {
+			config: `
+variable "token" {
+  type      = string
+  ephemeral = true
+}
+
+module "child" {
+  source = "./child"
+  token  = var.token
+}
+
+resource "test_object" "a" {
+  test_string = module.child.token
+}
+`,
+			wantErr: "Ephemeral value not allowed",
+		},
+		"ephemeral value in provider configuration": {
+			config: `
+variable "token" {
+  type      = string
+  ephemeral = true
+}
+
+provider "test" {
+  test_string = var.token
+}
+
+module "child" {
+  source = "./child"
+  token  = var.token
+}
+
+resource "test_object" "a" {
+  test_string = "static"
+}
+`,
+		},
+		"ephemeral value in resource argument": {
+			config: `
+variable "token" {
+  type      = string
+  ephemeral = true
+}
+
+resource "test_object" "a" {
+  test_string = var.token
+}
+`,
+			wantErr: "Ephemeral value not allowed",
+		},
+		"ephemeral value in root output": {
+			config: `
+variable "token" {
+  type      = string
+  ephemeral = true
+}
+
+output "token" {
+  value = var.token
+}
+`,
+			wantErr: "Output refers to ephemeral values",
+		},
+		"ephemeral value in non-ephemeral module output": {
+			config: `
+variable "token" {
+  type      = string
+  ephemeral = true
+}
+
+module "child" {
+  source = "./child"
+  token  = var.token
+  plain  = true
+}
+`,
+			wantErr: "Output refers to ephemeral values",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := testModuleInline(t, map[string]string{
+				"main.tf": test.config,
+				"child/main.tf": `
+variable "token" {
+  type = string
+}
+
+variable "plain" {
+  type    = bool
+  default = false
+}
+
+output "token" {
+  value     = var.token
+  ephemeral = true
+}
+
+output "plain" {
+  value = var.plain ? var.token : null
+}
+`,
+			})
+
+			p := simpleMockProvider()
+			ctx := testContext2(t, &ContextOpts{
+				Providers: map[addrs.Provider]providers.Factory{
+					addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+				},
+			})
+
+			plan, diags := ctx.Plan(m, states.NewState(), &PlanOpts{
+				Mode: plans.NormalMode,
+				SetVariables: InputValues{
+					"token": &InputValue{
+						Value:      cty.StringVal("secret"),
+						SourceType: ValueFromCaller,
+					},
+				},
+			})
+			if test.wantErr != "" {
+				if !diags.HasErrors() {
+					t.Fatal("succeeded; want error")
+				}
+				if got := diags.Err().Error(); !strings.Contains(got, test.wantErr) {
+					t.Fatalf("missing expected error %q in:\n%s", test.wantErr, got)
+				}
+				return
+			}
+			assertNoErrors(t, diags)
+
+			if got, want := plan.ApplyTimeVariables, []string{"token"}; !cmp.Equal(got, want) {
+				t.Errorf("wrong apply-time variables\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestContext2Plan_variableValidationCrossReferences(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+variable "min_size" {
+  type = number
+}
+
+variable "max_size" {
+  type = number
+
+  validation {
+    condition     = var.max_size >= var.min_size
+    error_message = "max_size must be at least ${var.min_size}."
+  }
+}
+
+module "child" {
+  source = "./child"
+  size   = var.max_size
+}
+`,
+		"child/main.tf": `
+variable "size" {
+  type = number
+
+  validation {
+    condition     = contains(local.sizes, var.size) && data.test_data_source.limits.id != ""
+    error_message = "size must be one of ${join(", ", local.sizes)}."
+  }
+}
+
+locals {
+  sizes = [1, 2, 3]
+}
+
+data "test_data_source" "limits" {
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	p.GetProviderSchemaResponse.DataSources = map[string]providers.Schema{
+		"test_data_source": {
+			Block: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"id": {Type: cty.String, Computed: true},
+				},
+			},
+		},
+	}
+	p.ReadDataSourceFn = func(req providers.ReadDataSourceRequest) (resp providers.ReadDataSourceResponse) {
+		resp.State = cty.ObjectVal(map[string]cty.Value{
+			"id": cty.StringVal("limits"),
+		})
+		return resp
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	tests := map[string]struct {
+		minSize, maxSize int64
+		wantErr          string
+	}{
+		"valid": {
+			minSize: 1,
+			maxSize: 2,
+		},
+		"invalid other variable": {
+			minSize: 3,
+			maxSize: 2,
+			wantErr: "max_size must be at least 3.",
+		},
+		"invalid local value": {
+			minSize: 1,
+			maxSize: 4,
+			wantErr: "size must be one of 1, 2, 3.",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			plan, diags := ctx.Plan(m, states.NewState(), &PlanOpts{
+				Mode: plans.NormalMode,
+				SetVariables: InputValues{
+					"min_size": &InputValue{
+						Value:      cty.NumberIntVal(test.minSize),
+						SourceType: ValueFromCLIArg,
+					},
+					"max_size": &InputValue{
+						Value:      cty.NumberIntVal(test.maxSize),
+						SourceType: ValueFromCLIArg,
+					},
+				},
+			})
+			if test.wantErr != "" {
+				if !diags.HasErrors() {
+					t.Fatal("succeeded; want error")
+				}
+				if got := diags.Err().Error(); !strings.Contains(got, test.wantErr) {
+					t.Fatalf("missing expected error %q in:\n%s", test.wantErr, got)
+				}
+				return
+			}
+			assertNoErrors(t, diags)
+
+			addr := addrs.InputVariable{Name: "size"}.Absolute(addrs.RootModuleInstance.Child("child", addrs.NoKey))
+			if got, want := plan.Checks.GetObjectResult(addr).Status, checks.StatusPass; got != want {
+				t.Errorf("wrong check status for %s\ngot:  %s\nwant: %s", addr, got, want)
+			}
+		})
+	}
+}
+
+func TestContext2Plan_allowDeferral(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "aws_instance" "a" {
+			}
+
+			resource "aws_instance" "b" {
+				for_each = toset([aws_instance.a.id])
+				ami      = each.key
+			}
+
+			resource "aws_instance" "c" {
+				ami = aws_instance.b[aws_instance.a.id].ami
+			}
+
+			resource "aws_instance" "d" {
+				ami = "unrelated"
+			}
+
+			module "child" {
+				source = "./child"
+				count  = aws_instance.a.id == "" ? 0 : 1
+			}
+
+			output "c" {
+				value = aws_instance.c.ami
+			}
+		`,
+		"child/main.tf": `
+			resource "aws_instance" "e" {
+			}
+		`,
+	})
+
+	p := testProvider("aws")
+	p.PlanResourceChangeFn = testDiffFn
+	p.ApplyResourceChangeFn = testApplyFn
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	// Without deferral, the unknown for_each and count are errors as usual.
+	_, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatalf("unexpected success without deferral")
+	}
+
+	opts := &PlanOpts{
+		Mode:          plans.NormalMode,
+		AllowDeferral: true,
+	}
+	plan, diags := ctx.Plan(m, states.NewState(), opts)
+	assertNoErrors(t, diags)
+
+	var gotChanges []string
+	for _, rc := range plan.Changes.Resources {
+		gotChanges = append(gotChanges, fmt.Sprintf("%s %s", rc.Action, rc.Addr))
+	}
+	sort.Strings(gotChanges)
+	wantChanges := []string{
+		"Create aws_instance.a",
+		"Create aws_instance.d",
+	}
+	if diff := cmp.Diff(wantChanges, gotChanges); diff != "" {
+		t.Fatalf("wrong planned changes\n%s", diff)
+	}
+
+	wantDeferred := []plans.DeferredChange{
+		{
+			Addr:   mustAbsResourceAddr("aws_instance.b"),
+			Reason: plans.DeferredReasonInstanceCountUnknown,
+		},
+		{
+			Addr:   mustAbsResourceAddr("aws_instance.c"),
+			Reason: plans.DeferredReasonDeferredPrereq,
+		},
+		{
+			Addr:   addrs.RootModuleInstance.Child("child", addrs.NoKey),
+			Reason: plans.DeferredReasonInstanceCountUnknown,
+		},
+	}
+	if diff := cmp.Diff(wantDeferred, plan.DeferredChanges); diff != "" {
+		t.Fatalf("wrong deferred changes\n%s", diff)
+	}
+
+	state, diags := ctx.Apply(plan, m)
+	assertNoErrors(t, diags)
+
+	// The second round plans everything that was deferred, so it needs no
+	// further deferral.
+	plan, diags = ctx.Plan(m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	gotChanges = nil
+	for _, rc := range plan.Changes.Resources {
+		gotChanges = append(gotChanges, fmt.Sprintf("%s %s", rc.Action, rc.Addr))
+	}
+	sort.Strings(gotChanges)
+	wantChanges = []string{
+		"Create aws_instance.b[\"foo\"]",
+		"Create aws_instance.c",
+		"Create module.child[0].aws_instance.e",
+		"NoOp aws_instance.a",
+		"NoOp aws_instance.d",
+	}
+	if diff := cmp.Diff(wantChanges, gotChanges); diff != "" {
+		t.Fatalf("wrong planned changes in second round\n%s", diff)
+	}
+	if len(plan.DeferredChanges) != 0 {
+		t.Fatalf("unexpected deferred changes in second round: %#v", plan.DeferredChanges)
+	}
+
+	state, diags = ctx.Apply(plan, m)
+	assertNoErrors(t, diags)
+
+	plan, diags = ctx.Plan(m, state, opts)
+	assertNoErrors(t, diags)
+	if !plan.Changes.Empty() {
+		t.Fatalf("configuration did not converge")
+	}
+}
+
+func TestContext2Plan_allowDeferralExistingObjects(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "aws_instance" "a" {
+				require_new = "new"
+			}
+
+			resource "aws_instance" "b" {
+				for_each = toset([aws_instance.a.id])
+			}
+
+			module "child" {
+				source   = "./child"
+				for_each = toset([aws_instance.a.id])
+			}
+
+			output "b" {
+				value = "${aws_instance.a.require_new},${aws_instance.b["old"].require_new}"
+			}
+
+			output "child" {
+				value = keys(module.child)
+			}
+		`,
+		"child/main.tf": `
+			resource "aws_instance" "e" {
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		for _, addr := range []string{
+			`aws_instance.a`,
+			`aws_instance.b["old"]`,
+			`module.child["old"].aws_instance.e`,
+		} {
+			s.SetResourceInstanceCurrent(mustResourceInstanceAddr(addr), &states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"old","require_new":"old"}`),
+				Status:    states.ObjectReady,
+			}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/aws"]`))
+		}
+		s.SetOutputValue(addrs.OutputValue{Name: "b"}.Absolute(addrs.RootModuleInstance), cty.StringVal("old,old"), false)
+		s.SetOutputValue(addrs.OutputValue{Name: "child"}.Absolute(addrs.RootModuleInstance), cty.ListVal([]cty.Value{cty.StringVal("old")}), false)
+	})
+
+	p := testProvider("aws")
+	p.PlanResourceChangeFn = testDiffFn
+	p.ApplyResourceChangeFn = testApplyFn
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, state, &PlanOpts{
+		Mode:          plans.NormalMode,
+		AllowDeferral: true,
+	})
+	assertNoErrors(t, diags)
+
+	// Only aws_instance.a can be planned, and the existing objects of the
+	// deferred resource and module call must not be planned for destruction.
+	for _, rc := range plan.Changes.Resources {
+		if rc.Addr.String() != "aws_instance.a" {
+			t.Errorf("unexpected %s change for %s", rc.Action, rc.Addr)
+		}
+	}
+	if got, want := len(plan.DeferredChanges), 2; got != want {
+		t.Errorf("wrong number of deferred changes %d; want %d", got, want)
+	}
+
+	// The deferred objects are left unchanged by apply, so the outputs
+	// referring to them must use their prior values rather than be cleared.
+	newState, diags := ctx.Apply(plan, m)
+	assertNoErrors(t, diags)
+
+	if newState.ResourceInstance(mustResourceInstanceAddr(`aws_instance.b["old"]`)) == nil {
+		t.Errorf("deferred instance aws_instance.b[\"old\"] was removed from state")
+	}
+	for name, want := range map[string]cty.Value{
+		"b":     cty.StringVal("new,old"),
+		"child": cty.ListVal([]cty.Value{cty.StringVal("old")}),
+	} {
+		got := newState.OutputValue(addrs.OutputValue{Name: name}.Absolute(addrs.RootModuleInstance))
+		if got == nil {
+			t.Errorf("output %q was removed from state", name)
+			continue
+		}
+		if !got.Value.RawEquals(want) {
+			t.Errorf("wrong value for output %q\ngot:  %#v\nwant: %#v", name, got.Value, want)
+		}
+	}
+}
+
+func TestContext2Plan_variableValidationBeforeUse(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+variable "name" {
+  type = string
+
+  validation {
+    condition     = length(var.name) > 3
+    error_message = "name must be longer than 3 characters."
+  }
+}
+
+provider "test" {
+  test_string = var.name
+}
+
+data "test_data_source" "lookup" {
+  name = var.name
+}
+
+resource "test_object" "a" {
+  test_string = var.name
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	p.GetProviderSchemaResponse.DataSources = map[string]providers.Schema{
+		"test_data_source": {
+			Block: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"name": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Plan(m, states.NewState(), &PlanOpts{
+		Mode: plans.NormalMode,
+		SetVariables: InputValues{
+			"name": &InputValue{
+				Value:      cty.StringVal("ab"),
+				SourceType: ValueFromCLIArg,
+			},
+		},
+	})
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want error")
+	}
+	if got, want := diags.Err().Error(), "name must be longer than 3 characters."; !strings.Contains(got, want) {
+		t.Fatalf("missing expected error %q in:\n%s", want, got)
+	}
+
+	// Nothing may use the invalid value.
+	if p.ConfigureProviderCalled {
+		t.Error("provider configured with invalid variable value")
+	}
+	if p.ReadDataSourceCalled {
+		t.Error("data source read with invalid variable value")
+	}
+	if p.PlanResourceChangeCalled {
+		t.Error("resource planned with invalid variable value")
+	}
+}
+
+func TestContext2Plan_variableValidationTargeted(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+variable "name" {
+  type = string
+
+  validation {
+    condition     = var.name != data.test_data_source.reserved.name
+    error_message = "name must not be reserved."
+  }
+}
+
+data "test_data_source" "reserved" {
+}
+
+resource "test_object" "a" {
+  test_string = var.name
+}
+
+resource "test_object" "b" {
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	p.GetProviderSchemaResponse.DataSources = map[string]providers.Schema{
+		"test_data_source": {
+			Block: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"name": {Type: cty.String, Computed: true},
+				},
+			},
+		},
+	}
+	p.ReadDataSourceFn = func(req providers.ReadDataSourceRequest) (resp providers.ReadDataSourceResponse) {
+		resp.State = cty.ObjectVal(map[string]cty.Value{
+			"name": cty.StringVal("admin"),
+		})
+		return resp
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	// The validation of the variable used by the targeted resource is
+	// checked, even though the data source it refers to isn't targeted.
+	_, diags := ctx.Plan(m, states.NewState(), &PlanOpts{
+		Mode: plans.NormalMode,
+		Targets: []addrs.Targetable{
+			mustResourceInstanceAddr("test_object.a"),
+		},
+		SetVariables: InputValues{
+			"name": &InputValue{
+				Value:      cty.StringVal("admin"),
+				SourceType: ValueFromCLIArg,
+			},
+		},
+	})
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want error")
+	}
+	if got, want := diags.Err().Error(), "name must not be reserved."; !strings.Contains(got, want) {
+		t.Fatalf("missing expected error %q in:\n%s", want, got)
+	}
+}
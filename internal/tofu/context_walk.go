@@ -4,9 +4,12 @@
 package tofu
 
 import (
+	"context"
 	"log"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/opentofu/opentofu/internal/checks"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/instances"
@@ -42,12 +45,26 @@ type graphWalkOpts struct {
 	PlanTimeTimestamp time.Time
 
 	MoveResults refactoring.MoveResults
+
+	// Deferrals tracks the objects whose planning is deferred during the
+	// walk. It can be nil if deferral isn't allowed.
+	Deferrals *deferrals
 }
 
 func (c *Context) walk(graph *Graph, operation walkOperation, opts *graphWalkOpts) (*ContextGraphWalker, tfdiags.Diagnostics) {
 	log.Printf("[DEBUG] Starting graph walk: %s", operation.String())
 
+	traceCtx := c.traceContext
+	if traceCtx == nil {
+		traceCtx = context.Background()
+	}
+	traceCtx, span := tracer.Start(traceCtx, "graph walk", trace.WithAttributes(
+		traceAttrWalkOperation.String(operation.String()),
+	))
+	defer span.End()
+
 	walker := c.graphWalker(operation, opts)
+	walker.traceCtx = traceCtx
 
 	// Watch for a stop so we can call the provider Stop() API.
 	watchStop, watchWait := c.watchStop(walker)
@@ -59,6 +76,9 @@ func (c *Context) walk(graph *Graph, operation walkOperation, opts *graphWalkOpt
 	close(watchStop)
 	<-watchWait
 
+	// Shut down any providers that were only started to call their functions.
+	walker.functionProviders.Close()
+
 	return walker, diags
 }
 
@@ -147,6 +167,7 @@ func (c *Context) graphWalker(operation walkOperation, opts *graphWalkOpts) *Con
 		Checks:           checkState,
 		InstanceExpander: instances.NewExpander(),
 		MoveResults:      opts.MoveResults,
+		Deferrals:        opts.Deferrals,
 		Operation:        operation,
 		StopContext:      c.runContext,
 		PlanTimestamp:    opts.PlanTimeTimestamp,
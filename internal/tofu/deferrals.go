@@ -0,0 +1,193 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// deferrals tracks the resources and module calls whose planning was
+// deferred during a graph walk, because the number of their instances, or of
+// the instances of something they depend on, can't be known until apply.
+//
+// A nil *deferrals is valid and behaves as if deferral isn't allowed, so
+// that callers don't need to check for it.
+type deferrals struct {
+	// allow is true if objects may be deferred during the walk. Otherwise,
+	// unknown count and for_each values are errors as usual.
+	allow bool
+
+	mu          sync.Mutex
+	resources   map[string]deferredObject[addrs.AbsResource]
+	moduleCalls map[string]deferredObject[addrs.AbsModuleCall]
+}
+
+type deferredObject[T any] struct {
+	addr   T
+	reason plans.DeferredReason
+}
+
+func newDeferrals(allow bool) *deferrals {
+	return &deferrals{
+		allow:       allow,
+		resources:   make(map[string]deferredObject[addrs.AbsResource]),
+		moduleCalls: make(map[string]deferredObject[addrs.AbsModuleCall]),
+	}
+}
+
+// loadDeferrals returns a deferrals object preloaded with the deferred
+// changes recorded in the given plan, so that the apply walk treats the same
+// objects as deferred.
+func loadDeferrals(changes []plans.DeferredChange) *deferrals {
+	d := newDeferrals(len(changes) > 0)
+	for _, change := range changes {
+		switch addr := change.Addr.(type) {
+		case addrs.AbsResource:
+			d.deferResource(addr, change.Reason)
+		case addrs.ModuleInstance:
+			if addr.IsRoot() {
+				continue
+			}
+			parent, call := addr.Call()
+			d.deferModuleCall(call.Absolute(parent), change.Reason)
+		}
+	}
+	return d
+}
+
+// allowed returns true if objects may be deferred during the walk.
+func (d *deferrals) allowed() bool {
+	return d != nil && d.allow
+}
+
+// deferResource records that the given resource was deferred for the given
+// reason. The first reason recorded for a resource is kept.
+func (d *deferrals) deferResource(addr addrs.AbsResource, reason plans.DeferredReason) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := addr.String()
+	if _, exists := d.resources[key]; !exists {
+		d.resources[key] = deferredObject[addrs.AbsResource]{addr, reason}
+	}
+}
+
+// deferModuleCall records that all instances of the given module call were
+// deferred for the given reason.
+func (d *deferrals) deferModuleCall(addr addrs.AbsModuleCall, reason plans.DeferredReason) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := addr.String()
+	if _, exists := d.moduleCalls[key]; !exists {
+		d.moduleCalls[key] = deferredObject[addrs.AbsModuleCall]{addr, reason}
+	}
+}
+
+// resourceDeferred returns true if the given resource was deferred, either
+// directly or because it belongs to a deferred module call.
+func (d *deferrals) resourceDeferred(addr addrs.AbsResource) bool {
+	if d == nil {
+		return false
+	}
+	if d.moduleInstanceDeferred(addr.Module) {
+		return true
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, exists := d.resources[addr.String()]
+	return exists
+}
+
+// moduleCallDeferred returns true if the given module call was deferred,
+// either directly or because it's nested in another deferred module call.
+func (d *deferrals) moduleCallDeferred(addr addrs.AbsModuleCall) bool {
+	if d == nil {
+		return false
+	}
+	if d.moduleInstanceDeferred(addr.Module) {
+		return true
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, exists := d.moduleCalls[addr.String()]
+	return exists
+}
+
+// moduleInstanceDeferred returns true if the given module instance belongs to
+// a deferred module call, or is nested inside one.
+func (d *deferrals) moduleInstanceDeferred(addr addrs.ModuleInstance) bool {
+	if d == nil {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, step := range addr {
+		if _, exists := d.moduleCalls[addr[:i].ChildCall(step.Name).String()]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// dependsOnDeferred returns true if any of the given dependencies might have
+// been deferred. Dependencies are only known by their configuration
+// addresses, so this is conservative: a dependency counts as deferred if any
+// of its instances across all module instances were.
+func (d *deferrals) dependsOnDeferred(deps []addrs.ConfigResource) bool {
+	if d == nil {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, dep := range deps {
+		for _, res := range d.resources {
+			if res.addr.Config().Equal(dep) {
+				return true
+			}
+		}
+		for _, call := range d.moduleCalls {
+			if call.addr.Instance(addrs.NoKey).Module().TargetContains(dep) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// changes returns the deferred objects as plan deferred changes, in a
+// predictable order.
+func (d *deferrals) changes() []plans.DeferredChange {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var ret []plans.DeferredChange
+	for _, res := range d.resources {
+		ret = append(ret, plans.DeferredChange{
+			Addr:   res.addr,
+			Reason: res.reason,
+		})
+	}
+	for _, call := range d.moduleCalls {
+		ret = append(ret, plans.DeferredChange{
+			Addr:   call.addr.Instance(addrs.NoKey),
+			Reason: call.reason,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Addr.String() < ret[j].Addr.String()
+	})
+	return ret
+}
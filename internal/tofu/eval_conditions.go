@@ -116,17 +116,15 @@ func evalCheckRule(addr addrs.CheckRule, rule *configs.CheckRule, ctx EvalContex
 	// of an otherwise-valid condition.
 	errorMessage, hclCtx, diags := validateCheckRule(addr, rule, ctx, keyData)
 
-	const errInvalidCondition = "Invalid condition result"
-
-	resultVal, hclDiags := rule.Condition.Value(hclCtx)
-	diags = diags.Append(hclDiags)
+	status, moreDiags := evalCheckRuleCondition(rule, hclCtx)
+	diags = diags.Append(moreDiags)
 
 	if diags.HasErrors() {
 		log.Printf("[TRACE] evalCheckRule: %s: %s", addr.Type, diags.Err().Error())
 		return checkResult{Status: checks.StatusError}, diags
 	}
 
-	if !resultVal.IsKnown() {
+	if status == checks.StatusUnknown {
 
 		// Check assertions warn if a status is unknown.
 		if addr.Type == addrs.CheckAssertion {
@@ -146,6 +144,53 @@ func evalCheckRule(addr addrs.CheckRule, rule *configs.CheckRule, ctx EvalContex
 		// We'll wait until we've learned more, then.
 		return checkResult{Status: checks.StatusUnknown}, diags
 	}
+
+	if status != checks.StatusFail {
+		return checkResult{Status: status}, diags
+	}
+
+	errorMessageForDiags := errorMessage
+	if errorMessageForDiags == "" {
+		errorMessageForDiags = "This check failed, but has an invalid error message as described in the other accompanying messages."
+	}
+	diags = diags.Append(&hcl.Diagnostic{
+		// The caller gets to choose the severity of this one, because we
+		// treat condition failures as warnings in the presence of
+		// certain special planning options.
+		Severity:    severity,
+		Summary:     fmt.Sprintf("%s failed", addr.Type.Description()),
+		Detail:      errorMessageForDiags,
+		Subject:     rule.Condition.Range().Ptr(),
+		Expression:  rule.Condition,
+		EvalContext: hclCtx,
+		Extra: &addrs.CheckRuleDiagnosticExtra{
+			CheckRule: addr,
+		},
+	})
+
+	return checkResult{
+		Status:         status,
+		FailureMessage: errorMessage,
+	}, diags
+}
+
+// evalCheckRuleCondition evaluates the condition of the given rule in the
+// given evaluation context, returning checks.StatusUnknown if the result
+// isn't known yet and checks.StatusError if the condition is invalid.
+func evalCheckRuleCondition(rule *configs.CheckRule, hclCtx *hcl.EvalContext) (checks.Status, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	const errInvalidCondition = "Invalid condition result"
+
+	resultVal, hclDiags := rule.Condition.Value(hclCtx)
+	diags = diags.Append(hclDiags)
+	if diags.HasErrors() {
+		return checks.StatusError, diags
+	}
+
+	if !resultVal.IsKnown() {
+		return checks.StatusUnknown, diags
+	}
 	if resultVal.IsNull() {
 		// NOTE: Intentionally not passing the caller's selected severity in here,
 		// because this reports errors in the configuration itself, not the failure
@@ -158,7 +203,7 @@ func evalCheckRule(addr addrs.CheckRule, rule *configs.CheckRule, ctx EvalContex
 			Expression:  rule.Condition,
 			EvalContext: hclCtx,
 		})
-		return checkResult{Status: checks.StatusError}, diags
+		return checks.StatusError, diags
 	}
 	var err error
 	resultVal, err = convert.Convert(resultVal, cty.Bool)
@@ -175,42 +220,14 @@ func evalCheckRule(addr addrs.CheckRule, rule *configs.CheckRule, ctx EvalContex
 			Expression:  rule.Condition,
 			EvalContext: hclCtx,
 		})
-		return checkResult{Status: checks.StatusError}, diags
+		return checks.StatusError, diags
 	}
 
 	// The condition result may be marked if the expression refers to a
 	// sensitive value.
 	resultVal, _ = resultVal.Unmark()
 
-	status := checks.StatusForCtyValue(resultVal)
-
-	if status != checks.StatusFail {
-		return checkResult{Status: status}, diags
-	}
-
-	errorMessageForDiags := errorMessage
-	if errorMessageForDiags == "" {
-		errorMessageForDiags = "This check failed, but has an invalid error message as described in the other accompanying messages."
-	}
-	diags = diags.Append(&hcl.Diagnostic{
-		// The caller gets to choose the severity of this one, because we
-		// treat condition failures as warnings in the presence of
-		// certain special planning options.
-		Severity:    severity,
-		Summary:     fmt.Sprintf("%s failed", addr.Type.Description()),
-		Detail:      errorMessageForDiags,
-		Subject:     rule.Condition.Range().Ptr(),
-		Expression:  rule.Condition,
-		EvalContext: hclCtx,
-		Extra: &addrs.CheckRuleDiagnosticExtra{
-			CheckRule: addr,
-		},
-	})
-
-	return checkResult{
-		Status:         status,
-		FailureMessage: errorMessage,
-	}, diags
+	return checks.StatusForCtyValue(resultVal), diags
 }
 
 // evalCheckErrorMessage makes a best effort to evaluate the given expression,
@@ -4,9 +4,12 @@
 package tofu
 
 import (
+	"context"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/instances"
 	"github.com/opentofu/opentofu/internal/lang"
@@ -35,22 +38,28 @@ type EvalContext interface {
 	// Input is the UIInput object for interacting with the UI.
 	Input() UIInput
 
-	// InitProvider initializes the provider with the given address, and returns
-	// the implementation of the resource provider or an error.
+	// InitProvider initializes the provider with the given address and
+	// instance key, and returns the implementation of the resource provider
+	// or an error. The instance key is addrs.NoKey unless the provider
+	// configuration has for_each.
 	//
 	// It is an error to initialize the same provider more than once. This
 	// method will panic if the module instance address of the given provider
 	// configuration does not match the Path() of the EvalContext.
-	InitProvider(addr addrs.AbsProviderConfig) (providers.Interface, error)
+	//
+	// The given configuration, which may be nil, is used only to determine
+	// whether the provider is mocked by the testing framework.
+	InitProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey, config *configs.Provider) (providers.Interface, error)
 
-	// Provider gets the provider instance with the given address (already
-	// initialized) or returns nil if the provider isn't initialized.
+	// Provider gets the provider instance with the given address and
+	// instance key (already initialized) or returns nil if the provider isn't
+	// initialized.
 	//
 	// This method expects an _absolute_ provider configuration address, since
 	// resources in one module are able to use providers from other modules.
 	// InitProvider must've been called on the EvalContext of the module
 	// that owns the given provider before calling this method.
-	Provider(addrs.AbsProviderConfig) providers.Interface
+	Provider(addr addrs.AbsProviderConfig, key addrs.InstanceKey) providers.Interface
 
 	// ProviderSchema retrieves the schema for a particular provider, which
 	// must have already been initialized with InitProvider.
@@ -59,20 +68,21 @@ type EvalContext interface {
 	// resources in one module are able to use providers from other modules.
 	ProviderSchema(addrs.AbsProviderConfig) (providers.ProviderSchema, error)
 
-	// CloseProvider closes provider connections that aren't needed anymore.
+	// CloseProvider closes provider connections that aren't needed anymore,
+	// for all of the instances of the given provider configuration.
 	//
 	// This method will panic if the module instance address of the given
 	// provider configuration does not match the Path() of the EvalContext.
 	CloseProvider(addrs.AbsProviderConfig) error
 
-	// ConfigureProvider configures the provider with the given
-	// configuration. This is a separate context call because this call
-	// is used to store the provider configuration for inheritance lookups
-	// with ParentProviderConfig().
+	// ConfigureProvider configures the provider instance with the given
+	// address and instance key using the given configuration. This is a
+	// separate context call because this call is used to store the provider
+	// configuration for inheritance lookups with ParentProviderConfig().
 	//
 	// This method will panic if the module instance address of the given
 	// provider configuration does not match the Path() of the EvalContext.
-	ConfigureProvider(addrs.AbsProviderConfig, cty.Value) tfdiags.Diagnostics
+	ConfigureProvider(addrs.AbsProviderConfig, addrs.InstanceKey, cty.Value) tfdiags.Diagnostics
 
 	// ProviderInput and SetProviderInput are used to configure providers
 	// from user input.
@@ -201,7 +211,16 @@ type EvalContext interface {
 	// objects accessible through it.
 	MoveResults() refactoring.MoveResults
 
+	// Deferrals returns the tracker of the resources and module calls whose
+	// planning is deferred because of unknown count or for_each values. The
+	// result is nil if deferral isn't allowed during the current walk.
+	Deferrals() *deferrals
+
 	// WithPath returns a copy of the context with the internal path set to the
 	// path argument.
 	WithPath(path addrs.ModuleInstance) EvalContext
+
+	// WithTraceContext returns a copy of the context that records the trace
+	// spans for provider calls as children of the span in the given context.
+	WithTraceContext(traceCtx context.Context) EvalContext
 }
@@ -5,8 +5,10 @@ package tofu
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 
 	"github.com/hashicorp/hcl/v2"
@@ -14,6 +16,7 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/instances"
 	"github.com/opentofu/opentofu/internal/lang"
@@ -73,6 +76,16 @@ type BuiltinEvalContext struct {
 	PrevRunStateValue     *states.SyncState
 	InstanceExpanderValue *instances.Expander
 	MoveResultsValue      refactoring.MoveResults
+	DeferralsValue        *deferrals
+
+	// FunctionProviders supplies the provider-defined functions available
+	// to each module, if any.
+	FunctionProviders *functionProviders
+
+	// traceCtx, if set, carries the trace span of the graph vertex this
+	// context is being used for. Calls to providers returned by Provider
+	// are recorded as its children.
+	traceCtx context.Context
 }
 
 // BuiltinEvalContext implements EvalContext
@@ -85,6 +98,12 @@ func (ctx *BuiltinEvalContext) WithPath(path addrs.ModuleInstance) EvalContext {
 	return &newCtx
 }
 
+func (ctx *BuiltinEvalContext) WithTraceContext(traceCtx context.Context) EvalContext {
+	newCtx := *ctx
+	newCtx.traceCtx = traceCtx
+	return &newCtx
+}
+
 func (ctx *BuiltinEvalContext) Stopped() <-chan struct{} {
 	// This can happen during tests. During tests, we just block forever.
 	if ctx.StopContext == nil {
@@ -118,10 +137,10 @@ func (ctx *BuiltinEvalContext) Input() UIInput {
 	return ctx.InputValue
 }
 
-func (ctx *BuiltinEvalContext) InitProvider(addr addrs.AbsProviderConfig) (providers.Interface, error) {
+func (ctx *BuiltinEvalContext) InitProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey, config *configs.Provider) (providers.Interface, error) {
 	// If we already initialized, it is an error
-	if p := ctx.Provider(addr); p != nil {
-		return nil, fmt.Errorf("%s is already initialized", addr)
+	if p := ctx.Provider(addr, key); p != nil {
+		return nil, fmt.Errorf("%s is already initialized", providerInstanceString(addr, key))
 	}
 
 	// Warning: make sure to acquire these locks AFTER the call to Provider
@@ -129,30 +148,39 @@ func (ctx *BuiltinEvalContext) InitProvider(addr addrs.AbsProviderConfig) (provi
 	ctx.ProviderLock.Lock()
 	defer ctx.ProviderLock.Unlock()
 
-	key := addr.String()
+	cacheKey := providerInstanceString(addr, key)
 
 	p, err := ctx.Plugins.NewProviderInstance(addr.Provider)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Printf("[TRACE] BuiltinEvalContext: Initialized %q provider for %s", addr.String(), addr)
-	ctx.ProviderCache[key] = p
+	if config != nil && config.IsMocked {
+		log.Printf("[TRACE] BuiltinEvalContext: Mocking %q provider for %s", addr.String(), cacheKey)
+		p = newProviderForMock(p, config.MockResources)
+	}
+
+	log.Printf("[TRACE] BuiltinEvalContext: Initialized %q provider for %s", addr.String(), cacheKey)
+	ctx.ProviderCache[cacheKey] = p
 
 	return p, nil
 }
 
-func (ctx *BuiltinEvalContext) Provider(addr addrs.AbsProviderConfig) providers.Interface {
+func (ctx *BuiltinEvalContext) Provider(addr addrs.AbsProviderConfig, key addrs.InstanceKey) providers.Interface {
 	ctx.ProviderLock.Lock()
 	defer ctx.ProviderLock.Unlock()
 
-	return ctx.ProviderCache[addr.String()]
+	p := ctx.ProviderCache[providerInstanceString(addr, key)]
+	if tp, ok := p.(providers.Traceable); ok && ctx.traceCtx != nil {
+		return tp.WithTraceContext(ctx.traceCtx)
+	}
+	return p
 }
 
 func (ctx *BuiltinEvalContext) ProviderSchema(addr addrs.AbsProviderConfig) (providers.ProviderSchema, error) {
 	// first see if we have already have an initialized provider to avoid
 	// re-loading it only for the schema
-	p := ctx.Provider(addr)
+	p := ctx.Provider(addr, addrs.NoKey)
 	if p != nil {
 		resp := p.GetProviderSchema()
 		// convert any diagnostics here in case this is the first call
@@ -172,17 +200,24 @@ func (ctx *BuiltinEvalContext) CloseProvider(addr addrs.AbsProviderConfig) error
 	ctx.ProviderLock.Lock()
 	defer ctx.ProviderLock.Unlock()
 
-	key := addr.String()
-	provider := ctx.ProviderCache[key]
-	if provider != nil {
-		delete(ctx.ProviderCache, key)
-		return provider.Close()
+	// The instances of a provider configuration with for_each are cached
+	// with their instance keys appended to the configuration address.
+	prefix := addr.String()
+	var errs []error
+	for cacheKey, provider := range ctx.ProviderCache {
+		if cacheKey != prefix && !strings.HasPrefix(cacheKey, prefix+"[") {
+			continue
+		}
+		delete(ctx.ProviderCache, cacheKey)
+		if err := provider.Close(); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-func (ctx *BuiltinEvalContext) ConfigureProvider(addr addrs.AbsProviderConfig, cfg cty.Value) tfdiags.Diagnostics {
+func (ctx *BuiltinEvalContext) ConfigureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey, cfg cty.Value) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 	if !addr.Module.Equal(ctx.Path().Module()) {
 		// This indicates incorrect use of ConfigureProvider: it should be used
@@ -190,9 +225,9 @@ func (ctx *BuiltinEvalContext) ConfigureProvider(addr addrs.AbsProviderConfig, c
 		panic(fmt.Sprintf("%s configured by wrong module %s", addr, ctx.Path()))
 	}
 
-	p := ctx.Provider(addr)
+	p := ctx.Provider(addr, key)
 	if p == nil {
-		diags = diags.Append(fmt.Errorf("%s not initialized", addr))
+		diags = diags.Append(fmt.Errorf("%s not initialized", providerInstanceString(addr, key)))
 		return diags
 	}
 
@@ -424,6 +459,7 @@ func (ctx *BuiltinEvalContext) EvaluationScope(self addrs.Referenceable, source
 	// real situations.
 	if mc := ctx.Evaluator.Config.DescendentForInstance(ctx.PathValue); mc != nil {
 		scope.SetActiveExperiments(mc.Module.ActiveExperiments)
+		scope.ProviderFunctions = ctx.FunctionProviders.ForModule(mc.Module)
 	}
 	return scope
 }
@@ -508,3 +544,7 @@ func (ctx *BuiltinEvalContext) InstanceExpander() *instances.Expander {
 func (ctx *BuiltinEvalContext) MoveResults() refactoring.MoveResults {
 	return ctx.MoveResultsValue
 }
+
+func (ctx *BuiltinEvalContext) Deferrals() *deferrals {
+	return ctx.DeferralsValue
+}
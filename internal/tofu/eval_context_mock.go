@@ -4,10 +4,13 @@
 package tofu
 
 import (
+	"context"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/instances"
 	"github.com/opentofu/opentofu/internal/lang"
@@ -37,11 +40,13 @@ type MockEvalContext struct {
 	InitProviderCalled   bool
 	InitProviderType     string
 	InitProviderAddr     addrs.AbsProviderConfig
+	InitProviderKey      addrs.InstanceKey
 	InitProviderProvider providers.Interface
 	InitProviderError    error
 
 	ProviderCalled   bool
 	ProviderAddr     addrs.AbsProviderConfig
+	ProviderKey      addrs.InstanceKey
 	ProviderProvider providers.Interface
 
 	ProviderSchemaCalled bool
@@ -66,6 +71,7 @@ type MockEvalContext struct {
 		cfg cty.Value) tfdiags.Diagnostics // overrides the other values below, if set
 	ConfigureProviderCalled bool
 	ConfigureProviderAddr   addrs.AbsProviderConfig
+	ConfigureProviderKey    addrs.InstanceKey
 	ConfigureProviderConfig cty.Value
 	ConfigureProviderDiags  tfdiags.Diagnostics
 
@@ -149,6 +155,9 @@ type MockEvalContext struct {
 	MoveResultsCalled  bool
 	MoveResultsResults refactoring.MoveResults
 
+	DeferralsCalled    bool
+	DeferralsDeferrals *deferrals
+
 	InstanceExpanderCalled   bool
 	InstanceExpanderExpander *instances.Expander
 }
@@ -177,16 +186,18 @@ func (c *MockEvalContext) Input() UIInput {
 	return c.InputInput
 }
 
-func (c *MockEvalContext) InitProvider(addr addrs.AbsProviderConfig) (providers.Interface, error) {
+func (c *MockEvalContext) InitProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey, _ *configs.Provider) (providers.Interface, error) {
 	c.InitProviderCalled = true
 	c.InitProviderType = addr.String()
 	c.InitProviderAddr = addr
+	c.InitProviderKey = key
 	return c.InitProviderProvider, c.InitProviderError
 }
 
-func (c *MockEvalContext) Provider(addr addrs.AbsProviderConfig) providers.Interface {
+func (c *MockEvalContext) Provider(addr addrs.AbsProviderConfig, key addrs.InstanceKey) providers.Interface {
 	c.ProviderCalled = true
 	c.ProviderAddr = addr
+	c.ProviderKey = key
 	return c.ProviderProvider
 }
 
@@ -202,10 +213,11 @@ func (c *MockEvalContext) CloseProvider(addr addrs.AbsProviderConfig) error {
 	return nil
 }
 
-func (c *MockEvalContext) ConfigureProvider(addr addrs.AbsProviderConfig, cfg cty.Value) tfdiags.Diagnostics {
+func (c *MockEvalContext) ConfigureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey, cfg cty.Value) tfdiags.Diagnostics {
 
 	c.ConfigureProviderCalled = true
 	c.ConfigureProviderAddr = addr
+	c.ConfigureProviderKey = key
 	c.ConfigureProviderConfig = cfg
 	if c.ConfigureProviderFn != nil {
 		return c.ConfigureProviderFn(addr, cfg)
@@ -335,6 +347,11 @@ func (c *MockEvalContext) WithPath(path addrs.ModuleInstance) EvalContext {
 	return &newC
 }
 
+func (c *MockEvalContext) WithTraceContext(traceCtx context.Context) EvalContext {
+	// The mock records calls on itself, so it isn't copied here.
+	return c
+}
+
 func (c *MockEvalContext) Path() addrs.ModuleInstance {
 	c.PathCalled = true
 	return c.PathPath
@@ -398,6 +415,11 @@ func (c *MockEvalContext) MoveResults() refactoring.MoveResults {
 	return c.MoveResultsResults
 }
 
+func (c *MockEvalContext) Deferrals() *deferrals {
+	c.DeferralsCalled = true
+	return c.DeferralsDeferrals
+}
+
 func (c *MockEvalContext) InstanceExpander() *instances.Expander {
 	c.InstanceExpanderCalled = true
 	return c.InstanceExpanderExpander
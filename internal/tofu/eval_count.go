@@ -23,12 +23,10 @@ import (
 func evaluateCountExpression(expr hcl.Expression, ctx EvalContext) (int, tfdiags.Diagnostics) {
 	countVal, diags := evaluateCountExpressionValue(expr, ctx)
 	if !countVal.IsKnown() {
-		// Currently this is a rather bad outcome from a UX standpoint, since we have
-		// no real mechanism to deal with this situation and all we can do is produce
-		// an error message.
-		// FIXME: In future, implement a built-in mechanism for deferring changes that
-		// can't yet be predicted, and use it to guide the user through several
-		// plan/apply steps until the desired configuration is eventually reached.
+		// Unknown counts can only be deferred when planning with deferral
+		// allowed, which evaluateDeferrableCountExpression handles before
+		// calling this function, so here all we can do is produce an error
+		// message.
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Invalid count argument",
@@ -51,6 +49,27 @@ func evaluateCountExpression(expr hcl.Expression, ctx EvalContext) (int, tfdiags
 	return int(count), diags
 }
 
+// evaluateDeferrableCountExpression is like evaluateCountExpression, except
+// that if deferral is allowed during the current walk then an unknown count
+// value isn't an error, and instead the result has deferred set to true.
+func evaluateDeferrableCountExpression(expr hcl.Expression, ctx EvalContext) (count int, deferred bool, diags tfdiags.Diagnostics) {
+	if !ctx.Deferrals().allowed() {
+		count, diags = evaluateCountExpression(expr, ctx)
+		return count, false, diags
+	}
+
+	countVal, diags := evaluateCountExpressionValue(expr, ctx)
+	switch {
+	case !countVal.IsKnown():
+		return 0, true, diags
+	case countVal.IsNull():
+		return -1, false, diags
+	}
+
+	c, _ := countVal.AsBigFloat().Int64()
+	return int(c), false, diags
+}
+
 // evaluateCountExpressionValue is like evaluateCountExpression
 // except that it returns a cty.Value which must be a cty.Number and can be
 // unknown.
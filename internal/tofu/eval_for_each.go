@@ -36,6 +36,27 @@ func evaluateForEachExpression(expr hcl.Expression, ctx EvalContext) (forEach ma
 	return forEachVal.AsValueMap(), diags
 }
 
+// evaluateDeferrableForEachExpression is like evaluateForEachExpression,
+// except that if deferral is allowed during the current walk then an unknown
+// for_each value isn't an error, and instead the result has deferred set to
+// true.
+func evaluateDeferrableForEachExpression(expr hcl.Expression, ctx EvalContext) (forEach map[string]cty.Value, deferred bool, diags tfdiags.Diagnostics) {
+	if !ctx.Deferrals().allowed() {
+		forEach, diags = evaluateForEachExpression(expr, ctx)
+		return forEach, false, diags
+	}
+
+	forEachVal, diags := evaluateForEachExpressionValue(expr, ctx, true)
+	switch {
+	case !forEachVal.IsKnown():
+		return map[string]cty.Value{}, true, diags
+	case forEachVal.IsNull() || markSafeLengthInt(forEachVal) == 0:
+		return map[string]cty.Value{}, false, diags
+	}
+
+	return forEachVal.AsValueMap(), false, diags
+}
+
 // evaluateForEachExpressionValue is like evaluateForEachExpression
 // except that it returns a cty.Value map or set which can be unknown.
 func evaluateForEachExpressionValue(expr hcl.Expression, ctx EvalContext, allowUnknown bool) (cty.Value, tfdiags.Diagnostics) {
@@ -78,6 +99,16 @@ func evaluateForEachExpressionValue(expr hcl.Expression, ctx EvalContext, allowU
 			Extra:       diagnosticCausedBySensitive(true),
 		})
 	}
+	if forEachVal.HasMark(marks.Ephemeral) {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity:    hcl.DiagError,
+			Summary:     "Invalid for_each argument",
+			Detail:      "Ephemeral values, or values derived from ephemeral values, cannot be used as for_each arguments, because the instance keys are saved in the state.",
+			Subject:     expr.Range().Ptr(),
+			Expression:  expr,
+			EvalContext: hclCtx,
+		})
+	}
 
 	if diags.HasErrors() {
 		return nullMap, diags
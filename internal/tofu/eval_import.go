@@ -2,9 +2,13 @@ package tofu
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/instances"
+	"github.com/opentofu/opentofu/internal/lang"
 	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/zclconf/go-cty/cty"
@@ -73,3 +77,113 @@ func evaluateImportIdExpression(expr hcl.Expression, ctx EvalContext) (string, t
 
 	return importId, diags
 }
+
+// expandImportTargets returns the given import targets with each one whose
+// import block uses for_each replaced by a separate target for each element
+// of its for_each value.
+//
+// Expanded targets whose resource instance is already tracked in the prior
+// state are left out, in the same way as for import blocks without for_each.
+func expandImportTargets(ctx EvalContext, targets []*ImportTarget) ([]*ImportTarget, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	// The import expressions are declared within the root module, so we
+	// need to explicitly use that context.
+	ctx = ctx.WithPath(addrs.RootModuleInstance)
+
+	ret := make([]*ImportTarget, 0, len(targets))
+	seen := make(map[string]*configs.Import)
+	for _, target := range targets {
+		if target.Config == nil || target.Config.ForEach == nil {
+			ret = append(ret, target)
+			seen[target.Addr.String()] = target.Config
+			continue
+		}
+
+		forEach, forEachDiags := evaluateForEachExpression(target.Config.ForEach, ctx)
+		diags = diags.Append(forEachDiags)
+		if forEachDiags.HasErrors() {
+			continue
+		}
+
+		// We visit the elements in a consistent order so that any errors
+		// are reported consistently.
+		keys := make([]string, 0, len(forEach))
+		for k := range forEach {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			keyData := instances.RepetitionData{
+				EachKey:   cty.StringVal(k),
+				EachValue: forEach[k],
+			}
+
+			addr, moreDiags := evaluateImportAddress(ctx, target.Config, keyData)
+			diags = diags.Append(moreDiags)
+			if moreDiags.HasErrors() {
+				continue
+			}
+
+			if prev, exists := seen[addr.String()]; exists {
+				detail := fmt.Sprintf("The import block for_each produces the resource address %s more than once.", addr)
+				if prev != target.Config {
+					detail = fmt.Sprintf("An import block for the resource %s was already declared. A resource can have only one import block.", addr)
+				}
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("Duplicate import configuration for %q", addr),
+					Detail:   detail,
+					Subject:  target.Config.DeclRange.Ptr(),
+				})
+				continue
+			}
+			seen[addr.String()] = target.Config
+
+			if ctx.PrevRunState().ResourceInstance(addr) != nil {
+				// Already imported by an earlier run.
+				continue
+			}
+
+			// The import ID may also refer to each.key and each.value, so we
+			// evaluate it now and record the result as a synthetic
+			// expression for evaluateImportIdExpression to check later.
+			idVal, moreDiags := ctx.EvaluationScope(nil, nil, keyData).EvalExpr(target.ID, cty.String)
+			diags = diags.Append(moreDiags)
+			if moreDiags.HasErrors() {
+				continue
+			}
+
+			ret = append(ret, &ImportTarget{
+				Config: target.Config,
+				Addr:   addr,
+				ID:     hcl.StaticExpr(idVal, target.ID.Range()),
+			})
+		}
+	}
+
+	return ret, diags
+}
+
+// evaluateImportAddress returns the resource instance address that an import
+// block using for_each targets for one element of its for_each value.
+func evaluateImportAddress(ctx EvalContext, cfg *configs.Import, keyData instances.RepetitionData) (addrs.AbsResourceInstance, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	var refs []*addrs.Reference
+	for _, expr := range cfg.ToKeyExprs() {
+		moreRefs, moreDiags := lang.ReferencesInExpr(addrs.ParseRef, expr)
+		diags = diags.Append(moreDiags)
+		refs = append(refs, moreRefs...)
+	}
+	hclCtx, moreDiags := ctx.EvaluationScope(nil, nil, keyData).EvalContext(refs)
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return addrs.AbsResourceInstance{}, diags
+	}
+
+	addr, hclDiags := cfg.EvalTo(hclCtx)
+	diags = diags.Append(hclDiags)
+	return addr, diags
+}
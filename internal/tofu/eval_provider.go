@@ -8,10 +8,13 @@ import (
 	"log"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/instances"
 	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
 func buildProviderConfig(ctx EvalContext, addr addrs.AbsProviderConfig, config *configs.Provider) hcl.Body {
@@ -42,15 +45,17 @@ func buildProviderConfig(ctx EvalContext, addr addrs.AbsProviderConfig, config *
 	}
 }
 
-// getProvider returns the providers.Interface and schema for a given provider.
-func getProvider(ctx EvalContext, addr addrs.AbsProviderConfig) (providers.Interface, providers.ProviderSchema, error) {
+// getProvider returns the providers.Interface and schema for the instance of
+// a provider with the given key, which is addrs.NoKey unless the provider
+// configuration has for_each.
+func getProvider(ctx EvalContext, addr addrs.AbsProviderConfig, key addrs.InstanceKey) (providers.Interface, providers.ProviderSchema, error) {
 	if addr.Provider.Type == "" {
 		// Should never happen
 		panic("GetProvider used with uninitialized provider configuration address")
 	}
-	provider := ctx.Provider(addr)
+	provider := ctx.Provider(addr, key)
 	if provider == nil {
-		return nil, providers.ProviderSchema{}, fmt.Errorf("provider %s not initialized", addr)
+		return nil, providers.ProviderSchema{}, fmt.Errorf("provider %s not initialized", providerInstanceString(addr, key))
 	}
 	// Not all callers require a schema, so we will leave checking for a nil
 	// schema to the callers.
@@ -60,3 +65,91 @@ func getProvider(ctx EvalContext, addr addrs.AbsProviderConfig) (providers.Inter
 	}
 	return provider, schema, nil
 }
+
+// providerInstanceString returns the string representation of the instance
+// of a provider configuration with the given key, such as
+// provider["registry.opentofu.org/hashicorp/aws"].by_region["us-east-1"].
+func providerInstanceString(addr addrs.AbsProviderConfig, key addrs.InstanceKey) string {
+	if key == addrs.NoKey {
+		return addr.String()
+	}
+	return addr.String() + key.String()
+}
+
+// providerKeyExpr describes how to select an instance of a provider
+// configuration with for_each for each instance of a resource that uses it.
+type providerKeyExpr struct {
+	// Expr is the expression that selects the instance key, from the
+	// provider argument of the resource or from the providers argument of a
+	// module call that passes the provider configuration to the resource's
+	// module.
+	Expr hcl.Expression
+
+	// ModuleCall is the path of the module called by the module call whose
+	// providers argument Expr is from, or nil if Expr is from the provider
+	// argument of the resource. Expr is evaluated in the calling module, once
+	// for each instance of the module call, or otherwise once for each
+	// instance of the resource.
+	ModuleCall addrs.Module
+}
+
+// resolveProviderKey returns the instance key of the provider configuration
+// that the given resource instance uses, by evaluating the given provider key
+// expression. It returns addrs.NoKey if keyExpr is nil, which it is unless
+// the provider configuration has for_each.
+//
+// The provider configuration must already have been initialized, so that
+// this can check that it has an instance with the resulting key.
+func resolveProviderKey(ctx EvalContext, addr addrs.AbsResourceInstance, providerAddr addrs.AbsProviderConfig, keyExpr *providerKeyExpr) (addrs.InstanceKey, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if keyExpr == nil {
+		return addrs.NoKey, diags
+	}
+
+	var keyData instances.RepetitionData
+	if keyExpr.ModuleCall == nil {
+		keyData = ctx.InstanceExpander().GetResourceInstanceRepetitionData(addr)
+	} else {
+		callInstance := addr.Module[:len(keyExpr.ModuleCall)]
+		keyData = ctx.InstanceExpander().GetModuleInstanceRepetitionData(callInstance)
+		ctx = ctx.WithPath(callInstance.Parent())
+	}
+
+	scope := ctx.EvaluationScope(nil, nil, keyData)
+	val, evalDiags := scope.EvalExpr(keyExpr.Expr, cty.String)
+	diags = diags.Append(evalDiags)
+	if evalDiags.HasErrors() {
+		return addrs.NoKey, diags
+	}
+
+	var problem string
+	switch {
+	case val.IsNull():
+		problem = "The instance key must not be null."
+	case !val.IsKnown():
+		problem = "The instance key depends on values that cannot be determined until apply, so OpenTofu cannot determine which provider instance to use."
+	case val.IsMarked():
+		problem = "The instance key must not be derived from sensitive values, because it is included in the addresses that OpenTofu shows in its output."
+	}
+	if problem != "" {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider instance key",
+			Detail:   fmt.Sprintf("Cannot select the provider instance for %s: %s", addr, problem),
+			Subject:  keyExpr.Expr.Range().Ptr(),
+		})
+		return addrs.NoKey, diags
+	}
+
+	key := addrs.StringKey(val.AsString())
+	if ctx.Provider(providerAddr, key) == nil {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Provider instance not present",
+			Detail:   fmt.Sprintf("The provider configuration %s has no instance with the key %s, which %s refers to.", providerAddr, key, addr),
+			Subject:  keyExpr.Expr.Range().Ptr(),
+		})
+		return addrs.NoKey, diags
+	}
+	return key, diags
+}
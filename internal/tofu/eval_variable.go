@@ -16,6 +16,7 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/checks"
 	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/lang"
 	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -196,7 +197,8 @@ func prepareFinalInputVariableValue(addr addrs.AbsInputVariableInstance, raw *In
 //
 // This must be used only after any side-effects that make the value of the
 // variable available for use in expression evaluation, such as
-// EvalModuleCallArgument for variables in descendent modules.
+// EvalModuleCallArgument for variables in descendent modules, and with an
+// EvalContext for the module instance where the variable is declared.
 func evalVariableValidations(addr addrs.AbsInputVariableInstance, config *configs.Variable, expr hcl.Expression, ctx EvalContext) (diags tfdiags.Diagnostics) {
 	if config == nil || len(config.Validations) == 0 {
 		log.Printf("[TRACE] evalVariableValidations: no validation rules declared for %s, so skipping", addr)
@@ -214,15 +216,14 @@ func evalVariableValidations(addr addrs.AbsInputVariableInstance, config *config
 		return diags
 	}
 
-	// Variable nodes evaluate in the parent module to where they were declared
-	// because the value expression (n.Expr, if set) comes from the calling
-	// "module" block in the parent module.
+	// Validation rules can refer to other input variables, local values and
+	// data resources declared in the same module as the variable, so ctx
+	// must belong to that module rather than to the calling module.
 	//
-	// Validation expressions are statically validated (during configuration
-	// loading) to refer only to the variable being validated, so we can
-	// bypass our usual evaluation machinery here and just produce a minimal
-	// evaluation context containing just the required value, and thus avoid
-	// the problem that ctx's evaluation functions refer to the wrong module.
+	// The value of the variable being validated is taken directly from its
+	// final value rather than from the evaluation scope, because the scope
+	// returns unknown values for all input variables during the validate walk
+	// while values assigned in a module call may already be known.
 	val := ctx.GetVariableValue(addr)
 	if val == cty.NilVal {
 		diags = diags.Append(&hcl.Diagnostic{
@@ -232,14 +233,32 @@ func evalVariableValidations(addr addrs.AbsInputVariableInstance, config *config
 		})
 		return diags
 	}
-	hclCtx := &hcl.EvalContext{
-		Variables: map[string]cty.Value{
-			"var": cty.ObjectVal(map[string]cty.Value{
-				config.Name: val,
-			}),
-		},
-		Functions: ctx.EvaluationScope(nil, nil, EvalDataForNoInstanceKey).Functions(),
+
+	var refs []*addrs.Reference
+	for _, validation := range config.Validations {
+		for _, expr := range []hcl.Expression{validation.Condition, validation.ErrorMessage} {
+			exprRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, expr)
+			for _, ref := range exprRefs {
+				if subject, ok := ref.Subject.(addrs.InputVariable); ok && subject.Name == config.Name {
+					continue
+				}
+				refs = append(refs, ref)
+			}
+		}
+	}
+	scope := ctx.EvaluationScope(nil, nil, EvalDataForNoInstanceKey)
+	hclCtx, moreDiags := scope.EvalContext(refs)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return diags
+	}
+
+	vars := map[string]cty.Value{}
+	if other, ok := hclCtx.Variables["var"]; ok && other.LengthInt() > 0 {
+		vars = other.AsValueMap()
 	}
+	vars[config.Name] = val
+	hclCtx.Variables["var"] = cty.ObjectVal(vars)
 
 	for ix, validation := range config.Validations {
 		result, ruleDiags := evalVariableValidation(validation, hclCtx, addr, config, expr, ix)
@@ -66,6 +66,10 @@ type Evaluator struct {
 	Changes *plans.ChangesSync
 
 	PlanTimestamp time.Time
+
+	// Deferrals tracks the resources and module calls whose planning was
+	// deferred, whose values are therefore unknown. It can be nil.
+	Deferrals *deferrals
 }
 
 // Scope creates an evaluation scope for the given module path and optional
@@ -262,11 +266,16 @@ func (d *evaluationStateData) GetInputVariable(addr addrs.InputVariable, rng tfd
 	// being liberal in what it accepts because the subsequent plan walk has
 	// more information available and so can be more conservative.
 	if d.Operation == walkValidate {
-		// Ensure variable sensitivity is captured in the validate walk
+		// Ensure variable sensitivity and ephemerality are captured in the
+		// validate walk
+		val := cty.UnknownVal(config.Type)
 		if config.Sensitive {
-			return cty.UnknownVal(config.Type).Mark(marks.Sensitive), diags
+			val = val.Mark(marks.Sensitive)
 		}
-		return cty.UnknownVal(config.Type), diags
+		if config.Ephemeral {
+			val = val.Mark(marks.Ephemeral)
+		}
+		return val, diags
 	}
 
 	moduleAddrStr := d.ModulePath.String()
@@ -300,10 +309,13 @@ func (d *evaluationStateData) GetInputVariable(addr addrs.InputVariable, rng tfd
 		val = cty.UnknownVal(config.Type)
 	}
 
-	// Mark if sensitive
+	// Mark if sensitive or ephemeral
 	if config.Sensitive {
 		val = val.Mark(marks.Sensitive)
 	}
+	if config.Ephemeral {
+		val = val.Mark(marks.Ephemeral)
+	}
 
 	return val, diags
 }
@@ -368,6 +380,13 @@ func (d *evaluationStateData) GetModule(addr addrs.ModuleCall, rng tfdiags.Sourc
 		return cty.DynamicVal, diags
 	}
 
+	// The outputs of a deferred module call won't be known until a later
+	// plan/apply round. Unlike resources, the prior values of child module
+	// outputs aren't saved in the state, so there is nothing to fall back on.
+	if d.Evaluator.Deferrals.moduleCallDeferred(addr.Absolute(d.ModulePath)) {
+		return cty.DynamicVal, diags
+	}
+
 	// We'll consult the configuration to see what output names we are
 	// expecting, so we can ensure the resulting object is of the expected
 	// type even if our data is incomplete for some reason.
@@ -469,6 +488,12 @@ func (d *evaluationStateData) GetModule(addr addrs.ModuleCall, rng tfdiags.Sourc
 			if change.Sensitive {
 				instance[cfg.Name] = change.After.Mark(marks.Sensitive)
 			}
+
+			// Planned changes don't retain marks, so ephemeral outputs
+			// must be marked again from their configuration.
+			if cfg.Ephemeral {
+				instance[cfg.Name] = instance[cfg.Name].Mark(marks.Ephemeral)
+			}
 		}
 	}
 
@@ -682,6 +707,13 @@ func (d *evaluationStateData) GetResource(addr addrs.Resource, rng tfdiags.Sourc
 
 	rs := d.Evaluator.State.Resource(addr.Absolute(d.ModulePath))
 
+	// The instances of a deferred resource won't be known until a later
+	// plan/apply round. Its existing instances are left untouched until then,
+	// so once planning is done we can still use their prior values.
+	if d.Evaluator.Deferrals.resourceDeferred(addr.Absolute(moduleAddr)) && (rs == nil || d.Operation == walkPlan) {
+		return cty.DynamicVal, diags
+	}
+
 	if rs == nil {
 		switch d.Operation {
 		case walkPlan, walkApply:
@@ -4,6 +4,7 @@
 package tofu
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -35,15 +36,17 @@ func (g *Graph) DirectedGraph() dag.Grapher {
 // will be walked with full parallelism, so the walker should expect
 // to be called in concurrently.
 func (g *Graph) Walk(walker GraphWalker) tfdiags.Diagnostics {
-	return g.walk(walker)
+	return g.walk(walker.TraceContext(), walker)
 }
 
-func (g *Graph) walk(walker GraphWalker) tfdiags.Diagnostics {
+// walk walks the graph, recording the trace spans for its vertices as
+// children of the span in the given context.
+func (g *Graph) walk(traceCtx context.Context, walker GraphWalker) tfdiags.Diagnostics {
 	// The callbacks for enter/exiting a graph
 	ctx := walker.EvalContext()
 
 	// Walk the graph.
-	walkFn := func(v dag.Vertex) (diags tfdiags.Diagnostics) {
+	walkFn := func(traceCtx context.Context, v dag.Vertex) (diags tfdiags.Diagnostics) {
 		// the walkFn is called asynchronously, and needs to be recovered
 		// separately in the case of a panic.
 		defer logging.PanicHandler()
@@ -73,6 +76,10 @@ func (g *Graph) walk(walker GraphWalker) tfdiags.Diagnostics {
 			defer walker.ExitPath(pn.Path())
 		}
 
+		// Provider calls and other work done for this vertex are recorded
+		// under the vertex's own trace span.
+		vertexCtx = vertexCtx.WithTraceContext(traceCtx)
+
 		// If the node is exec-able, then execute it.
 		if ev, ok := v.(GraphNodeExecutable); ok {
 			diags = diags.Append(walker.Execute(vertexCtx, ev))
@@ -116,7 +123,7 @@ func (g *Graph) walk(walker GraphWalker) tfdiags.Diagnostics {
 
 				// Walk the subgraph
 				log.Printf("[TRACE] vertex %q: entering dynamic subgraph", dag.VertexName(v))
-				subDiags := g.walk(walker)
+				subDiags := g.walk(traceCtx, walker)
 				diags = diags.Append(subDiags)
 				if subDiags.HasErrors() {
 					var errs []string
@@ -134,5 +141,5 @@ func (g *Graph) walk(walker GraphWalker) tfdiags.Diagnostics {
 		return
 	}
 
-	return g.AcyclicGraph.Walk(walkFn)
+	return g.AcyclicGraph.WalkContext(traceCtx, walkFn)
 }
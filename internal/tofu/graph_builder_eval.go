@@ -96,6 +96,10 @@ func (b *EvalGraphBuilder) Steps() []GraphTransformer {
 		// have to connect again later for providers and so on.
 		&ReferenceTransformer{},
 
+		// Make everything that refers to a variable wait for its validation
+		// rules to be checked.
+		&variableValidationTransformer{},
+
 		// Although we don't configure providers, we do still start them up
 		// to get their schemas, and so we must shut them down again here.
 		&CloseProviderTransformer{},
@@ -38,6 +38,7 @@ type ContextGraphWalker struct {
 	InstanceExpander   *instances.Expander // Tracks our gradual expansion of module and resource instances
 	Imports            []configs.Import
 	MoveResults        refactoring.MoveResults // Read-only record of earlier processing of move statements
+	Deferrals          *deferrals              // Tracks the objects whose planning is deferred
 	Operation          walkOperation
 	StopContext        context.Context
 	RootVariableValues InputValues
@@ -48,6 +49,7 @@ type ContextGraphWalker struct {
 	// is in progress.
 	NonFatalDiagnostics tfdiags.Diagnostics
 
+	traceCtx           context.Context
 	once               sync.Once
 	contexts           map[string]*BuiltinEvalContext
 	contextLock        sync.Mutex
@@ -59,6 +61,7 @@ type ContextGraphWalker struct {
 	provisionerCache   map[string]provisioners.Interface
 	provisionerSchemas map[string]*configschema.Block
 	provisionerLock    sync.Mutex
+	functionProviders  *functionProviders
 }
 
 func (w *ContextGraphWalker) EnterPath(path addrs.ModuleInstance) EvalContext {
@@ -76,6 +79,13 @@ func (w *ContextGraphWalker) EnterPath(path addrs.ModuleInstance) EvalContext {
 	return ctx
 }
 
+func (w *ContextGraphWalker) TraceContext() context.Context {
+	if w.traceCtx == nil {
+		return context.Background()
+	}
+	return w.traceCtx
+}
+
 func (w *ContextGraphWalker) EvalContext() EvalContext {
 	w.once.Do(w.init)
 
@@ -92,6 +102,7 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 		VariableValues:     w.variableValues,
 		VariableValuesLock: &w.variableValuesLock,
 		PlanTimestamp:      w.PlanTimestamp,
+		Deferrals:          w.Deferrals,
 	}
 
 	ctx := &BuiltinEvalContext{
@@ -101,6 +112,7 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 		InstanceExpanderValue: w.InstanceExpander,
 		Plugins:               w.Context.plugins,
 		MoveResultsValue:      w.MoveResults,
+		DeferralsValue:        w.Deferrals,
 		ProviderCache:         w.providerCache,
 		ProviderInputConfig:   w.Context.providerInputConfig,
 		ProviderLock:          &w.providerLock,
@@ -114,6 +126,7 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 		Evaluator:             evaluator,
 		VariableValues:        w.variableValues,
 		VariableValuesLock:    &w.variableValuesLock,
+		FunctionProviders:     w.functionProviders,
 	}
 
 	return ctx
@@ -125,6 +138,7 @@ func (w *ContextGraphWalker) init() {
 	w.providerSchemas = make(map[string]providers.ProviderSchema)
 	w.provisionerCache = make(map[string]provisioners.Interface)
 	w.provisionerSchemas = make(map[string]*configschema.Block)
+	w.functionProviders = newFunctionProviders(w.Context.plugins)
 	w.variableValues = make(map[string]map[string]cty.Value)
 
 	// Populate root module variable values. Other modules will be populated
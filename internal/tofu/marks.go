@@ -7,7 +7,12 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
 // filterMarks removes any PathValueMarks from marks which cannot be applied to
@@ -56,3 +61,26 @@ func marksEqual(a, b []cty.PathValueMarks) bool {
 
 	return true
 }
+
+// validateNoEphemeralValues returns an error for each attribute of the given
+// resource instance configuration value that is derived from an ephemeral
+// value. Resource instance objects are saved in both the plan and the state,
+// so ephemeral values must never reach them.
+func validateNoEphemeralValues(addr addrs.AbsResourceInstance, configVal cty.Value, body hcl.Body) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	_, pvms := configVal.UnmarkDeepWithPaths()
+	for _, pvm := range pvms {
+		if _, ok := pvm.Marks[marks.Ephemeral]; !ok {
+			continue
+		}
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Ephemeral value not allowed",
+			fmt.Sprintf("The arguments of %s are saved in the plan and state, so they cannot be derived from ephemeral values.", addr),
+			pvm.Path,
+		))
+	}
+
+	return diags.InConfigBody(body, addr.String())
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/dag"
 	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -53,7 +54,9 @@ func (n *nodeExpandModule) References() []*addrs.Reference {
 	refs = append(refs, n.DependsOn()...)
 
 	// Expansion only uses the count and for_each expressions, so this
-	// particular graph node only refers to those.
+	// particular graph node only refers to those and to the expressions that
+	// select provider instances for the child module instances, which are
+	// evaluated in the same namespace.
 	// Individual variable values in the module call definition might also
 	// refer to other objects, but that's handled by
 	// NodeApplyableModuleVariable.
@@ -71,6 +74,10 @@ func (n *nodeExpandModule) References() []*addrs.Reference {
 		forEachRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, n.ModuleCall.ForEach)
 		refs = append(refs, forEachRefs...)
 	}
+	for _, passed := range n.ModuleCall.Providers {
+		keyRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, passed.InParent.KeyExpression)
+		refs = append(refs, keyRefs...)
+	}
 	return refs
 }
 
@@ -113,19 +120,29 @@ func (n *nodeExpandModule) Execute(ctx EvalContext, op walkOperation) (diags tfd
 		ctx = ctx.WithPath(module)
 		switch {
 		case n.ModuleCall.Count != nil:
-			count, ctDiags := evaluateCountExpression(n.ModuleCall.Count, ctx)
+			count, deferred, ctDiags := evaluateDeferrableCountExpression(n.ModuleCall.Count, ctx)
 			diags = diags.Append(ctDiags)
 			if diags.HasErrors() {
 				return diags
 			}
+			if deferred {
+				// Registering no instances means that nothing inside the
+				// module call is planned until a later round.
+				log.Printf("[TRACE] nodeExpandModule: deferring %s because its count is unknown", call.Absolute(module))
+				ctx.Deferrals().deferModuleCall(call.Absolute(module), plans.DeferredReasonInstanceCountUnknown)
+			}
 			expander.SetModuleCount(module, call, count)
 
 		case n.ModuleCall.ForEach != nil:
-			forEach, feDiags := evaluateForEachExpression(n.ModuleCall.ForEach, ctx)
+			forEach, deferred, feDiags := evaluateDeferrableForEachExpression(n.ModuleCall.ForEach, ctx)
 			diags = diags.Append(feDiags)
 			if diags.HasErrors() {
 				return diags
 			}
+			if deferred {
+				log.Printf("[TRACE] nodeExpandModule: deferring %s because its for_each is unknown", call.Absolute(module))
+				ctx.Deferrals().deferModuleCall(call.Absolute(module), plans.DeferredReasonInstanceCountUnknown)
+			}
 			expander.SetModuleForEach(module, call, forEach)
 
 		default:
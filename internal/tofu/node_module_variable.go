@@ -189,7 +189,9 @@ func (n *nodeModuleVariable) Execute(ctx EvalContext, op walkOperation) (diags t
 	_, call := n.Addr.Module.CallInstance()
 	ctx.SetModuleCallArgument(call, n.Addr.Variable, val)
 
-	return evalVariableValidations(n.Addr, n.Config, n.Expr, ctx)
+	// Custom validation rules are checked separately by
+	// nodeVariableValidation, in the scope of the child module.
+	return diags
 }
 
 // dag.GraphNodeDotter impl.
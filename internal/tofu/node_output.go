@@ -370,6 +370,38 @@ If you do intend to export this data, annotate the output value as sensitive by
 				})
 			}
 		}
+
+		// Ephemeral values must never be saved, so they can only be returned
+		// by the output values of child modules that are declared as
+		// ephemeral, which are not saved in the state.
+		switch {
+		case n.Addr.Module.IsRoot() && n.Config.Ephemeral:
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Ephemeral output not allowed",
+				Detail:   "Root module output values are saved in the state, so they cannot be ephemeral. Only the output values of child modules can be declared with ephemeral = true.",
+				Subject:  n.Config.DeclRange.Ptr(),
+			})
+		case n.Addr.Module.IsRoot() && marks.Contains(val, marks.Ephemeral):
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Output refers to ephemeral values",
+				Detail:   "Root module output values are saved in the state, so they cannot refer to ephemeral values.",
+				Subject:  n.Config.DeclRange.Ptr(),
+			})
+		case !n.Config.Ephemeral && marks.Contains(val, marks.Ephemeral):
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Output refers to ephemeral values",
+				Detail: `OpenTofu requires that any module output value containing ephemeral data be explicitly marked as ephemeral, to confirm that it's never saved in the plan or state.
+
+If you do intend to return this data, annotate the output value as ephemeral by adding the following argument:
+    ephemeral = true`,
+				Subject: n.Config.DeclRange.Ptr(),
+			})
+		case n.Config.Ephemeral:
+			val = val.Mark(marks.Ephemeral)
+		}
 	}
 
 	// handling the interpolation error
@@ -399,6 +431,18 @@ If you do intend to export this data, annotate the output value as sensitive by
 		}
 		return diags
 	}
+
+	// A root output that still depends on objects deferred during planning
+	// can't be known until a later round, but those objects are left as they
+	// were, so we keep the prior value of the output rather than clearing it.
+	if !n.Planning && n.Addr.Module.IsRoot() && !val.IsWhollyKnown() && ctx.Deferrals().allowed() {
+		log.Printf("[TRACE] NodeApplyableOutput: keeping prior value of %s, which depends on deferred objects", n.Addr)
+		if changes != nil {
+			changes.RemoveOutputChange(n.Addr)
+		}
+		return diags
+	}
+
 	n.setValue(state, changes, val)
 
 	// If we were able to evaluate a new value, we can update that in the
@@ -8,6 +8,7 @@ import (
 	"log"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -25,31 +26,56 @@ var (
 
 // GraphNodeExecutable
 func (n *NodeApplyableProvider) Execute(ctx EvalContext, op walkOperation) (diags tfdiags.Diagnostics) {
-	_, err := ctx.InitProvider(n.Addr)
+	if op == walkValidate {
+		// Validation doesn't configure the provider, so a single instance
+		// is enough to validate the configurations of all of the instances
+		// of a provider configuration with for_each.
+		return n.executeInstance(ctx, op, addrs.NoKey)
+	}
+
+	for _, key := range n.instanceKeys() {
+		diags = diags.Append(n.executeInstance(ctx, op, key))
+	}
+	return diags
+}
+
+// executeInstance initializes the instance of the provider with the given key
+// and then validates or configures it as appropriate for the operation.
+func (n *NodeApplyableProvider) executeInstance(ctx EvalContext, op walkOperation, key addrs.InstanceKey) (diags tfdiags.Diagnostics) {
+	_, err := ctx.InitProvider(n.Addr, key, n.Config)
 	diags = diags.Append(err)
 	if diags.HasErrors() {
 		return diags
 	}
-	provider, _, err := getProvider(ctx, n.Addr)
+	provider, _, err := getProvider(ctx, n.Addr, key)
 	diags = diags.Append(err)
 	if diags.HasErrors() {
 		return diags
 	}
 
+	if n.Config != nil && n.Config.IsMocked {
+		// Mocked providers are never validated or configured, since the
+		// testing framework never lets them act on any remote objects.
+		log.Printf("[TRACE] NodeApplyableProvider: skipping configuration for mocked provider %s", n.Addr)
+		return diags
+	}
+
 	switch op {
 	case walkValidate:
 		log.Printf("[TRACE] NodeApplyableProvider: validating configuration for %s", n.Addr)
 		return diags.Append(n.ValidateProvider(ctx, provider))
 	case walkPlan, walkPlanDestroy, walkApply, walkDestroy:
-		log.Printf("[TRACE] NodeApplyableProvider: configuring %s", n.Addr)
-		return diags.Append(n.ConfigureProvider(ctx, provider, false))
+		log.Printf("[TRACE] NodeApplyableProvider: configuring %s", providerInstanceString(n.Addr, key))
+		return diags.Append(n.ConfigureProvider(ctx, key, provider, false))
 	case walkImport:
-		log.Printf("[TRACE] NodeApplyableProvider: configuring %s (requiring that configuration is wholly known)", n.Addr)
-		return diags.Append(n.ConfigureProvider(ctx, provider, true))
+		log.Printf("[TRACE] NodeApplyableProvider: configuring %s (requiring that configuration is wholly known)", providerInstanceString(n.Addr, key))
+		return diags.Append(n.ConfigureProvider(ctx, key, provider, true))
 	}
 	return diags
 }
 
+// ValidateProvider validates the configuration of each instance of the
+// provider configuration using the given unconfigured provider.
 func (n *NodeApplyableProvider) ValidateProvider(ctx EvalContext, provider providers.Interface) (diags tfdiags.Diagnostics) {
 
 	configBody := buildProviderConfig(ctx, n.Addr, n.ProviderConfig())
@@ -76,30 +102,33 @@ func (n *NodeApplyableProvider) ValidateProvider(ctx EvalContext, provider provi
 		configSchema = &configschema.Block{}
 	}
 
-	configVal, _, evalDiags := ctx.EvaluateBlock(configBody, configSchema, nil, EvalDataForNoInstanceKey)
-	if evalDiags.HasErrors() {
-		return diags.Append(evalDiags)
-	}
-	diags = diags.Append(evalDiags)
+	for _, key := range n.instanceKeys() {
+		configVal, _, evalDiags := ctx.EvaluateBlock(configBody, configSchema, nil, n.instanceData(key))
+		if evalDiags.HasErrors() {
+			return diags.Append(evalDiags)
+		}
+		diags = diags.Append(evalDiags)
 
-	// If our config value contains any marked values, ensure those are
-	// stripped out before sending this to the provider
-	unmarkedConfigVal, _ := configVal.UnmarkDeep()
+		// If our config value contains any marked values, ensure those are
+		// stripped out before sending this to the provider
+		unmarkedConfigVal, _ := configVal.UnmarkDeep()
 
-	req := providers.ValidateProviderConfigRequest{
-		Config: unmarkedConfigVal,
-	}
+		req := providers.ValidateProviderConfigRequest{
+			Config: unmarkedConfigVal,
+		}
 
-	validateResp := provider.ValidateProviderConfig(req)
-	diags = diags.Append(validateResp.Diagnostics.InConfigBody(configBody, n.Addr.String()))
+		validateResp := provider.ValidateProviderConfig(req)
+		diags = diags.Append(validateResp.Diagnostics.InConfigBody(configBody, providerInstanceString(n.Addr, key)))
+	}
 
 	return diags
 }
 
-// ConfigureProvider configures a provider that is already initialized and retrieved.
+// ConfigureProvider configures the instance of a provider with the given key
+// that is already initialized and retrieved.
 // If verifyConfigIsKnown is true, ConfigureProvider will return an error if the
 // provider configVal is not wholly known and is meant only for use during import.
-func (n *NodeApplyableProvider) ConfigureProvider(ctx EvalContext, provider providers.Interface, verifyConfigIsKnown bool) (diags tfdiags.Diagnostics) {
+func (n *NodeApplyableProvider) ConfigureProvider(ctx EvalContext, key addrs.InstanceKey, provider providers.Interface, verifyConfigIsKnown bool) (diags tfdiags.Diagnostics) {
 	config := n.ProviderConfig()
 
 	configBody := buildProviderConfig(ctx, n.Addr, config)
@@ -111,7 +140,7 @@ func (n *NodeApplyableProvider) ConfigureProvider(ctx EvalContext, provider prov
 	}
 
 	configSchema := resp.Provider.Block
-	configVal, configBody, evalDiags := ctx.EvaluateBlock(configBody, configSchema, nil, EvalDataForNoInstanceKey)
+	configVal, configBody, evalDiags := ctx.EvaluateBlock(configBody, configSchema, nil, n.instanceData(key))
 	diags = diags.Append(evalDiags)
 	if evalDiags.HasErrors() {
 		return diags
@@ -121,7 +150,7 @@ func (n *NodeApplyableProvider) ConfigureProvider(ctx EvalContext, provider prov
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Invalid provider configuration",
-			Detail:   fmt.Sprintf("The configuration for %s depends on values that cannot be determined until apply.", n.Addr),
+			Detail:   fmt.Sprintf("The configuration for %s depends on values that cannot be determined until apply.", providerInstanceString(n.Addr, key)),
 			Subject:  &config.DeclRange,
 		})
 		return diags
@@ -163,7 +192,7 @@ func (n *NodeApplyableProvider) ConfigureProvider(ctx EvalContext, provider prov
 		log.Printf("[WARN] ValidateProviderConfig from %q changed the config value, but that value is unused", n.Addr)
 	}
 
-	configDiags := ctx.ConfigureProvider(n.Addr, unmarkedConfigVal)
+	configDiags := ctx.ConfigureProvider(n.Addr, key, unmarkedConfigVal)
 	diags = diags.Append(configDiags.InConfigBody(configBody, n.Addr.String()))
 	if diags.HasErrors() && config == nil {
 		// If there isn't an explicit "provider" block in the configuration,
@@ -4,6 +4,8 @@
 package tofu
 
 import (
+	"sort"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
@@ -76,6 +78,33 @@ func (n *NodeAbstractProvider) ProviderConfig() *configs.Provider {
 	return n.Config
 }
 
+// instanceKeys returns the keys of the instances of the provider
+// configuration in a predictable order. A provider configuration without
+// for_each has a single instance with addrs.NoKey.
+func (n *NodeAbstractProvider) instanceKeys() []addrs.InstanceKey {
+	if n.Config == nil || n.Config.ForEach == nil {
+		return []addrs.InstanceKey{addrs.NoKey}
+	}
+
+	keys := make([]addrs.InstanceKey, 0, len(n.Config.Instances))
+	for key := range n.Config.Instances {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return addrs.InstanceKeyLess(keys[i], keys[j])
+	})
+	return keys
+}
+
+// instanceData returns the values of "each.key" and "each.value" for the
+// instance of the provider configuration with the given key.
+func (n *NodeAbstractProvider) instanceData(key addrs.InstanceKey) InstanceKeyEvalData {
+	if n.Config == nil || n.Config.ForEach == nil {
+		return EvalDataForNoInstanceKey
+	}
+	return n.Config.Instances[key]
+}
+
 // GraphNodeAttachProvider
 func (n *NodeAbstractProvider) AttachProvider(c *configs.Provider) {
 	n.Config = c
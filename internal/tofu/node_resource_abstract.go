@@ -7,11 +7,16 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/hashicorp/hcl/v2"
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/dag"
 	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -65,12 +70,24 @@ type NodeAbstractResource struct {
 	// Set from GraphNodeTargetable
 	Targets []addrs.Targetable
 
+	// Set from GraphNodeExcludable
+	Excludes []addrs.Targetable
+
 	// Set from AttachDataResourceDependsOn
 	dependsOn      []addrs.ConfigResource
 	forceDependsOn bool
 
 	// The address of the provider this resource will use
 	ResolvedProvider addrs.AbsProviderConfig
+	// ResolvedProviderKeyExpr selects the instance of the resolved provider
+	// configuration for each instance of the resource if the configuration
+	// has for_each, and is nil otherwise.
+	ResolvedProviderKeyExpr *providerKeyExpr
+	// ResolvedProviderKey is the instance key of the resolved provider
+	// configuration that a resource instance uses, which is set by the
+	// instance nodes before they use the provider. It's addrs.NoKey unless
+	// the provider configuration has for_each.
+	ResolvedProviderKey addrs.InstanceKey
 	// storedProviderConfig is the provider address retrieved from the
 	// state. This is defined here for access within the ProvidedBy method, but
 	// will be set from the embedding instance type when the state is attached.
@@ -97,6 +114,7 @@ var (
 	_ GraphNodeTargetable                  = (*NodeAbstractResource)(nil)
 	_ graphNodeAttachDataResourceDependsOn = (*NodeAbstractResource)(nil)
 	_ dag.GraphNodeDotter                  = (*NodeAbstractResource)(nil)
+	_ dag.TracedVertex                     = (*NodeAbstractResource)(nil)
 )
 
 // NewNodeAbstractResource creates an abstract resource graph node for
@@ -123,6 +141,7 @@ var (
 	_ GraphNodeAttachProviderMetaConfigs = (*NodeAbstractResourceInstance)(nil)
 	_ GraphNodeTargetable                = (*NodeAbstractResourceInstance)(nil)
 	_ dag.GraphNodeDotter                = (*NodeAbstractResourceInstance)(nil)
+	_ dag.TracedVertex                   = (*NodeAbstractResourceInstance)(nil)
 )
 
 func (n *NodeAbstractResource) Name() string {
@@ -134,6 +153,14 @@ func (n *NodeAbstractResource) ModulePath() addrs.Module {
 	return n.Addr.Module
 }
 
+// dag.TracedVertex
+func (n *NodeAbstractResource) TraceAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		traceAttrResourceAddress.String(n.ResourceAddr().String()),
+		traceAttrModulePath.String(n.Addr.Module.String()),
+	}
+}
+
 // GraphNodeReferenceable
 func (n *NodeAbstractResource) ReferenceableAddrs() []addrs.Referenceable {
 	return []addrs.Referenceable{n.Addr.Resource}
@@ -157,6 +184,11 @@ func (n *NodeAbstractResource) References() []*addrs.Reference {
 		refs, _ = lang.ReferencesInExpr(addrs.ParseRef, c.ForEach)
 		result = append(result, refs...)
 
+		if c.ProviderConfigRef != nil {
+			refs, _ = lang.ReferencesInExpr(addrs.ParseRef, c.ProviderConfigRef.KeyExpression)
+			result = append(result, refs...)
+		}
+
 		for _, expr := range c.TriggersReplacement {
 			refs, _ = lang.ReferencesInExpr(addrs.ParseRef, expr)
 			result = append(result, refs...)
@@ -215,6 +247,15 @@ func (n *NodeAbstractResource) RootReferences() []*addrs.Reference {
 	for _, importTarget := range n.importTargets {
 		refs, _ := lang.ReferencesInExpr(addrs.ParseRef, importTarget.ID)
 		root = append(root, refs...)
+
+		if importTarget.Config != nil && importTarget.Config.ForEach != nil {
+			refs, _ = lang.ReferencesInExpr(addrs.ParseRef, importTarget.Config.ForEach)
+			root = append(root, refs...)
+			for _, expr := range importTarget.Config.ToKeyExprs() {
+				refs, _ = lang.ReferencesInExpr(addrs.ParseRef, expr)
+				root = append(root, refs...)
+			}
+		}
 	}
 
 	return root
@@ -244,6 +285,19 @@ func (n *NodeAbstractResource) SetProvider(p addrs.AbsProviderConfig) {
 	n.ResolvedProvider = p
 }
 
+// graphNodeProviderInstanceConsumer
+func (n *NodeAbstractResource) providerKeyExpr() hcl.Expression {
+	if n.Config == nil || n.Config.ProviderConfigRef == nil {
+		return nil
+	}
+	return n.Config.ProviderConfigRef.KeyExpression
+}
+
+// graphNodeProviderInstanceConsumer
+func (n *NodeAbstractResource) setProviderKeyExpr(keyExpr *providerKeyExpr) {
+	n.ResolvedProviderKeyExpr = keyExpr
+}
+
 // GraphNodeProviderConsumer
 func (n *NodeAbstractResource) ProvidedBy() (addrs.ProviderConfig, bool) {
 	// Once the provider is fully resolved, we can return the known value.
@@ -346,6 +400,11 @@ func (n *NodeAbstractResource) SetTargets(targets []addrs.Targetable) {
 	n.Targets = targets
 }
 
+// GraphNodeExcludable
+func (n *NodeAbstractResource) SetExcludes(excludes []addrs.Targetable) {
+	n.Excludes = excludes
+}
+
 // graphNodeAttachDataResourceDependsOn
 func (n *NodeAbstractResource) AttachDataResourceDependsOn(deps []addrs.ConfigResource, force bool) {
 	n.dependsOn = deps
@@ -398,21 +457,32 @@ func (n *NodeAbstractResource) writeResourceState(ctx EvalContext, addr addrs.Ab
 
 	switch {
 	case n.Config != nil && n.Config.Count != nil:
-		count, countDiags := evaluateCountExpression(n.Config.Count, ctx)
+		count, deferred, countDiags := evaluateDeferrableCountExpression(n.Config.Count, ctx)
 		diags = diags.Append(countDiags)
 		if countDiags.HasErrors() {
 			return diags
 		}
+		if deferred {
+			// We register no instances, so that nothing else tries to plan
+			// them, and leave the existing ones in the state untouched.
+			log.Printf("[TRACE] writeResourceState: deferring %s because its count is unknown", addr)
+			ctx.Deferrals().deferResource(addr, plans.DeferredReasonInstanceCountUnknown)
+		}
 
 		state.SetResourceProvider(addr, n.ResolvedProvider)
 		expander.SetResourceCount(addr.Module, n.Addr.Resource, count)
 
 	case n.Config != nil && n.Config.ForEach != nil:
-		forEach, forEachDiags := evaluateForEachExpression(n.Config.ForEach, ctx)
+		forEach, deferred, forEachDiags := evaluateDeferrableForEachExpression(n.Config.ForEach, ctx)
 		diags = diags.Append(forEachDiags)
 		if forEachDiags.HasErrors() {
 			return diags
 		}
+		if deferred {
+			// As for count above, the resource has no instances for now.
+			log.Printf("[TRACE] writeResourceState: deferring %s because its for_each is unknown", addr)
+			ctx.Deferrals().deferResource(addr, plans.DeferredReasonInstanceCountUnknown)
+		}
 
 		// This method takes care of all of the business logic of updating this
 		// while ensuring that any existing instances are preserved, etc.
@@ -427,11 +497,23 @@ func (n *NodeAbstractResource) writeResourceState(ctx EvalContext, addr addrs.Ab
 	return diags
 }
 
+// getProvider returns the provider and schema for the instance of the given
+// provider configuration with the given key. If the resource has been overridden by the testing framework
+// the provider is wrapped so that the real provider never acts on the
+// resource's objects.
+func (n *NodeAbstractResource) getProvider(ctx EvalContext, addr addrs.AbsProviderConfig, key addrs.InstanceKey) (providers.Interface, providers.ProviderSchema, error) {
+	provider, schema, err := getProvider(ctx, addr, key)
+	if err != nil || n.Config == nil || !n.Config.IsOverridden {
+		return provider, schema, err
+	}
+	return newProviderForOverriddenResource(provider, n.Config.OverrideValues), schema, nil
+}
+
 // readResourceInstanceState reads the current object for a specific instance in
 // the state.
 func (n *NodeAbstractResource) readResourceInstanceState(ctx EvalContext, addr addrs.AbsResourceInstance) (*states.ResourceInstanceObject, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
-	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	provider, providerSchema, err := n.getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	if err != nil {
 		diags = diags.Append(err)
 		return nil, diags
@@ -451,7 +533,14 @@ func (n *NodeAbstractResource) readResourceInstanceState(ctx EvalContext, addr a
 		// Shouldn't happen since we should've failed long ago if no schema is present
 		return nil, diags.Append(fmt.Errorf("no schema available for %s while reading state; this is a bug in OpenTofu and should be reported", addr))
 	}
-	src, upgradeDiags := upgradeResourceState(addr, provider, src, schema, currentVersion)
+	var upgradeDiags tfdiags.Diagnostics
+	if move, moved := ctx.MoveResults().Changes.GetOk(addr); moved && move.ChangesType() {
+		// The object moved here from a resource of another type, so the
+		// provider must convert it rather than just upgrading it.
+		src, upgradeDiags = moveResourceState(move, provider, src, schema, currentVersion)
+	} else {
+		src, upgradeDiags = upgradeResourceState(addr, provider, src, schema, currentVersion)
+	}
 	if n.Config != nil {
 		upgradeDiags = upgradeDiags.InConfigBody(n.Config.Config, addr.String())
 	}
@@ -472,7 +561,7 @@ func (n *NodeAbstractResource) readResourceInstanceState(ctx EvalContext, addr a
 // instance in the state.
 func (n *NodeAbstractResource) readResourceInstanceStateDeposed(ctx EvalContext, addr addrs.AbsResourceInstance, key states.DeposedKey) (*states.ResourceInstanceObject, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
-	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	provider, providerSchema, err := n.getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	if err != nil {
 		diags = diags.Append(err)
 		return nil, diags
@@ -498,7 +587,14 @@ func (n *NodeAbstractResource) readResourceInstanceStateDeposed(ctx EvalContext,
 
 	}
 
-	src, upgradeDiags := upgradeResourceState(addr, provider, src, schema, currentVersion)
+	// Moves apply to whole resource instances, so a deposed object that
+	// moved from a resource of another type must be converted too.
+	var upgradeDiags tfdiags.Diagnostics
+	if move, moved := ctx.MoveResults().Changes.GetOk(addr); moved && move.ChangesType() {
+		src, upgradeDiags = moveResourceState(move, provider, src, schema, currentVersion)
+	} else {
+		src, upgradeDiags = upgradeResourceState(addr, provider, src, schema, currentVersion)
+	}
 	if n.Config != nil {
 		upgradeDiags = upgradeDiags.InConfigBody(n.Config.Config, addr.String())
 	}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/checks"
@@ -43,6 +44,10 @@ type NodeAbstractResourceInstance struct {
 	// During import we may generate configuration for a resource, which needs
 	// to be stored in the final change.
 	generatedConfigHCL string
+
+	// traceAction is the action of the most recent change this node read
+	// or wrote, reported in the trace span for the node.
+	traceAction string
 }
 
 // NewNodeAbstractResourceInstance creates an abstract resource instance graph
@@ -68,6 +73,29 @@ func (n *NodeAbstractResourceInstance) Path() addrs.ModuleInstance {
 	return n.Addr.Module
 }
 
+// getProvider is like NodeAbstractResource.getProvider, but if the provider
+// is mocked or overridden by the testing framework it also tells it which
+// instance it is acting on, so that each instance gets different values.
+func (n *NodeAbstractResourceInstance) getProvider(ctx EvalContext, addr addrs.AbsProviderConfig, key addrs.InstanceKey) (providers.Interface, providers.ProviderSchema, error) {
+	provider, schema, err := n.NodeAbstractResource.getProvider(ctx, addr, key)
+	if pft, ok := provider.(*providerForTest); ok {
+		provider = pft.withResourceInstance(n.Addr)
+	}
+	return provider, schema, err
+}
+
+// dag.TracedVertex
+func (n *NodeAbstractResourceInstance) TraceAttributes() []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		traceAttrResourceAddress.String(n.ResourceInstanceAddr().String()),
+		traceAttrModulePath.String(n.Addr.Module.String()),
+	}
+	if n.traceAction != "" {
+		attrs = append(attrs, traceAttrResourceAction.String(n.traceAction))
+	}
+	return attrs
+}
+
 // GraphNodeReferenceable
 func (n *NodeAbstractResourceInstance) ReferenceableAddrs() []addrs.Referenceable {
 	addr := n.ResourceInstanceAddr()
@@ -160,6 +188,7 @@ func (n *NodeAbstractResourceInstance) readDiff(ctx EvalContext, providerSchema
 	}
 
 	log.Printf("[TRACE] readDiff: Read %s change from plan for %s", change.Action, n.Addr)
+	n.traceAction = change.Action.String()
 
 	return change, nil
 }
@@ -270,7 +299,7 @@ func (n *NodeAbstractResourceInstance) writeResourceInstanceStateDeposed(ctx Eva
 // objects you are intending to write.
 func (n *NodeAbstractResourceInstance) writeResourceInstanceStateImpl(ctx EvalContext, deposedKey states.DeposedKey, obj *states.ResourceInstanceObject, targetState phaseState) error {
 	absAddr := n.Addr
-	_, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	_, providerSchema, err := getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	if err != nil {
 		return err
 	}
@@ -341,11 +370,62 @@ func (n *NodeAbstractResourceInstance) writeResourceInstanceStateImpl(ctx EvalCo
 	if err != nil {
 		return fmt.Errorf("failed to encode %s in state: %w", absAddr, err)
 	}
+	src.ProviderKey = n.ResolvedProviderKey
 
 	write(src)
 	return nil
 }
 
+// resolveProviderKey sets ResolvedProviderKey to the instance key of the
+// provider configuration that the resource instance uses according to the
+// configuration. This must be called before using the provider to plan or
+// apply changes to the resource instance's current object.
+func (n *NodeAbstractResourceInstance) resolveProviderKey(ctx EvalContext) tfdiags.Diagnostics {
+	key, diags := resolveProviderKey(ctx, n.Addr, n.ResolvedProvider, n.ResolvedProviderKeyExpr)
+	n.ResolvedProviderKey = key
+	return diags
+}
+
+// resolveStateProviderKey sets ResolvedProviderKey to the instance key of the
+// provider configuration that manages the given object of the resource
+// instance according to the state. This must be called instead of
+// resolveProviderKey before using the provider to refresh or destroy an
+// object that is no longer declared in the configuration, because only the
+// provider instance that created the object can safely manage it.
+func (n *NodeAbstractResourceInstance) resolveStateProviderKey(ctx EvalContext, deposedKey states.DeposedKey) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	var gen states.Generation = states.CurrentGen
+	if deposedKey != states.NotDeposed {
+		gen = deposedKey
+	}
+
+	key := addrs.NoKey
+	if obj := ctx.State().ResourceInstanceObject(n.Addr, gen); obj != nil {
+		key = obj.ProviderKey
+	}
+	if key == addrs.NoKey && n.ResolvedProviderKeyExpr != nil && ctx.InstanceExpander().AllInstances().HasResourceInstance(n.Addr) {
+		// Objects created before the provider configuration had for_each
+		// don't record a provider instance key, so if the resource instance
+		// is still declared we fall back to the key its configuration
+		// selects.
+		return n.resolveProviderKey(ctx)
+	}
+	n.ResolvedProviderKey = key
+
+	if ctx.Provider(n.ResolvedProvider, key) == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Provider instance not present",
+			fmt.Sprintf(
+				"To work with %s its original provider instance at %s is required, but it has been removed. This occurs when an instance key is removed from the for_each argument of a provider configuration while objects created by that provider instance still exist in the state. Re-add the instance key to destroy %s, after which you can remove it again.",
+				n.Addr, providerInstanceString(n.ResolvedProvider, key), n.Addr,
+			),
+		))
+	}
+	return diags
+}
+
 // planDestroy returns a plain destroy diff.
 func (n *NodeAbstractResourceInstance) planDestroy(ctx EvalContext, currentState *states.ResourceInstanceObject, deposedKey states.DeposedKey) (*plans.ResourceInstanceChange, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
@@ -388,7 +468,7 @@ func (n *NodeAbstractResourceInstance) planDestroy(ctx EvalContext, currentState
 	// operation.
 	nullVal := cty.NullVal(unmarkedPriorVal.Type())
 
-	provider, _, err := getProvider(ctx, n.ResolvedProvider)
+	provider, _, err := n.getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	if err != nil {
 		return plan, diags.Append(err)
 	}
@@ -451,6 +531,22 @@ func (n *NodeAbstractResourceInstance) planDestroy(ctx EvalContext, currentState
 	return plan, diags
 }
 
+// planForget returns a change that removes the given object from the state
+// without destroying it. Unlike planDestroy this doesn't involve the
+// provider at all, because the remote object is left untouched.
+func (n *NodeAbstractResourceInstance) planForget(ctx EvalContext, currentState *states.ResourceInstanceObject) *plans.ResourceInstanceChange {
+	return &plans.ResourceInstanceChange{
+		Addr:        n.Addr,
+		PrevRunAddr: n.prevRunAddr(ctx),
+		Change: plans.Change{
+			Action: plans.Forget,
+			Before: currentState.Value,
+			After:  cty.NullVal(currentState.Value.Type()),
+		},
+		ProviderAddr: n.ResolvedProvider,
+	}
+}
+
 // writeChange saves a planned change for an instance object into the set of
 // global planned changes.
 func (n *NodeAbstractResourceInstance) writeChange(ctx EvalContext, change *plans.ResourceInstanceChange, deposedKey states.DeposedKey) error {
@@ -467,7 +563,7 @@ func (n *NodeAbstractResourceInstance) writeChange(ctx EvalContext, change *plan
 		return nil
 	}
 
-	_, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	_, providerSchema, err := getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	if err != nil {
 		return err
 	}
@@ -500,6 +596,7 @@ func (n *NodeAbstractResourceInstance) writeChange(ctx EvalContext, change *plan
 	}
 
 	changes.AppendResourceInstanceChange(csrc)
+	n.traceAction = change.Action.String()
 	if deposedKey == states.NotDeposed {
 		log.Printf("[TRACE] writeChange: recorded %s change for %s", change.Action, n.Addr)
 	} else {
@@ -518,7 +615,7 @@ func (n *NodeAbstractResourceInstance) refresh(ctx EvalContext, deposedKey state
 	} else {
 		log.Printf("[TRACE] NodeAbstractResourceInstance.refresh for %s (deposed object %s)", absAddr, deposedKey)
 	}
-	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	provider, providerSchema, err := n.getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	if err != nil {
 		return state, diags.Append(err)
 	}
@@ -653,7 +750,7 @@ func (n *NodeAbstractResourceInstance) plan(
 	var keyData instances.RepetitionData
 
 	resource := n.Addr.Resource.Resource
-	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	provider, providerSchema, err := n.getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	if err != nil {
 		return nil, nil, keyData, diags.Append(err)
 	}
@@ -719,6 +816,10 @@ func (n *NodeAbstractResourceInstance) plan(
 	if configDiags.HasErrors() {
 		return nil, nil, keyData, diags
 	}
+	diags = diags.Append(validateNoEphemeralValues(n.Addr, origConfigVal, config.Config))
+	if diags.HasErrors() {
+		return nil, nil, keyData, diags
+	}
 
 	metaConfigVal, metaDiags := n.providerMetas(ctx)
 	diags = diags.Append(metaDiags)
@@ -1402,7 +1503,7 @@ func (n *NodeAbstractResourceInstance) readDataSource(ctx EvalContext, configVal
 
 	config := *n.Config
 
-	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	provider, providerSchema, err := n.getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	diags = diags.Append(err)
 	if diags.HasErrors() {
 		return newVal, diags
@@ -1522,7 +1623,7 @@ func (n *NodeAbstractResourceInstance) providerMetas(ctx EvalContext) (cty.Value
 	var diags tfdiags.Diagnostics
 	metaConfigVal := cty.NullVal(cty.DynamicPseudoType)
 
-	_, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	_, providerSchema, err := getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	if err != nil {
 		return metaConfigVal, diags.Append(err)
 	}
@@ -1560,7 +1661,7 @@ func (n *NodeAbstractResourceInstance) planDataSource(ctx EvalContext, checkRule
 	var keyData instances.RepetitionData
 	var configVal cty.Value
 
-	_, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	_, providerSchema, err := getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	if err != nil {
 		return nil, nil, keyData, diags.Append(err)
 	}
@@ -1596,6 +1697,10 @@ func (n *NodeAbstractResourceInstance) planDataSource(ctx EvalContext, checkRule
 	if configDiags.HasErrors() {
 		return nil, nil, keyData, diags
 	}
+	diags = diags.Append(validateNoEphemeralValues(n.Addr, configVal, config.Config))
+	if diags.HasErrors() {
+		return nil, nil, keyData, diags
+	}
 
 	check, nested := n.nestedInCheckBlock()
 	if nested {
@@ -1834,7 +1939,7 @@ func (n *NodeAbstractResourceInstance) applyDataSource(ctx EvalContext, planned
 	var diags tfdiags.Diagnostics
 	var keyData instances.RepetitionData
 
-	_, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	_, providerSchema, err := getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	if err != nil {
 		return nil, keyData, diags.Append(err)
 	}
@@ -2212,7 +2317,7 @@ func (n *NodeAbstractResourceInstance) apply(
 		return state, diags
 	}
 
-	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	provider, providerSchema, err := n.getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	if err != nil {
 		return nil, diags.Append(err)
 	}
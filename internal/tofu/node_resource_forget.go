@@ -0,0 +1,46 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"log"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// NodeForgetResourceInstance represents a resource instance that is to be
+// removed from the state without destroying the corresponding remote object,
+// as requested by a "removed" block in the configuration.
+type NodeForgetResourceInstance struct {
+	*NodeAbstractResourceInstance
+}
+
+var (
+	_ GraphNodeModuleInstance   = (*NodeForgetResourceInstance)(nil)
+	_ GraphNodeConfigResource   = (*NodeForgetResourceInstance)(nil)
+	_ GraphNodeResourceInstance = (*NodeForgetResourceInstance)(nil)
+	_ GraphNodeExecutable       = (*NodeForgetResourceInstance)(nil)
+	_ GraphNodeProviderConsumer = (*NodeForgetResourceInstance)(nil)
+)
+
+func (n *NodeForgetResourceInstance) Name() string {
+	return n.ResourceInstanceAddr().String() + " (forget)"
+}
+
+func (n *NodeForgetResourceInstance) ProvidedBy() (addr addrs.ProviderConfig, exact bool) {
+	// Forgetting an object never involves the provider, so we indicate that
+	// this node does not require a configured provider.
+	return nil, true
+}
+
+// GraphNodeExecutable
+func (n *NodeForgetResourceInstance) Execute(ctx EvalContext, op walkOperation) tfdiags.Diagnostics {
+	addr := n.ResourceInstanceAddr()
+
+	log.Printf("[TRACE] NodeForgetResourceInstance: removing %s from the state", addr)
+	ctx.State().SetResourceInstanceCurrent(addr, nil, n.ResolvedProvider)
+
+	return nil
+}
@@ -19,6 +19,11 @@ type graphNodeImportState struct {
 	ProviderAddr     addrs.AbsProviderConfig   // Provider address given by the user, or implied by the resource type
 	ResolvedProvider addrs.AbsProviderConfig   // provider node address after resolution
 
+	// ResolvedProviderKeyExpr selects the instance of the resolved provider
+	// configuration if it has for_each.
+	ResolvedProviderKeyExpr *providerKeyExpr
+	resolvedProviderKey     addrs.InstanceKey
+
 	states []providers.ImportedResource
 }
 
@@ -73,7 +78,14 @@ func (n *graphNodeImportState) Execute(ctx EvalContext, op walkOperation) (diags
 	// Reset our states
 	n.states = nil
 
-	provider, _, err := getProvider(ctx, n.ResolvedProvider)
+	key, keyDiags := resolveProviderKey(ctx, n.Addr, n.ResolvedProvider, n.ResolvedProviderKeyExpr)
+	diags = diags.Append(keyDiags)
+	if diags.HasErrors() {
+		return diags
+	}
+	n.resolvedProviderKey = key
+
+	provider, _, err := getProvider(ctx, n.ResolvedProvider, n.resolvedProviderKey)
 	diags = diags.Append(err)
 	if diags.HasErrors() {
 		return diags
@@ -173,9 +185,10 @@ func (n *graphNodeImportState) DynamicExpand(ctx EvalContext) (*Graph, error) {
 	// safe.
 	for i, state := range n.states {
 		g.Add(&graphNodeImportStateSub{
-			TargetAddr:       addrs[i],
-			State:            state,
-			ResolvedProvider: n.ResolvedProvider,
+			TargetAddr:          addrs[i],
+			State:               state,
+			ResolvedProvider:    n.ResolvedProvider,
+			ResolvedProviderKey: n.resolvedProviderKey,
 		})
 	}
 
@@ -189,9 +202,10 @@ func (n *graphNodeImportState) DynamicExpand(ctx EvalContext) (*Graph, error) {
 // and is part of the subgraph. This node is responsible for refreshing
 // and adding a resource to the state once it is imported.
 type graphNodeImportStateSub struct {
-	TargetAddr       addrs.AbsResourceInstance
-	State            providers.ImportedResource
-	ResolvedProvider addrs.AbsProviderConfig
+	TargetAddr          addrs.AbsResourceInstance
+	State               providers.ImportedResource
+	ResolvedProvider    addrs.AbsProviderConfig
+	ResolvedProviderKey addrs.InstanceKey
 }
 
 var (
@@ -221,7 +235,8 @@ func (n *graphNodeImportStateSub) Execute(ctx EvalContext, op walkOperation) (di
 	riNode := &NodeAbstractResourceInstance{
 		Addr: n.TargetAddr,
 		NodeAbstractResource: NodeAbstractResource{
-			ResolvedProvider: n.ResolvedProvider,
+			ResolvedProvider:    n.ResolvedProvider,
+			ResolvedProviderKey: n.ResolvedProviderKey,
 		},
 	}
 	state, refreshDiags := riNode.refresh(ctx, states.NotDeposed, state)
@@ -5,10 +5,16 @@ package tofu
 
 import (
 	"fmt"
+	"log"
 	"strings"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -114,8 +120,9 @@ func (n *nodeExpandPlannableResource) DynamicExpand(ctx EvalContext) (*Graph, er
 			}
 		}
 		// The module instance of the resource in the state doesn't exist
-		// in the current config, so this whole resource is orphaned.
-		if !found {
+		// in the current config, so this whole resource is orphaned, unless
+		// the module call it belongs to was deferred.
+		if !found && !ctx.Deferrals().moduleInstanceDeferred(res.Addr.Module) {
 			orphans = append(orphans, res)
 		}
 	}
@@ -130,6 +137,7 @@ func (n *nodeExpandPlannableResource) DynamicExpand(ctx EvalContext) (*Graph, er
 		// Add the config and state since we don't do that via transforms
 		a.Config = n.Config
 		a.ResolvedProvider = n.ResolvedProvider
+		a.ResolvedProviderKeyExpr = n.ResolvedProviderKeyExpr
 		a.Schema = n.Schema
 		a.ProvisionerSchemas = n.ProvisionerSchemas
 		a.ProviderMetas = n.ProviderMetas
@@ -160,11 +168,57 @@ func (n *nodeExpandPlannableResource) DynamicExpand(ctx EvalContext) (*Graph, er
 	// so that we can inform the checks subsystem of which instances it should
 	// be expecting check results for, below.
 	var diags tfdiags.Diagnostics
+
+	// Import blocks using for_each can only be expanded into their
+	// individual targets now that we're able to evaluate expressions.
+	importTargets, importDiags := expandImportTargets(ctx, n.importTargets)
+	diags = diags.Append(importDiags)
+	if diags.HasErrors() {
+		return nil, diags.ErrWithWarnings()
+	}
+
 	instAddrs := addrs.MakeSet[addrs.Checkable]()
+	anyDeferred := false
 	for _, module := range moduleInstances {
 		resAddr := n.Addr.Resource.Absolute(module)
-		err := n.expandResourceInstances(ctx, resAddr, &g, instAddrs)
+		err := n.expandResourceInstances(ctx, resAddr, importTargets, &g, instAddrs)
 		diags = diags.Append(err)
+		anyDeferred = anyDeferred || ctx.Deferrals().resourceDeferred(resAddr)
+	}
+	if diags.HasErrors() {
+		return nil, diags.ErrWithWarnings()
+	}
+
+	// Targets from import blocks using for_each weren't known in time for
+	// Context.postPlanValidateImports, so we check them here instead.
+	for _, target := range importTargets {
+		if target.Config == nil || target.Config.ForEach == nil {
+			continue
+		}
+		if target.Addr.Resource.Key == addrs.NoKey || instAddrs.Has(target.Addr) {
+			continue
+		}
+		if n.Config == nil && n.generateConfigPath != "" {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Config generation for count and for_each resources not supported",
+				Detail: fmt.Sprintf(
+					"Your configuration contains an import block with a \"to\" address of %s. This resource instance does not exist in configuration.\n\nIf you intended to target a resource that exists in configuration, please double-check the address. Otherwise, please remove this import block or re-run the plan without the -generate-config-out flag to ignore the import block.",
+					target.Addr,
+				),
+				Subject: target.Config.DeclRange.Ptr(),
+			})
+		} else {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Cannot import to non-existent resource address",
+				Detail: fmt.Sprintf(
+					"Importing to resource address %s is not possible, because that address does not exist in configuration. Please ensure that the resource key is correct, or remove this import block.",
+					target.Addr,
+				),
+				Subject: target.Config.DeclRange.Ptr(),
+			})
+		}
 	}
 	if diags.HasErrors() {
 		return nil, diags.ErrWithWarnings()
@@ -174,8 +228,9 @@ func (n *nodeExpandPlannableResource) DynamicExpand(ctx EvalContext) (*Graph, er
 	// (i.e. it has preconditions or postconditions) then the check state
 	// wants to know the addresses of the checkable objects so that it can
 	// treat them as unknown status if we encounter an error before actually
-	// visiting the checks.
-	if checkState := ctx.Checks(); checkState.ConfigHasChecks(n.NodeAbstractResource.Addr) {
+	// visiting the checks. We can't know all of those objects if some of
+	// them were deferred, in which case we leave them unreported.
+	if checkState := ctx.Checks(); checkState.ConfigHasChecks(n.NodeAbstractResource.Addr) && !anyDeferred {
 		checkState.ReportCheckableObjects(n.NodeAbstractResource.Addr, instAddrs)
 	}
 
@@ -196,7 +251,7 @@ func (n *nodeExpandPlannableResource) DynamicExpand(ctx EvalContext) (*Graph, er
 // within, the caller must register the final superset instAddrs with the
 // checks subsystem so that it knows the fully expanded set of checkable
 // object instances for this resource instance.
-func (n *nodeExpandPlannableResource) expandResourceInstances(globalCtx EvalContext, resAddr addrs.AbsResource, g *Graph, instAddrs addrs.Set[addrs.Checkable]) error {
+func (n *nodeExpandPlannableResource) expandResourceInstances(globalCtx EvalContext, resAddr addrs.AbsResource, importTargets []*ImportTarget, g *Graph, instAddrs addrs.Set[addrs.Checkable]) error {
 	var diags tfdiags.Diagnostics
 
 	// The rest of our work here needs to know which module instance it's
@@ -205,11 +260,33 @@ func (n *nodeExpandPlannableResource) expandResourceInstances(globalCtx EvalCont
 
 	// writeResourceState is responsible for informing the expander of what
 	// repetition mode this resource has, which allows expander.ExpandResource
-	// to work below.
-	moreDiags := n.writeResourceState(moduleCtx, resAddr)
-	diags = diags.Append(moreDiags)
-	if moreDiags.HasErrors() {
-		return diags.ErrWithWarnings()
+	// to work below. A resource whose configuration is being generated for
+	// import blocks using for_each has no configuration to expand, so it
+	// takes its instances from the import targets instead.
+	if forEach := generatedImportForEach(resAddr, n.Config, n.generateConfigPath, importTargets); forEach != nil {
+		moduleCtx.State().SetResourceProvider(resAddr, n.ResolvedProvider)
+		moduleCtx.InstanceExpander().SetResourceForEach(resAddr.Module, n.Addr.Resource, forEach)
+	} else {
+		moreDiags := n.writeResourceState(moduleCtx, resAddr)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			return diags.ErrWithWarnings()
+		}
+	}
+
+	// A resource that depends on a deferred object must be deferred too,
+	// because its configuration may refer to values we don't know yet. We
+	// don't add any instance nodes for a deferred resource, so its existing
+	// instances stay in the state unchanged until a later round.
+	deferrals := moduleCtx.Deferrals()
+	if deferrals.allowed() {
+		if !deferrals.resourceDeferred(resAddr) && deferrals.dependsOnDeferred(n.dependencies) {
+			log.Printf("[TRACE] expandResourceInstances: deferring %s because it depends on deferred objects", resAddr)
+			deferrals.deferResource(resAddr, plans.DeferredReasonDeferredPrereq)
+		}
+		if deferrals.resourceDeferred(resAddr) {
+			return diags.ErrWithWarnings()
+		}
 	}
 
 	// Before we expand our resource into potentially many resource instances,
@@ -291,7 +368,7 @@ func (n *nodeExpandPlannableResource) expandResourceInstances(globalCtx EvalCont
 	// construct a subgraph just for this individual modules's instances and
 	// then we'll steal all of its nodes and edges to incorporate into our
 	// main graph which contains all of the resource instances together.
-	instG, err := n.resourceInstanceSubgraph(moduleCtx, resAddr, instanceAddrs)
+	instG, err := n.resourceInstanceSubgraph(moduleCtx, resAddr, instanceAddrs, importTargets)
 	if err != nil {
 		diags = diags.Append(err)
 		return diags.ErrWithWarnings()
@@ -301,7 +378,34 @@ func (n *nodeExpandPlannableResource) expandResourceInstances(globalCtx EvalCont
 	return diags.ErrWithWarnings()
 }
 
-func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx EvalContext, addr addrs.AbsResource, instanceAddrs []addrs.AbsResourceInstance) (*Graph, error) {
+// generatedImportForEach returns the for_each mapping for a resource that
+// has no configuration, whose configuration will be generated for the
+// instances targeted by import blocks using for_each. It returns nil if the
+// resource isn't one of those, or if any of its import targets has an
+// instance key that isn't a string, which is reported later instead.
+func generatedImportForEach(addr addrs.AbsResource, config *configs.Resource, generateConfigPath string, importTargets []*ImportTarget) map[string]cty.Value {
+	if config != nil || generateConfigPath == "" {
+		return nil
+	}
+
+	var ret map[string]cty.Value
+	for _, target := range importTargets {
+		if !target.Addr.ContainingResource().Equal(addr) {
+			continue
+		}
+		key, ok := target.Addr.Resource.Key.(addrs.StringKey)
+		if target.Config == nil || target.Config.ForEach == nil || !ok {
+			return nil
+		}
+		if ret == nil {
+			ret = make(map[string]cty.Value)
+		}
+		ret[string(key)] = cty.StringVal(string(key))
+	}
+	return ret
+}
+
+func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx EvalContext, addr addrs.AbsResource, instanceAddrs []addrs.AbsResourceInstance, importTargets []*ImportTarget) (*Graph, error) {
 	var diags tfdiags.Diagnostics
 
 	// Our graph transformers require access to the full state, so we'll
@@ -316,7 +420,7 @@ func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx EvalContext,
 		// If we're in legacy import mode (the import CLI command), we only need
 		// to return the import node, not a plannable resource node.
 		if n.legacyImportMode {
-			for _, importTarget := range n.importTargets {
+			for _, importTarget := range importTargets {
 				if importTarget.Addr.Equal(a.Addr) {
 
 					// The import ID was supplied as a string on the command
@@ -330,9 +434,10 @@ func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx EvalContext,
 					}
 
 					return &graphNodeImportState{
-						Addr:             importTarget.Addr,
-						ID:               importId,
-						ResolvedProvider: n.ResolvedProvider,
+						Addr:                    importTarget.Addr,
+						ID:                      importId,
+						ResolvedProvider:        n.ResolvedProvider,
+						ResolvedProviderKeyExpr: n.ResolvedProviderKeyExpr,
 					}
 				}
 			}
@@ -341,6 +446,7 @@ func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx EvalContext,
 		// Add the config and state since we don't do that via transforms
 		a.Config = n.Config
 		a.ResolvedProvider = n.ResolvedProvider
+		a.ResolvedProviderKeyExpr = n.ResolvedProviderKeyExpr
 		a.Schema = n.Schema
 		a.ProvisionerSchemas = n.ProvisionerSchemas
 		a.ProviderMetas = n.ProviderMetas
@@ -361,7 +467,7 @@ func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx EvalContext,
 			forceReplace:             n.forceReplace,
 		}
 
-		for _, importTarget := range n.importTargets {
+		for _, importTarget := range importTargets {
 			if importTarget.Addr.Equal(a.Addr) {
 				// If we get here, we're definitely not in legacy import mode,
 				// so go ahead and plan the resource changes including import.
@@ -381,6 +487,7 @@ func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx EvalContext,
 		// Add the config and state since we don't do that via transforms
 		a.Config = n.Config
 		a.ResolvedProvider = n.ResolvedProvider
+		a.ResolvedProviderKeyExpr = n.ResolvedProviderKeyExpr
 		a.Schema = n.Schema
 		a.ProvisionerSchemas = n.ProvisionerSchemas
 		a.ProviderMetas = n.ProviderMetas
@@ -414,7 +521,7 @@ func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx EvalContext,
 		&AttachStateTransformer{State: state},
 
 		// Targeting
-		&TargetsTransformer{Targets: n.Targets},
+		&TargetsTransformer{Targets: n.Targets, Excludes: n.Excludes},
 
 		// Connect references so ordering is correct
 		&ReferenceTransformer{},
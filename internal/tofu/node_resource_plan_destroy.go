@@ -64,6 +64,11 @@ func (n *NodePlanDestroyableResourceInstance) managedResourceExecute(ctx EvalCon
 	var change *plans.ResourceInstanceChange
 	var state *states.ResourceInstanceObject
 
+	diags = diags.Append(n.resolveStateProviderKey(ctx, states.NotDeposed))
+	if diags.HasErrors() {
+		return diags
+	}
+
 	state, err := n.readResourceInstanceState(ctx, addr)
 	diags = diags.Append(err)
 	if diags.HasErrors() {
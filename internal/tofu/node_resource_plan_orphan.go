@@ -9,6 +9,7 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/refactoring"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -24,6 +25,11 @@ type NodePlannableResourceInstanceOrphan struct {
 	// skipPlanChanges indicates we should skip trying to plan change actions
 	// for any instances.
 	skipPlanChanges bool
+
+	// removeStatements are the "removed" blocks from the configuration, used
+	// to decide whether this instance should be destroyed or only removed
+	// from the state.
+	removeStatements []refactoring.RemoveStatement
 }
 
 var (
@@ -82,6 +88,14 @@ func (n *NodePlannableResourceInstanceOrphan) dataResourceExecute(ctx EvalContex
 func (n *NodePlannableResourceInstanceOrphan) managedResourceExecute(ctx EvalContext) (diags tfdiags.Diagnostics) {
 	addr := n.ResourceInstanceAddr()
 
+	// An orphaned object must be refreshed and destroyed by the provider
+	// instance that created it, even if the configuration now selects a
+	// different one or the resource instance is no longer declared.
+	diags = diags.Append(n.resolveStateProviderKey(ctx, states.NotDeposed))
+	if diags.HasErrors() {
+		return diags
+	}
+
 	oldState, readDiags := n.readResourceInstanceState(ctx, addr)
 	diags = diags.Append(readDiags)
 	if diags.HasErrors() {
@@ -132,6 +146,17 @@ func (n *NodePlannableResourceInstanceOrphan) managedResourceExecute(ctx EvalCon
 		return diags.Append(n.writeResourceInstanceState(ctx, oldState, workingState))
 	}
 
+	if stmt := refactoring.FindRemoveStatement(n.removeStatements, addr); stmt != nil && !stmt.Destroy {
+		// The configuration declares that this object should only be
+		// removed from the state, leaving the remote object in place.
+		log.Printf("[TRACE] NodePlannableResourceInstanceOrphan: planning to forget %s, as declared at %s", addr, stmt.DeclRange.StartString())
+		diags = diags.Append(n.writeChange(ctx, n.planForget(ctx, oldState), ""))
+		if diags.HasErrors() {
+			return diags
+		}
+		return diags.Append(n.writeResourceInstanceState(ctx, nil, workingState))
+	}
+
 	var change *plans.ResourceInstanceChange
 	change, destroyPlanDiags := n.planDestroy(ctx, oldState, "")
 	diags = diags.Append(destroyPlanDiags)
@@ -151,6 +151,17 @@ func TestNodeRootVariableExecute(t *testing.T) {
 			// as part of preparing the "final value".
 			t.Errorf("wrong value for ctx.SetRootModuleArgument\ngot:  %#v\nwant: %#v", got, want)
 		}
+
+		// The custom validation rules are checked by a separate node once
+		// the final value of the variable is available.
+		validation := &nodeVariableValidationInstance{
+			Addr:   n.Addr.Absolute(addrs.RootModuleInstance),
+			Config: n.Config,
+		}
+		diags = validation.Execute(ctx, walkApply)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected error: %s", diags.Err())
+		}
 		if status := ctx.Checks().ObjectCheckStatus(n.Addr.Absolute(addrs.RootModuleInstance)); status != checks.StatusPass {
 			t.Errorf("expected checks to pass but go %s instead", status)
 		}
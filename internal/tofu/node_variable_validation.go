@@ -0,0 +1,144 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// nodeVariableValidation checks the custom validation rules of an input
+// variable, for each instance of the module where the variable is declared.
+//
+// The rules are checked by this separate node rather than by the node for
+// the variable itself, because variable nodes evaluate in the calling module
+// while validation rules can refer to other input variables, local values
+// and data resources of the module that declares the variable. The
+// references in the rules make this node depend on all of those objects,
+// and variableValidationTransformer makes everything else that refers to the
+// variable depend on this node.
+type nodeVariableValidation struct {
+	Addr   addrs.InputVariable
+	Module addrs.Module
+	Config *configs.Variable
+
+	// Expr is the value expression given in the module call, which is used
+	// to report failed validations. It's always nil for root module
+	// variables.
+	Expr hcl.Expression
+}
+
+var (
+	_ GraphNodeModulePath        = (*nodeVariableValidation)(nil)
+	_ GraphNodeReferencer        = (*nodeVariableValidation)(nil)
+	_ GraphNodeDynamicExpandable = (*nodeVariableValidation)(nil)
+)
+
+func (n *nodeVariableValidation) Name() string {
+	return fmt.Sprintf("%s (validation)", n.Addr.InModule(n.Module))
+}
+
+// GraphNodeModulePath
+func (n *nodeVariableValidation) ModulePath() addrs.Module {
+	return n.Module
+}
+
+// validates returns true if the given vertex is the node for the variable
+// whose validation rules this node checks.
+func (n *nodeVariableValidation) validates(v dag.Vertex) bool {
+	switch v := v.(type) {
+	case *NodeRootVariable:
+		return n.Module.IsRoot() && v.Addr == n.Addr
+	case *nodeExpandModuleVariable:
+		return n.Module.Equal(v.Module) && v.Addr == n.Addr
+	default:
+		return false
+	}
+}
+
+// GraphNodeReferencer
+func (n *nodeVariableValidation) References() []*addrs.Reference {
+	// We ignore diagnostics here under the assumption that the validation
+	// rules were already checked during configuration loading.
+	var refs []*addrs.Reference
+	for _, validation := range n.Config.Validations {
+		condRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, validation.Condition)
+		refs = append(refs, condRefs...)
+		msgRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, validation.ErrorMessage)
+		refs = append(refs, msgRefs...)
+	}
+	return refs
+}
+
+// GraphNodeDynamicExpandable
+func (n *nodeVariableValidation) DynamicExpand(ctx EvalContext) (*Graph, error) {
+	var g Graph
+
+	expander := ctx.InstanceExpander()
+	for _, module := range expander.ExpandModule(n.Module) {
+		g.Add(&nodeVariableValidationInstance{
+			Addr:   n.Addr.Absolute(module),
+			Config: n.Config,
+			Expr:   n.Expr,
+		})
+	}
+	addRootNodeToGraph(&g)
+
+	return &g, nil
+}
+
+// nodeVariableValidationInstance checks the custom validation rules of an
+// input variable for a single instance of the module that declares it.
+type nodeVariableValidationInstance struct {
+	Addr   addrs.AbsInputVariableInstance
+	Config *configs.Variable
+	Expr   hcl.Expression
+}
+
+var (
+	_ GraphNodeModuleInstance = (*nodeVariableValidationInstance)(nil)
+	_ GraphNodeExecutable     = (*nodeVariableValidationInstance)(nil)
+	_ dag.GraphNodeDotter     = (*nodeVariableValidationInstance)(nil)
+)
+
+func (n *nodeVariableValidationInstance) Name() string {
+	return fmt.Sprintf("%s (validation)", n.Addr)
+}
+
+// GraphNodeModuleInstance
+func (n *nodeVariableValidationInstance) Path() addrs.ModuleInstance {
+	// Unlike the variable itself, the validation rules are evaluated in the
+	// module where the variable is declared.
+	return n.Addr.Module
+}
+
+// GraphNodeModulePath
+func (n *nodeVariableValidationInstance) ModulePath() addrs.Module {
+	return n.Addr.Module.Module()
+}
+
+// GraphNodeExecutable
+func (n *nodeVariableValidationInstance) Execute(ctx EvalContext, op walkOperation) tfdiags.Diagnostics {
+	log.Printf("[TRACE] nodeVariableValidationInstance: checking %s", n.Addr)
+	return evalVariableValidations(n.Addr, n.Config, n.Expr, ctx)
+}
+
+// dag.GraphNodeDotter impl.
+func (n *nodeVariableValidationInstance) DotNode(name string, opts *dag.DotOpts) *dag.DotNode {
+	return &dag.DotNode{
+		Name: name,
+		Attrs: map[string]string{
+			"label": n.Name(),
+			"shape": "note",
+		},
+	}
+}
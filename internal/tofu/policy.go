@@ -0,0 +1,243 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// EvalPolicies checks the planned changes to resource instances in the given
+// plan against the rules of the given policies, recording any violations in
+// the plan's PolicyViolations field.
+//
+// Each rule is evaluated once for each planned change to a resource instance
+// that the policy applies to, with the symbol "resource" referring to an
+// object describing the change. Changes that are planned as no-ops, and the
+// deletion of deposed objects, are not checked.
+//
+// Violations of mandatory policies are returned as errors and violations of
+// advisory policies as warnings. A rule whose result depends on values that
+// are not known until apply, or whose condition can't be evaluated at all,
+// counts as a violation of a mandatory policy, because it can't be known to
+// pass. The caller should still save a plan whose
+// only errors are policy violations, so that they can be reviewed later, but
+// such a plan must not be applied.
+func (c *Context) EvalPolicies(config *configs.Config, plan *plans.Plan, policies []*configs.Policy) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	plan.PolicyViolations = nil
+	if len(policies) == 0 || plan.Changes == nil {
+		return diags
+	}
+
+	schemas, moreDiags := c.Schemas(config, plan.PriorState)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return diags
+	}
+
+	scope := &lang.Scope{
+		BaseDir:       ".",
+		PureOnly:      true,
+		PlanTimestamp: plan.Timestamp,
+	}
+	funcs := scope.Functions()
+
+	// The changes are checked in order of their addresses, so that the
+	// diagnostics and violations are in a predictable order.
+	changes := make([]*plans.ResourceInstanceChangeSrc, len(plan.Changes.Resources))
+	copy(changes, plan.Changes.Resources)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Addr.Less(changes[j].Addr)
+	})
+
+	for _, rcs := range changes {
+		if rcs.Action == plans.NoOp || rcs.DeposedKey != states.NotDeposed {
+			continue
+		}
+		addr := rcs.Addr
+		resource := addr.Resource.Resource
+
+		schema, _ := schemas.ResourceTypeConfig(rcs.ProviderAddr.Provider, resource.Mode, resource.Type)
+		if schema == nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Missing resource schema",
+				fmt.Sprintf("Cannot check policies for %s because provider %s does not have a schema for this resource type. This is a bug in OpenTofu; please report it.", addr, rcs.ProviderAddr.Provider),
+			))
+			continue
+		}
+		change, err := rcs.Decode(schema.ImpliedType())
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to decode planned change",
+				fmt.Sprintf("Cannot check policies for %s because its planned change is invalid: %s.", addr, err),
+			))
+			continue
+		}
+
+		hclCtx := &hcl.EvalContext{
+			Variables: map[string]cty.Value{
+				"resource": policyResourceValue(change),
+			},
+			Functions: funcs,
+		}
+
+		for _, policy := range policies {
+			if policy.ResourceType != "" && policy.ResourceType != resource.Type {
+				continue
+			}
+			log.Printf("[TRACE] EvalPolicies: checking policy %q for %s", policy.Name, addr)
+			for i, rule := range policy.Rules {
+				diags = diags.Append(evalPolicyRule(policy, i, rule, addr, hclCtx, plan))
+			}
+		}
+	}
+
+	return diags
+}
+
+func evalPolicyRule(policy *configs.Policy, idx int, rule *configs.CheckRule, addr addrs.AbsResourceInstance, hclCtx *hcl.EvalContext, plan *plans.Plan) tfdiags.Diagnostics {
+	status, diags := evalCheckRuleCondition(rule, hclCtx)
+
+	mandatory := policy.EnforcementLevel == configs.PolicyMandatory
+	severity := hcl.DiagWarning
+	if mandatory {
+		severity = hcl.DiagError
+	}
+
+	switch status {
+	case checks.StatusUnknown:
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity:    severity,
+			Summary:     "Policy result known after apply",
+			Detail:      fmt.Sprintf("The condition of policy %q could not be evaluated for %s, because it depends on values that will not be known until the plan is applied.", policy.Name, addr),
+			Subject:     rule.Condition.Range().Ptr(),
+			Expression:  rule.Condition,
+			EvalContext: hclCtx,
+		})
+
+		// A mandatory policy must be known to pass before the change can be
+		// applied, so an unknown result counts as a violation.
+		if mandatory {
+			plan.PolicyViolations = append(plan.PolicyViolations, &plans.PolicyViolation{
+				Policy:    policy.Name,
+				Rule:      idx,
+				Addr:      addr,
+				Mandatory: true,
+				Message:   "The condition depends on values that will not be known until the plan is applied.",
+			})
+		}
+
+	case checks.StatusError:
+		// The diagnostics from evaluating the condition already describe
+		// the problem, but as above a mandatory policy that can't be
+		// evaluated must not let the change be applied.
+		if mandatory {
+			plan.PolicyViolations = append(plan.PolicyViolations, &plans.PolicyViolation{
+				Policy:    policy.Name,
+				Rule:      idx,
+				Addr:      addr,
+				Mandatory: true,
+				Message:   "The condition could not be evaluated.",
+			})
+		}
+
+	case checks.StatusFail:
+		errorMessage, moreDiags := evalCheckErrorMessage(rule.ErrorMessage, hclCtx)
+		diags = diags.Append(moreDiags)
+		if errorMessage == "" {
+			errorMessage = "This policy rule failed, but has an invalid error message as described in the other accompanying messages."
+		}
+
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity:    severity,
+			Summary:     fmt.Sprintf("Policy %q violated", policy.Name),
+			Detail:      fmt.Sprintf("%s\n\nThis rule failed for the planned change to %s.", errorMessage, addr),
+			Subject:     rule.Condition.Range().Ptr(),
+			Expression:  rule.Condition,
+			EvalContext: hclCtx,
+		})
+
+		plan.PolicyViolations = append(plan.PolicyViolations, &plans.PolicyViolation{
+			Policy:    policy.Name,
+			Rule:      idx,
+			Addr:      addr,
+			Mandatory: mandatory,
+			Message:   errorMessage,
+		})
+	}
+
+	return diags
+}
+
+// policyResourceValue returns the value of the symbol "resource" when
+// evaluating policy rules for the given change, which has the same attributes
+// as the change's representation in the JSON plan output.
+func policyResourceValue(change *plans.ResourceInstanceChange) cty.Value {
+	addr := change.Addr
+	resource := addr.Resource.Resource
+
+	mode := "managed"
+	if resource.Mode == addrs.DataResourceMode {
+		mode = "data"
+	}
+
+	index := cty.NullVal(cty.DynamicPseudoType)
+	if addr.Resource.Key != addrs.NoKey {
+		index = addr.Resource.Key.Value()
+	}
+
+	var actions []cty.Value
+	switch change.Action {
+	case plans.CreateThenDelete:
+		actions = []cty.Value{cty.StringVal("create"), cty.StringVal("delete")}
+	case plans.DeleteThenCreate:
+		actions = []cty.Value{cty.StringVal("delete"), cty.StringVal("create")}
+	case plans.Create:
+		actions = []cty.Value{cty.StringVal("create")}
+	case plans.Update:
+		actions = []cty.Value{cty.StringVal("update")}
+	case plans.Delete:
+		actions = []cty.Value{cty.StringVal("delete")}
+	case plans.Read:
+		actions = []cty.Value{cty.StringVal("read")}
+	case plans.Forget:
+		actions = []cty.Value{cty.StringVal("forget")}
+	default:
+		actions = []cty.Value{cty.StringVal("no-op")}
+	}
+
+	moduleAddr := cty.NullVal(cty.String)
+	if !addr.Module.IsRoot() {
+		moduleAddr = cty.StringVal(addr.Module.String())
+	}
+
+	return cty.ObjectVal(map[string]cty.Value{
+		"address":        cty.StringVal(addr.String()),
+		"module_address": moduleAddr,
+		"mode":           cty.StringVal(mode),
+		"type":           cty.StringVal(resource.Type),
+		"name":           cty.StringVal(resource.Name),
+		"index":          index,
+		"provider_name":  cty.StringVal(change.ProviderAddr.Provider.String()),
+		"actions":        cty.ListVal(actions),
+		"before":         change.Before,
+		"after":          change.After,
+	})
+}
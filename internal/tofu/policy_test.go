@@ -0,0 +1,296 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+func TestContextEvalPolicies(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  count       = 2
+  test_string = "hello ${count.index}"
+}
+
+resource "test_object" "b" {
+  test_number = 5
+}
+`,
+	})
+	policies := testPolicies(t, `
+policy "greeting" {
+  resource_type = "test_object"
+
+  rule {
+    condition     = resource.after.test_string == null || resource.after.test_string == "hello 0"
+    error_message = "Greeting must be \"hello 0\", not ${jsonencode(resource.after.test_string)}."
+  }
+}
+
+policy "numbers" {
+  enforcement_level = "advisory"
+
+  rule {
+    condition     = resource.after.test_number == null
+    error_message = "Numbers are discouraged."
+  }
+  rule {
+    condition     = contains(resource.actions, "create")
+    error_message = "Unused."
+  }
+}
+
+policy "other_type" {
+  resource_type = "test_other"
+
+  rule {
+    condition     = resource.after == null
+    error_message = "Unused."
+  }
+}
+`)
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, nil, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	diags = ctx.EvalPolicies(m, plan, policies)
+	if got, want := len(diags), 2; got != want {
+		t.Fatalf("wrong number of diagnostics %d; want %d\n%s", got, want, diags.ErrWithWarnings())
+	}
+	for _, diag := range diags {
+		switch diag.Description().Summary {
+		case `Policy "greeting" violated`:
+			if got, want := diag.Severity(), tfdiags.Error; got != want {
+				t.Errorf("wrong severity %s; want %s", got, want)
+			}
+		case `Policy "numbers" violated`:
+			if got, want := diag.Severity(), tfdiags.Warning; got != want {
+				t.Errorf("wrong severity %s; want %s", got, want)
+			}
+		default:
+			t.Errorf("unexpected diagnostic: %s", diag.Description().Summary)
+		}
+	}
+
+	want := []*plans.PolicyViolation{
+		{
+			Policy:    "greeting",
+			Rule:      0,
+			Addr:      mustResourceInstanceAddr(`test_object.a[1]`),
+			Mandatory: true,
+			Message:   `Greeting must be "hello 0", not "hello 1".`,
+		},
+		{
+			Policy:    "numbers",
+			Rule:      0,
+			Addr:      mustResourceInstanceAddr(`test_object.b`),
+			Mandatory: false,
+			Message:   "Numbers are discouraged.",
+		},
+	}
+	if diff := cmp.Diff(want, plan.PolicyViolations); diff != "" {
+		t.Errorf("wrong violations\n%s", diff)
+	}
+	if !plan.HasMandatoryPolicyViolations() {
+		t.Error("plan has no mandatory policy violations")
+	}
+}
+
+func TestContextEvalPolicies_unknown(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+}
+
+resource "test_object" "b" {
+  test_string = test_object.a.id
+}
+`,
+	})
+	policies := testPolicies(t, `
+policy "named" {
+  enforcement_level = "advisory"
+
+  rule {
+    condition     = resource.after.test_string != ""
+    error_message = "Unused."
+  }
+}
+`)
+
+	p := simpleMockProvider()
+	p.GetProviderSchemaResponse.ResourceTypes["test_object"].Block.Attributes["id"] = &configschema.Attribute{
+		Type:     cty.String,
+		Computed: true,
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, nil, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	diags = ctx.EvalPolicies(m, plan, policies)
+	assertNoErrors(t, diags)
+	if got, want := len(diags), 1; got != want {
+		t.Fatalf("wrong number of diagnostics %d; want %d\n%s", got, want, diags.ErrWithWarnings())
+	}
+	if got, want := diags[0].Description().Summary, "Policy result known after apply"; got != want {
+		t.Errorf("wrong summary %q; want %q", got, want)
+	}
+	if len(plan.PolicyViolations) != 0 {
+		t.Errorf("unexpected violations: %#v", plan.PolicyViolations)
+	}
+}
+
+func TestContextEvalPolicies_unknownMandatory(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+}
+
+resource "test_object" "b" {
+  test_string = test_object.a.id
+}
+`,
+	})
+	policies := testPolicies(t, `
+policy "named" {
+  rule {
+    condition     = resource.after.test_string != ""
+    error_message = "Unused."
+  }
+}
+`)
+
+	p := simpleMockProvider()
+	p.GetProviderSchemaResponse.ResourceTypes["test_object"].Block.Attributes["id"] = &configschema.Attribute{
+		Type:     cty.String,
+		Computed: true,
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, nil, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	diags = ctx.EvalPolicies(m, plan, policies)
+	if got, want := len(diags), 1; got != want {
+		t.Fatalf("wrong number of diagnostics %d; want %d\n%s", got, want, diags.ErrWithWarnings())
+	}
+	if got, want := diags[0].Severity(), tfdiags.Error; got != want {
+		t.Errorf("wrong severity %s; want %s", got, want)
+	}
+	if got, want := diags[0].Description().Summary, "Policy result known after apply"; got != want {
+		t.Errorf("wrong summary %q; want %q", got, want)
+	}
+	if !plan.HasMandatoryPolicyViolations() {
+		t.Error("plan has no mandatory policy violations")
+	}
+	if got, want := plan.PolicyViolations[0].Addr, mustResourceInstanceAddr(`test_object.b`); !got.Equal(want) {
+		t.Errorf("wrong violation address %s; want %s", got, want)
+	}
+}
+
+func TestContextEvalPolicies_error(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+}
+`,
+	})
+	policies := testPolicies(t, `
+policy "mandatory" {
+  rule {
+    condition     = resource.after.nonexistent != ""
+    error_message = "Unused."
+  }
+}
+
+policy "advisory" {
+  enforcement_level = "advisory"
+
+  rule {
+    condition     = resource.after.nonexistent != ""
+    error_message = "Unused."
+  }
+}
+`)
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, nil, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	diags = ctx.EvalPolicies(m, plan, policies)
+	if got, want := len(diags), 2; got != want {
+		t.Fatalf("wrong number of diagnostics %d; want %d\n%s", got, want, diags.ErrWithWarnings())
+	}
+	for _, diag := range diags {
+		if got, want := diag.Description().Summary, "Unsupported attribute"; got != want {
+			t.Errorf("wrong summary %q; want %q", got, want)
+		}
+	}
+
+	// Only the mandatory policy that couldn't be evaluated prevents the
+	// plan from being applied.
+	want := []*plans.PolicyViolation{
+		{
+			Policy:    "mandatory",
+			Rule:      0,
+			Addr:      mustResourceInstanceAddr(`test_object.a`),
+			Mandatory: true,
+			Message:   "The condition could not be evaluated.",
+		},
+	}
+	if diff := cmp.Diff(want, plan.PolicyViolations); diff != "" {
+		t.Errorf("wrong violations\n%s", diff)
+	}
+}
+
+// testPolicies loads the policies in the given policy file source.
+func testPolicies(t *testing.T, src string) []*configs.Policy {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.tfpolicy.hcl"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	policies, diags := configs.NewParser(nil).LoadPolicyDir(dir)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	return policies
+}
@@ -0,0 +1,363 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// providerForTest wraps a real provider while the testing framework executes
+// a run block that mocks the provider, or that overrides a resource belonging
+// to the provider.
+//
+// The wrapped provider is still used to retrieve schemas, to upgrade and
+// validate objects, but it is never configured and it never plans, applies or
+// reads any objects. Instead, providerForTest synthesizes the objects from the
+// resource schemas, filling in computed attributes with either the values
+// given by the test author or generated values.
+type providerForTest struct {
+	providers.Interface
+
+	// mockResources holds the default values from the mock_resource and
+	// mock_data blocks of a mocked provider, keyed by mode and type.
+	mockResources map[mockResourceKey]map[string]cty.Value
+
+	// overrideValues holds the values for a single overridden resource, if
+	// overridden is true. In that case mockResources is ignored.
+	overridden     bool
+	overrideValues map[string]cty.Value
+
+	// resourceInstance is the address of the resource instance the provider
+	// is acting on, if known. It seeds the generated values, so that each
+	// instance gets different values.
+	resourceInstance string
+}
+
+type mockResourceKey struct {
+	mode     addrs.ResourceMode
+	typeName string
+}
+
+var _ providers.Interface = (*providerForTest)(nil)
+
+// newProviderForMock returns a provider that mocks every resource and data
+// source of the given provider, using the given defaults where present.
+func newProviderForMock(internal providers.Interface, mockResources []*configs.MockResource) *providerForTest {
+	p := &providerForTest{
+		Interface:     internal,
+		mockResources: make(map[mockResourceKey]map[string]cty.Value, len(mockResources)),
+	}
+	for _, res := range mockResources {
+		p.mockResources[mockResourceKey{res.Mode, res.Type}] = res.Defaults
+	}
+	return p
+}
+
+// newProviderForOverriddenResource returns a provider that is only suitable
+// for use by a single overridden resource, using the given values for its
+// computed attributes.
+func newProviderForOverriddenResource(internal providers.Interface, values map[string]cty.Value) *providerForTest {
+	return &providerForTest{
+		Interface:      internal,
+		overridden:     true,
+		overrideValues: values,
+	}
+}
+
+// withResourceInstance returns a copy of the provider for use by the given
+// resource instance.
+func (p *providerForTest) withResourceInstance(addr addrs.AbsResourceInstance) *providerForTest {
+	ret := *p
+	ret.resourceInstance = addr.String()
+	return &ret
+}
+
+func (p *providerForTest) ConfigureProvider(providers.ConfigureProviderRequest) providers.ConfigureProviderResponse {
+	// Mocked providers are never configured, so there is nothing to do here.
+	return providers.ConfigureProviderResponse{}
+}
+
+func (p *providerForTest) ReadResource(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+	// There is no remote object to refresh, so the prior state is always
+	// up-to-date.
+	return providers.ReadResourceResponse{
+		NewState: req.PriorState,
+		Private:  req.Private,
+	}
+}
+
+func (p *providerForTest) PlanResourceChange(req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+	var resp providers.PlanResourceChangeResponse
+
+	if req.ProposedNewState.IsNull() {
+		// Then the object is being destroyed.
+		resp.PlannedState = req.ProposedNewState
+		resp.PlannedPrivate = req.PriorPrivate
+		return resp
+	}
+
+	schema, diags := p.schemaFor(addrs.ManagedResourceMode, req.TypeName)
+	if diags.HasErrors() {
+		resp.Diagnostics = diags
+		return resp
+	}
+
+	// We compose the planned state from the configuration rather than from
+	// the proposed new state, so that computed attributes always reflect the
+	// current mock and override values instead of those from a previous run.
+	resp.PlannedState, resp.Diagnostics = p.valueComposer(req.TypeName).composeBySchema(schema, req.Config, p.valuesFor(addrs.ManagedResourceMode, req.TypeName), false)
+	resp.PlannedPrivate = req.PriorPrivate
+	return resp
+}
+
+func (p *providerForTest) ApplyResourceChange(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+	var resp providers.ApplyResourceChangeResponse
+
+	if req.PlannedState.IsNull() {
+		// Then the object is being destroyed.
+		resp.NewState = req.PlannedState
+		return resp
+	}
+
+	schema, diags := p.schemaFor(addrs.ManagedResourceMode, req.TypeName)
+	if diags.HasErrors() {
+		resp.Diagnostics = diags
+		return resp
+	}
+
+	resp.NewState, resp.Diagnostics = p.valueComposer(req.TypeName).composeBySchema(schema, req.PlannedState, p.valuesFor(addrs.ManagedResourceMode, req.TypeName), true)
+	resp.Private = req.PlannedPrivate
+	return resp
+}
+
+func (p *providerForTest) ImportResourceState(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+	var resp providers.ImportResourceStateResponse
+	resp.Diagnostics = resp.Diagnostics.Append(tfdiags.Sourceless(
+		tfdiags.Error,
+		"Cannot import mocked resource",
+		fmt.Sprintf("Resources of type %q are mocked or overridden in this test run, so there is no remote object to import.", req.TypeName),
+	))
+	return resp
+}
+
+func (p *providerForTest) ReadDataSource(req providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+	var resp providers.ReadDataSourceResponse
+
+	schema, diags := p.schemaFor(addrs.DataResourceMode, req.TypeName)
+	if diags.HasErrors() {
+		resp.Diagnostics = diags
+		return resp
+	}
+
+	resp.State, resp.Diagnostics = p.valueComposer(req.TypeName).composeBySchema(schema, req.Config, p.valuesFor(addrs.DataResourceMode, req.TypeName), true)
+	return resp
+}
+
+func (p *providerForTest) valueComposer(typeName string) mockValueComposer {
+	// The resource instance address already includes the type name, so
+	// the type name alone is used only when the instance isn't known.
+	seed := typeName
+	if p.resourceInstance != "" {
+		seed = p.resourceInstance
+	}
+	return newMockValueComposer(seed)
+}
+
+func (p *providerForTest) valuesFor(mode addrs.ResourceMode, typeName string) map[string]cty.Value {
+	if p.overridden {
+		return p.overrideValues
+	}
+	return p.mockResources[mockResourceKey{mode, typeName}]
+}
+
+func (p *providerForTest) schemaFor(mode addrs.ResourceMode, typeName string) (*configschema.Block, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	resp := p.GetProviderSchema()
+	diags = diags.Append(resp.Diagnostics)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	schema, _ := resp.SchemaForResourceType(mode, typeName)
+	if schema == nil {
+		return nil, diags.Append(fmt.Errorf("provider does not support %s type %q", mode, typeName))
+	}
+	return schema, diags
+}
+
+// mockValueComposer synthesizes values for mocked objects.
+//
+// The generated values are pseudo-random, but are seeded from the resource
+// instance address so that planning the same object twice, as happens during
+// apply, produces consistent results while different instances get different
+// values.
+type mockValueComposer struct {
+	rand *rand.Rand
+}
+
+func newMockValueComposer(seed string) mockValueComposer {
+	hash := fnv.New64()
+	_, _ = hash.Write([]byte(seed))
+	return mockValueComposer{
+		rand: rand.New(rand.NewSource(int64(hash.Sum64()))), //nolint:gosec // These values need not be secure.
+	}
+}
+
+// composeBySchema returns a copy of the given object with all null
+// computed attributes set, either to the corresponding entry in values or to
+// a value generated from the attribute type.
+//
+// Unknown values are left untouched unless final is set, in which case they
+// are replaced in the same way as null computed attributes.
+func (mvc mockValueComposer) composeBySchema(schema *configschema.Block, obj cty.Value, values map[string]cty.Value, final bool) (cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	if obj.IsNull() || !obj.IsKnown() {
+		obj = cty.NullVal(schema.ImpliedType())
+		if !final {
+			return obj, diags
+		}
+	}
+
+	attrs := make(map[string]cty.Value)
+
+	for name, attr := range schema.Attributes {
+		current := cty.NullVal(attr.ImpliedType())
+		if !obj.IsNull() {
+			current = obj.GetAttr(name)
+		}
+
+		switch {
+		case !current.IsKnown() && !final:
+			attrs[name] = current
+		case current.IsKnown() && !current.IsNull():
+			attrs[name] = current
+		case !attr.Computed:
+			attrs[name] = cty.NullVal(attr.ImpliedType())
+		default:
+			if value, exists := values[name]; exists {
+				converted, err := convert.Convert(value, attr.ImpliedType())
+				if err != nil {
+					diags = diags.Append(tfdiags.Sourceless(
+						tfdiags.Error,
+						"Invalid mock value",
+						fmt.Sprintf("The value given for the attribute %q is not valid: %s.", name, tfdiags.FormatError(err)),
+					))
+					attrs[name] = cty.NullVal(attr.ImpliedType())
+					continue
+				}
+				attrs[name] = converted
+				continue
+			}
+			attrs[name] = mvc.generateValue(attr.ImpliedType())
+		}
+	}
+
+	for name, block := range schema.BlockTypes {
+		current := cty.NullVal(block.ImpliedType())
+		if !obj.IsNull() {
+			current = obj.GetAttr(name)
+		}
+
+		if current.IsNull() || !current.IsKnown() {
+			attrs[name] = current
+			continue
+		}
+
+		var nestedValues map[string]cty.Value
+		if value, exists := values[name]; exists && !value.IsNull() && (value.Type().IsObjectType() || value.Type().IsMapType()) {
+			nestedValues = value.AsValueMap()
+		}
+
+		switch block.Nesting {
+		case configschema.NestingSingle, configschema.NestingGroup:
+			nested, nestedDiags := mvc.composeBySchema(&block.Block, current, nestedValues, final)
+			diags = diags.Append(nestedDiags)
+			attrs[name] = nested
+		default:
+			if current.LengthInt() == 0 {
+				attrs[name] = current
+				continue
+			}
+
+			elems := make(map[string]cty.Value)
+			var list []cty.Value
+			for it := current.ElementIterator(); it.Next(); {
+				key, value := it.Element()
+				nested, nestedDiags := mvc.composeBySchema(&block.Block, value, nestedValues, final)
+				diags = diags.Append(nestedDiags)
+				if key.Type() == cty.String {
+					elems[key.AsString()] = nested
+				}
+				list = append(list, nested)
+			}
+
+			switch {
+			case current.Type().IsListType():
+				attrs[name] = cty.ListVal(list)
+			case current.Type().IsSetType():
+				attrs[name] = cty.SetVal(list)
+			case current.Type().IsTupleType():
+				attrs[name] = cty.TupleVal(list)
+			case current.Type().IsMapType():
+				attrs[name] = cty.MapVal(elems)
+			default:
+				attrs[name] = cty.ObjectVal(elems)
+			}
+		}
+	}
+
+	return cty.ObjectVal(attrs), diags
+}
+
+const mockStringCharset = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// generateValue returns an arbitrary value of the given type, suitable for
+// populating a computed attribute of a mocked object.
+func (mvc mockValueComposer) generateValue(ty cty.Type) cty.Value {
+	switch {
+	case ty == cty.String:
+		buf := make([]byte, 8)
+		for i := range buf {
+			buf[i] = mockStringCharset[mvc.rand.Intn(len(mockStringCharset))]
+		}
+		return cty.StringVal(string(buf))
+	case ty == cty.Number:
+		return cty.Zero
+	case ty == cty.Bool:
+		return cty.False
+	case ty.IsListType():
+		return cty.ListValEmpty(ty.ElementType())
+	case ty.IsSetType():
+		return cty.SetValEmpty(ty.ElementType())
+	case ty.IsMapType():
+		return cty.MapValEmpty(ty.ElementType())
+	case ty.IsObjectType():
+		attrs := make(map[string]cty.Value)
+		for name, attrTy := range ty.AttributeTypes() {
+			attrs[name] = mvc.generateValue(attrTy)
+		}
+		return cty.ObjectVal(attrs)
+	case ty.IsTupleType():
+		elems := make([]cty.Value, 0, len(ty.TupleElementTypes()))
+		for _, elemTy := range ty.TupleElementTypes() {
+			elems = append(elems, mvc.generateValue(elemTy))
+		}
+		return cty.TupleVal(elems)
+	default:
+		return cty.NullVal(ty)
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/providers"
+)
+
+func TestProviderForTest_mock(t *testing.T) {
+	internal := testProvider("test")
+	internal.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		ResourceTypes: map[string]*configschema.Block{
+			"test_object": {
+				Attributes: map[string]*configschema.Attribute{
+					"id":    {Type: cty.String, Computed: true},
+					"value": {Type: cty.String, Optional: true},
+					"count": {Type: cty.Number, Computed: true},
+				},
+			},
+		},
+		DataSources: map[string]*configschema.Block{
+			"test_data": {
+				Attributes: map[string]*configschema.Attribute{
+					"name":   {Type: cty.String, Required: true},
+					"result": {Type: cty.List(cty.String), Computed: true},
+				},
+			},
+		},
+	})
+
+	p := newProviderForMock(internal, []*configs.MockResource{
+		{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test_object",
+			Defaults: map[string]cty.Value{
+				"id": cty.StringVal("mocked"),
+			},
+		},
+	})
+
+	config := cty.ObjectVal(map[string]cty.Value{
+		"id":    cty.NullVal(cty.String),
+		"value": cty.UnknownVal(cty.String),
+		"count": cty.NullVal(cty.Number),
+	})
+
+	planResp := p.PlanResourceChange(providers.PlanResourceChangeRequest{
+		TypeName:         "test_object",
+		PriorState:       cty.NullVal(config.Type()),
+		ProposedNewState: config,
+		Config:           config,
+	})
+	if planResp.Diagnostics.HasErrors() {
+		t.Fatal(planResp.Diagnostics.Err())
+	}
+	wantPlanned := cty.ObjectVal(map[string]cty.Value{
+		"id":    cty.StringVal("mocked"),
+		"value": cty.UnknownVal(cty.String),
+		"count": cty.Zero,
+	})
+	if !planResp.PlannedState.RawEquals(wantPlanned) {
+		t.Errorf("wrong planned state\ngot:  %#v\nwant: %#v", planResp.PlannedState, wantPlanned)
+	}
+
+	planned := cty.ObjectVal(map[string]cty.Value{
+		"id":    cty.StringVal("mocked"),
+		"value": cty.StringVal("hello"),
+		"count": cty.UnknownVal(cty.Number),
+	})
+	applyResp := p.ApplyResourceChange(providers.ApplyResourceChangeRequest{
+		TypeName:     "test_object",
+		PriorState:   cty.NullVal(config.Type()),
+		PlannedState: planned,
+		Config:       config,
+	})
+	if applyResp.Diagnostics.HasErrors() {
+		t.Fatal(applyResp.Diagnostics.Err())
+	}
+	wantApplied := cty.ObjectVal(map[string]cty.Value{
+		"id":    cty.StringVal("mocked"),
+		"value": cty.StringVal("hello"),
+		"count": cty.Zero,
+	})
+	if !applyResp.NewState.RawEquals(wantApplied) {
+		t.Errorf("wrong new state\ngot:  %#v\nwant: %#v", applyResp.NewState, wantApplied)
+	}
+
+	readResp := p.ReadDataSource(providers.ReadDataSourceRequest{
+		TypeName: "test_data",
+		Config: cty.ObjectVal(map[string]cty.Value{
+			"name":   cty.StringVal("foo"),
+			"result": cty.NullVal(cty.List(cty.String)),
+		}),
+	})
+	if readResp.Diagnostics.HasErrors() {
+		t.Fatal(readResp.Diagnostics.Err())
+	}
+	wantRead := cty.ObjectVal(map[string]cty.Value{
+		"name":   cty.StringVal("foo"),
+		"result": cty.ListValEmpty(cty.String),
+	})
+	if !readResp.State.RawEquals(wantRead) {
+		t.Errorf("wrong data source state\ngot:  %#v\nwant: %#v", readResp.State, wantRead)
+	}
+
+	if internal.ConfigureProviderCalled || internal.PlanResourceChangeCalled || internal.ApplyResourceChangeCalled || internal.ReadDataSourceCalled {
+		t.Errorf("the mocked provider called the real provider")
+	}
+}
+
+func TestProviderForTest_perInstanceValues(t *testing.T) {
+	internal := testProvider("test")
+	internal.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		ResourceTypes: map[string]*configschema.Block{
+			"test_object": {
+				Attributes: map[string]*configschema.Attribute{
+					"id": {Type: cty.String, Computed: true},
+				},
+			},
+		},
+	})
+	p := newProviderForMock(internal, nil)
+
+	config := cty.ObjectVal(map[string]cty.Value{
+		"id": cty.NullVal(cty.String),
+	})
+	plan := func(key addrs.InstanceKey) cty.Value {
+		addr := mustResourceInstanceAddr("test_object.a").ContainingResource().Instance(key)
+		resp := p.withResourceInstance(addr).PlanResourceChange(providers.PlanResourceChangeRequest{
+			TypeName:         "test_object",
+			PriorState:       cty.NullVal(config.Type()),
+			ProposedNewState: config,
+			Config:           config,
+		})
+		if resp.Diagnostics.HasErrors() {
+			t.Fatal(resp.Diagnostics.Err())
+		}
+		return resp.PlannedState
+	}
+
+	first, second := plan(addrs.IntKey(0)), plan(addrs.IntKey(1))
+	if first.RawEquals(second) {
+		t.Errorf("different instances got the same values: %#v", first)
+	}
+	if again := plan(addrs.IntKey(0)); !again.RawEquals(first) {
+		t.Errorf("planning the same instance again produced different values\ngot:  %#v\nwant: %#v", again, first)
+	}
+}
+
+func TestProviderForTest_overriddenResource(t *testing.T) {
+	internal := testProvider("test")
+	internal.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		ResourceTypes: map[string]*configschema.Block{
+			"test_object": {
+				Attributes: map[string]*configschema.Attribute{
+					"id":    {Type: cty.String, Optional: true, Computed: true},
+					"value": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+	})
+
+	p := newProviderForOverriddenResource(internal, map[string]cty.Value{
+		"id":    cty.StringVal("overridden"),
+		"value": cty.StringVal("ignored"),
+	})
+
+	prior := cty.ObjectVal(map[string]cty.Value{
+		"id":    cty.StringVal("previous"),
+		"value": cty.StringVal("hello"),
+	})
+	config := cty.ObjectVal(map[string]cty.Value{
+		"id":    cty.NullVal(cty.String),
+		"value": cty.StringVal("hello"),
+	})
+
+	resp := p.PlanResourceChange(providers.PlanResourceChangeRequest{
+		TypeName:         "test_object",
+		PriorState:       prior,
+		ProposedNewState: prior,
+		Config:           config,
+	})
+	if resp.Diagnostics.HasErrors() {
+		t.Fatal(resp.Diagnostics.Err())
+	}
+
+	// Computed attributes take the override value, even if the prior state
+	// holds something else, while configured attributes are left alone.
+	want := cty.ObjectVal(map[string]cty.Value{
+		"id":    cty.StringVal("overridden"),
+		"value": cty.StringVal("hello"),
+	})
+	if !resp.PlannedState.RawEquals(want) {
+		t.Errorf("wrong planned state\ngot:  %#v\nwant: %#v", resp.PlannedState, want)
+	}
+
+	destroyResp := p.PlanResourceChange(providers.PlanResourceChangeRequest{
+		TypeName:         "test_object",
+		PriorState:       prior,
+		ProposedNewState: cty.NullVal(prior.Type()),
+		Config:           cty.NullVal(prior.Type()),
+	})
+	if !destroyResp.PlannedState.IsNull() {
+		t.Errorf("expected null planned state for destroy, got %#v", destroyResp.PlannedState)
+	}
+
+	if internal.PlanResourceChangeCalled {
+		t.Errorf("the overridden resource called the real provider")
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/providers"
+)
+
+// functionProviders tracks the provider instances used to call
+// provider-defined functions during a single graph walk.
+//
+// Provider-defined functions don't depend on the provider configuration, so
+// they are called on a separate unconfigured instance of each provider, which
+// is started the first time one of its functions is called and then shared
+// by all modules until the walk completes.
+type functionProviders struct {
+	plugins *contextPlugins
+
+	mu        sync.Mutex
+	specs     map[addrs.Provider]map[string]providers.FunctionSpec
+	instances map[addrs.Provider]providers.Interface
+	modules   map[*configs.Module]map[string]function.Function
+}
+
+func newFunctionProviders(plugins *contextPlugins) *functionProviders {
+	return &functionProviders{
+		plugins:   plugins,
+		specs:     make(map[addrs.Provider]map[string]providers.FunctionSpec),
+		instances: make(map[addrs.Provider]providers.Interface),
+		modules:   make(map[*configs.Module]map[string]function.Function),
+	}
+}
+
+// ForModule returns all of the functions exported by the providers required
+// by the given module, keyed by the namespaced names that the module uses to
+// call them. The result is built once per module and then reused for the
+// rest of the walk, so callers must not modify it.
+func (fp *functionProviders) ForModule(mod *configs.Module) map[string]function.Function {
+	if fp == nil || mod == nil || mod.ProviderRequirements == nil {
+		return nil
+	}
+
+	fp.mu.Lock()
+	ret, ok := fp.modules[mod]
+	fp.mu.Unlock()
+	if ok {
+		return ret
+	}
+
+	for localName, req := range mod.ProviderRequirements.RequiredProviders {
+		addr := req.Type
+		for name, spec := range fp.functionSpecs(addr) {
+			if ret == nil {
+				ret = make(map[string]function.Function)
+			}
+			ret[lang.ProviderFunctionName(localName, name)] = spec.AsFunction(fp.caller(addr, name))
+		}
+	}
+
+	fp.mu.Lock()
+	fp.modules[mod] = ret
+	fp.mu.Unlock()
+	return ret
+}
+
+// functionSpecs returns the functions exported by the given provider, which
+// are memoized for the rest of the walk.
+func (fp *functionProviders) functionSpecs(addr addrs.Provider) map[string]providers.FunctionSpec {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	if specs, ok := fp.specs[addr]; ok {
+		return specs
+	}
+
+	var specs map[string]providers.FunctionSpec
+	if fp.plugins.HasProvider(addr) {
+		schema, err := fp.plugins.ProviderSchema(addr)
+		if err != nil {
+			// Any problem with the schema is reported elsewhere, in terms of
+			// the objects that actually need it.
+			log.Printf("[WARN] Failed to read functions from provider %s: %s", addr, err)
+		}
+		specs = schema.Functions
+	}
+	fp.specs[addr] = specs
+	return specs
+}
+
+func (fp *functionProviders) caller(addr addrs.Provider, name string) func(args []cty.Value) (cty.Value, error) {
+	return func(args []cty.Value) (cty.Value, error) {
+		provider, err := fp.instance(addr)
+		if err != nil {
+			return cty.NilVal, err
+		}
+
+		resp := provider.CallFunction(providers.CallFunctionRequest{
+			Name:      name,
+			Arguments: args,
+		})
+		if resp.Diagnostics.HasErrors() {
+			return cty.NilVal, resp.Diagnostics.Err()
+		}
+		return resp.Result, nil
+	}
+}
+
+func (fp *functionProviders) instance(addr addrs.Provider) (providers.Interface, error) {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	if provider, ok := fp.instances[addr]; ok {
+		return provider, nil
+	}
+
+	log.Printf("[TRACE] Initializing provider %s to call its functions", addr)
+	provider, err := fp.plugins.NewProviderInstance(addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate provider %s to call its functions: %w", addr, err)
+	}
+	fp.instances[addr] = provider
+	return provider, nil
+}
+
+// Close shuts down all of the provider instances started to call functions.
+func (fp *functionProviders) Close() {
+	if fp == nil {
+		return
+	}
+
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	for addr, provider := range fp.instances {
+		if err := provider.Close(); err != nil {
+			log.Printf("[WARN] Failed to close provider %s after calling its functions: %s", addr, err)
+		}
+		delete(fp.instances, addr)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestFunctionProviders(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+terraform {
+  required_providers {
+    local = {
+      source = "hashicorp/test"
+    }
+  }
+}
+`,
+	})
+
+	p := &MockProvider{
+		GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+			Functions: map[string]providers.FunctionSpec{
+				"echo": {
+					Parameters: []providers.FunctionParameterSpec{
+						{
+							Name: "input",
+							Type: cty.String,
+						},
+					},
+					Return: cty.String,
+				},
+			},
+		},
+		CallFunctionFn: func(req providers.CallFunctionRequest) (resp providers.CallFunctionResponse) {
+			if req.Name != "echo" {
+				t.Fatalf("wrong function called %q", req.Name)
+			}
+			resp.Result = req.Arguments[0]
+			return resp
+		},
+	}
+
+	fp := newFunctionProviders(newContextPlugins(map[addrs.Provider]providers.Factory{
+		addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+	}, nil))
+
+	funcs := fp.ForModule(m.Module)
+	if _, ok := funcs["provider::local::echo"]; !ok || len(funcs) != 1 {
+		t.Fatalf("wrong functions %#v", funcs)
+	}
+	if again := fp.ForModule(m.Module); reflect.ValueOf(again).Pointer() != reflect.ValueOf(funcs).Pointer() {
+		t.Fatal("functions rebuilt for the same module")
+	}
+
+	t.Run("known argument", func(t *testing.T) {
+		got, err := funcs["provider::local::echo"].Call([]cty.Value{cty.StringVal("hello")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := cty.StringVal("hello"); !want.RawEquals(got) {
+			t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+		}
+	})
+
+	t.Run("unknown argument", func(t *testing.T) {
+		p.CallFunctionCalled = false
+		got, err := funcs["provider::local::echo"].Call([]cty.Value{cty.UnknownVal(cty.String)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := cty.UnknownVal(cty.String); !want.RawEquals(got) {
+			t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+		}
+		if p.CallFunctionCalled {
+			t.Fatal("provider called with unknown argument")
+		}
+	})
+
+	t.Run("expression", func(t *testing.T) {
+		expr, diags := hclsyntax.ParseExpression([]byte(`provider::local::echo("world")`), "test.tf", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		got, diags := expr.Value(&hcl.EvalContext{Functions: funcs})
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		if want := cty.StringVal("world"); !want.RawEquals(got) {
+			t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+		}
+	})
+
+	p.CloseCalled = false
+	fp.Close()
+	if !p.CloseCalled {
+		t.Fatal("provider not closed")
+	}
+}
+
+func TestContext2Plan_providerFunctions(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+terraform {
+  required_providers {
+    test = {
+      source = "hashicorp/test"
+    }
+  }
+}
+
+resource "test_object" "a" {
+  test_string = provider::test::upper("hello")
+}
+
+output "out" {
+  value = provider::test::upper(test_object.a.test_string)
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	p.GetProviderSchemaResponse.Functions = map[string]providers.FunctionSpec{
+		"upper": {
+			Parameters: []providers.FunctionParameterSpec{
+				{
+					Name: "input",
+					Type: cty.String,
+				},
+			},
+			Return: cty.String,
+		},
+	}
+	p.CallFunctionFn = func(req providers.CallFunctionRequest) (resp providers.CallFunctionResponse) {
+		input := req.Arguments[0].AsString()
+		if input == "" {
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("input must not be empty"))
+			return resp
+		}
+		resp.Result = cty.StringVal(strings.ToUpper(input))
+		return resp
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	schema := p.GetProviderSchemaResponse.ResourceTypes["test_object"].Block
+	change := plan.Changes.ResourceInstance(mustResourceInstanceAddr("test_object.a"))
+	if change == nil {
+		t.Fatal("no change planned for test_object.a")
+	}
+	decoded, err := change.Decode(schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := decoded.After.GetAttr("test_string"), cty.StringVal("HELLO"); !want.RawEquals(got) {
+		t.Fatalf("wrong test_string\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	t.Run("error", func(t *testing.T) {
+		m := testModuleInline(t, map[string]string{
+			"main.tf": `
+terraform {
+  required_providers {
+    test = {
+      source = "hashicorp/test"
+    }
+  }
+}
+
+output "out" {
+  value = provider::test::upper("")
+}
+`,
+		})
+
+		_, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+		if !diags.HasErrors() {
+			t.Fatal("succeeded; want errors")
+		}
+		if got, want := diags.Err().Error(), "input must not be empty"; !strings.Contains(got, want) {
+			t.Fatalf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+		}
+	})
+}
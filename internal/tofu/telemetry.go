@@ -0,0 +1,24 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer trace.Tracer
+
+func init() {
+	tracer = otel.Tracer("github.com/opentofu/opentofu/internal/tofu")
+}
+
+// Attribute keys for the trace spans recorded during graph walks.
+const (
+	traceAttrWalkOperation   = attribute.Key("opentofu.walk.operation")
+	traceAttrResourceAddress = attribute.Key("opentofu.resource.address")
+	traceAttrResourceAction  = attribute.Key("opentofu.resource.action")
+	traceAttrModulePath      = attribute.Key("opentofu.module.path")
+)
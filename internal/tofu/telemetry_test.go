@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestContext2Plan_traceSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+	})
+
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+module "child" {
+  source = "./child"
+}
+`,
+		"child/main.tf": `
+resource "test_object" "a" {
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	traceCtx, parent := otel.Tracer("test").Start(context.Background(), "test")
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+		TraceContext: traceCtx,
+	})
+
+	_, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+	parent.End()
+
+	var walk, instance sdktrace.ReadOnlySpan
+	byID := make(map[trace.SpanID]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		byID[span.SpanContext().SpanID()] = span
+		switch span.Name() {
+		case "graph walk":
+			if walk == nil {
+				walk = span
+			}
+		case "module.child.test_object.a":
+			instance = span
+		}
+	}
+
+	if walk == nil {
+		t.Fatal("no span recorded for the graph walk")
+	}
+	if got, want := walk.Parent().SpanID(), parent.SpanContext().SpanID(); got != want {
+		t.Errorf("graph walk span has wrong parent %s; want %s", got, want)
+	}
+
+	if instance == nil {
+		t.Fatal("no span recorded for the resource instance")
+	}
+	if got, want := instance.SpanContext().TraceID(), parent.SpanContext().TraceID(); got != want {
+		t.Errorf("resource instance span is in the wrong trace %s; want %s", got, want)
+	}
+	// The instance is in the subgraph of the resource node, so its span is
+	// recorded under the span of the resource node that expanded it.
+	if expand := byID[instance.Parent().SpanID()]; expand == nil || expand.Name() != "module.child.test_object.a (expand)" {
+		t.Errorf("resource instance span is not a child of the resource's expand span")
+	}
+
+	want := map[attribute.Key]string{
+		traceAttrResourceAddress: "module.child.test_object.a",
+		traceAttrModulePath:      "module.child",
+		traceAttrResourceAction:  "Create",
+	}
+	got := make(map[attribute.Key]string)
+	for _, attr := range instance.Attributes() {
+		got[attr.Key] = attr.Value.AsString()
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("wrong value for attribute %s: got %q, want %q", key, got[key], value)
+		}
+	}
+}
@@ -307,6 +307,15 @@ func (t *pruneUnusedNodesTransformer) Transform(g *Graph) error {
 			func() {
 				n := nodes[i]
 				switch n := n.(type) {
+				case *nodeVariableValidation:
+					// variable validations are kept for as long as the
+					// variable they check.
+					for _, v := range g.DownEdges(n) {
+						if n.validates(v) {
+							return
+						}
+					}
+
 				case graphNodeTemporaryValue:
 					// root module outputs indicate they are not temporary by
 					// returning false here.
@@ -317,6 +326,11 @@ func (t *pruneUnusedNodesTransformer) Transform(g *Graph) error {
 					// temporary values, which consist of variables, locals,
 					// and outputs, must be kept if anything refers to them.
 					for _, v := range g.UpEdges(n) {
+						// a variable's own validation rules don't need it to
+						// be kept.
+						if vv, ok := v.(*nodeVariableValidation); ok && vv.validates(n) {
+							continue
+						}
 						// keep any value which is connected through a
 						// reference
 						if _, ok := v.(GraphNodeReferencer); ok {
@@ -89,7 +89,7 @@ func (t *DiffTransformer) Transform(g *Graph) error {
 		// Depending on the action we'll need some different combinations of
 		// nodes, because destroying uses a special node type separate from
 		// other actions.
-		var update, delete, createBeforeDestroy bool
+		var update, delete, forget, createBeforeDestroy bool
 		switch rc.Action {
 		case plans.NoOp:
 			// For a no-op change we don't take any action but we still
@@ -99,6 +99,8 @@ func (t *DiffTransformer) Transform(g *Graph) error {
 			update = t.hasConfigConditions(addr)
 		case plans.Delete:
 			delete = true
+		case plans.Forget:
+			forget = true
 		case plans.DeleteThenCreate, plans.CreateThenDelete:
 			update = true
 			delete = true
@@ -209,6 +211,13 @@ func (t *DiffTransformer) Transform(g *Graph) error {
 			g.Add(node)
 		}
 
+		if forget {
+			node := &NodeForgetResourceInstance{
+				NodeAbstractResourceInstance: NewNodeAbstractResourceInstance(addr),
+			}
+			log.Printf("[TRACE] DiffTransformer: %s will be represented for removal from state by %s", addr, dag.VertexName(node))
+			g.Add(node)
+		}
 	}
 
 	log.Printf("[TRACE] DiffTransformer complete")
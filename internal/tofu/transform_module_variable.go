@@ -111,6 +111,15 @@ func (t *ModuleVariableTransformer) transformSingle(g *Graph, parent, c *configs
 			Expr:   expr,
 		}
 		g.Add(node)
+
+		if len(v.Validations) != 0 {
+			g.Add(&nodeVariableValidation{
+				Addr:   node.Addr,
+				Module: c.Path,
+				Config: v,
+				Expr:   expr,
+			})
+		}
 	}
 
 	return nil
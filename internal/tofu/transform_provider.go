@@ -82,6 +82,20 @@ type GraphNodeProviderConsumer interface {
 	SetProvider(addrs.AbsProviderConfig)
 }
 
+// graphNodeProviderInstanceConsumer is a GraphNodeProviderConsumer that may
+// use an instance of a provider configuration with for_each.
+type graphNodeProviderInstanceConsumer interface {
+	GraphNodeProviderConsumer
+
+	// providerKeyExpr returns the expression that selects an instance of the
+	// provider configuration in the node's own provider reference, if any.
+	providerKeyExpr() hcl.Expression
+
+	// setProviderKeyExpr records how to select the instance of the resolved
+	// provider configuration, which is nil unless it has for_each.
+	setProviderKeyExpr(*providerKeyExpr)
+}
+
 // ProviderTransformer is a GraphTransformer that maps resources to providers
 // within the graph. This will error if there are any resources that don't map
 // to proper resources.
@@ -226,9 +240,23 @@ func (t *ProviderTransformer) Transform(g *Graph) error {
 				break
 			}
 
+			// An instance of a provider configuration with for_each is
+			// selected either by the node's own provider reference or by the
+			// module call that passed the configuration to the node's module.
+			var keyExpr *providerKeyExpr
+			ic, isInstanceConsumer := v.(graphNodeProviderInstanceConsumer)
+			if isInstanceConsumer && !req.Exact {
+				if expr := ic.providerKeyExpr(); expr != nil {
+					keyExpr = &providerKeyExpr{Expr: expr}
+				}
+			}
+
 			// see if this is a proxy provider pointing to another concrete config
 			if p, ok := target.(*graphNodeProxyProvider); ok {
 				g.Remove(p)
+				if keyExpr == nil {
+					keyExpr = p.KeyExpr()
+				}
 				target = p.Target()
 			}
 
@@ -236,6 +264,9 @@ func (t *ProviderTransformer) Transform(g *Graph) error {
 			if pv, ok := v.(GraphNodeProviderConsumer); ok {
 				pv.SetProvider(target.ProviderAddr())
 			}
+			if isInstanceConsumer {
+				ic.setProviderKeyExpr(keyExpr)
+			}
 			g.Connect(dag.BasicEdge(v, target))
 		}
 	}
@@ -444,6 +475,10 @@ func (n *graphNodeCloseProvider) DotNode(name string, opts *dag.DotOpts) *dag.Do
 type graphNodeProxyProvider struct {
 	addr   addrs.AbsProviderConfig
 	target GraphNodeProvider
+
+	// keyExpr selects an instance of the target if it's a provider
+	// configuration with for_each.
+	keyExpr hcl.Expression
 }
 
 var (
@@ -463,6 +498,21 @@ func (n *graphNodeProxyProvider) Name() string {
 	return n.addr.String() + " (proxy)"
 }
 
+// KeyExpr returns how to select an instance of the concrete provider
+// configuration, or nil if it doesn't have for_each.
+func (n *graphNodeProxyProvider) KeyExpr() *providerKeyExpr {
+	if n.keyExpr != nil {
+		return &providerKeyExpr{
+			Expr:       n.keyExpr,
+			ModuleCall: n.addr.Module,
+		}
+	}
+	if t, ok := n.target.(*graphNodeProxyProvider); ok {
+		return t.KeyExpr()
+	}
+	return nil
+}
+
 // find the concrete provider instance
 func (n *graphNodeProxyProvider) Target() GraphNodeProvider {
 	switch t := n.target.(type) {
@@ -673,8 +723,9 @@ func (t *ProviderConfigTransformer) addProxyProviders(g *Graph, c *configs.Confi
 		}
 
 		proxy := &graphNodeProxyProvider{
-			addr:   fullAddr,
-			target: parentProvider,
+			addr:    fullAddr,
+			target:  parentProvider,
+			keyExpr: pair.InParent.KeyExpression,
 		}
 
 		concreteProvider := t.providers[fullName]
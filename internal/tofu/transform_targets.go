@@ -19,12 +19,25 @@ type GraphNodeTargetable interface {
 	SetTargets([]addrs.Targetable)
 }
 
+// GraphNodeExcludable is the counterpart of GraphNodeTargetable for nodes
+// that need to be told about excluded addresses, because only some of the
+// instances they will dynamically expand into are excluded.
+type GraphNodeExcludable interface {
+	SetExcludes([]addrs.Targetable)
+}
+
 // TargetsTransformer is a GraphTransformer that, when the user specifies a
 // list of resources to target, limits the graph to only those resources and
 // their dependencies.
+//
+// When the user specifies a list of resources to exclude instead, it removes
+// those resources and everything that depends on them from the graph.
 type TargetsTransformer struct {
 	// List of targeted resource names specified by the user
 	Targets []addrs.Targetable
+
+	// List of excluded resource names specified by the user
+	Excludes []addrs.Targetable
 }
 
 func (t *TargetsTransformer) Transform(g *Graph) error {
@@ -42,9 +55,114 @@ func (t *TargetsTransformer) Transform(g *Graph) error {
 		}
 	}
 
+	if len(t.Excludes) > 0 {
+		excludedNodes := t.selectExcludedNodes(g, t.Excludes)
+
+		for _, v := range g.Vertices() {
+			if excludedNodes.Include(v) {
+				log.Printf("[DEBUG] Removing %q, filtered by exclusion.", dag.VertexName(v))
+				g.Remove(v)
+			}
+		}
+	}
+
 	return nil
 }
 
+// Returns a set of excluded nodes. An excluded node is either addressed
+// directly, addressed indirectly via its container, or it depends on an
+// excluded node.
+func (t *TargetsTransformer) selectExcludedNodes(g *Graph, excludes []addrs.Targetable) dag.Set {
+	excludedNodes := make(dag.Set)
+
+	for _, v := range g.Vertices() {
+		excluded, partial := t.nodeIsExcluded(v, excludes)
+		switch {
+		case excluded:
+			excludedNodes.Add(v)
+		case partial:
+			// Only some of the instances this node expands into are
+			// excluded, so the node itself must filter them out once it
+			// expands.
+			if en, ok := v.(GraphNodeExcludable); ok {
+				en.SetExcludes(excludes)
+			}
+		default:
+			continue
+		}
+
+		// We can't tell which instances the dependents of a partially
+		// excluded node refer to until everything is expanded, so we
+		// exclude all of them to be safe.
+		deps, _ := g.Descendents(v)
+		for _, d := range deps {
+			excludedNodes.Add(d)
+		}
+	}
+
+	return excludedNodes
+}
+
+// nodeIsExcluded returns whether the given node is entirely covered by the
+// given excludes, or, if not, whether some of the instances it will expand
+// into might be.
+func (t *TargetsTransformer) nodeIsExcluded(v dag.Vertex, excludes []addrs.Targetable) (excluded, partial bool) {
+	switch r := v.(type) {
+	case GraphNodeResourceInstance:
+		addr := r.ResourceInstanceAddr()
+		for _, excludeAddr := range excludes {
+			if excludeAddr.TargetContains(addr) {
+				return true, false
+			}
+		}
+		return false, false
+	case GraphNodeConfigResource:
+		addr := r.ResourceAddr()
+		for _, excludeAddr := range excludes {
+			// Before expansion happens, we only have nodes that know their
+			// ConfigResource address. An exclude address that doesn't refer
+			// to any particular module or resource instance can be compared
+			// directly once generalized, while any other exclude address
+			// only covers some of the node's instances.
+			var configAddr addrs.Targetable
+			instanceSpecific := false
+			switch exclude := excludeAddr.(type) {
+			case addrs.AbsResourceInstance:
+				configAddr = exclude.ContainingResource().Config()
+				instanceSpecific = true
+			case addrs.AbsResource:
+				configAddr = exclude.Config()
+				instanceSpecific = !moduleInstanceIsUnkeyed(exclude.Module)
+			case addrs.ModuleInstance:
+				configAddr = exclude.Module()
+				instanceSpecific = !moduleInstanceIsUnkeyed(exclude)
+			default:
+				configAddr = exclude
+			}
+
+			if configAddr.TargetContains(addr) {
+				if !instanceSpecific {
+					return true, false
+				}
+				partial = true
+			}
+		}
+		return false, partial
+	default:
+		// Only resource and resource instance nodes can be excluded.
+		return false, false
+	}
+}
+
+func moduleInstanceIsUnkeyed(addr addrs.ModuleInstance) bool {
+	for _, step := range addr {
+		if step.InstanceKey != addrs.NoKey {
+			return false
+		}
+	}
+	return true
+}
+
 // Returns a set of targeted nodes. A targeted node is either addressed
 // directly, address indirectly via its container, or it's a dependency of a
 // targeted node.
@@ -120,6 +238,28 @@ func (t *TargetsTransformer) selectTargetedNodes(g *Graph, addrs []addrs.Targeta
 		}
 	}
 
+	// Input variable validations are kept whenever the variable they check
+	// is, along with everything the validation rules refer to, so that the
+	// values of variables used by targeted resources are still checked.
+	for _, v := range vertices {
+		vn, ok := v.(*nodeVariableValidation)
+		if !ok || targetedNodes.Include(vn) {
+			continue
+		}
+
+		for _, tv := range targetedNodes {
+			if !vn.validates(tv) {
+				continue
+			}
+			targetedNodes.Add(vn)
+			deps, _ := g.Ancestors(vn)
+			for _, d := range deps {
+				targetedNodes.Add(d)
+			}
+			break
+		}
+	}
+
 	return targetedNodes, nil
 }
 
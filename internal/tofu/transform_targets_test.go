@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/dag"
 )
 
 func TestTargetsTransformer(t *testing.T) {
@@ -203,3 +204,122 @@ output.grandchild_id (expand)
 		t.Fatalf("bad:\n\nexpected:\n%s\n\ngot:\n%s\n", expected, actual)
 	}
 }
+
+func TestTargetsTransformer_excludes(t *testing.T) {
+	mod := testModule(t, "transform-targets-basic")
+
+	g := Graph{Path: addrs.RootModuleInstance}
+	{
+		tf := &ConfigTransformer{Config: mod}
+		if err := tf.Transform(&g); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	{
+		transform := &AttachResourceConfigTransformer{Config: mod}
+		if err := transform.Transform(&g); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	{
+		transform := &ReferenceTransformer{}
+		if err := transform.Transform(&g); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	{
+		transform := &TargetsTransformer{
+			Excludes: []addrs.Targetable{
+				addrs.RootModuleInstance.Resource(
+					addrs.ManagedResourceMode, "aws_subnet", "me",
+				),
+			},
+		}
+		if err := transform.Transform(&g); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	actual := strings.TrimSpace(g.String())
+	expected := strings.TrimSpace(`
+aws_instance.notme
+aws_subnet.notme
+aws_vpc.me
+aws_vpc.notme
+	`)
+	if actual != expected {
+		t.Fatalf("bad:\n\nexpected:\n%s\n\ngot:\n%s\n", expected, actual)
+	}
+}
+
+func TestTargetsTransformer_excludesInstance(t *testing.T) {
+	mod := testModule(t, "transform-targets-basic")
+
+	g := Graph{Path: addrs.RootModuleInstance}
+	{
+		tf := &ConfigTransformer{Config: mod}
+		if err := tf.Transform(&g); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	{
+		transform := &AttachResourceConfigTransformer{Config: mod}
+		if err := transform.Transform(&g); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	{
+		transform := &ReferenceTransformer{}
+		if err := transform.Transform(&g); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	exclude := addrs.RootModuleInstance.ResourceInstance(
+		addrs.ManagedResourceMode, "aws_subnet", "me", addrs.IntKey(0),
+	)
+	{
+		transform := &TargetsTransformer{
+			Excludes: []addrs.Targetable{exclude},
+		}
+		if err := transform.Transform(&g); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	// Only one instance of the subnet is excluded, so the subnet itself is
+	// kept to filter its instances once expanded, but everything that
+	// depends on it is removed.
+	actual := strings.TrimSpace(g.String())
+	expected := strings.TrimSpace(`
+aws_instance.notme
+aws_subnet.me
+  aws_vpc.me
+aws_subnet.notme
+aws_vpc.me
+aws_vpc.notme
+	`)
+	if actual != expected {
+		t.Fatalf("bad:\n\nexpected:\n%s\n\ngot:\n%s\n", expected, actual)
+	}
+
+	found := false
+	for _, v := range g.Vertices() {
+		n, ok := v.(*NodeAbstractResource)
+		if !ok || n.Addr.Resource.Name != "me" || n.Addr.Resource.Type != "aws_subnet" {
+			continue
+		}
+		found = true
+		if len(n.Excludes) != 1 || !n.Excludes[0].(addrs.AbsResourceInstance).Equal(exclude) {
+			t.Fatalf("wrong excludes for %s: %#v", dag.VertexName(v), n.Excludes)
+		}
+	}
+	if !found {
+		t.Fatal("aws_subnet.me not found in graph")
+	}
+}
@@ -6,6 +6,7 @@ package tofu
 import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/dag"
 )
 
 // RootVariableTransformer is a GraphTransformer that adds all the root
@@ -40,6 +41,59 @@ func (t *RootVariableTransformer) Transform(g *Graph) error {
 			RawValue: t.RawValues[v.Name],
 		}
 		g.Add(node)
+
+		if len(v.Validations) != 0 {
+			g.Add(&nodeVariableValidation{
+				Addr:   node.Addr,
+				Module: addrs.RootModule,
+				Config: v,
+			})
+		}
+	}
+
+	return nil
+}
+
+// variableValidationTransformer makes everything that refers to an input
+// variable depend on the node that checks the variable's validation rules,
+// so that nothing uses the value of the variable before it's known to be
+// valid.
+//
+// This must run after ReferenceTransformer, which connects each variable to
+// the objects that refer to it.
+type variableValidationTransformer struct{}
+
+func (t *variableValidationTransformer) Transform(g *Graph) error {
+	var validations []*nodeVariableValidation
+	for _, v := range g.Vertices() {
+		if vn, ok := v.(*nodeVariableValidation); ok {
+			validations = append(validations, vn)
+		}
+	}
+	if len(validations) == 0 {
+		return nil
+	}
+
+	for _, v := range g.Vertices() {
+		for _, vn := range validations {
+			if !vn.validates(v) {
+				continue
+			}
+
+			// The objects that the validation rules themselves refer to
+			// must be evaluated before the rules can be checked, so they
+			// can't wait for the validation.
+			deps, err := g.Ancestors(vn)
+			if err != nil {
+				return err
+			}
+			for _, referrer := range g.UpEdges(v) {
+				if referrer == vn || deps.Include(referrer) {
+					continue
+				}
+				g.Connect(dag.BasicEdge(referrer, vn))
+			}
+		}
 	}
 
 	return nil
@@ -11,6 +11,7 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/refactoring"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/zclconf/go-cty/cty"
@@ -125,6 +126,74 @@ func upgradeResourceState(addr addrs.AbsResourceInstance, provider providers.Int
 	return new, diags
 }
 
+// moveResourceState asks the provider of the destination of the given move
+// to convert the given state object, which belongs to the resource type of
+// the source of the move, into an object conforming to the current schema of
+// the destination resource type.
+//
+// This is used instead of upgradeResourceState for objects that moved from a
+// resource of a different type, because the provider must also translate
+// the object into the new resource type. As for upgrades, it's not safe to
+// proceed with using the original state object if any errors are returned.
+func moveResourceState(move refactoring.MoveSuccess, provider providers.Interface, src *states.ResourceInstanceObjectSrc, currentSchema *configschema.Block, currentVersion uint64) (*states.ResourceInstanceObjectSrc, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	addr := move.To
+
+	// TODO: This should eventually use a proper FQN.
+	providerType := addr.Resource.Resource.ImpliedProvider()
+	if len(src.AttrsJSON) == 0 {
+		// Legacy flatmap state can only be understood by the provider that
+		// created it, and so it can't be converted by another resource type.
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported state format for moved resource instance",
+			fmt.Sprintf("The state of %s uses a legacy format that can't be moved to %s, which is a resource of a different type. Apply the configuration without this move first, so that the state is upgraded.", move.From, addr),
+		))
+		return nil, diags
+	}
+
+	log.Printf("[TRACE] moveResourceState: converting state for %s from %s using provider %q", addr, move.From, providerType)
+
+	resp := provider.MoveResourceState(providers.MoveResourceStateRequest{
+		SourceProviderAddress: move.FromProvider.Provider.String(),
+		SourceTypeName:        move.From.Resource.Resource.Type,
+
+		// TODO: See the similar comment in upgradeResourceState.
+		SourceSchemaVersion: int64(src.SchemaVersion),
+		SourceStateJSON:     src.AttrsJSON,
+		SourcePrivate:       src.Private,
+		TargetTypeName:      addr.Resource.Resource.Type,
+	})
+	diags = diags.Append(resp.Diagnostics)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	newValue := resp.TargetState
+	if errs := newValue.Type().TestConformance(currentSchema.ImpliedType()); len(errs) > 0 {
+		for _, err := range errs {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid resource state move",
+				fmt.Sprintf("The %s provider converted the state for %s moved from %s, but produced an invalid result: %s.", providerType, addr, move.From, tfdiags.FormatError(err)),
+			))
+		}
+		return nil, diags
+	}
+
+	new, err := src.CompleteUpgrade(newValue, currentSchema.ImpliedType(), currentVersion)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to encode result of resource state move",
+			fmt.Sprintf("Failed to encode state for %s after moving it from %s: %s.", addr, move.From, tfdiags.FormatError(err)),
+		))
+		return nil, diags
+	}
+	new.Private = resp.TargetPrivate
+	return new, diags
+}
+
 // stripRemovedStateAttributes deletes any attributes no longer present in the
 // schema, so that the json can be correctly decoded.
 func stripRemovedStateAttributes(state []byte, ty cty.Type) []byte {
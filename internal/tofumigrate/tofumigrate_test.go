@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
 	"github.com/opentofu/opentofu/internal/states"
 )
@@ -166,7 +167,7 @@ func TestMigrateStateProviderAddresses(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cfg, hclDiags := loader.LoadConfig(tt.args.configDir)
+			cfg, hclDiags := loader.LoadConfig(tt.args.configDir, configs.RootModuleCallForTesting())
 			if hclDiags.HasErrors() {
 				t.Fatalf("invalid configuration: %s", hclDiags.Error())
 			}